@@ -22,6 +22,7 @@ import (
 
 	"cloud.google.com/go/pubsub"
 
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -32,6 +33,7 @@ import (
 	"github.com/google/knative-gcp/pkg/utils/mainhelper"
 
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -46,6 +48,14 @@ type envConfig struct {
 	HandlerConcurrency     int    `envconfig:"HANDLER_CONCURRENCY"`
 	MaxConcurrencyPerEvent int    `envconfig:"MAX_CONCURRENCY_PER_EVENT"`
 
+	// The address of the server streaming the targets config of the
+	// BrokerCell. If empty, the targets config is read from TargetsConfigPath.
+	TargetsConfigServerAddress string `envconfig:"TARGETS_CONFIG_SERVER_ADDRESS"`
+	BrokerCellNamespace        string `envconfig:"BROKER_CELL_NAMESPACE"`
+	BrokerCellName             string `envconfig:"BROKER_CELL_NAME"`
+	// The path of the token authenticating to the targets config server.
+	TargetsConfigTokenPath string `envconfig:"TARGETS_CONFIG_TOKEN_PATH"`
+
 	// Environment variable containing the authType, which represents the authentication configuration mode the Pod is using.
 	AuthType authcheck.AuthType `envconfig:"K_GCP_AUTH_TYPE" default:""`
 
@@ -98,6 +108,12 @@ func main() {
 			volume.WithPath(env.TargetsConfigPath),
 			volume.WithNotifyChan(targetsUpdateCh),
 		},
+		[]stream.Option{
+			stream.WithAddress(env.TargetsConfigServerAddress),
+			stream.WithBrokerCell(types.NamespacedName{Namespace: env.BrokerCellNamespace, Name: env.BrokerCellName}),
+			stream.WithTokenFile(env.TargetsConfigTokenPath),
+			stream.WithNotifyChan(targetsUpdateCh),
+		},
 		buildHandlerOptions(env)...,
 	)
 	if err != nil {
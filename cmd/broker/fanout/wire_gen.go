@@ -7,6 +7,7 @@ package main
 
 import (
 	"context"
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -15,8 +16,8 @@ import (
 
 // Injectors from wire.go:
 
-func InitializeSyncPool(ctx context.Context, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, targetsVolumeOpts []volume.Option, opts ...handler.Option) (*handler.FanoutPool, error) {
-	readonlyTargets, err := volume.NewTargetsFromFile(targetsVolumeOpts...)
+func InitializeSyncPool(ctx context.Context, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, targetsVolumeOpts []volume.Option, targetsServerOpts []stream.Option, opts ...handler.Option) (*handler.FanoutPool, error) {
+	readonlyTargets, err := stream.NewTargets(ctx, targetsServerOpts, targetsVolumeOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/utils"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
@@ -26,6 +27,7 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type envConfig struct {
@@ -37,6 +39,14 @@ type envConfig struct {
 
 	// Default 300Mi.
 	PublishBufferedByteLimit int `envconfig:"PUBLISH_BUFFERED_BYTES_LIMIT" default:"314572800"`
+
+	// The address of the server streaming the targets config of the
+	// BrokerCell. If empty, the targets config is read from the mounted ConfigMap.
+	TargetsConfigServerAddress string `envconfig:"TARGETS_CONFIG_SERVER_ADDRESS"`
+	BrokerCellNamespace        string `envconfig:"BROKER_CELL_NAMESPACE"`
+	BrokerCellName             string `envconfig:"BROKER_CELL_NAME"`
+	// The path of the token authenticating to the targets config server.
+	TargetsConfigTokenPath string `envconfig:"TARGETS_CONFIG_TOKEN_PATH"`
 }
 
 const (
@@ -71,6 +81,11 @@ func main() {
 		metrics.ContainerName(component),
 		publishSetting(logger.Desugar(), env),
 		env.AuthType,
+		[]stream.Option{
+			stream.WithAddress(env.TargetsConfigServerAddress),
+			stream.WithBrokerCell(types.NamespacedName{Namespace: env.BrokerCellNamespace, Name: env.BrokerCellName}),
+			stream.WithTokenFile(env.TargetsConfigTokenPath),
+		},
 	)
 	if err != nil {
 		logger.Desugar().Fatal("Unable to create ingress handler: ", zap.Error(err))
@@ -22,6 +22,7 @@ import (
 	"context"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/ingress"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -38,10 +39,11 @@ func InitializeHandler(
 	containerName metrics.ContainerName,
 	publishSettings pubsub.PublishSettings,
 	authType authcheck.AuthType,
+	targetsServerOpts []stream.Option,
 ) (*ingress.Handler, error) {
 	panic(wire.Build(
 		ingress.HandlerSet,
 		wire.Value([]volume.Option(nil)),
-		volume.NewTargetsFromFile,
+		stream.NewTargets,
 	))
 }
@@ -8,6 +8,7 @@ package main
 import (
 	"cloud.google.com/go/pubsub"
 	"context"
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/ingress"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -17,10 +18,10 @@ import (
 
 // Injectors from wire.go:
 
-func InitializeHandler(ctx context.Context, port clients.Port, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, publishSettings pubsub.PublishSettings, authType authcheck.AuthType) (*ingress.Handler, error) {
+func InitializeHandler(ctx context.Context, port clients.Port, projectID clients.ProjectID, podName metrics.PodName, containerName metrics.ContainerName, publishSettings pubsub.PublishSettings, authType authcheck.AuthType, targetsServerOpts []stream.Option) (*ingress.Handler, error) {
 	httpMessageReceiver := clients.NewHTTPMessageReceiverWithChecker(port, authType)
 	v := _wireValue
-	readonlyTargets, err := volume.NewTargetsFromFile(v...)
+	readonlyTargets, err := stream.NewTargets(ctx, targetsServerOpts, v)
 	if err != nil {
 		return nil, err
 	}
@@ -29,11 +30,13 @@ func InitializeHandler(ctx context.Context, port clients.Port, projectID clients
 		return nil, err
 	}
 	multiTopicDecoupleSink := ingress.NewMultiTopicDecoupleSink(ctx, readonlyTargets, client, publishSettings)
+	kubernetesInterface := clients.NewKubeClient(ctx)
+	tokenReviewAuthorizer := ingress.NewTokenReviewAuthorizer(readonlyTargets, kubernetesInterface)
 	ingressReporter, err := metrics.NewIngressReporter(podName, containerName)
 	if err != nil {
 		return nil, err
 	}
-	handler := ingress.NewHandler(ctx, httpMessageReceiver, multiTopicDecoupleSink, ingressReporter, authType)
+	handler := ingress.NewHandler(ctx, httpMessageReceiver, multiTopicDecoupleSink, tokenReviewAuthorizer, ingressReporter, authType)
 	return handler, nil
 }
 
@@ -23,7 +23,9 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
 	"github.com/google/knative-gcp/pkg/broker/config/volume"
 	"github.com/google/knative-gcp/pkg/broker/handler"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -45,6 +47,14 @@ type envConfig struct {
 	TargetsConfigPath  string `envconfig:"TARGETS_CONFIG_PATH" default:"/var/run/events-system/broker/targets"`
 	HandlerConcurrency int    `envconfig:"HANDLER_CONCURRENCY"`
 
+	// The address of the server streaming the targets config of the
+	// BrokerCell. If empty, the targets config is read from TargetsConfigPath.
+	TargetsConfigServerAddress string `envconfig:"TARGETS_CONFIG_SERVER_ADDRESS"`
+	BrokerCellNamespace        string `envconfig:"BROKER_CELL_NAMESPACE"`
+	BrokerCellName             string `envconfig:"BROKER_CELL_NAME"`
+	// The path of the token authenticating to the targets config server.
+	TargetsConfigTokenPath string `envconfig:"TARGETS_CONFIG_TOKEN_PATH"`
+
 	// Environment variable containing the authType, which represents the authentication configuration mode the Pod is using.
 	AuthType authcheck.AuthType `envconfig:"K_GCP_AUTH_TYPE" default:""`
 
@@ -96,6 +106,12 @@ func main() {
 			volume.WithPath(env.TargetsConfigPath),
 			volume.WithNotifyChan(targetsUpdateCh),
 		},
+		[]stream.Option{
+			stream.WithAddress(env.TargetsConfigServerAddress),
+			stream.WithBrokerCell(types.NamespacedName{Namespace: env.BrokerCellNamespace, Name: env.BrokerCellName}),
+			stream.WithTokenFile(env.TargetsConfigTokenPath),
+			stream.WithNotifyChan(targetsUpdateCh),
+		},
 		buildHandlerOptions(env)...,
 	)
 	if err != nil {
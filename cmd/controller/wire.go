@@ -19,6 +19,7 @@ import (
 	"context"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 	"github.com/google/knative-gcp/pkg/reconciler/broker"
@@ -26,6 +27,8 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/deployment"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/firestore"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
@@ -47,11 +50,14 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 		wire.Struct(new(brokerdelivery.StoreSingleton)),
 		wire.Struct(new(gcpauth.StoreSingleton)),
 		wire.Struct(new(dataresidency.StoreSingleton)),
+		wire.Struct(new(brokerplacement.StoreSingleton)),
 		auditlogs.NewConstructor,
 		storage.NewConstructor,
 		scheduler.NewConstructor,
 		pubsub.NewConstructor,
 		build.NewConstructor,
+		firestore.NewConstructor,
+		monitoring.NewConstructor,
 		static.NewConstructor,
 		keda.NewConstructor,
 		topic.NewConstructor,
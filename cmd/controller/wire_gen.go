@@ -9,6 +9,7 @@ import (
 	"cloud.google.com/go/iam/admin/apiv1"
 	"context"
 	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 	"github.com/google/knative-gcp/pkg/reconciler/broker"
@@ -16,6 +17,8 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/deployment"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/firestore"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
@@ -50,6 +53,8 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 	schedulerConstructor := scheduler.NewConstructor(iamPolicyManager, storeSingleton)
 	pubsubConstructor := pubsub.NewConstructor(iamPolicyManager, storeSingleton)
 	buildConstructor := build.NewConstructor(iamPolicyManager, storeSingleton)
+	firestoreConstructor := firestore.NewConstructor(iamPolicyManager, storeSingleton)
+	monitoringConstructor := monitoring.NewConstructor(iamPolicyManager, storeSingleton)
 	staticConstructor := static.NewConstructor(iamPolicyManager, storeSingleton)
 	kedaConstructor := keda.NewConstructor(iamPolicyManager, storeSingleton)
 	dataresidencyStoreSingleton := &dataresidency.StoreSingleton{}
@@ -57,9 +62,10 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 	channelConstructor := channel.NewConstructor(iamPolicyManager, storeSingleton)
 	triggerConstructor := trigger.NewConstructor(dataresidencyStoreSingleton)
 	brokerdeliveryStoreSingleton := &brokerdelivery.StoreSingleton{}
-	brokerConstructor := broker.NewConstructor(brokerdeliveryStoreSingleton, dataresidencyStoreSingleton)
+	brokerplacementStoreSingleton := &brokerplacement.StoreSingleton{}
+	brokerConstructor := broker.NewConstructor(brokerdeliveryStoreSingleton, dataresidencyStoreSingleton, brokerplacementStoreSingleton)
 	deploymentConstructor := deployment.NewConstructor()
 	brokercellConstructor := brokercell.NewConstructor()
-	v2 := Controllers(constructor, storageConstructor, schedulerConstructor, pubsubConstructor, buildConstructor, staticConstructor, kedaConstructor, topicConstructor, channelConstructor, triggerConstructor, brokerConstructor, deploymentConstructor, brokercellConstructor)
+	v2 := Controllers(constructor, storageConstructor, schedulerConstructor, pubsubConstructor, buildConstructor, firestoreConstructor, monitoringConstructor, staticConstructor, kedaConstructor, topicConstructor, channelConstructor, triggerConstructor, brokerConstructor, deploymentConstructor, brokercellConstructor)
 	return v2, nil
 }
@@ -64,10 +64,27 @@ type envConfig struct {
 	// Otherwise, only Sink is used (for either the sub.reply or sub.reply)
 	Transformer string `envconfig:"TRANSFORMER_URI"`
 
+	// Environment variable containing the URI the replies of the sink are
+	// sent to. The replies are discarded if it is empty.
+	Reply string `envconfig:"REPLY_URI"`
+
 	// Environment variable specifying the type of adapter to use.
 	// Used for CE conversion.
 	AdapterType string `envconfig:"ADAPTER_TYPE"`
 
+	// Environment variable containing the JSON encoded filter of the events,
+	// which depends on the type of adapter. All the events are delivered if
+	// it is empty.
+	AdapterFilter string `envconfig:"ADAPTER_FILTER"`
+
+	// Environment variable containing the JSON encoded mapping of the
+	// attributes of the events. The events are not mapped if it is empty.
+	EventMapping string `envconfig:"EVENT_MAPPING"`
+
+	// Environment variable containing the encoding of the requests sent to
+	// the sink, one of binary, structured, raw and push. Defaults to binary.
+	SendMode string `envconfig:"SEND_MODE"`
+
 	// Topic is the environment variable containing the PubSub Topic being
 	// subscribed to's name. In the form that is unique within the project.
 	// E.g. 'laconia', not 'projects/my-gcp-project/topics/laconia'.
@@ -169,6 +186,16 @@ func main() {
 		logger.Error("Failed to convert base64 extensions to map: %v", zap.Error(err))
 	}
 
+	filter, err := NewEventFilter(converters.ConverterType(env.AdapterType), env.AdapterFilter)
+	if err != nil {
+		logger.Fatal("Failed to create the event filter", zap.Error(err))
+	}
+
+	mapper, err := NewEventMapper(env.EventMapping)
+	if err != nil {
+		logger.Fatal("Failed to create the event mapper", zap.Error(err))
+	}
+
 	logger.Info("Initializing adapter", zap.String("projectID", projectID), zap.String("topicID", env.Topic), zap.String("subscriptionID", env.Subscription))
 
 	args := &AdapterArgs{
@@ -176,7 +203,11 @@ func main() {
 		ConverterType:  converters.ConverterType(env.AdapterType),
 		SinkURI:        env.Sink,
 		TransformerURI: env.Transformer,
+		ReplyURI:       env.Reply,
 		Extensions:     extensions,
+		Filter:         filter,
+		Mapper:         mapper,
+		Mode:           converters.ModeType(env.SendMode),
 		AuthType:       env.AuthType,
 	}
 
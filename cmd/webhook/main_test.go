@@ -0,0 +1,173 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	_ "knative.dev/pkg/client/injection/kube/client/fake"
+	vwhinformer "knative.dev/pkg/client/injection/kube/informers/admissionregistration/v1/validatingwebhookconfiguration"
+	kubeinformerfactory "knative.dev/pkg/client/injection/kube/informers/factory"
+	_ "knative.dev/pkg/client/injection/kube/informers/factory/fake"
+	"knative.dev/pkg/configmap"
+	_ "knative.dev/pkg/injection/clients/namespacedkube/informers/core/v1/secret/fake"
+	logtesting "knative.dev/pkg/logging/testing"
+	rectesting "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/pkg/webhook/resourcesemantics/validation"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
+	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+)
+
+const (
+	triggerWithSpecDelivery = `{
+  "apiVersion": "eventing.knative.dev/v1beta1",
+  "kind": "Trigger",
+  "metadata": {"name": "my-trigger", "namespace": "my-namespace"},
+  "spec": {
+    "broker": "default",
+    "subscriber": {"uri": "http://subscriber.example.com"},
+    "delivery": {"retry": 3}
+  }
+}`
+	triggerWithDeliveryAnnotation = `{
+  "apiVersion": "eventing.knative.dev/v1beta1",
+  "kind": "Trigger",
+  "metadata": {
+    "name": "my-trigger",
+    "namespace": "my-namespace",
+    "annotations": {"events.cloud.google.com/delivery": "{\"retry\": 3}"}
+  },
+  "spec": {
+    "broker": "default",
+    "subscriber": {"uri": "http://subscriber.example.com"}
+  }
+}`
+	triggerWithInvalidDeliveryAnnotation = `{
+  "apiVersion": "eventing.knative.dev/v1beta1",
+  "kind": "Trigger",
+  "metadata": {
+    "name": "my-trigger",
+    "namespace": "my-namespace",
+    "annotations": {"events.cloud.google.com/delivery": "{\"retry\": -1}"}
+  },
+  "spec": {
+    "broker": "default",
+    "subscriber": {"uri": "http://subscriber.example.com"}
+  }
+}`
+)
+
+// TestTriggerDeliveryAdmission checks which webhook admits a Trigger's
+// delivery spec. The eventing webhook, which doesn't know about
+// spec.delivery, rejects it, so the delivery annotation must be used instead.
+func TestTriggerDeliveryAdmission(t *testing.T) {
+	tests := []struct {
+		name           string
+		trigger        string
+		wantEventing   string
+		wantKnativeGCP string
+	}{{
+		name:         "spec.delivery",
+		trigger:      triggerWithSpecDelivery,
+		wantEventing: `unknown field "delivery"`,
+	}, {
+		name:    "delivery annotation",
+		trigger: triggerWithDeliveryAnnotation,
+	}, {
+		name:           "invalid delivery annotation",
+		trigger:        triggerWithInvalidDeliveryAnnotation,
+		wantKnativeGCP: "metadata.annotations[events.cloud.google.com/delivery].retry",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := setupWebhookContext(t)
+			eventingTypes := map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
+				eventingv1beta1.SchemeGroupVersion.WithKind("Trigger"): &eventingv1beta1.Trigger{},
+			}
+			// The eventing webhook is configured like ours, disallowing unknown fields.
+			eventing := validation.NewAdmissionController(ctx, "validation.webhook.eventing.knative.dev", "/resource-validation",
+				eventingTypes, func(ctx context.Context) context.Context { return ctx }, true)
+			knativeGCP := newValidationAdmissionController(ctx, configmap.NewStaticWatcher(),
+				newBrokerDeliveryStore(t), newGCPAuthStore(t))
+
+			req := &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Kind: metav1.GroupVersionKind{
+					Group:   brokerv1beta1.SchemeGroupVersion.Group,
+					Version: brokerv1beta1.SchemeGroupVersion.Version,
+					Kind:    "Trigger",
+				},
+				Object: runtime.RawExtension{Raw: []byte(test.trigger)},
+			}
+			checkAdmission(t, "eventing", eventing.Reconciler.(webhook.AdmissionController).Admit(ctx, req), test.wantEventing)
+			checkAdmission(t, "knative-gcp", knativeGCP.Reconciler.(webhook.AdmissionController).Admit(ctx, req), test.wantKnativeGCP)
+		})
+	}
+}
+
+func checkAdmission(t *testing.T, webhookName string, resp *admissionv1.AdmissionResponse, wantErr string) {
+	t.Helper()
+	if wantErr == "" {
+		if !resp.Allowed {
+			t.Errorf("%s webhook rejected the Trigger: %v", webhookName, resp.Result.Message)
+		}
+		return
+	}
+	if resp.Allowed {
+		t.Errorf("%s webhook admitted the Trigger, want error containing %q", webhookName, wantErr)
+	} else if !strings.Contains(resp.Result.Message, wantErr) {
+		t.Errorf("%s webhook error = %q, want error containing %q", webhookName, resp.Result.Message, wantErr)
+	}
+}
+
+func setupWebhookContext(t *testing.T) context.Context {
+	ctx, _ := rectesting.SetupFakeContext(t)
+	ctx = webhook.WithOptions(ctx, webhook.Options{SecretName: "webhook-certs"})
+	vwh := kubeinformerfactory.Get(ctx).Admissionregistration().V1().ValidatingWebhookConfigurations()
+	return context.WithValue(ctx, vwhinformer.Key{}, vwh)
+}
+
+func newBrokerDeliveryStore(t *testing.T) *brokerdelivery.Store {
+	store := brokerdelivery.NewStore(logtesting.TestLogger(t))
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: brokerdelivery.ConfigMapName()},
+		Data:       map[string]string{"default-br-delivery-config": "clusterDefaults: {}"},
+	})
+	return store
+}
+
+func newGCPAuthStore(t *testing.T) *gcpauth.Store {
+	store := gcpauth.NewStore(logtesting.TestLogger(t))
+	store.OnConfigChanged(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: gcpauth.ConfigMapName()},
+		Data:       map[string]string{"default-auth-config": "clusterDefaults: {}"},
+	})
+	return store
+}
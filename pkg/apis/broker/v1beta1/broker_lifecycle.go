@@ -17,8 +17,12 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
 )
 
 var brokerCondSet = apis.NewLivingConditionSet(
@@ -37,6 +41,10 @@ const (
 	// BrokerConditionSubscription reports the status of the Broker's PubSub
 	// subscription. This condition is specific to the Google Cloud Broker.
 	BrokerConditionSubscription apis.ConditionType = "SubscriptionReady"
+	// BrokerConditionBacklogHealthy reports whether the backlog of the Broker's
+	// PubSub subscription is below its thresholds, if the backlog reporting is
+	// enabled. It does not affect the Broker readiness.
+	BrokerConditionBacklogHealthy apis.ConditionType = "BacklogHealthy"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -105,3 +113,28 @@ func (bs *BrokerStatus) MarkSubscriptionUnknown(reason, format string, args ...i
 func (bs *BrokerStatus) MarkSubscriptionReady() {
 	brokerCondSet.Manage(bs).MarkTrue(BrokerConditionSubscription)
 }
+
+// SetBacklog reports the backlog of the Broker's subscription in the status
+// annotations.
+func (bs *BrokerStatus) SetBacklog(undeliveredMessages int64, oldestUnackedMessageAge time.Duration) {
+	bs.Annotations = duck.SetBacklogStatusAnnotations(bs.Annotations, undeliveredMessages, oldestUnackedMessageAge)
+}
+
+func (bs *BrokerStatus) MarkBacklogHealthy() {
+	brokerCondSet.Manage(bs).MarkTrue(BrokerConditionBacklogHealthy)
+}
+
+func (bs *BrokerStatus) MarkBacklogThresholdExceeded(format string, args ...interface{}) {
+	brokerCondSet.Manage(bs).MarkFalse(BrokerConditionBacklogHealthy, "BacklogThresholdExceeded", format, args...)
+}
+
+func (bs *BrokerStatus) MarkBacklogUnknown(reason, format string, args ...interface{}) {
+	brokerCondSet.Manage(bs).MarkUnknown(BrokerConditionBacklogHealthy, reason, format, args...)
+}
+
+// ClearBacklog removes the backlog status annotations and condition once the
+// backlog reporting is disabled.
+func (bs *BrokerStatus) ClearBacklog() {
+	bs.Annotations = duck.ClearBacklogStatusAnnotations(bs.Annotations)
+	brokerCondSet.Manage(bs).ClearCondition(BrokerConditionBacklogHealthy)
+}
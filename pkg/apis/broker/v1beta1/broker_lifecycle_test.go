@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -385,3 +386,32 @@ func TestBrokerConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestBrokerBacklog(t *testing.T) {
+	bs := TestHelper.ReadyBrokerStatus()
+
+	bs.SetBacklog(20, 90*time.Second)
+	bs.MarkBacklogUnknown("BacklogUnavailable", "no data")
+	wantAnnotations := map[string]string{
+		"events.cloud.google.com/undeliveredMessages":     "20",
+		"events.cloud.google.com/oldestUnackedMessageAge": "1m30s",
+	}
+	if diff := cmp.Diff(wantAnnotations, bs.Annotations); diff != "" {
+		t.Errorf("unexpected status annotations (-want, +got) = %v", diff)
+	}
+	if got := bs.GetCondition(BrokerConditionBacklogHealthy).Status; got != corev1.ConditionUnknown {
+		t.Errorf("unexpected backlog condition: want %v, got %v", corev1.ConditionUnknown, got)
+	}
+	bs.MarkBacklogThresholdExceeded("backlog of %d messages", 20)
+	if !bs.IsReady() {
+		t.Error("a Broker with a backlog exceeding its thresholds should stay ready")
+	}
+
+	bs.ClearBacklog()
+	if bs.Annotations != nil {
+		t.Errorf("expected the backlog status annotations to be cleared, got %v", bs.Annotations)
+	}
+	if got := bs.GetCondition(BrokerConditionBacklogHealthy); got != nil {
+		t.Errorf("expected the backlog condition to be cleared, got %v", got)
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -30,6 +32,50 @@ const (
 	// BrokerClass is the annotation value to use when creating a
 	// Google Cloud Broker object.
 	BrokerClass = "googlecloud"
+
+	// OrderingKeyExtensionAnnotationKey is the annotation key used to enable
+	// ordered delivery on a Broker. Its value is the name of the CloudEvents
+	// extension whose value is used as the ordering key: events with the same
+	// ordering key are delivered to each Trigger in the order they were received.
+	OrderingKeyExtensionAnnotationKey = "events.cloud.google.com/orderingKeyExtension"
+
+	// DeliveryAudienceAnnotationKey is the annotation key used to enable
+	// authenticated delivery on a Broker or Trigger. Its value is the audience
+	// of the Google-signed ID token sent to the subscribers, e.g. the URL of a
+	// Cloud Run service. A Trigger's annotation overrides its Broker's.
+	DeliveryAudienceAnnotationKey = "events.cloud.google.com/deliveryAudience"
+
+	// IngressAllowedServiceAccountsAnnotationKey is the annotation key used to
+	// restrict the publishers of a Broker's events. Its value is a comma
+	// separated list of Kubernetes service accounts in the form
+	// "namespace/name", where a name of "*" allows all the service accounts of
+	// the namespace.
+	IngressAllowedServiceAccountsAnnotationKey = "events.cloud.google.com/ingressAllowedServiceAccounts"
+	// IngressAudienceAnnotationKey is the annotation key used to set the
+	// audience that the publishers' service account tokens must be issued for.
+	IngressAudienceAnnotationKey = "events.cloud.google.com/ingressAudience"
+
+	// BrokerCellLabelKey is the label key of the name of the BrokerCell, in
+	// the system namespace, that serves a Broker. Brokers without the label
+	// are served by the default BrokerCell. The label is managed by the Broker
+	// controller.
+	BrokerCellLabelKey = "events.cloud.google.com/brokercell"
+
+	// BrokerCellAnnotationKey is the annotation key of the name of the
+	// BrokerCell requested for a Broker. It takes precedence over the cluster
+	// placement policy, and changing it moves the Broker to the requested
+	// BrokerCell.
+	BrokerCellAnnotationKey = "events.cloud.google.com/requestedBrokerCell"
+
+	// PreviousBrokerCellLabelKey is the label key of the name of the BrokerCell
+	// a Broker is moving away from. The previous BrokerCell keeps serving the
+	// Broker until the drain deadline passed.
+	PreviousBrokerCellLabelKey = "internal.events.cloud.google.com/previousBrokerCell"
+
+	// BrokerCellDrainDeadlineAnnotationKey is the annotation key of the time,
+	// in RFC 3339 format, until which the previous BrokerCell of a Broker
+	// keeps serving it.
+	BrokerCellDrainDeadlineAnnotationKey = "internal.events.cloud.google.com/brokerCellDrainDeadline"
 )
 
 // +genclient
@@ -126,3 +172,41 @@ func (*Broker) GetConditionSet() apis.ConditionSet {
 func (b *Broker) GetStatus() *duckv1.Status {
 	return &b.Status.Status
 }
+
+// OrderingKeyExtension returns the name of the CloudEvents extension used as
+// the ordering key of the Broker, or an empty string if ordered delivery is not
+// enabled.
+func (b *Broker) OrderingKeyExtension() string {
+	return b.GetAnnotations()[OrderingKeyExtensionAnnotationKey]
+}
+
+// DeliveryAudience returns the audience of the ID token sent to the
+// subscribers of the Broker's Triggers, or an empty string if deliveries are
+// not authenticated.
+func (b *Broker) DeliveryAudience() string {
+	return b.GetAnnotations()[DeliveryAudienceAnnotationKey]
+}
+
+// RequestedBrokerCell returns the name of the BrokerCell requested for the
+// Broker, or an empty string if the Broker is placed by the cluster placement
+// policy.
+func (b *Broker) RequestedBrokerCell() string {
+	return b.GetAnnotations()[BrokerCellAnnotationKey]
+}
+
+// IngressPolicy returns the Kubernetes service accounts allowed to publish
+// events to the Broker and the audience their tokens must be issued for. No
+// service accounts means that anyone who can reach the ingress can publish.
+func (b *Broker) IngressPolicy() (allowedServiceAccounts []string, audience string) {
+	annotations := b.GetAnnotations()
+	v, ok := annotations[IngressAllowedServiceAccountsAnnotationKey]
+	if !ok {
+		return nil, ""
+	}
+	for _, sa := range strings.Split(v, ",") {
+		if sa = strings.TrimSpace(sa); sa != "" {
+			allowedServiceAccounts = append(allowedServiceAccounts, sa)
+		}
+	}
+	return allowedServiceAccounts, annotations[IngressAudienceAnnotationKey]
+}
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
@@ -64,3 +65,39 @@ func TestBroker_GetStatus(t *testing.T) {
 		t.Errorf("GetStatus=%v, want=%v", got, want)
 	}
 }
+
+func TestBroker_IngressPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantAccounts []string
+		wantAudience string
+	}{{
+		name: "no policy",
+	}, {
+		name: "audience without service accounts",
+		annotations: map[string]string{
+			IngressAudienceAnnotationKey: "broker-ingress",
+		},
+	}, {
+		name: "service accounts and audience",
+		annotations: map[string]string{
+			IngressAllowedServiceAccountsAnnotationKey: "ns/publisher, other-ns/*,",
+			IngressAudienceAnnotationKey:               "broker-ingress",
+		},
+		wantAccounts: []string{"ns/publisher", "other-ns/*"},
+		wantAudience: "broker-ingress",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			accounts, audience := b.IngressPolicy()
+			if diff := cmp.Diff(test.wantAccounts, accounts); diff != "" {
+				t.Errorf("IngressPolicy() accounts (-want,+got): %v", diff)
+			}
+			if audience != test.wantAudience {
+				t.Errorf("IngressPolicy() audience=%q, want=%q", audience, test.wantAudience)
+			}
+		})
+	}
+}
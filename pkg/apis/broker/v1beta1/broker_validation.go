@@ -18,21 +18,113 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/validation"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
 )
 
+// PubsubDeadLetterSinkScheme is the URI scheme of dead letter sinks that refer
+// to a Pub/Sub topic.
+const PubsubDeadLetterSinkScheme = "pubsub"
+
 // Validate verifies that the Broker is valid.
 func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
-	// We validate the GCP Broker's delivery spec. The eventing webhook will run
-	// the other usual validations.
+	// We validate the GCP Broker's delivery spec, ordering, delivery audience,
+	// ingress policy, BrokerCell and backlog annotations. The eventing webhook
+	// will run the other usual validations.
+	errs := validateOrderingKeyExtension(b.GetAnnotations())
+	errs = errs.Also(validateDeliveryAudience(b.GetAnnotations()))
+	errs = errs.Also(validateIngressPolicy(b.GetAnnotations()))
+	errs = errs.Also(validateBrokerCell(b.GetAnnotations()))
+	errs = duck.ValidateBacklogAnnotations(b.GetAnnotations(), errs)
+	if original, ok := apis.GetBaseline(ctx).(*Broker); ok && apis.IsInUpdate(ctx) {
+		errs = errs.Also(b.CheckImmutableFields(ctx, original))
+	}
 	if b.Spec.Delivery == nil {
-		return nil
+		return errs
 	}
 	withNS := apis.AllowDifferentNamespace(apis.WithinParent(ctx, b.ObjectMeta))
-	return ValidateDeliverySpec(withNS, b.Spec.Delivery).ViaField("spec", "delivery")
+	return errs.Also(ValidateDeliverySpec(withNS, b.Spec.Delivery).ViaField("spec", "delivery"))
+}
+
+// CheckImmutableFields checks that the ordering key extension annotation is
+// unchanged, as message ordering can't be changed on existing Pub/Sub
+// subscriptions.
+func (b *Broker) CheckImmutableFields(ctx context.Context, original *Broker) *apis.FieldError {
+	if original == nil {
+		return nil
+	}
+	if diff := cmp.Diff(original.OrderingKeyExtension(), b.OrderingKeyExtension()); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", OrderingKeyExtensionAnnotationKey)},
+			Details: diff,
+		}
+	}
+	return nil
+}
+
+func validateOrderingKeyExtension(annotations map[string]string) *apis.FieldError {
+	if ext, ok := annotations[OrderingKeyExtensionAnnotationKey]; ok && !validAttributeName.MatchString(ext) {
+		return apis.ErrInvalidValue(ext, fmt.Sprintf("metadata.annotations[%s]", OrderingKeyExtensionAnnotationKey))
+	}
+	return nil
+}
+
+func validateDeliveryAudience(annotations map[string]string) *apis.FieldError {
+	if audience, ok := annotations[DeliveryAudienceAnnotationKey]; ok && (audience == "" || strings.ContainsAny(audience, " \t\r\n")) {
+		return apis.ErrInvalidValue(audience, fmt.Sprintf("metadata.annotations[%s]", DeliveryAudienceAnnotationKey))
+	}
+	return nil
+}
+
+func validateIngressPolicy(annotations map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	v, ok := annotations[IngressAllowedServiceAccountsAnnotationKey]
+	if ok {
+		sas := strings.Split(v, ",")
+		for _, sa := range sas {
+			if !validServiceAccount(strings.TrimSpace(sa)) {
+				errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", IngressAllowedServiceAccountsAnnotationKey)))
+				break
+			}
+		}
+	}
+	if audience, hasAudience := annotations[IngressAudienceAnnotationKey]; hasAudience {
+		if !ok {
+			errs = errs.Also(apis.ErrMissingField(fmt.Sprintf("metadata.annotations[%s]", IngressAllowedServiceAccountsAnnotationKey)))
+		}
+		if audience == "" || strings.ContainsAny(audience, " \t\r\n") {
+			errs = errs.Also(apis.ErrInvalidValue(audience, fmt.Sprintf("metadata.annotations[%s]", IngressAudienceAnnotationKey)))
+		}
+	}
+	return errs
+}
+
+func validateBrokerCell(annotations map[string]string) *apis.FieldError {
+	// The name of the BrokerCell is the prefix of the names of its data plane
+	// resources, so it must be a DNS label.
+	if bc, ok := annotations[BrokerCellAnnotationKey]; ok && len(validation.IsDNS1123Label(bc)) != 0 {
+		return apis.ErrInvalidValue(bc, fmt.Sprintf("metadata.annotations[%s]", BrokerCellAnnotationKey))
+	}
+	return nil
+}
+
+// validServiceAccount returns true if sa is a Kubernetes service account in
+// the form "namespace/name" or "namespace/*".
+func validServiceAccount(sa string) bool {
+	parts := strings.Split(sa, "/")
+	if len(parts) != 2 || len(validation.IsDNS1123Label(parts[0])) != 0 {
+		return false
+	}
+	return parts[1] == "*" || len(validation.IsDNS1123Subdomain(parts[1])) == 0
 }
 
 func ValidateDeliverySpec(ctx context.Context, spec *eventingduckv1beta1.DeliverySpec) *apis.FieldError {
@@ -49,15 +141,15 @@ func ValidateDeliverySpec(ctx context.Context, spec *eventingduckv1beta1.Deliver
 	return errs.Also(ValidateDeadLetterSink(ctx, spec.DeadLetterSink).ViaField("deadLetterSink"))
 }
 
+// ValidateDeadLetterSink validates the dead letter sink of a delivery spec. A
+// sink with a pubsub URI scheme refers to an existing Pub/Sub topic; any other
+// sink must be a resolvable destination.
 func ValidateDeadLetterSink(ctx context.Context, sink *duckv1.Destination) *apis.FieldError {
 	if sink == nil {
 		return nil
 	}
-	if sink.URI == nil {
-		return apis.ErrMissingField("uri")
-	}
-	if scheme := sink.URI.Scheme; scheme != "pubsub" {
-		return apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub", "uri")
+	if !IsPubsubDeadLetterSink(sink) {
+		return sink.Validate(ctx)
 	}
 	topicID := sink.URI.Host
 	if topicID == "" {
@@ -68,3 +160,9 @@ func ValidateDeadLetterSink(ctx context.Context, sink *duckv1.Destination) *apis
 	}
 	return nil
 }
+
+// IsPubsubDeadLetterSink returns true if the dead letter sink refers to a
+// Pub/Sub topic directly, i.e. its URI is of the form pubsub://<topic-id>.
+func IsPubsubDeadLetterSink(sink *duckv1.Destination) bool {
+	return sink != nil && sink.Ref == nil && sink.URI != nil && sink.URI.Scheme == PubsubDeadLetterSinkScheme
+}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
@@ -84,9 +85,25 @@ func TestBroker_Validate(t *testing.T) {
 				},
 			},
 		},
-		want: apis.ErrMissingField("spec.delivery.deadLetterSink.uri"),
+		want: apis.ErrGeneric("expected at least one, got none", "spec.delivery.deadLetterSink.ref", "spec.delivery.deadLetterSink.uri"),
 	}, {
-		name: "invalid dead letter sink uri scheme",
+		name: "invalid relative dead letter sink uri",
+		broker: Broker{
+			Spec: v1beta1.BrokerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &bod,
+					BackoffPolicy: &bop,
+					DeadLetterSink: &duckv1.Destination{
+						URI: &apis.URL{
+							Path: "/dead-letter",
+						},
+					},
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("Relative URI is not allowed when Ref and [apiVersion, kind, name] is absent", "spec.delivery.deadLetterSink.uri"),
+	}, {
+		name: "valid http dead letter sink",
 		broker: Broker{
 			Spec: v1beta1.BrokerSpec{
 				Delivery: &eventingduckv1beta1.DeliverySpec{
@@ -95,13 +112,30 @@ func TestBroker_Validate(t *testing.T) {
 					DeadLetterSink: &duckv1.Destination{
 						URI: &apis.URL{
 							Scheme: "http",
-							Host:   "test-topic-id",
+							Host:   "dead-letter.example.com",
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "valid addressable dead letter sink",
+		broker: Broker{
+			Spec: v1beta1.BrokerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &bod,
+					BackoffPolicy: &bop,
+					DeadLetterSink: &duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "serving.knative.dev/v1",
+							Kind:       "Service",
+							Name:       "dead-letter",
+							Namespace:  "ns",
 						},
 					},
 				},
 			},
 		},
-		want: apis.ErrInvalidValue("Dead letter sink URI scheme should be pubsub", "spec.delivery.deadLetterSink.uri"),
 	}, {
 		name: "invalid empty dead letter topic id",
 		broker: Broker{
@@ -152,6 +186,99 @@ func TestBroker_Validate(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "valid ordering key extension",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{OrderingKeyExtensionAnnotationKey: "partitionkey"},
+			},
+		},
+	}, {
+		name: "invalid ordering key extension",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{OrderingKeyExtensionAnnotationKey: "Partition-Key"},
+			},
+		},
+		want: apis.ErrInvalidValue("Partition-Key", "metadata.annotations[events.cloud.google.com/orderingKeyExtension]"),
+	}, {
+		name: "valid delivery audience",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{DeliveryAudienceAnnotationKey: "https://subscriber.a.run.app"},
+			},
+		},
+	}, {
+		name: "invalid delivery audience",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{DeliveryAudienceAnnotationKey: ""},
+			},
+		},
+		want: apis.ErrInvalidValue("", "metadata.annotations[events.cloud.google.com/deliveryAudience]"),
+	}, {
+		name: "valid ingress policy",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					IngressAllowedServiceAccountsAnnotationKey: "ns/publisher, other-ns/*",
+					IngressAudienceAnnotationKey:               "broker-ingress",
+				},
+			},
+		},
+	}, {
+		name: "invalid ingress policy",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					IngressAllowedServiceAccountsAnnotationKey: "ns/publisher,publisher",
+					IngressAudienceAnnotationKey:               "",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("ns/publisher,publisher", "metadata.annotations[events.cloud.google.com/ingressAllowedServiceAccounts]").Also(
+			apis.ErrInvalidValue("", "metadata.annotations[events.cloud.google.com/ingressAudience]")),
+	}, {
+		name: "ingress audience without service accounts",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{IngressAudienceAnnotationKey: "broker-ingress"},
+			},
+		},
+		want: apis.ErrMissingField("metadata.annotations[events.cloud.google.com/ingressAllowedServiceAccounts]"),
+	}, {
+		name: "valid brokercell",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{BrokerCellAnnotationKey: "team-a"},
+			},
+		},
+	}, {
+		name: "invalid brokercell",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{BrokerCellAnnotationKey: "Team.A"},
+			},
+		},
+		want: apis.ErrInvalidValue("Team.A", "metadata.annotations[events.cloud.google.com/requestedBrokerCell]"),
+	}, {
+		name: "valid backlog thresholds",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"events.cloud.google.com/backlogThreshold":    "1000",
+					"events.cloud.google.com/backlogAgeThreshold": "10m",
+				},
+			},
+		},
+	}, {
+		name: "invalid backlog age threshold",
+		broker: Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{"events.cloud.google.com/backlogAgeThreshold": "10"},
+			},
+		},
+		want: apis.ErrInvalidValue("10", "metadata.annotations[events.cloud.google.com/backlogAgeThreshold]"),
 	}}
 
 	for _, test := range tests {
@@ -163,3 +290,43 @@ func TestBroker_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestBroker_CheckImmutableFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		original map[string]string
+		current  map[string]string
+		wantErr  bool
+	}{{
+		name: "no ordering key extension",
+	}, {
+		name:     "unchanged ordering key extension",
+		original: map[string]string{OrderingKeyExtensionAnnotationKey: "partitionkey"},
+		current:  map[string]string{OrderingKeyExtensionAnnotationKey: "partitionkey"},
+	}, {
+		name:    "added ordering key extension",
+		current: map[string]string{OrderingKeyExtensionAnnotationKey: "partitionkey"},
+		wantErr: true,
+	}, {
+		name:     "removed ordering key extension",
+		original: map[string]string{OrderingKeyExtensionAnnotationKey: "partitionkey"},
+		wantErr:  true,
+	}, {
+		name:     "changed ordering key extension",
+		original: map[string]string{OrderingKeyExtensionAnnotationKey: "partitionkey"},
+		current:  map[string]string{OrderingKeyExtensionAnnotationKey: "orderid"},
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := &Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.original}}
+			current := &Broker{ObjectMeta: metav1.ObjectMeta{Annotations: test.current}}
+			ctx := apis.WithinUpdate(context.Background(), original)
+			err := current.Validate(ctx)
+			if test.wantErr != (err != nil) {
+				t.Errorf("Broker.Validate got err=%v, wantErr=%v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -18,6 +18,11 @@ package v1beta1
 
 import (
 	"context"
+
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
 )
 
 const (
@@ -33,3 +38,45 @@ func (t *Trigger) SetDefaults(ctx context.Context) {
 		t.Spec.Subscriber.Ref.APIVersion = knativeServingV1ApiVersion
 	}
 }
+
+// DeliverySpecWithDefaults returns the delivery spec to apply to the Trigger.
+// Fields not set in the Trigger's delivery spec, or its delivery annotation,
+// are taken from the Broker's
+// delivery spec, which is defaulted from the config-br-delivery defaults.
+// Neither the Trigger nor the Broker is modified.
+func (t *Trigger) DeliverySpecWithDefaults(ctx context.Context, b *Broker) *eventingduckv1beta1.DeliverySpec {
+	if b.Spec.Delivery == nil {
+		b = b.DeepCopy()
+		b.SetDefaults(ctx)
+	}
+	brokerDelivery := b.Spec.Delivery
+	if brokerDelivery == nil {
+		brokerDelivery = &eventingduckv1beta1.DeliverySpec{}
+	}
+	triggerDelivery := t.DeliverySpec()
+	if triggerDelivery == nil {
+		return brokerDelivery.DeepCopy()
+	}
+
+	delivery := triggerDelivery.DeepCopy()
+	if delivery.BackoffPolicy == nil {
+		delivery.BackoffPolicy = brokerDelivery.BackoffPolicy
+	}
+	if delivery.BackoffDelay == nil {
+		delivery.BackoffDelay = brokerDelivery.BackoffDelay
+	}
+	if delivery.DeadLetterSink == nil {
+		delivery.DeadLetterSink = brokerDelivery.DeadLetterSink.DeepCopy()
+	}
+	if delivery.Retry == nil {
+		delivery.Retry = brokerDelivery.Retry
+	}
+	if delivery.Retry == nil && delivery.DeadLetterSink != nil {
+		// The Broker may not have a dead letter sink, and therefore no retry count.
+		withNS := apis.WithinParent(ctx, t.ObjectMeta)
+		if defaults := brokerdelivery.FromContextOrDefaults(withNS).BrokerDeliverySpecDefaults; defaults != nil {
+			delivery.Retry = defaults.Retry(apis.ParentMeta(withNS).Namespace)
+		}
+	}
+	return delivery
+}
@@ -21,8 +21,13 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
 )
 
 func TestTriggerDefaults(t *testing.T) {
@@ -32,41 +37,45 @@ func TestTriggerDefaults(t *testing.T) {
 	}{
 		"non-knative v1alpha1 service subscriber": {
 			initial: Trigger{
-				Spec: eventingv1beta1.TriggerSpec{
-					Subscriber: duckv1.Destination{
-						Ref: &duckv1.KReference{
-							APIVersion: "serving.knative.dev/v1",
-							Kind:       "Service",
-						},
-					},
-				}},
-			expected: Trigger{
-				Spec: eventingv1beta1.TriggerSpec{
-					Subscriber: duckv1.Destination{
-						Ref: &duckv1.KReference{
-							APIVersion: "serving.knative.dev/v1",
-							Kind:       "Service",
+				Spec: TriggerSpec{
+					TriggerSpec: eventingv1beta1.TriggerSpec{
+						Subscriber: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "serving.knative.dev/v1",
+								Kind:       "Service",
+							},
 						},
 					}}},
+			expected: Trigger{
+				Spec: TriggerSpec{
+					TriggerSpec: eventingv1beta1.TriggerSpec{
+						Subscriber: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "serving.knative.dev/v1",
+								Kind:       "Service",
+							},
+						}}}},
 		},
 		"knative v1alpha1 service subscriber": {
 			initial: Trigger{
-				Spec: eventingv1beta1.TriggerSpec{
-					Subscriber: duckv1.Destination{
-						Ref: &duckv1.KReference{
-							APIVersion: "serving.knative.dev/v1alpha1",
-							Kind:       "Service",
-						},
-					},
-				}},
-			expected: Trigger{
-				Spec: eventingv1beta1.TriggerSpec{
-					Subscriber: duckv1.Destination{
-						Ref: &duckv1.KReference{
-							APIVersion: "serving.knative.dev/v1",
-							Kind:       "Service",
+				Spec: TriggerSpec{
+					TriggerSpec: eventingv1beta1.TriggerSpec{
+						Subscriber: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "serving.knative.dev/v1alpha1",
+								Kind:       "Service",
+							},
 						},
 					}}},
+			expected: Trigger{
+				Spec: TriggerSpec{
+					TriggerSpec: eventingv1beta1.TriggerSpec{
+						Subscriber: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "serving.knative.dev/v1",
+								Kind:       "Service",
+							},
+						}}}},
 		},
 	}
 
@@ -79,3 +88,151 @@ func TestTriggerDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestTrigger_DeliverySpecWithDefaults(t *testing.T) {
+	brokerBackoffDelay := "PT5S"
+	brokerBackoffPolicy := eventingduckv1beta1.BackoffPolicyLinear
+	brokerRetry := int32(3)
+	triggerBackoffDelay := "PT10S"
+	triggerRetry := int32(20)
+	brokerSink := &duckv1.Destination{
+		URI: &apis.URL{
+			Scheme: "pubsub",
+			Host:   "broker-dead-letter-topic-id",
+		},
+	}
+	triggerSink := &duckv1.Destination{
+		URI: &apis.URL{
+			Scheme: "http",
+			Host:   "trigger-dead-letter.example.com",
+		},
+	}
+
+	testCases := map[string]struct {
+		trigger  Trigger
+		broker   Broker
+		expected *eventingduckv1beta1.DeliverySpec
+	}{
+		"everything from broker": {
+			broker: Broker{
+				Spec: eventingv1beta1.BrokerSpec{
+					Delivery: &eventingduckv1beta1.DeliverySpec{
+						BackoffDelay:   &brokerBackoffDelay,
+						BackoffPolicy:  &brokerBackoffPolicy,
+						DeadLetterSink: brokerSink,
+						Retry:          &brokerRetry,
+					},
+				},
+			},
+			expected: &eventingduckv1beta1.DeliverySpec{
+				BackoffDelay:   &brokerBackoffDelay,
+				BackoffPolicy:  &brokerBackoffPolicy,
+				DeadLetterSink: brokerSink,
+				Retry:          &brokerRetry,
+			},
+		},
+		"everything from cluster defaults": {
+			expected: &eventingduckv1beta1.DeliverySpec{
+				BackoffDelay:  &clusterDefaultedBackoffDelay,
+				BackoffPolicy: &clusterDefaultedBackoffPolicy,
+				DeadLetterSink: &duckv1.Destination{
+					URI: &apis.URL{
+						Scheme: "pubsub",
+						Host:   "cluster-default-dead-letter-topic-id",
+					},
+				},
+				Retry: &clusterDefaultedRetry,
+			},
+		},
+		"trigger overrides some fields": {
+			trigger: Trigger{
+				Spec: TriggerSpec{
+					Delivery: &eventingduckv1beta1.DeliverySpec{
+						BackoffDelay: &triggerBackoffDelay,
+						Retry:        &triggerRetry,
+					},
+				},
+			},
+			broker: Broker{
+				Spec: eventingv1beta1.BrokerSpec{
+					Delivery: &eventingduckv1beta1.DeliverySpec{
+						BackoffDelay:   &brokerBackoffDelay,
+						BackoffPolicy:  &brokerBackoffPolicy,
+						DeadLetterSink: brokerSink,
+						Retry:          &brokerRetry,
+					},
+				},
+			},
+			expected: &eventingduckv1beta1.DeliverySpec{
+				BackoffDelay:   &triggerBackoffDelay,
+				BackoffPolicy:  &brokerBackoffPolicy,
+				DeadLetterSink: brokerSink,
+				Retry:          &triggerRetry,
+			},
+		},
+		"trigger overrides some fields with the delivery annotation": {
+			trigger: Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DeliveryAnnotationKey: `{"backoffDelay": "PT10S", "retry": 20}`,
+					},
+				},
+			},
+			broker: Broker{
+				Spec: eventingv1beta1.BrokerSpec{
+					Delivery: &eventingduckv1beta1.DeliverySpec{
+						BackoffDelay:   &brokerBackoffDelay,
+						BackoffPolicy:  &brokerBackoffPolicy,
+						DeadLetterSink: brokerSink,
+						Retry:          &brokerRetry,
+					},
+				},
+			},
+			expected: &eventingduckv1beta1.DeliverySpec{
+				BackoffDelay:   &triggerBackoffDelay,
+				BackoffPolicy:  &brokerBackoffPolicy,
+				DeadLetterSink: brokerSink,
+				Retry:          &triggerRetry,
+			},
+		},
+		"trigger dead letter sink with retry from namespace defaults": {
+			trigger: Trigger{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "mynamespace"},
+				Spec: TriggerSpec{
+					Delivery: &eventingduckv1beta1.DeliverySpec{
+						DeadLetterSink: triggerSink,
+					},
+				},
+			},
+			broker: Broker{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "mynamespace"},
+				Spec: eventingv1beta1.BrokerSpec{
+					Delivery: &eventingduckv1beta1.DeliverySpec{
+						BackoffDelay:  &brokerBackoffDelay,
+						BackoffPolicy: &brokerBackoffPolicy,
+					},
+				},
+			},
+			expected: &eventingduckv1beta1.DeliverySpec{
+				BackoffDelay:   &brokerBackoffDelay,
+				BackoffPolicy:  &brokerBackoffPolicy,
+				DeadLetterSink: triggerSink,
+				Retry:          &nsDefaultedRetry,
+			},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			ctx := brokerdelivery.ToContext(context.Background(), defaultConfig)
+			broker := tc.broker.DeepCopy()
+			got := tc.trigger.DeliverySpecWithDefaults(ctx, broker)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Error("Unexpected delivery spec (-want, +got):", diff)
+			}
+			if diff := cmp.Diff(&tc.broker, broker); diff != "" {
+				t.Error("Unexpected broker modification (-want, +got):", diff)
+			}
+		})
+	}
+}
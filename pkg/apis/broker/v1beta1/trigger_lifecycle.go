@@ -17,10 +17,14 @@ limitations under the License.
 package v1beta1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
 )
 
 var triggerCondSet = apis.NewLivingConditionSet(
@@ -34,6 +38,30 @@ var triggerCondSet = apis.NewLivingConditionSet(
 const (
 	TriggerConditionTopic        apis.ConditionType = "TopicReady"
 	TriggerConditionSubscription apis.ConditionType = "SubscriptionReady"
+
+	// TriggerConditionCircuitBreaker reports the state of the Trigger's
+	// circuit breaker, if enabled. It does not affect the Trigger readiness.
+	TriggerConditionCircuitBreaker apis.ConditionType = "CircuitBreakerClosed"
+
+	// TriggerConditionReplay reports whether the snapshot and replay requested
+	// for the Trigger's subscription succeeded. It does not affect the Trigger
+	// readiness.
+	TriggerConditionReplay apis.ConditionType = "Replayed"
+
+	// TriggerConditionIsolatedSubscription reports whether the dedicated
+	// subscription of an isolated Trigger is ready, in which case the data
+	// plane delivers the Trigger's events from it. Its failures are reported
+	// by TriggerConditionSubscription.
+	TriggerConditionIsolatedSubscription apis.ConditionType = "IsolatedSubscriptionReady"
+
+	// TriggerConditionPaused reports whether the deliveries to the subscriber
+	// are paused. A paused Trigger stays ready.
+	TriggerConditionPaused apis.ConditionType = "Paused"
+
+	// TriggerConditionBacklogHealthy reports whether the backlog of the
+	// Trigger's subscriptions is below its thresholds, if the backlog
+	// reporting is enabled. It does not affect the Trigger readiness.
+	TriggerConditionBacklogHealthy apis.ConditionType = "BacklogHealthy"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -159,3 +187,87 @@ func (ts *TriggerStatus) PropagateDependencyStatus(src *duckv1.Source) {
 		ts.MarkDependencyUnknown("DependencyUnknown", "The status of Dependency is invalid: %v", sc.Status)
 	}
 }
+
+func (ts *TriggerStatus) MarkCircuitBreakerClosed() {
+	triggerCondSet.Manage(ts).MarkTrue(TriggerConditionCircuitBreaker)
+}
+
+func (ts *TriggerStatus) MarkCircuitBreakerOpen(reason, format string, args ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionCircuitBreaker, reason, format, args...)
+}
+
+func (ts *TriggerStatus) MarkCircuitBreakerUnknown(reason, format string, args ...interface{}) {
+	triggerCondSet.Manage(ts).MarkUnknown(TriggerConditionCircuitBreaker, reason, format, args...)
+}
+
+// ClearCircuitBreakerCondition removes the circuit breaker condition, e.g.
+// when the circuit breaker is disabled.
+func (ts *TriggerStatus) ClearCircuitBreakerCondition() {
+	triggerCondSet.Manage(ts).ClearCondition(TriggerConditionCircuitBreaker)
+}
+
+func (ts *TriggerStatus) MarkReplayed(reason, format string, args ...interface{}) {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionReplay, reason, format, args...)
+}
+
+func (ts *TriggerStatus) MarkReplayFailed(reason, format string, args ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionReplay, reason, format, args...)
+}
+
+// ClearReplayCondition removes the replay condition, e.g. when no snapshot or
+// replay is requested.
+func (ts *TriggerStatus) ClearReplayCondition() {
+	triggerCondSet.Manage(ts).ClearCondition(TriggerConditionReplay)
+}
+
+func (ts *TriggerStatus) MarkIsolatedSubscriptionReady() {
+	triggerCondSet.Manage(ts).MarkTrue(TriggerConditionIsolatedSubscription)
+}
+
+// MarkIsolatedSubscriptionUnknown reports the isolated subscription not ready,
+// e.g. while it is recreated, so that the data plane delivers the events of
+// the Trigger from the Broker's decouple subscription.
+func (ts *TriggerStatus) MarkIsolatedSubscriptionUnknown(reason, format string, args ...interface{}) {
+	triggerCondSet.Manage(ts).MarkUnknown(TriggerConditionIsolatedSubscription, reason, format, args...)
+}
+
+// ClearIsolatedSubscriptionCondition removes the isolated subscription
+// condition, e.g. when the Trigger is no longer isolated.
+func (ts *TriggerStatus) ClearIsolatedSubscriptionCondition() {
+	triggerCondSet.Manage(ts).ClearCondition(TriggerConditionIsolatedSubscription)
+}
+
+func (ts *TriggerStatus) MarkPaused() {
+	triggerCondSet.Manage(ts).MarkTrueWithReason(TriggerConditionPaused, "Paused", "Deliveries to the subscriber are paused, the events accumulate in the Trigger's subscription")
+}
+
+// ClearPausedCondition removes the paused condition once the Trigger is
+// resumed.
+func (ts *TriggerStatus) ClearPausedCondition() {
+	triggerCondSet.Manage(ts).ClearCondition(TriggerConditionPaused)
+}
+
+// SetBacklog reports the backlog of the Trigger's subscriptions in the status
+// annotations.
+func (ts *TriggerStatus) SetBacklog(undeliveredMessages int64, oldestUnackedMessageAge time.Duration) {
+	ts.Annotations = duck.SetBacklogStatusAnnotations(ts.Annotations, undeliveredMessages, oldestUnackedMessageAge)
+}
+
+func (ts *TriggerStatus) MarkBacklogHealthy() {
+	triggerCondSet.Manage(ts).MarkTrue(TriggerConditionBacklogHealthy)
+}
+
+func (ts *TriggerStatus) MarkBacklogThresholdExceeded(format string, args ...interface{}) {
+	triggerCondSet.Manage(ts).MarkFalse(TriggerConditionBacklogHealthy, "BacklogThresholdExceeded", format, args...)
+}
+
+func (ts *TriggerStatus) MarkBacklogUnknown(reason, format string, args ...interface{}) {
+	triggerCondSet.Manage(ts).MarkUnknown(TriggerConditionBacklogHealthy, reason, format, args...)
+}
+
+// ClearBacklog removes the backlog status annotations and condition once the
+// backlog reporting is disabled.
+func (ts *TriggerStatus) ClearBacklog() {
+	ts.Annotations = duck.ClearBacklogStatusAnnotations(ts.Annotations)
+	triggerCondSet.Manage(ts).ClearCondition(TriggerConditionBacklogHealthy)
+}
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -428,3 +429,147 @@ func TestTriggerConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestTriggerCircuitBreakerCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+
+	ts.MarkCircuitBreakerOpen("CircuitBreakerOpen", "induced failure")
+	if got := ts.GetCondition(TriggerConditionCircuitBreaker).Status; got != corev1.ConditionFalse {
+		t.Errorf("unexpected circuit breaker condition: want %v, got %v", corev1.ConditionFalse, got)
+	}
+	if !ts.IsReady() {
+		t.Error("an open circuit breaker should not affect readiness")
+	}
+
+	ts.MarkCircuitBreakerUnknown("CircuitBreakerHalfOpen", "probing")
+	if got := ts.GetCondition(TriggerConditionCircuitBreaker).Status; got != corev1.ConditionUnknown {
+		t.Errorf("unexpected circuit breaker condition: want %v, got %v", corev1.ConditionUnknown, got)
+	}
+
+	ts.MarkCircuitBreakerClosed()
+	if got := ts.GetCondition(TriggerConditionCircuitBreaker).Status; got != corev1.ConditionTrue {
+		t.Errorf("unexpected circuit breaker condition: want %v, got %v", corev1.ConditionTrue, got)
+	}
+	if !ts.IsReady() {
+		t.Error("expected happy true, got false")
+	}
+
+	ts.ClearCircuitBreakerCondition()
+	if got := ts.GetCondition(TriggerConditionCircuitBreaker); got != nil {
+		t.Errorf("expected the circuit breaker condition to be cleared, got %v", got)
+	}
+}
+
+func TestTriggerReplayCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+
+	ts.MarkReplayFailed("SeekFailed", "induced failure")
+	if got := ts.GetCondition(TriggerConditionReplay).Status; got != corev1.ConditionFalse {
+		t.Errorf("unexpected replay condition: want %v, got %v", corev1.ConditionFalse, got)
+	}
+	if !ts.IsReady() {
+		t.Error("a failed replay should not affect readiness")
+	}
+
+	ts.MarkReplayed("Replayed", "replayed")
+	if got := ts.GetCondition(TriggerConditionReplay).Status; got != corev1.ConditionTrue {
+		t.Errorf("unexpected replay condition: want %v, got %v", corev1.ConditionTrue, got)
+	}
+
+	ts.ClearReplayCondition()
+	if got := ts.GetCondition(TriggerConditionReplay); got != nil {
+		t.Errorf("expected the replay condition to be cleared, got %v", got)
+	}
+}
+
+func TestTriggerIsolatedSubscriptionCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+
+	ts.MarkIsolatedSubscriptionReady()
+	if got := ts.GetCondition(TriggerConditionIsolatedSubscription).Status; got != corev1.ConditionTrue {
+		t.Errorf("unexpected isolated subscription condition: want %v, got %v", corev1.ConditionTrue, got)
+	}
+	if !ts.IsReady() {
+		t.Error("the isolated subscription condition should not affect readiness")
+	}
+
+	ts.ClearIsolatedSubscriptionCondition()
+	if got := ts.GetCondition(TriggerConditionIsolatedSubscription); got != nil {
+		t.Errorf("expected the isolated subscription condition to be cleared, got %v", got)
+	}
+}
+
+func TestTriggerPausedCondition(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+
+	ts.MarkPaused()
+	if got := ts.GetCondition(TriggerConditionPaused).Status; got != corev1.ConditionTrue {
+		t.Errorf("unexpected paused condition: want %v, got %v", corev1.ConditionTrue, got)
+	}
+	if !ts.IsReady() {
+		t.Error("a paused Trigger should stay ready")
+	}
+
+	ts.ClearPausedCondition()
+	if got := ts.GetCondition(TriggerConditionPaused); got != nil {
+		t.Errorf("expected the paused condition to be cleared, got %v", got)
+	}
+}
+
+func TestTriggerBacklog(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.PropagateBrokerStatus(TestHelper.ReadyBrokerStatus())
+	ts.MarkTopicReady()
+	ts.MarkSubscriptionReady()
+	ts.MarkSubscriberResolvedSucceeded()
+	ts.MarkDependencySucceeded()
+
+	ts.SetBacklog(1500, 10*time.Minute)
+	ts.MarkBacklogThresholdExceeded("backlog of %d messages", 1500)
+	wantAnnotations := map[string]string{
+		"events.cloud.google.com/undeliveredMessages":     "1500",
+		"events.cloud.google.com/oldestUnackedMessageAge": "10m0s",
+	}
+	if diff := cmp.Diff(wantAnnotations, ts.Annotations); diff != "" {
+		t.Errorf("unexpected status annotations (-want, +got) = %v", diff)
+	}
+	if got := ts.GetCondition(TriggerConditionBacklogHealthy).Status; got != corev1.ConditionFalse {
+		t.Errorf("unexpected backlog condition: want %v, got %v", corev1.ConditionFalse, got)
+	}
+	if !ts.IsReady() {
+		t.Error("a Trigger with a backlog exceeding its thresholds should stay ready")
+	}
+
+	ts.MarkBacklogHealthy()
+	if got := ts.GetCondition(TriggerConditionBacklogHealthy).Status; got != corev1.ConditionTrue {
+		t.Errorf("unexpected backlog condition: want %v, got %v", corev1.ConditionTrue, got)
+	}
+
+	ts.ClearBacklog()
+	if ts.Annotations != nil {
+		t.Errorf("expected the backlog status annotations to be cleared, got %v", ts.Annotations)
+	}
+	if got := ts.GetCondition(TriggerConditionBacklogHealthy); got != nil {
+		t.Errorf("expected the backlog condition to be cleared, got %v", got)
+	}
+}
@@ -17,13 +17,22 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
 )
 
 const (
@@ -33,6 +42,66 @@ const (
 	// InjectionAnnotation is the annotation key used to enable knative eventing injection for a namespace and automatically create a default broker.
 	// This will be used when the client creates a trigger paired with default broker and the default broker doesn't exist in the namespace
 	InjectionAnnotation = "knative-eventing-injection"
+	// DeliveryAnnotationKey is the annotation key used to set the delivery spec of a Trigger, as a JSON
+	// DeliverySpec, e.g. `{"retry": 5, "backoffPolicy": "exponential"}`. The eventing webhook rejects spec.delivery
+	// on a Trigger, so the annotation is the way to set it until eventing supports it.
+	DeliveryAnnotationKey = "events.cloud.google.com/delivery"
+	// BatchMaxSizeAnnotationKey is the annotation key used to enable batched delivery for a Trigger. Its value is the
+	// maximum number of events delivered to the subscriber in a single request.
+	BatchMaxSizeAnnotationKey = "events.cloud.google.com/batchMaxSize"
+	// BatchMaxLingerAnnotationKey is the annotation key used to set how long an event waits for its batch to fill
+	// before the batch is delivered, e.g. "500ms". It defaults to DefaultBatchMaxLinger.
+	BatchMaxLingerAnnotationKey = "events.cloud.google.com/batchMaxLinger"
+	// MaxConcurrencyAnnotationKey is the annotation key used to limit the number of concurrent deliveries to the
+	// subscriber of a Trigger, per fanout and retry replica.
+	MaxConcurrencyAnnotationKey = "events.cloud.google.com/maxConcurrency"
+	// MaxEventsPerSecondAnnotationKey is the annotation key used to limit the rate of events delivered to the
+	// subscriber of a Trigger, per fanout and retry replica.
+	MaxEventsPerSecondAnnotationKey = "events.cloud.google.com/maxEventsPerSecond"
+	// CircuitBreakerFailureThresholdAnnotationKey is the annotation key used to enable the circuit breaker of a
+	// Trigger. Its value is the number of consecutive failed deliveries that open the circuit breaker.
+	CircuitBreakerFailureThresholdAnnotationKey = "events.cloud.google.com/circuitBreakerFailureThreshold"
+	// CircuitBreakerOpenDurationAnnotationKey is the annotation key used to set how long the circuit breaker of a
+	// Trigger stays open before a delivery is attempted again, e.g. "1m". It defaults to
+	// DefaultCircuitBreakerOpenDuration.
+	CircuitBreakerOpenDurationAnnotationKey = "events.cloud.google.com/circuitBreakerOpenDuration"
+	// ReplayableAnnotationKey is the annotation key used to retain the acknowledged events of an isolated Trigger's
+	// subscription, so that events delivered successfully can be replayed too. Its value is either "true" or "false".
+	ReplayableAnnotationKey = "events.cloud.google.com/replayable"
+	// SnapshotAnnotationKey is the annotation key used to create a Pub/Sub snapshot of an isolated Trigger's
+	// subscription. Its value is the ID of the snapshot, which can later be replayed with ReplaySnapshotAnnotationKey.
+	SnapshotAnnotationKey = "events.cloud.google.com/snapshot"
+	// ReplayTimeAnnotationKey is the annotation key used to replay the events of an isolated Trigger's subscription
+	// published since a point in time, formatted as RFC 3339, e.g. "2021-01-02T15:04:05Z".
+	ReplayTimeAnnotationKey = "events.cloud.google.com/replayTime"
+	// ReplaySnapshotAnnotationKey is the annotation key used to replay the events of an isolated Trigger's
+	// subscription from a Pub/Sub snapshot. Its value is the ID of the snapshot.
+	ReplaySnapshotAnnotationKey = "events.cloud.google.com/replaySnapshot"
+	// IsolationAnnotationKey is the annotation key used to deliver the events of a Trigger from a dedicated Pub/Sub
+	// subscription, so that a slow subscriber doesn't hold back the other Triggers of the Broker. Its value is either
+	// IsolationSubscription or IsolationFilteredSubscription.
+	IsolationAnnotationKey = "events.cloud.google.com/isolation"
+	// PausedAnnotationKey is the annotation key used to pause the deliveries to the subscriber of a Trigger. The events
+	// of a paused Trigger accumulate in its Pub/Sub subscription until it is resumed.
+	PausedAnnotationKey = duck.PausedAnnotation
+
+	// IsolationSubscription isolates a Trigger with a subscription receiving all the events of the Broker.
+	IsolationSubscription = "subscription"
+	// IsolationFilteredSubscription isolates a Trigger with a subscription receiving only the events of the Broker
+	// which match the exact attributes of the Trigger's filter, as filtered by Pub/Sub.
+	IsolationFilteredSubscription = "filteredSubscription"
+
+	// DefaultBatchMaxLinger is the default maximum linger time of a batch.
+	DefaultBatchMaxLinger = 100 * time.Millisecond
+	// MaxBatchSize is the largest allowed batch size.
+	MaxBatchSize = 1000
+	// MinDeliveryTimeout is the shortest timeout of a delivery by the fanout and retry components. The linger time of
+	// a batch must be shorter, otherwise its events time out before the batch is delivered.
+	MinDeliveryTimeout = 10 * time.Second
+	// DefaultCircuitBreakerOpenDuration is the default time a circuit breaker stays open.
+	DefaultCircuitBreakerOpenDuration = 30 * time.Second
+	// MaxCircuitBreakerOpenDuration is the largest allowed time a circuit breaker stays open.
+	MaxCircuitBreakerOpenDuration = time.Hour
 )
 
 // +genclient
@@ -47,7 +116,7 @@ type Trigger struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	// Spec defines the desired state of the Trigger.
-	Spec eventingv1beta1.TriggerSpec `json:"spec,omitempty"`
+	Spec TriggerSpec `json:"spec,omitempty"`
 
 	// Status represents the current state of the Trigger. This data may be out of
 	// date.
@@ -72,6 +141,60 @@ var (
 	_ duckv1.KRShaped = (*Trigger)(nil)
 )
 
+// TriggerSpec defines the desired state of a Trigger.
+type TriggerSpec struct {
+	eventingv1beta1.TriggerSpec `json:",inline"`
+
+	// Delivery contains the delivery spec for this specific Trigger. Fields
+	// that are not set are defaulted from the Broker's delivery spec. It is
+	// rejected by the eventing webhook until eventing supports it, use
+	// DeliveryAnnotationKey instead.
+	// +optional
+	Delivery *eventingduckv1beta1.DeliverySpec `json:"delivery,omitempty"`
+
+	// Filters is a list of CloudEvents Subscriptions API filter expressions.
+	// An event must match all of them, as well as Filter, to be delivered to
+	// the subscriber.
+	// +optional
+	Filters []SubscriptionsAPIFilter `json:"filters,omitempty"`
+}
+
+// SubscriptionsAPIFilter is a filter expression of the CloudEvents
+// Subscriptions API. Exactly one of its fields must be set.
+type SubscriptionsAPIFilter struct {
+	// All evaluates to true if all the nested expressions evaluate to true.
+	// +optional
+	All []SubscriptionsAPIFilter `json:"all,omitempty"`
+
+	// Any evaluates to true if at least one of the nested expressions
+	// evaluates to true.
+	// +optional
+	Any []SubscriptionsAPIFilter `json:"any,omitempty"`
+
+	// Not evaluates to true if the nested expression evaluates to false.
+	// +optional
+	Not *SubscriptionsAPIFilter `json:"not,omitempty"`
+
+	// Exact evaluates to true if the value of the single given attribute
+	// exactly matches the given value.
+	// +optional
+	Exact map[string]string `json:"exact,omitempty"`
+
+	// Prefix evaluates to true if the value of the single given attribute
+	// starts with the given value.
+	// +optional
+	Prefix map[string]string `json:"prefix,omitempty"`
+
+	// Suffix evaluates to true if the value of the single given attribute
+	// ends with the given value.
+	// +optional
+	Suffix map[string]string `json:"suffix,omitempty"`
+
+	// SQL is a CloudEvents SQL expression that must evaluate to true.
+	// +optional
+	SQL string `json:"sql,omitempty"`
+}
+
 // TriggerStatus represents the current state of a Trigger.
 type TriggerStatus struct {
 	eventingv1beta1.TriggerStatus `json:",inline"`
@@ -123,3 +246,231 @@ func (*Trigger) GetConditionSet() apis.ConditionSet {
 func (t *Trigger) GetStatus() *duckv1.Status {
 	return &t.Status.Status
 }
+
+// DeliverySpec returns the delivery spec set on the Trigger, either in its
+// spec or with DeliveryAnnotationKey, or nil if neither is set. An invalid
+// annotation is ignored.
+func (t *Trigger) DeliverySpec() *eventingduckv1beta1.DeliverySpec {
+	if t.Spec.Delivery != nil {
+		return t.Spec.Delivery
+	}
+	delivery, _ := parseDeliverySpec(t.GetAnnotations())
+	return delivery
+}
+
+// parseDeliverySpec parses the delivery annotation, returning nil if it is
+// not set. Unknown fields are rejected, as they would be in spec.delivery.
+func parseDeliverySpec(annotations map[string]string) (*eventingduckv1beta1.DeliverySpec, error) {
+	v, ok := annotations[DeliveryAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(v))
+	decoder.DisallowUnknownFields()
+	delivery := &eventingduckv1beta1.DeliverySpec{}
+	if err := decoder.Decode(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// BatchPolicy returns the maximum size and linger time of the Trigger's
+// batches. A maximum size of zero means that batched delivery is disabled,
+// which is also the case if the annotations are invalid.
+func (t *Trigger) BatchPolicy() (maxSize int32, maxLinger time.Duration) {
+	annotations := t.GetAnnotations()
+	size, ok := parseBatchMaxSize(annotations)
+	if !ok || size == 0 {
+		return 0, 0
+	}
+	linger, ok := parseBatchMaxLinger(annotations)
+	if !ok {
+		return 0, 0
+	}
+	return size, linger
+}
+
+// parseBatchMaxSize parses the batch max size annotation, returning zero if
+// it is not set.
+func parseBatchMaxSize(annotations map[string]string) (int32, bool) {
+	size, ok := parsePositiveInt(annotations, BatchMaxSizeAnnotationKey)
+	if !ok || size > MaxBatchSize {
+		return 0, false
+	}
+	return size, true
+}
+
+// parseBatchMaxLinger parses the batch max linger annotation, returning
+// DefaultBatchMaxLinger if it is not set.
+func parseBatchMaxLinger(annotations map[string]string) (time.Duration, bool) {
+	v, ok := annotations[BatchMaxLingerAnnotationKey]
+	if !ok {
+		return DefaultBatchMaxLinger, true
+	}
+	linger, err := time.ParseDuration(v)
+	if err != nil || linger <= 0 || linger >= MinDeliveryTimeout {
+		return 0, false
+	}
+	return linger, true
+}
+
+// RateLimit returns the maximum number of concurrent deliveries and events per
+// second of the Trigger. Zero means no limit, which is also the case if the
+// corresponding annotation is invalid.
+func (t *Trigger) RateLimit() (maxConcurrency, maxEventsPerSecond int32) {
+	maxConcurrency, _ = parsePositiveInt(t.GetAnnotations(), MaxConcurrencyAnnotationKey)
+	maxEventsPerSecond, _ = parsePositiveInt(t.GetAnnotations(), MaxEventsPerSecondAnnotationKey)
+	return maxConcurrency, maxEventsPerSecond
+}
+
+// DeliveryAudience returns the audience of the ID token sent to the Trigger's
+// subscriber, inherited from the Broker unless the Trigger sets its own. An
+// empty string means that deliveries are not authenticated.
+func (t *Trigger) DeliveryAudience(b *Broker) string {
+	if audience, ok := t.GetAnnotations()[DeliveryAudienceAnnotationKey]; ok {
+		return audience
+	}
+	if b == nil {
+		return ""
+	}
+	return b.DeliveryAudience()
+}
+
+// CircuitBreaker returns the number of consecutive failed deliveries that
+// open the Trigger's circuit breaker, and how long it then stays open. A
+// failure threshold of zero means that the circuit breaker is disabled, which
+// is also the case if the annotations are invalid.
+func (t *Trigger) CircuitBreaker() (failureThreshold int32, openDuration time.Duration) {
+	annotations := t.GetAnnotations()
+	threshold, ok := parsePositiveInt(annotations, CircuitBreakerFailureThresholdAnnotationKey)
+	if !ok || threshold == 0 {
+		return 0, 0
+	}
+	openDuration, ok = parseCircuitBreakerOpenDuration(annotations)
+	if !ok {
+		return 0, 0
+	}
+	return threshold, openDuration
+}
+
+// parseCircuitBreakerOpenDuration parses the circuit breaker open duration
+// annotation, returning DefaultCircuitBreakerOpenDuration if it is not set.
+func parseCircuitBreakerOpenDuration(annotations map[string]string) (time.Duration, bool) {
+	v, ok := annotations[CircuitBreakerOpenDurationAnnotationKey]
+	if !ok {
+		return DefaultCircuitBreakerOpenDuration, true
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 || d > MaxCircuitBreakerOpenDuration {
+		return 0, false
+	}
+	return d, true
+}
+
+// IsPaused returns true if the deliveries to the subscriber of the Trigger are
+// paused.
+func (t *Trigger) IsPaused() bool {
+	return duck.IsPaused(t.GetAnnotations())
+}
+
+// Isolation returns whether the events of the Trigger are delivered from a
+// dedicated subscription, and whether that subscription is filtered by Pub/Sub.
+// The Trigger is not isolated if the annotation is invalid.
+func (t *Trigger) Isolation() (isolated, filtered bool) {
+	switch t.GetAnnotations()[IsolationAnnotationKey] {
+	case IsolationSubscription:
+		return true, false
+	case IsolationFilteredSubscription:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// IsReplayable returns true if the isolated subscription of the Trigger
+// retains its acknowledged events. It is false if the Trigger is not isolated
+// or the annotation is invalid.
+func (t *Trigger) IsReplayable() bool {
+	if isolated, _ := t.Isolation(); !isolated {
+		return false
+	}
+	replayable, _ := strconv.ParseBool(t.GetAnnotations()[ReplayableAnnotationKey])
+	return replayable
+}
+
+// Snapshot returns the ID of the Pub/Sub snapshot to create of the isolated
+// subscription of the Trigger. An empty string means that no snapshot is
+// requested, which is also the case if the Trigger is not isolated or the
+// annotation is invalid.
+func (t *Trigger) Snapshot() string {
+	if isolated, _ := t.Isolation(); !isolated {
+		return ""
+	}
+	snapshot, _ := parseSnapshotID(t.GetAnnotations(), SnapshotAnnotationKey)
+	return snapshot
+}
+
+// Replay returns the point in time or the ID of the Pub/Sub snapshot to seek
+// the isolated subscription of the Trigger to, at most one of which is set.
+// Zero values mean that no replay is requested, which is also the case if the
+// Trigger is not isolated or the annotations are invalid.
+func (t *Trigger) Replay() (replayTime time.Time, snapshot string) {
+	if isolated, _ := t.Isolation(); !isolated {
+		return time.Time{}, ""
+	}
+	annotations := t.GetAnnotations()
+	replayTime, ok := parseReplayTime(annotations)
+	if !ok {
+		return time.Time{}, ""
+	}
+	snapshot, ok = parseSnapshotID(annotations, ReplaySnapshotAnnotationKey)
+	if !ok || (snapshot != "" && !replayTime.IsZero()) {
+		return time.Time{}, ""
+	}
+	return replayTime, snapshot
+}
+
+// parseReplayTime parses the replay time annotation, returning the zero time
+// if it is not set.
+func parseReplayTime(annotations map[string]string) (time.Time, bool) {
+	v, ok := annotations[ReplayTimeAnnotationKey]
+	if !ok {
+		return time.Time{}, true
+	}
+	replayTime, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return replayTime, true
+}
+
+// validSnapshotID matches valid Pub/Sub snapshot IDs, see
+// https://cloud.google.com/pubsub/docs/admin#resource_names.
+var validSnapshotID = regexp.MustCompile(`^[a-zA-Z][-a-zA-Z0-9_.~+%]{2,254}$`)
+
+// parseSnapshotID parses the annotation as a Pub/Sub snapshot ID, returning
+// an empty string if it is not set.
+func parseSnapshotID(annotations map[string]string, key string) (string, bool) {
+	v, ok := annotations[key]
+	if !ok {
+		return "", true
+	}
+	if !validSnapshotID.MatchString(v) || strings.HasPrefix(v, "goog") {
+		return "", false
+	}
+	return v, true
+}
+
+// parsePositiveInt parses the annotation as a positive integer, returning
+// zero if it is not set.
+func parsePositiveInt(annotations map[string]string, key string) (int32, bool) {
+	v, ok := annotations[key]
+	if !ok {
+		return 0, true
+	}
+	i, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || i < 1 {
+		return 0, false
+	}
+	return int32(i), true
+}
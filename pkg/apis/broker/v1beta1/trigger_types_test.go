@@ -18,10 +18,11 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
 )
 
@@ -40,10 +41,10 @@ func TestTrigger_GetGroupVersionKind(t *testing.T) {
 
 func TestTrigger_GetUntypedSpec(t *testing.T) {
 	b := Trigger{
-		Spec: eventingv1beta1.TriggerSpec{},
+		Spec: TriggerSpec{},
 	}
 	s := b.GetUntypedSpec()
-	if _, ok := s.(eventingv1beta1.TriggerSpec); !ok {
+	if _, ok := s.(TriggerSpec); !ok {
 		t.Errorf("untyped spec was not a TriggerSpec")
 	}
 }
@@ -64,3 +65,295 @@ func TestTrigger_GetStatus(t *testing.T) {
 		t.Errorf("GetStatus=%v, want=%v", got, want)
 	}
 }
+
+func TestTrigger_BatchPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		wantMaxSize   int32
+		wantMaxLinger time.Duration
+	}{{
+		name: "not batched",
+	}, {
+		name:          "default linger",
+		annotations:   map[string]string{BatchMaxSizeAnnotationKey: "10"},
+		wantMaxSize:   10,
+		wantMaxLinger: DefaultBatchMaxLinger,
+	}, {
+		name: "custom linger",
+		annotations: map[string]string{
+			BatchMaxSizeAnnotationKey:   "10",
+			BatchMaxLingerAnnotationKey: "2s",
+		},
+		wantMaxSize:   10,
+		wantMaxLinger: 2 * time.Second,
+	}, {
+		name:        "invalid size",
+		annotations: map[string]string{BatchMaxSizeAnnotationKey: "many"},
+	}, {
+		name: "invalid linger",
+		annotations: map[string]string{
+			BatchMaxSizeAnnotationKey:   "10",
+			BatchMaxLingerAnnotationKey: "-1s",
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			maxSize, maxLinger := tr.BatchPolicy()
+			if maxSize != test.wantMaxSize || maxLinger != test.wantMaxLinger {
+				t.Errorf("BatchPolicy=(%v, %v), want=(%v, %v)", maxSize, maxLinger, test.wantMaxSize, test.wantMaxLinger)
+			}
+		})
+	}
+}
+
+func TestTrigger_RateLimit(t *testing.T) {
+	tests := []struct {
+		name                   string
+		annotations            map[string]string
+		wantMaxConcurrency     int32
+		wantMaxEventsPerSecond int32
+	}{{
+		name: "no limit",
+	}, {
+		name: "limits",
+		annotations: map[string]string{
+			MaxConcurrencyAnnotationKey:     "5",
+			MaxEventsPerSecondAnnotationKey: "100",
+		},
+		wantMaxConcurrency:     5,
+		wantMaxEventsPerSecond: 100,
+	}, {
+		name: "invalid limit",
+		annotations: map[string]string{
+			MaxConcurrencyAnnotationKey:     "0",
+			MaxEventsPerSecondAnnotationKey: "100",
+		},
+		wantMaxEventsPerSecond: 100,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			maxConcurrency, maxEventsPerSecond := tr.RateLimit()
+			if maxConcurrency != test.wantMaxConcurrency || maxEventsPerSecond != test.wantMaxEventsPerSecond {
+				t.Errorf("RateLimit=(%v, %v), want=(%v, %v)", maxConcurrency, maxEventsPerSecond, test.wantMaxConcurrency, test.wantMaxEventsPerSecond)
+			}
+		})
+	}
+}
+
+func TestTrigger_CircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name                 string
+		annotations          map[string]string
+		wantFailureThreshold int32
+		wantOpenDuration     time.Duration
+	}{{
+		name: "disabled",
+	}, {
+		name: "default open duration",
+		annotations: map[string]string{
+			CircuitBreakerFailureThresholdAnnotationKey: "5",
+		},
+		wantFailureThreshold: 5,
+		wantOpenDuration:     DefaultCircuitBreakerOpenDuration,
+	}, {
+		name: "open duration",
+		annotations: map[string]string{
+			CircuitBreakerFailureThresholdAnnotationKey: "5",
+			CircuitBreakerOpenDurationAnnotationKey:     "1m",
+		},
+		wantFailureThreshold: 5,
+		wantOpenDuration:     time.Minute,
+	}, {
+		name: "open duration only",
+		annotations: map[string]string{
+			CircuitBreakerOpenDurationAnnotationKey: "1m",
+		},
+	}, {
+		name: "invalid open duration",
+		annotations: map[string]string{
+			CircuitBreakerFailureThresholdAnnotationKey: "5",
+			CircuitBreakerOpenDurationAnnotationKey:     "2h",
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			failureThreshold, openDuration := tr.CircuitBreaker()
+			if failureThreshold != test.wantFailureThreshold || openDuration != test.wantOpenDuration {
+				t.Errorf("CircuitBreaker=(%v, %v), want=(%v, %v)", failureThreshold, openDuration, test.wantFailureThreshold, test.wantOpenDuration)
+			}
+		})
+	}
+}
+
+func TestTrigger_IsReplayable(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{{
+		name: "not requested",
+		annotations: map[string]string{
+			IsolationAnnotationKey: IsolationSubscription,
+		},
+	}, {
+		name: "replayable",
+		annotations: map[string]string{
+			IsolationAnnotationKey:  IsolationSubscription,
+			ReplayableAnnotationKey: "true",
+		},
+		want: true,
+	}, {
+		name: "not isolated",
+		annotations: map[string]string{
+			ReplayableAnnotationKey: "true",
+		},
+	}, {
+		name: "invalid",
+		annotations: map[string]string{
+			IsolationAnnotationKey:  IsolationSubscription,
+			ReplayableAnnotationKey: "yes",
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := tr.IsReplayable(); got != test.want {
+				t.Errorf("IsReplayable=%v, want=%v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTrigger_Replay(t *testing.T) {
+	tests := []struct {
+		name             string
+		annotations      map[string]string
+		wantSnapshot     string
+		wantReplayTime   time.Time
+		wantReplaySnapID string
+	}{{
+		name: "not requested",
+	}, {
+		name: "snapshot",
+		annotations: map[string]string{
+			IsolationAnnotationKey: IsolationSubscription,
+			SnapshotAnnotationKey:  "before-deploy",
+		},
+		wantSnapshot: "before-deploy",
+	}, {
+		name: "replay time",
+		annotations: map[string]string{
+			IsolationAnnotationKey:  IsolationSubscription,
+			ReplayTimeAnnotationKey: "2021-01-02T15:04:05Z",
+		},
+		wantReplayTime: time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC),
+	}, {
+		name: "replay snapshot",
+		annotations: map[string]string{
+			IsolationAnnotationKey:      IsolationSubscription,
+			SnapshotAnnotationKey:       "before-deploy",
+			ReplaySnapshotAnnotationKey: "before-deploy",
+		},
+		wantSnapshot:     "before-deploy",
+		wantReplaySnapID: "before-deploy",
+	}, {
+		name: "replay time and snapshot",
+		annotations: map[string]string{
+			IsolationAnnotationKey:      IsolationSubscription,
+			ReplayTimeAnnotationKey:     "2021-01-02T15:04:05Z",
+			ReplaySnapshotAnnotationKey: "before-deploy",
+		},
+	}, {
+		name: "not isolated",
+		annotations: map[string]string{
+			SnapshotAnnotationKey:   "before-deploy",
+			ReplayTimeAnnotationKey: "2021-01-02T15:04:05Z",
+		},
+	}, {
+		name: "invalid",
+		annotations: map[string]string{
+			IsolationAnnotationKey:  IsolationSubscription,
+			SnapshotAnnotationKey:   "goog-snapshot",
+			ReplayTimeAnnotationKey: "yesterday",
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := tr.Snapshot(); got != test.wantSnapshot {
+				t.Errorf("Snapshot=%q, want=%q", got, test.wantSnapshot)
+			}
+			replayTime, snapshot := tr.Replay()
+			if !replayTime.Equal(test.wantReplayTime) || snapshot != test.wantReplaySnapID {
+				t.Errorf("Replay=(%v, %q), want=(%v, %q)", replayTime, snapshot, test.wantReplayTime, test.wantReplaySnapID)
+			}
+		})
+	}
+}
+
+func TestTrigger_Isolation(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantIsolated bool
+		wantFiltered bool
+	}{{
+		name: "not isolated",
+	}, {
+		name:         "subscription",
+		annotations:  map[string]string{IsolationAnnotationKey: IsolationSubscription},
+		wantIsolated: true,
+	}, {
+		name:         "filtered subscription",
+		annotations:  map[string]string{IsolationAnnotationKey: IsolationFilteredSubscription},
+		wantIsolated: true,
+		wantFiltered: true,
+	}, {
+		name:        "invalid",
+		annotations: map[string]string{IsolationAnnotationKey: "true"},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			isolated, filtered := tr.Isolation()
+			if isolated != test.wantIsolated || filtered != test.wantFiltered {
+				t.Errorf("Isolation=(%v, %v), want=(%v, %v)", isolated, filtered, test.wantIsolated, test.wantFiltered)
+			}
+		})
+	}
+}
+
+func TestTrigger_DeliveryAudience(t *testing.T) {
+	broker := &Broker{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{DeliveryAudienceAnnotationKey: "broker-audience"},
+	}}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		broker      *Broker
+		want        string
+	}{{
+		name: "not authenticated",
+	}, {
+		name:   "inherited from broker",
+		broker: broker,
+		want:   "broker-audience",
+	}, {
+		name:        "trigger audience",
+		annotations: map[string]string{DeliveryAudienceAnnotationKey: "trigger-audience"},
+		broker:      broker,
+		want:        "trigger-audience",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tr := &Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations}}
+			if got := tr.DeliveryAudience(test.broker); got != test.want {
+				t.Errorf("DeliveryAudience=%q, want=%q", got, test.want)
+			}
+		})
+	}
+}
@@ -18,13 +18,227 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strconv"
 
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	"github.com/google/knative-gcp/pkg/broker/cesql"
 )
 
+// validAttributeName matches valid CloudEvents attribute names.
+var validAttributeName = regexp.MustCompile(`^[a-z0-9]+$`)
+
 // Validate the Trigger.
 func (t *Trigger) Validate(ctx context.Context) *apis.FieldError {
-	// The Google Cloud Broker doesn't have any custom validations. The
-	// eventing webhook will run the usual validations.
+	// We validate the Trigger's delivery spec, filters, batch policy, rate
+	// limit, circuit breaker, replay, isolation, pause and delivery audience.
+	// The eventing webhook will run the other usual validations.
+	var errs *apis.FieldError
+	withNS := apis.AllowDifferentNamespace(apis.WithinParent(ctx, t.ObjectMeta))
+	if t.Spec.Delivery != nil {
+		errs = errs.Also(ValidateTriggerDeliverySpec(withNS, t.Spec.Delivery).ViaField("spec", "delivery"))
+	}
+	errs = errs.Also(validateDeliveryAnnotation(withNS, t.Spec.Delivery, t.GetAnnotations()))
+	for i, f := range t.Spec.Filters {
+		errs = errs.Also(ValidateSubscriptionsAPIFilter(&f).ViaFieldIndex("filters", i).ViaField("spec"))
+	}
+	errs = errs.Also(validateBatchPolicy(t.GetAnnotations()))
+	errs = errs.Also(validateRateLimit(t.GetAnnotations()))
+	errs = errs.Also(validateCircuitBreaker(t.GetAnnotations()))
+	errs = errs.Also(validateReplay(t.GetAnnotations()))
+	errs = errs.Also(validateIsolation(t.GetAnnotations()))
+	errs = duck.ValidatePausedAnnotation(t.GetAnnotations(), errs)
+	errs = duck.ValidateBacklogAnnotations(t.GetAnnotations(), errs)
+	errs = errs.Also(validateDeliveryAudience(t.GetAnnotations()))
+	return errs
+}
+
+func validateBatchPolicy(annotations map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	if _, ok := parseBatchMaxSize(annotations); !ok {
+		errs = errs.Also(apis.ErrInvalidValue(annotations[BatchMaxSizeAnnotationKey], fmt.Sprintf("metadata.annotations[%s]", BatchMaxSizeAnnotationKey)))
+	}
+	if _, ok := parseBatchMaxLinger(annotations); !ok {
+		err := apis.ErrInvalidValue(annotations[BatchMaxLingerAnnotationKey], fmt.Sprintf("metadata.annotations[%s]", BatchMaxLingerAnnotationKey))
+		err.Details = fmt.Sprintf("must be a positive duration shorter than the delivery timeout of %v", MinDeliveryTimeout)
+		errs = errs.Also(err)
+	}
+	return errs
+}
+
+func validateRateLimit(annotations map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	for _, key := range []string{MaxConcurrencyAnnotationKey, MaxEventsPerSecondAnnotationKey} {
+		if _, ok := parsePositiveInt(annotations, key); !ok {
+			errs = errs.Also(apis.ErrInvalidValue(annotations[key], fmt.Sprintf("metadata.annotations[%s]", key)))
+		}
+	}
+	return errs
+}
+
+func validateCircuitBreaker(annotations map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	if _, ok := parsePositiveInt(annotations, CircuitBreakerFailureThresholdAnnotationKey); !ok {
+		errs = errs.Also(apis.ErrInvalidValue(annotations[CircuitBreakerFailureThresholdAnnotationKey], fmt.Sprintf("metadata.annotations[%s]", CircuitBreakerFailureThresholdAnnotationKey)))
+	}
+	if _, ok := parseCircuitBreakerOpenDuration(annotations); !ok {
+		errs = errs.Also(apis.ErrInvalidValue(annotations[CircuitBreakerOpenDurationAnnotationKey], fmt.Sprintf("metadata.annotations[%s]", CircuitBreakerOpenDurationAnnotationKey)))
+	}
+	return errs
+}
+
+func validateReplay(annotations map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	for _, key := range []string{SnapshotAnnotationKey, ReplaySnapshotAnnotationKey} {
+		if _, ok := parseSnapshotID(annotations, key); !ok {
+			errs = errs.Also(apis.ErrInvalidValue(annotations[key], fmt.Sprintf("metadata.annotations[%s]", key)))
+		}
+	}
+	if _, ok := parseReplayTime(annotations); !ok {
+		errs = errs.Also(apis.ErrInvalidValue(annotations[ReplayTimeAnnotationKey], fmt.Sprintf("metadata.annotations[%s]", ReplayTimeAnnotationKey)))
+	}
+	if v, ok := annotations[ReplayableAnnotationKey]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", ReplayableAnnotationKey)))
+		}
+	}
+	_, hasReplayTime := annotations[ReplayTimeAnnotationKey]
+	_, hasReplaySnapshot := annotations[ReplaySnapshotAnnotationKey]
+	if hasReplayTime && hasReplaySnapshot {
+		errs = errs.Also(apis.ErrMultipleOneOf(
+			fmt.Sprintf("metadata.annotations[%s]", ReplayTimeAnnotationKey),
+			fmt.Sprintf("metadata.annotations[%s]", ReplaySnapshotAnnotationKey)))
+	}
+	// Only the dedicated subscription of an isolated Trigger is replayed.
+	if _, isolated := annotations[IsolationAnnotationKey]; !isolated {
+		for _, key := range []string{ReplayableAnnotationKey, SnapshotAnnotationKey, ReplayTimeAnnotationKey, ReplaySnapshotAnnotationKey} {
+			if _, ok := annotations[key]; ok {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("requires the %s annotation", IsolationAnnotationKey), fmt.Sprintf("metadata.annotations[%s]", key)))
+			}
+		}
+	}
+	return errs
+}
+
+func validateDeliveryAnnotation(ctx context.Context, specDelivery *eventingduckv1beta1.DeliverySpec, annotations map[string]string) *apis.FieldError {
+	path := fmt.Sprintf("metadata.annotations[%s]", DeliveryAnnotationKey)
+	delivery, err := parseDeliverySpec(annotations)
+	if err != nil {
+		fe := apis.ErrInvalidValue(annotations[DeliveryAnnotationKey], path)
+		fe.Details = err.Error()
+		return fe
+	}
+	if delivery == nil {
+		return nil
+	}
+	if specDelivery != nil {
+		return apis.ErrMultipleOneOf("spec.delivery", path)
+	}
+	return ValidateTriggerDeliverySpec(ctx, delivery).ViaField(path)
+}
+
+func validateIsolation(annotations map[string]string) *apis.FieldError {
+	if isolation, ok := annotations[IsolationAnnotationKey]; ok && isolation != IsolationSubscription && isolation != IsolationFilteredSubscription {
+		return apis.ErrInvalidValue(isolation, fmt.Sprintf("metadata.annotations[%s]", IsolationAnnotationKey))
+	}
 	return nil
 }
+
+// ValidateTriggerDeliverySpec validates the delivery spec of a Trigger. Unlike
+// the Broker's, any field may be left unset to inherit the Broker's value.
+func ValidateTriggerDeliverySpec(ctx context.Context, spec *eventingduckv1beta1.DeliverySpec) *apis.FieldError {
+	// The dead letter sink is validated separately as it may be a Pub/Sub topic.
+	withoutSink := spec.DeepCopy()
+	withoutSink.DeadLetterSink = nil
+	errs := withoutSink.Validate(ctx)
+	return errs.Also(ValidateDeadLetterSink(ctx, spec.DeadLetterSink).ViaField("deadLetterSink"))
+}
+
+// ValidateSubscriptionsAPIFilter validates a CloudEvents Subscriptions API
+// filter expression and all its nested expressions.
+func ValidateSubscriptionsAPIFilter(f *SubscriptionsAPIFilter) *apis.FieldError {
+	var dialects []string
+	if f.All != nil {
+		dialects = append(dialects, "all")
+	}
+	if f.Any != nil {
+		dialects = append(dialects, "any")
+	}
+	if f.Not != nil {
+		dialects = append(dialects, "not")
+	}
+	if f.Exact != nil {
+		dialects = append(dialects, "exact")
+	}
+	if f.Prefix != nil {
+		dialects = append(dialects, "prefix")
+	}
+	if f.Suffix != nil {
+		dialects = append(dialects, "suffix")
+	}
+	if f.SQL != "" {
+		dialects = append(dialects, "sql")
+	}
+	switch len(dialects) {
+	case 0:
+		return apis.ErrMissingOneOf("all", "any", "not", "exact", "prefix", "suffix", "sql")
+	case 1:
+	default:
+		return apis.ErrMultipleOneOf(dialects...)
+	}
+
+	var errs *apis.FieldError
+	switch {
+	case f.All != nil:
+		errs = validateNestedFilters(f.All, "all")
+	case f.Any != nil:
+		errs = validateNestedFilters(f.Any, "any")
+	case f.Not != nil:
+		errs = ValidateSubscriptionsAPIFilter(f.Not).ViaField("not")
+	case f.Exact != nil:
+		errs = validateAttributeFilter(f.Exact, true).ViaField("exact")
+	case f.Prefix != nil:
+		errs = validateAttributeFilter(f.Prefix, false).ViaField("prefix")
+	case f.Suffix != nil:
+		errs = validateAttributeFilter(f.Suffix, false).ViaField("suffix")
+	case f.SQL != "":
+		if _, err := cesql.Parse(f.SQL); err != nil {
+			errs = apis.ErrInvalidValue(fmt.Sprintf("%s: %v", f.SQL, err), "sql")
+		}
+	}
+	return errs
+}
+
+func validateNestedFilters(filters []SubscriptionsAPIFilter, field string) *apis.FieldError {
+	if len(filters) == 0 {
+		return apis.ErrMissingField(field)
+	}
+	var errs *apis.FieldError
+	for i, f := range filters {
+		errs = errs.Also(ValidateSubscriptionsAPIFilter(&f).ViaFieldIndex(field, i))
+	}
+	return errs
+}
+
+func validateAttributeFilter(attrs map[string]string, allowEmptyValue bool) *apis.FieldError {
+	if len(attrs) != 1 {
+		return &apis.FieldError{
+			Message: "expected exactly one attribute",
+			Paths:   []string{apis.CurrentField},
+		}
+	}
+	var errs *apis.FieldError
+	for k, v := range attrs {
+		if !validAttributeName.MatchString(k) {
+			errs = errs.Also(apis.ErrInvalidKeyName(k, apis.CurrentField, "attribute names must consist of lowercase letters and digits"))
+		}
+		if v == "" && !allowEmptyValue {
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		}
+	}
+	return errs
+}
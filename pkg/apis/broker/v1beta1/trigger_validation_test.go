@@ -19,11 +19,388 @@ package v1beta1
 import (
 	"context"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 func TestTrigger_Validate(t *testing.T) {
-	trig := Trigger{}
-	if err := trig.Validate(context.TODO()); err != nil {
-		t.Errorf("expected nil, got %v", err)
+	invalidBackoffDelay := "invalid"
+	validBackoffDelay := "PT2S"
+	invalidRetry := int32(-1)
+	tests := []struct {
+		name string
+		trig Trigger
+		want *apis.FieldError
+	}{{
+		name: "no delivery",
+		trig: Trigger{},
+	}, {
+		name: "valid partial delivery",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay: &validBackoffDelay,
+				},
+			},
+		},
+	}, {
+		name: "valid pubsub dead letter sink",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{
+						URI: &apis.URL{
+							Scheme: "pubsub",
+							Host:   "test-topic-id",
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "invalid backoff delay and retry",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay: &invalidBackoffDelay,
+					Retry:        &invalidRetry,
+				},
+			},
+		},
+		want: apis.ErrInvalidValue(invalidRetry, "spec.delivery.retry").Also(
+			apis.ErrInvalidValue(invalidBackoffDelay, "spec.delivery.backoffDelay")),
+	}, {
+		name: "invalid empty pubsub dead letter topic",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{
+						URI: &apis.URL{
+							Scheme: "pubsub",
+						},
+					},
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("Dead letter topic must not be empty", "spec.delivery.deadLetterSink.uri"),
+	}, {
+		name: "valid delivery annotation",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryAnnotationKey: `{"retry": 3, "deadLetterSink": {"uri": "pubsub://test-topic-id"}}`,
+				},
+			},
+		},
+	}, {
+		name: "invalid delivery annotation",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryAnnotationKey: `{"retry": -1, "backoffDelay": "invalid"}`,
+				},
+			},
+		},
+		want: apis.ErrInvalidValue(invalidRetry, "metadata.annotations[events.cloud.google.com/delivery].retry").Also(
+			apis.ErrInvalidValue(invalidBackoffDelay, "metadata.annotations[events.cloud.google.com/delivery].backoffDelay")),
+	}, {
+		name: "unknown delivery annotation field",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryAnnotationKey: `{"retries": 3}`,
+				},
+			},
+		},
+		want: invalidDeliveryAnnotation(`{"retries": 3}`, `json: unknown field "retries"`),
+	}, {
+		name: "delivery annotation and spec",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryAnnotationKey: `{"retry": 3}`,
+				},
+			},
+			Spec: TriggerSpec{
+				Delivery: &eventingduckv1beta1.DeliverySpec{
+					BackoffDelay: &validBackoffDelay,
+				},
+			},
+		},
+		want: apis.ErrMultipleOneOf("spec.delivery", "metadata.annotations[events.cloud.google.com/delivery]"),
+	}, {
+		name: "valid filters",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Filters: []SubscriptionsAPIFilter{{
+					Exact: map[string]string{"type": "foo"},
+				}, {
+					Any: []SubscriptionsAPIFilter{{
+						Prefix: map[string]string{"source": "bar"},
+					}, {
+						Not: &SubscriptionsAPIFilter{Suffix: map[string]string{"subject": ".png"}},
+					}},
+				}, {
+					SQL: "myext = 'baz' AND source LIKE 'bar%'",
+				}},
+			},
+		},
+	}, {
+		name: "empty filter",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Filters: []SubscriptionsAPIFilter{{}},
+			},
+		},
+		want: apis.ErrMissingOneOf("all", "any", "not", "exact", "prefix", "suffix", "sql").ViaFieldIndex("filters", 0).ViaField("spec"),
+	}, {
+		name: "multiple dialects",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Filters: []SubscriptionsAPIFilter{{
+					Exact: map[string]string{"type": "foo"},
+					SQL:   "type = 'foo'",
+				}},
+			},
+		},
+		want: apis.ErrMultipleOneOf("exact", "sql").ViaFieldIndex("filters", 0).ViaField("spec"),
+	}, {
+		name: "multiple attributes",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Filters: []SubscriptionsAPIFilter{{
+					Exact: map[string]string{"type": "foo", "source": "bar"},
+				}},
+			},
+		},
+		want: &apis.FieldError{
+			Message: "expected exactly one attribute",
+			Paths:   []string{"spec.filters[0].exact"},
+		},
+	}, {
+		name: "invalid attribute name and empty prefix",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Filters: []SubscriptionsAPIFilter{{
+					All: []SubscriptionsAPIFilter{{
+						Prefix: map[string]string{"Type": ""},
+					}},
+				}},
+			},
+		},
+		want: apis.ErrInvalidKeyName("Type", "spec.filters[0].all[0].prefix", "attribute names must consist of lowercase letters and digits").Also(
+			apis.ErrInvalidValue("", "spec.filters[0].all[0].prefix.Type")),
+	}, {
+		name: "empty any",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Filters: []SubscriptionsAPIFilter{{
+					Any: []SubscriptionsAPIFilter{},
+				}},
+			},
+		},
+		want: apis.ErrMissingField("spec.filters[0].any"),
+	}, {
+		name: "invalid sql",
+		trig: Trigger{
+			Spec: TriggerSpec{
+				Filters: []SubscriptionsAPIFilter{{
+					Not: &SubscriptionsAPIFilter{SQL: "type ="},
+				}},
+			},
+		},
+		want: apis.ErrInvalidValue("type =: unexpected end of expression", "spec.filters[0].not.sql"),
+	}, {
+		name: "valid batch policy",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					BatchMaxSizeAnnotationKey:   "100",
+					BatchMaxLingerAnnotationKey: "500ms",
+				},
+			},
+		},
+	}, {
+		name: "invalid batch policy",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					BatchMaxSizeAnnotationKey:   "0",
+					BatchMaxLingerAnnotationKey: "1m",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("0", "metadata.annotations[events.cloud.google.com/batchMaxSize]").Also(
+			invalidBatchMaxLinger("1m")),
+	}, {
+		name: "batch linger not shorter than the delivery timeout",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					BatchMaxSizeAnnotationKey:   "100",
+					BatchMaxLingerAnnotationKey: "10s",
+				},
+			},
+		},
+		want: invalidBatchMaxLinger("10s"),
+	}, {
+		name: "valid rate limit",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					MaxConcurrencyAnnotationKey:     "5",
+					MaxEventsPerSecondAnnotationKey: "100",
+				},
+			},
+		},
+	}, {
+		name: "invalid rate limit",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					MaxConcurrencyAnnotationKey:     "-1",
+					MaxEventsPerSecondAnnotationKey: "fast",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("-1", "metadata.annotations[events.cloud.google.com/maxConcurrency]").Also(
+			apis.ErrInvalidValue("fast", "metadata.annotations[events.cloud.google.com/maxEventsPerSecond]")),
+	}, {
+		name: "valid circuit breaker",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					CircuitBreakerFailureThresholdAnnotationKey: "5",
+					CircuitBreakerOpenDurationAnnotationKey:     "1m",
+				},
+			},
+		},
+	}, {
+		name: "invalid circuit breaker",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					CircuitBreakerFailureThresholdAnnotationKey: "many",
+					CircuitBreakerOpenDurationAnnotationKey:     "-1s",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("many", "metadata.annotations[events.cloud.google.com/circuitBreakerFailureThreshold]").Also(
+			apis.ErrInvalidValue("-1s", "metadata.annotations[events.cloud.google.com/circuitBreakerOpenDuration]")),
+	}, {
+		name: "valid replay",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					IsolationAnnotationKey:  IsolationSubscription,
+					ReplayableAnnotationKey: "true",
+					SnapshotAnnotationKey:   "before-deploy",
+					ReplayTimeAnnotationKey: "2021-01-02T15:04:05Z",
+				},
+			},
+		},
+	}, {
+		name: "invalid replay",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					IsolationAnnotationKey:      IsolationSubscription,
+					ReplayableAnnotationKey:     "yes",
+					SnapshotAnnotationKey:       "goog-snapshot",
+					ReplaySnapshotAnnotationKey: "before-deploy",
+					ReplayTimeAnnotationKey:     "yesterday",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("goog-snapshot", "metadata.annotations[events.cloud.google.com/snapshot]").Also(
+			apis.ErrInvalidValue("yesterday", "metadata.annotations[events.cloud.google.com/replayTime]"),
+			apis.ErrInvalidValue("yes", "metadata.annotations[events.cloud.google.com/replayable]"),
+			apis.ErrMultipleOneOf("metadata.annotations[events.cloud.google.com/replayTime]", "metadata.annotations[events.cloud.google.com/replaySnapshot]")),
+	}, {
+		name: "replay without isolation",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					ReplayableAnnotationKey: "true",
+					ReplayTimeAnnotationKey: "2021-01-02T15:04:05Z",
+				},
+			},
+		},
+		want: apis.ErrGeneric("requires the events.cloud.google.com/isolation annotation", "metadata.annotations[events.cloud.google.com/replayable]").Also(
+			apis.ErrGeneric("requires the events.cloud.google.com/isolation annotation", "metadata.annotations[events.cloud.google.com/replayTime]")),
+	}, {
+		name: "valid isolation",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					IsolationAnnotationKey: IsolationFilteredSubscription,
+				},
+			},
+		},
+	}, {
+		name: "invalid isolation",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					IsolationAnnotationKey: "true",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("true", "metadata.annotations[events.cloud.google.com/isolation]"),
+	}, {
+		name: "invalid paused",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					PausedAnnotationKey: "yes",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("yes", "metadata.annotations[events.cloud.google.com/paused]"),
+	}, {
+		name: "invalid backlog threshold",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"events.cloud.google.com/backlogThreshold": "many",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("many", "metadata.annotations[events.cloud.google.com/backlogThreshold]"),
+	}, {
+		name: "invalid delivery audience",
+		trig: Trigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					DeliveryAudienceAnnotationKey: "two audiences",
+				},
+			},
+		},
+		want: apis.ErrInvalidValue("two audiences", "metadata.annotations[events.cloud.google.com/deliveryAudience]"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.trig.Validate(context.Background())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Trigger.Validate (-want, +got) = %v", diff)
+			}
+		})
 	}
 }
+
+func invalidDeliveryAnnotation(value, details string) *apis.FieldError {
+	err := apis.ErrInvalidValue(value, "metadata.annotations[events.cloud.google.com/delivery]")
+	err.Details = details
+	return err
+}
+
+func invalidBatchMaxLinger(value string) *apis.FieldError {
+	err := apis.ErrInvalidValue(value, "metadata.annotations[events.cloud.google.com/batchMaxLinger]")
+	err.Details = "must be a positive duration shorter than the delivery timeout of 10s"
+	return err
+}
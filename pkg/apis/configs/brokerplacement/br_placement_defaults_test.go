@@ -0,0 +1,145 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokerplacement
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	. "knative.dev/pkg/configmap/testing"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func TestDefaultsConfigurationFromFile(t *testing.T) {
+	actual, example := ConfigMapsFromTestFile(t, configName, defaulterKey)
+	for _, cm := range []string{"actual", "example"} {
+		t.Run(cm, func(t *testing.T) {
+			c := actual
+			if cm == "example" {
+				c = example
+			}
+			if _, err := NewDefaultsConfigFromConfigMap(c); err != nil {
+				t.Errorf("NewDefaultsConfigFromConfigMap(%s) = %v", cm, err)
+			}
+		})
+	}
+}
+
+func TestNewDefaultsConfigFromConfigMap(t *testing.T) {
+	_, example := ConfigMapsFromTestFile(t, configName, defaulterKey)
+	defaults, err := NewDefaultsConfigFromConfigMap(example)
+	if err != nil {
+		t.Fatalf("NewDefaultsConfigFromConfigMap(example) = %v", err)
+	}
+	want := &Defaults{
+		NamespaceDefaults: map[string]ScopedDefaults{
+			"team-a-prod":    {Strategy: FixedStrategy, BrokerCell: "team-a"},
+			"team-a-staging": {Strategy: FixedStrategy, BrokerCell: "team-a"},
+		},
+		ClusterDefaults: ScopedDefaults{Strategy: HashStrategy, BrokerCell: "default", BrokerCells: 3},
+	}
+	if diff := cmp.Diff(want, defaults); diff != "" {
+		t.Errorf("Unexpected defaults (-want, +got): %s", diff)
+	}
+}
+
+func TestNewDefaultsConfigFromMapErrors(t *testing.T) {
+	testCases := map[string]map[string]string{
+		"missing key": {},
+		"empty key":   {defaulterKey: ""},
+		"invalid yaml": {defaulterKey: `
+clusterDefaults: [`},
+		"unknown strategy": {defaulterKey: `
+clusterDefaults:
+  strategy: random`},
+		"hash without brokerCells": {defaulterKey: `
+clusterDefaults:
+  strategy: hash`},
+		"invalid namespace strategy": {defaulterKey: `
+namespaceDefaults:
+  ns:
+    strategy: hash
+    brokerCells: 0`},
+	}
+	for n, data := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if _, err := NewDefaultsConfigFromMap(data); err == nil {
+				t.Error("NewDefaultsConfigFromMap() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestBrokerCell(t *testing.T) {
+	defaults := &Defaults{
+		NamespaceDefaults: map[string]ScopedDefaults{
+			"team-a":  {Strategy: FixedStrategy, BrokerCell: "team-a-cell"},
+			"tenant":  {Strategy: NamespaceStrategy},
+			"unnamed": {},
+		},
+		ClusterDefaults: ScopedDefaults{Strategy: HashStrategy, BrokerCell: "shared", BrokerCells: 4},
+	}
+	testCases := []struct {
+		name      string
+		defaults  *Defaults
+		namespace string
+		broker    string
+		want      string
+	}{{
+		name:      "no policy",
+		namespace: "ns",
+		broker:    "broker",
+		want:      "default",
+	}, {
+		name:      "fixed",
+		defaults:  defaults,
+		namespace: "team-a",
+		broker:    "broker",
+		want:      "team-a-cell",
+	}, {
+		name:      "fixed without brokercell",
+		defaults:  defaults,
+		namespace: "unnamed",
+		broker:    "broker",
+		want:      "default",
+	}, {
+		name:      "namespace",
+		defaults:  defaults,
+		namespace: "tenant",
+		broker:    "broker",
+		want:      "tenant",
+	}, {
+		name:      "hash",
+		defaults:  defaults,
+		namespace: "ns",
+		broker:    "broker",
+		want:      "shared-2",
+	}, {
+		name:      "hash of another broker",
+		defaults:  defaults,
+		namespace: "ns",
+		broker:    "other",
+		want:      "shared-3",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.defaults.BrokerCell(tc.namespace, tc.broker); got != tc.want {
+				t.Errorf("BrokerCell(%q, %q) = %q, want %q", tc.namespace, tc.broker, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokerplacement
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// defaultBrokerCell is the name of the BrokerCell of the Brokers which are not
+// placed by a policy.
+const defaultBrokerCell = "default"
+
+// Strategy is a strategy to place Brokers on BrokerCells.
+type Strategy string
+
+const (
+	// FixedStrategy places the Brokers on a single BrokerCell.
+	FixedStrategy Strategy = "fixed"
+	// NamespaceStrategy places the Brokers of each namespace on a dedicated
+	// BrokerCell named after the namespace.
+	NamespaceStrategy Strategy = "namespace"
+	// HashStrategy spreads the Brokers across a number of BrokerCells by the
+	// hash of their namespace and name.
+	HashStrategy Strategy = "hash"
+)
+
+// Defaults includes the Broker placement policies applied by the Broker
+// controller.
+type Defaults struct {
+	// NamespaceDefaults are the placement policies to use in specific
+	// namespaces. The namespace is the key, the value is the policy.
+	NamespaceDefaults map[string]ScopedDefaults `json:"namespaceDefaults,omitempty"`
+	// ClusterDefaults is the placement policy to use for all namespaces that
+	// are not in NamespaceDefaults.
+	ClusterDefaults ScopedDefaults `json:"clusterDefaults,omitempty"`
+}
+
+// ScopedDefaults is a Broker placement policy.
+type ScopedDefaults struct {
+	// Strategy is the placement strategy. Defaults to fixed.
+	Strategy Strategy `json:"strategy,omitempty"`
+	// BrokerCell is the name of the BrokerCell of the fixed strategy, and the
+	// prefix of the names of the BrokerCells of the hash strategy. Defaults to
+	// "default".
+	BrokerCell string `json:"brokerCell,omitempty"`
+	// BrokerCells is the number of BrokerCells of the hash strategy. The
+	// BrokerCells are named <brokerCell>-0 to <brokerCell>-<brokerCells - 1>.
+	BrokerCells int `json:"brokerCells,omitempty"`
+}
+
+// scoped gets the placement policy for the given namespace.
+func (d *Defaults) scoped(ns string) *ScopedDefaults {
+	scopedDefaults := &d.ClusterDefaults
+	if sd, present := d.NamespaceDefaults[ns]; present {
+		scopedDefaults = &sd
+	}
+	return scopedDefaults
+}
+
+// BrokerCell returns the name of the BrokerCell on which the placement policy
+// places the given Broker.
+func (d *Defaults) BrokerCell(namespace, name string) string {
+	if d == nil {
+		return defaultBrokerCell
+	}
+	sd := d.scoped(namespace)
+	switch sd.Strategy {
+	case NamespaceStrategy:
+		return namespace
+	case HashStrategy:
+		h := fnv.New32a()
+		h.Write([]byte(namespace + "/" + name))
+		return fmt.Sprintf("%s-%d", sd.brokerCell(), h.Sum32()%uint32(sd.BrokerCells))
+	default:
+		return sd.brokerCell()
+	}
+}
+
+func (sd *ScopedDefaults) brokerCell() string {
+	if sd.BrokerCell == "" {
+		return defaultBrokerCell
+	}
+	return sd.BrokerCell
+}
+
+func (sd *ScopedDefaults) validate() error {
+	switch sd.Strategy {
+	case "", FixedStrategy, NamespaceStrategy:
+	case HashStrategy:
+		if sd.BrokerCells < 1 {
+			return fmt.Errorf("brokerCells must be at least 1 for the %s strategy, got %d", HashStrategy, sd.BrokerCells)
+		}
+	default:
+		return fmt.Errorf("unknown strategy %q", sd.Strategy)
+	}
+	return nil
+}
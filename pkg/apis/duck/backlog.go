@@ -0,0 +1,101 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package duck
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"knative.dev/pkg/apis"
+)
+
+// BacklogThresholds are the thresholds above which the backlog of a resource
+// is unhealthy. A zero threshold is not checked.
+type BacklogThresholds struct {
+	UndeliveredMessages     int64
+	OldestUnackedMessageAge time.Duration
+}
+
+// Exceeded returns true if the backlog exceeds one of the thresholds.
+func (t BacklogThresholds) Exceeded(undeliveredMessages int64, oldestUnackedMessageAge time.Duration) bool {
+	return (t.UndeliveredMessages > 0 && undeliveredMessages > t.UndeliveredMessages) ||
+		(t.OldestUnackedMessageAge > 0 && oldestUnackedMessageAge > t.OldestUnackedMessageAge)
+}
+
+// BacklogReporting returns whether the annotations enable the backlog
+// reporting of the resource, and the thresholds of its backlog.
+func BacklogReporting(annotations map[string]string) (bool, BacklogThresholds) {
+	var thresholds BacklogThresholds
+	enabled, _ := strconv.ParseBool(annotations[BacklogReportingAnnotation])
+	if v, ok := annotations[BacklogThresholdAnnotation]; ok {
+		thresholds.UndeliveredMessages, _ = strconv.ParseInt(v, 10, 64)
+		enabled = true
+	}
+	if v, ok := annotations[BacklogAgeThresholdAnnotation]; ok {
+		thresholds.OldestUnackedMessageAge, _ = time.ParseDuration(v)
+		enabled = true
+	}
+	return enabled, thresholds
+}
+
+// ValidateBacklogAnnotations validates the backlog reporting annotations.
+func ValidateBacklogAnnotations(annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
+	if v, ok := annotations[BacklogReportingAnnotation]; ok {
+		if _, err := strconv.ParseBool(v); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", BacklogReportingAnnotation)))
+		}
+	}
+	if v, ok := annotations[BacklogThresholdAnnotation]; ok {
+		if threshold, err := strconv.ParseInt(v, 10, 64); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", BacklogThresholdAnnotation)))
+		} else if threshold < 1 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(threshold, 1, math.MaxInt64, fmt.Sprintf("metadata.annotations[%s]", BacklogThresholdAnnotation)))
+		}
+	}
+	if v, ok := annotations[BacklogAgeThresholdAnnotation]; ok {
+		if threshold, err := time.ParseDuration(v); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", BacklogAgeThresholdAnnotation)))
+		} else if threshold <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", BacklogAgeThresholdAnnotation)))
+		}
+	}
+	return errs
+}
+
+// SetBacklogStatusAnnotations sets the backlog status annotations in the
+// status annotations, and returns them.
+func SetBacklogStatusAnnotations(annotations map[string]string, undeliveredMessages int64, oldestUnackedMessageAge time.Duration) map[string]string {
+	if annotations == nil {
+		annotations = make(map[string]string, 2)
+	}
+	annotations[UndeliveredMessagesStatusAnnotation] = strconv.FormatInt(undeliveredMessages, 10)
+	annotations[OldestUnackedMessageAgeStatusAnnotation] = oldestUnackedMessageAge.String()
+	return annotations
+}
+
+// ClearBacklogStatusAnnotations removes the backlog status annotations from
+// the status annotations, and returns them.
+func ClearBacklogStatusAnnotations(annotations map[string]string) map[string]string {
+	delete(annotations, UndeliveredMessagesStatusAnnotation)
+	delete(annotations, OldestUnackedMessageAgeStatusAnnotation)
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
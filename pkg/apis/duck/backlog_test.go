@@ -0,0 +1,155 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package duck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+)
+
+func TestBacklogAnnotations(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		enabled     bool
+		thresholds  BacklogThresholds
+		error       bool
+	}{
+		"not enabled": {},
+		"enabled": {
+			annotations: map[string]string{BacklogReportingAnnotation: "true"},
+			enabled:     true,
+		},
+		"disabled": {
+			annotations: map[string]string{BacklogReportingAnnotation: "false"},
+		},
+		"thresholds": {
+			annotations: map[string]string{
+				BacklogThresholdAnnotation:    "1000",
+				BacklogAgeThresholdAnnotation: "10m",
+			},
+			enabled: true,
+			thresholds: BacklogThresholds{
+				UndeliveredMessages:     1000,
+				OldestUnackedMessageAge: 10 * time.Minute,
+			},
+		},
+		"invalid reporting": {
+			annotations: map[string]string{BacklogReportingAnnotation: "yes"},
+			error:       true,
+		},
+		"invalid threshold": {
+			annotations: map[string]string{BacklogThresholdAnnotation: "many"},
+			enabled:     true,
+			error:       true,
+		},
+		"threshold out of bounds": {
+			annotations: map[string]string{BacklogThresholdAnnotation: "0"},
+			enabled:     true,
+			error:       true,
+		},
+		"invalid age threshold": {
+			annotations: map[string]string{BacklogAgeThresholdAnnotation: "10"},
+			enabled:     true,
+			error:       true,
+		},
+		"negative age threshold": {
+			annotations: map[string]string{BacklogAgeThresholdAnnotation: "-10m"},
+			enabled:     true,
+			thresholds: BacklogThresholds{
+				OldestUnackedMessageAge: -10 * time.Minute,
+			},
+			error: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var errs *apis.FieldError
+			err := ValidateBacklogAnnotations(tc.annotations, errs)
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+			enabled, thresholds := BacklogReporting(tc.annotations)
+			if enabled != tc.enabled {
+				t.Errorf("BacklogReporting() enabled=%v, want=%v", enabled, tc.enabled)
+			}
+			if diff := cmp.Diff(tc.thresholds, thresholds); diff != "" {
+				t.Errorf("BacklogReporting() thresholds (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestBacklogThresholdsExceeded(t *testing.T) {
+	thresholds := BacklogThresholds{
+		UndeliveredMessages:     100,
+		OldestUnackedMessageAge: time.Minute,
+	}
+	testCases := map[string]struct {
+		thresholds  BacklogThresholds
+		undelivered int64
+		age         time.Duration
+		want        bool
+	}{
+		"no thresholds": {
+			undelivered: 1000,
+			age:         time.Hour,
+		},
+		"below thresholds": {
+			thresholds:  thresholds,
+			undelivered: 100,
+			age:         time.Minute,
+		},
+		"messages exceeded": {
+			thresholds:  thresholds,
+			undelivered: 101,
+			want:        true,
+		},
+		"age exceeded": {
+			thresholds: thresholds,
+			age:        2 * time.Minute,
+			want:       true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.thresholds.Exceeded(tc.undelivered, tc.age); got != tc.want {
+				t.Errorf("Exceeded()=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBacklogStatusAnnotations(t *testing.T) {
+	annotations := SetBacklogStatusAnnotations(nil, 42, 90*time.Second)
+	want := map[string]string{
+		UndeliveredMessagesStatusAnnotation:     "42",
+		OldestUnackedMessageAgeStatusAnnotation: "1m30s",
+	}
+	if diff := cmp.Diff(want, annotations); diff != "" {
+		t.Errorf("SetBacklogStatusAnnotations() (-want, +got) = %v", diff)
+	}
+	annotations["other"] = "value"
+	if diff := cmp.Diff(map[string]string{"other": "value"}, ClearBacklogStatusAnnotations(annotations)); diff != "" {
+		t.Errorf("ClearBacklogStatusAnnotations() (-want, +got) = %v", diff)
+	}
+	if got := ClearBacklogStatusAnnotations(SetBacklogStatusAnnotations(nil, 1, time.Second)); got != nil {
+		t.Errorf("ClearBacklogStatusAnnotations()=%v, want=nil", got)
+	}
+}
@@ -47,6 +47,28 @@ const (
 	// Pub/Sub subscription that Keda uses in order to decide when and by how much to scale out.
 	KedaAutoscalingSubscriptionSizeAnnotation = KEDA + "/subscriptionSize"
 
+	// PausedAnnotation is the annotation to pause the delivery of the events of a resource. Its value is "true" or
+	// "false". The events published while the resource is paused accumulate in its Pub/Sub subscription until it is
+	// resumed.
+	PausedAnnotation = "events.cloud.google.com/paused"
+
+	// BacklogReportingAnnotation is the annotation to periodically report the backlog of the Pub/Sub subscriptions of
+	// a resource in its status. Its value is "true" or "false".
+	BacklogReportingAnnotation = "events.cloud.google.com/backlogReporting"
+	// BacklogThresholdAnnotation is the annotation for the number of undelivered messages above which the backlog of
+	// a resource is unhealthy. Setting it enables the backlog reporting.
+	BacklogThresholdAnnotation = "events.cloud.google.com/backlogThreshold"
+	// BacklogAgeThresholdAnnotation is the annotation for the age of the oldest unacknowledged message, e.g. "10m",
+	// above which the backlog of a resource is unhealthy. Setting it enables the backlog reporting.
+	BacklogAgeThresholdAnnotation = "events.cloud.google.com/backlogAgeThreshold"
+
+	// UndeliveredMessagesStatusAnnotation is the status annotation reporting the number of undelivered messages in the
+	// Pub/Sub subscriptions of the resources which status has no backlog field, i.e. Brokers and Triggers.
+	UndeliveredMessagesStatusAnnotation = "events.cloud.google.com/undeliveredMessages"
+	// OldestUnackedMessageAgeStatusAnnotation is the status annotation reporting the age of the oldest unacknowledged
+	// message in the Pub/Sub subscriptions of the resources which status has no backlog field.
+	OldestUnackedMessageAgeStatusAnnotation = "events.cloud.google.com/oldestUnackedMessageAge"
+
 	// defaultMinScale is the default minimum set of Pods the scaler should
 	// downscale the resource to.
 	defaultMinScale = "0"
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	"knative.dev/pkg/apis"
 )
 
@@ -75,3 +77,58 @@ func (s *PubSubStatus) MarkPullSubscriptionReady(cs *apis.ConditionSet) {
 func (s *PubSubStatus) MarkPullSubscriptionNotConfigured(cs *apis.ConditionSet) {
 	cs.Manage(s).MarkUnknown(PullSubscriptionReady, "PullSubscriptionNotConfigured", "PullSubscription has not yet been reconciled")
 }
+
+// MarkPaused sets the condition that the delivery of the events is paused.
+func (s *PubSubStatus) MarkPaused(cs *apis.ConditionSet) {
+	cs.Manage(s).MarkTrueWithReason(Paused, "Paused", "The delivery of the events is paused, the events accumulate in the Pub/Sub subscription")
+}
+
+// ClearPausedCondition removes the paused condition once the delivery of the
+// events is resumed.
+func (s *PubSubStatus) ClearPausedCondition(cs *apis.ConditionSet) {
+	cs.Manage(s).ClearCondition(Paused)
+}
+
+// SetBacklog sets the backlog of the subscription.
+func (s *PubSubStatus) SetBacklog(undeliveredMessages int64, oldestUnackedMessageAge time.Duration) {
+	s.Backlog = &SubscriptionBacklog{
+		UndeliveredMessages:     undeliveredMessages,
+		OldestUnackedMessageAge: oldestUnackedMessageAge.String(),
+	}
+}
+
+// MarkBacklogHealthy sets the condition that the backlog of the subscription
+// is below its thresholds.
+func (s *PubSubStatus) MarkBacklogHealthy(cs *apis.ConditionSet) {
+	cs.Manage(s).MarkTrue(BacklogHealthy)
+}
+
+// MarkBacklogThresholdExceeded sets the condition that the backlog of the
+// subscription exceeds one of its thresholds.
+func (s *PubSubStatus) MarkBacklogThresholdExceeded(cs *apis.ConditionSet, messageFormat string, messageA ...interface{}) {
+	cs.Manage(s).MarkFalse(BacklogHealthy, "BacklogThresholdExceeded", messageFormat, messageA...)
+}
+
+// MarkBacklogUnknown sets the condition that the backlog of the subscription
+// is unknown.
+func (s *PubSubStatus) MarkBacklogUnknown(cs *apis.ConditionSet, reason, messageFormat string, messageA ...interface{}) {
+	cs.Manage(s).MarkUnknown(BacklogHealthy, reason, messageFormat, messageA...)
+}
+
+// ClearBacklog removes the backlog and its condition once the backlog
+// reporting is disabled.
+func (s *PubSubStatus) ClearBacklog(cs *apis.ConditionSet) {
+	s.Backlog = nil
+	cs.Manage(s).ClearCondition(BacklogHealthy)
+}
+
+// PropagateBacklog sets the backlog and its condition to the ones of the
+// PullSubscription of the resource.
+func (s *PubSubStatus) PropagateBacklog(cs *apis.ConditionSet, backlog *SubscriptionBacklog, cond *apis.Condition) {
+	s.Backlog = backlog.DeepCopy()
+	if cond == nil {
+		cs.Manage(s).ClearCondition(BacklogHealthy)
+		return
+	}
+	cs.Manage(s).SetCondition(*cond)
+}
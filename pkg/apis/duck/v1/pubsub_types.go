@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -63,8 +64,71 @@ type PubSubSpec struct {
 	// If omitted, defaults to same as the cluster.
 	// +optional
 	Project string `json:"project,omitempty"`
+
+	// Mode defines the encoding of the requests sent to the sink. If omitted,
+	// defaults to CloudEventsBinary.
+	// +optional
+	Mode ModeType `json:"mode,omitempty"`
+
+	// Delivery defines the retry and dead letter policy of the Pub/Sub
+	// subscription. The retry is the maximum number of delivery attempts of
+	// a message before it is sent to the dead letter sink.
+	// +optional
+	Delivery *eventingduckv1.DeliverySpec `json:"delivery,omitempty"`
+
+	// Reply is the destination the CloudEvents the sink replies with are sent
+	// to. If omitted, the replies are discarded.
+	// +optional
+	Reply *duckv1.Destination `json:"reply,omitempty"`
+
+	// Mapping computes attributes of the events from their data, after the
+	// conversion of the Pub/Sub messages and before the CloudEventOverrides.
+	// +optional
+	Mapping *EventMapping `json:"mapping,omitempty"`
+}
+
+// EventMapping computes attributes of the events from their data with
+// JSONPath templates, e.g. "{.bucket}" or "gs://{.bucket}/{.name}". The fields
+// missing from the data evaluate to the empty string, and the attributes which
+// evaluate to the empty string are left as converted.
+type EventMapping struct {
+	// Type is the template of the type of the events.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Subject is the template of the subject of the events.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// Extensions are the templates of extension attributes of the events,
+	// keyed by the names of the attributes.
+	// +optional
+	Extensions map[string]string `json:"extensions,omitempty"`
 }
 
+// ModeType is the encoding of the requests sent to the sink.
+type ModeType string
+
+const (
+	// ModeCloudEventsBinary sends the events in the CloudEvents binary HTTP
+	// mode.
+	ModeCloudEventsBinary ModeType = "CloudEventsBinary"
+
+	// ModeCloudEventsStructured sends the events in the CloudEvents
+	// structured HTTP mode.
+	ModeCloudEventsStructured ModeType = "CloudEventsStructured"
+
+	// ModeRawData sends the events in the CloudEvents binary HTTP mode, with
+	// the data of the Pub/Sub messages as is rather than wrapped in an
+	// envelope.
+	ModeRawData ModeType = "RawData"
+
+	// ModePush sends the Pub/Sub messages in the format of the requests of
+	// Cloud Pub/Sub push subscriptions, without CloudEvents headers. It can't
+	// be combined with the CloudEventOverrides or the Mapping.
+	ModePush ModeType = "Push"
+)
+
 // PubSubStatus shows how we expect folks to embed Addressable in
 // their Status field.
 type PubSubStatus struct {
@@ -90,6 +154,23 @@ type PubSubStatus struct {
 	// SubscriptionID is the created subscription ID.
 	// +optional
 	SubscriptionID string `json:"subscriptionId,omitempty"`
+
+	// Backlog is the backlog of the subscription, reported when the backlog
+	// reporting is enabled.
+	// +optional
+	Backlog *SubscriptionBacklog `json:"backlog,omitempty"`
+}
+
+// SubscriptionBacklog is the backlog of a Pub/Sub subscription, as reported by
+// Cloud Monitoring.
+type SubscriptionBacklog struct {
+	// UndeliveredMessages is the number of unacknowledged messages in the
+	// subscription.
+	UndeliveredMessages int64 `json:"undeliveredMessages"`
+
+	// OldestUnackedMessageAge is the age of the oldest unacknowledged message
+	// in the subscription, e.g. "1m30s".
+	OldestUnackedMessageAge string `json:"oldestUnackedMessageAge"`
 }
 
 const (
@@ -98,6 +179,15 @@ const (
 
 	// PullSubscriptionReay has status True when the PullSubscription is ready.
 	PullSubscriptionReady apis.ConditionType = "PullSubscriptionReady"
+
+	// Paused has status True when the delivery of the events of the resource
+	// is paused. It does not affect the readiness of the resource.
+	Paused apis.ConditionType = "Paused"
+
+	// BacklogHealthy has status True when the backlog of the subscription of
+	// the resource is below its thresholds. It is only set when the backlog
+	// reporting is enabled, and does not affect the readiness of the resource.
+	BacklogHealthy apis.ConditionType = "BacklogHealthy"
 )
 
 var (
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rickb777/date/period"
+	"k8s.io/client-go/util/jsonpath"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/duck"
+)
+
+const (
+	// MinDeliveryAttempts and MaxDeliveryAttempts bound the retry of the
+	// delivery spec, as Pub/Sub does the maximum delivery attempts of its
+	// dead letter policies.
+	MinDeliveryAttempts = 5
+	MaxDeliveryAttempts = 100
+
+	// MaxBackoffDelay bounds the backoff delay of the delivery spec, as
+	// Pub/Sub does the backoff of its retry policies.
+	MaxBackoffDelay = 600 * time.Second
+)
+
+// ValidateMode validates the Mode of the PubSubSpec. The requests of mode Push
+// carry no CloudEvent attributes, so it can't be combined with the
+// CloudEventOverrides or the Mapping.
+func (s *PubSubSpec) ValidateMode() *apis.FieldError {
+	switch s.Mode {
+	case "", ModeCloudEventsBinary, ModeCloudEventsStructured, ModeRawData:
+		return nil
+	case ModePush:
+		var errs *apis.FieldError
+		if s.CloudEventOverrides != nil {
+			errs = errs.Also(apis.ErrGeneric("ceOverrides can't be set with mode Push", "ceOverrides"))
+		}
+		if s.Mapping != nil {
+			errs = errs.Also(apis.ErrGeneric("mapping can't be set with mode Push", "mapping"))
+		}
+		return errs
+	default:
+		return apis.ErrInvalidValue(s.Mode, "mode")
+	}
+}
+
+// ValidateDelivery validates the Delivery of the PubSubSpec. The delivery spec
+// must translate to the retry and dead letter policy of a Pub/Sub
+// subscription.
+func (s *PubSubSpec) ValidateDelivery(ctx context.Context) *apis.FieldError {
+	ds := s.Delivery
+	if ds == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if ds.Retry != nil {
+		if ds.DeadLetterSink == nil {
+			errs = errs.Also(apis.ErrGeneric("need DeadLetterSink when retry is defined", "deadLetterSink"))
+		}
+		if *ds.Retry < MinDeliveryAttempts || *ds.Retry > MaxDeliveryAttempts {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*ds.Retry, MinDeliveryAttempts, MaxDeliveryAttempts, "retry"))
+		}
+	}
+	if ds.BackoffPolicy != nil {
+		switch *ds.BackoffPolicy {
+		case eventingduckv1.BackoffPolicyExponential, eventingduckv1.BackoffPolicyLinear:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(*ds.BackoffPolicy, "backoffPolicy"))
+		}
+	}
+	if ds.BackoffDelay != nil {
+		if delay, err := BackoffDelay(ds); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(*ds.BackoffDelay, "backoffDelay"))
+		} else if delay < 0 || delay > MaxBackoffDelay {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*ds.BackoffDelay, "PT0S", "PT600S", "backoffDelay"))
+		}
+	}
+	errs = errs.Also(brokerv1beta1.ValidateDeadLetterSink(ctx, ds.DeadLetterSink).ViaField("deadLetterSink"))
+	return errs.ViaField("delivery")
+}
+
+// ValidateReply validates the Reply of the PubSubSpec.
+func (s *PubSubSpec) ValidateReply(ctx context.Context) *apis.FieldError {
+	return s.Reply.Validate(ctx).ViaField("reply")
+}
+
+// ValidateMapping validates the Mapping of the PubSubSpec. Its templates must
+// be JSONPath templates, and the names of its extensions CloudEvent extension
+// names.
+func (s *PubSubSpec) ValidateMapping() *apis.FieldError {
+	m := s.Mapping
+	if m == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	errs = errs.Also(validateTemplate(m.Type, "type"))
+	errs = errs.Also(validateTemplate(m.Subject, "subject"))
+	for name, template := range m.Extensions {
+		if !duck.IsExtensionName(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "extensions", "the name must be a CloudEvent extension name"))
+		}
+		errs = errs.Also(validateTemplate(template, apis.CurrentField).ViaFieldKey("extensions", name))
+	}
+	return errs.ViaField("mapping")
+}
+
+// validateTemplate validates that the template of the field is empty or a
+// JSONPath template.
+func validateTemplate(template, field string) *apis.FieldError {
+	if template == "" {
+		return nil
+	}
+	if err := jsonpath.New(field).Parse(template); err != nil {
+		return &apis.FieldError{
+			Message: fmt.Sprint("invalid value: ", template),
+			Paths:   []string{field},
+			Details: err.Error(),
+		}
+	}
+	return nil
+}
+
+// BackoffDelay parses the ISO 8601 backoff delay of the delivery spec.
+func BackoffDelay(ds *eventingduckv1.DeliverySpec) (time.Duration, error) {
+	p, err := period.Parse(*ds.BackoffDelay)
+	if err != nil {
+		return 0, err
+	}
+	d, _ := p.Duration()
+	return d, nil
+}
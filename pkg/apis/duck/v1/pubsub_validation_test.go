@@ -0,0 +1,243 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestPubSubSpec_ValidateMode(t *testing.T) {
+	overrides := &duckv1.CloudEventOverrides{Extensions: map[string]string{"foo": "bar"}}
+	mapping := &EventMapping{Subject: "{.name}"}
+
+	testCases := map[string]struct {
+		mode      ModeType
+		overrides *duckv1.CloudEventOverrides
+		mapping   *EventMapping
+		want      *apis.FieldError
+	}{
+		"default": {
+			mode: "",
+		},
+		"binary": {
+			mode: ModeCloudEventsBinary,
+		},
+		"structured": {
+			mode: ModeCloudEventsStructured,
+		},
+		"raw data": {
+			mode: ModeRawData,
+		},
+		"push": {
+			mode: ModePush,
+		},
+		"binary with overrides and mapping": {
+			mode:      ModeCloudEventsBinary,
+			overrides: overrides,
+			mapping:   mapping,
+		},
+		"push with overrides": {
+			mode:      ModePush,
+			overrides: overrides,
+			want:      apis.ErrGeneric("ceOverrides can't be set with mode Push", "ceOverrides"),
+		},
+		"push with mapping": {
+			mode:    ModePush,
+			mapping: mapping,
+			want:    apis.ErrGeneric("mapping can't be set with mode Push", "mapping"),
+		},
+		"push with overrides and mapping": {
+			mode:      ModePush,
+			overrides: overrides,
+			mapping:   mapping,
+			want: apis.ErrGeneric("ceOverrides can't be set with mode Push", "ceOverrides").Also(
+				apis.ErrGeneric("mapping can't be set with mode Push", "mapping")),
+		},
+		"invalid": {
+			mode: "PushCompatible",
+			want: apis.ErrInvalidValue("PushCompatible", "mode"),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			spec := &PubSubSpec{Mode: tc.mode, Mapping: tc.mapping}
+			spec.CloudEventOverrides = tc.overrides
+			got := spec.ValidateMode()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Error("unexpected error (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestPubSubSpec_ValidateDelivery(t *testing.T) {
+	retry := func(r int32) *int32 { return &r }
+	linear := eventingduckv1.BackoffPolicyLinear
+	invalidPolicy := eventingduckv1.BackoffPolicyType("constant")
+	delay := func(d string) *string { return &d }
+	sink := &duckv1.Destination{URI: apis.HTTP("dead-letter")}
+
+	testCases := map[string]struct {
+		delivery *eventingduckv1.DeliverySpec
+		want     *apis.FieldError
+	}{
+		"nil": {},
+		"valid": {
+			delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: sink,
+				Retry:          retry(10),
+				BackoffPolicy:  &linear,
+				BackoffDelay:   delay("PT10S"),
+			},
+		},
+		"pubsub dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter"}},
+			},
+		},
+		"empty pubsub dead letter topic": {
+			delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub"}},
+			},
+			want: apis.ErrInvalidValue("Dead letter topic must not be empty", "delivery.deadLetterSink.uri"),
+		},
+		"retry without dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{
+				Retry: retry(5),
+			},
+			want: apis.ErrGeneric("need DeadLetterSink when retry is defined", "delivery.deadLetterSink"),
+		},
+		"retry out of bounds": {
+			delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: sink,
+				Retry:          retry(3),
+			},
+			want: apis.ErrOutOfBoundsValue(3, MinDeliveryAttempts, MaxDeliveryAttempts, "delivery.retry"),
+		},
+		"invalid backoff policy": {
+			delivery: &eventingduckv1.DeliverySpec{
+				BackoffPolicy: &invalidPolicy,
+			},
+			want: apis.ErrInvalidValue(invalidPolicy, "delivery.backoffPolicy"),
+		},
+		"invalid backoff delay": {
+			delivery: &eventingduckv1.DeliverySpec{
+				BackoffDelay: delay("10s"),
+			},
+			want: apis.ErrInvalidValue("10s", "delivery.backoffDelay"),
+		},
+		"backoff delay out of bounds": {
+			delivery: &eventingduckv1.DeliverySpec{
+				BackoffDelay: delay("PT11M"),
+			},
+			want: apis.ErrOutOfBoundsValue("PT11M", "PT0S", "PT600S", "delivery.backoffDelay"),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			spec := &PubSubSpec{Delivery: tc.delivery}
+			got := spec.ValidateDelivery(context.Background())
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Error("unexpected error (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestPubSubSpec_ValidateReply(t *testing.T) {
+	testCases := map[string]struct {
+		reply *duckv1.Destination
+		want  *apis.FieldError
+	}{
+		"nil": {},
+		"valid": {
+			reply: &duckv1.Destination{URI: apis.HTTP("reply")},
+		},
+		"empty": {
+			reply: &duckv1.Destination{},
+			want:  apis.ErrGeneric("expected at least one, got none", "reply.ref", "reply.uri"),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			spec := &PubSubSpec{Reply: tc.reply}
+			got := spec.ValidateReply(context.Background())
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Error("unexpected error (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestPubSubSpec_ValidateMapping(t *testing.T) {
+	testCases := map[string]struct {
+		mapping *EventMapping
+		want    *apis.FieldError
+	}{
+		"nil": {},
+		"valid": {
+			mapping: &EventMapping{
+				Type:       "com.example.{.kind}",
+				Subject:    "{.bucket}/{.name}",
+				Extensions: map[string]string{"method": "{.protoPayload.methodName}"},
+			},
+		},
+		"bad type": {
+			mapping: &EventMapping{Type: "{.kind"},
+			want: &apis.FieldError{
+				Message: "invalid value: {.kind",
+				Paths:   []string{"mapping.type"},
+				Details: "unclosed action",
+			},
+		},
+		"bad subject": {
+			mapping: &EventMapping{Subject: "{.bucket}{"},
+			want: &apis.FieldError{
+				Message: "invalid value: {.bucket}{",
+				Paths:   []string{"mapping.subject"},
+				Details: "unclosed action",
+			},
+		},
+		"bad extension template": {
+			mapping: &EventMapping{Extensions: map[string]string{"method": "{.method"}},
+			want: &apis.FieldError{
+				Message: "invalid value: {.method",
+				Paths:   []string{"mapping.extensions[method]"},
+				Details: "unclosed action",
+			},
+		},
+		"bad extension name": {
+			mapping: &EventMapping{Extensions: map[string]string{"subject": "{.name}"}},
+			want:    apis.ErrInvalidKeyName("subject", "mapping.extensions", "the name must be a CloudEvent extension name"),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			spec := &PubSubSpec{Mapping: tc.mapping}
+			got := spec.ValidateMapping()
+			if diff := cmp.Diff(tc.want.Error(), got.Error()); diff != "" {
+				t.Error("unexpected error (-want, +got):", diff)
+			}
+		})
+	}
+}
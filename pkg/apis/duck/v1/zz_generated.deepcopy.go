@@ -23,10 +23,34 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	duckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	apis "knative.dev/pkg/apis"
-	duckv1 "knative.dev/pkg/apis/duck/v1"
+	apisduckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventMapping) DeepCopyInto(out *EventMapping) {
+	*out = *in
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventMapping.
+func (in *EventMapping) DeepCopy() *EventMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(EventMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IdentitySpec) DeepCopyInto(out *IdentitySpec) {
 	*out = *in
@@ -131,6 +155,21 @@ func (in *PubSubSpec) DeepCopyInto(out *PubSubSpec) {
 		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Delivery != nil {
+		in, out := &in.Delivery, &out.Delivery
+		*out = new(duckv1.DeliverySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Reply != nil {
+		in, out := &in.Reply, &out.Reply
+		*out = new(apisduckv1.Destination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mapping != nil {
+		in, out := &in.Mapping, &out.Mapping
+		*out = new(EventMapping)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -155,9 +194,14 @@ func (in *PubSubStatus) DeepCopyInto(out *PubSubStatus) {
 	}
 	if in.CloudEventAttributes != nil {
 		in, out := &in.CloudEventAttributes, &out.CloudEventAttributes
-		*out = make([]duckv1.CloudEventAttributes, len(*in))
+		*out = make([]apisduckv1.CloudEventAttributes, len(*in))
 		copy(*out, *in)
 	}
+	if in.Backlog != nil {
+		in, out := &in.Backlog, &out.Backlog
+		*out = new(SubscriptionBacklog)
+		**out = **in
+	}
 	return
 }
 
@@ -229,3 +273,19 @@ func (in *ResourceList) DeepCopyObject() runtime.Object {
 	}
 	return nil
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionBacklog) DeepCopyInto(out *SubscriptionBacklog) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionBacklog.
+func (in *SubscriptionBacklog) DeepCopy() *SubscriptionBacklog {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionBacklog)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -28,6 +28,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/apis"
 )
 
@@ -35,8 +36,28 @@ var (
 	// The name of a k8s ServiceAccount object must be a valid DNS subdomain name.
 	// https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#dns-subdomain-names
 	ksaValidationRegex = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9\-]{0,61}[A-Za-z0-9])?$`)
+
+	// reservedAttributeNames are the names of the CloudEvent context
+	// attributes, which can't be used as extension names.
+	reservedAttributeNames = sets.NewString(
+		"id", "source", "specversion", "type", "datacontenttype", "dataschema", "subject", "time", "data")
 )
 
+// IsExtensionName returns true if the name is a CloudEvent extension name: it
+// consists of lower-case letters and digits, and is not the name of a context
+// attribute.
+func IsExtensionName(name string) bool {
+	if name == "" || reservedAttributeNames.Has(name) {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
 // ValidateAutoscalingAnnotations validates the autoscaling annotations.
 // The class ensures that we reconcile using the corresponding controller.
 func ValidateAutoscalingAnnotations(ctx context.Context, annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
@@ -89,6 +110,23 @@ func validateAnnotationNotExists(annotations map[string]string, annotation strin
 	return errs
 }
 
+// ValidatePausedAnnotation validates the paused annotation.
+func ValidatePausedAnnotation(annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
+	if paused, ok := annotations[PausedAnnotation]; ok {
+		if _, err := strconv.ParseBool(paused); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(paused, fmt.Sprintf("metadata.annotations[%s]", PausedAnnotation)))
+		}
+	}
+	return errs
+}
+
+// IsPaused returns true if the annotations pause the delivery of the events of
+// the resource.
+func IsPaused(annotations map[string]string) bool {
+	paused, _ := strconv.ParseBool(annotations[PausedAnnotation])
+	return paused
+}
+
 // CheckImmutableClusterNameAnnotation checks non-empty cluster-name annotation is immutable.
 func CheckImmutableClusterNameAnnotation(current *metav1.ObjectMeta, original *metav1.ObjectMeta, errs *apis.FieldError) *apis.FieldError {
 	if _, ok := original.Annotations[ClusterNameAnnotation]; ok {
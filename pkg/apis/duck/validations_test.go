@@ -120,6 +120,39 @@ func TestValidateAutoscalingAnnotations(t *testing.T) {
 	}
 }
 
+func TestValidatePausedAnnotation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		paused      bool
+		error       bool
+	}{
+		"not paused": {},
+		"paused": {
+			annotations: map[string]string{PausedAnnotation: "true"},
+			paused:      true,
+		},
+		"resumed": {
+			annotations: map[string]string{PausedAnnotation: "false"},
+		},
+		"invalid": {
+			annotations: map[string]string{PausedAnnotation: "yes"},
+			error:       true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var errs *apis.FieldError
+			err := ValidatePausedAnnotation(tc.annotations, errs)
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+			if got := IsPaused(tc.annotations); got != tc.paused {
+				t.Errorf("IsPaused()=%v, want=%v", got, tc.paused)
+			}
+		})
+	}
+}
+
 func TestCheckImmutableClusterNameAnnotation(t *testing.T) {
 	testCases := map[string]struct {
 		original *v1.ObjectMeta
@@ -345,3 +378,23 @@ func TestValidateCredential(t *testing.T) {
 		}
 	}
 }
+
+func TestIsExtensionName(t *testing.T) {
+	testCases := []struct {
+		name string
+		want bool
+	}{
+		{name: "bucket", want: true},
+		{name: "method2", want: true},
+		{name: "", want: false},
+		{name: "Bucket", want: false},
+		{name: "bucket-name", want: false},
+		{name: "subject", want: false},
+	}
+
+	for _, tc := range testCases {
+		if got := IsExtensionName(tc.name); got != tc.want {
+			t.Errorf("IsExtensionName(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
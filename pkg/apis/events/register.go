@@ -50,4 +50,14 @@ var (
 		Group:    GroupName,
 		Resource: "cloudbuildsources",
 	}
+	// CloudFirestoreSourcesResource represents a CloudFirestoreSource.
+	CloudFirestoreSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudfirestoresources",
+	}
+	// CloudMonitoringAlertSourcesResource represents a CloudMonitoringAlertSource.
+	CloudMonitoringAlertSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudmonitoringalertsources",
+	}
 )
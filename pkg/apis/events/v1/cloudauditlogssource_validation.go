@@ -35,7 +35,8 @@ func (current *CloudAuditLogsSource) Validate(ctx context.Context) *apis.FieldEr
 		original := apis.GetBaseline(ctx).(*CloudAuditLogsSource)
 		err = err.Also(current.CheckImmutableFields(ctx, original))
 	}
-	return err
+	err = duck.ValidatePausedAnnotation(current.Annotations, err)
+	return duck.ValidateBacklogAnnotations(current.Annotations, err)
 }
 
 func (current *CloudAuditLogsSourceSpec) Validate(ctx context.Context) *apis.FieldError {
@@ -61,6 +62,22 @@ func (current *CloudAuditLogsSourceSpec) Validate(ctx context.Context) *apis.Fie
 		errs = errs.Also(err)
 	}
 
+	if err := current.ValidateMode(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateDelivery(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateReply(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateMapping(); err != nil {
+		errs = errs.Also(err)
+	}
+
 	return errs
 }
 
@@ -74,7 +91,7 @@ func (current *CloudAuditLogsSource) CheckImmutableFields(ctx context.Context, o
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudAuditLogsSourceSpec{},
-			"Sink", "CloudEventOverrides")); diff != "" {
+			"Sink", "CloudEventOverrides", "Mode", "Delivery", "Reply", "Mapping")); diff != "" {
 		errs = errs.Also(
 			&apis.FieldError{
 				Message: "Immutable fields changed (-old +new)",
@@ -33,6 +33,8 @@ func (current *CloudBuildSource) Validate(ctx context.Context) *apis.FieldError
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
 
+	errs = duck.ValidatePausedAnnotation(current.Annotations, errs)
+	errs = duck.ValidateBacklogAnnotations(current.Annotations, errs)
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -50,6 +52,22 @@ func (current *CloudBuildSourceSpec) Validate(ctx context.Context) *apis.FieldEr
 		errs = errs.Also(err)
 	}
 
+	if err := current.ValidateMode(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateDelivery(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateReply(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateMapping(); err != nil {
+		errs = errs.Also(err)
+	}
+
 	return errs
 }
 
@@ -62,7 +80,7 @@ func (current *CloudBuildSource) CheckImmutableFields(ctx context.Context, origi
 	// Modification of Topic, Secret and Project are not allowed. Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudBuildSourceSpec{},
-			"Sink", "CloudEventOverrides")); diff != "" {
+			"Sink", "CloudEventOverrides", "Mode", "Delivery", "Reply", "Mapping")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
@@ -0,0 +1,60 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *CloudFirestoreSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return firestoreCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *CloudFirestoreSourceStatus) GetTopLevelCondition() *apis.Condition {
+	return firestoreCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *CloudFirestoreSourceStatus) IsReady() bool {
+	return firestoreCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *CloudFirestoreSourceStatus) InitializeConditions() {
+	firestoreCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkChangeFeedNotReady sets the condition that the change feeds of the
+// CloudFirestoreSource have not been successfully created.
+func (s *CloudFirestoreSourceStatus) MarkChangeFeedNotReady(reason, messageFormat string, messageA ...interface{}) {
+	firestoreCondSet.Manage(s).MarkFalse(ChangeFeedReady, reason, messageFormat, messageA...)
+}
+
+// MarkChangeFeedUnknown sets the condition that the status of the change feeds
+// of the CloudFirestoreSource is unknown.
+func (s *CloudFirestoreSourceStatus) MarkChangeFeedUnknown(reason, messageFormat string, messageA ...interface{}) {
+	firestoreCondSet.Manage(s).MarkUnknown(ChangeFeedReady, reason, messageFormat, messageA...)
+}
+
+// MarkChangeFeedReady sets the condition for the change feeds of the
+// CloudFirestoreSource as Ready and sets Status.ChangeFeeds to feeds.
+func (s *CloudFirestoreSourceStatus) MarkChangeFeedReady(feeds []string) {
+	firestoreCondSet.Manage(s).MarkTrue(ChangeFeedReady)
+	s.ChangeFeeds = feeds
+}
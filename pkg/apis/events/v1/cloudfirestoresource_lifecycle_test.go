@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCloudFirestoreSourceStatusIsReady(t *testing.T) {
+	tests := []struct {
+		name                string
+		s                   *CloudFirestoreSourceStatus
+		wantConditionStatus corev1.ConditionStatus
+		want                bool
+	}{{
+		name: "uninitialized",
+		s:    &CloudFirestoreSourceStatus{},
+		want: false,
+	}, {
+		name: "initialized",
+		s: func() *CloudFirestoreSourceStatus {
+			s := &CloudFirestoreSource{}
+			s.Status.InitializeConditions()
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionUnknown,
+		want:                false,
+	}, {
+		name: "the status of topic is false",
+		s: func() *CloudFirestoreSourceStatus {
+			s := &CloudFirestoreSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+			s.Status.MarkChangeFeedReady([]string{"feed"})
+			s.Status.MarkTopicFailed(s.ConditionSet(), "TopicFailed", "the status of topic is false")
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionFalse,
+		want:                false,
+	}, {
+		name: "the status pullsubscription is unknown",
+		s: func() *CloudFirestoreSourceStatus {
+			s := &CloudFirestoreSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkTopicReady(s.ConditionSet())
+			s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), "PullSubscriptionUnknown", "the status of pullsubscription is unknown")
+			s.Status.MarkChangeFeedReady([]string{"feed"})
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionUnknown,
+		want:                false,
+	}, {
+		name: "change feed not ready",
+		s: func() *CloudFirestoreSourceStatus {
+			s := &CloudFirestoreSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkTopicReady(s.ConditionSet())
+			s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+			s.Status.MarkChangeFeedNotReady("NotReady", "feed not ready")
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionFalse,
+		want:                false,
+	}, {
+		name: "the status of change feed is unknown",
+		s: func() *CloudFirestoreSourceStatus {
+			s := &CloudFirestoreSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkTopicReady(s.ConditionSet())
+			s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+			s.Status.MarkChangeFeedUnknown("Unknown", "feed unknown")
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionUnknown,
+		want:                false,
+	}, {
+		name: "ready",
+		s: func() *CloudFirestoreSourceStatus {
+			s := &CloudFirestoreSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkTopicReady(s.ConditionSet())
+			s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+			s.Status.MarkChangeFeedReady([]string{"feed"})
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionTrue,
+		want:                true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.wantConditionStatus != "" {
+				gotConditionStatus := test.s.GetTopLevelCondition().Status
+				if gotConditionStatus != test.wantConditionStatus {
+					t.Errorf("unexpected condition status: want %v, got %v", test.wantConditionStatus, gotConditionStatus)
+				}
+			}
+			got := test.s.IsReady()
+			if got != test.want {
+				t.Errorf("unexpected readiness: want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestCloudFirestoreSourceStatusMarkChangeFeedReady(t *testing.T) {
+	s := &CloudFirestoreSourceStatus{}
+	s.InitializeConditions()
+	s.MarkChangeFeedReady([]string{"feed-1", "feed-2"})
+
+	if diff := cmp.Diff([]string{"feed-1", "feed-2"}, s.ChangeFeeds); diff != "" {
+		t.Errorf("unexpected change feeds (-want, +got) = %v", diff)
+	}
+	if got := s.GetCondition(ChangeFeedReady); got == nil || !got.IsTrue() {
+		t.Errorf("unexpected ChangeFeedReady condition: %v", got)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudFirestoreSource is a specification for a CloudFirestoreSource resource.
+type CloudFirestoreSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudFirestoreSourceSpec   `json:"spec"`
+	Status CloudFirestoreSourceStatus `json:"status"`
+}
+
+// Verify that CloudFirestoreSource matches various duck types.
+var (
+	_ apis.Convertible             = (*CloudFirestoreSource)(nil)
+	_ apis.Defaultable             = (*CloudFirestoreSource)(nil)
+	_ apis.Validatable             = (*CloudFirestoreSource)(nil)
+	_ runtime.Object               = (*CloudFirestoreSource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudFirestoreSource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudFirestoreSource)(nil)
+	_ kngcpduck.Identifiable       = (*CloudFirestoreSource)(nil)
+	_ kngcpduck.PubSubable         = (*CloudFirestoreSource)(nil)
+	_ duckv1.KRShaped              = (*CloudFirestoreSource)(nil)
+)
+
+// CloudFirestoreSourceSpec is the spec for a CloudFirestoreSource resource.
+type CloudFirestoreSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret and Project
+	gcpduckv1.PubSubSpec `json:",inline"`
+
+	// Location of the Firestore database, for example: "nam5" or
+	// "us-central1". The change feeds of the documents are created in it.
+	Location string `json:"location"`
+
+	// Database is the ID of the Firestore database. Defaults to "(default)".
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// Document is the path pattern of the documents to watch, relative to the
+	// root of the database, for example: "users/{userId}". A segment "*"
+	// matches any document or collection ID, "**" any number of segments,
+	// and "{name}" captures the ID of the segment.
+	Document string `json:"document"`
+
+	// EventTypes to subscribe to. If unspecified, then subscribe to the
+	// google.cloud.firestore.document.v1.written events, which are sent for
+	// all the changes of the documents.
+	// +optional
+	EventTypes []string `json:"eventTypes,omitempty"`
+}
+
+const (
+	// CloudFirestoreSourceConditionReady has status True when the CloudFirestoreSource is ready to send events.
+	CloudFirestoreSourceConditionReady = apis.ConditionReady
+
+	// ChangeFeedReady has status True when the change feeds of the documents
+	// to the Topic have been successfully created. It has status Unknown while
+	// the creation of a change feed is in progress.
+	ChangeFeedReady apis.ConditionType = "ChangeFeedReady"
+)
+
+var firestoreCondSet = apis.NewLivingConditionSet(
+	gcpduckv1.PullSubscriptionReady,
+	gcpduckv1.TopicReady,
+	ChangeFeedReady)
+
+// CloudFirestoreSourceStatus is the status for a CloudFirestoreSource resource.
+type CloudFirestoreSourceStatus struct {
+	// This brings in our GCP PubSub based events importers
+	// duck/v1 Status, SinkURI, ProjectID, TopicID and SubscriptionID
+	gcpduckv1.PubSubStatus `json:",inline"`
+
+	// ChangeFeeds are the names of the created change feeds, one per event
+	// type.
+	// +optional
+	ChangeFeeds []string `json:"changeFeeds,omitempty"`
+}
+
+func (*CloudFirestoreSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudFirestoreSource")
+}
+
+// Methods for identifiable interface
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudFirestoreSource) IdentitySpec() *gcpduckv1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudFirestoreSource) IdentityStatus() *gcpduckv1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (s *CloudFirestoreSource) ConditionSet() *apis.ConditionSet {
+	return &firestoreCondSet
+}
+
+// Methods for pubsubable interface
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudFirestoreSource) PubSubSpec() *gcpduckv1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudFirestoreSource) PubSubStatus() *gcpduckv1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudFirestoreSourceList is a list of CloudFirestoreSource resources.
+type CloudFirestoreSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CloudFirestoreSource `json:"items"`
+}
+
+// GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
+func (*CloudFirestoreSource) GetConditionSet() apis.ConditionSet {
+	return firestoreCondSet
+}
+
+// GetStatus retrieves the status of the CloudFirestoreSource. Implements the KRShaped interface.
+func (s *CloudFirestoreSource) GetStatus() *duckv1.Status {
+	return &s.Status.Status
+}
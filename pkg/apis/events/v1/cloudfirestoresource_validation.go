@@ -0,0 +1,145 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+// captureSegment matches the segments of a document path pattern which
+// capture the ID of the segment, e.g. "{userId}".
+var captureSegment = regexp.MustCompile(`^\{[A-Za-z_][A-Za-z0-9_]*\}$`)
+
+func (current *CloudFirestoreSource) Validate(ctx context.Context) *apis.FieldError {
+	errs := current.Spec.Validate(ctx).ViaField("spec")
+
+	if apis.IsInUpdate(ctx) {
+		original := apis.GetBaseline(ctx).(*CloudFirestoreSource)
+		errs = errs.Also(current.CheckImmutableFields(ctx, original))
+	}
+	errs = duck.ValidatePausedAnnotation(current.Annotations, errs)
+	errs = duck.ValidateBacklogAnnotations(current.Annotations, errs)
+	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+}
+
+func (current *CloudFirestoreSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	// Sink [required]
+	if equality.Semantic.DeepEqual(current.Sink, duckv1.Destination{}) {
+		errs = errs.Also(apis.ErrMissingField("sink"))
+	} else if err := current.Sink.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("sink"))
+	}
+
+	// Location [required]
+	if current.Location == "" {
+		errs = errs.Also(apis.ErrMissingField("location"))
+	}
+
+	// Document [required]
+	if current.Document == "" {
+		errs = errs.Also(apis.ErrMissingField("document"))
+	} else if !isDocumentPattern(current.Document) {
+		errs = errs.Also(apis.ErrInvalidValue(current.Document, "document"))
+	}
+
+	// EventTypes [optional]
+	for i, eventType := range current.EventTypes {
+		if !schemasv1.IsCloudFirestoreEventType(eventType) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(eventType, "eventTypes", i))
+		}
+	}
+
+	if err := duck.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateMode(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateDelivery(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateReply(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateMapping(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	return errs
+}
+
+// isDocumentPattern returns true if the path is a pattern of document paths:
+// its segments are IDs, "*", "**" or captures, and it has an even number of
+// segments unless one of them is "**".
+func isDocumentPattern(path string) bool {
+	segments := strings.Split(path, "/")
+	anyDepth := false
+	for _, segment := range segments {
+		switch {
+		case segment == "":
+			return false
+		case segment == "*":
+		case segment == "**":
+			anyDepth = true
+		case captureSegment.MatchString(segment):
+		case strings.ContainsAny(segment, "*{}"):
+			return false
+		}
+	}
+	return anyDepth || len(segments)%2 == 0
+}
+
+func (current *CloudFirestoreSource) CheckImmutableFields(ctx context.Context, original *CloudFirestoreSource) *apis.FieldError {
+	if original == nil {
+		return nil
+	}
+
+	var errs *apis.FieldError
+	// Modification of Location, Database, Document, Secret, ServiceAccountName, Project are not allowed.
+	// Everything else is mutable, the change feeds of the EventTypes are created and deleted accordingly.
+	if diff := cmp.Diff(original.Spec, current.Spec,
+		cmpopts.IgnoreFields(CloudFirestoreSourceSpec{},
+			"Sink", "CloudEventOverrides", "Mode", "Delivery", "Reply", "Mapping", "EventTypes")); diff != "" {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec"},
+			Details: diff,
+		})
+	}
+	// Modification of AutoscalingClassAnnotations is not allowed.
+	errs = duck.CheckImmutableAutoscalingClassAnnotations(&current.ObjectMeta, &original.ObjectMeta, errs)
+
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duck.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
+}
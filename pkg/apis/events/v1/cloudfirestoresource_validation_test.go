@@ -0,0 +1,298 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	// Location, Database, Document, EventTypes, Sink and Secret
+	firestoreWithSecret = CloudFirestoreSourceSpec{
+		Location:   "nam5",
+		Database:   schemasv1.CloudFirestoreDefaultDatabase,
+		Document:   "users/{userId}",
+		EventTypes: []string{schemasv1.CloudFirestoreDocumentWrittenEventType},
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "secret-name",
+				},
+				Key: "secret-key",
+			},
+		},
+	}
+)
+
+func TestCloudFirestoreSourceValidationFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    *CloudFirestoreSource
+		want *apis.FieldError
+	}{{
+		name: "empty",
+		s:    &CloudFirestoreSource{Spec: CloudFirestoreSourceSpec{}},
+		want: apis.ErrMissingField("spec.document", "spec.location", "spec.sink"),
+	}, {
+		name: "valid",
+		s:    &CloudFirestoreSource{Spec: firestoreWithSecret},
+		want: nil,
+	}}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.s.Validate(context.TODO())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: Validate CloudFirestoreSource (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestCloudFirestoreSourceSpecValidationFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *CloudFirestoreSourceSpec
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: firestoreWithSecret.DeepCopy(),
+		want: nil,
+	}, {
+		name: "missing location and document",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := firestoreWithSecret.DeepCopy()
+			s.Location = ""
+			s.Document = ""
+			return s
+		}(),
+		want: apis.ErrMissingField("document", "location"),
+	}, {
+		name: "all event types",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := firestoreWithSecret.DeepCopy()
+			s.EventTypes = []string{
+				schemasv1.CloudFirestoreDocumentCreatedEventType,
+				schemasv1.CloudFirestoreDocumentUpdatedEventType,
+				schemasv1.CloudFirestoreDocumentDeletedEventType,
+				schemasv1.CloudFirestoreDocumentWrittenEventType,
+			}
+			return s
+		}(),
+		want: nil,
+	}, {
+		name: "invalid event type",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := firestoreWithSecret.DeepCopy()
+			s.EventTypes = []string{schemasv1.CloudFirestoreDocumentCreatedEventType, "google.cloud.firestore.document.v1.read"}
+			return s
+		}(),
+		want: apis.ErrInvalidArrayValue("google.cloud.firestore.document.v1.read", "eventTypes", 1),
+	}, {
+		name: "invalid sink",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := firestoreWithSecret.DeepCopy()
+			s.Sink.Ref.Kind = ""
+			return s
+		}(),
+		want: apis.ErrMissingField("sink.ref.kind"),
+	}, {
+		name: "have k8s service account and secret at the same time",
+		spec: func() *CloudFirestoreSourceSpec {
+			s := firestoreWithSecret.DeepCopy()
+			s.ServiceAccountName = validServiceAccountName
+			return s
+		}(),
+		want: &apis.FieldError{
+			Message: "Can't have spec.serviceAccountName and spec.secret at the same time",
+			Paths:   []string{""},
+		},
+	}}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.spec.Validate(context.TODO())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: Validate CloudFirestoreSourceSpec (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestCloudFirestoreSourceDocumentPatterns(t *testing.T) {
+	testCases := map[string]bool{
+		"users/alice":                     true,
+		"users/*":                         true,
+		"users/{userId}":                  true,
+		"users/{userId}/messages/{msgId}": true,
+		"users/**":                        true,
+		"**":                              true,
+		"users/{userId}/**":               true,
+		"users":                           false,
+		"users/alice/messages":            false,
+		"/users/alice":                    false,
+		"users/alice/":                    false,
+		"users//alice":                    false,
+		"users/al*ce":                     false,
+		"users/{user-id}":                 false,
+		"users/{userId":                   false,
+		"users/***":                       false,
+	}
+
+	for document, valid := range testCases {
+		t.Run(document, func(t *testing.T) {
+			s := firestoreWithSecret.DeepCopy()
+			s.Document = document
+			err := s.Validate(context.TODO())
+			if valid != (err == nil) {
+				t.Errorf("Unexpected validation of document %q. Expected valid %v. Actual %v", document, valid, err)
+			}
+		})
+	}
+}
+
+func TestCloudFirestoreSourceSpecCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		orig              *CloudFirestoreSourceSpec
+		updated           CloudFirestoreSourceSpec
+		origAnnotation    map[string]string
+		updatedAnnotation map[string]string
+		allowed           bool
+	}{
+		"nil orig": {
+			updated: firestoreWithSecret,
+			allowed: true,
+		},
+		"ClusterName annotation changed": {
+			origAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "old",
+			},
+			updatedAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "new",
+			},
+			allowed: false,
+		},
+		"Location changed": {
+			orig: &firestoreWithSecret,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreWithSecret.DeepCopy()
+				s.Location = "eur3"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"Database changed": {
+			orig: &firestoreWithSecret,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreWithSecret.DeepCopy()
+				s.Database = "other"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"Document changed": {
+			orig: &firestoreWithSecret,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreWithSecret.DeepCopy()
+				s.Document = "users/**"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"Secret changed": {
+			orig: &firestoreWithSecret,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreWithSecret.DeepCopy()
+				s.Secret.Key = "other-key"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"EventTypes changed": {
+			orig: &firestoreWithSecret,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreWithSecret.DeepCopy()
+				s.EventTypes = []string{schemasv1.CloudFirestoreDocumentCreatedEventType, schemasv1.CloudFirestoreDocumentDeletedEventType}
+				return *s
+			}(),
+			allowed: true,
+		},
+		"Sink changed": {
+			orig: &firestoreWithSecret,
+			updated: func() CloudFirestoreSourceSpec {
+				s := firestoreWithSecret.DeepCopy()
+				s.Sink.Ref.Name = "other"
+				return *s
+			}(),
+			allowed: true,
+		},
+		"no change": {
+			orig:    &firestoreWithSecret,
+			updated: firestoreWithSecret,
+			allowed: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var orig *CloudFirestoreSource
+
+			if tc.origAnnotation != nil {
+				orig = &CloudFirestoreSource{
+					ObjectMeta: v1.ObjectMeta{
+						Annotations: tc.origAnnotation,
+					},
+				}
+			} else if tc.orig != nil {
+				orig = &CloudFirestoreSource{
+					Spec: *tc.orig,
+				}
+			}
+			updated := &CloudFirestoreSource{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: tc.updatedAnnotation,
+				},
+				Spec: tc.updated,
+			}
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *CloudMonitoringAlertSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return monitoringAlertCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *CloudMonitoringAlertSourceStatus) GetTopLevelCondition() *apis.Condition {
+	return monitoringAlertCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *CloudMonitoringAlertSourceStatus) IsReady() bool {
+	return monitoringAlertCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *CloudMonitoringAlertSourceStatus) InitializeConditions() {
+	monitoringAlertCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkNotificationChannelNotReady sets the condition that the notification
+// channel of the CloudMonitoringAlertSource has not been successfully created
+// or attached to the alert policies.
+func (s *CloudMonitoringAlertSourceStatus) MarkNotificationChannelNotReady(reason, messageFormat string, messageA ...interface{}) {
+	monitoringAlertCondSet.Manage(s).MarkFalse(NotificationChannelReady, reason, messageFormat, messageA...)
+}
+
+// MarkNotificationChannelUnknown sets the condition that the status of the
+// notification channel of the CloudMonitoringAlertSource is unknown.
+func (s *CloudMonitoringAlertSourceStatus) MarkNotificationChannelUnknown(reason, messageFormat string, messageA ...interface{}) {
+	monitoringAlertCondSet.Manage(s).MarkUnknown(NotificationChannelReady, reason, messageFormat, messageA...)
+}
+
+// MarkNotificationChannelReady sets the condition for the notification channel
+// of the CloudMonitoringAlertSource as Ready, and sets
+// Status.NotificationChannel to channel and Status.AlertPolicies to policies.
+func (s *CloudMonitoringAlertSourceStatus) MarkNotificationChannelReady(channel string, policies []string) {
+	monitoringAlertCondSet.Manage(s).MarkTrue(NotificationChannelReady)
+	s.NotificationChannel = channel
+	s.AlertPolicies = policies
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCloudMonitoringAlertSourceStatusIsReady(t *testing.T) {
+	tests := []struct {
+		name                string
+		s                   *CloudMonitoringAlertSourceStatus
+		wantConditionStatus corev1.ConditionStatus
+		want                bool
+	}{{
+		name: "uninitialized",
+		s:    &CloudMonitoringAlertSourceStatus{},
+		want: false,
+	}, {
+		name: "initialized",
+		s: func() *CloudMonitoringAlertSourceStatus {
+			s := &CloudMonitoringAlertSource{}
+			s.Status.InitializeConditions()
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionUnknown,
+		want:                false,
+	}, {
+		name: "the status of topic is false",
+		s: func() *CloudMonitoringAlertSourceStatus {
+			s := &CloudMonitoringAlertSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+			s.Status.MarkNotificationChannelReady("channel", []string{"policy"})
+			s.Status.MarkTopicFailed(s.ConditionSet(), "TopicFailed", "the status of topic is false")
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionFalse,
+		want:                false,
+	}, {
+		name: "the status pullsubscription is unknown",
+		s: func() *CloudMonitoringAlertSourceStatus {
+			s := &CloudMonitoringAlertSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkTopicReady(s.ConditionSet())
+			s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), "PullSubscriptionUnknown", "the status of pullsubscription is unknown")
+			s.Status.MarkNotificationChannelReady("channel", []string{"policy"})
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionUnknown,
+		want:                false,
+	}, {
+		name: "notification channel not ready",
+		s: func() *CloudMonitoringAlertSourceStatus {
+			s := &CloudMonitoringAlertSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkTopicReady(s.ConditionSet())
+			s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+			s.Status.MarkNotificationChannelNotReady("NotReady", "channel not ready")
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionFalse,
+		want:                false,
+	}, {
+		name: "the status of notification channel is unknown",
+		s: func() *CloudMonitoringAlertSourceStatus {
+			s := &CloudMonitoringAlertSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkTopicReady(s.ConditionSet())
+			s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+			s.Status.MarkNotificationChannelUnknown("Unknown", "channel unknown")
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionUnknown,
+		want:                false,
+	}, {
+		name: "ready",
+		s: func() *CloudMonitoringAlertSourceStatus {
+			s := &CloudMonitoringAlertSource{}
+			s.Status.InitializeConditions()
+			s.Status.MarkTopicReady(s.ConditionSet())
+			s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+			s.Status.MarkNotificationChannelReady("channel", []string{"policy"})
+			return &s.Status
+		}(),
+		wantConditionStatus: corev1.ConditionTrue,
+		want:                true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.wantConditionStatus != "" {
+				gotConditionStatus := test.s.GetTopLevelCondition().Status
+				if gotConditionStatus != test.wantConditionStatus {
+					t.Errorf("unexpected condition status: want %v, got %v", test.wantConditionStatus, gotConditionStatus)
+				}
+			}
+			got := test.s.IsReady()
+			if got != test.want {
+				t.Errorf("unexpected readiness: want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestCloudMonitoringAlertSourceStatusMarkNotificationChannelReady(t *testing.T) {
+	s := &CloudMonitoringAlertSourceStatus{}
+	s.InitializeConditions()
+	s.MarkNotificationChannelReady("channel", []string{"policy-1", "policy-2"})
+
+	if diff := cmp.Diff("channel", s.NotificationChannel); diff != "" {
+		t.Errorf("unexpected notification channel (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff([]string{"policy-1", "policy-2"}, s.AlertPolicies); diff != "" {
+		t.Errorf("unexpected alert policies (-want, +got) = %v", diff)
+	}
+	if got := s.GetCondition(NotificationChannelReady); got == nil || !got.IsTrue() {
+		t.Errorf("unexpected NotificationChannelReady condition: %v", got)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	// AlertPolicies, Sink and Secret
+	monitoringAlertWithSecret = CloudMonitoringAlertSourceSpec{
+		AlertPolicies: []string{"1234567890", "projects/my-project/alertPolicies/9876543210"},
+		PubSubSpec: gcpduckv1.PubSubSpec{
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "secret-name",
+				},
+				Key: "secret-key",
+			},
+		},
+	}
+)
+
+func TestCloudMonitoringAlertSourceValidationFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    *CloudMonitoringAlertSource
+		want *apis.FieldError
+	}{{
+		name: "empty",
+		s:    &CloudMonitoringAlertSource{Spec: CloudMonitoringAlertSourceSpec{}},
+		want: apis.ErrMissingField("spec.alertPolicies", "spec.sink"),
+	}, {
+		name: "valid",
+		s:    &CloudMonitoringAlertSource{Spec: monitoringAlertWithSecret},
+		want: nil,
+	}}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.s.Validate(context.TODO())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: Validate CloudMonitoringAlertSource (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestCloudMonitoringAlertSourceSpecValidationFields(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *CloudMonitoringAlertSourceSpec
+		want *apis.FieldError
+	}{{
+		name: "valid",
+		spec: monitoringAlertWithSecret.DeepCopy(),
+		want: nil,
+	}, {
+		name: "missing alert policies",
+		spec: func() *CloudMonitoringAlertSourceSpec {
+			s := monitoringAlertWithSecret.DeepCopy()
+			s.AlertPolicies = nil
+			return s
+		}(),
+		want: apis.ErrMissingField("alertPolicies"),
+	}, {
+		name: "invalid sink",
+		spec: func() *CloudMonitoringAlertSourceSpec {
+			s := monitoringAlertWithSecret.DeepCopy()
+			s.Sink.Ref.Kind = ""
+			return s
+		}(),
+		want: apis.ErrMissingField("sink.ref.kind"),
+	}, {
+		name: "have k8s service account and secret at the same time",
+		spec: func() *CloudMonitoringAlertSourceSpec {
+			s := monitoringAlertWithSecret.DeepCopy()
+			s.ServiceAccountName = validServiceAccountName
+			return s
+		}(),
+		want: &apis.FieldError{
+			Message: "Can't have spec.serviceAccountName and spec.secret at the same time",
+			Paths:   []string{""},
+		},
+	}}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.spec.Validate(context.TODO())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: Validate CloudMonitoringAlertSourceSpec (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestCloudMonitoringAlertSourceAlertPolicies(t *testing.T) {
+	testCases := map[string]bool{
+		"1234567890":                          true,
+		"projects/my-project/alertPolicies/1": true,
+		"projects/123456/alertPolicies/1":     true,
+		"":                                    false,
+		"alertPolicies/1":                     false,
+		"projects/my-project/alertPolicies/":  false,
+		"projects/my-project/channels/1":      false,
+		"projects//alertPolicies/1":           false,
+		"12 34":                               false,
+	}
+
+	for policy, valid := range testCases {
+		t.Run(policy, func(t *testing.T) {
+			s := monitoringAlertWithSecret.DeepCopy()
+			s.AlertPolicies = []string{policy}
+			err := s.Validate(context.TODO())
+			if valid != (err == nil) {
+				t.Errorf("Unexpected validation of alert policy %q. Expected valid %v. Actual %v", policy, valid, err)
+			}
+		})
+	}
+}
+
+func TestCloudMonitoringAlertSourceSpecCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		orig              *CloudMonitoringAlertSourceSpec
+		updated           CloudMonitoringAlertSourceSpec
+		origAnnotation    map[string]string
+		updatedAnnotation map[string]string
+		allowed           bool
+	}{
+		"nil orig": {
+			updated: monitoringAlertWithSecret,
+			allowed: true,
+		},
+		"ClusterName annotation changed": {
+			origAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "old",
+			},
+			updatedAnnotation: map[string]string{
+				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "new",
+			},
+			allowed: false,
+		},
+		"Project changed": {
+			orig: &monitoringAlertWithSecret,
+			updated: func() CloudMonitoringAlertSourceSpec {
+				s := monitoringAlertWithSecret.DeepCopy()
+				s.Project = "other-project"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"Secret changed": {
+			orig: &monitoringAlertWithSecret,
+			updated: func() CloudMonitoringAlertSourceSpec {
+				s := monitoringAlertWithSecret.DeepCopy()
+				s.Secret.Key = "other-key"
+				return *s
+			}(),
+			allowed: false,
+		},
+		"AlertPolicies changed": {
+			orig: &monitoringAlertWithSecret,
+			updated: func() CloudMonitoringAlertSourceSpec {
+				s := monitoringAlertWithSecret.DeepCopy()
+				s.AlertPolicies = []string{"1111111111"}
+				return *s
+			}(),
+			allowed: true,
+		},
+		"Sink changed": {
+			orig: &monitoringAlertWithSecret,
+			updated: func() CloudMonitoringAlertSourceSpec {
+				s := monitoringAlertWithSecret.DeepCopy()
+				s.Sink.Ref.Name = "other"
+				return *s
+			}(),
+			allowed: true,
+		},
+		"no change": {
+			orig:    &monitoringAlertWithSecret,
+			updated: monitoringAlertWithSecret,
+			allowed: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var orig *CloudMonitoringAlertSource
+
+			if tc.origAnnotation != nil {
+				orig = &CloudMonitoringAlertSource{
+					ObjectMeta: v1.ObjectMeta{
+						Annotations: tc.origAnnotation,
+					},
+				}
+			} else if tc.orig != nil {
+				orig = &CloudMonitoringAlertSource{
+					Spec: *tc.orig,
+				}
+			}
+			updated := &CloudMonitoringAlertSource{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: tc.updatedAnnotation,
+				},
+				Spec: tc.updated,
+			}
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
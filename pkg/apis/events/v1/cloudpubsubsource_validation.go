@@ -37,6 +37,8 @@ func (current *CloudPubSubSource) Validate(ctx context.Context) *apis.FieldError
 		original := apis.GetBaseline(ctx).(*CloudPubSubSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = duck.ValidatePausedAnnotation(current.Annotations, errs)
+	errs = duck.ValidateBacklogAnnotations(current.Annotations, errs)
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -77,6 +79,22 @@ func (current *CloudPubSubSourceSpec) Validate(ctx context.Context) *apis.FieldE
 		errs = errs.Also(err)
 	}
 
+	if err := current.ValidateMode(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateDelivery(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateReply(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateMapping(); err != nil {
+		errs = errs.Also(err)
+	}
+
 	return errs
 }
 
@@ -89,7 +107,7 @@ func (current *CloudPubSubSource) CheckImmutableFields(ctx context.Context, orig
 	// Modification of Topic, Secret, AckDeadline, RetainAckedMessages, RetentionDuration, ServiceAccountName and Project are not allowed.
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
-		cmpopts.IgnoreFields(CloudPubSubSourceSpec{}, "Sink", "CloudEventOverrides")); diff != "" {
+		cmpopts.IgnoreFields(CloudPubSubSourceSpec{}, "Sink", "CloudEventOverrides", "Mode", "Delivery", "Reply", "Mapping")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
@@ -28,6 +28,7 @@ import (
 	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/ptr"
@@ -114,6 +115,71 @@ func TestCloudPubSubSourceCheckValidationFields(t *testing.T) {
 			}(),
 			error: true,
 		},
+		"bad Mode": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mode = "PushCompatible"
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad Mode, push with mapping": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mode = gcpduckv1.ModePush
+				obj.Mapping = &gcpduckv1.EventMapping{Subject: "{.name}"}
+				return *obj
+			}(),
+			error: true,
+		},
+		"valid Delivery": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter")},
+					Retry:          ptr.Int32(10),
+				}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad Delivery, retry without dead letter sink": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1.DeliverySpec{
+					Retry: ptr.Int32(10),
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad Reply": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Reply = &duckv1.Destination{}
+				return *obj
+			}(),
+			error: true,
+		},
+		"valid Mapping": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mapping = &gcpduckv1.EventMapping{
+					Subject:    "{.name}",
+					Extensions: map[string]string{"kind": "{.kind}"},
+				}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad Mapping": {
+			spec: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mapping = &gcpduckv1.EventMapping{Type: "{.type"}
+				return *obj
+			}(),
+			error: true,
+		},
 		"bad AckDeadline": {
 			spec: func() CloudPubSubSourceSpec {
 				obj := pubSubSourceSpec.DeepCopy()
@@ -261,6 +327,44 @@ func TestCloudPubSubSourceCheckImmutableFields(t *testing.T) {
 			updated: pubSubSourceSpec,
 			allowed: true,
 		},
+		"Mode changed": {
+			orig: &pubSubSourceSpec,
+			updated: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mode = gcpduckv1.ModePush
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Delivery changed": {
+			orig: &pubSubSourceSpec,
+			updated: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter")},
+				}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Reply changed": {
+			orig: &pubSubSourceSpec,
+			updated: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Reply = &duckv1.Destination{URI: apis.HTTP("reply")}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Mapping changed": {
+			orig: &pubSubSourceSpec,
+			updated: func() CloudPubSubSourceSpec {
+				obj := pubSubSourceSpec.DeepCopy()
+				obj.Mapping = &gcpduckv1.EventMapping{Subject: "{.name}"}
+				return *obj
+			}(),
+			allowed: true,
+		},
 		"ClusterName annotation changed": {
 			origAnnotation: map[string]string{
 				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "old",
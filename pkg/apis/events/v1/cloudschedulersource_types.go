@@ -73,8 +73,65 @@ type CloudSchedulerSourceSpec struct {
 	// every minute.
 	Schedule string `json:"schedule"`
 
+	// TimeZone of the Schedule, from the tz database, for example:
+	// "America/New_York". Defaults to UTC, to which the Job is reset when the
+	// field is removed.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
 	// What data to send
-	Data string `json:"data"`
+	// +optional
+	Data string `json:"data,omitempty"`
+
+	// DataBase64 is the base64 encoded binary data to send, instead of Data.
+	// +optional
+	DataBase64 string `json:"dataBase64,omitempty"`
+
+	// Attributes are custom attributes of the Pub/Sub messages of the Job,
+	// which become extensions of the events. Their names must be valid
+	// CloudEvent extension names.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// RetryConfig of the Job when the publication of a message fails. The
+	// fields left unset are reset to the defaults of Cloud Scheduler.
+	// +optional
+	RetryConfig *CloudSchedulerRetryConfig `json:"retryConfig,omitempty"`
+
+	// Paused pauses the Job, which doesn't run until it is resumed, so the
+	// skipped runs send no events. The events.cloud.google.com/paused
+	// annotation instead pauses the receive adapter: the Job keeps running and
+	// its events wait in the Pub/Sub subscription until the adapter resumes.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// CloudSchedulerRetryConfig is the retry config of a Cloud Scheduler Job.
+type CloudSchedulerRetryConfig struct {
+	// RetryCount is the number of retries of a failed run of the Job, at
+	// most 5.
+	// +optional
+	RetryCount *int32 `json:"retryCount,omitempty"`
+
+	// MaxRetryDuration is the time limit for retrying a failed run of the
+	// Job, for example: "1h". Zero means unlimited.
+	// +optional
+	MaxRetryDuration *string `json:"maxRetryDuration,omitempty"`
+
+	// MinBackoffDuration is the minimum time to wait before retrying, for
+	// example: "5s".
+	// +optional
+	MinBackoffDuration *string `json:"minBackoffDuration,omitempty"`
+
+	// MaxBackoffDuration is the maximum time to wait before retrying, for
+	// example: "1h".
+	// +optional
+	MaxBackoffDuration *string `json:"maxBackoffDuration,omitempty"`
+
+	// MaxDoublings is the number of times the time to wait before retrying
+	// doubles, before increasing linearly up to MaxBackoffDuration.
+	// +optional
+	MaxDoublings *int32 `json:"maxDoublings,omitempty"`
 }
 
 const (
@@ -18,6 +18,8 @@ package v1
 
 import (
 	"context"
+	"encoding/base64"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -27,6 +29,9 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+// maxRetryCount is the maximum retry count of a Cloud Scheduler Job.
+const maxRetryCount = 5
+
 func (current *CloudSchedulerSource) Validate(ctx context.Context) *apis.FieldError {
 	errs := current.Spec.Validate(ctx).ViaField("spec")
 
@@ -34,6 +39,8 @@ func (current *CloudSchedulerSource) Validate(ctx context.Context) *apis.FieldEr
 		original := apis.GetBaseline(ctx).(*CloudSchedulerSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = duck.ValidatePausedAnnotation(current.Annotations, errs)
+	errs = duck.ValidateBacklogAnnotations(current.Annotations, errs)
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -57,28 +64,96 @@ func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.Fie
 		errs = errs.Also(apis.ErrMissingField("schedule"))
 	}
 
-	// Data [required]
-	if current.Data == "" {
-		errs = errs.Also(apis.ErrMissingField("data"))
+	// Data or DataBase64 [required]
+	if current.Data == "" && current.DataBase64 == "" {
+		errs = errs.Also(apis.ErrMissingOneOf("data", "dataBase64"))
+	} else if current.Data != "" && current.DataBase64 != "" {
+		errs = errs.Also(apis.ErrMultipleOneOf("data", "dataBase64"))
+	} else if current.DataBase64 != "" {
+		if _, err := base64.StdEncoding.DecodeString(current.DataBase64); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(current.DataBase64, "dataBase64"))
+		}
+	}
+
+	for name := range current.Attributes {
+		if !duck.IsExtensionName(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "attributes", "the name must be a CloudEvent extension name"))
+		}
+	}
+
+	if current.RetryConfig != nil {
+		errs = errs.Also(current.RetryConfig.Validate(ctx).ViaField("retryConfig"))
 	}
 
 	if err := duck.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
 		errs = errs.Also(err)
 	}
 
+	if err := current.ValidateMode(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateDelivery(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateReply(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateMapping(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	return errs
+}
+
+func (current *CloudSchedulerRetryConfig) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if current.RetryCount != nil && (*current.RetryCount < 0 || *current.RetryCount > maxRetryCount) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*current.RetryCount, 0, maxRetryCount, "retryCount"))
+	}
+	if current.MaxDoublings != nil && *current.MaxDoublings < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*current.MaxDoublings, "maxDoublings"))
+	}
+	errs = errs.Also(validateDuration(current.MaxRetryDuration, "maxRetryDuration"))
+	errs = errs.Also(validateDuration(current.MinBackoffDuration, "minBackoffDuration"))
+	errs = errs.Also(validateDuration(current.MaxBackoffDuration, "maxBackoffDuration"))
+	if errs == nil && current.MinBackoffDuration != nil && current.MaxBackoffDuration != nil {
+		min, _ := time.ParseDuration(*current.MinBackoffDuration)
+		max, _ := time.ParseDuration(*current.MaxBackoffDuration)
+		if min > max {
+			errs = errs.Also(&apis.FieldError{
+				Message: "minBackoffDuration must not be greater than maxBackoffDuration",
+				Paths:   []string{"minBackoffDuration", "maxBackoffDuration"},
+			})
+		}
+	}
 	return errs
 }
 
+// validateDuration validates an optional non-negative duration.
+func validateDuration(duration *string, field string) *apis.FieldError {
+	if duration == nil {
+		return nil
+	}
+	if d, err := time.ParseDuration(*duration); err != nil || d < 0 {
+		return apis.ErrInvalidValue(*duration, field)
+	}
+	return nil
+}
+
 func (current *CloudSchedulerSource) CheckImmutableFields(ctx context.Context, original *CloudSchedulerSource) *apis.FieldError {
 	if original == nil {
 		return nil
 	}
 
 	var errs *apis.FieldError
-	// Modification of Location, Schedule, Data, Secret, ServiceAccountName, Project are not allowed.
-	// Everything else is mutable.
+	// Modification of Location, Secret, ServiceAccountName, Project are not allowed.
+	// Everything else is mutable, the changes of the Job are pushed with a Job update.
 	if diff := cmp.Diff(original.Spec, current.Spec,
-		cmpopts.IgnoreFields(CloudSchedulerSourceSpec{}, "Sink", "CloudEventOverrides")); diff != "" {
+		cmpopts.IgnoreFields(CloudSchedulerSourceSpec{}, "Sink", "CloudEventOverrides", "Mode", "Delivery", "Reply", "Mapping",
+			"Schedule", "TimeZone", "Data", "DataBase64", "Attributes", "RetryConfig", "Paused")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
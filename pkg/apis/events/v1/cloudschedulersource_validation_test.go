@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
 )
 
 var (
@@ -107,14 +108,14 @@ func TestCloudSchedulerSourceValidationFields(t *testing.T) {
 		name: "empty",
 		s:    &CloudSchedulerSource{Spec: CloudSchedulerSourceSpec{}},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("spec.location", "spec.data", "spec.schedule", "spec.sink")
+			fe := apis.ErrMissingField("spec.location", "spec.schedule", "spec.sink").Also(apis.ErrMissingOneOf("spec.data", "spec.dataBase64"))
 			return fe
 		}(),
 	}, {
 		name: "missing data, schedule and sink",
 		s:    &CloudSchedulerSource{Spec: CloudSchedulerSourceSpec{Location: "location"}},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("spec.data", "spec.schedule", "spec.sink")
+			fe := apis.ErrMissingField("spec.schedule", "spec.sink").Also(apis.ErrMissingOneOf("spec.data", "spec.dataBase64"))
 			return fe
 		}(),
 	}, {
@@ -151,14 +152,14 @@ func TestCloudSchedulerSourceSpecValidationFields(t *testing.T) {
 		name: "empty",
 		spec: &CloudSchedulerSourceSpec{},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("data", "location", "schedule", "sink")
+			fe := apis.ErrMissingField("location", "schedule", "sink").Also(apis.ErrMissingOneOf("data", "dataBase64"))
 			return fe
 		}(),
 	}, {
 		name: "missing data, schedule and sink",
 		spec: &CloudSchedulerSourceSpec{Location: "location"},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("data", "schedule", "sink")
+			fe := apis.ErrMissingField("schedule", "sink").Also(apis.ErrMissingOneOf("data", "dataBase64"))
 			return fe
 		}(),
 	}, {
@@ -179,7 +180,7 @@ func TestCloudSchedulerSourceSpecValidationFields(t *testing.T) {
 			},
 		},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("data", "schedule")
+			fe := apis.ErrMissingField("schedule").Also(apis.ErrMissingOneOf("data", "dataBase64"))
 			return fe
 		}(),
 	}, {
@@ -222,9 +223,90 @@ func TestCloudSchedulerSourceSpecValidationFields(t *testing.T) {
 			},
 		},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("data")
+			fe := apis.ErrMissingOneOf("data", "dataBase64")
 			return fe
 		}(),
+	}, {
+		name: "both data and dataBase64",
+		spec: func() *CloudSchedulerSourceSpec {
+			s := schedulerWithSecret.DeepCopy()
+			s.DataBase64 = "ZGF0YQ=="
+			return s
+		}(),
+		want: apis.ErrMultipleOneOf("data", "dataBase64"),
+	}, {
+		name: "invalid dataBase64",
+		spec: func() *CloudSchedulerSourceSpec {
+			s := schedulerWithSecret.DeepCopy()
+			s.Data = ""
+			s.DataBase64 = "not base64"
+			return s
+		}(),
+		want: apis.ErrInvalidValue("not base64", "dataBase64"),
+	}, {
+		name: "valid job config",
+		spec: func() *CloudSchedulerSourceSpec {
+			s := schedulerWithSecret.DeepCopy()
+			s.Data = ""
+			s.DataBase64 = "ZGF0YQ=="
+			s.TimeZone = "America/New_York"
+			s.Attributes = map[string]string{"team": "a", "env1": "prod"}
+			s.RetryConfig = &CloudSchedulerRetryConfig{
+				RetryCount:         ptr.Int32(3),
+				MaxRetryDuration:   ptr.String("1h"),
+				MinBackoffDuration: ptr.String("5s"),
+				MaxBackoffDuration: ptr.String("1m"),
+				MaxDoublings:       ptr.Int32(4),
+			}
+			s.Paused = true
+			return s
+		}(),
+		want: nil,
+	}, {
+		name: "invalid attribute names",
+		spec: func() *CloudSchedulerSourceSpec {
+			s := schedulerWithSecret.DeepCopy()
+			s.Attributes = map[string]string{"Team": "a"}
+			return s
+		}(),
+		want: apis.ErrInvalidKeyName("Team", "attributes", "the name must be a CloudEvent extension name"),
+	}, {
+		name: "reserved attribute name",
+		spec: func() *CloudSchedulerSourceSpec {
+			s := schedulerWithSecret.DeepCopy()
+			s.Attributes = map[string]string{"source": "a"}
+			return s
+		}(),
+		want: apis.ErrInvalidKeyName("source", "attributes", "the name must be a CloudEvent extension name"),
+	}, {
+		name: "invalid retry config",
+		spec: func() *CloudSchedulerSourceSpec {
+			s := schedulerWithSecret.DeepCopy()
+			s.RetryConfig = &CloudSchedulerRetryConfig{
+				RetryCount:       ptr.Int32(6),
+				MaxRetryDuration: ptr.String("forever"),
+				MaxDoublings:     ptr.Int32(-1),
+			}
+			return s
+		}(),
+		want: apis.ErrOutOfBoundsValue(6, 0, 5, "retryCount").
+			Also(apis.ErrInvalidValue(-1, "maxDoublings")).
+			Also(apis.ErrInvalidValue("forever", "maxRetryDuration")).
+			ViaField("retryConfig"),
+	}, {
+		name: "min backoff greater than max backoff",
+		spec: func() *CloudSchedulerSourceSpec {
+			s := schedulerWithSecret.DeepCopy()
+			s.RetryConfig = &CloudSchedulerRetryConfig{
+				MinBackoffDuration: ptr.String("1h"),
+				MaxBackoffDuration: ptr.String("1m"),
+			}
+			return s
+		}(),
+		want: (&apis.FieldError{
+			Message: "minBackoffDuration must not be greater than maxBackoffDuration",
+			Paths:   []string{"minBackoffDuration", "maxBackoffDuration"},
+		}).ViaField("retryConfig"),
 	}, {
 		name: "invalid secret, missing name",
 		spec: &CloudSchedulerSourceSpec{
@@ -434,7 +516,7 @@ func TestCloudSchedulerSourceSpecCheckImmutableFields(t *testing.T) {
 				Data:       schedulerWithSecret.Data,
 				PubSubSpec: schedulerWithSecret.PubSubSpec,
 			},
-			allowed: false,
+			allowed: true,
 		},
 		"Data changed": {
 			orig: &schedulerWithSecret,
@@ -444,7 +526,23 @@ func TestCloudSchedulerSourceSpecCheckImmutableFields(t *testing.T) {
 				Data:       "some-other-data",
 				PubSubSpec: schedulerWithSecret.PubSubSpec,
 			},
-			allowed: false,
+			allowed: true,
+		},
+		"Job config changed": {
+			orig: &schedulerWithSecret,
+			updated: CloudSchedulerSourceSpec{
+				Location:   schedulerWithSecret.Location,
+				Schedule:   schedulerWithSecret.Schedule,
+				TimeZone:   "America/New_York",
+				DataBase64: "ZGF0YQ==",
+				Attributes: map[string]string{"team": "a"},
+				RetryConfig: &CloudSchedulerRetryConfig{
+					RetryCount: ptr.Int32(3),
+				},
+				Paused:     true,
+				PubSubSpec: schedulerWithSecret.PubSubSpec,
+			},
+			allowed: true,
 		},
 		"Secret.Name changed": {
 			orig: &schedulerWithSecret,
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"encoding/json"
+
 	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1"
 
@@ -52,6 +54,7 @@ var (
 	_ resourcesemantics.GenericCRD = (*CloudStorageSource)(nil)
 	_ kngcpduck.Identifiable       = (*CloudStorageSource)(nil)
 	_ kngcpduck.PubSubable         = (*CloudStorageSource)(nil)
+	_ kngcpduck.AdapterFilterable  = (*CloudStorageSource)(nil)
 	_ duckv1.KRShaped              = (*CloudStorageSource)(nil)
 )
 
@@ -71,6 +74,44 @@ type CloudStorageSourceSpec struct {
 	// ObjectNamePrefix limits the notifications to objects with this prefix
 	// +optional
 	ObjectNamePrefix string `json:"objectNamePrefix,omitempty"`
+
+	// Filter limits the events to the objects matching it. Unlike
+	// ObjectNamePrefix, it is not supported by GCS notifications and is
+	// enforced by the receive adapter, which acknowledges the events of the
+	// other objects without delivering them.
+	// +optional
+	Filter *CloudStorageObjectFilter `json:"filter,omitempty"`
+}
+
+// CloudStorageObjectFilter matches the objects matching all of its non-empty
+// fields. A list matches the objects matching any of its values.
+type CloudStorageObjectFilter struct {
+	// NamePrefixes matches the objects which name starts with one of the
+	// prefixes.
+	// +optional
+	NamePrefixes []string `json:"namePrefixes,omitempty"`
+
+	// NameSuffixes matches the objects which name ends with one of the
+	// suffixes, e.g. ".png".
+	// +optional
+	NameSuffixes []string `json:"nameSuffixes,omitempty"`
+
+	// NamePatterns matches the objects which name matches one of the glob
+	// patterns, e.g. "images/*/thumbnail-*.png". The syntax of the patterns
+	// is the one of path.Match, "*" doesn't match "/".
+	// +optional
+	NamePatterns []string `json:"namePatterns,omitempty"`
+
+	// ContentTypes matches the objects which content type is one of the media
+	// types, e.g. "image/png", ignoring its parameters. "image/*" matches all
+	// the image types.
+	// +optional
+	ContentTypes []string `json:"contentTypes,omitempty"`
+
+	// Metadata matches the objects which custom metadata has all the
+	// key-value pairs.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 const (
@@ -130,6 +171,16 @@ func (s *CloudStorageSource) PubSubStatus() *gcpduckv1.PubSubStatus {
 	return &s.Status.PubSubStatus
 }
 
+// AdapterFilter returns the JSON encoded Filter of the CloudStorageSource.
+func (s *CloudStorageSource) AdapterFilter() string {
+	if s.Spec.Filter == nil {
+		return ""
+	}
+	// A CloudStorageObjectFilter can always be encoded.
+	b, _ := json.Marshal(s.Spec.Filter)
+	return string(b)
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // CloudStorageSourceList is a list of CloudStorageSource resources.
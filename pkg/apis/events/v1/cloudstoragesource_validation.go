@@ -18,6 +18,8 @@ package v1
 
 import (
 	"context"
+	"mime"
+	"path"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"knative.dev/pkg/apis"
@@ -35,6 +37,8 @@ func (current *CloudStorageSource) Validate(ctx context.Context) *apis.FieldErro
 		original := apis.GetBaseline(ctx).(*CloudStorageSource)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = duck.ValidatePausedAnnotation(current.Annotations, errs)
+	errs = duck.ValidateBacklogAnnotations(current.Annotations, errs)
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
@@ -57,6 +61,56 @@ func (current *CloudStorageSourceSpec) Validate(ctx context.Context) *apis.Field
 		errs = errs.Also(err)
 	}
 
+	if err := current.ValidateMode(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateDelivery(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateReply(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if err := current.ValidateMapping(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	if current.Filter != nil {
+		errs = errs.Also(current.Filter.Validate(ctx).ViaField("filter"))
+	}
+
+	return errs
+}
+
+func (current *CloudStorageObjectFilter) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	for i, prefix := range current.NamePrefixes {
+		if prefix == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(prefix, "namePrefixes", i))
+		}
+	}
+	for i, suffix := range current.NameSuffixes {
+		if suffix == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(suffix, "nameSuffixes", i))
+		}
+	}
+	for i, pattern := range current.NamePatterns {
+		if _, err := path.Match(pattern, ""); pattern == "" || err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(pattern, "namePatterns", i))
+		}
+	}
+	for i, contentType := range current.ContentTypes {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(contentType, "contentTypes", i))
+		}
+	}
+	for key := range current.Metadata {
+		if key == "" {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, "metadata"))
+		}
+	}
 	return errs
 }
 
@@ -70,7 +124,7 @@ func (current *CloudStorageSource) CheckImmutableFields(ctx context.Context, ori
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudStorageSourceSpec{},
-			"Sink", "CloudEventOverrides")); diff != "" {
+			"Sink", "CloudEventOverrides", "Mode", "Delivery", "Reply", "Mapping", "Filter")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
@@ -354,6 +354,35 @@ func TestSpecValidationFields(t *testing.T) {
 			}
 			return fe
 		}(),
+	}, {
+		name: "valid filter",
+		spec: func() *CloudStorageSourceSpec {
+			s := minimalCloudStorageSourceSpec.DeepCopy()
+			s.Filter = &CloudStorageObjectFilter{
+				NamePrefixes: []string{"images/", "videos/"},
+				NameSuffixes: []string{".png"},
+				NamePatterns: []string{"images/*/thumbnail-*"},
+				ContentTypes: []string{"image/png", "video/*"},
+				Metadata:     map[string]string{"team": "a"},
+			}
+			return s
+		}(),
+		want: nil,
+	}, {
+		name: "invalid filter",
+		spec: func() *CloudStorageSourceSpec {
+			s := minimalCloudStorageSourceSpec.DeepCopy()
+			s.Filter = &CloudStorageObjectFilter{
+				NamePrefixes: []string{""},
+				NamePatterns: []string{"images/[*"},
+				ContentTypes: []string{"image/"},
+			}
+			return s
+		}(),
+		want: apis.ErrInvalidArrayValue("", "namePrefixes", 0).
+			Also(apis.ErrInvalidArrayValue("images/[*", "namePatterns", 0)).
+			Also(apis.ErrInvalidArrayValue("image/", "contentTypes", 0)).
+			ViaField("filter"),
 	}}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
@@ -439,6 +468,19 @@ func TestCheckImmutableFields(t *testing.T) {
 			},
 			allowed: false,
 		},
+		"Filter changed": {
+			orig: &storageSourceSpec,
+			updated: CloudStorageSourceSpec{
+				Bucket:           storageSourceSpec.Bucket,
+				EventTypes:       storageSourceSpec.EventTypes,
+				ObjectNamePrefix: storageSourceSpec.ObjectNamePrefix,
+				PubSubSpec:       storageSourceSpec.PubSubSpec,
+				Filter: &CloudStorageObjectFilter{
+					NameSuffixes: []string{".png"},
+				},
+			},
+			allowed: true,
+		},
 		"Secret.Name changed": {
 			orig: &storageSourceSpec,
 			updated: CloudStorageSourceSpec{
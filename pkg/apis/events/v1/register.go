@@ -49,6 +49,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CloudAuditLogsSourceList{},
 		&CloudBuildSource{},
 		&CloudBuildSourceList{},
+		&CloudFirestoreSource{},
+		&CloudFirestoreSourceList{},
+		&CloudMonitoringAlertSource{},
+		&CloudMonitoringAlertSourceList{},
 		&CloudPubSubSource{},
 		&CloudPubSubSourceList{},
 		&CloudSchedulerSource{},
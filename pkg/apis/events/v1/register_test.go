@@ -61,6 +61,8 @@ func TestKnownTypes(t *testing.T) {
 	for _, name := range []string{
 		"CloudAuditLogsSource",
 		"CloudBuildSource",
+		"CloudFirestoreSource",
+		"CloudMonitoringAlertSource",
 		"CloudPubSubSource",
 		"CloudSchedulerSource",
 		"CloudStorageSource",
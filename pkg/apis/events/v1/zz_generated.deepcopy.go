@@ -214,6 +214,216 @@ func (in *CloudBuildSourceStatus) DeepCopy() *CloudBuildSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFirestoreSource) DeepCopyInto(out *CloudFirestoreSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFirestoreSource.
+func (in *CloudFirestoreSource) DeepCopy() *CloudFirestoreSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFirestoreSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudFirestoreSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFirestoreSourceList) DeepCopyInto(out *CloudFirestoreSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudFirestoreSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFirestoreSourceList.
+func (in *CloudFirestoreSourceList) DeepCopy() *CloudFirestoreSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFirestoreSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudFirestoreSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFirestoreSourceSpec) DeepCopyInto(out *CloudFirestoreSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.EventTypes != nil {
+		in, out := &in.EventTypes, &out.EventTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFirestoreSourceSpec.
+func (in *CloudFirestoreSourceSpec) DeepCopy() *CloudFirestoreSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFirestoreSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudFirestoreSourceStatus) DeepCopyInto(out *CloudFirestoreSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	if in.ChangeFeeds != nil {
+		in, out := &in.ChangeFeeds, &out.ChangeFeeds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudFirestoreSourceStatus.
+func (in *CloudFirestoreSourceStatus) DeepCopy() *CloudFirestoreSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudFirestoreSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSource) DeepCopyInto(out *CloudMonitoringAlertSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSource.
+func (in *CloudMonitoringAlertSource) DeepCopy() *CloudMonitoringAlertSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudMonitoringAlertSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceList) DeepCopyInto(out *CloudMonitoringAlertSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudMonitoringAlertSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceList.
+func (in *CloudMonitoringAlertSourceList) DeepCopy() *CloudMonitoringAlertSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudMonitoringAlertSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceSpec) DeepCopyInto(out *CloudMonitoringAlertSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.AlertPolicies != nil {
+		in, out := &in.AlertPolicies, &out.AlertPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceSpec.
+func (in *CloudMonitoringAlertSourceSpec) DeepCopy() *CloudMonitoringAlertSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceStatus) DeepCopyInto(out *CloudMonitoringAlertSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	if in.AlertPolicies != nil {
+		in, out := &in.AlertPolicies, &out.AlertPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceStatus.
+func (in *CloudMonitoringAlertSourceStatus) DeepCopy() *CloudMonitoringAlertSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudPubSubSource) DeepCopyInto(out *CloudPubSubSource) {
 	*out = *in
@@ -319,6 +529,47 @@ func (in *CloudPubSubSourceStatus) DeepCopy() *CloudPubSubSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudSchedulerRetryConfig) DeepCopyInto(out *CloudSchedulerRetryConfig) {
+	*out = *in
+	if in.RetryCount != nil {
+		in, out := &in.RetryCount, &out.RetryCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRetryDuration != nil {
+		in, out := &in.MaxRetryDuration, &out.MaxRetryDuration
+		*out = new(string)
+		**out = **in
+	}
+	if in.MinBackoffDuration != nil {
+		in, out := &in.MinBackoffDuration, &out.MinBackoffDuration
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxBackoffDuration != nil {
+		in, out := &in.MaxBackoffDuration, &out.MaxBackoffDuration
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxDoublings != nil {
+		in, out := &in.MaxDoublings, &out.MaxDoublings
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudSchedulerRetryConfig.
+func (in *CloudSchedulerRetryConfig) DeepCopy() *CloudSchedulerRetryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudSchedulerRetryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudSchedulerSource) DeepCopyInto(out *CloudSchedulerSource) {
 	*out = *in
@@ -384,6 +635,18 @@ func (in *CloudSchedulerSourceList) DeepCopyObject() runtime.Object {
 func (in *CloudSchedulerSourceSpec) DeepCopyInto(out *CloudSchedulerSourceSpec) {
 	*out = *in
 	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RetryConfig != nil {
+		in, out := &in.RetryConfig, &out.RetryConfig
+		*out = new(CloudSchedulerRetryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -414,6 +677,49 @@ func (in *CloudSchedulerSourceStatus) DeepCopy() *CloudSchedulerSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudStorageObjectFilter) DeepCopyInto(out *CloudStorageObjectFilter) {
+	*out = *in
+	if in.NamePrefixes != nil {
+		in, out := &in.NamePrefixes, &out.NamePrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NameSuffixes != nil {
+		in, out := &in.NameSuffixes, &out.NameSuffixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamePatterns != nil {
+		in, out := &in.NamePatterns, &out.NamePatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContentTypes != nil {
+		in, out := &in.ContentTypes, &out.ContentTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudStorageObjectFilter.
+func (in *CloudStorageObjectFilter) DeepCopy() *CloudStorageObjectFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudStorageObjectFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudStorageSource) DeepCopyInto(out *CloudStorageSource) {
 	*out = *in
@@ -484,6 +790,11 @@ func (in *CloudStorageSourceSpec) DeepCopyInto(out *CloudStorageSourceSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(CloudStorageObjectFilter)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
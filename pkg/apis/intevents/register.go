@@ -31,6 +31,9 @@ const (
 	SourceLabelKey = "events.cloud.google.com/source-name"
 	// ChannelLabelKey is the label name used to identify the channel that owns a PS or Topic.
 	ChannelLabelKey = "events.cloud.google.com/channel-name"
+	// AdapterFilterAnnotation is the annotation of a PS holding the JSON encoded filter of the events of its source,
+	// which is enforced by its receive adapter.
+	AdapterFilterAnnotation = GroupName + "/adapterFilter"
 	// DefaultRetentionDuration is the default retention duration (7 days) in the default pullSubscription spec.
 	DefaultRetentionDuration = 7 * 24 * time.Hour
 	// DefaultAckDeadline is the default ack deadline (30 seconds) in the default pullSubscription spec.
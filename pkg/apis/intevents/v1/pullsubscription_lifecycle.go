@@ -93,6 +93,41 @@ func (s *PullSubscriptionStatus) MarkDeployedUnknown(reason, messageFormat strin
 	pullSubscriptionCondSet.Manage(s).MarkUnknown(PullSubscriptionConditionDeployed, reason, messageFormat, messageA...)
 }
 
+// MarkPaused sets the condition that the PullSubscription is paused.
+func (s *PullSubscriptionStatus) MarkPaused() {
+	pullSubscriptionCondSet.Manage(s).MarkTrueWithReason(PullSubscriptionConditionPaused, "Paused", "The receive adapter is scaled to zero, the events accumulate in the Pub/Sub subscription")
+}
+
+// ClearPausedCondition removes the paused condition once the PullSubscription
+// is resumed.
+func (s *PullSubscriptionStatus) ClearPausedCondition() {
+	pullSubscriptionCondSet.Manage(s).ClearCondition(PullSubscriptionConditionPaused)
+}
+
+// MarkBacklogHealthy sets the condition that the backlog of the subscription
+// is below its thresholds.
+func (s *PullSubscriptionStatus) MarkBacklogHealthy() {
+	s.PubSubStatus.MarkBacklogHealthy(&pullSubscriptionCondSet)
+}
+
+// MarkBacklogThresholdExceeded sets the condition that the backlog of the
+// subscription exceeds one of its thresholds.
+func (s *PullSubscriptionStatus) MarkBacklogThresholdExceeded(messageFormat string, messageA ...interface{}) {
+	s.PubSubStatus.MarkBacklogThresholdExceeded(&pullSubscriptionCondSet, messageFormat, messageA...)
+}
+
+// MarkBacklogUnknown sets the condition that the backlog of the subscription
+// is unknown.
+func (s *PullSubscriptionStatus) MarkBacklogUnknown(reason, messageFormat string, messageA ...interface{}) {
+	s.PubSubStatus.MarkBacklogUnknown(&pullSubscriptionCondSet, reason, messageFormat, messageA...)
+}
+
+// ClearBacklog removes the backlog and its condition once the backlog
+// reporting is disabled.
+func (s *PullSubscriptionStatus) ClearBacklog() {
+	s.PubSubStatus.ClearBacklog(&pullSubscriptionCondSet)
+}
+
 // PropagateDeploymentAvailability uses the availability of the provided Deployment to determine if
 // PullSubscriptionConditionDeployed should be marked as true or false.
 // For authentication check purpose, this method will return false if a false condition
@@ -18,6 +18,7 @@ package v1
 
 import (
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +27,8 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 )
 
 var (
@@ -531,3 +534,49 @@ func TestPropagateDeploymentAvailability(t *testing.T) {
 		t.Error("unexpected condition (-want, +got) =", diff)
 	}
 }
+
+func TestPullSubscriptionPausedCondition(t *testing.T) {
+	s := &PullSubscriptionStatus{}
+	s.InitializeConditions()
+
+	s.MarkPaused()
+	if got := s.GetCondition(PullSubscriptionConditionPaused).Status; got != corev1.ConditionTrue {
+		t.Errorf("unexpected paused condition: want %v, got %v", corev1.ConditionTrue, got)
+	}
+
+	s.ClearPausedCondition()
+	if got := s.GetCondition(PullSubscriptionConditionPaused); got != nil {
+		t.Errorf("expected the paused condition to be cleared, got %v", got)
+	}
+}
+
+func TestPullSubscriptionBacklog(t *testing.T) {
+	s := &PullSubscriptionStatus{}
+	s.InitializeConditions()
+
+	s.SetBacklog(42, 90*time.Second)
+	s.MarkBacklogThresholdExceeded("backlog of %d messages", 42)
+	want := &duckv1.SubscriptionBacklog{
+		UndeliveredMessages:     42,
+		OldestUnackedMessageAge: "1m30s",
+	}
+	if diff := cmp.Diff(want, s.Backlog); diff != "" {
+		t.Errorf("unexpected backlog (-want, +got) = %v", diff)
+	}
+	if got := s.GetCondition(PullSubscriptionConditionBacklogHealthy).Status; got != corev1.ConditionFalse {
+		t.Errorf("unexpected backlog condition: want %v, got %v", corev1.ConditionFalse, got)
+	}
+
+	s.MarkBacklogHealthy()
+	if got := s.GetCondition(PullSubscriptionConditionBacklogHealthy).Status; got != corev1.ConditionTrue {
+		t.Errorf("unexpected backlog condition: want %v, got %v", corev1.ConditionTrue, got)
+	}
+
+	s.ClearBacklog()
+	if s.Backlog != nil {
+		t.Errorf("expected the backlog to be cleared, got %v", s.Backlog)
+	}
+	if got := s.GetCondition(PullSubscriptionConditionBacklogHealthy); got != nil {
+		t.Errorf("expected the backlog condition to be cleared, got %v", got)
+	}
+}
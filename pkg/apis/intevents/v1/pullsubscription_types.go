@@ -137,6 +137,16 @@ const (
 	// PullSubscriptionConditionTransformerProvided has status True when the
 	// PullSubscription has been configured with a transformer target.
 	PullSubscriptionConditionTransformerProvided apis.ConditionType = "TransformerProvided"
+
+	// PullSubscriptionConditionPaused has status True when the receive
+	// adapter is scaled to zero because the PullSubscription is paused. The
+	// Pub/Sub subscription is kept and accumulates the events.
+	PullSubscriptionConditionPaused apis.ConditionType = "Paused"
+
+	// PullSubscriptionConditionBacklogHealthy has status True when the backlog
+	// of the Pub/Sub subscription is below its thresholds. It is only set when
+	// the backlog reporting is enabled.
+	PullSubscriptionConditionBacklogHealthy apis.ConditionType = "BacklogHealthy"
 )
 
 var pullSubscriptionCondSet = apis.NewLivingConditionSet(
@@ -154,6 +164,11 @@ type PullSubscriptionStatus struct {
 	// +optional
 	TransformerURI *apis.URL `json:"transformerUri,omitempty"`
 
+	// ReplyURI is the current active reply URI that has been configured for
+	// the PullSubscription.
+	// +optional
+	ReplyURI *apis.URL `json:"replyUri,omitempty"`
+
 	// SubscriptionID is the created subscription ID used by the PullSubscription.
 	// +optional
 	SubscriptionID string `json:"subscriptionId,omitempty"`
@@ -18,11 +18,15 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/duck"
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
 	"github.com/google/knative-gcp/pkg/apis/intevents"
 
 	corev1 "k8s.io/api/core/v1"
@@ -38,9 +42,35 @@ func (current *PullSubscription) Validate(ctx context.Context) *apis.FieldError
 		original := apis.GetBaseline(ctx).(*PullSubscription)
 		errs = errs.Also(current.CheckImmutableFields(ctx, original))
 	}
+	errs = duck.ValidatePausedAnnotation(current.Annotations, errs)
+	errs = duck.ValidateBacklogAnnotations(current.Annotations, errs)
+	errs = validateAdapterFilterAnnotation(current.Spec.AdapterType, current.Annotations, errs)
 	return duck.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
 }
 
+// storageAdapterType is the adapter type of the receive adapter of
+// CloudStorageSources, see converters.CloudStorage.
+const storageAdapterType = "storage"
+
+// validateAdapterFilterAnnotation validates the filter of the events of the
+// source of the PullSubscription. Only the receive adapter of
+// CloudStorageSources filters events, which fails to start with an invalid
+// filter.
+func validateAdapterFilterAnnotation(adapterType string, annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
+	filter, ok := annotations[intevents.AdapterFilterAnnotation]
+	if !ok {
+		return errs
+	}
+	field := fmt.Sprintf("metadata.annotations[%s]", intevents.AdapterFilterAnnotation)
+	if adapterType != storageAdapterType {
+		return errs.Also(apis.ErrGeneric(fmt.Sprintf("the events of the adapter type %q can't be filtered", adapterType), field))
+	}
+	if err := json.Unmarshal([]byte(filter), &eventsv1.CloudStorageObjectFilter{}); err != nil {
+		return errs.Also(apis.ErrInvalidValue(filter, field))
+	}
+	return errs
+}
+
 func (current *PullSubscriptionSpec) Validate(ctx context.Context) *apis.FieldError {
 	var errs *apis.FieldError
 	// Topic [required]
@@ -80,6 +110,31 @@ func (current *PullSubscriptionSpec) Validate(ctx context.Context) *apis.FieldEr
 		}
 	}
 
+	// Mode [optional]
+	if err := current.ValidateMode(); err != nil {
+		errs = errs.Also(err)
+	}
+
+	// Delivery [optional]
+	if err := current.ValidateDelivery(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+	// The dead letter sink of a PullSubscription must be a Pub/Sub topic, the
+	// sources manage the delivery to any other dead letter sink.
+	if current.Delivery != nil && current.Delivery.DeadLetterSink != nil && !brokerv1beta1.IsPubsubDeadLetterSink(current.Delivery.DeadLetterSink) {
+		errs = errs.Also(apis.ErrInvalidValue("Dead letter sink must be a pubsub:// topic URI", "delivery.deadLetterSink"))
+	}
+
+	// Reply [optional]
+	if err := current.ValidateReply(ctx); err != nil {
+		errs = errs.Also(err)
+	}
+
+	// Mapping [optional]
+	if err := current.ValidateMapping(); err != nil {
+		errs = errs.Also(err)
+	}
+
 	if current.Secret != nil {
 		if !equality.Semantic.DeepEqual(current.Secret, &corev1.SecretKeySelector{}) {
 			err := validateSecret(current.Secret)
@@ -114,7 +169,7 @@ func (current *PullSubscription) CheckImmutableFields(ctx context.Context, origi
 	// Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(PullSubscriptionSpec{},
-			"Sink", "Transformer", "CloudEventOverrides")); diff != "" {
+			"Sink", "Transformer", "CloudEventOverrides", "Mode", "Delivery", "Reply", "Mapping")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
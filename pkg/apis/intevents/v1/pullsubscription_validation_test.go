@@ -21,11 +21,13 @@ import (
 	"testing"
 
 	"github.com/google/knative-gcp/pkg/apis/duck"
+	"github.com/google/knative-gcp/pkg/apis/intevents"
 	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 
 	v1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/ptr"
@@ -120,6 +122,50 @@ func TestPullSubscriptionCheckValidationFields(t *testing.T) {
 			}(),
 			error: true,
 		},
+		"bad Mode": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Mode = "PushCompatible"
+				return *obj
+			}(),
+			error: true,
+		},
+		"pubsub dead letter sink": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter"}},
+				}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad Delivery, dead letter sink isn't a topic": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter")},
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad Reply": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Reply = &duckv1.Destination{}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad Mapping": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Mapping = &v1.EventMapping{Extensions: map[string]string{"Kind": "{.kind}"}}
+				return *obj
+			}(),
+			error: true,
+		},
 		"bad AckDeadline": {
 			spec: func() PullSubscriptionSpec {
 				obj := pullSubscriptionSpec.DeepCopy()
@@ -240,6 +286,44 @@ func TestPullSubscriptionCheckValidationFields(t *testing.T) {
 	}
 }
 
+func TestPullSubscriptionAdapterFilterAnnotation(t *testing.T) {
+	testCases := map[string]struct {
+		adapterType string
+		filter      string
+		error       bool
+	}{
+		"storage filter": {
+			adapterType: "storage",
+			filter:      `{"namePrefixes":["images/"]}`,
+		},
+		"invalid storage filter": {
+			adapterType: "storage",
+			filter:      `{"namePrefixes":"images/"}`,
+			error:       true,
+		},
+		"adapter type without filter": {
+			adapterType: "pubsub",
+			filter:      `{"namePrefixes":["images/"]}`,
+			error:       true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			ps := &PullSubscription{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{intevents.AdapterFilterAnnotation: tc.filter},
+				},
+				Spec: *pullSubscriptionSpec.DeepCopy(),
+			}
+			ps.Spec.AdapterType = tc.adapterType
+			err := ps.Validate(context.TODO())
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
 func TestPullSubscriptionCheckImmutableFields(t *testing.T) {
 	testCases := map[string]struct {
 		orig              interface{}
@@ -252,6 +336,44 @@ func TestPullSubscriptionCheckImmutableFields(t *testing.T) {
 			updated: pullSubscriptionSpec,
 			allowed: true,
 		},
+		"Mode changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Mode = v1.ModePush
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Delivery changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Delivery = &eventingduckv1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter"}},
+				}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Reply changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Reply = &duckv1.Destination{URI: apis.HTTP("reply")}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Mapping changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Mapping = &v1.EventMapping{Type: "{.kind}"}
+				return *obj
+			}(),
+			allowed: true,
+		},
 		"ClusterName annotation changed": {
 			origAnnotation: map[string]string{
 				duck.ClusterNameAnnotation: metadatatesting.FakeClusterName + "old",
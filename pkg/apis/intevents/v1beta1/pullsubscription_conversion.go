@@ -31,7 +31,7 @@ import (
 func (source *PullSubscription) ConvertTo(ctx context.Context, to apis.Convertible) error {
 	switch sink := to.(type) {
 	case *v1.PullSubscription:
-		// Since we remove Mode from PullSubscriptionSpec in v1, we silently remove it here.
+		// Since the v1 Mode values have different semantics, we silently remove Mode here.
 		sink.ObjectMeta = source.ObjectMeta
 		sink.Spec.PubSubSpec = convert.ToV1PubSubSpec(source.Spec.PubSubSpec)
 		sink.Spec.Topic = source.Spec.Topic
@@ -62,7 +62,7 @@ func (sink *PullSubscription) ConvertFrom(ctx context.Context, from apis.Convert
 		sink.Spec.RetainAckedMessages = source.Spec.RetainAckedMessages
 		sink.Spec.RetentionDuration = source.Spec.RetentionDuration
 		sink.Spec.Transformer = source.Spec.Transformer
-		// Since we remove Mode from PullSubscriptionSpec in v1, we treat it as an empty string.
+		// Since the v1 Mode values have different semantics, we treat Mode as an empty string.
 		sink.Spec.Mode = ""
 		sink.Spec.AdapterType = source.Spec.AdapterType
 		sink.Status.PubSubStatus = convert.FromV1PubSubStatus(source.Status.PubSubStatus)
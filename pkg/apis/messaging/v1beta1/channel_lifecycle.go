@@ -23,25 +23,41 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+// conditionSet returns the condition set of the Channel. Channels served by a
+// BrokerCell have the BrokerCellReady condition, which is set by
+// InitializeBrokerCellConditions.
+func (cs *ChannelStatus) conditionSet() apis.ConditionSet {
+	if cs.Status.GetCondition(ChannelConditionBrokerCellReady) != nil {
+		return brokerCellChannelCondSet
+	}
+	return channelCondSet
+}
+
 // GetCondition returns the condition currently associated with the given type,
 // or nil.
 func (cs *ChannelStatus) GetCondition(t apis.ConditionType) *apis.Condition {
-	return channelCondSet.Manage(cs).GetCondition(t)
+	return cs.conditionSet().Manage(cs).GetCondition(t)
 }
 
 // GetTopLevelCondition returns the top level condition.
 func (cs *ChannelStatus) GetTopLevelCondition() *apis.Condition {
-	return channelCondSet.Manage(cs).GetTopLevelCondition()
+	return cs.conditionSet().Manage(cs).GetTopLevelCondition()
 }
 
 // IsReady returns true if the resource is ready overall.
 func (cs *ChannelStatus) IsReady() bool {
-	return channelCondSet.Manage(cs).IsHappy()
+	return cs.conditionSet().Manage(cs).IsHappy()
 }
 
 // InitializeConditions sets relevant unset conditions to Unknown state.
 func (cs *ChannelStatus) InitializeConditions() {
-	channelCondSet.Manage(cs).InitializeConditions()
+	cs.conditionSet().Manage(cs).InitializeConditions()
+}
+
+// InitializeBrokerCellConditions sets the unset conditions of a Channel served
+// by a BrokerCell to Unknown state.
+func (cs *ChannelStatus) InitializeBrokerCellConditions() {
+	brokerCellChannelCondSet.Manage(cs).InitializeConditions()
 }
 
 // SetAddress updates the Addressable status of the channel and propagates a
@@ -52,16 +68,16 @@ func (cs *ChannelStatus) SetAddress(url *apis.URL) {
 	}
 	if url != nil {
 		cs.Address.URL = url
-		channelCondSet.Manage(cs).MarkTrue(ChannelConditionAddressable)
+		cs.conditionSet().Manage(cs).MarkTrue(ChannelConditionAddressable)
 	} else {
 		cs.Address.URL = nil
-		channelCondSet.Manage(cs).MarkFalse(ChannelConditionAddressable, "emptyUrl", "url is empty")
+		cs.conditionSet().Manage(cs).MarkFalse(ChannelConditionAddressable, "emptyUrl", "url is empty")
 	}
 }
 
 // MarkTopicReady sets the condition that the topic has been created and ready.
 func (cs *ChannelStatus) MarkTopicReady() {
-	channelCondSet.Manage(cs).MarkTrue(ChannelConditionTopicReady)
+	cs.conditionSet().Manage(cs).MarkTrue(ChannelConditionTopicReady)
 }
 
 func (cs *ChannelStatus) PropagateTopicStatus(ts *v1beta1.TopicStatus) {
@@ -86,18 +102,48 @@ func (cs *ChannelStatus) PropagateTopicStatus(ts *v1beta1.TopicStatus) {
 // MarkTopicFailed sets the condition that signals there is not a topic for this
 // Channel. This could be because of an error or the Channel is being deleted.
 func (cs *ChannelStatus) MarkTopicFailed(reason, messageFormat string, messageA ...interface{}) {
-	channelCondSet.Manage(cs).MarkFalse(ChannelConditionTopicReady, reason, messageFormat, messageA...)
+	cs.conditionSet().Manage(cs).MarkFalse(ChannelConditionTopicReady, reason, messageFormat, messageA...)
 }
 
 func (cs *ChannelStatus) MarkTopicNotOwned(messageFormat string, messageA ...interface{}) {
-	channelCondSet.Manage(cs).MarkFalse(ChannelConditionTopicReady, "NotOwned", messageFormat, messageA...)
+	cs.conditionSet().Manage(cs).MarkFalse(ChannelConditionTopicReady, "NotOwned", messageFormat, messageA...)
 }
 
 func (cs *ChannelStatus) MarkTopicNotConfigured() {
-	channelCondSet.Manage(cs).MarkUnknown(ChannelConditionTopicReady,
+	cs.conditionSet().Manage(cs).MarkUnknown(ChannelConditionTopicReady,
 		"TopicNotConfigured", "Topic has not yet been reconciled")
 }
 
 func (cs *ChannelStatus) MarkTopicUnknown(reason, messageFormat string, messageA ...interface{}) {
-	channelCondSet.Manage(cs).MarkUnknown(ChannelConditionTopicReady, reason, messageFormat, messageA...)
+	cs.conditionSet().Manage(cs).MarkUnknown(ChannelConditionTopicReady, reason, messageFormat, messageA...)
+}
+
+// MarkSubscriptionReady sets the condition that the subscription pulled by the
+// BrokerCell's fanout has been created.
+func (cs *ChannelStatus) MarkSubscriptionReady() {
+	brokerCellChannelCondSet.Manage(cs).MarkTrue(ChannelConditionSubscriptionReady)
+}
+
+// MarkSubscriptionFailed sets the condition that signals there is not a
+// subscription pulled by the BrokerCell's fanout.
+func (cs *ChannelStatus) MarkSubscriptionFailed(reason, messageFormat string, messageA ...interface{}) {
+	brokerCellChannelCondSet.Manage(cs).MarkFalse(ChannelConditionSubscriptionReady, reason, messageFormat, messageA...)
+}
+
+func (cs *ChannelStatus) MarkSubscriptionUnknown(reason, messageFormat string, messageA ...interface{}) {
+	brokerCellChannelCondSet.Manage(cs).MarkUnknown(ChannelConditionSubscriptionReady, reason, messageFormat, messageA...)
+}
+
+// MarkBrokerCellReady sets the condition that the BrokerCell serving the
+// Channel is ready.
+func (cs *ChannelStatus) MarkBrokerCellReady() {
+	brokerCellChannelCondSet.Manage(cs).MarkTrue(ChannelConditionBrokerCellReady)
+}
+
+func (cs *ChannelStatus) MarkBrokerCellFailed(reason, messageFormat string, messageA ...interface{}) {
+	brokerCellChannelCondSet.Manage(cs).MarkFalse(ChannelConditionBrokerCellReady, reason, messageFormat, messageA...)
+}
+
+func (cs *ChannelStatus) MarkBrokerCellUnknown(reason, messageFormat string, messageA ...interface{}) {
+	brokerCellChannelCondSet.Manage(cs).MarkUnknown(ChannelConditionBrokerCellReady, reason, messageFormat, messageA...)
 }
@@ -301,3 +301,56 @@ func UnknownTopicStatus() *v1beta1.TopicStatus {
 	ts.InitializeConditions()
 	return ts
 }
+
+func TestBrokerCellChannelIsReady(t *testing.T) {
+	tests := []struct {
+		name                string
+		subscriptionReady   bool
+		brokerCellReady     bool
+		wantConditionStatus corev1.ConditionStatus
+		want                bool
+	}{{
+		name:                "all happy",
+		subscriptionReady:   true,
+		brokerCellReady:     true,
+		wantConditionStatus: corev1.ConditionTrue,
+		want:                true,
+	}, {
+		name:                "subscription not ready",
+		brokerCellReady:     true,
+		wantConditionStatus: corev1.ConditionFalse,
+		want:                false,
+	}, {
+		name:                "brokercell not ready",
+		subscriptionReady:   true,
+		wantConditionStatus: corev1.ConditionUnknown,
+		want:                false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cs := &ChannelStatus{}
+			cs.InitializeBrokerCellConditions()
+			cs.SetAddress(&apis.URL{Scheme: "http", Host: "foo.bar"})
+			cs.MarkTopicReady()
+			if test.subscriptionReady {
+				cs.MarkSubscriptionReady()
+			} else {
+				cs.MarkSubscriptionFailed("SubscriptionCreationFailed", "failed")
+			}
+			if test.brokerCellReady {
+				cs.MarkBrokerCellReady()
+			} else {
+				cs.MarkBrokerCellUnknown("BrokerCellNotReady", "not ready")
+			}
+			// Marking the conditions of all Channels keeps the BrokerCell conditions.
+			cs.SetAddress(&apis.URL{Scheme: "http", Host: "foo.bar"})
+			gotConditionStatus := cs.GetTopLevelCondition().Status
+			if test.wantConditionStatus != gotConditionStatus {
+				t.Errorf("unexpected condition status: want %v, got %v", test.wantConditionStatus, gotConditionStatus)
+			}
+			if got := cs.IsReady(); got != test.want {
+				t.Errorf("unexpected readiness: want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
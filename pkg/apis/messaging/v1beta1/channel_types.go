@@ -86,6 +86,15 @@ var channelCondSet = apis.NewLivingConditionSet(
 	ChannelConditionTopicReady,
 )
 
+// brokerCellChannelCondSet is the condition set of the Channels served by a
+// BrokerCell.
+var brokerCellChannelCondSet = apis.NewLivingConditionSet(
+	ChannelConditionAddressable,
+	ChannelConditionTopicReady,
+	ChannelConditionSubscriptionReady,
+	ChannelConditionBrokerCellReady,
+)
+
 const (
 	// ChannelConditionReady has status True when all subconditions below have
 	// been set to True.
@@ -98,6 +107,23 @@ const (
 	// ChannelConditionTopicReady has status True when the Channel has had a
 	// Pub/Sub topic created for it.
 	ChannelConditionTopicReady apis.ConditionType = "TopicReady"
+
+	// ChannelConditionSubscriptionReady has status True when the Pub/Sub
+	// subscription pulled by the BrokerCell's fanout has been created. Only
+	// Channels served by a BrokerCell have this condition.
+	ChannelConditionSubscriptionReady apis.ConditionType = "SubscriptionReady"
+
+	// ChannelConditionBrokerCellReady has status True when the BrokerCell
+	// serving the Channel is ready. Only Channels served by a BrokerCell have
+	// this condition.
+	ChannelConditionBrokerCellReady apis.ConditionType = "BrokerCellReady"
+)
+
+const (
+	// BrokerCellAnnotationKey is the annotation of a Channel naming the
+	// BrokerCell whose data plane delivers the Channel's events. The events of
+	// Channels without it are delivered by a PullSubscription per subscriber.
+	BrokerCellAnnotationKey = "events.cloud.google.com/requestedBrokerCell"
 )
 
 // ChannelStatus represents the current state of a Channel.
@@ -136,7 +162,14 @@ func (c *Channel) IdentityStatus() *gcpduckv1.IdentityStatus {
 
 // ConditionSet returns the apis.ConditionSet of the embedding object
 func (s *Channel) ConditionSet() *apis.ConditionSet {
-	return &channelCondSet
+	cs := s.GetConditionSet()
+	return &cs
+}
+
+// BrokerCell returns the name of the BrokerCell serving the Channel, or an
+// empty string if the Channel is not served by a BrokerCell.
+func (c *Channel) BrokerCell() string {
+	return c.GetAnnotations()[BrokerCellAnnotationKey]
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -155,7 +188,10 @@ func (c *Channel) GetGroupVersionKind() schema.GroupVersionKind {
 }
 
 // GetConditionSet retrieves the condition set for this resource. Implements the KRShaped interface.
-func (*Channel) GetConditionSet() apis.ConditionSet {
+func (c *Channel) GetConditionSet() apis.ConditionSet {
+	if c.BrokerCell() != "" {
+		return brokerCellChannelCondSet
+	}
 	return channelCondSet
 }
 
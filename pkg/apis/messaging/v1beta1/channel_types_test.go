@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"knative.dev/pkg/apis"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/google/go-cmp/cmp"
@@ -95,6 +96,39 @@ func TestChannelConditionSet(t *testing.T) {
 	}
 }
 
+func TestBrokerCellChannelConditionSet(t *testing.T) {
+	want := []apis.Condition{{
+		Type: ChannelConditionAddressable,
+	}, {
+		Type: ChannelConditionTopicReady,
+	}, {
+		Type: ChannelConditionSubscriptionReady,
+	}, {
+		Type: ChannelConditionBrokerCellReady,
+	}, {
+		Type: apis.ConditionReady,
+	}}
+	c := &Channel{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{BrokerCellAnnotationKey: "default"},
+	}}
+	if got := c.BrokerCell(); got != "default" {
+		t.Errorf("BrokerCell() = %q, want %q", got, "default")
+	}
+
+	c.ConditionSet().Manage(&c.Status).InitializeConditions()
+	var got []apis.Condition = c.Status.GetConditions()
+
+	compareConditionTypes := cmp.Transformer("ConditionType", func(c apis.Condition) apis.ConditionType {
+		return c.Type
+	})
+	sortConditionTypes := cmpopts.SortSlices(func(a, b apis.Condition) bool {
+		return a.Type < b.Type
+	})
+	if diff := cmp.Diff(want, got, sortConditionTypes, compareConditionTypes); diff != "" {
+		t.Errorf("failed to get expected (-want, +got) = %v", diff)
+	}
+}
+
 func TestChannel_GetConditionSet(t *testing.T) {
 	c := &Channel{}
 
@@ -24,12 +24,19 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/knative-gcp/pkg/apis/duck"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
 func (c *Channel) Validate(ctx context.Context) *apis.FieldError {
 	err := c.Spec.Validate(ctx).ViaField("spec")
 
+	// The name of the BrokerCell is the prefix of the names of its data plane
+	// resources, so it must be a DNS label.
+	if bc, ok := c.GetAnnotations()[BrokerCellAnnotationKey]; ok && len(validation.IsDNS1123Label(bc)) != 0 {
+		err = err.Also(apis.ErrInvalidValue(bc, fmt.Sprintf("metadata.annotations[%s]", BrokerCellAnnotationKey)))
+	}
+
 	if apis.IsInUpdate(ctx) {
 		original := apis.GetBaseline(ctx).(*Channel)
 		err = err.Also(c.CheckImmutableFields(ctx, original))
@@ -77,6 +84,16 @@ func (current *Channel) CheckImmutableFields(ctx context.Context, original *Chan
 	// Modification of AutoscalingClassAnnotations is not allowed.
 	errs = duck.CheckImmutableAutoscalingClassAnnotations(&current.ObjectMeta, &original.ObjectMeta, errs)
 
+	// Moving between the PullSubscription and BrokerCell data planes, or
+	// between BrokerCells, is not allowed.
+	if original.BrokerCell() != current.BrokerCell() {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", BrokerCellAnnotationKey)},
+			Details: fmt.Sprintf("-: %q\n+: %q", original.BrokerCell(), current.BrokerCell()),
+		})
+	}
+
 	// Modification of non-empty cluster name annotation is not allowed.
 	return duck.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
 }
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/google/go-cmp/cmp"
 	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
@@ -172,6 +173,22 @@ func TestChannelValidation(t *testing.T) {
 			}
 			return fe
 		}(),
+	}, {
+		name: "valid brokercell",
+		cr: &Channel{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{BrokerCellAnnotationKey: "team-a"},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid brokercell",
+		cr: &Channel{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{BrokerCellAnnotationKey: "Team_A"},
+			},
+		},
+		want: apis.ErrInvalidValue("Team_A", "metadata.annotations[events.cloud.google.com/requestedBrokerCell]"),
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -244,3 +261,47 @@ func TestCheckImmutableFields(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckImmutableBrokerCell(t *testing.T) {
+	withBrokerCell := func(bc string) *Channel {
+		c := &Channel{Spec: channelSpec}
+		if bc != "" {
+			c.Annotations = map[string]string{BrokerCellAnnotationKey: bc}
+		}
+		return c
+	}
+	testCases := map[string]struct {
+		orig    *Channel
+		updated *Channel
+		allowed bool
+	}{
+		"unchanged": {
+			orig:    withBrokerCell("team-a"),
+			updated: withBrokerCell("team-a"),
+			allowed: true,
+		},
+		"added": {
+			orig:    withBrokerCell(""),
+			updated: withBrokerCell("team-a"),
+			allowed: false,
+		},
+		"removed": {
+			orig:    withBrokerCell("team-a"),
+			updated: withBrokerCell(""),
+			allowed: false,
+		},
+		"changed": {
+			orig:    withBrokerCell("team-a"),
+			updated: withBrokerCell("team-b"),
+			allowed: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.updated.CheckImmutableFields(context.TODO(), tc.orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
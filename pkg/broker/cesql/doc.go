@@ -0,0 +1,26 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cesql implements the subset of the CloudEvents SQL expression
+// language (CESQL) used by Trigger filters.
+//
+// Supported are the string, integer and boolean literals, event attribute
+// references, the unary NOT and minus operators, arithmetic, comparison and
+// logical (AND, OR, XOR) operators, LIKE, EXISTS, IN and a set of built-in
+// functions. Evaluation follows the CESQL type casting rules: operands are
+// implicitly cast to the type expected by an operator, and any error, such as
+// a missing attribute or a failed cast, makes the expression not match.
+package cesql
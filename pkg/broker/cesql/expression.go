@@ -0,0 +1,369 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+)
+
+// Expression is a parsed CESQL expression.
+type Expression interface {
+	// Evaluate evaluates the expression against the event. The result is a
+	// string, an int32 or a bool.
+	Evaluate(e *event.Event) (interface{}, error)
+}
+
+// Matches evaluates the expression against the event and casts the result to
+// a boolean. Evaluation errors are returned along with a false result.
+func Matches(expr Expression, e *event.Event) (bool, error) {
+	v, err := expr.Evaluate(e)
+	if err != nil {
+		return false, err
+	}
+	return castToBool(v)
+}
+
+func castToBool(v interface{}) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("cannot cast %v to boolean", v)
+}
+
+func castToInt(v interface{}) (int32, error) {
+	switch v := v.(type) {
+	case int32:
+		return v, nil
+	case string:
+		i, err := strconv.ParseInt(v, 10, 32)
+		if err == nil {
+			return int32(i), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot cast %v to integer", v)
+}
+
+func castToString(v interface{}) string {
+	switch v := v.(type) {
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// castTo casts v to the type of the target value.
+func castTo(target, v interface{}) (interface{}, error) {
+	switch target.(type) {
+	case bool:
+		return castToBool(v)
+	case int32:
+		return castToInt(v)
+	}
+	return castToString(v), nil
+}
+
+func evaluateBool(expr Expression, e *event.Event) (bool, error) {
+	v, err := expr.Evaluate(e)
+	if err != nil {
+		return false, err
+	}
+	return castToBool(v)
+}
+
+func evaluateInt(expr Expression, e *event.Event) (int32, error) {
+	v, err := expr.Evaluate(e)
+	if err != nil {
+		return 0, err
+	}
+	return castToInt(v)
+}
+
+func evaluateString(expr Expression, e *event.Event) (string, error) {
+	v, err := expr.Evaluate(e)
+	if err != nil {
+		return "", err
+	}
+	return castToString(v), nil
+}
+
+// equal compares the values after casting the right value to the type of the left one.
+func equal(left, right interface{}) (bool, error) {
+	right, err := castTo(left, right)
+	if err != nil {
+		return false, err
+	}
+	return left == right, nil
+}
+
+type literalExpression struct {
+	value interface{}
+}
+
+func (l *literalExpression) Evaluate(*event.Event) (interface{}, error) {
+	return l.value, nil
+}
+
+type attributeExpression struct {
+	attribute string
+}
+
+func (a *attributeExpression) Evaluate(e *event.Event) (interface{}, error) {
+	v, ok := eventutil.GetAttribute(e, a.attribute)
+	if !ok {
+		return nil, fmt.Errorf("missing attribute %q", a.attribute)
+	}
+	return v, nil
+}
+
+type existsExpression struct {
+	attribute string
+}
+
+func (x *existsExpression) Evaluate(e *event.Event) (interface{}, error) {
+	_, ok := eventutil.GetAttribute(e, x.attribute)
+	return ok, nil
+}
+
+type notExpression struct {
+	operand Expression
+}
+
+func (n *notExpression) Evaluate(e *event.Event) (interface{}, error) {
+	b, err := evaluateBool(n.operand, e)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type negateExpression struct {
+	operand Expression
+}
+
+func (n *negateExpression) Evaluate(e *event.Event) (interface{}, error) {
+	i, err := evaluateInt(n.operand, e)
+	if err != nil {
+		return nil, err
+	}
+	return -i, nil
+}
+
+type logicExpression struct {
+	op          string
+	left, right Expression
+}
+
+func (l *logicExpression) Evaluate(e *event.Event) (interface{}, error) {
+	left, err := evaluateBool(l.left, e)
+	if err != nil {
+		return nil, err
+	}
+	// Short circuit AND and OR.
+	if (l.op == "AND" && !left) || (l.op == "OR" && left) {
+		return left, nil
+	}
+	right, err := evaluateBool(l.right, e)
+	if err != nil {
+		return nil, err
+	}
+	if l.op == "XOR" {
+		return left != right, nil
+	}
+	return right, nil
+}
+
+type comparisonExpression struct {
+	op          string
+	left, right Expression
+}
+
+func (c *comparisonExpression) Evaluate(e *event.Event) (interface{}, error) {
+	left, err := evaluateInt(c.left, e)
+	if err != nil {
+		return nil, err
+	}
+	right, err := evaluateInt(c.right, e)
+	if err != nil {
+		return nil, err
+	}
+	switch c.op {
+	case "<":
+		return left < right, nil
+	case "<=":
+		return left <= right, nil
+	case ">":
+		return left > right, nil
+	}
+	return left >= right, nil
+}
+
+type equalityExpression struct {
+	not         bool
+	left, right Expression
+}
+
+func (eq *equalityExpression) Evaluate(e *event.Event) (interface{}, error) {
+	left, err := eq.left.Evaluate(e)
+	if err != nil {
+		return nil, err
+	}
+	right, err := eq.right.Evaluate(e)
+	if err != nil {
+		return nil, err
+	}
+	res, err := equal(left, right)
+	if err != nil {
+		return nil, err
+	}
+	return res != eq.not, nil
+}
+
+var errDivisionByZero = errors.New("division by zero")
+
+type arithmeticExpression struct {
+	op          string
+	left, right Expression
+}
+
+func (a *arithmeticExpression) Evaluate(e *event.Event) (interface{}, error) {
+	left, err := evaluateInt(a.left, e)
+	if err != nil {
+		return nil, err
+	}
+	right, err := evaluateInt(a.right, e)
+	if err != nil {
+		return nil, err
+	}
+	switch a.op {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	}
+	if right == 0 {
+		return nil, errDivisionByZero
+	}
+	if a.op == "/" {
+		return left / right, nil
+	}
+	return left % right, nil
+}
+
+type likeExpression struct {
+	not     bool
+	operand Expression
+	pattern *regexp.Regexp
+}
+
+func (l *likeExpression) Evaluate(e *event.Event) (interface{}, error) {
+	s, err := evaluateString(l.operand, e)
+	if err != nil {
+		return nil, err
+	}
+	return l.pattern.MatchString(s) != l.not, nil
+}
+
+// compileLikePattern translates a LIKE pattern, where % matches any sequence
+// of characters, _ matches a single character and \ escapes them, to a regular
+// expression.
+func compileLikePattern(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("(?s)^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case r == '%':
+			sb.WriteString(".*")
+		case r == '_':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+type inExpression struct {
+	not     bool
+	operand Expression
+	set     []Expression
+}
+
+func (in *inExpression) Evaluate(e *event.Event) (interface{}, error) {
+	v, err := in.operand.Evaluate(e)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range in.set {
+		sv, err := s.Evaluate(e)
+		if err != nil {
+			return nil, err
+		}
+		if res, err := equal(v, sv); err == nil && res {
+			return !in.not, nil
+		}
+	}
+	return in.not, nil
+}
+
+type functionExpression struct {
+	name     string
+	function function
+	args     []Expression
+}
+
+func (f *functionExpression) Evaluate(e *event.Event) (interface{}, error) {
+	args := make([]interface{}, 0, len(f.args))
+	for _, a := range f.args {
+		v, err := a.Evaluate(e)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	v, err := f.function.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.name, err)
+	}
+	return v, nil
+}
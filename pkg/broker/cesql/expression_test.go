@@ -0,0 +1,185 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func newTestEvent() *event.Event {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("/sources/storage")
+	e.SetType("com.google.cloud.storage.object.finalize")
+	e.SetSubject("objects/image.png")
+	e.SetExtension("bucket", "my-bucket")
+	e.SetExtension("size", int32(1024))
+	e.SetExtension("public", true)
+	e.SetExtension("count", "42")
+	return &e
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    interface{}
+		wantErr bool
+	}{
+		// Literals and attributes.
+		{expr: "TRUE", want: true},
+		{expr: "false", want: false},
+		{expr: "'it''s'", wantErr: true},
+		{expr: `'it\'s'`, want: "it's"},
+		{expr: "123", want: int32(123)},
+		{expr: "-123", want: int32(-123)},
+		{expr: "type", want: "com.google.cloud.storage.object.finalize"},
+		{expr: "TYPE", want: "com.google.cloud.storage.object.finalize"},
+		{expr: "size", want: int32(1024)},
+		{expr: "missing", wantErr: true},
+		{expr: "dataschema", wantErr: true},
+		// Existence.
+		{expr: "EXISTS bucket", want: true},
+		{expr: "EXISTS missing", want: false},
+		{expr: "NOT EXISTS dataschema", want: true},
+		// Equality with implicit casting to the type of the left operand.
+		{expr: "source = '/sources/storage'", want: true},
+		{expr: "source != '/sources/storage'", want: false},
+		{expr: "source <> '/sources/other'", want: true},
+		{expr: "size = '1024'", want: true},
+		{expr: "count = 42", want: true},
+		{expr: "public = 'TRUE'", want: true},
+		{expr: "size = 'abc'", wantErr: true},
+		// Comparison and arithmetic.
+		{expr: "size > 1000", want: true},
+		{expr: "size >= 1024 AND size <= 1024", want: true},
+		{expr: "count < 10", want: false},
+		{expr: "size / 2 + 1 = 513", want: true},
+		{expr: "size % 1000 * 2 = 48", want: true},
+		{expr: "size / 0 = 1", wantErr: true},
+		// Logic.
+		{expr: "TRUE AND FALSE", want: false},
+		{expr: "TRUE OR missing", want: true},
+		{expr: "FALSE AND missing", want: false},
+		{expr: "TRUE XOR TRUE", want: false},
+		{expr: "NOT (type = 'a' OR source = 'b')", want: true},
+		{expr: "size AND TRUE", wantErr: true},
+		// LIKE.
+		{expr: "type LIKE 'com.google.cloud.storage.%'", want: true},
+		{expr: "subject LIKE '%.png'", want: true},
+		{expr: "subject LIKE 'objects/image.pn_'", want: true},
+		{expr: "subject NOT LIKE '%.png'", want: false},
+		{expr: "subject LIKE 'objects/image.png%'", want: true},
+		{expr: "'100%' LIKE '100\\%'", want: true},
+		{expr: "'1000' LIKE '100\\%'", want: false},
+		{expr: "'a.c' LIKE 'a.c'", want: true},
+		{expr: "'abc' LIKE 'a.c'", want: false},
+		// IN.
+		{expr: "bucket IN ('a', 'my-bucket')", want: true},
+		{expr: "bucket NOT IN ('a', 'my-bucket')", want: false},
+		{expr: "size IN (1, 2, '1024')", want: true},
+		// Functions.
+		{expr: "LENGTH(bucket) = 9", want: true},
+		{expr: "CONCAT(bucket, '/', subject)", want: "my-bucket/objects/image.png"},
+		{expr: "CONCAT_WS('-', 'a', 'b', 'c')", want: "a-b-c"},
+		{expr: "LOWER('ABC') = UPPER('abc')", want: false},
+		{expr: "TRIM('  a  ')", want: "a"},
+		{expr: "LEFT(bucket, 2)", want: "my"},
+		{expr: "RIGHT(bucket, 100)", want: "my-bucket"},
+		{expr: "ABS(-5)", want: int32(5)},
+		{expr: "INT('12') + 1", want: int32(13)},
+		{expr: "BOOL('false')", want: false},
+		{expr: "STRING(size)", want: "1024"},
+		{expr: "IS_INT(count) AND NOT IS_BOOL(count)", want: true},
+	}
+	e := newTestEvent()
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			expr, err := Parse(tc.expr)
+			if err != nil {
+				if !tc.wantErr {
+					t.Fatalf("Parse(%q) unexpected error: %v", tc.expr, err)
+				}
+				return
+			}
+			got, err := expr.Evaluate(e)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Evaluate(%q) got error %v, want error %v", tc.expr, err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("Evaluate(%q) got %#v, want %#v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"type =",
+		"(type = 'a'",
+		"type = 'a')",
+		"'unterminated",
+		"type LIKE subject",
+		"type IN ()",
+		"EXISTS",
+		"EXISTS AND",
+		"UNKNOWN(type)",
+		"LENGTH(type, source)",
+		"LEFT(type)",
+		"type == 'a'",
+		"type = 'a' AND",
+		"size = 99999999999",
+		"type # 'a'",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := Parse(expr); err == nil {
+				t.Errorf("Parse(%q) expected error, got none", expr)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	e := newTestEvent()
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: "type LIKE '%.finalize'", want: true},
+		{expr: "'true'", want: true},
+		{expr: "size", wantErr: true},
+		{expr: "missing = 'a'", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			expr, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tc.expr, err)
+			}
+			got, err := Matches(expr, e)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Matches(%q) got error %v, want error %v", tc.expr, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Matches(%q) got %v, want %v", tc.expr, got, tc.want)
+			}
+		})
+	}
+}
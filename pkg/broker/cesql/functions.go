@@ -0,0 +1,112 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"strings"
+)
+
+type function struct {
+	minArgs int
+	// maxArgs is -1 for variadic functions.
+	maxArgs int
+	call    func(args []interface{}) (interface{}, error)
+}
+
+// functions are the supported built-in functions, keyed by upper cased name.
+var functions = map[string]function{
+	"LENGTH": {1, 1, func(args []interface{}) (interface{}, error) {
+		return int32(len([]rune(castToString(args[0])))), nil
+	}},
+	"CONCAT": {0, -1, func(args []interface{}) (interface{}, error) {
+		var sb strings.Builder
+		for _, a := range args {
+			sb.WriteString(castToString(a))
+		}
+		return sb.String(), nil
+	}},
+	"CONCAT_WS": {1, -1, func(args []interface{}) (interface{}, error) {
+		parts := make([]string, 0, len(args)-1)
+		for _, a := range args[1:] {
+			parts = append(parts, castToString(a))
+		}
+		return strings.Join(parts, castToString(args[0])), nil
+	}},
+	"LOWER": {1, 1, func(args []interface{}) (interface{}, error) {
+		return strings.ToLower(castToString(args[0])), nil
+	}},
+	"UPPER": {1, 1, func(args []interface{}) (interface{}, error) {
+		return strings.ToUpper(castToString(args[0])), nil
+	}},
+	"TRIM": {1, 1, func(args []interface{}) (interface{}, error) {
+		return strings.TrimSpace(castToString(args[0])), nil
+	}},
+	"LEFT": {2, 2, func(args []interface{}) (interface{}, error) {
+		s := []rune(castToString(args[0]))
+		n, err := castToInt(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return string(s[:clamp(n, len(s))]), nil
+	}},
+	"RIGHT": {2, 2, func(args []interface{}) (interface{}, error) {
+		s := []rune(castToString(args[0]))
+		n, err := castToInt(args[1])
+		if err != nil {
+			return nil, err
+		}
+		return string(s[len(s)-clamp(n, len(s)):]), nil
+	}},
+	"ABS": {1, 1, func(args []interface{}) (interface{}, error) {
+		i, err := castToInt(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			return -i, nil
+		}
+		return i, nil
+	}},
+	"INT": {1, 1, func(args []interface{}) (interface{}, error) {
+		return castToInt(args[0])
+	}},
+	"BOOL": {1, 1, func(args []interface{}) (interface{}, error) {
+		return castToBool(args[0])
+	}},
+	"STRING": {1, 1, func(args []interface{}) (interface{}, error) {
+		return castToString(args[0]), nil
+	}},
+	"IS_INT": {1, 1, func(args []interface{}) (interface{}, error) {
+		_, err := castToInt(args[0])
+		return err == nil, nil
+	}},
+	"IS_BOOL": {1, 1, func(args []interface{}) (interface{}, error) {
+		_, err := castToBool(args[0])
+		return err == nil, nil
+	}},
+}
+
+// clamp limits n to the range [0, max].
+func clamp(n int32, max int) int {
+	if n < 0 {
+		return 0
+	}
+	if int(n) > max {
+		return max
+	}
+	return int(n)
+}
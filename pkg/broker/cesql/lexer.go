@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenInteger
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	// text is the raw text of the token. Identifiers and keywords are upper
+	// cased, string literals are unquoted and unescaped.
+	text string
+	// raw is the text of identifiers as written in the expression.
+	raw string
+	pos int
+}
+
+// operators are sorted so that longer operators are matched first.
+var operators = []string{"!=", "<>", "<=", ">=", "=", "<", ">", "+", "-", "*", "/", "%"}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case r == '\'' || r == '"':
+			s, next, err := readString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s, pos: i})
+			i = next
+		case r >= '0' && r <= '9':
+			start := i
+			for i < len(runes) && runes[i] >= '0' && runes[i] <= '9' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenInteger, text: string(runes[start:i]), pos: start})
+		case isIdentifierRune(r):
+			start := i
+			for i < len(runes) && (isIdentifierRune(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			raw := string(runes[start:i])
+			tokens = append(tokens, token{kind: tokenIdentifier, text: strings.ToUpper(raw), raw: raw, pos: start})
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(string(runes[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len([]rune(op))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func isIdentifierRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// readString reads a string literal starting at the quote at position start
+// and returns the unescaped string and the position after the closing quote.
+func readString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var sb strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 >= len(runes) {
+				return "", 0, fmt.Errorf("unterminated string literal at position %d", start)
+			}
+			i++
+			if runes[i] == '%' || runes[i] == '_' {
+				// Keep escaped wildcards escaped for LIKE patterns.
+				sb.WriteRune('\\')
+			}
+			sb.WriteRune(runes[i])
+		case quote:
+			return sb.String(), i + 1, nil
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string literal at position %d", start)
+}
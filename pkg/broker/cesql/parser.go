@@ -0,0 +1,332 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cesql
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var keywords = map[string]bool{
+	"AND":    true,
+	"OR":     true,
+	"XOR":    true,
+	"NOT":    true,
+	"LIKE":   true,
+	"IN":     true,
+	"EXISTS": true,
+	"TRUE":   true,
+	"FALSE":  true,
+}
+
+// Parse parses a CESQL expression.
+func Parse(expr string) (Expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseLogic()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+	return e, nil
+}
+
+// parser is a recursive descent parser. The precedence of the operators
+// follows the CESQL grammar, from the lowest to the highest:
+//  1. AND, OR, XOR
+//  2. <, <=, >, >=
+//  3. =, !=, <>
+//  4. +, -
+//  5. *, /, %
+//  6. IN, LIKE
+//  7. unary NOT, unary -
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.kind == tokenIdentifier && t.text == kw
+}
+
+func (p *parser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, fmt.Errorf("expected %s at position %d", what, t.pos)
+	}
+	return t, nil
+}
+
+func (p *parser) parseLogic() (Expression, error) {
+	left, err := p.parseRelational()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") || p.isKeyword("OR") || p.isKeyword("XOR") {
+		op := p.next().text
+		right, err := p.parseRelational()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicExpression{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseRelational() (Expression, error) {
+	left, err := p.parseEquality()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("<", "<=", ">", ">=") {
+		op := p.next().text
+		right, err := p.parseEquality()
+		if err != nil {
+			return nil, err
+		}
+		left = &comparisonExpression{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseEquality() (Expression, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("=", "!=", "<>") {
+		op := p.next().text
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = &equalityExpression{not: op != "=", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (Expression, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next().text
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpression{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Expression, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/", "%") {
+		op := p.next().text
+		right, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithmeticExpression{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parsePostfix() (Expression, error) {
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		not := false
+		if p.isKeyword("NOT") {
+			// NOT is only a postfix operator when followed by LIKE or IN.
+			if t := p.tokens[p.pos+1]; t.kind != tokenIdentifier || (t.text != "LIKE" && t.text != "IN") {
+				return operand, nil
+			}
+			p.next()
+			not = true
+		}
+		switch {
+		case p.isKeyword("LIKE"):
+			p.next()
+			pattern, err := p.expect(tokenString, "a string literal pattern")
+			if err != nil {
+				return nil, err
+			}
+			operand = &likeExpression{not: not, operand: operand, pattern: compileLikePattern(pattern.text)}
+		case p.isKeyword("IN"):
+			p.next()
+			set, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			if len(set) == 0 {
+				return nil, fmt.Errorf("expected at least one value in set at position %d", p.peek().pos)
+			}
+			operand = &inExpression{not: not, operand: operand, set: set}
+		default:
+			return operand, nil
+		}
+	}
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	switch {
+	case p.isKeyword("NOT"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpression{operand: operand}, nil
+	case p.isOperator("-"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negateExpression{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &literalExpression{value: t.text}, nil
+	case tokenInteger:
+		i, err := strconv.ParseInt(t.text, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q at position %d", t.text, t.pos)
+		}
+		return &literalExpression{value: int32(i)}, nil
+	case tokenLeftParen:
+		e, err := p.parseLogic()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRightParen, "')'"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case tokenIdentifier:
+		switch t.text {
+		case "TRUE":
+			return &literalExpression{value: true}, nil
+		case "FALSE":
+			return &literalExpression{value: false}, nil
+		case "EXISTS":
+			name, err := p.expect(tokenIdentifier, "an attribute name")
+			if err != nil {
+				return nil, err
+			}
+			if keywords[name.text] {
+				return nil, fmt.Errorf("expected an attribute name at position %d", name.pos)
+			}
+			return &existsExpression{attribute: name.raw}, nil
+		}
+		if keywords[t.text] {
+			return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+		}
+		if p.peek().kind == tokenLeftParen {
+			return p.parseFunction(t)
+		}
+		return &attributeExpression{attribute: t.raw}, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+func (p *parser) parseFunction(name token) (Expression, error) {
+	f, ok := functions[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.raw, name.pos)
+	}
+	args, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < f.minArgs || (f.maxArgs >= 0 && len(args) > f.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments for function %s at position %d", name.text, name.pos)
+	}
+	return &functionExpression{name: name.text, function: f, args: args}, nil
+}
+
+// parseList parses a parenthesized, comma separated list of expressions.
+func (p *parser) parseList() ([]Expression, error) {
+	if _, err := p.expect(tokenLeftParen, "'('"); err != nil {
+		return nil, err
+	}
+	var list []Expression
+	if p.peek().kind == tokenRightParen {
+		p.next()
+		return list, nil
+	}
+	for {
+		e, err := p.parseLogic()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		t := p.next()
+		if t.kind == tokenRightParen {
+			return list, nil
+		}
+		if t.kind != tokenComma {
+			return nil, fmt.Errorf("expected ',' or ')' at position %d", t.pos)
+		}
+	}
+}
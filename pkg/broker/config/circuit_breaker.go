@@ -0,0 +1,36 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// CircuitBreakerStateLabel is the label of a target's retry subscription that
+// the data plane sets to the state of the target's circuit breaker, so that the
+// control plane can surface it in the Trigger status.
+const CircuitBreakerStateLabel = "circuit-breaker-state"
+
+// CircuitBreakerState is the state of a target's circuit breaker.
+type CircuitBreakerState string
+
+const (
+	// CircuitBreakerClosed means that deliveries to the target are allowed.
+	CircuitBreakerClosed CircuitBreakerState = "closed"
+	// CircuitBreakerOpen means that deliveries to the target are rejected
+	// after too many consecutive failures.
+	CircuitBreakerOpen CircuitBreakerState = "open"
+	// CircuitBreakerHalfOpen means that a probe delivery is allowed to check
+	// whether the target recovered.
+	CircuitBreakerHalfOpen CircuitBreakerState = "half-open"
+)
@@ -48,6 +48,12 @@ type CellTenantMutation interface {
 	SetDecoupleQueue(q *Queue) CellTenantMutation
 	// SetState sets the CellTenant's state.
 	SetState(s State) CellTenantMutation
+	// SetOrderingKeyExtension sets the CloudEvents extension used as the
+	// CellTenant's ordering key.
+	SetOrderingKeyExtension(ext string) CellTenantMutation
+	// SetIngressPolicy sets the policy restricting the publishers of the
+	// CellTenant's events.
+	SetIngressPolicy(p *IngressPolicy) CellTenantMutation
 	// UpsertTargets upserts Targets to the CellTenant.
 	// The targets' namespace, CellTenantType, and CellTenantName will be set to the CellTenant's
 	// value.
@@ -23,6 +23,7 @@ import (
 	"go.opencensus.io/trace"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	messagingv1beta1 "github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 	"go.opencensus.io/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -120,7 +121,8 @@ func CellTenantKeyFromPersistenceString(s string) (*CellTenantKey, error) {
 	}, nil
 }
 
-// MetricsResource generates the Resource object that metrics will be associated with.
+// MetricsResource generates the Resource object that metrics will be associated with. There is no
+// monitored resource type for Channels, so they are reported as Brokers.
 func (k *CellTenantKey) MetricsResource() resource.Resource {
 	return resource.Resource{
 		Type: metricskey.ResourceTypeKnativeBroker,
@@ -143,16 +145,25 @@ func (k *CellTenantKey) CreateEmptyCellTenant() *CellTenant {
 
 // SpanMessagingDestination is the Messaging Destination of requests sent to this CellTenantKey.
 func (k *CellTenantKey) SpanMessagingDestination() string {
-	return kntracing.BrokerMessagingDestination(k.namespacedName())
+	if k.cellTenantType == CellTenantType_CHANNEL {
+		return fmt.Sprintf("channel:%s.%s", k.name, k.namespace)
+	}
+	return kntracing.BrokerMessagingDestination(k.NamespacedName())
 }
 
 // SpanMessagingDestinationAttribute is the Messaging Destination attribute that should be attached
 // to the tracing Span.
 func (k *CellTenantKey) SpanMessagingDestinationAttribute() trace.Attribute {
-	return kntracing.BrokerMessagingDestinationAttribute(k.namespacedName())
+	return trace.StringAttribute(kntracing.MessagingDestinationAttributeName, k.SpanMessagingDestination())
+}
+
+// Type returns the type of the CellTenant.
+func (k *CellTenantKey) Type() CellTenantType {
+	return k.cellTenantType
 }
 
-func (k *CellTenantKey) namespacedName() types.NamespacedName {
+// NamespacedName returns the namespace and name of the CellTenant.
+func (k *CellTenantKey) NamespacedName() types.NamespacedName {
 	return types.NamespacedName{
 		Namespace: k.namespace,
 		Name:      k.name,
@@ -208,6 +219,15 @@ func KeyFromBroker(b *brokerv1beta1.Broker) *CellTenantKey {
 	}
 }
 
+// KeyFromChannel creates a CellTenantKey from a K8s Channel object.
+func KeyFromChannel(c *messagingv1beta1.Channel) *CellTenantKey {
+	return &CellTenantKey{
+		cellTenantType: CellTenantType_CHANNEL,
+		namespace:      c.Namespace,
+		name:           c.Name,
+	}
+}
+
 // TestOnlyBrokerKey returns the key of a broker. This method exists to make tests that need a
 // CellTenantKey, but do not need an actual Broker, easier to write.
 func TestOnlyBrokerKey(namespace, name string) *CellTenantKey {
@@ -218,6 +238,16 @@ func TestOnlyBrokerKey(namespace, name string) *CellTenantKey {
 	}
 }
 
+// TestOnlyChannelKey returns the key of a channel. This method exists to make tests that need a
+// CellTenantKey, but do not need an actual Channel, easier to write.
+func TestOnlyChannelKey(namespace, name string) *CellTenantKey {
+	return &CellTenantKey{
+		cellTenantType: CellTenantType_CHANNEL,
+		namespace:      namespace,
+		name:           name,
+	}
+}
+
 // validateNamespace validates that the given string is a valid K8s namespace.
 func validateNamespace(ns string) error {
 	errs := validation.IsDNS1123Label(ns)
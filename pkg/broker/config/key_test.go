@@ -18,6 +18,8 @@ package config
 
 import (
 	"testing"
+
+	"go.opencensus.io/trace"
 )
 
 func TestCellTenantKeyToFromLowerCase(t *testing.T) {
@@ -49,6 +51,14 @@ func TestBrokerKeyPersistenceString(t *testing.T) {
 			},
 			want: "my-namespace/my-name",
 		},
+		"channel": {
+			key: CellTenantKey{
+				cellTenantType: CellTenantType_CHANNEL,
+				namespace:      "my-namespace",
+				name:           "my-name",
+			},
+			want: "channel/my-namespace/my-name",
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -121,6 +131,18 @@ func TestCellTenantKeyFromPersistenceString(t *testing.T) {
 				name:           "my-name",
 			},
 		},
+		"channel": {
+			s: "/channel/my-ns/my-name",
+			want: &CellTenantKey{
+				cellTenantType: CellTenantType_CHANNEL,
+				namespace:      "my-ns",
+				name:           "my-name",
+			},
+		},
+		"unknown type": {
+			s:       "/queue/my-ns/my-name",
+			wantErr: true,
+		},
 	}
 
 	for n, tc := range testCases {
@@ -139,3 +161,29 @@ func TestCellTenantKeyFromPersistenceString(t *testing.T) {
 		})
 	}
 }
+
+func TestCellTenantKeySpanMessagingDestination(t *testing.T) {
+	testCases := map[string]struct {
+		key  *CellTenantKey
+		want string
+	}{
+		"broker": {
+			key:  TestOnlyBrokerKey("my-ns", "my-name"),
+			want: "broker:my-name.my-ns",
+		},
+		"channel": {
+			key:  TestOnlyChannelKey("my-ns", "my-name"),
+			want: "channel:my-name.my-ns",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.key.SpanMessagingDestination(); got != tc.want {
+				t.Errorf("Unexpected messaging destination, want %q, got %q", tc.want, got)
+			}
+			if want, got := trace.StringAttribute("messaging.destination", tc.want), tc.key.SpanMessagingDestinationAttribute(); got != want {
+				t.Errorf("Unexpected messaging destination attribute, want %v, got %v", want, got)
+			}
+		})
+	}
+}
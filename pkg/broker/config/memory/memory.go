@@ -54,6 +54,18 @@ func (m *cellTenantMutation) SetState(s config.State) config.CellTenantMutation
 	return m
 }
 
+func (m *cellTenantMutation) SetOrderingKeyExtension(ext string) config.CellTenantMutation {
+	m.delete = false
+	m.b.OrderingKeyExtension = ext
+	return m
+}
+
+func (m *cellTenantMutation) SetIngressPolicy(p *config.IngressPolicy) config.CellTenantMutation {
+	m.delete = false
+	m.b.IngressPolicy = p
+	return m
+}
+
 func (m *cellTenantMutation) UpsertTargets(targets ...*config.Target) config.CellTenantMutation {
 	m.delete = false
 	if m.b.Targets == nil {
@@ -114,19 +126,17 @@ func (m *memoryTargets) MutateCellTenant(key *config.CellTenantKey, mutate func(
 	defer m.mux.Unlock()
 
 	b := key.CreateEmptyCellTenant()
-	var newVal *config.TargetsConfig
-	val := m.Load()
-	if val != nil {
-		// Don't modify the existing copy because it
-		// will break the atomic store/load.
-		newVal = proto.Clone(val).(*config.TargetsConfig)
-	} else {
-		newVal = &config.TargetsConfig{}
-	}
-
-	if newVal.CellTenants != nil {
-		if existing, ok := newVal.CellTenants[key.PersistenceString()]; ok {
-			b = existing
+	newVal := &config.TargetsConfig{}
+	if val := m.Load(); val != nil && val.CellTenants != nil {
+		// Don't modify the existing copy because it will break the atomic
+		// store/load. Only the mutated CellTenant is cloned, the others are
+		// shared with the existing copy and never modified.
+		newVal.CellTenants = make(map[string]*config.CellTenant, len(val.CellTenants)+1)
+		for k, v := range val.CellTenants {
+			newVal.CellTenants[k] = v
+		}
+		if existing, ok := val.CellTenants[key.PersistenceString()]; ok {
+			b = proto.Clone(existing).(*config.CellTenant)
 		}
 	}
 
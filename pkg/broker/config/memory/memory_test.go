@@ -96,6 +96,22 @@ func TestMutateBroker(t *testing.T) {
 		assertBroker(t, wantBroker, targets)
 	})
 
+	t.Run("update broker ordering key extension", func(t *testing.T) {
+		wantBroker.OrderingKeyExtension = "partitionkey"
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetOrderingKeyExtension("partitionkey")
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
+	t.Run("update broker ingress policy", func(t *testing.T) {
+		wantBroker.IngressPolicy = &config.IngressPolicy{AllowedServiceAccounts: []string{"ns/publisher"}}
+		targets.MutateCellTenant(wantBroker.Key(), func(m config.CellTenantMutation) {
+			m.SetIngressPolicy(&config.IngressPolicy{AllowedServiceAccounts: []string{"ns/publisher"}})
+		})
+		assertBroker(t, wantBroker, targets)
+	})
+
 	t1 := &config.Target{
 		Id:             "uid-1",
 		Address:        "consumer1.example.com",
@@ -176,6 +192,8 @@ func TestMutateBroker(t *testing.T) {
 			m.Delete()
 			// Then make some changes which should "recreate" the broker.
 			m.SetID("b-uid").SetAddress("external.broker.example.com").SetState(config.State_READY)
+			m.SetOrderingKeyExtension("partitionkey")
+			m.SetIngressPolicy(&config.IngressPolicy{AllowedServiceAccounts: []string{"ns/publisher"}})
 			m.SetDecoupleQueue(&config.Queue{
 				Topic:        "topic",
 				Subscription: "sub",
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Option is the option to load targets.
+type Option func(*Targets)
+
+// WithAddress is the option to load targets from the server at the given
+// address.
+func WithAddress(address string) Option {
+	return func(t *Targets) {
+		t.address = address
+	}
+}
+
+// WithBrokerCell is the option to load the targets of the given BrokerCell.
+func WithBrokerCell(brokerCell types.NamespacedName) Option {
+	return func(t *Targets) {
+		t.brokerCell = brokerCell
+	}
+}
+
+// WithNotifyChan is the option to notify the given channel
+// when the config cache was updated.
+func WithNotifyChan(ch chan<- struct{}) Option {
+	return func(t *Targets) {
+		t.notifyChan = ch
+	}
+}
+
+// WithSyncTimeout is the option to set how long to wait for the initial
+// targets config from the server.
+func WithSyncTimeout(timeout time.Duration) Option {
+	return func(t *Targets) {
+		t.syncTimeout = timeout
+	}
+}
+
+// WithDialOptions is the option to add options to dial the server.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(t *Targets) {
+		t.dialOpts = append(t.dialOpts, opts...)
+	}
+}
+
+// WithTokenFile is the option to authenticate to the server with the token in
+// the given file, e.g. a projected service account token. The file is read
+// for every watch so that the rotated tokens are used. No token is sent if the
+// path is empty.
+func WithTokenFile(path string) Option {
+	if path == "" {
+		return func(*Targets) {}
+	}
+	return WithDialOptions(grpc.WithPerRPCCredentials(tokenFile(path)))
+}
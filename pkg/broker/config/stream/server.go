@@ -0,0 +1,316 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/tokenreview"
+)
+
+// TokenAudience is the audience of the service account tokens the watchers
+// authenticate with.
+const TokenAudience = "targets-config-server"
+
+// Server implements config.TargetsConfigServiceServer. It serves the targets
+// config of each BrokerCell, as last set by Update, to the watchers of the
+// BrokerCell.
+type Server struct {
+	config.UnimplementedTargetsConfigServiceServer
+
+	// reviewer authenticates the watchers, which must use a token of
+	// serviceAccountName in the namespace of the watched BrokerCell. If nil,
+	// watchers are not authenticated.
+	reviewer           *tokenreview.Reviewer
+	serviceAccountName string
+
+	mu    sync.Mutex
+	cells map[types.NamespacedName]*cell
+	// watchers is the number of watchers of each BrokerCell, so that the
+	// cells that are neither updated nor watched are removed.
+	watchers map[types.NamespacedName]int
+	// lastVersion is the version of the last update of any BrokerCell. It
+	// starts from the time the server is created so that the versions are
+	// not reused after a restart.
+	lastVersion int64
+}
+
+// cell is the targets config of a BrokerCell.
+type cell struct {
+	// version is 0 until the first update of the BrokerCell.
+	version int64
+	// tenants are the CellTenants keyed by their persistence string. The
+	// map and its values are never modified once stored.
+	tenants map[string]*config.CellTenant
+	// updated is closed when the cell is updated.
+	updated chan struct{}
+}
+
+var _ config.TargetsConfigServiceServer = (*Server)(nil)
+
+// ServerOption is the option to create a Server.
+type ServerOption func(*Server)
+
+// WithServiceAccountAuth is the option to only serve the watchers that
+// authenticate with a token of the given service account, issued for
+// TokenAudience, in the namespace of the watched BrokerCell.
+func WithServiceAccountAuth(reviewer *tokenreview.Reviewer, serviceAccountName string) ServerOption {
+	return func(s *Server) {
+		s.reviewer = reviewer
+		s.serviceAccountName = serviceAccountName
+	}
+}
+
+// NewServer creates a new Server.
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		cells:       make(map[types.NamespacedName]*cell),
+		watchers:    make(map[types.NamespacedName]int),
+		lastVersion: time.Now().UnixNano(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Update sets the targets config of the BrokerCell. Watchers are only
+// notified if the targets config changed.
+func (s *Server) Update(brokerCell types.NamespacedName, targets config.ReadonlyTargets) {
+	tenants := make(map[string]*config.CellTenant)
+	targets.RangeCellTenants(func(t *config.CellTenant) bool {
+		tenants[t.Key().PersistenceString()] = proto.Clone(t).(*config.CellTenant)
+		return true
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.getLocked(brokerCell)
+	if c.version != 0 && equal(c.tenants, tenants) {
+		return
+	}
+	s.lastVersion++
+	updated := c.updated
+	s.cells[brokerCell] = &cell{
+		version: s.lastVersion,
+		tenants: tenants,
+		updated: make(chan struct{}),
+	}
+	close(updated)
+}
+
+// Delete forgets the targets config of the deleted BrokerCell.
+func (s *Server) Delete(brokerCell types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.cells[brokerCell]
+	if !ok {
+		return
+	}
+	if s.watchers[brokerCell] > 0 {
+		// The remaining watchers wait for the BrokerCell to be created again.
+		s.cells[brokerCell] = &cell{updated: make(chan struct{})}
+	} else {
+		delete(s.cells, brokerCell)
+	}
+	close(c.updated)
+}
+
+// Serve serves the targets config on the given port until the context is
+// done.
+func (s *Server) Serve(ctx context.Context, port int) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	return s.serve(ctx, lis)
+}
+
+func (s *Server) serve(ctx context.Context, lis net.Listener) error {
+	srv := grpc.NewServer()
+	config.RegisterTargetsConfigServiceServer(srv, s)
+	go func() {
+		<-ctx.Done()
+		srv.Stop()
+	}()
+	return srv.Serve(lis)
+}
+
+// Watch implements config.TargetsConfigServiceServer. It sends a snapshot of
+// the targets config of the BrokerCell, unless the client already has its
+// current version, and then the delta of each update.
+func (s *Server) Watch(req *config.WatchTargetsRequest, stream config.TargetsConfigService_WatchServer) error {
+	brokerCell := types.NamespacedName{Namespace: req.GetBrokercellNamespace(), Name: req.GetBrokercellName()}
+	if err := s.authorize(stream, brokerCell); err != nil {
+		return err
+	}
+	s.addWatcher(brokerCell)
+	defer s.removeWatcher(brokerCell)
+
+	sentVersion := req.GetVersion()
+	var sent map[string]*config.CellTenant
+	for {
+		c := s.get(brokerCell)
+		// Nothing is sent before the first update so that clients don't
+		// discard their targets config when the server restarts.
+		if c.version != 0 {
+			switch {
+			case sent == nil && c.version == sentVersion:
+				sent = c.tenants
+			case sent == nil:
+				if err := stream.Send(snapshot(c)); err != nil {
+					return err
+				}
+				sent, sentVersion = c.tenants, c.version
+			case c.version != sentVersion:
+				if err := stream.Send(delta(sent, c)); err != nil {
+					return err
+				}
+				sent, sentVersion = c.tenants, c.version
+			}
+		}
+		select {
+		case <-c.updated:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// authorize returns an error unless the watcher authenticated with a token of
+// the service account of the data plane of the BrokerCell.
+func (s *Server) authorize(stream grpc.ServerStream, brokerCell types.NamespacedName) error {
+	if s.reviewer == nil {
+		return nil
+	}
+	ctx := stream.Context()
+	md, _ := metadata.FromIncomingContext(ctx)
+	var token string
+	if auth := md.Get("authorization"); len(auth) == 1 && strings.HasPrefix(auth[0], "Bearer ") {
+		token = strings.TrimPrefix(auth[0], "Bearer ")
+	}
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	username, err := s.reviewer.Review(ctx, token, TokenAudience)
+	if errors.Is(err, tokenreview.ErrTooManyReviews) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if username == "" {
+		return status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	if namespace, name, ok := tokenreview.ServiceAccount(username); !ok || namespace != brokerCell.Namespace || name != s.serviceAccountName {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed to watch brokercell %s", username, brokerCell)
+	}
+	return nil
+}
+
+func (s *Server) addWatcher(brokerCell types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers[brokerCell]++
+}
+
+func (s *Server) removeWatcher(brokerCell types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers[brokerCell]--
+	if s.watchers[brokerCell] > 0 {
+		return
+	}
+	delete(s.watchers, brokerCell)
+	// Forget the BrokerCells that were watched but never updated, e.g.
+	// because they don't exist.
+	if c, ok := s.cells[brokerCell]; ok && c.version == 0 {
+		delete(s.cells, brokerCell)
+	}
+}
+
+func (s *Server) get(brokerCell types.NamespacedName) *cell {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.getLocked(brokerCell)
+}
+
+func (s *Server) getLocked(brokerCell types.NamespacedName) *cell {
+	c, ok := s.cells[brokerCell]
+	if !ok {
+		c = &cell{updated: make(chan struct{})}
+		s.cells[brokerCell] = c
+	}
+	return c
+}
+
+// snapshot returns an update which replaces the whole targets config with the
+// cell's.
+func snapshot(c *cell) *config.TargetsConfigUpdate {
+	return &config.TargetsConfigUpdate{
+		Version:             c.version,
+		Snapshot:            true,
+		UpsertedCellTenants: c.tenants,
+	}
+}
+
+// delta returns an update which changes the sent CellTenants to the cell's.
+func delta(sent map[string]*config.CellTenant, c *cell) *config.TargetsConfigUpdate {
+	update := &config.TargetsConfigUpdate{
+		Version:             c.version,
+		UpsertedCellTenants: make(map[string]*config.CellTenant),
+	}
+	for k, t := range c.tenants {
+		if old, ok := sent[k]; !ok || !proto.Equal(old, t) {
+			update.UpsertedCellTenants[k] = t
+		}
+	}
+	for k := range sent {
+		if _, ok := c.tenants[k]; !ok {
+			update.DeletedCellTenants = append(update.DeletedCellTenants, k)
+		}
+	}
+	sort.Strings(update.DeletedCellTenants)
+	return update
+}
+
+func equal(a, b map[string]*config.CellTenant) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, t := range a {
+		if other, ok := b[k]; !ok || !proto.Equal(t, other) {
+			return false
+		}
+	}
+	return true
+}
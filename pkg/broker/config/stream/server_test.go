@@ -0,0 +1,371 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/tokenreview"
+)
+
+var testBrokerCell = types.NamespacedName{Namespace: "events-system", Name: "default"}
+
+func broker(namespace, name, address string) *config.CellTenant {
+	return &config.CellTenant{
+		Id:        namespace + "-" + name,
+		Type:      config.CellTenantType_BROKER,
+		Name:      name,
+		Namespace: namespace,
+		Address:   address,
+		DecoupleQueue: &config.Queue{
+			Topic:        "topic-" + name,
+			Subscription: "sub-" + name,
+			State:        config.State_READY,
+		},
+		Targets: map[string]*config.Target{
+			"trigger": {
+				Id:             namespace + "-" + name + "-trigger",
+				Name:           "trigger",
+				Namespace:      namespace,
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: name,
+				Address:        "subscriber.example.com",
+				State:          config.State_READY,
+			},
+		},
+		State: config.State_READY,
+	}
+}
+
+func targetsOf(tenants ...*config.CellTenant) config.Targets {
+	tc := &config.TargetsConfig{CellTenants: make(map[string]*config.CellTenant)}
+	for _, t := range tenants {
+		tc.CellTenants[t.Key().PersistenceString()] = t
+	}
+	return memory.NewTargets(tc)
+}
+
+// startServer serves the server on a local port and returns a client of it.
+func startServer(ctx context.Context, t *testing.T, s *Server) (string, config.TargetsConfigServiceClient) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.serve(ctx, lis)
+	conn, err := grpc.DialContext(ctx, lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return lis.Addr().String(), config.NewTargetsConfigServiceClient(conn)
+}
+
+func recv(t *testing.T, stream config.TargetsConfigService_WatchClient) *config.TargetsConfigUpdate {
+	t.Helper()
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive update: %v", err)
+	}
+	return update
+}
+
+func TestServerWatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s := NewServer()
+	_, client := startServer(ctx, t, s)
+
+	broker1 := broker("ns1", "broker1", "broker1.example.com")
+	broker2 := broker("ns2", "broker2", "broker2.example.com")
+	broker3 := broker("ns3", "broker3", "broker3.example.com")
+
+	// Watching before the first update waits for it.
+	stream, err := client.Watch(ctx, &config.WatchTargetsRequest{
+		BrokercellNamespace: testBrokerCell.Namespace,
+		BrokercellName:      testBrokerCell.Name,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Update(types.NamespacedName{Namespace: "events-system", Name: "other"}, targetsOf(broker3))
+	s.Update(testBrokerCell, targetsOf(broker1, broker2))
+
+	snapshot := recv(t, stream)
+	wantSnapshot := &config.TargetsConfigUpdate{
+		Version:  snapshot.GetVersion(),
+		Snapshot: true,
+		UpsertedCellTenants: map[string]*config.CellTenant{
+			"ns1/broker1": broker1,
+			"ns2/broker2": broker2,
+		},
+	}
+	if diff := cmp.Diff(wantSnapshot, snapshot, protocmp.Transform()); diff != "" {
+		t.Errorf("Unexpected snapshot (-want,+got): %v", diff)
+	}
+
+	// Updates without changes are not sent.
+	s.Update(testBrokerCell, targetsOf(broker1, broker2))
+	changedBroker1 := proto.Clone(broker1).(*config.CellTenant)
+	changedBroker1.Address = "changed.example.com"
+	s.Update(testBrokerCell, targetsOf(changedBroker1, broker3))
+
+	delta := recv(t, stream)
+	if delta.GetVersion() <= snapshot.GetVersion() {
+		t.Errorf("Delta version %d is not after snapshot version %d", delta.GetVersion(), snapshot.GetVersion())
+	}
+	wantDelta := &config.TargetsConfigUpdate{
+		Version: delta.GetVersion(),
+		UpsertedCellTenants: map[string]*config.CellTenant{
+			"ns1/broker1": changedBroker1,
+			"ns3/broker3": broker3,
+		},
+		DeletedCellTenants: []string{"ns2/broker2"},
+	}
+	if diff := cmp.Diff(wantDelta, delta, protocmp.Transform()); diff != "" {
+		t.Errorf("Unexpected delta (-want,+got): %v", diff)
+	}
+}
+
+// fakeWatchServer is a TargetsConfigService_WatchServer which signals when the
+// server starts waiting for updates.
+type fakeWatchServer struct {
+	grpc.ServerStream
+	ctx     context.Context
+	updates chan *config.TargetsConfigUpdate
+	waiting chan struct{}
+	once    sync.Once
+}
+
+func (f *fakeWatchServer) Send(update *config.TargetsConfigUpdate) error {
+	f.updates <- update
+	return nil
+}
+
+func (f *fakeWatchServer) Context() context.Context {
+	f.once.Do(func() { close(f.waiting) })
+	return f.ctx
+}
+
+func TestServerWatchCurrentVersion(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s := NewServer()
+
+	broker1 := broker("ns1", "broker1", "broker1.example.com")
+	broker2 := broker("ns2", "broker2", "broker2.example.com")
+	s.Update(testBrokerCell, targetsOf(broker1))
+	version := s.get(testBrokerCell).version
+
+	// A client which already has the current version only receives the deltas.
+	stream := &fakeWatchServer{
+		ctx:     ctx,
+		updates: make(chan *config.TargetsConfigUpdate, 1),
+		waiting: make(chan struct{}),
+	}
+	go s.Watch(&config.WatchTargetsRequest{
+		BrokercellNamespace: testBrokerCell.Namespace,
+		BrokercellName:      testBrokerCell.Name,
+		Version:             version,
+	}, stream)
+	<-stream.waiting
+	s.Update(testBrokerCell, targetsOf(broker1, broker2))
+
+	want := &config.TargetsConfigUpdate{
+		Version: version + 1,
+		UpsertedCellTenants: map[string]*config.CellTenant{
+			"ns2/broker2": broker2,
+		},
+	}
+	select {
+	case got := <-stream.updates:
+		if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("Unexpected update (-want,+got): %v", diff)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the update")
+	}
+}
+
+func TestServerWatchOutdatedVersion(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s := NewServer()
+	_, client := startServer(ctx, t, s)
+
+	broker1 := broker("ns1", "broker1", "broker1.example.com")
+	s.Update(testBrokerCell, targetsOf(broker1))
+	version := s.get(testBrokerCell).version
+
+	// A client with another version, e.g. from before a restart of the
+	// server, receives a snapshot.
+	stream, err := client.Watch(ctx, &config.WatchTargetsRequest{
+		BrokercellNamespace: testBrokerCell.Namespace,
+		BrokercellName:      testBrokerCell.Name,
+		Version:             version - 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &config.TargetsConfigUpdate{
+		Version:  version,
+		Snapshot: true,
+		UpsertedCellTenants: map[string]*config.CellTenant{
+			"ns1/broker1": broker1,
+		},
+	}
+	if diff := cmp.Diff(want, recv(t, stream), protocmp.Transform()); diff != "" {
+		t.Errorf("Unexpected update (-want,+got): %v", diff)
+	}
+}
+
+func TestServerWatchAuthorization(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		tr := action.(clientgotesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if len(tr.Spec.Audiences) != 1 || tr.Spec.Audiences[0] != TokenAudience {
+			t.Errorf("Unexpected TokenReview audiences: %v", tr.Spec.Audiences)
+		}
+		users := map[string]string{
+			"broker-token":       "system:serviceaccount:events-system:broker",
+			"other-ns-token":     "system:serviceaccount:other:broker",
+			"other-broker-token": "system:serviceaccount:events-system:controller",
+		}
+		if user, ok := users[tr.Spec.Token]; ok {
+			tr.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: user},
+			}
+		}
+		return true, tr, nil
+	})
+	s := NewServer(WithServiceAccountAuth(tokenreview.NewReviewer(client), "broker"))
+	address, _ := startServer(ctx, t, s)
+	s.Update(testBrokerCell, targetsOf(broker("ns1", "broker1", "broker1.example.com")))
+
+	tests := []struct {
+		name  string
+		token string
+		want  codes.Code
+	}{{
+		name: "missing token",
+		want: codes.Unauthenticated,
+	}, {
+		name:  "invalid token",
+		token: "invalid-token",
+		want:  codes.Unauthenticated,
+	}, {
+		name:  "service account of another namespace",
+		token: "other-ns-token",
+		want:  codes.PermissionDenied,
+	}, {
+		name:  "other service account",
+		token: "other-broker-token",
+		want:  codes.PermissionDenied,
+	}, {
+		name:  "data plane service account",
+		token: "broker-token",
+		want:  codes.OK,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := []grpc.DialOption{grpc.WithInsecure()}
+			if test.token != "" {
+				path := filepath.Join(t.TempDir(), "token")
+				if err := ioutil.WriteFile(path, []byte(test.token+"\n"), 0600); err != nil {
+					t.Fatal(err)
+				}
+				opts = append(opts, grpc.WithPerRPCCredentials(tokenFile(path)))
+			}
+			conn, err := grpc.DialContext(ctx, address, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			stream, err := config.NewTargetsConfigServiceClient(conn).Watch(ctx, &config.WatchTargetsRequest{
+				BrokercellNamespace: testBrokerCell.Namespace,
+				BrokercellName:      testBrokerCell.Name,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = stream.Recv()
+			if got := status.Code(err); got != test.want {
+				t.Errorf("Recv() = %v, want code %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestServerDelete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s := NewServer()
+
+	s.Update(testBrokerCell, targetsOf(broker("ns1", "broker1", "broker1.example.com")))
+	s.Delete(testBrokerCell)
+	if len(s.cells) != 0 {
+		t.Errorf("Unexpected cells after delete: %v", s.cells)
+	}
+
+	// The cells which are watched but never updated are forgotten once the
+	// watchers are gone.
+	wctx, wcancel := context.WithCancel(ctx)
+	stream := &fakeWatchServer{
+		ctx:     wctx,
+		updates: make(chan *config.TargetsConfigUpdate, 1),
+		waiting: make(chan struct{}),
+	}
+	done := make(chan struct{})
+	go func() {
+		s.Watch(&config.WatchTargetsRequest{
+			BrokercellNamespace: testBrokerCell.Namespace,
+			BrokercellName:      testBrokerCell.Name,
+		}, stream)
+		close(done)
+	}()
+	<-stream.waiting
+	wcancel()
+	<-done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cells) != 0 || len(s.watchers) != 0 {
+		t.Errorf("Unexpected cells %v and watchers %v once unwatched", s.cells, s.watchers)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/volume"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	defaultSyncTimeout = 30 * time.Second
+
+	minWatchBackoff = time.Second
+	maxWatchBackoff = 30 * time.Second
+
+	// maxRecvMsgSize is the largest update received from the server. The
+	// snapshot of the targets config is sent as a single update, which can be
+	// much larger than the 4 MiB gRPC default.
+	maxRecvMsgSize = 256 << 20
+)
+
+// Targets implements config.ReadonlyTargets with data
+// streamed from a targets config server.
+// It keeps watching the server and applies every update
+// to the in memory cache.
+type Targets struct {
+	config.CachedTargets
+	address     string
+	brokerCell  types.NamespacedName
+	dialOpts    []grpc.DialOption
+	syncTimeout time.Duration
+	notifyChan  chan<- struct{}
+
+	// version and tenants are the last applied update. They are only
+	// accessed by the watch goroutine.
+	version int64
+	tenants map[string]*config.CellTenant
+}
+
+var _ config.ReadonlyTargets = (*Targets)(nil)
+
+// NewTargetsFromServer initializes the targets config from a targets config
+// server. It blocks until the initial targets config is received.
+func NewTargetsFromServer(ctx context.Context, opts ...Option) (config.ReadonlyTargets, error) {
+	t := &Targets{
+		CachedTargets: config.CachedTargets{},
+		dialOpts: []grpc.DialOption{
+			grpc.WithInsecure(),
+			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxRecvMsgSize)),
+		},
+		syncTimeout: defaultSyncTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if t.address == "" {
+		return nil, errors.New("targets config server address is empty")
+	}
+	conn, err := grpc.DialContext(ctx, t.address, t.dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial targets config server: %w", err)
+	}
+
+	synced := make(chan struct{})
+	go func() {
+		defer conn.Close()
+		t.watch(ctx, config.NewTargetsConfigServiceClient(conn), synced)
+	}()
+
+	select {
+	case <-synced:
+		return t, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(t.syncTimeout):
+		return nil, fmt.Errorf("timed out waiting for the targets config of %v from %s", t.brokerCell, t.address)
+	}
+}
+
+// NewTargets initializes the targets config from a targets config server if
+// its address is set, or from a file otherwise.
+func NewTargets(ctx context.Context, serverOpts []Option, volumeOpts []volume.Option) (config.ReadonlyTargets, error) {
+	t := &Targets{}
+	for _, opt := range serverOpts {
+		opt(t)
+	}
+	if t.address == "" {
+		return volume.NewTargetsFromFile(volumeOpts...)
+	}
+	return NewTargetsFromServer(ctx, serverOpts...)
+}
+
+// watch watches the targets config until the context is done. It closes
+// synced once the initial targets config is applied.
+func (t *Targets) watch(ctx context.Context, client config.TargetsConfigServiceClient, synced chan<- struct{}) {
+	backoff := minWatchBackoff
+	for {
+		err := t.watchOnce(ctx, client, synced)
+		if t.version != 0 {
+			// Updates were received, the server is healthy.
+			backoff = minWatchBackoff
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		logging.FromContext(ctx).Warn("Watching targets config failed, retrying",
+			zap.Error(err), zap.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxWatchBackoff {
+			backoff = maxWatchBackoff
+		}
+	}
+}
+
+func (t *Targets) watchOnce(ctx context.Context, client config.TargetsConfigServiceClient, synced chan<- struct{}) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := client.Watch(ctx, &config.WatchTargetsRequest{
+		BrokercellNamespace: t.brokerCell.Namespace,
+		BrokercellName:      t.brokerCell.Name,
+		Version:             t.version,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		update, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		initial := t.version == 0
+		t.apply(update)
+		if initial {
+			close(synced)
+		} else if t.notifyChan != nil {
+			t.notifyChan <- struct{}{}
+		}
+	}
+}
+
+// apply applies the update to the in memory cache.
+func (t *Targets) apply(update *config.TargetsConfigUpdate) {
+	tenants := make(map[string]*config.CellTenant, len(t.tenants)+len(update.GetUpsertedCellTenants()))
+	if !update.GetSnapshot() {
+		for k, v := range t.tenants {
+			tenants[k] = v
+		}
+		for _, k := range update.GetDeletedCellTenants() {
+			delete(tenants, k)
+		}
+	}
+	for k, v := range update.GetUpsertedCellTenants() {
+		tenants[k] = v
+	}
+	t.tenants = tenants
+	t.version = update.GetVersion()
+	t.Store(&config.TargetsConfig{CellTenants: tenants})
+}
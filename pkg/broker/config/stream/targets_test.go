@@ -0,0 +1,154 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/volume"
+)
+
+func assertCellTenant(t *testing.T, targets config.ReadonlyTargets, key string, want *config.CellTenant) {
+	t.Helper()
+	k, err := config.CellTenantKeyFromPersistenceString("/" + key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := targets.GetCellTenantByKey(k)
+	if want == nil {
+		if ok {
+			t.Errorf("CellTenant %s exists, want deleted", key)
+		}
+		return
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Unexpected CellTenant %s (-want,+got): %v", key, diff)
+	}
+}
+
+func TestTargetsFromServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s := NewServer()
+	address, _ := startServer(ctx, t, s)
+
+	broker1 := broker("ns1", "broker1", "broker1.example.com")
+	broker2 := broker("ns2", "broker2", "broker2.example.com")
+	s.Update(testBrokerCell, targetsOf(broker1, broker2))
+
+	ch := make(chan struct{}, 1)
+	targets, err := NewTargetsFromServer(ctx,
+		WithAddress(address),
+		WithBrokerCell(testBrokerCell),
+		WithNotifyChan(ch),
+	)
+	if err != nil {
+		t.Fatalf("NewTargetsFromServer() failed: %v", err)
+	}
+	assertCellTenant(t, targets, "ns1/broker1", broker1)
+	assertCellTenant(t, targets, "ns2/broker2", broker2)
+
+	changedBroker1 := proto.Clone(broker1).(*config.CellTenant)
+	changedBroker1.Address = "changed.example.com"
+	s.Update(testBrokerCell, targetsOf(changedBroker1))
+	select {
+	case <-ch:
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the targets update")
+	}
+	assertCellTenant(t, targets, "ns1/broker1", changedBroker1)
+	assertCellTenant(t, targets, "ns2/broker2", nil)
+}
+
+func TestTargetsFromServerLargeSnapshot(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s := NewServer()
+	address, _ := startServer(ctx, t, s)
+
+	// The snapshot is larger than the default gRPC message size limit of 4 MiB.
+	var brokers []*config.CellTenant
+	for i := 0; i < 5; i++ {
+		b := broker("ns", fmt.Sprintf("broker%d", i), "broker.example.com")
+		b.Targets["trigger"].Address = strings.Repeat("a", 1<<20)
+		brokers = append(brokers, b)
+	}
+	s.Update(testBrokerCell, targetsOf(brokers...))
+
+	targets, err := NewTargetsFromServer(ctx,
+		WithAddress(address),
+		WithBrokerCell(testBrokerCell),
+	)
+	if err != nil {
+		t.Fatalf("NewTargetsFromServer() failed: %v", err)
+	}
+	for i, b := range brokers {
+		assertCellTenant(t, targets, fmt.Sprintf("ns/broker%d", i), b)
+	}
+}
+
+func TestTargetsFromServerSyncTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	address, _ := startServer(ctx, t, NewServer())
+
+	// The server never receives the targets config of the BrokerCell.
+	if _, err := NewTargetsFromServer(ctx,
+		WithAddress(address),
+		WithBrokerCell(testBrokerCell),
+		WithSyncTimeout(100*time.Millisecond),
+	); err == nil {
+		t.Error("NewTargetsFromServer() succeeded, want error")
+	}
+}
+
+func TestNewTargetsFromFile(t *testing.T) {
+	broker1 := broker("ns1", "broker1", "broker1.example.com")
+	b, err := proto.Marshal(&config.TargetsConfig{
+		CellTenants: map[string]*config.CellTenant{"ns1/broker1": broker1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "targets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	// Without an address, the targets config is loaded from the file.
+	targets, err := NewTargets(context.Background(), []Option{WithBrokerCell(testBrokerCell)}, []volume.Option{volume.WithPath(f.Name())})
+	if err != nil {
+		t.Fatalf("NewTargets() failed: %v", err)
+	}
+	assertCellTenant(t, targets, "ns1/broker1", broker1)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tokenFile implements credentials.PerRPCCredentials with the bearer token in
+// a file.
+type tokenFile string
+
+var _ credentials.PerRPCCredentials = tokenFile("")
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (f tokenFile) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token, err := ioutil.ReadFile(string(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	return map[string]string{"authorization": "Bearer " + strings.TrimSpace(string(token))}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. The
+// server doesn't use TLS, so the token is sent in plaintext over the
+// in-cluster connection. The controller only serves the targets config once
+// this is acknowledged with BROKER_CELL_TARGETS_CONFIG_SERVER_INSECURE.
+func (tokenFile) RequireTransportSecurity() bool {
+	return false
+}
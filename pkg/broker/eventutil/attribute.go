@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// GetAttribute returns the value of the context attribute or extension of the
+// event with the given name. Context attributes are returned in their
+// canonical string representation, extensions keep their integer or boolean
+// type. Optional attributes which are not set are reported as missing.
+func GetAttribute(e *event.Event, name string) (interface{}, bool) {
+	name = strings.ToLower(name)
+	var v string
+	switch name {
+	case "specversion":
+		v = e.SpecVersion()
+	case "id":
+		v = e.ID()
+	case "source":
+		v = e.Source()
+	case "type":
+		v = e.Type()
+	case "subject":
+		v = e.Subject()
+	case "datacontenttype":
+		v = e.DataContentType()
+	case "dataschema":
+		v = e.DataSchema()
+	case "time":
+		if !e.Time().IsZero() {
+			v = types.FormatTime(e.Time())
+		}
+	default:
+		ext, ok := e.Extensions()[name]
+		if !ok {
+			return nil, false
+		}
+		switch ext := ext.(type) {
+		case int32, bool:
+			return ext, true
+		}
+		s, err := types.Format(ext)
+		if err != nil {
+			return nil, false
+		}
+		return s, true
+	}
+	if v == "" {
+		return nil, false
+	}
+	return v, true
+}
+
+// GetAttributeString returns the value of the context attribute or extension
+// of the event with the given name in its canonical string representation.
+func GetAttributeString(e *event.Event, name string) (string, bool) {
+	v, ok := GetAttribute(e, name)
+	if !ok {
+		return "", false
+	}
+	s, err := types.Format(v)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestGetAttribute(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetTime(time.Date(2021, time.January, 2, 3, 4, 5, 0, time.UTC))
+	e.SetExtension("ext", "value")
+	e.SetExtension("num", 10)
+	e.SetExtension("flag", true)
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		wantString string
+		wantOK     bool
+	}{
+		{name: "specversion", want: "1.0", wantString: "1.0", wantOK: true},
+		{name: "id", want: "id", wantString: "id", wantOK: true},
+		{name: "source", want: "source", wantString: "source", wantOK: true},
+		{name: "TYPE", want: "type", wantString: "type", wantOK: true},
+		{name: "time", want: "2021-01-02T03:04:05Z", wantString: "2021-01-02T03:04:05Z", wantOK: true},
+		{name: "subject"},
+		{name: "dataschema"},
+		{name: "ext", want: "value", wantString: "value", wantOK: true},
+		{name: "num", want: int32(10), wantString: "10", wantOK: true},
+		{name: "flag", want: true, wantString: "true", wantOK: true},
+		{name: "missing"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := GetAttribute(&e, tc.name)
+			if ok != tc.wantOK || got != tc.want {
+				t.Errorf("GetAttribute got (%#v, %v), want (%#v, %v)", got, ok, tc.want, tc.wantOK)
+			}
+			gotString, ok := GetAttributeString(&e, tc.name)
+			if ok != tc.wantOK || gotString != tc.wantString {
+				t.Errorf("GetAttributeString got (%q, %v), want (%q, %v)", gotString, ok, tc.wantString, tc.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// OrderingKey returns the Pub/Sub ordering key of an event, which is the value
+// of the given extension. It returns an empty string if ordering is disabled,
+// i.e. the extension name is empty, or if the event doesn't have the extension.
+func OrderingKey(e *event.Event, extension string) string {
+	if extension == "" {
+		return ""
+	}
+	key, _ := GetAttributeString(e, extension)
+	return key
+}
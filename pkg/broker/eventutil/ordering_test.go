@@ -0,0 +1,58 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+func TestOrderingKey(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetExtension("partitionkey", "order-1")
+	e.SetExtension("priority", 3)
+
+	tests := []struct {
+		name      string
+		extension string
+		want      string
+	}{{
+		name: "ordering disabled",
+	}, {
+		name:      "string extension",
+		extension: "partitionkey",
+		want:      "order-1",
+	}, {
+		name:      "integer extension",
+		extension: "priority",
+		want:      "3",
+	}, {
+		name:      "missing extension",
+		extension: "orderid",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := OrderingKey(&e, tc.extension); got != tc.want {
+				t.Errorf("OrderingKey got=%q, want=%q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+const (
+	// circuitBreakerRecordTimeout bounds the time spent recording the state of
+	// a circuit breaker.
+	circuitBreakerRecordTimeout = 10 * time.Second
+
+	// circuitBreakerRecordInterval is the minimum interval between the
+	// records of the state of a target's circuit breaker, which bounds the
+	// subscription updates of each replica.
+	circuitBreakerRecordInterval = 30 * time.Second
+)
+
+// recordCircuitBreakerState returns a function that records the circuit
+// breaker state of a target as a label of its retry subscription, from which
+// the trigger reconciler reads it. Only the retry pool records the state: the
+// retry replicas are the ones that keep delivering to a failing target, while
+// the fanout replicas send its events to the retry topic.
+func recordCircuitBreakerState(pubsubClient *pubsub.Client) deliver.CircuitBreakerStateFunc {
+	return func(ctx context.Context, target *config.Target, state config.CircuitBreakerState) {
+		if target.RetryQueue == nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, circuitBreakerRecordTimeout)
+		defer cancel()
+		logger := logging.FromContext(ctx).With(zap.Stringer("target", target.Key()), zap.String("state", string(state)))
+
+		sub := pubsubClient.Subscription(target.RetryQueue.Subscription)
+		cfg, err := sub.Config(ctx)
+		if err != nil {
+			logger.Error("failed to get retry subscription to record circuit breaker state", zap.Error(err))
+			return
+		}
+		if cfg.Labels[config.CircuitBreakerStateLabel] == string(state) {
+			return
+		}
+		labels := make(map[string]string, len(cfg.Labels)+1)
+		for k, v := range cfg.Labels {
+			labels[k] = v
+		}
+		labels[config.CircuitBreakerStateLabel] = string(state)
+		if _, err := sub.Update(ctx, pubsub.SubscriptionConfigToUpdate{Labels: labels}); err != nil {
+			logger.Error("failed to record circuit breaker state", zap.Error(err))
+			return
+		}
+		logger.Info("circuit breaker state changed")
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlertesting "github.com/google/knative-gcp/pkg/broker/handler/testing"
+)
+
+func TestRecordCircuitBreakerState(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	helper, err := handlertesting.NewHelper(ctx, "test-project")
+	if err != nil {
+		t.Fatalf("failed to create pool testing helper: %v", err)
+	}
+	defer helper.Close()
+
+	b := helper.GenerateBroker(ctx, t, "ns")
+	target := helper.GenerateTarget(ctx, t, b.Key(), nil)
+	record := recordCircuitBreakerState(helper.PubsubClient)
+
+	for _, state := range []config.CircuitBreakerState{config.CircuitBreakerOpen, config.CircuitBreakerHalfOpen, config.CircuitBreakerClosed} {
+		record(ctx, target, state)
+		cfg, err := helper.PubsubClient.Subscription(target.RetryQueue.Subscription).Config(ctx)
+		if err != nil {
+			t.Fatalf("failed to get retry subscription config: %v", err)
+		}
+		if got := cfg.Labels[config.CircuitBreakerStateLabel]; got != string(state) {
+			t.Errorf("circuit breaker state label = %q, want %q", got, state)
+		}
+	}
+}
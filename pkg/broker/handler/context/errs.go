@@ -21,4 +21,6 @@ import "errors"
 var (
 	ErrTargetKeyNotPresent = errors.New("target key not present in the context")
 	ErrBrokerKeyNotPresent = errors.New("broker key not present in the context")
+
+	ErrPubsubAttributesNotPresent = errors.New("pubsub attributes not present in the context")
 )
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+)
+
+type pubsubAttributesKey struct{}
+
+// WithPubsubAttributes sets the attributes of the received Pubsub message in
+// the context. These include attributes that are not carried by the
+// CloudEvent, e.g. the ones Pubsub adds when forwarding a message to a dead
+// letter topic.
+func WithPubsubAttributes(ctx context.Context, attrs map[string]string) context.Context {
+	return context.WithValue(ctx, pubsubAttributesKey{}, attrs)
+}
+
+// GetPubsubAttributes gets the attributes of the received Pubsub message from
+// the context.
+func GetPubsubAttributes(ctx context.Context) (map[string]string, error) {
+	untyped := ctx.Value(pubsubAttributesKey{})
+	if untyped == nil {
+		return nil, ErrPubsubAttributesNotPresent
+	}
+	return untyped.(map[string]string), nil
+}
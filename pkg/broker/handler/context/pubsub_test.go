@@ -0,0 +1,40 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPubsubAttributes(t *testing.T) {
+	_, err := GetPubsubAttributes(context.Background())
+	if err != ErrPubsubAttributesNotPresent {
+		t.Errorf("error from GetPubsubAttributes got=%v, want=%v", err, ErrPubsubAttributesNotPresent)
+	}
+	wantAttrs := map[string]string{"CloudPubSubDeadLetterSourceDeliveryCount": "5"}
+	ctx := WithPubsubAttributes(context.Background(), wantAttrs)
+	gotAttrs, err := GetPubsubAttributes(ctx)
+	if err != nil {
+		t.Errorf("unexpected error from GetPubsubAttributes: %v", err)
+	}
+	if diff := cmp.Diff(wantAttrs, gotAttrs); diff != "" {
+		t.Errorf("GetPubsubAttributes (-want,+got): %v", diff)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/deadletter"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+// deadLetterPool is the sync pool for dead letter handlers. It is owned and
+// synced by the RetryPool.
+// For each trigger in the config with a dead letter queue, it will attempt to
+// create a handler that pulls from the queue and delivers to the dead letter sink.
+type deadLetterPool struct {
+	options *Options
+	targets config.ReadonlyTargets
+	// pool maps config.TargetKey to *deadLetterHandlerCache.
+	pool sync.Map
+	// Pubsub client used to pull events from dead letter queues.
+	pubsubClient *pubsub.Client
+	// Shared client to deliver events to dead letter sinks.
+	deliverClient *http.Client
+	statsReporter *metrics.DeliveryReporter
+}
+
+type deadLetterHandlerCache struct {
+	Handler
+	t *config.Target
+}
+
+// If somehow the existing handler's setting has deviated from the current target config,
+// we need to renew the handler.
+func (hc *deadLetterHandlerCache) shouldRenew(t *config.Target) bool {
+	if !hc.IsAlive() {
+		return true
+	}
+	if t.DeadLetterQueue.Topic != hc.t.DeadLetterQueue.Topic ||
+		t.DeadLetterQueue.Subscription != hc.t.DeadLetterQueue.Subscription {
+		return true
+	}
+	return false
+}
+
+// hasDeadLetterQueue returns true if events of the target should be
+// delivered from the dead letter queue by the data plane.
+func hasDeadLetterQueue(t *config.Target) bool {
+	return t != nil && t.DeadLetterQueue != nil && t.DeadLetterAddress != ""
+}
+
+func newDeadLetterPool(
+	targets config.ReadonlyTargets,
+	pubsubClient *pubsub.Client,
+	deliverClient *http.Client,
+	statsReporter *metrics.DeliveryReporter,
+	options *Options) *deadLetterPool {
+	return &deadLetterPool{
+		targets:       targets,
+		options:       options,
+		pubsubClient:  pubsubClient,
+		deliverClient: deliverClient,
+		statsReporter: statsReporter,
+	}
+}
+
+// SyncOnce syncs once the handler pool based on the targets config.
+func (p *deadLetterPool) SyncOnce(ctx context.Context) {
+	p.pool.Range(func(key, value interface{}) bool {
+		tk := key.(config.TargetKey)
+		if t, ok := p.targets.GetTargetByKey(&tk); !ok || !hasDeadLetterQueue(t) {
+			value.(*deadLetterHandlerCache).Stop()
+			p.pool.Delete(key)
+		}
+		return true
+	})
+
+	p.targets.RangeAllTargets(func(t *config.Target) bool {
+		if !hasDeadLetterQueue(t) {
+			return true
+		}
+		if value, ok := p.pool.Load(*t.Key()); ok {
+			hc := value.(*deadLetterHandlerCache)
+			// Skip if we don't need to renew the handler.
+			if !hc.shouldRenew(t) {
+				return true
+			}
+			// Stop and clean up the old handler before we start a new one.
+			hc.Stop()
+			p.pool.Delete(*t.Key())
+		}
+
+		// Don't start the handler if the target is not ready.
+		// The dead letter topic/sub might not be ready at this point.
+		if t.State != config.State_READY {
+			return true
+		}
+
+		sub := p.pubsubClient.Subscription(t.DeadLetterQueue.Subscription)
+		sub.ReceiveSettings = p.options.PubsubReceiveSettings
+
+		h := NewHandler(
+			sub,
+			processors.ChainProcessors(
+				&deadletter.Processor{
+					DeliverClient: p.deliverClient,
+					Targets:       p.targets,
+					StatsReporter: p.statsReporter,
+				},
+			),
+			p.options.TimeoutPerEvent,
+		)
+		hc := &deadLetterHandlerCache{
+			Handler: *h,
+			t:       t,
+		}
+
+		ctx, err := metrics.AddTargetTags(ctx, t)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to add target tags to context", zap.Error(err))
+		}
+
+		ctx = handlerctx.WithBrokerKey(ctx, t.Key().ParentKey())
+		ctx = handlerctx.WithTargetKey(ctx, t.Key())
+		hc.Start(ctx, func(err error) {
+			if err != nil {
+				logging.FromContext(ctx).Error("dead letter handler for trigger has stopped with error", zap.Stringer("trigger", t.Key()), zap.Error(err))
+			} else {
+				logging.FromContext(ctx).Info("dead letter handler for trigger has stopped", zap.Stringer("trigger", t.Key()))
+			}
+		})
+
+		p.pool.Store(*t.Key(), hc)
+		return true
+	})
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.uber.org/zap"
 
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
@@ -51,12 +52,25 @@ type FanoutPool struct {
 	options *Options
 	targets config.ReadonlyTargets
 	pool    *syncMapBrokerKey
+	// isolated maps config.TargetKey to *isolatedHandlerCache.
+	isolated sync.Map
 
 	// Pubsub client used to pull events from decoupling topics.
 	pubsubClient *pubsub.Client
 	// For sending retry events. We only need a shared client.
 	// And we can set retry topic dynamically.
 	deliverRetryClient ceclient.Client
+	// For sending retry events of brokers with ordered delivery.
+	orderedRetryClient *deliver.OrderedRetryClient
+	// For aggregating the events of targets with a batch policy.
+	batcher *deliver.Batcher
+	// For enforcing the rate limits of targets.
+	limiter *deliver.Limiter
+	// For enforcing the circuit breakers of targets. Their state is recorded
+	// by the retry pool.
+	circuitBreakers *deliver.CircuitBreakers
+	// For authenticating deliveries to targets with an audience.
+	idTokens *deliver.IDTokens
 	// For initial events delivery. We only need a shared client.
 	// And we can set target address dynamically.
 	deliverClient *http.Client
@@ -100,8 +114,10 @@ func NewFanoutPool(
 		return nil, err
 	}
 
-	if options.TimeoutPerEvent < timeoutCushion {
-		return nil, fmt.Errorf("timeout per event cannot be lower than %v", 5*time.Second)
+	// The delivery timeout can't be shorter than the Triggers' batch linger
+	// times allow.
+	if options.TimeoutPerEvent < timeoutCushion+brokerv1beta1.MinDeliveryTimeout {
+		return nil, fmt.Errorf("timeout per event cannot be lower than %v", timeoutCushion+brokerv1beta1.MinDeliveryTimeout)
 	}
 
 	// For fanout delivery, we need a slightly shorter timeout
@@ -119,6 +135,11 @@ func NewFanoutPool(
 		pubsubClient:       pubsubClient,
 		deliverClient:      deliverClient,
 		deliverRetryClient: retryClient,
+		orderedRetryClient: deliver.NewOrderedRetryClient(pubsubClient),
+		batcher:            deliver.NewBatcher(),
+		limiter:            deliver.NewLimiter(),
+		circuitBreakers:    deliver.NewCircuitBreakers(nil, 0),
+		idTokens:           deliver.NewIDTokens(options.IDTokenSource),
 		statsReporter:      statsReporter,
 	}
 	return p, nil
@@ -164,14 +185,7 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 			processors.ChainProcessors(
 				&fanout.Processor{MaxConcurrency: p.options.MaxConcurrencyPerEvent, Targets: p.targets},
 				&filter.Processor{Targets: p.targets},
-				&deliver.Processor{
-					DeliverClient:      p.deliverClient,
-					Targets:            p.targets,
-					RetryOnFailure:     true,
-					DeliverRetryClient: p.deliverRetryClient,
-					DeliverTimeout:     p.options.DeliveryTimeout,
-					StatsReporter:      p.statsReporter,
-				},
+				p.newDeliverProcessor(),
 			),
 			p.options.TimeoutPerEvent,
 		)
@@ -193,9 +207,28 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 		return true
 	})
 
+	p.syncIsolatedHandlers(ctx)
 	return nil
 }
 
+// newDeliverProcessor creates a processor delivering events to the targets
+// and sending the failed ones to their retry queues.
+func (p *FanoutPool) newDeliverProcessor() *deliver.Processor {
+	return &deliver.Processor{
+		DeliverClient:      p.deliverClient,
+		Targets:            p.targets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: p.deliverRetryClient,
+		OrderedRetryClient: p.orderedRetryClient,
+		Batcher:            p.batcher,
+		Limiter:            p.limiter,
+		CircuitBreakers:    p.circuitBreakers,
+		IDTokens:           p.idTokens,
+		DeliverTimeout:     p.options.DeliveryTimeout,
+		StatsReporter:      p.statsReporter,
+	}
+}
+
 // syncMapBrokerKey is a typed version of sync.Map.
 type syncMapBrokerKey struct {
 	m sync.Map
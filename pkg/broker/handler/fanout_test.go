@@ -25,6 +25,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
@@ -395,6 +396,87 @@ func TestFanoutSyncPoolE2E(t *testing.T) {
 	})
 }
 
+func TestFanoutIsolatedTarget(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	testProject := "test-project"
+
+	helper, err := handlertesting.NewHelper(ctx, testProject)
+	if err != nil {
+		t.Fatalf("failed to create pool testing helper: %v", err)
+	}
+	defer helper.Close()
+
+	b := helper.GenerateBroker(ctx, t, "ns")
+	shared := helper.GenerateTarget(ctx, t, b.Key(), nil)
+	isolated := helper.GenerateTarget(ctx, t, b.Key(), nil)
+	isolated = helper.IsolateTarget(ctx, t, isolated.Key())
+
+	signal := make(chan struct{})
+	syncPool, err := InitializeTestFanoutPool(ctx, fanoutPod, fanoutContainer, helper.Targets, helper.PubsubClient)
+	if err != nil {
+		t.Errorf("unexpected error from getting sync pool: %v", err)
+	}
+
+	p, err := GetFreePort()
+	if err != nil {
+		t.Fatalf("failed to get random free port: %v", err)
+	}
+
+	if _, err := StartSyncPool(ctx, syncPool, signal, time.Minute, p, &authcheck.FakeAuthenticationCheck{}); err != nil {
+		t.Errorf("unexpected error from starting sync pool: %v", err)
+	}
+	if _, ok := syncPool.isolated.Load(*isolated.Key()); !ok {
+		t.Errorf("no isolated handler for target %v", isolated.Key())
+	}
+	if _, ok := syncPool.isolated.Load(*shared.Key()); ok {
+		t.Errorf("unexpected isolated handler for target %v", shared.Key())
+	}
+
+	e := event.New()
+	e.SetType("type")
+	e.SetID("id")
+	e.SetSource("source")
+
+	t.Run("isolated target receives events once", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+
+		group, ctx := errgroup.WithContext(ctx)
+		group.Go(func() error {
+			helper.VerifyNextTargetEvent(ctx, t, shared.Key(), &e)
+			return nil
+		})
+		group.Go(func() error {
+			helper.VerifyNextTargetEvent(ctx, t, isolated.Key(), &e)
+			// The handler of the decouple subscription skips the isolated target.
+			helper.VerifyNextTargetEvent(ctx, t, isolated.Key(), nil)
+			return nil
+		})
+
+		helper.SendEventToDecoupleQueue(ctx, t, b.Key(), &e)
+
+		if err := group.Wait(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("removing the isolation stops the isolated handler", func(t *testing.T) {
+		notIsolated := proto.Clone(isolated).(*config.Target)
+		notIsolated.IsolatedQueue = nil
+		helper.Targets.MutateCellTenant(b.Key(), func(bm config.CellTenantMutation) {
+			bm.UpsertTargets(notIsolated)
+		})
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		if _, ok := syncPool.isolated.Load(*isolated.Key()); ok {
+			t.Errorf("unexpected isolated handler for target %v", isolated.Key())
+		}
+	})
+}
+
 func assertFanoutHandlers(t *testing.T, p *FanoutPool, targets config.Targets) {
 	t.Helper()
 	gotHandlers := make(map[config.CellTenantKey]bool)
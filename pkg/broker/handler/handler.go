@@ -25,6 +25,8 @@ import (
 	"cloud.google.com/go/pubsub"
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	"github.com/google/knative-gcp/pkg/logging"
 	"github.com/google/knative-gcp/pkg/metrics"
@@ -32,7 +34,9 @@ import (
 )
 
 // Handler pulls Pubsub messages as events and processes them
-// with chain of processors.
+// with chain of processors. When the subscription has message ordering
+// enabled, messages with the same ordering key are processed sequentially
+// while messages with different keys are processed in parallel.
 type Handler struct {
 	// Subscription is the pubsub subscription that messages will be
 	// received from.
@@ -104,18 +108,52 @@ func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
 		return
 	}
 
+	// Keep the message attributes around, some of them are not part of the event.
+	ctx = handlerctx.WithPubsubAttributes(ctx, msg.Attributes)
+	received := time.Now()
+	for {
+		err := h.process(ctx, event)
+		var delayErr *processors.DelayError
+		if errors.As(err, &delayErr) && time.Since(received)+delayErr.Delay < h.maxHold() {
+			// Hold the message, the pubsub client keeps extending its ack
+			// deadline, so that the delay is not counted as a delivery attempt.
+			logging.FromContext(ctx).Debug("delaying event processing", zap.String("eventID", event.ID()), zap.Error(err))
+			select {
+			case <-ctx.Done():
+				msg.Nack()
+				return
+			case <-time.After(delayErr.Delay):
+				continue
+			}
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to process event", zap.String("eventID", event.ID()), zap.Error(err))
+			msg.Nack()
+			return
+		}
+		msg.Ack()
+		return
+	}
+}
+
+// process processes the event within the timeout per event.
+func (h *Handler) process(ctx context.Context, e *event.Event) error {
 	if h.Timeout != 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
 		defer cancel()
 	}
-	if err := h.Processor.Process(ctx, event); err != nil {
-		logging.FromContext(ctx).Error("failed to process event", zap.String("eventID", event.ID()), zap.Error(err))
-		msg.Nack()
-		return
-	}
+	return h.Processor.Process(ctx, e)
+}
 
-	msg.Ack()
+// maxHold returns how long a message can be held before the pubsub client
+// stops extending its ack deadline. A negative MaxExtension disables the
+// extension, in which case messages are not held.
+func (h *Handler) maxHold() time.Duration {
+	if h.Subscription.ReceiveSettings.MaxExtension != 0 {
+		return h.Subscription.ReceiveSettings.MaxExtension
+	}
+	return pubsub.DefaultReceiveSettings.MaxExtension
 }
 
 func isNonRetryable(err error) bool {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/sync/semaphore"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
@@ -128,6 +130,28 @@ func TestHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("hold event on processing delay", func(t *testing.T) {
+		unlock := processor.Lock()
+		processor.OneTimeErr = false
+		processor.OneTimeDelay = 200 * time.Millisecond
+		unlock()
+		if err := p.Send(ctx, binding.ToMessage(&testEvent)); err != nil {
+			t.Fatalf("failed to seed event to pubsub: %v", err)
+		}
+		// On delay, the handler should process the event again after the
+		// delay without nacking the pubsub message.
+		start := time.Now()
+		for i := 0; i < 2; i++ {
+			gotEvent := nextEventWithTimeout(eventCh)
+			if diff := cmp.Diff(&testEvent, gotEvent); diff != "" {
+				t.Errorf("processed event (-want,+got): %v", diff)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Errorf("event processed again after %v, want at least %v", elapsed, 200*time.Millisecond)
+		}
+	})
+
 	t.Run("message is not an event", func(t *testing.T) {
 		res := topic.Publish(context.Background(), &pubsub.Message{ID: "testid"})
 		if _, err := res.Get(context.Background()); err != nil {
@@ -160,6 +184,122 @@ func TestHandler(t *testing.T) {
 	})
 }
 
+// orderingProcessor records the events processed per ordering key, whether
+// events with the same key overlapped and the maximum concurrency observed.
+type orderingProcessor struct {
+	processors.BaseProcessor
+	delay time.Duration
+
+	mu            sync.Mutex
+	active        map[string]bool
+	overlapPerKey bool
+	concurrent    int
+	maxConcurrent int
+	processed     map[string][]string
+	wg            sync.WaitGroup
+}
+
+func (p *orderingProcessor) Process(ctx context.Context, e *event.Event) error {
+	defer p.wg.Done()
+	key := e.Extensions()["partitionkey"].(string)
+	p.mu.Lock()
+	if p.active[key] {
+		p.overlapPerKey = true
+	}
+	p.active[key] = true
+	p.concurrent++
+	if p.concurrent > p.maxConcurrent {
+		p.maxConcurrent = p.concurrent
+	}
+	p.processed[key] = append(p.processed[key], e.ID())
+	p.mu.Unlock()
+
+	time.Sleep(p.delay)
+
+	p.mu.Lock()
+	p.active[key] = false
+	p.concurrent--
+	p.mu.Unlock()
+	return nil
+}
+
+func TestHandlerOrderedDelivery(t *testing.T) {
+	ctx := context.Background()
+	c, cleanup := testPubsubClient(ctx, t, testProjectID)
+	defer cleanup()
+
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	topic.EnableMessageOrdering = true
+	sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+		Topic:                 topic,
+		EnableMessageOrdering: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	keys := []string{"key-1", "key-2"}
+	const eventsPerKey = 3
+	processor := &orderingProcessor{
+		delay:     100 * time.Millisecond,
+		active:    make(map[string]bool),
+		processed: make(map[string][]string),
+	}
+	processor.wg.Add(len(keys) * eventsPerKey)
+
+	want := make(map[string][]string)
+	for i := 0; i < eventsPerKey; i++ {
+		for _, key := range keys {
+			e := event.New()
+			e.SetID(fmt.Sprintf("%s-%d", key, i))
+			e.SetSource("source")
+			e.SetType("type")
+			e.SetExtension("partitionkey", key)
+			msg := &pubsub.Message{OrderingKey: key}
+			if err := cepubsub.WritePubSubMessage(ctx, binding.ToMessage(&e), msg); err != nil {
+				t.Fatalf("failed to write pubsub message: %v", err)
+			}
+			if _, err := topic.Publish(ctx, msg).Get(ctx); err != nil {
+				t.Fatalf("failed to publish message: %v", err)
+			}
+			want[key] = append(want[key], e.ID())
+		}
+	}
+
+	h := NewHandler(sub, processor, 10*time.Second)
+	h.Start(ctx, func(err error) {})
+	defer h.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		processor.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the events to be processed")
+	}
+
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	if processor.overlapPerKey {
+		t.Error("events with the same ordering key were processed concurrently")
+	}
+	if processor.maxConcurrent < 2 {
+		t.Errorf("events with different ordering keys were not processed concurrently, max concurrency=%d", processor.maxConcurrent)
+	}
+	// The fake server does not deliver messages in publish order, so only
+	// check that every event was processed exactly once.
+	sortStrings := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+	if diff := cmp.Diff(want, processor.processed, sortStrings); diff != "" {
+		t.Errorf("processed events (-want,+got): %v", diff)
+	}
+}
+
 type BenchProcessor struct {
 	processors.BaseProcessor
 
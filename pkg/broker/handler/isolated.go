@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+type isolatedHandlerCache struct {
+	Handler
+	t *config.Target
+}
+
+// If somehow the existing handler's setting has deviated from the current target config,
+// we need to renew the handler.
+func (hc *isolatedHandlerCache) shouldRenew(t *config.Target) bool {
+	if !hc.IsAlive() {
+		return true
+	}
+	if t.IsolatedQueue.Topic != hc.t.IsolatedQueue.Topic ||
+		t.IsolatedQueue.Subscription != hc.t.IsolatedQueue.Subscription {
+		return true
+	}
+	return false
+}
+
+// syncIsolatedHandlers syncs the handlers of the isolated targets, which
+// deliver the events of the targets' dedicated subscriptions. The handler of
+// the CellTenant's decouple subscription skips these targets, so that a slow
+// isolated target doesn't hold back the others.
+func (p *FanoutPool) syncIsolatedHandlers(ctx context.Context) {
+	p.isolated.Range(func(key, value interface{}) bool {
+		tk := key.(config.TargetKey)
+		if t, ok := p.targets.GetTargetByKey(&tk); !ok || !t.IsIsolated() || t.Paused {
+			value.(*isolatedHandlerCache).Stop()
+			p.isolated.Delete(key)
+		}
+		return true
+	})
+
+	p.targets.RangeAllTargets(func(t *config.Target) bool {
+		// The events of paused targets accumulate in their dedicated
+		// subscription.
+		if !t.IsIsolated() || t.Paused {
+			return true
+		}
+		if value, ok := p.isolated.Load(*t.Key()); ok {
+			hc := value.(*isolatedHandlerCache)
+			// Skip if we don't need to renew the handler.
+			if !hc.shouldRenew(t) {
+				return true
+			}
+			// Stop and clean up the old handler before we start a new one.
+			hc.Stop()
+			p.isolated.Delete(*t.Key())
+		}
+
+		sub := p.pubsubClient.Subscription(t.IsolatedQueue.Subscription)
+		sub.ReceiveSettings = p.options.PubsubReceiveSettings
+
+		h := NewHandler(
+			sub,
+			processors.ChainProcessors(
+				&filter.Processor{Targets: p.targets},
+				p.newDeliverProcessor(),
+			),
+			p.options.TimeoutPerEvent,
+		)
+		hc := &isolatedHandlerCache{
+			Handler: *h,
+			t:       t,
+		}
+
+		ctx, err := metrics.AddTargetTags(ctx, t)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to add target tags to context", zap.Error(err))
+		}
+
+		ctx = handlerctx.WithBrokerKey(ctx, t.Key().ParentKey())
+		ctx = handlerctx.WithTargetKey(ctx, t.Key())
+		hc.Start(ctx, func(err error) {
+			if err != nil {
+				logging.FromContext(ctx).Error("isolated handler for trigger has stopped with error", zap.Stringer("trigger", t.Key()), zap.Error(err))
+			} else {
+				logging.FromContext(ctx).Info("isolated handler for trigger has stopped", zap.Stringer("trigger", t.Key()))
+			}
+		})
+
+		p.isolated.Store(*t.Key(), hc)
+		return true
+	})
+}
@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"cloud.google.com/go/pubsub"
+
+	"github.com/google/knative-gcp/pkg/gclient/idtoken"
 )
 
 var (
@@ -50,6 +52,9 @@ type Options struct {
 	DeliveryTimeout time.Duration
 	// PubsubReceiveSettings is the pubsub receive settings.
 	PubsubReceiveSettings pubsub.ReceiveSettings
+	// IDTokenSource is the source of the ID tokens that authenticate
+	// deliveries to targets with an audience.
+	IDTokenSource idtoken.Source
 }
 
 // NewOptions creates a Options.
@@ -59,6 +64,7 @@ func NewOptions(opts ...Option) (*Options, error) {
 		MaxConcurrencyPerEvent: defaultMaxConcurrencyPerEvent,
 		TimeoutPerEvent:        defaultTimeout,
 		PubsubReceiveSettings:  pubsub.DefaultReceiveSettings,
+		IDTokenSource:          idtoken.NewSource(),
 	}
 	for _, o := range opts {
 		o(opt)
@@ -107,3 +113,10 @@ func WithDeliveryTimeout(t time.Duration) Option {
 		o.DeliveryTimeout = t
 	}
 }
+
+// WithIDTokenSource sets the IDTokenSource.
+func WithIDTokenSource(s idtoken.Source) Option {
+	return func(o *Options) {
+		o.IDTokenSource = s
+	}
+}
@@ -22,6 +22,8 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/go-cmp/cmp"
+
+	idtokentesting "github.com/google/knative-gcp/pkg/gclient/idtoken/testing"
 )
 
 func TestWithHandlerConcurrency(t *testing.T) {
@@ -96,3 +98,14 @@ func TestWithDeliveryTimeout(t *testing.T) {
 		t.Errorf("options timeout per event got=%v, want=%v", opt.DeliveryTimeout, want)
 	}
 }
+
+func TestWithIDTokenSource(t *testing.T) {
+	want := idtokentesting.NewTestSource(idtokentesting.TestSourceData{})
+	opt, err := NewOptions(WithIDTokenSource(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.IDTokenSource != want {
+		t.Errorf("options ID token source got=%v, want=%v", opt.IDTokenSource, want)
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+const (
+	// ErrorDestExtension is the extension that holds the address of the
+	// target the event failed to be delivered to.
+	ErrorDestExtension = "knativeerrordest"
+	// ErrorReasonExtension is the extension that holds the reason the event
+	// was dead lettered.
+	ErrorReasonExtension = "knativeerrorreason"
+	// ErrorAttemptsExtension is the extension that holds the number of
+	// delivery attempts made before the event was dead lettered.
+	ErrorAttemptsExtension = "knativeerrorattempts"
+
+	// ReasonRetriesExhausted is the reason set on events that were dead
+	// lettered after exhausting the trigger's retries.
+	ReasonRetriesExhausted = "RetriesExhausted"
+
+	// deliveryCountAttribute is the attribute Pubsub sets on messages
+	// forwarded to a dead letter topic with the number of delivery attempts
+	// of the source subscription.
+	deliveryCountAttribute = "CloudPubSubDeadLetterSourceDeliveryCount"
+)
+
+// Processor delivers events from a trigger's dead letter queue to the dead
+// letter sink of the target in the context.
+type Processor struct {
+	processors.BaseProcessor
+
+	// DeliverClient is the client to send events.
+	DeliverClient *http.Client
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	// DeliverTimeout is the timeout applied to cancel delivery.
+	// If zero, not additional timeout is applied.
+	DeliverTimeout time.Duration
+
+	// StatsReporter is used to report delivery metrics.
+	StatsReporter *metrics.DeliveryReporter
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process delivers the event to the dead letter sink of the target in the context.
+func (p *Processor) Process(ctx context.Context, e *event.Event) error {
+	tk, err := handlerctx.GetTargetKey(ctx)
+	if err != nil {
+		return err
+	}
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok {
+		// If the target no longer exists, then there is nothing to process.
+		logging.FromContext(ctx).Warn("target no longer exist in the config", zap.Stringer("target", tk))
+		trace.FromContext(ctx).Annotate(
+			ceclient.EventTraceAttributes(e),
+			"event dropped: trigger config no longer exists",
+		)
+		return nil
+	}
+	if target.DeadLetterAddress == "" {
+		// Without an address the message can only be kept in the queue until
+		// the dead letter sink is resolvable again.
+		return fmt.Errorf("target %v has no dead letter address", tk)
+	}
+
+	p.StatsReporter.FinishEventProcessing(ctx)
+
+	dctx := ctx
+	if p.DeliverTimeout > 0 {
+		var cancel context.CancelFunc
+		dctx, cancel = context.WithTimeout(dctx, p.DeliverTimeout)
+		defer cancel()
+	}
+
+	transformers := []binding.Transformer{
+		// Hops is a broker local counter and is meaningless to the dead letter sink.
+		transformer.DeleteExtension(eventutil.HopsAttribute),
+		setExtension(ErrorDestExtension, target.Address),
+		setExtension(ErrorReasonExtension, ReasonRetriesExhausted),
+	}
+	if attempts, ok := deliveryAttempts(ctx); ok {
+		transformers = append(transformers, setExtension(ErrorAttemptsExtension, attempts))
+	}
+
+	if err := p.deliver(dctx, target.DeadLetterAddress, eventutil.NewImmutableEventMessage(e), transformers...); err != nil {
+		logging.FromContext(ctx).Warn("dead letter sink delivery failed", zap.Stringer("target", tk), zap.Error(err))
+		return err
+	}
+	return p.Next().Process(ctx, e)
+}
+
+// deliver sends msg to the dead letter sink. Any reply is ignored.
+func (p *Processor) deliver(ctx context.Context, address string, msg binding.Message, transformers ...binding.Transformer) error {
+	startTime := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, nil)
+	if err != nil {
+		return err
+	}
+	if err := cehttp.WriteRequest(ctx, msg, req, transformers...); err != nil {
+		return err
+	}
+	resp, err := p.DeliverClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Warn("failed to close response body", zap.Error(err))
+		}
+	}()
+
+	cctx, err := metrics.AddRespStatusCodeTags(ctx, resp.StatusCode)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to add status code tags to context", zap.Error(err))
+	}
+	p.StatsReporter.ReportEventDispatchTime(cctx, time.Since(startTime))
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("dead letter delivery failed: HTTP status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// deliveryAttempts returns the number of delivery attempts Pubsub made on
+// the retry subscription before forwarding the message to the dead letter topic.
+func deliveryAttempts(ctx context.Context) (int, bool) {
+	attrs, err := handlerctx.GetPubsubAttributes(ctx)
+	if err != nil {
+		return 0, false
+	}
+	count, ok := attrs[deliveryCountAttribute]
+	if !ok {
+		return 0, false
+	}
+	attempts, err := strconv.Atoi(count)
+	if err != nil {
+		return 0, false
+	}
+	return attempts, true
+}
+
+// setExtension overrides any existing value of the extension, e.g. from an
+// earlier dead lettering of the same event.
+func setExtension(name string, value interface{}) binding.TransformerFunc {
+	return transformer.SetExtension(name, func(interface{}) (interface{}, error) {
+		return value, nil
+	})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+
+	_ "knative.dev/pkg/metrics/testing"
+)
+
+func TestInvalidContext(t *testing.T) {
+	p := &Processor{Targets: memory.NewEmptyTargets()}
+	e := event.New()
+	err := p.Process(context.Background(), &e)
+	if err != handlerctx.ErrTargetKeyNotPresent {
+		t.Errorf("Process error got=%v, want=%v", err, handlerctx.ErrTargetKeyNotPresent)
+	}
+}
+
+func TestDeadLetterDelivery(t *testing.T) {
+	// Hops must not be forwarded to the dead letter sink.
+	sampleEvent := newSampleEvent()
+	eventutil.UpdateRemainingHops(context.Background(), sampleEvent, 10)
+
+	cases := []struct {
+		name       string
+		attrs      map[string]string
+		statusCode int
+		noAddress  bool
+		wantEvent  *event.Event
+		wantErr    bool
+	}{{
+		name:       "success",
+		attrs:      map[string]string{deliveryCountAttribute: "5"},
+		statusCode: http.StatusAccepted,
+		wantEvent: func() *event.Event {
+			e := newSampleEvent()
+			e.SetExtension(ErrorDestExtension, "http://target.example.com")
+			e.SetExtension(ErrorReasonExtension, ReasonRetriesExhausted)
+			e.SetExtension(ErrorAttemptsExtension, "5")
+			return e
+		}(),
+	}, {
+		name:       "success without delivery count",
+		statusCode: http.StatusOK,
+		wantEvent: func() *event.Event {
+			e := newSampleEvent()
+			e.SetExtension(ErrorDestExtension, "http://target.example.com")
+			e.SetExtension(ErrorReasonExtension, ReasonRetriesExhausted)
+			return e
+		}(),
+	}, {
+		name:       "dead letter sink failure",
+		statusCode: http.StatusInternalServerError,
+		wantEvent: func() *event.Event {
+			e := newSampleEvent()
+			e.SetExtension(ErrorDestExtension, "http://target.example.com")
+			e.SetExtension(ErrorReasonExtension, ReasonRetriesExhausted)
+			return e
+		}(),
+		wantErr: true,
+	}, {
+		name:      "no dead letter address",
+		noAddress: true,
+		wantErr:   true,
+	}}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+
+			gotEvents := make(chan *event.Event, 1)
+			sinkSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+				if err != nil {
+					t.Errorf("dead letter sink received message cannot be converted to an event: %v", err)
+				}
+				gotEvents <- e
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer sinkSvr.Close()
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:         "ns",
+				Name:              "target",
+				CellTenantType:    config.CellTenantType_BROKER,
+				CellTenantName:    "broker",
+				Address:           "http://target.example.com",
+				DeadLetterAddress: sinkSvr.URL,
+			}
+			if tc.noAddress {
+				target.DeadLetterAddress = ""
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			if tc.attrs != nil {
+				ctx = handlerctx.WithPubsubAttributes(ctx, tc.attrs)
+			}
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				StatsReporter: r,
+			}
+
+			err = p.Process(ctx, sampleEvent)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("Process error got=%v, want error=%v", err, tc.wantErr)
+			}
+
+			var gotEvent *event.Event
+			select {
+			case gotEvent = <-gotEvents:
+			case <-time.After(100 * time.Millisecond):
+			}
+			if diff := cmp.Diff(tc.wantEvent, gotEvent); diff != "" {
+				t.Errorf("dead letter sink received event (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func newSampleEvent() *event.Event {
+	sampleEvent := event.New()
+	sampleEvent.SetID("id")
+	sampleEvent.SetSource("source")
+	sampleEvent.SetSubject("subject")
+	sampleEvent.SetType("type")
+	sampleEvent.SetTime(time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC))
+	return &sampleEvent
+}
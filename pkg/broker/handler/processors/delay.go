@@ -0,0 +1,43 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processors
+
+import "time"
+
+// DelayError is returned by a processor when an event cannot be processed
+// yet, e.g. because its target is throttled. Rather than nacking the message,
+// which counts as a delivery attempt, the handler holds it and processes the
+// event again after Delay.
+type DelayError struct {
+	// Delay is the time to wait before processing the event again.
+	Delay time.Duration
+	// Err is the reason the processing is delayed.
+	Err error
+}
+
+// Delay returns a DelayError delaying the processing by d because of err.
+func Delay(err error, d time.Duration) error {
+	return &DelayError{Delay: d, Err: err}
+}
+
+func (e *DelayError) Error() string {
+	return "processing delayed: " + e.Err.Error()
+}
+
+func (e *DelayError) Unwrap() error {
+	return e.Err
+}
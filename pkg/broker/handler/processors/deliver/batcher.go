@@ -0,0 +1,175 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// batchEntry is an event waiting in a batch for delivery.
+type batchEntry struct {
+	event *event.Event
+	hops  int32
+	// done receives the delivery result of the batch.
+	done chan error
+}
+
+// batchFlushFunc delivers a batch of events. ctx is not cancelled when the
+// processing of the events that make up the batch is cancelled.
+type batchFlushFunc func(ctx context.Context, entries []*batchEntry) error
+
+type batch struct {
+	ctx     context.Context
+	entries []*batchEntry
+	timer   *time.Timer
+	flush   batchFlushFunc
+}
+
+// Batcher aggregates the events of targets with a batch policy, and flushes
+// a target's batch once it is full or its oldest event has waited for the
+// maximum linger time.
+type Batcher struct {
+	mu      sync.Mutex
+	batches map[config.TargetKey]*batch
+}
+
+// NewBatcher creates a new Batcher.
+func NewBatcher() *Batcher {
+	return &Batcher{
+		batches: make(map[config.TargetKey]*batch),
+	}
+}
+
+// add adds the event to the current batch of the target, and blocks until
+// the batch has been flushed. It returns the result of the batch delivery, or
+// the error of ctx if ctx is done before the batch is flushed, in which case
+// the event is removed from the batch. flush is used if the event starts a new
+// batch.
+func (b *Batcher) add(ctx context.Context, target *config.Target, e *event.Event, hops int32, flush batchFlushFunc) error {
+	entry := &batchEntry{
+		event: e,
+		hops:  hops,
+		done:  make(chan error, 1),
+	}
+	key := *target.Key()
+
+	b.mu.Lock()
+	bt, ok := b.batches[key]
+	if !ok {
+		bt = &batch{
+			ctx:   detachedContext{ctx},
+			flush: flush,
+		}
+		b.batches[key] = bt
+		linger := time.Duration(target.BatchPolicy.GetMaxLingerMillis()) * time.Millisecond
+		bt.timer = time.AfterFunc(linger, func() {
+			b.flush(key, bt)
+		})
+	}
+	bt.entries = append(bt.entries, entry)
+	if len(bt.entries) >= int(target.BatchPolicy.GetMaxSize()) {
+		// The batch is full, remove it so that the next event starts a new one.
+		delete(b.batches, key)
+		bt.timer.Stop()
+		go bt.deliver()
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-entry.done:
+		return err
+	case <-ctx.Done():
+	}
+	if b.remove(key, bt, entry) {
+		return ctx.Err()
+	}
+	// The batch is already being delivered with the event.
+	return <-entry.done
+}
+
+// remove removes the entry from the batch unless the batch is already being
+// delivered, and returns whether it was removed. The batch is discarded once
+// it is empty.
+func (b *Batcher) remove(key config.TargetKey, bt *batch, entry *batchEntry) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.batches[key] != bt {
+		return false
+	}
+	for i, e := range bt.entries {
+		if e == entry {
+			bt.entries = append(bt.entries[:i], bt.entries[i+1:]...)
+			break
+		}
+	}
+	if len(bt.entries) == 0 {
+		delete(b.batches, key)
+		bt.timer.Stop()
+	}
+	return true
+}
+
+// flush delivers the batch when its linger time has elapsed, unless it was
+// already delivered because it became full.
+func (b *Batcher) flush(key config.TargetKey, bt *batch) {
+	b.mu.Lock()
+	if b.batches[key] != bt {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.batches, key)
+	b.mu.Unlock()
+	bt.deliver()
+}
+
+// deliver flushes the batch and reports the result to all its entries.
+func (bt *batch) deliver() {
+	err := bt.flush(bt.ctx, bt.entries)
+	for _, entry := range bt.entries {
+		entry.done <- err
+	}
+}
+
+// detachedContext keeps the values of its parent context, e.g. the logger,
+// metric tags and trace span, but is never cancelled. A batch is delivered
+// with the detached context of its first event, so that the cancellation of
+// that event's processing does not fail the whole batch.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
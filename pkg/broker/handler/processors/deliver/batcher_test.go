@@ -0,0 +1,223 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestBatcher(t *testing.T) {
+	deliveryErr := errors.New("delivery failed")
+
+	cases := []struct {
+		name        string
+		policy      *config.BatchPolicy
+		events      int
+		flushErr    error
+		wantBatches []int
+	}{{
+		name:        "full batches",
+		policy:      &config.BatchPolicy{MaxSize: 2, MaxLingerMillis: 10000},
+		events:      4,
+		wantBatches: []int{2, 2},
+	}, {
+		name:        "linger time elapsed",
+		policy:      &config.BatchPolicy{MaxSize: 10, MaxLingerMillis: 50},
+		events:      3,
+		wantBatches: []int{3},
+	}, {
+		name:        "failed delivery",
+		policy:      &config.BatchPolicy{MaxSize: 3, MaxLingerMillis: 10000},
+		events:      3,
+		flushErr:    deliveryErr,
+		wantBatches: []int{3},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				BatchPolicy:    tc.policy,
+			}
+			b := NewBatcher()
+
+			var mu sync.Mutex
+			var gotBatches []int
+			flush := func(ctx context.Context, entries []*batchEntry) error {
+				mu.Lock()
+				defer mu.Unlock()
+				gotBatches = append(gotBatches, len(entries))
+				return tc.flushErr
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			errs := make(chan error, tc.events)
+			for i := 0; i < tc.events; i++ {
+				e := event.New()
+				e.SetID(fmt.Sprintf("id-%d", i))
+				go func() {
+					errs <- b.add(ctx, target, &e, defaultEventHopsLimit, flush)
+				}()
+			}
+			for i := 0; i < tc.events; i++ {
+				if err := <-errs; err != tc.flushErr {
+					t.Errorf("add() = %v, want %v", err, tc.flushErr)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(gotBatches) != len(tc.wantBatches) {
+				t.Fatalf("got batches of sizes %v, want %v", gotBatches, tc.wantBatches)
+			}
+			for i := range gotBatches {
+				if gotBatches[i] != tc.wantBatches[i] {
+					t.Errorf("got batches of sizes %v, want %v", gotBatches, tc.wantBatches)
+				}
+			}
+		})
+	}
+}
+
+func TestBatcherContextDone(t *testing.T) {
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		BatchPolicy:    &config.BatchPolicy{MaxSize: 10, MaxLingerMillis: 100},
+	}
+	b := NewBatcher()
+	type flushed struct {
+		err error
+		ids []string
+	}
+	flushes := make(chan flushed, 2)
+	flush := func(ctx context.Context, entries []*batchEntry) error {
+		var ids []string
+		for _, entry := range entries {
+			ids = append(ids, entry.event.ID())
+		}
+		// The batch is delivered with a context that is not cancelled with
+		// the context of its first event.
+		flushes <- flushed{err: ctx.Err(), ids: ids}
+		return nil
+	}
+
+	// The first event is cancelled while the batch lingers.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := event.New()
+	first.SetID("first")
+	firstErr := make(chan error, 1)
+	go func() {
+		firstErr <- b.add(ctx, target, &first, defaultEventHopsLimit, flush)
+	}()
+	second := event.New()
+	second.SetID("second")
+	secondErr := make(chan error, 1)
+	go func() {
+		// Wait for the first event to start the batch.
+		time.Sleep(10 * time.Millisecond)
+		secondErr <- b.add(context.Background(), target, &second, defaultEventHopsLimit, flush)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-firstErr; err != context.Canceled {
+		t.Errorf("add() of the cancelled event = %v, want %v", err, context.Canceled)
+	}
+	if err := <-secondErr; err != nil {
+		t.Errorf("add() = %v, want nil", err)
+	}
+
+	select {
+	case f := <-flushes:
+		if f.err != nil {
+			t.Errorf("batch delivered with a done context: %v", f.err)
+		}
+		// The cancelled event is not delivered, its processing is retried.
+		if len(f.ids) != 1 || f.ids[0] != "second" {
+			t.Errorf("batch delivered events %v, want [second]", f.ids)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("timed out waiting for the batch to be delivered")
+	}
+}
+
+func TestBatcherContextDoneOnlyEvent(t *testing.T) {
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		BatchPolicy:    &config.BatchPolicy{MaxSize: 10, MaxLingerMillis: 50},
+	}
+	b := NewBatcher()
+	flushed := make(chan struct{}, 1)
+	flush := func(ctx context.Context, entries []*batchEntry) error {
+		flushed <- struct{}{}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e := event.New()
+	if err := b.add(ctx, target, &e, defaultEventHopsLimit, flush); err != context.Canceled {
+		t.Errorf("add() = %v, want %v", err, context.Canceled)
+	}
+	// The empty batch is discarded.
+	select {
+	case <-flushed:
+		t.Error("empty batch delivered")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBatcherContextDoneDuringDelivery(t *testing.T) {
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		BatchPolicy:    &config.BatchPolicy{MaxSize: 1, MaxLingerMillis: 10000},
+	}
+	b := NewBatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	flush := func(context.Context, []*batchEntry) error {
+		// The event is cancelled once its batch is being delivered.
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}
+
+	e := event.New()
+	if err := b.add(ctx, target, &e, defaultEventHopsLimit, flush); err != nil {
+		t.Errorf("add() = %v, want the delivery result", err)
+	}
+}
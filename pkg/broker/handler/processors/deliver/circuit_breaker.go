@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// ErrCircuitOpen is returned when an event is not delivered because the
+// circuit breaker of its target is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitBreakerStateFunc is called when the circuit breaker of a target is
+// created or changes state. Calls for the same target are serialized, start at
+// least the notify interval of the CircuitBreakers apart and always report the
+// latest state, so that the state changes within an interval are coalesced.
+type CircuitBreakerStateFunc func(ctx context.Context, target *config.Target, state config.CircuitBreakerState)
+
+type circuitBreaker struct {
+	policy   *config.CircuitBreaker
+	state    config.CircuitBreakerState
+	failures int32
+	openedAt time.Time
+	// probing is true while the single delivery allowed by a half-open
+	// circuit breaker is in flight.
+	probing bool
+	// notifying is true while the state change callback is running or
+	// waiting for the notify interval, and pending if the state changed again
+	// since it started.
+	notifying bool
+	pending   bool
+	// notifiedAt is when the state change callback was last called.
+	notifiedAt time.Time
+}
+
+// CircuitBreakers enforces the circuit breakers of targets. A circuit breaker
+// opens after consecutive failed deliveries to its target, rejecting
+// deliveries with ErrCircuitOpen. Once open for the target's open duration,
+// it becomes half-open and allows a single probe delivery: a successful probe
+// closes the circuit breaker, a failed one opens it again.
+type CircuitBreakers struct {
+	mu       sync.Mutex
+	breakers map[config.TargetKey]*circuitBreaker
+
+	onStateChange  CircuitBreakerStateFunc
+	notifyInterval time.Duration
+	// now is replaced in tests.
+	now func() time.Time
+}
+
+// NewCircuitBreakers creates a new CircuitBreakers. onStateChange may be nil,
+// otherwise it is called at most once per notifyInterval for each target.
+func NewCircuitBreakers(onStateChange CircuitBreakerStateFunc, notifyInterval time.Duration) *CircuitBreakers {
+	return &CircuitBreakers{
+		breakers:       make(map[config.TargetKey]*circuitBreaker),
+		onStateChange:  onStateChange,
+		notifyInterval: notifyInterval,
+		now:            time.Now,
+	}
+}
+
+// getLocked returns the circuit breaker of the target, or nil if it is
+// disabled. The circuit breaker keeps its state when the target's policy
+// changes. b.mu must be held.
+func (b *CircuitBreakers) getLocked(ctx context.Context, target *config.Target) *circuitBreaker {
+	key := *target.Key()
+	if target.CircuitBreaker == nil {
+		delete(b.breakers, key)
+		return nil
+	}
+	cb, ok := b.breakers[key]
+	if !ok {
+		cb = &circuitBreaker{state: config.CircuitBreakerClosed}
+		b.breakers[key] = cb
+		// The recorded state may be stale, e.g. after a restart.
+		b.notifyLocked(ctx, target, cb)
+	}
+	cb.policy = target.CircuitBreaker
+	return cb
+}
+
+// allow returns ErrCircuitOpen if an event cannot be delivered to target
+// because its circuit breaker is open. Otherwise the outcome of the delivery
+// must be reported.
+func (b *CircuitBreakers) allow(ctx context.Context, target *config.Target) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cb := b.getLocked(ctx, target)
+	if cb == nil {
+		return nil
+	}
+	switch cb.state {
+	case config.CircuitBreakerOpen:
+		openDuration := time.Duration(cb.policy.GetOpenDurationMillis()) * time.Millisecond
+		if b.now().Sub(cb.openedAt) < openDuration {
+			return ErrCircuitOpen
+		}
+		b.setStateLocked(ctx, target, cb, config.CircuitBreakerHalfOpen)
+		fallthrough
+	case config.CircuitBreakerHalfOpen:
+		if cb.probing {
+			return ErrCircuitOpen
+		}
+		cb.probing = true
+	}
+	return nil
+}
+
+// report records the outcome of a delivery to target allowed by allow.
+// Throttled deliveries say nothing about the target's health and are ignored.
+func (b *CircuitBreakers) report(ctx context.Context, target *config.Target, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	cb := b.getLocked(ctx, target)
+	if cb == nil {
+		return
+	}
+	switch {
+	case err == nil:
+		cb.failures = 0
+		cb.probing = false
+		b.setStateLocked(ctx, target, cb, config.CircuitBreakerClosed)
+	case errors.Is(err, ErrThrottled) || errors.Is(err, ErrCircuitOpen):
+		cb.probing = false
+	default:
+		cb.failures++
+		if cb.state == config.CircuitBreakerHalfOpen ||
+			(cb.state == config.CircuitBreakerClosed && cb.failures >= cb.policy.GetFailureThreshold()) {
+			cb.probing = false
+			cb.openedAt = b.now()
+			b.setStateLocked(ctx, target, cb, config.CircuitBreakerOpen)
+		}
+	}
+}
+
+func (b *CircuitBreakers) setStateLocked(ctx context.Context, target *config.Target, cb *circuitBreaker, state config.CircuitBreakerState) {
+	if cb.state == state {
+		return
+	}
+	cb.state = state
+	b.notifyLocked(ctx, target, cb)
+}
+
+// notifyLocked calls onStateChange with the latest state of cb in the
+// background once the notify interval has elapsed since the previous call,
+// unless a call for cb is already pending, in which case that call is followed
+// by another one. b.mu must be held.
+func (b *CircuitBreakers) notifyLocked(ctx context.Context, target *config.Target, cb *circuitBreaker) {
+	if b.onStateChange == nil {
+		return
+	}
+	cb.pending = true
+	if cb.notifying {
+		return
+	}
+	cb.notifying = true
+	ctx = detachedContext{ctx}
+	go func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for cb.pending {
+			if wait := time.Until(cb.notifiedAt.Add(b.notifyInterval)); wait > 0 {
+				b.mu.Unlock()
+				time.Sleep(wait)
+				b.mu.Lock()
+			}
+			cb.pending = false
+			cb.notifiedAt = time.Now()
+			state := cb.state
+			b.mu.Unlock()
+			b.onStateChange(ctx, target, state)
+			b.mu.Lock()
+		}
+		cb.notifying = false
+	}()
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func newCircuitBreakerTarget(circuitBreaker *config.CircuitBreaker) *config.Target {
+	return &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		CircuitBreaker: circuitBreaker,
+	}
+}
+
+type stateRecorder struct {
+	mu     sync.Mutex
+	states []config.CircuitBreakerState
+}
+
+func (r *stateRecorder) record(_ context.Context, _ *config.Target, state config.CircuitBreakerState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states = append(r.states, state)
+}
+
+func (r *stateRecorder) last() config.CircuitBreakerState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.states) == 0 {
+		return ""
+	}
+	return r.states[len(r.states)-1]
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	recorder := &stateRecorder{}
+	b := NewCircuitBreakers(recorder.record, 0)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	target := newCircuitBreakerTarget(&config.CircuitBreaker{FailureThreshold: 2, OpenDurationMillis: 1000})
+	ctx := context.Background()
+	errDelivery := errors.New("delivery failed")
+
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		result    error
+		wantAllow error
+		wantState config.CircuitBreakerState
+	}{{
+		name:      "first failure",
+		result:    errDelivery,
+		wantState: config.CircuitBreakerClosed,
+	}, {
+		name:      "throttled deliveries are ignored",
+		result:    ErrThrottled,
+		wantState: config.CircuitBreakerClosed,
+	}, {
+		name:      "failure threshold reached",
+		result:    errDelivery,
+		wantState: config.CircuitBreakerOpen,
+	}, {
+		name:      "open",
+		advance:   500 * time.Millisecond,
+		wantAllow: ErrCircuitOpen,
+		wantState: config.CircuitBreakerOpen,
+	}, {
+		name:      "failed probe",
+		advance:   500 * time.Millisecond,
+		result:    errDelivery,
+		wantState: config.CircuitBreakerOpen,
+	}, {
+		name:      "open again",
+		advance:   500 * time.Millisecond,
+		wantAllow: ErrCircuitOpen,
+		wantState: config.CircuitBreakerOpen,
+	}, {
+		name:      "successful probe",
+		advance:   500 * time.Millisecond,
+		wantState: config.CircuitBreakerClosed,
+	}}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		err := b.allow(ctx, target)
+		if err != step.wantAllow {
+			t.Fatalf("%s: allow() = %v, want %v", step.name, err, step.wantAllow)
+		}
+		if err == nil {
+			b.report(ctx, target, step.result)
+		}
+		if got := b.breakers[*target.Key()].state; got != step.wantState {
+			t.Errorf("%s: state = %v, want %v", step.name, got, step.wantState)
+		}
+	}
+
+	// The last notified state is eventually the current state.
+	deadline := time.Now().Add(time.Second)
+	for recorder.last() != config.CircuitBreakerClosed && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := recorder.last(); got != config.CircuitBreakerClosed {
+		t.Errorf("last notified state = %v, want %v", got, config.CircuitBreakerClosed)
+	}
+}
+
+func TestCircuitBreakersNotifyInterval(t *testing.T) {
+	recorder := &stateRecorder{}
+	b := NewCircuitBreakers(recorder.record, 200*time.Millisecond)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	target := newCircuitBreakerTarget(&config.CircuitBreaker{FailureThreshold: 1, OpenDurationMillis: 1000})
+	ctx := context.Background()
+
+	// The circuit breaker is created closed, opens, becomes half-open and
+	// closes again within the notify interval.
+	if err := b.allow(ctx, target); err != nil {
+		t.Fatalf("allow() = %v, want nil", err)
+	}
+	b.report(ctx, target, errors.New("delivery failed"))
+	now = now.Add(time.Second)
+	if err := b.allow(ctx, target); err != nil {
+		t.Fatalf("allow() of probe = %v, want nil", err)
+	}
+	b.report(ctx, target, nil)
+
+	// The state changes after the first notification are coalesced into a
+	// single one reporting the latest state.
+	time.Sleep(400 * time.Millisecond)
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.states) > 2 {
+		t.Errorf("notified states = %v, want at most 2", recorder.states)
+	}
+	if got := recorder.states[len(recorder.states)-1]; got != config.CircuitBreakerClosed {
+		t.Errorf("last notified state = %v, want %v", got, config.CircuitBreakerClosed)
+	}
+}
+
+func TestCircuitBreakersHalfOpenSingleProbe(t *testing.T) {
+	b := NewCircuitBreakers(nil, 0)
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	target := newCircuitBreakerTarget(&config.CircuitBreaker{FailureThreshold: 1, OpenDurationMillis: 1000})
+	ctx := context.Background()
+
+	if err := b.allow(ctx, target); err != nil {
+		t.Fatalf("allow() = %v, want nil", err)
+	}
+	b.report(ctx, target, errors.New("delivery failed"))
+
+	now = now.Add(time.Second)
+	if err := b.allow(ctx, target); err != nil {
+		t.Fatalf("allow() of probe = %v, want nil", err)
+	}
+	if err := b.allow(ctx, target); err != ErrCircuitOpen {
+		t.Errorf("allow() during probe = %v, want %v", err, ErrCircuitOpen)
+	}
+	// A throttled probe allows another one.
+	b.report(ctx, target, ErrThrottled)
+	if err := b.allow(ctx, target); err != nil {
+		t.Errorf("allow() after throttled probe = %v, want nil", err)
+	}
+}
+
+func TestCircuitBreakersDisabled(t *testing.T) {
+	recorder := &stateRecorder{}
+	b := NewCircuitBreakers(recorder.record, 0)
+	target := newCircuitBreakerTarget(nil)
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		if err := b.allow(ctx, target); err != nil {
+			t.Fatalf("allow() = %v, want nil", err)
+		}
+		b.report(ctx, target, errors.New("delivery failed"))
+	}
+	if len(b.breakers) != 0 {
+		t.Errorf("unexpected circuit breakers: %v", b.breakers)
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if diff := cmp.Diff([]config.CircuitBreakerState(nil), recorder.states); diff != "" {
+		t.Errorf("unexpected notified states (-want, +got) = %v", diff)
+	}
+}
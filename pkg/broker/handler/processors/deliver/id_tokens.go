@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2"
+
+	"github.com/google/knative-gcp/pkg/gclient/idtoken"
+)
+
+// IDTokens provides the ID tokens that authenticate deliveries to targets
+// with an audience. Tokens are cached per audience and refreshed when they
+// expire.
+type IDTokens struct {
+	source idtoken.Source
+
+	mu      sync.Mutex
+	sources map[string]oauth2.TokenSource
+}
+
+// NewIDTokens creates a new IDTokens getting tokens from source.
+func NewIDTokens(source idtoken.Source) *IDTokens {
+	return &IDTokens{
+		source:  source,
+		sources: make(map[string]oauth2.TokenSource),
+	}
+}
+
+// tokenSource returns the caching token source of audience.
+func (t *IDTokens) tokenSource(audience string) (oauth2.TokenSource, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ts, ok := t.sources[audience]; ok {
+		return ts, nil
+	}
+	// The token source outlives the delivery that creates it.
+	ts, err := t.source.TokenSource(context.Background(), audience)
+	if err != nil {
+		return nil, err
+	}
+	ts = oauth2.ReuseTokenSource(nil, ts)
+	t.sources[audience] = ts
+	return ts, nil
+}
+
+// authorize sets the ID token of audience as the bearer token of req.
+func (t *IDTokens) authorize(req *http.Request, audience string) error {
+	ts, err := t.tokenSource(audience)
+	if err != nil {
+		return fmt.Errorf("failed to create ID token source for audience %q: %w", audience, err)
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return fmt.Errorf("failed to get ID token for audience %q: %w", audience, err)
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
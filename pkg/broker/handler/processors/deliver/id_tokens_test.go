@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	idtokentesting "github.com/google/knative-gcp/pkg/gclient/idtoken/testing"
+)
+
+func TestIDTokensAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       idtokentesting.TestSourceData
+		wantHeader []string
+		wantIssued int
+	}{{
+		name:       "tokens are cached",
+		data:       idtokentesting.TestSourceData{Expiry: time.Hour},
+		wantHeader: []string{"Bearer audience/1", "Bearer audience/1"},
+		wantIssued: 1,
+	}, {
+		name: "expired tokens are refreshed",
+		// Tokens expiring within 10 seconds are considered expired.
+		data:       idtokentesting.TestSourceData{Expiry: time.Second},
+		wantHeader: []string{"Bearer audience/1", "Bearer audience/2"},
+		wantIssued: 2,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := idtokentesting.NewTestSource(test.data)
+			tokens := NewIDTokens(source)
+			for _, want := range test.wantHeader {
+				req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
+				if err := tokens.authorize(req, "audience"); err != nil {
+					t.Fatalf("authorize() = %v, want nil", err)
+				}
+				if got := req.Header.Get("Authorization"); got != want {
+					t.Errorf("Authorization header got=%q, want=%q", got, want)
+				}
+			}
+			if got := source.Issued("audience"); got != test.wantIssued {
+				t.Errorf("issued tokens got=%d, want=%d", got, test.wantIssued)
+			}
+			if got := source.Issued("other"); got != 0 {
+				t.Errorf("issued tokens for other audience got=%d, want=0", got)
+			}
+		})
+	}
+}
+
+func TestIDTokensAuthorizeError(t *testing.T) {
+	wantErr := errors.New("no credentials")
+	for _, data := range []idtokentesting.TestSourceData{{TokenSourceErr: wantErr}, {TokenErr: wantErr}} {
+		tokens := NewIDTokens(idtokentesting.NewTestSource(data))
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
+		if err := tokens.authorize(req, "audience"); !errors.Is(err, wantErr) {
+			t.Errorf("authorize() = %v, want %v", err, wantErr)
+		}
+		if got := req.Header.Get("Authorization"); got != "" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+	}
+}
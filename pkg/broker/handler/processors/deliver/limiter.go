@@ -0,0 +1,104 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// ErrThrottled is returned when an event cannot be delivered within the rate
+// limit of its target before its delivery context is done.
+var ErrThrottled = errors.New("delivery throttled")
+
+type targetLimit struct {
+	policy   *config.RateLimit
+	rate     *rate.Limiter
+	inflight chan struct{}
+}
+
+// Limiter enforces the rate limits of targets.
+type Limiter struct {
+	mu     sync.Mutex
+	limits map[config.TargetKey]*targetLimit
+}
+
+// NewLimiter creates a new Limiter.
+func NewLimiter() *Limiter {
+	return &Limiter{
+		limits: make(map[config.TargetKey]*targetLimit),
+	}
+}
+
+// get returns the limit of the target, or nil if the target is not limited.
+// The limit is recreated when the target's rate limit changes.
+func (l *Limiter) get(target *config.Target) *targetLimit {
+	key := *target.Key()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if target.RateLimit == nil {
+		delete(l.limits, key)
+		return nil
+	}
+	if tl, ok := l.limits[key]; ok && proto.Equal(tl.policy, target.RateLimit) {
+		return tl
+	}
+	tl := &targetLimit{policy: target.RateLimit}
+	if eps := target.RateLimit.MaxEventsPerSecond; eps > 0 {
+		tl.rate = rate.NewLimiter(rate.Limit(eps), int(eps))
+	}
+	if c := target.RateLimit.MaxConcurrency; c > 0 {
+		tl.inflight = make(chan struct{}, c)
+	}
+	l.limits[key] = tl
+	return tl
+}
+
+// waitRate waits until an event can be delivered to target within its maximum
+// events per second.
+func (l *Limiter) waitRate(ctx context.Context, target *config.Target) error {
+	tl := l.get(target)
+	if tl == nil || tl.rate == nil {
+		return nil
+	}
+	if err := tl.rate.Wait(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrThrottled, err)
+	}
+	return nil
+}
+
+// acquire waits until a delivery to target can start within its maximum
+// concurrency. The returned function must be called when the delivery is done.
+func (l *Limiter) acquire(ctx context.Context, target *config.Target) (func(), error) {
+	tl := l.get(target)
+	if tl == nil || tl.inflight == nil {
+		return func() {}, nil
+	}
+	select {
+	case tl.inflight <- struct{}{}:
+		return func() { <-tl.inflight }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %v", ErrThrottled, ctx.Err())
+	}
+}
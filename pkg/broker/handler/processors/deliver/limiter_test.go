@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func newLimitedTarget(rateLimit *config.RateLimit) *config.Target {
+	return &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		RateLimit:      rateLimit,
+	}
+}
+
+func TestLimiterConcurrency(t *testing.T) {
+	l := NewLimiter()
+	target := newLimitedTarget(&config.RateLimit{MaxConcurrency: 2})
+	ctx := context.Background()
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := l.acquire(ctx, target)
+		if err != nil {
+			t.Fatalf("acquire() = %v, want nil", err)
+		}
+		releases = append(releases, release)
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(tctx, target); !errors.Is(err, ErrThrottled) {
+		t.Errorf("acquire() over max concurrency = %v, want %v", err, ErrThrottled)
+	}
+
+	releases[0]()
+	tctx, cancel = context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if _, err := l.acquire(tctx, target); err != nil {
+		t.Errorf("acquire() after release = %v, want nil", err)
+	}
+}
+
+func TestLimiterRate(t *testing.T) {
+	l := NewLimiter()
+	target := newLimitedTarget(&config.RateLimit{MaxEventsPerSecond: 2})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := l.waitRate(ctx, target); err != nil {
+			t.Fatalf("waitRate() = %v, want nil", err)
+		}
+	}
+	// The next event can only be delivered in 500ms.
+	tctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if err := l.waitRate(tctx, target); !errors.Is(err, ErrThrottled) {
+		t.Errorf("waitRate() over max events per second = %v, want %v", err, ErrThrottled)
+	}
+}
+
+func TestLimiterUpdate(t *testing.T) {
+	l := NewLimiter()
+	ctx := context.Background()
+	if _, err := l.acquire(ctx, newLimitedTarget(&config.RateLimit{MaxConcurrency: 1})); err != nil {
+		t.Fatalf("acquire() = %v, want nil", err)
+	}
+
+	// A new rate limit resets the in-flight deliveries.
+	target := newLimitedTarget(&config.RateLimit{MaxConcurrency: 2})
+	for i := 0; i < 2; i++ {
+		if _, err := l.acquire(ctx, target); err != nil {
+			t.Fatalf("acquire() after update = %v, want nil", err)
+		}
+	}
+
+	// Without rate limit, deliveries are not limited.
+	target = newLimitedTarget(nil)
+	for i := 0; i < 10; i++ {
+		if _, err := l.acquire(ctx, target); err != nil {
+			t.Fatalf("acquire() without rate limit = %v, want nil", err)
+		}
+		if err := l.waitRate(ctx, target); err != nil {
+			t.Fatalf("waitRate() without rate limit = %v, want nil", err)
+		}
+	}
+}
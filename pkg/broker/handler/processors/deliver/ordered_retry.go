@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deliver
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"go.opencensus.io/trace"
+)
+
+// OrderedRetryClient sends events to retry topics with a Pub/Sub ordering key.
+// The cloudevents Pub/Sub protocol doesn't support ordering keys, so events of
+// CellTenants with ordered delivery are published through it instead.
+type OrderedRetryClient struct {
+	client *pubsub.Client
+
+	mu     sync.Mutex
+	topics map[string]*pubsub.Topic
+}
+
+// NewOrderedRetryClient creates a new OrderedRetryClient.
+func NewOrderedRetryClient(client *pubsub.Client) *OrderedRetryClient {
+	return &OrderedRetryClient{
+		client: client,
+		topics: make(map[string]*pubsub.Topic),
+	}
+}
+
+// Send publishes the event to the topic with the given ordering key.
+func (c *OrderedRetryClient) Send(ctx context.Context, topicID, orderingKey string, e *event.Event) error {
+	dt := extensions.FromSpanContext(trace.FromContext(ctx).SpanContext())
+	msg := new(pubsub.Message)
+	if err := cepubsub.WritePubSubMessage(ctx, binding.ToMessage(e), msg, dt.WriteTransformer()); err != nil {
+		return err
+	}
+	msg.OrderingKey = orderingKey
+
+	topic := c.topic(topicID)
+	if _, err := topic.Publish(ctx, msg).Get(ctx); err != nil {
+		// Publishing is paused for an ordering key after a failure. Resume it so
+		// that the following events with the same key can be retried.
+		topic.ResumePublish(orderingKey)
+		return err
+	}
+	return nil
+}
+
+func (c *OrderedRetryClient) topic(id string) *pubsub.Topic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if topic, ok := c.topics[id]; ok {
+		return topic
+	}
+	topic := c.client.Topic(id)
+	topic.EnableMessageOrdering = true
+	c.topics[id] = topic
+	return topic
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package deliver
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -44,6 +46,10 @@ import (
 
 const defaultEventHopsLimit int32 = 255
 
+// heldEventDelay is the delay before an event whose delivery could not be
+// attempted yet is processed again, when it is not sent to the retry topic.
+const heldEventDelay = time.Second
+
 // Processor delivers events based on the broker/target in the context.
 type Processor struct {
 	processors.BaseProcessor
@@ -62,6 +68,28 @@ type Processor struct {
 	// to the retry topic.
 	DeliverRetryClient ceclient.Client
 
+	// OrderedRetryClient is used instead of DeliverRetryClient to send the
+	// events of CellTenants with ordered delivery to the retry topic, so that
+	// they keep their ordering key. If nil, the ordering key is dropped.
+	OrderedRetryClient *OrderedRetryClient
+
+	// Batcher aggregates the events of targets with a batch policy. If nil,
+	// events are delivered one at a time regardless of the batch policy.
+	Batcher *Batcher
+
+	// Limiter enforces the rate limits of targets. If nil, deliveries are not
+	// limited regardless of the target's rate limit.
+	Limiter *Limiter
+
+	// CircuitBreakers enforces the circuit breakers of targets. If nil,
+	// deliveries are attempted regardless of the target's circuit breaker.
+	CircuitBreakers *CircuitBreakers
+
+	// IDTokens authenticates the deliveries to targets with an audience. If
+	// nil, deliveries are not authenticated regardless of the target's
+	// audience.
+	IDTokens *IDTokens
+
 	// DeliverTimeout is the timeout applied to cancel delivery.
 	// If zero, not additional timeout is applied.
 	DeliverTimeout time.Duration
@@ -121,15 +149,22 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 
 	p.StatsReporter.FinishEventProcessing(ctx)
 
-	dctx := ctx
-	if p.DeliverTimeout > 0 {
-		var cancel context.CancelFunc
-		dctx, cancel = context.WithTimeout(dctx, p.DeliverTimeout)
-		defer cancel()
+	err = p.allow(ctx, target)
+	if err == nil {
+		err = p.deliverWithinLimits(ctx, target, broker, e, hops)
+		p.report(ctx, target, err)
 	}
-
-	if err := p.deliver(dctx, target, broker, eventutil.NewImmutableEventMessage(e), hops); err != nil {
+	if err != nil {
+		if errors.Is(err, ErrThrottled) {
+			p.StatsReporter.ReportThrottledEvent(ctx)
+		}
 		if !p.RetryOnFailure {
+			if errors.Is(err, ErrThrottled) || errors.Is(err, ErrCircuitOpen) {
+				// Nacking a throttled event, or one rejected by an open
+				// circuit breaker, would count as a delivery attempt
+				// although the event was not delivered.
+				return processors.Delay(err, heldEventDelay)
+			}
 			return err
 		}
 
@@ -139,17 +174,94 @@ func (p *Processor) Process(ctx context.Context, e *event.Event) error {
 			"enqueueing for retry",
 		)
 
-		return p.sendToRetryTopic(ctx, target, e)
+		return p.sendToRetryTopic(ctx, broker, target, e)
 	}
 	// For post-delivery processing.
 	return p.Next().Process(ctx, e)
 }
 
+// deliverWithinLimits delivers the event to target, in a batch if the target
+// has a batch policy, within the target's rate limit.
+func (p *Processor) deliverWithinLimits(ctx context.Context, target *config.Target, broker *config.CellTenant, e *event.Event, hops int32) error {
+	if err := p.waitRate(ctx, target); err != nil {
+		return err
+	}
+	if p.Batcher != nil && target.BatchPolicy.GetMaxSize() > 0 {
+		return p.Batcher.add(ctx, target, e, hops, func(ctx context.Context, entries []*batchEntry) error {
+			return p.deliverBatch(ctx, target, broker, entries)
+		})
+	}
+	ctx, cancel := p.withDeliverTimeout(ctx)
+	defer cancel()
+	return p.deliverWithConcurrencyLimit(ctx, target, broker, eventutil.NewImmutableEventMessage(e), hops)
+}
+
+// ErrPaused is returned when an event is not delivered because its target is
+// paused.
+var ErrPaused = errors.New("target paused")
+
+// allow returns ErrPaused if target is paused, or ErrCircuitOpen if the
+// circuit breaker of target is open.
+func (p *Processor) allow(ctx context.Context, target *config.Target) error {
+	if target.Paused {
+		return ErrPaused
+	}
+	if p.CircuitBreakers == nil {
+		return nil
+	}
+	return p.CircuitBreakers.allow(ctx, target)
+}
+
+// report records the outcome of a delivery allowed by the circuit breaker of
+// target.
+func (p *Processor) report(ctx context.Context, target *config.Target, err error) {
+	if p.CircuitBreakers != nil {
+		p.CircuitBreakers.report(ctx, target, err)
+	}
+}
+
+// withDeliverTimeout applies the delivery timeout, if any, to ctx.
+func (p *Processor) withDeliverTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.DeliverTimeout > 0 {
+		return context.WithTimeout(ctx, p.DeliverTimeout)
+	}
+	return ctx, func() {}
+}
+
+// waitRate waits until the event can be delivered within the maximum events
+// per second of target, for at most the delivery timeout.
+func (p *Processor) waitRate(ctx context.Context, target *config.Target) error {
+	if p.Limiter == nil {
+		return nil
+	}
+	ctx, cancel := p.withDeliverTimeout(ctx)
+	defer cancel()
+	return p.Limiter.waitRate(ctx, target)
+}
+
+// acquire waits until a delivery to target can start within its maximum
+// concurrency. The returned function must be called when the delivery is done.
+func (p *Processor) acquire(ctx context.Context, target *config.Target) (func(), error) {
+	if p.Limiter == nil {
+		return func() {}, nil
+	}
+	return p.Limiter.acquire(ctx, target)
+}
+
+func (p *Processor) deliverWithConcurrencyLimit(ctx context.Context, target *config.Target, broker *config.CellTenant, msg binding.Message, hops int32) error {
+	release, err := p.acquire(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return p.deliver(ctx, target, broker, msg, hops)
+}
+
 // deliver delivers msg to target and sends the target's reply to the broker ingress.
 func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *config.CellTenant, msg binding.Message, hops int32) error {
 	startTime := time.Now()
 	// Remove hops from forwarded event.
-	resp, err := p.sendMsg(ctx, target.Address, msg, transformer.DeleteExtension(eventutil.HopsAttribute))
+	resp, err := p.sendMsg(ctx, target.Address, target.Audience, msg, transformer.DeleteExtension(eventutil.HopsAttribute))
 	if err != nil {
 		var result *url.Error
 		if errors.As(err, &result) && result.Timeout() {
@@ -176,6 +288,87 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 	if resp.StatusCode/100 != 2 {
 		return fmt.Errorf("event delivery failed: HTTP status code %d", resp.StatusCode)
 	}
+	return p.forwardReply(ctx, target, broker, resp, hops)
+}
+
+// deliverBatch delivers the events of a batch to target in a single request
+// using the batched content mode. Replies are sent to the broker ingress with
+// the lowest remaining hops of the batch's events.
+func (p *Processor) deliverBatch(ctx context.Context, target *config.Target, broker *config.CellTenant, entries []*batchEntry) error {
+	ctx, cancel := p.withDeliverTimeout(ctx)
+	defer cancel()
+	release, err := p.acquire(ctx, target)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	hops := defaultEventHopsLimit
+	events := make([]*event.Event, 0, len(entries))
+	for _, entry := range entries {
+		// Remove hops from forwarded events, without modifying the original
+		// events as they are sent to the retry queue on failure.
+		e := entry.event.Clone()
+		eventutil.DeleteRemainingHops(ctx, &e)
+		events = append(events, &e)
+		if entry.hops < hops {
+			hops = entry.hops
+		}
+	}
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to encode batch: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.Address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", event.ApplicationCloudEventsBatchJSON)
+	if err := p.authorize(req, target.Audience); err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	resp, err := p.DeliverClient.Do(req)
+	if err != nil {
+		var result *url.Error
+		if errors.As(err, &result) && result.Timeout() {
+			for range entries {
+				p.StatsReporter.ReportEventDispatchTime(ctx, time.Since(startTime))
+			}
+		}
+		return err
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Warn("failed to close response body", zap.Error(err))
+		}
+	}()
+
+	cctx, err := metrics.AddRespStatusCodeTags(ctx, resp.StatusCode)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to add status code tags to context", zap.Error(err))
+	}
+	// Report the dispatch time of each event as well as the size of the batch.
+	dispatchTime := time.Since(startTime)
+	for range entries {
+		p.StatsReporter.ReportEventDispatchTime(cctx, dispatchTime)
+	}
+	p.StatsReporter.ReportBatchSize(cctx, len(entries))
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("batch delivery failed: HTTP status code %d", resp.StatusCode)
+	}
+	return p.forwardReply(ctx, target, broker, resp, hops)
+}
+
+// forwardReply sends the event(s) in the target's response, if any, to the
+// broker ingress, or to the reply address of a channel subscriber.
+func (p *Processor) forwardReply(ctx context.Context, target *config.Target, broker *config.CellTenant, resp *http.Response, hops int32) error {
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), event.ApplicationCloudEventsBatchJSON) {
+		return p.forwardBatchReply(ctx, target, broker, resp, hops)
+	}
 
 	// Pre-check the reply response header, if it's not in structured mode/batched mode or binary mode,
 	// then it's not a CloudEvent, we treat the delivery as successful and ignore the response.
@@ -234,8 +427,12 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 		return nil
 	}
 
+	replyAddress, ok := replyAddress(broker, target)
+	if !ok {
+		return nil
+	}
 	// Attach the previous hops for the reply.
-	replyResp, err := p.sendMsg(ctx, broker.Address, respMsg, eventutil.SetRemainingHopsTransformer(hops))
+	replyResp, err := p.sendMsg(ctx, replyAddress, "", respMsg, eventutil.SetRemainingHopsTransformer(hops))
 	if err != nil {
 		return err
 	}
@@ -245,18 +442,82 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 	return nil
 }
 
-func (p *Processor) sendMsg(ctx context.Context, address string, msg binding.Message, transformers ...binding.Transformer) (*http.Response, error) {
+// forwardBatchReply sends the events of a batched reply to the broker ingress,
+// or to the reply address of a channel subscriber.
+func (p *Processor) forwardBatchReply(ctx context.Context, target *config.Target, broker *config.CellTenant, resp *http.Response, hops int32) error {
+	var events []event.Event
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return fmt.Errorf("Received a malformed batch in reply: %w", err)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	if hops <= 0 {
+		logging.FromContext(ctx).Warn("event has exhausted allowed hops: dropping batched reply",
+			zap.String("target", target.Name),
+			zap.Int32("hops", hops),
+			zap.Int("events", len(events)),
+		)
+		return nil
+	}
+	replyAddress, ok := replyAddress(broker, target)
+	if !ok {
+		return nil
+	}
+	for i := range events {
+		replyResp, err := p.sendMsg(ctx, replyAddress, "", binding.ToMessage(&events[i]), eventutil.SetRemainingHopsTransformer(hops))
+		if err != nil {
+			return err
+		}
+		if err := replyResp.Body.Close(); err != nil {
+			logging.FromContext(ctx).Warn("failed to close reply response body", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// replyAddress returns the address the replies of the target are sent to. The
+// replies of Broker targets go back to the Broker, the replies of Channel
+// targets go to their reply address. It returns false if the replies are
+// dropped because the Channel target has no reply address.
+func replyAddress(cellTenant *config.CellTenant, target *config.Target) (string, bool) {
+	if cellTenant.Type == config.CellTenantType_CHANNEL {
+		return target.ReplyAddress, target.ReplyAddress != ""
+	}
+	return cellTenant.Address, true
+}
+
+// sendMsg sends msg to address, authenticated with an ID token if audience is
+// not empty.
+func (p *Processor) sendMsg(ctx context.Context, address, audience string, msg binding.Message, transformers ...binding.Transformer) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, nil)
 	if err != nil {
 		return nil, err
 	}
+	if err := p.authorize(req, audience); err != nil {
+		return nil, err
+	}
 	if err := cehttp.WriteRequest(ctx, msg, req, transformers...); err != nil {
 		return nil, err
 	}
 	return p.DeliverClient.Do(req)
 }
 
-func (p *Processor) sendToRetryTopic(ctx context.Context, target *config.Target, event *event.Event) error {
+// authorize authenticates req with an ID token if audience is not empty.
+func (p *Processor) authorize(req *http.Request, audience string) error {
+	if audience == "" || p.IDTokens == nil {
+		return nil
+	}
+	return p.IDTokens.authorize(req, audience)
+}
+
+func (p *Processor) sendToRetryTopic(ctx context.Context, broker *config.CellTenant, target *config.Target, event *event.Event) error {
+	if key := eventutil.OrderingKey(event, broker.OrderingKeyExtension); key != "" && p.OrderedRetryClient != nil {
+		if err := p.OrderedRetryClient.Send(ctx, target.RetryQueue.Topic, key, event); err != nil {
+			return fmt.Errorf("failed to send event to retry topic: %w", err)
+		}
+		return nil
+	}
 	pctx := cecontext.WithTopic(ctx, target.RetryQueue.Topic)
 	if err := p.DeliverRetryClient.Send(pctx, *event); err != nil {
 		return fmt.Errorf("failed to send event to retry topic: %w", err)
@@ -19,12 +19,15 @@ package deliver
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -37,6 +40,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	kgcptesting "github.com/google/knative-gcp/pkg/testing"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
@@ -44,11 +48,14 @@ import (
 	"google.golang.org/grpc"
 	"knative.dev/pkg/logging"
 	logtest "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricstest"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	idtokentesting "github.com/google/knative-gcp/pkg/gclient/idtoken/testing"
 	"github.com/google/knative-gcp/pkg/metrics"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
 
@@ -86,6 +93,10 @@ func TestDeliverSuccess(t *testing.T) {
 		wantOrigin *event.Event
 		reply      *event.Event
 		wantReply  *event.Event
+		// channel delivers to a Channel subscriber, which sends its replies
+		// to the reply address instead of the CellTenant's ingress.
+		channel        bool
+		noReplyAddress bool
 	}{{
 		name:       "success",
 		origin:     sampleEvent,
@@ -105,6 +116,24 @@ func TestDeliverSuccess(t *testing.T) {
 		}(),
 		wantOrigin: sampleEvent,
 		reply:      &sampleReply,
+	}, {
+		name:       "channel subscriber success",
+		origin:     sampleEvent,
+		wantOrigin: sampleEvent,
+		reply:      &sampleReply,
+		wantReply: func() *event.Event {
+			copy := sampleReply.Clone()
+			eventutil.UpdateRemainingHops(context.Background(), &copy, defaultEventHopsLimit)
+			return &copy
+		}(),
+		channel: true,
+	}, {
+		name:           "channel subscriber without reply address",
+		origin:         sampleEvent,
+		wantOrigin:     sampleEvent,
+		reply:          &sampleReply,
+		channel:        true,
+		noReplyAddress: true,
 	}}
 
 	for _, tc := range cases {
@@ -137,9 +166,19 @@ func TestDeliverSuccess(t *testing.T) {
 				CellTenantName: "broker",
 				Address:        targetSvr.URL,
 			}
+			ingressAddress := ingressSvr.URL
+			if tc.channel {
+				broker.Type = config.CellTenantType_CHANNEL
+				target.CellTenantType = config.CellTenantType_CHANNEL
+				// Replies must not be sent to the channel itself.
+				ingressAddress = "http://channel.invalid"
+				if !tc.noReplyAddress {
+					target.ReplyAddress = ingressSvr.URL
+				}
+			}
 			testTargets := memory.NewEmptyTargets()
 			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
-				bm.SetAddress(ingressSvr.URL)
+				bm.SetAddress(ingressAddress)
 				bm.UpsertTargets(target)
 			})
 			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
@@ -365,6 +404,492 @@ func TestDeliverFailure(t *testing.T) {
 	}
 }
 
+func TestDeliverFailureRetryWithOrderingKey(t *testing.T) {
+	cases := []struct {
+		name                 string
+		orderingKeyExtension string
+		wantOrderingKey      string
+	}{{
+		name: "ordering disabled",
+	}, {
+		name:                 "ordering enabled",
+		orderingKeyExtension: "partitionkey",
+		wantOrderingKey:      "order-1",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetSvr := httptest.NewServer(&targetWithFailureHandler{t: t, respCode: http.StatusInternalServerError})
+			defer targetSvr.Close()
+
+			srv, c, close := testPubsubClient(ctx, t, "test-project")
+			defer close()
+			if _, err := c.CreateTopic(ctx, "test-retry-topic"); err != nil {
+				t.Fatalf("failed to create test pubsub topc: %v", err)
+			}
+			ps, err := cepubsub.New(ctx, cepubsub.WithClient(c), cepubsub.WithProjectID("test-project"))
+			if err != nil {
+				t.Fatalf("failed to create pubsub protocol: %v", err)
+			}
+			deliverRetryClient, err := ceclient.New(ps)
+			if err != nil {
+				t.Fatalf("failed to create cloudevents client: %v", err)
+			}
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				RetryQueue: &config.Queue{
+					Topic: "test-retry-topic",
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.SetOrderingKeyExtension(tc.orderingKeyExtension)
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient:      http.DefaultClient,
+				Targets:            testTargets,
+				RetryOnFailure:     true,
+				DeliverRetryClient: deliverRetryClient,
+				OrderedRetryClient: NewOrderedRetryClient(c),
+				StatsReporter:      r,
+			}
+
+			origin := newSampleEvent()
+			origin.SetExtension("partitionkey", "order-1")
+			if err := p.Process(ctx, origin); err != nil {
+				t.Fatalf("processing got error=%v", err)
+			}
+
+			msgs := srv.Messages()
+			if len(msgs) != 1 {
+				t.Fatalf("retry messages got=%d, want=1", len(msgs))
+			}
+			if got := msgs[0].OrderingKey; got != tc.wantOrderingKey {
+				t.Errorf("retry message ordering key got=%q, want=%q", got, tc.wantOrderingKey)
+			}
+			got, err := binding.ToEvent(ctx, cepubsub.NewMessage(&pubsub.Message{Data: msgs[0].Data, Attributes: msgs[0].Attributes}))
+			if err != nil {
+				t.Fatalf("failed to convert retry message to event: %v", err)
+			}
+			if diff := cmp.Diff(origin, got); diff != "" {
+				t.Errorf("retry event (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+// blockingHandler blocks requests until release is closed.
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.started <- struct{}{}
+	<-h.release
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func TestDeliverThrottled(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	targetHandler := &blockingHandler{
+		started: make(chan struct{}, 2),
+		release: make(chan struct{}),
+	}
+	targetSvr := httptest.NewServer(targetHandler)
+	defer targetSvr.Close()
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		RateLimit:      &config.RateLimit{MaxConcurrency: 1},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:  http.DefaultClient,
+		Targets:        testTargets,
+		Limiter:        NewLimiter(),
+		DeliverTimeout: 5 * time.Second,
+		StatsReporter:  r,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- p.Process(ctx, newSampleEvent())
+	}()
+	<-targetHandler.started
+
+	// The second delivery cannot start while the first one is in flight.
+	tctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err = p.Process(tctx, newSampleEvent())
+	if !errors.Is(err, ErrThrottled) {
+		t.Errorf("processing got error=%v, want=%v", err, ErrThrottled)
+	}
+	// Throttled events are held rather than nacked.
+	var delayErr *processors.DelayError
+	if !errors.As(err, &delayErr) {
+		t.Errorf("processing got error=%v, want a delay", err)
+	}
+	metricstest.CheckCountData(t, "throttled_event_count", map[string]string{}, 1)
+
+	close(targetHandler.release)
+	if err := <-errs; err != nil {
+		t.Errorf("unexpected error from processing: %v", err)
+	}
+}
+
+func TestDeliverCircuitOpen(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	var requests int32
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		CircuitBreaker: &config.CircuitBreaker{FailureThreshold: 2, OpenDurationMillis: time.Hour.Milliseconds()},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:   http.DefaultClient,
+		Targets:         testTargets,
+		CircuitBreakers: NewCircuitBreakers(nil, 0),
+		StatsReporter:   r,
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := p.Process(ctx, newSampleEvent()); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("processing got error=%v, want a delivery error", err)
+		}
+	}
+	// The circuit breaker is now open, the target is not called anymore.
+	err = p.Process(ctx, newSampleEvent())
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("processing got error=%v, want=%v", err, ErrCircuitOpen)
+	}
+	// Events rejected by the open circuit breaker are held rather than nacked.
+	var delayErr *processors.DelayError
+	if !errors.As(err, &delayErr) {
+		t.Errorf("processing got error=%v, want a delay", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("target received %d requests, want 2", got)
+	}
+}
+
+func TestDeliverPaused(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	var requests int32
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		Paused:         true,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+	}
+
+	if err := p.Process(ctx, newSampleEvent()); !errors.Is(err, ErrPaused) {
+		t.Errorf("processing got error=%v, want=%v", err, ErrPaused)
+	}
+	if got := atomic.LoadInt32(&requests); got != 0 {
+		t.Errorf("target received %d requests, want 0", got)
+	}
+}
+
+func TestDeliverAuthenticated(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	authorization := make(chan string, 1)
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		authorization <- req.Header.Get("Authorization")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.CellTenant{
+		Type:      config.CellTenantType_BROKER,
+		Namespace: "ns",
+		Name:      "broker",
+	}
+	target := &config.Target{
+		Namespace:      "ns",
+		Name:           "target",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "broker",
+		Address:        targetSvr.URL,
+		Audience:       "https://subscriber.example.com",
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		IDTokens:      NewIDTokens(idtokentesting.NewTestSource(idtokentesting.TestSourceData{})),
+		StatsReporter: r,
+	}
+
+	if err := p.Process(ctx, newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got, want := <-authorization, "Bearer https://subscriber.example.com/1"; got != want {
+		t.Errorf("Authorization header got=%q, want=%q", got, want)
+	}
+}
+
+// batchTargetHandler records the batches it receives and replies with the
+// given status code and batch of events.
+type batchTargetHandler struct {
+	t        *testing.T
+	respCode int
+	reply    []*event.Event
+	batches  chan []*event.Event
+}
+
+func (h *batchTargetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if got := req.Header.Get("Content-Type"); got != event.ApplicationCloudEventsBatchJSON {
+		h.t.Errorf("batch received with content type %q, want %q", got, event.ApplicationCloudEventsBatchJSON)
+	}
+	var batch []*event.Event
+	if err := json.NewDecoder(req.Body).Decode(&batch); err != nil {
+		h.t.Errorf("failed to decode received batch: %v", err)
+	}
+	h.batches <- batch
+	if len(h.reply) > 0 {
+		w.Header().Set("Content-Type", event.ApplicationCloudEventsBatchJSON)
+	}
+	w.WriteHeader(h.respCode)
+	if len(h.reply) > 0 {
+		if err := json.NewEncoder(w).Encode(h.reply); err != nil {
+			h.t.Errorf("failed to encode reply batch: %v", err)
+		}
+	}
+}
+
+func TestDeliverBatch(t *testing.T) {
+	sampleReply := newSampleEvent()
+	sampleReply.SetID("reply")
+
+	cases := []struct {
+		name      string
+		respCode  int
+		reply     []*event.Event
+		wantReply bool
+		wantErr   bool
+	}{{
+		name:     "batch delivered",
+		respCode: http.StatusAccepted,
+	}, {
+		name:      "batch delivered with reply",
+		respCode:  http.StatusOK,
+		reply:     []*event.Event{sampleReply},
+		wantReply: true,
+	}, {
+		name:     "batch delivery failure",
+		respCode: http.StatusInternalServerError,
+		wantErr:  true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+			targetHandler := &batchTargetHandler{
+				t:        t,
+				respCode: tc.respCode,
+				reply:    tc.reply,
+				batches:  make(chan []*event.Event, 1),
+			}
+			targetSvr := httptest.NewServer(targetHandler)
+			defer targetSvr.Close()
+			replies := make(chan *event.Event, 1)
+			ingressSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				e, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+				if err != nil {
+					t.Errorf("ingress received message cannot be converted to an event: %v", err)
+				}
+				replies <- e
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer ingressSvr.Close()
+
+			broker := &config.CellTenant{
+				Type:      config.CellTenantType_BROKER,
+				Namespace: "ns",
+				Name:      "broker",
+			}
+			target := &config.Target{
+				Namespace:      "ns",
+				Name:           "target",
+				CellTenantType: config.CellTenantType_BROKER,
+				CellTenantName: "broker",
+				Address:        targetSvr.URL,
+				BatchPolicy: &config.BatchPolicy{
+					MaxSize:         3,
+					MaxLingerMillis: 10000,
+				},
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateCellTenant(broker.Key(), func(bm config.CellTenantMutation) {
+				bm.SetAddress(ingressSvr.URL)
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				Batcher:       NewBatcher(),
+				StatsReporter: r,
+			}
+
+			// The remaining hops of the events are removed before delivery, and
+			// the lowest one is used for the reply.
+			var origins, wantBatch []*event.Event
+			for i, hops := range []int32{10, 5, 8} {
+				origin := newSampleEvent()
+				origin.SetID(fmt.Sprintf("id-%d", i))
+				want := origin.Clone()
+				wantBatch = append(wantBatch, &want)
+				eventutil.UpdateRemainingHops(ctx, origin, hops)
+				origins = append(origins, origin)
+			}
+
+			errs := make(chan error, len(origins))
+			for _, origin := range origins {
+				go func(origin *event.Event) {
+					errs <- p.Process(ctx, origin)
+				}(origin)
+			}
+			for range origins {
+				if err := <-errs; (err != nil) != tc.wantErr {
+					t.Errorf("processing got error=%v, want=%v", err, tc.wantErr)
+				}
+			}
+
+			gotBatch := <-targetHandler.batches
+			sortByID := cmpopts.SortSlices(func(a, b *event.Event) bool { return a.ID() < b.ID() })
+			if diff := cmp.Diff(wantBatch, gotBatch, sortByID); diff != "" {
+				t.Errorf("target received batch (-want,+got): %v", diff)
+			}
+
+			if tc.wantReply {
+				gotReply := <-replies
+				if hops, ok := eventutil.GetRemainingHops(ctx, gotReply); !ok || hops != 4 {
+					t.Errorf("reply remaining hops got=%v, want=4", hops)
+				}
+				eventutil.DeleteRemainingHops(ctx, gotReply)
+				if diff := cmp.Diff(sampleReply, gotReply); diff != "" {
+					t.Errorf("ingress received reply (-want,+got): %v", diff)
+				}
+			}
+		})
+	}
+}
+
 type NoReplyHandler struct{}
 
 func (NoReplyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
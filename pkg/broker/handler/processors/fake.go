@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 )
@@ -34,6 +35,10 @@ type FakeProcessor struct {
 	// OneTimeErr once set will immediately return an error exactly once.
 	OneTimeErr bool
 
+	// OneTimeDelay once set will delay the processing by OneTimeDelay
+	// exactly once.
+	OneTimeDelay time.Duration
+
 	// BlockUntilCancel will block until context gets cancelled only
 	// if no error return is required.
 	BlockUntilCancel bool
@@ -48,8 +53,9 @@ type FakeProcessor struct {
 	// WasCancelled records whether the processing was cancelled.
 	WasCancelled bool
 
-	mux  sync.Mutex
-	once sync.Once
+	mux       sync.Mutex
+	once      sync.Once
+	delayOnce sync.Once
 }
 
 var _ Interface = (*FakeProcessor)(nil)
@@ -75,6 +81,16 @@ func (p *FakeProcessor) Process(ctx context.Context, event *event.Event) error {
 		}
 	}
 
+	if p.OneTimeDelay != 0 {
+		var err error
+		p.delayOnce.Do(func() {
+			err = Delay(errors.New("process delayed"), p.OneTimeDelay)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	if p.BlockUntilCancel {
 		<-ctx.Done()
 		p.WasCancelled = true
@@ -64,15 +64,23 @@ func (p *Processor) Process(ctx context.Context, event *event.Event) error {
 		return nil
 	}
 
+	// Isolated targets receive the events from their dedicated subscription.
+	targets := make([]*config.Target, 0, len(broker.Targets))
+	for _, target := range broker.Targets {
+		if !target.IsIsolated() {
+			targets = append(targets, target)
+		}
+	}
+
 	tc := make(chan *config.Target)
 	go func() {
 		defer close(tc)
-		for _, target := range broker.Targets {
+		for _, target := range targets {
 			tc <- target
 		}
 	}()
 
-	curr := len(broker.Targets)
+	curr := len(targets)
 	if curr > p.MaxConcurrency {
 		curr = p.MaxConcurrency
 	}
@@ -155,6 +155,65 @@ func TestFanoutPartialFailure(t *testing.T) {
 	close(ch)
 }
 
+func TestFanoutSkipsIsolatedTargets(t *testing.T) {
+	ch := make(chan *event.Event, 4)
+	ns, broker := "ns", "broker"
+	bk := config.TestOnlyBrokerKey(ns, broker)
+	shared := &config.Target{
+		Name:           "shared",
+		Namespace:      ns,
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: broker,
+	}
+	isolated := &config.Target{
+		Name:           "isolated",
+		Namespace:      ns,
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: broker,
+		IsolatedQueue:  &config.Queue{Subscription: "sub", State: config.State_READY},
+	}
+	isolatedNotReady := &config.Target{
+		Name:           "isolated-not-ready",
+		Namespace:      ns,
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: broker,
+		IsolatedQueue:  &config.Queue{Subscription: "sub"},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateCellTenant(bk, func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(shared, isolated, isolatedNotReady)
+	})
+	var gotTargets []*config.TargetKey
+	next := &processors.FakeProcessor{
+		PrevEventsCh: ch,
+		InterceptFunc: func(ctx context.Context, e *event.Event) *event.Event {
+			t, _ := handlerctx.GetTargetKey(ctx)
+			gotTargets = append(gotTargets, t)
+			return e
+		},
+	}
+
+	p := &Processor{MaxConcurrency: 1, Targets: testTargets}
+	p.WithNext(next)
+
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+
+	ctx := handlerctx.WithBrokerKey(context.Background(), bk)
+	if err := p.Process(ctx, &e); err != nil {
+		t.Errorf("unexpected error from processing: %v", err)
+	}
+	close(ch)
+
+	// Isolated targets are only skipped once their subscription is ready.
+	wantTargets := []*config.TargetKey{shared.Key(), isolatedNotReady.Key()}
+	if diff := cmp.Diff(wantTargets, gotTargets, diffTargetKeySlice); diff != "" {
+		t.Errorf("got target keys (-want,+got): %v", diff)
+	}
+}
+
 func newTestTargets(key *config.CellTenantKey, num int) config.ReadonlyTargets {
 	targets := memory.NewEmptyTargets()
 	targets.MutateCellTenant(key, func(bm config.CellTenantMutation) {
@@ -18,6 +18,7 @@ package filter
 
 import (
 	"context"
+	"sync"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/extensions"
@@ -39,6 +40,18 @@ type Processor struct {
 
 	// Targets is the targets from config.
 	Targets config.ReadonlyTargets
+
+	// filters holds the parsed filters of the targets, keyed by target key.
+	// A target's filter is parsed again when the target changes in the
+	// targets config.
+	filters sync.Map
+}
+
+// cachedFilter is the parsed filter of a target.
+type cachedFilter struct {
+	// source is the target the filter was parsed from.
+	source *config.Target
+	filter *TargetFilter
 }
 
 var _ processors.Interface = (*Processor)(nil)
@@ -64,17 +77,33 @@ func (p *Processor) Process(ctx context.Context, event *event.Event) error {
 	ctx, span := startSpan(ctx, trigger, event)
 	defer span.End()
 
-	if target.FilterAttributes == nil {
-		return p.Next().Process(ctx, event)
-	}
-
-	if PassFilter(ctx, target.FilterAttributes, event) {
+	if p.targetFilter(ctx, tk, target).Pass(ctx, event) {
 		return p.Next().Process(ctx, event)
 	}
 	logging.FromContext(ctx).Debug("event does not pass filter for target", zap.Any("target", target))
 	return nil
 }
 
+// targetFilter returns the parsed filter of the given target, parsing it if
+// the target changed since its filter was last parsed.
+func (p *Processor) targetFilter(ctx context.Context, key *config.TargetKey, target *config.Target) *TargetFilter {
+	if v, ok := p.filters.Load(*key); ok && v.(*cachedFilter).source == target {
+		return v.(*cachedFilter).filter
+	}
+	// The targets config has changed, drop the filters of the targets which
+	// no longer exist so that they don't pile up.
+	p.filters.Range(func(k, _ interface{}) bool {
+		key := k.(config.TargetKey)
+		if _, ok := p.Targets.GetTargetByKey(&key); !ok {
+			p.filters.Delete(k)
+		}
+		return true
+	})
+	f := NewTargetFilter(ctx, target)
+	p.filters.Store(*key, &cachedFilter{source: target, filter: f})
+	return f
+}
+
 func startSpan(ctx context.Context, trigger types.NamespacedName, event *event.Event) (context.Context, *trace.Span) {
 	var span *trace.Span
 	if dt, ok := extensions.GetDistributedTracingExtension(*event); ok {
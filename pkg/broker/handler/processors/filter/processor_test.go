@@ -26,6 +26,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/google/go-cmp/cmp"
 	"go.opencensus.io/trace"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
@@ -311,6 +312,43 @@ func TestFilterProcessor(t *testing.T) {
 	}
 }
 
+func TestFilterProcessorTargetChanged(t *testing.T) {
+	ctx, testTargets := newTestTargets(nil)
+	tk, _ := handlerctx.GetTargetKey(ctx)
+	target, _ := testTargets.GetTargetByKey(tk)
+	next := &processors.FakeProcessor{}
+	p := &Processor{Targets: testTargets}
+	p.WithNext(next)
+
+	e := event.New()
+	e.SetID("id")
+	e.SetType("foo")
+	for _, tc := range []struct {
+		sql        string
+		shouldPass bool
+	}{
+		{sql: "type = 'foo'", shouldPass: true},
+		// The filter must be parsed again when the target changes.
+		{sql: "type = 'bar'", shouldPass: false},
+	} {
+		changed := proto.Clone(target).(*config.Target)
+		changed.Filters = []*config.Filter{{Sql: tc.sql}}
+		testTargets.MutateCellTenant(changed.Key().ParentKey(), func(bm config.CellTenantMutation) {
+			bm.UpsertTargets(changed)
+		})
+
+		ch := make(chan *event.Event, 1)
+		next.PrevEventsCh = ch
+		if err := p.Process(ctx, &e); err != nil {
+			t.Errorf("unexpected error from processing: %v", err)
+		}
+		close(ch)
+		if gotEvent := <-ch; (gotEvent != nil) != tc.shouldPass {
+			t.Errorf("event passed filter %q got=%v, want=%v", tc.sql, gotEvent != nil, tc.shouldPass)
+		}
+	}
+}
+
 func newTestTargets(filter map[string]string) (context.Context, config.Targets) {
 	testTarget := &config.Target{
 		Name:             "target",
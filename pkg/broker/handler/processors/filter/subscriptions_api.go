@@ -0,0 +1,172 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/broker/cesql"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// TargetFilter holds the attribute filter and the CloudEvents Subscriptions
+// API filters of a target, with their SQL expressions parsed once so that they
+// aren't parsed for every event. It must be rebuilt when the target changes.
+type TargetFilter struct {
+	attributes map[string]string
+	filters    []*parsedFilter
+}
+
+// parsedFilter is a Subscriptions API filter with its SQL expression and
+// nested expressions parsed.
+type parsedFilter struct {
+	filter *config.Filter
+	all    []*parsedFilter
+	any    []*parsedFilter
+	not    *parsedFilter
+	sql    cesql.Expression
+}
+
+// NewTargetFilter parses the filters of the given target. An invalid SQL
+// expression, which the webhook doesn't let through, matches no event.
+func NewTargetFilter(ctx context.Context, target *config.Target) *TargetFilter {
+	return &TargetFilter{
+		attributes: target.FilterAttributes,
+		filters:    parseFilters(ctx, target.Filters),
+	}
+}
+
+func parseFilters(ctx context.Context, filters []*config.Filter) []*parsedFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	parsed := make([]*parsedFilter, 0, len(filters))
+	for _, f := range filters {
+		parsed = append(parsed, parseFilter(ctx, f))
+	}
+	return parsed
+}
+
+func parseFilter(ctx context.Context, f *config.Filter) *parsedFilter {
+	p := &parsedFilter{
+		filter: f,
+		all:    parseFilters(ctx, f.All),
+		any:    parseFilters(ctx, f.Any),
+	}
+	if f.Not != nil {
+		p.not = parseFilter(ctx, f.Not)
+	}
+	if f.Sql != "" {
+		expr, err := cesql.Parse(f.Sql)
+		if err != nil {
+			// The expression is validated by the webhook, so this is not expected.
+			logging.FromContext(ctx).Error("Failed to parse SQL filter", zap.String("sql", f.Sql), zap.Error(err))
+		}
+		p.sql = expr
+	}
+	return p
+}
+
+// Pass checks the given event against both the attribute filter and the
+// CloudEvents Subscriptions API filters of the target.
+func (tf *TargetFilter) Pass(ctx context.Context, event *event.Event) bool {
+	if tf.attributes != nil && !PassFilter(ctx, tf.attributes, event) {
+		return false
+	}
+	return passFilters(ctx, tf.filters, event)
+}
+
+// passFilters checks the given event against the CloudEvents Subscriptions API
+// filters. The event must pass all of them.
+func passFilters(ctx context.Context, filters []*parsedFilter, event *event.Event) bool {
+	for _, f := range filters {
+		if !passFilter(ctx, f, event) {
+			return false
+		}
+	}
+	return true
+}
+
+func passFilter(ctx context.Context, p *parsedFilter, event *event.Event) bool {
+	f := p.filter
+	switch {
+	case len(f.Exact) > 0:
+		return passAttributes(ctx, f.Exact, event, "exact", func(value, filter string) bool { return value == filter })
+	case len(f.Prefix) > 0:
+		return passAttributes(ctx, f.Prefix, event, "prefix", strings.HasPrefix)
+	case len(f.Suffix) > 0:
+		return passAttributes(ctx, f.Suffix, event, "suffix", strings.HasSuffix)
+	case len(p.all) > 0:
+		return passFilters(ctx, p.all, event)
+	case len(p.any) > 0:
+		for _, nested := range p.any {
+			if passFilter(ctx, nested, event) {
+				return true
+			}
+		}
+		return false
+	case p.not != nil:
+		return !passFilter(ctx, p.not, event)
+	case f.Sql != "":
+		return passSQL(ctx, f.Sql, p.sql, event)
+	}
+	// An empty filter matches everything.
+	return true
+}
+
+func passAttributes(ctx context.Context, attrs map[string]string, event *event.Event, dialect string, match func(value, filter string) bool) bool {
+	for k, v := range attrs {
+		value, ok := eventutil.GetAttributeString(event, k)
+		if !ok {
+			logging.FromContext(ctx).Debug("Attribute not found", zap.String("attribute", k))
+			trace.FromContext(ctx).Annotatef(nil, "event missing filter attribute %q", k)
+			return false
+		}
+		if !match(value, v) {
+			logging.FromContext(ctx).Debug("Attribute had non-matching value", zap.String("attribute", k), zap.String("dialect", dialect), zap.String("filter", v), zap.String("received", value))
+			trace.FromContext(ctx).Annotatef(nil, "event attribute %q does not match %s filter value %q", k, dialect, v)
+			return false
+		}
+	}
+	return true
+}
+
+func passSQL(ctx context.Context, sql string, expr cesql.Expression, event *event.Event) bool {
+	if expr == nil {
+		// The expression failed to parse when the filter was built.
+		trace.FromContext(ctx).Annotatef(nil, "invalid SQL filter %q", sql)
+		return false
+	}
+	pass, err := cesql.Matches(expr, event)
+	if err != nil {
+		// Evaluation errors, e.g. a missing attribute, mean no match.
+		logging.FromContext(ctx).Debug("SQL filter evaluation failed", zap.String("sql", sql), zap.Error(err))
+		trace.FromContext(ctx).Annotatef(nil, "event failed SQL filter evaluation: %v", err)
+		return false
+	}
+	if !pass {
+		trace.FromContext(ctx).Annotatef(nil, "event does not match SQL filter %q", sql)
+	}
+	return pass
+}
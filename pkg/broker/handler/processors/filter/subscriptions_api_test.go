@@ -0,0 +1,187 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestTargetFilterFilters(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("//storage.googleapis.com/projects/_/buckets/bucket")
+	e.SetType("com.google.cloud.storage.object.v1.finalized")
+	e.SetSubject("objects/photo.jpg")
+	e.SetExtension("priority", 5)
+
+	tests := []struct {
+		name    string
+		filters []*config.Filter
+		want    bool
+	}{{
+		name: "no filters",
+		want: true,
+	}, {
+		name:    "exact match",
+		filters: []*config.Filter{{Exact: map[string]string{"type": "com.google.cloud.storage.object.v1.finalized"}}},
+		want:    true,
+	}, {
+		name:    "exact mismatch",
+		filters: []*config.Filter{{Exact: map[string]string{"type": "com.google.cloud.storage.object.v1.deleted"}}},
+		want:    false,
+	}, {
+		name:    "exact missing attribute",
+		filters: []*config.Filter{{Exact: map[string]string{"foo": "bar"}}},
+		want:    false,
+	}, {
+		name:    "prefix match",
+		filters: []*config.Filter{{Prefix: map[string]string{"type": "com.google.cloud.storage."}}},
+		want:    true,
+	}, {
+		name:    "prefix mismatch",
+		filters: []*config.Filter{{Prefix: map[string]string{"type": "com.google.cloud.pubsub."}}},
+		want:    false,
+	}, {
+		name:    "suffix match",
+		filters: []*config.Filter{{Suffix: map[string]string{"subject": ".jpg"}}},
+		want:    true,
+	}, {
+		name:    "suffix mismatch",
+		filters: []*config.Filter{{Suffix: map[string]string{"subject": ".png"}}},
+		want:    false,
+	}, {
+		name:    "extension exact match",
+		filters: []*config.Filter{{Exact: map[string]string{"priority": "5"}}},
+		want:    true,
+	}, {
+		name: "all match",
+		filters: []*config.Filter{{All: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.google.cloud.storage."}},
+			{Suffix: map[string]string{"subject": ".jpg"}},
+		}}},
+		want: true,
+	}, {
+		name: "all with one mismatch",
+		filters: []*config.Filter{{All: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.google.cloud.storage."}},
+			{Suffix: map[string]string{"subject": ".png"}},
+		}}},
+		want: false,
+	}, {
+		name: "any with one match",
+		filters: []*config.Filter{{Any: []*config.Filter{
+			{Suffix: map[string]string{"subject": ".png"}},
+			{Suffix: map[string]string{"subject": ".jpg"}},
+		}}},
+		want: true,
+	}, {
+		name: "any without match",
+		filters: []*config.Filter{{Any: []*config.Filter{
+			{Suffix: map[string]string{"subject": ".png"}},
+			{Suffix: map[string]string{"subject": ".gif"}},
+		}}},
+		want: false,
+	}, {
+		name:    "not",
+		filters: []*config.Filter{{Not: &config.Filter{Suffix: map[string]string{"subject": ".png"}}}},
+		want:    true,
+	}, {
+		name:    "sql match",
+		filters: []*config.Filter{{Sql: "type LIKE 'com.google.cloud.storage.%' AND priority > 3"}},
+		want:    true,
+	}, {
+		name:    "sql mismatch",
+		filters: []*config.Filter{{Sql: "priority > 10"}},
+		want:    false,
+	}, {
+		name:    "sql missing attribute",
+		filters: []*config.Filter{{Sql: "foo = 'bar'"}},
+		want:    false,
+	}, {
+		name:    "sql invalid expression",
+		filters: []*config.Filter{{Sql: "type ="}},
+		want:    false,
+	}, {
+		name: "multiple filters must all pass",
+		filters: []*config.Filter{
+			{Prefix: map[string]string{"type": "com.google.cloud.storage."}},
+			{Sql: "priority > 10"},
+		},
+		want: false,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if got := NewTargetFilter(ctx, &config.Target{Filters: tc.filters}).Pass(ctx, &e); got != tc.want {
+				t.Errorf("TargetFilter.Pass got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTargetFilter(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+
+	tests := []struct {
+		name   string
+		target *config.Target
+		want   bool
+	}{{
+		name:   "no filters",
+		target: &config.Target{},
+		want:   true,
+	}, {
+		name: "attributes and filters pass",
+		target: &config.Target{
+			FilterAttributes: map[string]string{"type": "type"},
+			Filters:          []*config.Filter{{Exact: map[string]string{"source": "source"}}},
+		},
+		want: true,
+	}, {
+		name: "attributes fail",
+		target: &config.Target{
+			FilterAttributes: map[string]string{"type": "other"},
+			Filters:          []*config.Filter{{Exact: map[string]string{"source": "source"}}},
+		},
+		want: false,
+	}, {
+		name: "filters fail",
+		target: &config.Target{
+			FilterAttributes: map[string]string{"type": "type"},
+			Filters:          []*config.Filter{{Exact: map[string]string{"source": "other"}}},
+		},
+		want: false,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if got := NewTargetFilter(ctx, tc.target).Pass(ctx, &e); got != tc.want {
+				t.Errorf("TargetFilter.Pass got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sync"
 
@@ -26,6 +27,7 @@ import (
 
 	"cloud.google.com/go/pubsub"
 
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
@@ -48,6 +50,17 @@ type RetryPool struct {
 	// And we can set target address dynamically.
 	deliverClient *http.Client
 	statsReporter *metrics.DeliveryReporter
+	// For aggregating the events of targets with a batch policy.
+	batcher *deliver.Batcher
+	// For enforcing the rate limits of targets.
+	limiter *deliver.Limiter
+	// For enforcing the circuit breakers of targets.
+	circuitBreakers *deliver.CircuitBreakers
+	// For authenticating deliveries to targets with an audience.
+	idTokens *deliver.IDTokens
+	// deadLetterPool delivers events from the dead letter queues of targets
+	// whose dead letter sink is not a Pubsub topic.
+	deadLetterPool *deadLetterPool
 }
 
 type retryHandlerCache struct {
@@ -84,14 +97,24 @@ func NewRetryPool(
 	if err != nil {
 		return nil, err
 	}
+	// The delivery timeout can't be shorter than the Triggers' batch linger
+	// times allow.
+	if options.TimeoutPerEvent < brokerv1beta1.MinDeliveryTimeout {
+		return nil, fmt.Errorf("timeout per event cannot be lower than %v", brokerv1beta1.MinDeliveryTimeout)
+	}
 
 	p := &RetryPool{
-		targets:       targets,
-		options:       options,
-		pool:          &syncMapTargetKey{},
-		pubsubClient:  pubsubClient,
-		deliverClient: deliverClient,
-		statsReporter: statsReporter,
+		targets:         targets,
+		options:         options,
+		pool:            &syncMapTargetKey{},
+		pubsubClient:    pubsubClient,
+		deliverClient:   deliverClient,
+		statsReporter:   statsReporter,
+		batcher:         deliver.NewBatcher(),
+		limiter:         deliver.NewLimiter(),
+		circuitBreakers: deliver.NewCircuitBreakers(recordCircuitBreakerState(pubsubClient), circuitBreakerRecordInterval),
+		idTokens:        deliver.NewIDTokens(options.IDTokenSource),
+		deadLetterPool:  newDeadLetterPool(targets, pubsubClient, deliverClient, statsReporter, options),
 	}
 	return p, nil
 }
@@ -104,8 +127,9 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 	}
 
 	p.pool.Range(func(key config.TargetKey, value *retryHandlerCache) bool {
-		// Each target represents a trigger.
-		if _, ok := p.targets.GetTargetByKey(&key); !ok {
+		// Each target represents a trigger. The events of paused targets
+		// accumulate in their retry subscription.
+		if t, ok := p.targets.GetTargetByKey(&key); !ok || t.Paused {
 			value.Stop()
 			p.pool.Delete(key)
 		}
@@ -123,9 +147,9 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 			p.pool.Delete(*t.Key())
 		}
 
-		// Don't start the handler if the target is not ready.
+		// Don't start the handler if the target is not ready or paused.
 		// The retry topic/sub might not be ready at this point.
-		if t.State != config.State_READY {
+		if t.State != config.State_READY || t.Paused {
 			return true
 		}
 
@@ -137,9 +161,13 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 			processors.ChainProcessors(
 				&filter.Processor{Targets: p.targets},
 				&deliver.Processor{
-					DeliverClient: p.deliverClient,
-					Targets:       p.targets,
-					StatsReporter: p.statsReporter,
+					DeliverClient:   p.deliverClient,
+					Targets:         p.targets,
+					Batcher:         p.batcher,
+					Limiter:         p.limiter,
+					CircuitBreakers: p.circuitBreakers,
+					IDTokens:        p.idTokens,
+					StatsReporter:   p.statsReporter,
 				},
 			),
 			p.options.TimeoutPerEvent,
@@ -171,6 +199,7 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 		return true
 	})
 
+	p.deadLetterPool.SyncOnce(ctx)
 	return nil
 }
 
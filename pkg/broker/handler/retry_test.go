@@ -24,6 +24,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
@@ -110,6 +111,51 @@ func TestRetryWatchAndSync(t *testing.T) {
 		assertRetryHandlers(t, syncPool, helper.Targets)
 	})
 
+	t.Run("handler stopped for paused target", func(t *testing.T) {
+		for _, bt := range bs[2].Targets {
+			paused := proto.Clone(bt).(*config.Target)
+			paused.Paused = true
+			helper.Targets.MutateCellTenant(bs[2].Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(paused)
+			})
+		}
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertRetryHandlers(t, syncPool, helper.Targets)
+	})
+
+	t.Run("handler restarted for resumed target", func(t *testing.T) {
+		for _, bt := range bs[2].Targets {
+			resumed := proto.Clone(bt).(*config.Target)
+			resumed.Paused = false
+			helper.Targets.MutateCellTenant(bs[2].Key(), func(bm config.CellTenantMutation) {
+				bm.UpsertTargets(resumed)
+			})
+		}
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertRetryHandlers(t, syncPool, helper.Targets)
+	})
+
+	t.Run("dead letter handler created for target with dead letter queue", func(t *testing.T) {
+		target := helper.GenerateTarget(ctx, t, bs[3].Key(), nil)
+		target.DeadLetterQueue = &config.Queue{
+			Topic:        "dlq-topic-" + target.Name,
+			Subscription: "dlq-sub-" + target.Name,
+		}
+		target.DeadLetterAddress = "http://dead-letter.example.com"
+		helper.Targets.MutateCellTenant(bs[3].Key(), func(bm config.CellTenantMutation) {
+			bm.UpsertTargets(target)
+		})
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertRetryHandlers(t, syncPool, helper.Targets)
+		assertDeadLetterHandlers(t, syncPool, helper.Targets)
+	})
+
 	t.Run("deleting all brokers with their targets", func(t *testing.T) {
 		// clean up all brokers
 		for _, b := range bs {
@@ -119,6 +165,7 @@ func TestRetryWatchAndSync(t *testing.T) {
 		// Wait a short period for the handlers to be updated.
 		<-time.After(time.Second)
 		assertRetryHandlers(t, syncPool, helper.Targets)
+		assertDeadLetterHandlers(t, syncPool, helper.Targets)
 	})
 }
 
@@ -332,7 +379,7 @@ func assertRetryHandlers(t *testing.T, p *RetryPool, targets config.Targets) {
 	})
 
 	targets.RangeAllTargets(func(t *config.Target) bool {
-		if t.State == config.State_READY {
+		if t.State == config.State_READY && !t.Paused {
 			wantHandlers[*t.Key()] = true
 		}
 		return true
@@ -343,6 +390,28 @@ func assertRetryHandlers(t *testing.T, p *RetryPool, targets config.Targets) {
 	}
 }
 
+func assertDeadLetterHandlers(t *testing.T, p *RetryPool, targets config.Targets) {
+	t.Helper()
+	gotHandlers := make(map[config.TargetKey]bool)
+	wantHandlers := make(map[config.TargetKey]bool)
+
+	p.deadLetterPool.pool.Range(func(key, _ interface{}) bool {
+		gotHandlers[key.(config.TargetKey)] = true
+		return true
+	})
+
+	targets.RangeAllTargets(func(t *config.Target) bool {
+		if t.State == config.State_READY && hasDeadLetterQueue(t) {
+			wantHandlers[*t.Key()] = true
+		}
+		return true
+	})
+
+	if diff := cmp.Diff(wantHandlers, gotHandlers); diff != "" {
+		t.Errorf("dead letter handlers map (-want,+got): %v", diff)
+	}
+}
+
 func genTestEvent(subject, t, id, source string) event.Event {
 	e := event.New()
 	e.SetSubject(subject)
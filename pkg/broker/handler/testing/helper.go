@@ -291,6 +291,35 @@ func (h *Helper) RenewTarget(ctx context.Context, t *testing.T, targetKey *confi
 	return target
 }
 
+// IsolateTarget creates a dedicated subscription for the target on the decouple
+// topic of its broker and makes the target isolated.
+func (h *Helper) IsolateTarget(ctx context.Context, t *testing.T, targetKey *config.TargetKey) *config.Target {
+	t.Helper()
+	target, ok := h.Targets.GetTargetByKey(targetKey)
+	if !ok {
+		t.Fatalf("target with key %q doesn't exist", targetKey)
+	}
+	b, ok := h.Targets.GetCellTenantByKey(targetKey.ParentKey())
+	if !ok {
+		t.Fatalf("broker with key %q doesn't exist", targetKey.ParentKey())
+	}
+
+	sub := "isolated-sub-" + uuid.New().String()
+	if _, err := h.PubsubClient.CreateSubscription(ctx, sub, pubsub.SubscriptionConfig{Topic: h.PubsubClient.Topic(b.DecoupleQueue.Topic)}); err != nil {
+		t.Fatalf("failed to create test target isolated subscription: %v", err)
+	}
+
+	target.IsolatedQueue = &config.Queue{
+		Topic:        b.DecoupleQueue.Topic,
+		Subscription: sub,
+		State:        config.State_READY,
+	}
+	h.Targets.MutateCellTenant(targetKey.ParentKey(), func(bm config.CellTenantMutation) {
+		bm.UpsertTargets(target)
+	})
+	return target
+}
+
 // DeleteTarget deletes a target and test resources used by it.
 func (h *Helper) DeleteTarget(ctx context.Context, t *testing.T, targetKey *config.TargetKey) {
 	t.Helper()
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/tokenreview"
+)
+
+// Authorizer decides whether a request is allowed to publish events to a broker.
+type Authorizer interface {
+	// Authorize returns nil if the request is allowed to publish to the broker, ErrUnauthenticated if the request
+	// doesn't carry a valid token and ErrForbidden if the caller is not allowed to publish to the broker.
+	Authorize(ctx context.Context, broker *config.CellTenantKey, request *nethttp.Request) error
+}
+
+// NewTokenReviewAuthorizer creates a new tokenReviewAuthorizer.
+func NewTokenReviewAuthorizer(brokerConfig config.ReadonlyTargets, kubeClient kubernetes.Interface) *tokenReviewAuthorizer {
+	return &tokenReviewAuthorizer{
+		brokerConfig: brokerConfig,
+		reviewer:     tokenreview.NewReviewer(kubeClient),
+	}
+}
+
+// tokenReviewAuthorizer implements Authorizer. It authenticates the Kubernetes service account token sent as a
+// bearer token with a TokenReview and checks the service account against the ingress policy of the broker. Brokers
+// without an ingress policy are open to anyone who can reach the ingress.
+type tokenReviewAuthorizer struct {
+	// brokerConfig holds the ingress policies of all brokers.
+	brokerConfig config.ReadonlyTargets
+	reviewer     *tokenreview.Reviewer
+}
+
+// Authorize implements Authorizer.
+func (a *tokenReviewAuthorizer) Authorize(ctx context.Context, broker *config.CellTenantKey, request *nethttp.Request) error {
+	tenant, ok := a.brokerConfig.GetCellTenantByKey(broker)
+	if !ok {
+		// The broker is unknown, the decouple sink will report it as not found.
+		return nil
+	}
+	policy := tenant.GetIngressPolicy()
+	if len(policy.GetAllowedServiceAccounts()) == 0 {
+		return nil
+	}
+
+	token := bearerToken(request)
+	if token == "" {
+		return fmt.Errorf("%w: missing bearer token", ErrUnauthenticated)
+	}
+	username, err := a.reviewer.Review(ctx, token, policy.GetAudience())
+	if errors.Is(err, tokenreview.ErrTooManyReviews) {
+		return fmt.Errorf("%w: %v", ErrTooManyRequests, err)
+	}
+	if err != nil {
+		return err
+	}
+	if username == "" {
+		return fmt.Errorf("%w: invalid bearer token", ErrUnauthenticated)
+	}
+	if !allowed(policy.GetAllowedServiceAccounts(), username) {
+		return fmt.Errorf("%w: %s is not allowed to publish to broker %s", ErrForbidden, username, broker)
+	}
+	return nil
+}
+
+// bearerToken returns the bearer token of the request, or an empty string if there is none.
+func bearerToken(request *nethttp.Request) string {
+	const prefix = "Bearer "
+	auth := request.Header.Get("Authorization")
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(auth[len(prefix):])
+}
+
+// allowed returns true if the username is one of the allowed service accounts. Service accounts are in the form
+// "namespace/name", where name "*" matches all the service accounts of the namespace.
+func allowed(serviceAccounts []string, username string) bool {
+	namespace, name, ok := tokenreview.ServiceAccount(username)
+	if !ok {
+		return false
+	}
+	for _, sa := range serviceAccounts {
+		if sa == namespace+"/"+name || sa == namespace+"/*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+)
+
+// tokenUsers maps the valid tokens to their users.
+var tokenUsers = map[string]string{
+	"publisher-token": "system:serviceaccount:ns1:publisher",
+	"other-token":     "system:serviceaccount:ns1:other",
+	"ns2-token":       "system:serviceaccount:ns2:any",
+	"user-token":      "alice@example.com",
+}
+
+func TestTokenReviewAuthorizer(t *testing.T) {
+	targets := memory.NewTargets(&config.TargetsConfig{
+		CellTenants: map[string]*config.CellTenant{
+			"ns1/open": {
+				Type:      config.CellTenantType_BROKER,
+				Name:      "open",
+				Namespace: "ns1",
+			},
+			"ns1/restricted": {
+				Type:      config.CellTenantType_BROKER,
+				Name:      "restricted",
+				Namespace: "ns1",
+				IngressPolicy: &config.IngressPolicy{
+					AllowedServiceAccounts: []string{"ns1/publisher", "ns2/*"},
+					Audience:               "broker-ingress",
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name   string
+		broker string
+		header string
+		want   error
+	}{{
+		name:   "unknown broker",
+		broker: "ns1/unknown",
+	}, {
+		name:   "broker without policy",
+		broker: "ns1/open",
+	}, {
+		name:   "missing token",
+		broker: "ns1/restricted",
+		want:   ErrUnauthenticated,
+	}, {
+		name:   "not a bearer token",
+		broker: "ns1/restricted",
+		header: "Basic publisher-token",
+		want:   ErrUnauthenticated,
+	}, {
+		name:   "invalid token",
+		broker: "ns1/restricted",
+		header: "Bearer invalid-token",
+		want:   ErrUnauthenticated,
+	}, {
+		name:   "allowed service account",
+		broker: "ns1/restricted",
+		header: "Bearer publisher-token",
+	}, {
+		name:   "allowed namespace",
+		broker: "ns1/restricted",
+		header: "bearer ns2-token",
+	}, {
+		name:   "service account not allowed",
+		broker: "ns1/restricted",
+		header: "Bearer other-token",
+		want:   ErrForbidden,
+	}, {
+		name:   "not a service account",
+		broker: "ns1/restricted",
+		header: "Bearer user-token",
+		want:   ErrForbidden,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			client.PrependReactor("create", "tokenreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+				tr := action.(clientgotesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				if len(tr.Spec.Audiences) != 1 || tr.Spec.Audiences[0] != "broker-ingress" {
+					t.Errorf("Unexpected TokenReview audiences: %v", tr.Spec.Audiences)
+				}
+				if user, ok := tokenUsers[tr.Spec.Token]; ok {
+					tr.Status = authenticationv1.TokenReviewStatus{
+						Authenticated: true,
+						User:          authenticationv1.UserInfo{Username: user},
+					}
+				}
+				return true, tr, nil
+			})
+			a := NewTokenReviewAuthorizer(targets, client)
+
+			broker, err := config.CellTenantKeyFromPersistenceString("/" + test.broker)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, _ := nethttp.NewRequest(nethttp.MethodPost, "/"+test.broker, nil)
+			if test.header != "" {
+				req.Header.Set("Authorization", test.header)
+			}
+			if err := a.Authorize(context.Background(), broker, req); !errors.Is(err, test.want) {
+				t.Errorf("Authorize() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
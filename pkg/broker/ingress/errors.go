@@ -28,3 +28,14 @@ var ErrIncomplete = errors.New("incomplete config")
 
 // ErrNotReady is the error when a broker is not ready.
 var ErrNotReady = errors.New("not ready")
+
+// ErrUnauthenticated is the error when a request to a broker that restricts its publishers doesn't carry a valid
+// Kubernetes service account token.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ErrForbidden is the error when the service account of a request is not allowed to publish to a broker.
+var ErrForbidden = errors.New("forbidden")
+
+// ErrTooManyRequests is the error when a request to a broker that restricts its publishers cannot be authenticated
+// because too many tokens are being reviewed.
+var ErrTooManyRequests = errors.New("too many requests")
@@ -73,7 +73,10 @@ var HandlerSet wire.ProviderSet = wire.NewSet(
 	wire.Bind(new(HttpMessageReceiver), new(*kncloudevents.HTTPMessageReceiver)),
 	NewMultiTopicDecoupleSink,
 	wire.Bind(new(DecoupleSink), new(*multiTopicDecoupleSink)),
+	NewTokenReviewAuthorizer,
+	wire.Bind(new(Authorizer), new(*tokenReviewAuthorizer)),
 	clients.NewPubsubClient,
+	clients.NewKubeClient,
 	metrics.NewIngressReporter,
 )
 
@@ -94,16 +97,20 @@ type Handler struct {
 	httpReceiver HttpMessageReceiver
 	// decouple is the client to send events to a decouple sink.
 	decouple DecoupleSink
-	logger   *zap.Logger
-	reporter *metrics.IngressReporter
-	authType authcheck.AuthType
+	// authorizer checks that the sender of a request is allowed to publish to the broker. Nil means that anyone can
+	// publish.
+	authorizer Authorizer
+	logger     *zap.Logger
+	reporter   *metrics.IngressReporter
+	authType   authcheck.AuthType
 }
 
 // NewHandler creates a new ingress handler.
-func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, reporter *metrics.IngressReporter, authType authcheck.AuthType) *Handler {
+func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, authorizer Authorizer, reporter *metrics.IngressReporter, authType authcheck.AuthType) *Handler {
 	return &Handler{
 		httpReceiver: httpReceiver,
 		decouple:     decouple,
+		authorizer:   authorizer,
 		reporter:     reporter,
 		logger:       logging.FromContext(ctx),
 		authType:     authType,
@@ -118,8 +125,9 @@ func (h *Handler) Start(ctx context.Context) error {
 // ServeHTTP implements net/http Handler interface method.
 // 1. Performs basic validation of the request.
 // 2. Parse request URL to get namespace and broker.
-// 3. Convert request to event.
-// 4. Send event to decouple sink.
+// 3. Check that the sender is allowed to publish to the broker.
+// 4. Convert request to event.
+// 5. Send event to decouple sink.
 func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
 	ctx := request.Context()
 	ctx = logging.WithLogger(ctx, h.logger)
@@ -145,6 +153,24 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 	ctx = logging.With(ctx, zap.Stringer("broker", broker))
 	ctx = metricskey.WithResource(ctx, broker.MetricsResource())
 
+	if h.authorizer != nil {
+		if err := h.authorizer.Authorize(ctx, broker, request); err != nil {
+			logging.FromContext(ctx).Debug("Unauthorized request", zap.Error(err))
+			httpStatus := nethttp.StatusInternalServerError
+			switch {
+			case errors.Is(err, ErrUnauthenticated):
+				httpStatus = nethttp.StatusUnauthorized
+			case errors.Is(err, ErrForbidden):
+				httpStatus = nethttp.StatusForbidden
+			case errors.Is(err, ErrTooManyRequests):
+				httpStatus = nethttp.StatusTooManyRequests
+			}
+			nethttp.Error(response, err.Error(), httpStatus)
+			h.reportMetrics(ctx, "_unauthorized_", httpStatus)
+			return
+		}
+	}
+
 	event, err := h.toEvent(ctx, request)
 	if err != nil {
 		httpStatus := nethttp.StatusBadRequest
@@ -130,6 +130,7 @@ type testCase struct {
 	// additional assertions on the output event.
 	eventAssertions []eventAssertion
 	decouple        DecoupleSink
+	authorizer      Authorizer
 	contentLength   *int64
 	timeout         time.Duration
 }
@@ -140,6 +141,14 @@ func (m *fakeOverloadedDecoupleSink) Send(_ context.Context, _ *config.CellTenan
 	return bundler.ErrOverflow
 }
 
+type fakeAuthorizer struct {
+	err error
+}
+
+func (a *fakeAuthorizer) Authorize(_ context.Context, _ *config.CellTenantKey, _ *nethttp.Request) error {
+	return a.err
+}
+
 func TestHandler(t *testing.T) {
 	tests := []testCase{
 		{
@@ -311,6 +320,51 @@ func TestHandler(t *testing.T) {
 			},
 			decouple: &fakeOverloadedDecoupleSink{},
 		},
+		{
+			name:       "authorized",
+			path:       "/ns1/broker1",
+			event:      createTestEvent("test-event"),
+			authorizer: &fakeAuthorizer{},
+			wantCode:   nethttp.StatusAccepted,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "202",
+				metricskey.LabelResponseCodeClass: "2xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+			wantEventCount: 1,
+		},
+		{
+			name:           "unauthenticated",
+			path:           "/ns1/broker1",
+			event:          createTestEvent("test-event"),
+			authorizer:     &fakeAuthorizer{err: fmt.Errorf("%w: missing bearer token", ErrUnauthenticated)},
+			wantCode:       nethttp.StatusUnauthorized,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         "_unauthorized_",
+				metricskey.LabelResponseCode:      "401",
+				metricskey.LabelResponseCodeClass: "4xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+		},
+		{
+			name:           "forbidden",
+			path:           "/ns1/broker1",
+			event:          createTestEvent("test-event"),
+			authorizer:     &fakeAuthorizer{err: fmt.Errorf("%w: not allowed", ErrForbidden)},
+			wantCode:       nethttp.StatusForbidden,
+			wantEventCount: 1,
+			wantMetricTags: map[string]string{
+				metricskey.LabelEventType:         "_unauthorized_",
+				metricskey.LabelResponseCode:      "403",
+				metricskey.LabelResponseCodeClass: "4xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+		},
 	}
 
 	client := nethttp.Client{}
@@ -334,7 +388,7 @@ func TestHandler(t *testing.T) {
 				decouple = NewMultiTopicDecoupleSink(ctx, memory.NewTargets(brokerConfig), createPubsubClient(ctx, t, psSrv), pubsub.DefaultPublishSettings)
 			}
 
-			url := createAndStartIngress(ctx, t, psSrv, decouple)
+			url := createAndStartIngress(ctx, t, psSrv, decouple, tc.authorizer)
 			rec := setupTestReceiver(ctx, t, psSrv)
 			req := createRequest(tc, url)
 			if tc.contentLength != nil {
@@ -414,7 +468,7 @@ func runIngressHandlerBenchmark(b *testing.B, eventSize int, targetCounts int) {
 	if err != nil {
 		b.Fatal(err)
 	}
-	h := NewHandler(ctx, nil, decouple, statsReporter, "")
+	h := NewHandler(ctx, nil, decouple, nil, statsReporter, "")
 
 	if _, err := psClient.CreateTopic(ctx, topicID); err != nil {
 		b.Fatal(err)
@@ -516,13 +570,13 @@ func setupTestReceiver(ctx context.Context, t testing.TB, psSrv *pstest.Server)
 }
 
 // createAndStartIngress creates an ingress and calls its Start() method in a goroutine.
-func createAndStartIngress(ctx context.Context, t testing.TB, psSrv *pstest.Server, decouple DecoupleSink) string {
+func createAndStartIngress(ctx context.Context, t testing.TB, psSrv *pstest.Server, decouple DecoupleSink, authorizer Authorizer) string {
 	receiver := &testHttpMessageReceiver{urlCh: make(chan string)}
 	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
 	if err != nil {
 		t.Fatal(err)
 	}
-	h := NewHandler(ctx, receiver, decouple, statsReporter, "")
+	h := NewHandler(ctx, receiver, decouple, authorizer, statsReporter, "")
 
 	errCh := make(chan error, 1)
 	go func() {
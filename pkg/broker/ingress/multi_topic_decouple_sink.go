@@ -32,6 +32,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
 	"github.com/google/knative-gcp/pkg/logging"
 )
@@ -49,9 +50,10 @@ func NewMultiTopicDecoupleSink(
 		pubsub:          client,
 		publishSettings: publishSettings,
 		brokerConfig:    brokerConfig,
+		targetIndex:     newTargetIndex(brokerConfig),
 		// TODO(#1118): remove Topic when broker config is removed
 		topics: make(map[config.CellTenantKey]*pubsub.Topic),
-		// TODO(#1804): remove this field when enabling the feature by default.
+		// TODO(#1804): remove this field when the feature can no longer be disabled.
 		enableEventFiltering: enableEventFilterFunc(),
 	}
 }
@@ -68,7 +70,9 @@ type multiTopicDecoupleSink struct {
 	// brokerConfig holds configurations for all brokers. It's a view of a configmap populated by
 	// the broker controller.
 	brokerConfig config.ReadonlyTargets
-	// TODO(#1804): remove this field when enabling the feature by default.
+	// targetIndex indexes the targets of each broker by their filters.
+	targetIndex *targetIndex
+	// TODO(#1804): remove this field when the feature can no longer be disabled.
 	enableEventFiltering bool
 }
 
@@ -87,8 +91,8 @@ func (m *multiTopicDecoupleSink) Send(ctx context.Context, broker *config.CellTe
 
 	// Check to see if there are any triggers interested in this event. If not, no need to send this
 	// to the decouple topic.
-	// TODO(#1804): remove first check when enabling the feature by default.
-	if m.enableEventFiltering && !m.hasTrigger(ctx, &event) {
+	// TODO(#1804): remove first check when the feature can no longer be disabled.
+	if m.enableEventFiltering && !m.hasTrigger(ctx, broker, &event) {
 		logging.FromContext(ctx).Debug("Filtering target-less event at ingress", zap.String("Eventid", event.ID()))
 		return nil
 	}
@@ -99,38 +103,52 @@ func (m *multiTopicDecoupleSink) Send(ctx context.Context, broker *config.CellTe
 		return err
 	}
 
+	if topic.EnableMessageOrdering {
+		msg.OrderingKey = m.orderingKey(broker, &event)
+	}
+
 	_, err = topic.Publish(ctx, msg).Get(ctx)
+	if err != nil && msg.OrderingKey != "" {
+		// Publishing is paused for an ordering key after a failure. Resume it so that
+		// the following events with the same key are accepted, the failed event is
+		// retried by the sender.
+		topic.ResumePublish(msg.OrderingKey)
+	}
 	return err
 }
 
+// orderingKey returns the Pub/Sub ordering key of the event, which is the value
+// of the broker's ordering key extension. Events without the extension are not
+// ordered.
+func (m *multiTopicDecoupleSink) orderingKey(broker *config.CellTenantKey, event *cev2.Event) string {
+	brokerConfig, ok := m.brokerConfig.GetCellTenantByKey(broker)
+	if !ok {
+		return ""
+	}
+	return eventutil.OrderingKey(event, brokerConfig.OrderingKeyExtension)
+}
+
 // eventFilterFunc is used to see if a target is interested in an event.
 // It is used as a vaiable to allow stubbing out in unit tests.
-var eventFilterFunc = filter.PassFilter
+var eventFilterFunc = func(ctx context.Context, f *filter.TargetFilter, event *cev2.Event) bool {
+	return f.Pass(ctx, event)
+}
 
 // enableEventFilterFunc is a temporary function to control enabling and
 // disabling trigger-less event filtering in ingress.
-// TODO(#1804): remove this variable when enabling the feature by default.
+// TODO(#1804): remove this variable when the feature can no longer be disabled.
 var enableEventFilterFunc = isEventFilteringEnabled
 
-// TODO(#1804): remove this method when enabling the feature by default.
+// The feature is enabled unless explicitly disabled.
+// TODO(#1804): remove this method when the feature can no longer be disabled.
 func isEventFilteringEnabled() bool {
-	return os.Getenv("ENABLE_INGRESS_EVENT_FILTERING") == "true"
+	return os.Getenv("ENABLE_INGRESS_EVENT_FILTERING") != "false"
 }
 
-// hasTrigger checks given event against all targets to see if it will pass any of their filters.
-// If one is fouund, hasTrigger returns true.
-func (m *multiTopicDecoupleSink) hasTrigger(ctx context.Context, event *cev2.Event) bool {
-	hasTrigger := false
-	m.brokerConfig.RangeAllTargets(func(target *config.Target) bool {
-		if eventFilterFunc(ctx, target.FilterAttributes, event) {
-			hasTrigger = true
-			return false
-		}
-
-		return true
-	})
-
-	return hasTrigger
+// hasTrigger checks given event against the targets of the broker to see if it will pass any of
+// their filters. If one is found, hasTrigger returns true.
+func (m *multiTopicDecoupleSink) hasTrigger(ctx context.Context, broker *config.CellTenantKey, event *cev2.Event) bool {
+	return m.targetIndex.hasTarget(ctx, broker, event)
 }
 
 // getTopicForBroker finds the corresponding decouple topic for the broker from the mounted broker configmap volume.
@@ -170,6 +188,9 @@ func (m *multiTopicDecoupleSink) updateTopicForBroker(ctx context.Context, broke
 	}
 	topic := m.pubsub.Topic(topicID)
 	topic.PublishSettings = m.publishSettings
+	if brokerConfig, ok := m.brokerConfig.GetCellTenantByKey(broker); ok {
+		topic.EnableMessageOrdering = brokerConfig.OrderingKeyExtension != ""
+	}
 	m.topics[*broker] = topic
 	return topic, nil
 }
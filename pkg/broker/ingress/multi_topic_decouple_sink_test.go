@@ -31,11 +31,12 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
 	logtest "knative.dev/pkg/logging/testing"
 )
 
 func TestMultiTopicDecoupleSink(t *testing.T) {
-	// TODO(#1804): remove this mock when enabling the feature by default.
+	// TODO(#1804): remove this mock when the feature can no longer be disabled.
 	origEnableEventFilterFunc := enableEventFilterFunc
 	defer func() { enableEventFilterFunc = origEnableEventFilterFunc }()
 	enableEventFilterFunc = func() bool {
@@ -245,8 +246,15 @@ func TestMultiTopicDecoupleSink(t *testing.T) {
 
 // Temoporary test to ensure functionality doesn't change when the filtering feature is disabled.
 // This is an exact copy of 'TestMultiTopicDecoupleSink'.
-// TODO(#1804): remove this test when enabling the feature by default.
+// TODO(#1804): remove this test when the feature can no longer be disabled.
 func TestMultiTopicDecoupleSinkWithoutIngressFiltering(t *testing.T) {
+	// TODO(#1804): remove this mock when the feature can no longer be disabled.
+	origEnableEventFilterFunc := enableEventFilterFunc
+	defer func() { enableEventFilterFunc = origEnableEventFilterFunc }()
+	enableEventFilterFunc = func() bool {
+		return false
+	}
+
 	// If the broker has no targets, it will drop events at ingress without sending them
 	// to pub/sub. So we add a target with no filter to the broker to ensure events are not
 	// dropped due to ingress filtering.
@@ -579,7 +587,7 @@ func TestHasTrigger(t *testing.T) {
 
 			event := createTestEvent(uuid.New().String())
 
-			hasTrigger := sink.hasTrigger(ctx, event)
+			hasTrigger := sink.hasTrigger(ctx, config.TestOnlyBrokerKey("test_ns_1", "test_broker_1"), event)
 			if hasTrigger != test.hasTrigger {
 				t.Errorf("Sink says event has trigger %t which should be %t", hasTrigger, test.hasTrigger)
 			}
@@ -588,7 +596,7 @@ func TestHasTrigger(t *testing.T) {
 }
 
 func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
-	// TODO(#1804): remove this mock when enabling the feature by default.
+	// TODO(#1804): remove this mock when the feature can no longer be disabled.
 	origEnableEventFilterFunc := enableEventFilterFunc
 	defer func() { enableEventFilterFunc = origEnableEventFilterFunc }()
 	enableEventFilterFunc = func() bool {
@@ -598,7 +606,7 @@ func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
 	filterCalled := false
 	origEventFilterFunc := eventFilterFunc
 	defer func() { eventFilterFunc = origEventFilterFunc }()
-	eventFilterFunc = func(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	eventFilterFunc = func(ctx context.Context, f *filter.TargetFilter, event *event.Event) bool {
 		filterCalled = true
 		return true
 	}
@@ -614,7 +622,8 @@ func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
 				Type:          config.CellTenantType_BROKER,
 				DecoupleQueue: &config.Queue{Topic: "test_topic_1", State: config.State_READY},
 				Targets: map[string]*config.Target{"target_1": {
-					CellTenantType: config.CellTenantType_BROKER,
+					CellTenantType:   config.CellTenantType_BROKER,
+					FilterAttributes: map[string]string{"source": "test-source"},
 				}},
 			},
 		},
@@ -648,13 +657,19 @@ func TestMultiTopicDecoupleSinkSendChecksFilter(t *testing.T) {
 	}
 }
 
-// Temoporary test to ensure the filtering feature is disabled by default.
-// TODO(#1804): remove this test when enabling the feature by default.
+// Temoporary test to ensure the filtering feature can be disabled.
+// TODO(#1804): remove this test when the feature can no longer be disabled.
 func TestMultiTopicDecoupleSinkSendDoesNotChecksFilterWhenFeatureDisabled(t *testing.T) {
+	origEnableEventFilterFunc := enableEventFilterFunc
+	defer func() { enableEventFilterFunc = origEnableEventFilterFunc }()
+	enableEventFilterFunc = func() bool {
+		return false
+	}
+
 	filterCalled := false
 	origEventFilterFunc := eventFilterFunc
 	defer func() { eventFilterFunc = origEventFilterFunc }()
-	eventFilterFunc = func(ctx context.Context, attrs map[string]string, event *event.Event) bool {
+	eventFilterFunc = func(ctx context.Context, f *filter.TargetFilter, event *event.Event) bool {
 		filterCalled = true
 		return true
 	}
@@ -751,3 +766,69 @@ func TestMultiTopicDecoupleSinkUsesPublishSettings(t *testing.T) {
 		t.Fatalf("Unexpected error, expected %q, actually %q", want, got)
 	}
 }
+
+func TestMultiTopicDecoupleSinkSetsOrderingKey(t *testing.T) {
+	tests := []struct {
+		name                 string
+		orderingKeyExtension string
+		extensions           map[string]interface{}
+		wantOrderingKey      string
+	}{{
+		name:       "ordering disabled",
+		extensions: map[string]interface{}{"partitionkey": "order-1"},
+	}, {
+		name:                 "ordering enabled",
+		orderingKeyExtension: "partitionkey",
+		extensions:           map[string]interface{}{"partitionkey": "order-1"},
+		wantOrderingKey:      "order-1",
+	}, {
+		name:                 "ordering enabled with non string extension",
+		orderingKeyExtension: "partitionkey",
+		extensions:           map[string]interface{}{"partitionkey": 42},
+		wantOrderingKey:      "42",
+	}, {
+		name:                 "ordering enabled without extension",
+		orderingKeyExtension: "partitionkey",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := logtest.TestContextWithLogger(t)
+			psSrv := pstest.NewServer()
+			defer psSrv.Close()
+			psClient := createPubsubClient(ctx, t, psSrv)
+
+			testBrokerConfig := &config.TargetsConfig{
+				CellTenants: map[string]*config.CellTenant{
+					"test_ns_1/test_broker_1": {
+						Type:                 config.CellTenantType_BROKER,
+						DecoupleQueue:        &config.Queue{Topic: "test_topic_1", State: config.State_READY},
+						OrderingKeyExtension: test.orderingKeyExtension,
+						Targets: map[string]*config.Target{"target_1": {
+							CellTenantType: config.CellTenantType_BROKER,
+						}},
+					},
+				},
+			}
+			if _, err := psClient.CreateTopic(ctx, "test_topic_1"); err != nil {
+				t.Fatal(err)
+			}
+
+			sink := NewMultiTopicDecoupleSink(ctx, memory.NewTargets(testBrokerConfig), psClient, pubsub.DefaultPublishSettings)
+			event := createTestEvent(uuid.New().String())
+			for k, v := range test.extensions {
+				event.SetExtension(k, v)
+			}
+			if err := sink.Send(context.Background(), config.TestOnlyBrokerKey("test_ns_1", "test_broker_1"), *event); err != nil {
+				t.Fatal(err)
+			}
+
+			msgs := psSrv.Messages()
+			if len(msgs) != 1 {
+				t.Fatalf("Published messages got=%d, want=1", len(msgs))
+			}
+			if got := msgs[0].OrderingKey; got != test.wantOrderingKey {
+				t.Errorf("Message ordering key got=%q, want=%q", got, test.wantOrderingKey)
+			}
+		})
+	}
+}
@@ -29,6 +29,12 @@ func BrokerPath(namespace, name string) string {
 	return fmt.Sprintf("/%s/%s", namespace, name)
 }
 
+// ChannelPath returns the path to be set in the status of a channel served by
+// a brokercell. The format is /channel/channelNamespace/channelName
+func ChannelPath(namespace, name string) string {
+	return fmt.Sprintf("/channel/%s/%s", namespace, name)
+}
+
 // convertPathToNamespacedName converts the broker path to a NamespaceName.
 func ConvertPathToNamespacedName(path string) (types.NamespacedName, error) {
 	// Path should be in the form of "/<ns>/<broker>".
@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
 )
 
 const (
@@ -37,6 +39,21 @@ func TestBrokerPath(t *testing.T) {
 	}
 }
 
+func TestChannelPath(t *testing.T) {
+	want := "/channel/namespace/broker"
+	got := ChannelPath(testNS, testName)
+	if got != want {
+		t.Errorf("unexpected path: want %v, got %v", want, got)
+	}
+	key, err := config.CellTenantKeyFromPersistenceString(got)
+	if err != nil {
+		t.Fatalf("unexpected error parsing channel path: %v", err)
+	}
+	if *key != *config.TestOnlyChannelKey(testNS, testName) {
+		t.Errorf("unexpected key: want channel %s/%s, got %v", testNS, testName, key)
+	}
+}
+
 func TestConvertPathtoNamespacedName(t *testing.T) {
 	want := types.NamespacedName{
 		Namespace: testNS,
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"sync"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+)
+
+// typeAttribute is the CloudEvents attribute the targets are indexed by.
+const typeAttribute = "type"
+
+// targetIndex is a per-CellTenant index of the targets' filters. It is used to
+// determine whether any target of a CellTenant may be interested in an event
+// without evaluating the filters of every target in the BrokerCell.
+//
+// The targets config is replaced as a whole whenever it changes, e.g. when the
+// volume watcher reloads the config file. The index of a CellTenant is rebuilt
+// when the CellTenant it was built from is no longer the current one.
+type targetIndex struct {
+	targets config.ReadonlyTargets
+
+	mu          sync.RWMutex
+	cellTenants map[config.CellTenantKey]*cellTenantIndex
+}
+
+// cellTenantIndex indexes the targets of a single CellTenant. It holds the
+// targets' parsed filters, which are parsed when the index is built.
+type cellTenantIndex struct {
+	// source is the CellTenant the index was built from.
+	source *config.CellTenant
+	// matchAll is true if the CellTenant has a target without any filter.
+	matchAll bool
+	// byType holds the filters of the targets that only accept a single event
+	// type, keyed by that type.
+	byType map[string][]*filter.TargetFilter
+	// others holds the filters of the targets that accept any event type.
+	others []*filter.TargetFilter
+}
+
+func newTargetIndex(targets config.ReadonlyTargets) *targetIndex {
+	return &targetIndex{
+		targets:     targets,
+		cellTenants: make(map[config.CellTenantKey]*cellTenantIndex),
+	}
+}
+
+// hasTarget returns true if the event passes the filters of at least one
+// target of the given CellTenant.
+func (ti *targetIndex) hasTarget(ctx context.Context, key *config.CellTenantKey, event *cev2.Event) bool {
+	idx := ti.get(ctx, key)
+	if idx == nil {
+		return false
+	}
+	if idx.matchAll {
+		return true
+	}
+	for _, f := range idx.byType[event.Type()] {
+		if eventFilterFunc(ctx, f, event) {
+			return true
+		}
+	}
+	for _, f := range idx.others {
+		if eventFilterFunc(ctx, f, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns the up-to-date index of the given CellTenant, or nil if the
+// CellTenant doesn't exist.
+func (ti *targetIndex) get(ctx context.Context, key *config.CellTenantKey) *cellTenantIndex {
+	cellTenant, ok := ti.targets.GetCellTenantByKey(key)
+	if !ok {
+		return nil
+	}
+
+	ti.mu.RLock()
+	idx, ok := ti.cellTenants[*key]
+	ti.mu.RUnlock()
+	if ok && idx.source == cellTenant {
+		return idx
+	}
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+	// The targets config has changed, drop the indexes which are stale so
+	// that the indexes of deleted CellTenants don't pile up.
+	for k, idx := range ti.cellTenants {
+		if current, ok := ti.targets.GetCellTenantByKey(&k); !ok || current != idx.source {
+			delete(ti.cellTenants, k)
+		}
+	}
+	if idx, ok := ti.cellTenants[*key]; ok && idx.source == cellTenant {
+		// Another request already rebuilt the index.
+		return idx
+	}
+	idx = buildCellTenantIndex(ctx, cellTenant)
+	ti.cellTenants[*key] = idx
+	return idx
+}
+
+func buildCellTenantIndex(ctx context.Context, cellTenant *config.CellTenant) *cellTenantIndex {
+	idx := &cellTenantIndex{
+		source: cellTenant,
+		byType: make(map[string][]*filter.TargetFilter),
+	}
+	for _, target := range cellTenant.Targets {
+		if len(target.FilterAttributes) == 0 && len(target.Filters) == 0 {
+			idx.matchAll = true
+		}
+		f := filter.NewTargetFilter(ctx, target)
+		if eventType, ok := requiredEventType(target); ok {
+			idx.byType[eventType] = append(idx.byType[eventType], f)
+		} else {
+			idx.others = append(idx.others, f)
+		}
+	}
+	return idx
+}
+
+// requiredEventType returns the event type a target requires, if its filters
+// only accept a single event type.
+func requiredEventType(target *config.Target) (string, bool) {
+	// An empty attribute filter value matches any value.
+	if eventType := target.FilterAttributes[typeAttribute]; eventType != "" {
+		return eventType, true
+	}
+	// All the top level filters must pass, so an exact match on the event type
+	// in any of them is required.
+	for _, f := range target.Filters {
+		if eventType, ok := f.Exact[typeAttribute]; ok {
+			return eventType, true
+		}
+	}
+	return "", false
+}
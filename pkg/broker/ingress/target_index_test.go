@@ -0,0 +1,158 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+)
+
+func TestTargetIndexScopedToCellTenant(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	targets := memory.NewTargets(&config.TargetsConfig{
+		CellTenants: map[string]*config.CellTenant{
+			"test_ns_1/test_broker_1": {
+				Type: config.CellTenantType_BROKER,
+				Targets: map[string]*config.Target{
+					"non_matching_target": {
+						FilterAttributes: map[string]string{"type": eventType + "dummy"},
+					},
+				},
+			},
+			"test_ns_1/test_broker_2": {
+				Type: config.CellTenantType_BROKER,
+				Targets: map[string]*config.Target{
+					"matching_target": {
+						FilterAttributes: map[string]string{"type": eventType},
+					},
+				},
+			},
+		},
+	})
+	index := newTargetIndex(targets)
+	event := createTestEvent(uuid.New().String())
+
+	if index.hasTarget(ctx, config.TestOnlyBrokerKey("test_ns_1", "test_broker_1"), event) {
+		t.Error("hasTarget got=true for a broker without matching targets, want=false")
+	}
+	if !index.hasTarget(ctx, config.TestOnlyBrokerKey("test_ns_1", "test_broker_2"), event) {
+		t.Error("hasTarget got=false for a broker with a matching target, want=true")
+	}
+	if index.hasTarget(ctx, config.TestOnlyBrokerKey("test_ns_1", "non_existing_broker"), event) {
+		t.Error("hasTarget got=true for a non existing broker, want=false")
+	}
+}
+
+func TestTargetIndexRebuiltOnConfigChange(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	key := config.TestOnlyBrokerKey("test_ns_1", "test_broker_1")
+	targets := memory.NewEmptyTargets()
+	targets.MutateCellTenant(key, func(m config.CellTenantMutation) {
+		m.UpsertTargets(&config.Target{
+			Name:             "target_1",
+			FilterAttributes: map[string]string{"type": eventType + "dummy"},
+		})
+	})
+	index := newTargetIndex(targets)
+	event := createTestEvent(uuid.New().String())
+
+	if index.hasTarget(ctx, key, event) {
+		t.Error("hasTarget got=true before adding a matching target, want=false")
+	}
+
+	targets.MutateCellTenant(key, func(m config.CellTenantMutation) {
+		m.UpsertTargets(&config.Target{
+			Name: "target_2",
+			Filters: []*config.Filter{{
+				Exact: map[string]string{"type": eventType},
+			}, {
+				Prefix: map[string]string{"source": "test-"},
+			}},
+		})
+	})
+	if !index.hasTarget(ctx, key, event) {
+		t.Error("hasTarget got=false after adding a matching target, want=true")
+	}
+
+	targets.MutateCellTenant(key, func(m config.CellTenantMutation) {
+		m.Delete()
+	})
+	if index.hasTarget(ctx, key, event) {
+		t.Error("hasTarget got=true after deleting the broker, want=false")
+	}
+	if len(index.cellTenants) != 1 {
+		t.Errorf("Number of indexed CellTenants got=%d, want=1", len(index.cellTenants))
+	}
+}
+
+func TestRequiredEventType(t *testing.T) {
+	tests := []struct {
+		name   string
+		target *config.Target
+		want   string
+		wantOK bool
+	}{{
+		name:   "no filters",
+		target: &config.Target{},
+	}, {
+		name: "attribute filter",
+		target: &config.Target{
+			FilterAttributes: map[string]string{"type": "foo", "source": "bar"},
+		},
+		want:   "foo",
+		wantOK: true,
+	}, {
+		name: "attribute filter matching any type",
+		target: &config.Target{
+			FilterAttributes: map[string]string{"type": ""},
+		},
+	}, {
+		name: "exact filter",
+		target: &config.Target{
+			Filters: []*config.Filter{
+				{Prefix: map[string]string{"source": "bar"}},
+				{Exact: map[string]string{"type": "foo"}},
+			},
+		},
+		want:   "foo",
+		wantOK: true,
+	}, {
+		name: "nested exact filter",
+		target: &config.Target{
+			Filters: []*config.Filter{{
+				Any: []*config.Filter{
+					{Exact: map[string]string{"type": "foo"}},
+					{Exact: map[string]string{"type": "bar"}},
+				},
+			}},
+		},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := requiredEventType(tc.target)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("requiredEventType got=(%q, %v), want=(%q, %v)", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
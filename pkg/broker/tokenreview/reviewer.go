@@ -0,0 +1,127 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tokenreview authenticates Kubernetes service account tokens with
+// TokenReviews.
+package tokenreview
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// reviewTTL is how long the result of a TokenReview is reused for the same valid token.
+	reviewTTL = time.Minute
+	// invalidReviewTTL is how long the result of a TokenReview is reused for the same invalid token.
+	invalidReviewTTL = 10 * time.Minute
+	// cacheSize bounds the number of cached TokenReview results. The least recently used are evicted first.
+	cacheSize = 4096
+	// reviewsPerSecond and reviewBurst limit the rate of TokenReviews, so that requests with arbitrary tokens cannot
+	// amplify the load on the API server.
+	reviewsPerSecond = 20
+	reviewBurst      = 100
+
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+)
+
+// ErrTooManyReviews is the error when a token is not reviewed because of the rate limit of the TokenReviews.
+var ErrTooManyReviews = errors.New("too many token reviews")
+
+// Reviewer authenticates tokens with TokenReviews. It caches the results of the reviews and limits their rate.
+type Reviewer struct {
+	kubeClient kubernetes.Interface
+	// reviews caches the usernames returned by TokenReviews, keyed by the hash of the token and the audience. The
+	// username is empty if the token is invalid.
+	reviews *cache.LRUExpireCache
+	// limiter limits the rate of the TokenReviews of tokens that are not cached.
+	limiter *rate.Limiter
+	now     func() time.Time
+}
+
+// NewReviewer creates a new Reviewer.
+func NewReviewer(kubeClient kubernetes.Interface) *Reviewer {
+	r := &Reviewer{
+		kubeClient: kubeClient,
+		limiter:    rate.NewLimiter(reviewsPerSecond, reviewBurst),
+		now:        time.Now,
+	}
+	r.reviews = cache.NewLRUExpireCacheWithClock(cacheSize, clockFunc(func() time.Time { return r.now() }))
+	return r
+}
+
+// clockFunc implements cache.Clock.
+type clockFunc func() time.Time
+
+func (f clockFunc) Now() time.Time {
+	return f()
+}
+
+// Review returns the user the token was issued to, or an empty string if the token is not valid for the audience.
+// An empty audience stands for the Kubernetes API server. It returns ErrTooManyReviews if the token is not cached
+// and cannot be reviewed within the rate limit.
+func (r *Reviewer) Review(ctx context.Context, token, audience string) (string, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:]) + "/" + audience
+	if cached, ok := r.reviews.Get(key); ok {
+		return cached.(string), nil
+	}
+	if !r.limiter.AllowN(r.now(), 1) {
+		return "", ErrTooManyReviews
+	}
+
+	tr := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	if audience != "" {
+		tr.Spec.Audiences = []string{audience}
+	}
+	result, err := r.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, tr, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review token: %w", err)
+	}
+	if !result.Status.Authenticated {
+		r.reviews.Add(key, "", invalidReviewTTL)
+		return "", nil
+	}
+	username := result.Status.User.Username
+	r.reviews.Add(key, username, reviewTTL)
+	return username, nil
+}
+
+// ServiceAccount returns the namespace and name of the service account of the username, or false if the username is
+// not a service account.
+func ServiceAccount(username string) (namespace, name string, ok bool) {
+	if !strings.HasPrefix(username, serviceAccountUsernamePrefix) {
+		return "", "", false
+	}
+	parts := strings.Split(strings.TrimPrefix(username, serviceAccountUsernamePrefix), ":")
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tokenreview
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+)
+
+func TestReviewerCache(t *testing.T) {
+	reviews := 0
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		tr := action.(clientgotesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if len(tr.Spec.Audiences) != 1 || tr.Spec.Audiences[0] != "audience" {
+			t.Errorf("Unexpected TokenReview audiences: %v", tr.Spec.Audiences)
+		}
+		if tr.Spec.Token == "valid-token" {
+			tr.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User:          authenticationv1.UserInfo{Username: "system:serviceaccount:ns:sa"},
+			}
+		}
+		return true, tr, nil
+	})
+	r := NewReviewer(client)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	review := func(token, wantUsername string, wantReviews int) {
+		t.Helper()
+		username, err := r.Review(context.Background(), token, "audience")
+		if err != nil {
+			t.Errorf("Review() = %v, want nil", err)
+		}
+		if username != wantUsername {
+			t.Errorf("Review() = %q, want %q", username, wantUsername)
+		}
+		if reviews != wantReviews {
+			t.Errorf("TokenReviews = %d, want %d", reviews, wantReviews)
+		}
+	}
+
+	review("valid-token", "system:serviceaccount:ns:sa", 1)
+	// The result of the review is reused.
+	review("valid-token", "system:serviceaccount:ns:sa", 1)
+	// The token is reviewed again after the cached result expires.
+	now = now.Add(reviewTTL + time.Second)
+	review("valid-token", "system:serviceaccount:ns:sa", 2)
+
+	// The rejection of an invalid token is reused for longer.
+	review("invalid-token", "", 3)
+	now = now.Add(reviewTTL + time.Second)
+	review("invalid-token", "", 3)
+	now = now.Add(invalidReviewTTL)
+	review("invalid-token", "", 4)
+}
+
+func TestReviewerRateLimit(t *testing.T) {
+	reviews := 0
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		return true, action.(clientgotesting.CreateAction).GetObject(), nil
+	})
+	r := NewReviewer(client)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	// Arbitrary tokens are reviewed up to the burst, then rejected without a review.
+	for i := 0; i < reviewBurst+10; i++ {
+		_, err := r.Review(context.Background(), fmt.Sprint("token-", i), "")
+		if i < reviewBurst && err != nil {
+			t.Errorf("Review() = %v, want nil", err)
+		}
+		if i >= reviewBurst && err != ErrTooManyReviews {
+			t.Errorf("Review() = %v, want %v", err, ErrTooManyReviews)
+		}
+	}
+	if reviews != reviewBurst {
+		t.Errorf("TokenReviews = %d, want %d", reviews, reviewBurst)
+	}
+	// Reviews are allowed again over time.
+	now = now.Add(time.Second)
+	if _, err := r.Review(context.Background(), "another-token", ""); err != nil {
+		t.Errorf("Review() = %v, want nil", err)
+	}
+}
+
+func TestServiceAccount(t *testing.T) {
+	tests := []struct {
+		username      string
+		wantNamespace string
+		wantName      string
+		wantOK        bool
+	}{{
+		username:      "system:serviceaccount:ns:sa",
+		wantNamespace: "ns",
+		wantName:      "sa",
+		wantOK:        true,
+	}, {
+		username: "alice@example.com",
+	}, {
+		username: "system:serviceaccount:ns",
+	}}
+	for _, test := range tests {
+		namespace, name, ok := ServiceAccount(test.username)
+		if namespace != test.wantNamespace || name != test.wantName || ok != test.wantOK {
+			t.Errorf("ServiceAccount(%q) = %q, %q, %v, want %q, %q, %v", test.username, namespace, name, ok, test.wantNamespace, test.wantName, test.wantOK)
+		}
+	}
+}
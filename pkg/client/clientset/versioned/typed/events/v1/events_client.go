@@ -28,6 +28,8 @@ type EventsV1Interface interface {
 	RESTClient() rest.Interface
 	CloudAuditLogsSourcesGetter
 	CloudBuildSourcesGetter
+	CloudFirestoreSourcesGetter
+	CloudMonitoringAlertSourcesGetter
 	CloudPubSubSourcesGetter
 	CloudSchedulerSourcesGetter
 	CloudStorageSourcesGetter
@@ -46,6 +48,14 @@ func (c *EventsV1Client) CloudBuildSources(namespace string) CloudBuildSourceInt
 	return newCloudBuildSources(c, namespace)
 }
 
+func (c *EventsV1Client) CloudFirestoreSources(namespace string) CloudFirestoreSourceInterface {
+	return newCloudFirestoreSources(c, namespace)
+}
+
+func (c *EventsV1Client) CloudMonitoringAlertSources(namespace string) CloudMonitoringAlertSourceInterface {
+	return newCloudMonitoringAlertSources(c, namespace)
+}
+
 func (c *EventsV1Client) CloudPubSubSources(namespace string) CloudPubSubSourceInterface {
 	return newCloudPubSubSources(c, namespace)
 }
@@ -36,6 +36,14 @@ func (c *FakeEventsV1) CloudBuildSources(namespace string) v1.CloudBuildSourceIn
 	return &FakeCloudBuildSources{c, namespace}
 }
 
+func (c *FakeEventsV1) CloudFirestoreSources(namespace string) v1.CloudFirestoreSourceInterface {
+	return &FakeCloudFirestoreSources{c, namespace}
+}
+
+func (c *FakeEventsV1) CloudMonitoringAlertSources(namespace string) v1.CloudMonitoringAlertSourceInterface {
+	return &FakeCloudMonitoringAlertSources{c, namespace}
+}
+
 func (c *FakeEventsV1) CloudPubSubSources(namespace string) v1.CloudPubSubSourceInterface {
 	return &FakeCloudPubSubSources{c, namespace}
 }
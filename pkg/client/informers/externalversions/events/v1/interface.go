@@ -28,6 +28,10 @@ type Interface interface {
 	CloudAuditLogsSources() CloudAuditLogsSourceInformer
 	// CloudBuildSources returns a CloudBuildSourceInformer.
 	CloudBuildSources() CloudBuildSourceInformer
+	// CloudFirestoreSources returns a CloudFirestoreSourceInformer.
+	CloudFirestoreSources() CloudFirestoreSourceInformer
+	// CloudMonitoringAlertSources returns a CloudMonitoringAlertSourceInformer.
+	CloudMonitoringAlertSources() CloudMonitoringAlertSourceInformer
 	// CloudPubSubSources returns a CloudPubSubSourceInformer.
 	CloudPubSubSources() CloudPubSubSourceInformer
 	// CloudSchedulerSources returns a CloudSchedulerSourceInformer.
@@ -57,6 +61,16 @@ func (v *version) CloudBuildSources() CloudBuildSourceInformer {
 	return &cloudBuildSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CloudFirestoreSources returns a CloudFirestoreSourceInformer.
+func (v *version) CloudFirestoreSources() CloudFirestoreSourceInformer {
+	return &cloudFirestoreSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CloudMonitoringAlertSources returns a CloudMonitoringAlertSourceInformer.
+func (v *version) CloudMonitoringAlertSources() CloudMonitoringAlertSourceInformer {
+	return &cloudMonitoringAlertSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudPubSubSources returns a CloudPubSubSourceInformer.
 func (v *version) CloudPubSubSources() CloudPubSubSourceInformer {
 	return &cloudPubSubSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -69,6 +69,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudAuditLogsSources().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cloudbuildsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudBuildSources().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cloudfirestoresources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudFirestoreSources().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("cloudmonitoringalertsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudMonitoringAlertSources().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cloudpubsubsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1().CloudPubSubSources().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("cloudschedulersources"):
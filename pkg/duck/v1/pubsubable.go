@@ -29,3 +29,11 @@ type PubSubable interface {
 	// PubSubStatus returns the PubSubStatus portion of the Status.
 	PubSubStatus() *duckv1.PubSubStatus
 }
+
+// AdapterFilterable is implemented by the PubSubables which events are
+// filtered by their receive adapter.
+type AdapterFilterable interface {
+	// AdapterFilter returns the JSON encoded filter of the events, or an
+	// empty string if all the events are delivered.
+	AdapterFilter() string
+}
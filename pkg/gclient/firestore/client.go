@@ -0,0 +1,227 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+const (
+	// defaultEndpoint is the endpoint of the Eventarc API, whose triggers
+	// implement the change feeds.
+	defaultEndpoint    = "https://eventarc.googleapis.com/v1/"
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+	matchPathPattern = "match-path-pattern"
+)
+
+// CreateFn is a factory function to create a Firestore change feed client.
+type CreateFn func(ctx context.Context, opts ...option.ClientOption) (Client, error)
+
+// NewClient creates a new Firestore change feed client, backed by Eventarc
+// triggers with a Pub/Sub transport.
+func NewClient(ctx context.Context, opts ...option.ClientOption) (Client, error) {
+	opts = append([]option.ClientOption{option.WithScopes(cloudPlatformScope)}, opts...)
+	client, endpoint, err := htransport.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	return &firestoreClient{
+		client:   client,
+		endpoint: strings.TrimSuffix(endpoint, "/") + "/",
+	}, nil
+}
+
+// firestoreClient calls the Eventarc REST API. Is the client that will be used everywhere except unit tests.
+type firestoreClient struct {
+	client   *http.Client
+	endpoint string
+}
+
+// Verify that it satisfies the Client interface.
+var _ Client = &firestoreClient{}
+
+// trigger is the subset of the Eventarc Trigger resource used by change feeds.
+type trigger struct {
+	Name                 string        `json:"name,omitempty"`
+	EventFilters         []eventFilter `json:"eventFilters,omitempty"`
+	Transport            *transport    `json:"transport,omitempty"`
+	EventDataContentType string        `json:"eventDataContentType,omitempty"`
+}
+
+type eventFilter struct {
+	Attribute string `json:"attribute"`
+	Value     string `json:"value"`
+	Operator  string `json:"operator,omitempty"`
+}
+
+type transport struct {
+	Pubsub *pubsubTransport `json:"pubsub,omitempty"`
+}
+
+type pubsubTransport struct {
+	Topic string `json:"topic,omitempty"`
+}
+
+// Close implements Client.Close
+func (c *firestoreClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// GetChangeFeed implements Client.GetChangeFeed
+func (c *firestoreClient) GetChangeFeed(ctx context.Context, name string) (*ChangeFeed, error) {
+	var t trigger
+	if err := c.do(ctx, http.MethodGet, name, nil, &t); err != nil {
+		return nil, err
+	}
+	return fromTrigger(&t), nil
+}
+
+// CreateChangeFeed implements Client.CreateChangeFeed
+func (c *firestoreClient) CreateChangeFeed(ctx context.Context, feed *ChangeFeed) (*ChangeFeed, error) {
+	i := strings.LastIndex(feed.Name, "/triggers/")
+	if i < 0 {
+		return nil, gstatus.Errorf(codes.InvalidArgument, "invalid change feed name %q", feed.Name)
+	}
+	parent, id := feed.Name[:i], feed.Name[i+len("/triggers/"):]
+	path := fmt.Sprintf("%s/triggers?triggerId=%s", parent, url.QueryEscape(id))
+	// The creation is a long running operation, which is not waited for.
+	if err := c.do(ctx, http.MethodPost, path, toTrigger(feed), nil); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// DeleteChangeFeed implements Client.DeleteChangeFeed
+func (c *firestoreClient) DeleteChangeFeed(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, name, nil, nil)
+}
+
+// do sends the request to the path relative to the endpoint and decodes the
+// response into out, if not nil. HTTP errors are converted to gRPC statuses.
+func (c *firestoreClient) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if err := googleapi.CheckResponse(res); err != nil {
+		return toStatus(err)
+	}
+	if out == nil {
+		_, err = ioutil.ReadAll(res.Body)
+		return err
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// toStatus converts a googleapi.Error to a gRPC status with the matching code.
+func toStatus(err error) error {
+	gerr, ok := err.(*googleapi.Error)
+	if !ok {
+		return err
+	}
+	code := codes.Unknown
+	switch gerr.Code {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		code = codes.Unavailable
+	}
+	return gstatus.Error(code, gerr.Error())
+}
+
+func toTrigger(feed *ChangeFeed) *trigger {
+	return &trigger{
+		Name: feed.Name,
+		EventFilters: []eventFilter{{
+			Attribute: "type",
+			Value:     feed.EventType,
+		}, {
+			Attribute: "database",
+			Value:     feed.Database,
+		}, {
+			Attribute: "document",
+			Value:     feed.Document,
+			Operator:  matchPathPattern,
+		}},
+		Transport: &transport{
+			Pubsub: &pubsubTransport{
+				Topic: feed.Topic,
+			},
+		},
+		EventDataContentType: "application/json",
+	}
+}
+
+func fromTrigger(t *trigger) *ChangeFeed {
+	feed := &ChangeFeed{
+		Name: t.Name,
+	}
+	for _, f := range t.EventFilters {
+		switch f.Attribute {
+		case "type":
+			feed.EventType = f.Value
+		case "database":
+			feed.Database = f.Value
+		case "document":
+			feed.Document = f.Value
+		}
+	}
+	if t.Transport != nil && t.Transport.Pubsub != nil {
+		feed.Topic = t.Transport.Pubsub.Topic
+	}
+	return feed
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firestore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+const testFeedName = "projects/my-project/locations/nam5/triggers/my-feed"
+
+var testFeed = ChangeFeed{
+	Name:      testFeedName,
+	Database:  "(default)",
+	Document:  "users/{userId}",
+	EventType: "google.cloud.firestore.document.v1.written",
+	Topic:     "projects/my-project/topics/my-topic",
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := NewClient(context.Background(), option.WithEndpoint(srv.URL), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() = %v", err)
+	}
+	return c
+}
+
+func TestCreateChangeFeed(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		if got, want := r.URL.Path, "/projects/my-project/locations/nam5/triggers"; got != want {
+			t.Errorf("unexpected path %s, want %s", got, want)
+		}
+		if got, want := r.URL.Query().Get("triggerId"), "my-feed"; got != want {
+			t.Errorf("unexpected trigger ID %s, want %s", got, want)
+		}
+		var got trigger
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to decode the trigger: %v", err)
+		}
+		if diff := cmp.Diff(toTrigger(&testFeed), &got); diff != "" {
+			t.Errorf("unexpected trigger (-want, +got) = %v", diff)
+		}
+		w.Write([]byte(`{"name": "operations/op"}`))
+	})
+
+	got, err := c.CreateChangeFeed(context.Background(), &testFeed)
+	if err != nil {
+		t.Fatalf("CreateChangeFeed() = %v", err)
+	}
+	if diff := cmp.Diff(&testFeed, got); diff != "" {
+		t.Errorf("unexpected change feed (-want, +got) = %v", diff)
+	}
+}
+
+func TestGetChangeFeed(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/"+testFeedName; got != want {
+			t.Errorf("unexpected path %s, want %s", got, want)
+		}
+		json.NewEncoder(w).Encode(toTrigger(&testFeed))
+	})
+
+	got, err := c.GetChangeFeed(context.Background(), testFeedName)
+	if err != nil {
+		t.Fatalf("GetChangeFeed() = %v", err)
+	}
+	if diff := cmp.Diff(&testFeed, got); diff != "" {
+		t.Errorf("unexpected change feed (-want, +got) = %v", diff)
+	}
+}
+
+func TestGetChangeFeedNotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "not found"}}`))
+	})
+
+	_, err := c.GetChangeFeed(context.Background(), testFeedName)
+	if got := gstatus.Code(err); got != codes.NotFound {
+		t.Errorf("unexpected error code %v, want %v: %v", got, codes.NotFound, err)
+	}
+}
+
+func TestDeleteChangeFeed(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		if got, want := r.URL.Path, "/"+testFeedName; got != want {
+			t.Errorf("unexpected path %s, want %s", got, want)
+		}
+		w.Write([]byte(`{"name": "operations/op"}`))
+	})
+
+	if err := c.DeleteChangeFeed(context.Background(), testFeedName); err != nil {
+		t.Errorf("DeleteChangeFeed() = %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firestore
+
+import (
+	"context"
+)
+
+// ChangeFeed publishes the changes of the Firestore documents matching a path
+// pattern to a Pub/Sub topic, as CloudEvents in the Pub/Sub binary content mode.
+type ChangeFeed struct {
+	// Name is the name of the change feed, like
+	// projects/PROJECT_ID/locations/LOCATION_ID/triggers/FEED_ID.
+	Name string
+	// Database is the ID of the Firestore database, e.g. "(default)".
+	Database string
+	// Document is the path pattern of the documents, e.g. "users/{userId}".
+	Document string
+	// EventType is the type of the document changes published, e.g.
+	// google.cloud.firestore.document.v1.written.
+	EventType string
+	// Topic is the name of the Pub/Sub topic the changes are published to, like
+	// projects/PROJECT_ID/topics/TOPIC_ID.
+	Topic string
+}
+
+// Client provisions the change feeds of Firestore documents. Errors are gRPC
+// statuses, in particular a codes.NotFound status is returned for a change
+// feed which does not exist.
+type Client interface {
+	// Close closes the connection to the API service.
+	Close() error
+	// GetChangeFeed returns the change feed with the given name.
+	GetChangeFeed(ctx context.Context, name string) (*ChangeFeed, error)
+	// CreateChangeFeed creates the change feed. It returns once the creation
+	// is accepted, the feed may take a few minutes to publish the changes.
+	CreateChangeFeed(ctx context.Context, feed *ChangeFeed) (*ChangeFeed, error)
+	// DeleteChangeFeed deletes the change feed with the given name.
+	DeleteChangeFeed(ctx context.Context, name string) error
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/google/knative-gcp/pkg/gclient/firestore"
+)
+
+// TestClientCreator returns a firestore.CreateFn used to construct the test Firestore client.
+func TestClientCreator(value interface{}) firestore.CreateFn {
+	var data TestClientData
+	var ok bool
+	if data, ok = value.(TestClientData); !ok {
+		data = TestClientData{}
+	}
+	if data.CreateClientErr != nil {
+		return func(_ context.Context, _ ...option.ClientOption) (firestore.Client, error) {
+			return nil, data.CreateClientErr
+		}
+	}
+
+	return func(_ context.Context, _ ...option.ClientOption) (firestore.Client, error) {
+		return &testClient{
+			data: data,
+		}, nil
+	}
+}
+
+// TestClientData is the data used to configure the test Firestore client.
+type TestClientData struct {
+	CreateClientErr     error
+	GetChangeFeedErr    error
+	CreateChangeFeedErr error
+	DeleteChangeFeedErr error
+	CloseErr            error
+	// ChangeFeeds are the existing change feeds, by name. GetChangeFeed
+	// returns a codes.NotFound status for the other change feeds.
+	ChangeFeeds map[string]firestore.ChangeFeed
+}
+
+// testClient is the test Firestore client.
+type testClient struct {
+	data TestClientData
+}
+
+// Verify that it satisfies the firestore.Client interface.
+var _ firestore.Client = &testClient{}
+
+// Close implements client.Close
+func (c *testClient) Close() error {
+	return c.data.CloseErr
+}
+
+// GetChangeFeed implements client.GetChangeFeed
+func (c *testClient) GetChangeFeed(ctx context.Context, name string) (*firestore.ChangeFeed, error) {
+	if c.data.GetChangeFeedErr != nil {
+		return nil, c.data.GetChangeFeedErr
+	}
+	feed, ok := c.data.ChangeFeeds[name]
+	if !ok {
+		return nil, gstatus.Errorf(codes.NotFound, "change feed %q not found", name)
+	}
+	return &feed, nil
+}
+
+// CreateChangeFeed implements client.CreateChangeFeed
+func (c *testClient) CreateChangeFeed(ctx context.Context, feed *firestore.ChangeFeed) (*firestore.ChangeFeed, error) {
+	if c.data.CreateChangeFeedErr != nil {
+		return nil, c.data.CreateChangeFeedErr
+	}
+	return feed, nil
+}
+
+// DeleteChangeFeed implements client.DeleteChangeFeed
+func (c *testClient) DeleteChangeFeed(ctx context.Context, name string) error {
+	return c.data.DeleteChangeFeedErr
+}
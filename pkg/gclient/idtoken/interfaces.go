@@ -0,0 +1,29 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idtoken
+
+import (
+	"context"
+
+	"golang.org/x/oauth2"
+)
+
+// Source creates token sources of Google-signed ID tokens.
+type Source interface {
+	// TokenSource see https://pkg.go.dev/google.golang.org/api/idtoken#NewTokenSource
+	TokenSource(ctx context.Context, audience string) (oauth2.TokenSource, error)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package idtoken
+
+import (
+	"context"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/idtoken"
+	"google.golang.org/api/option"
+)
+
+// NewSource creates a Source of ID tokens signed for the identity of the
+// application default credentials, e.g. the Workload Identity of the pod.
+func NewSource(opts ...option.ClientOption) Source {
+	return &idTokenSource{opts: opts}
+}
+
+// idTokenSource wraps idtoken.NewTokenSource. Is the source that will be used everywhere except unit tests.
+type idTokenSource struct {
+	opts []option.ClientOption
+}
+
+// Verify that it satisfies the Source interface.
+var _ Source = &idTokenSource{}
+
+// TokenSource implements idtoken.NewTokenSource
+func (s *idTokenSource) TokenSource(ctx context.Context, audience string) (oauth2.TokenSource, error) {
+	return idtoken.NewTokenSource(ctx, audience, s.opts...)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/google/knative-gcp/pkg/gclient/idtoken"
+)
+
+// TestSourceData is the data used to configure the test ID token source.
+type TestSourceData struct {
+	TokenSourceErr error
+	TokenErr       error
+	// Expiry is the lifetime of the issued tokens. Tokens never expire if zero.
+	Expiry time.Duration
+}
+
+// TestSource is a local ID token source that issues fake tokens of the form
+// "<audience>/<n>", where n counts the tokens issued for the audience.
+type TestSource struct {
+	data TestSourceData
+
+	mu     sync.Mutex
+	issued map[string]int
+}
+
+// NewTestSource creates a new TestSource.
+func NewTestSource(data TestSourceData) *TestSource {
+	return &TestSource{
+		data:   data,
+		issued: make(map[string]int),
+	}
+}
+
+// Verify that it satisfies the idtoken.Source interface.
+var _ idtoken.Source = &TestSource{}
+
+// TokenSource implements idtoken.Source.TokenSource
+func (s *TestSource) TokenSource(_ context.Context, audience string) (oauth2.TokenSource, error) {
+	if s.data.TokenSourceErr != nil {
+		return nil, s.data.TokenSourceErr
+	}
+	return &testTokenSource{source: s, audience: audience}, nil
+}
+
+// Issued returns the number of tokens issued for the audience.
+func (s *TestSource) Issued(audience string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issued[audience]
+}
+
+type testTokenSource struct {
+	source   *TestSource
+	audience string
+}
+
+func (ts *testTokenSource) Token() (*oauth2.Token, error) {
+	s := ts.source
+	if s.data.TokenErr != nil {
+		return nil, s.data.TokenErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.issued[ts.audience]++
+	token := &oauth2.Token{
+		AccessToken: fmt.Sprintf("%s/%d", ts.audience, s.issued[ts.audience]),
+		TokenType:   "Bearer",
+	}
+	if s.data.Expiry > 0 {
+		token.Expiry = time.Now().Add(s.data.Expiry)
+	}
+	return token, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+)
+
+// AlertingCreateFn is a factory function to create a Cloud Monitoring alerting client.
+type AlertingCreateFn func(ctx context.Context, opts ...option.ClientOption) (AlertingClient, error)
+
+// NewAlertingClient creates a new wrapped Cloud Monitoring alerting client.
+func NewAlertingClient(ctx context.Context, opts ...option.ClientOption) (AlertingClient, error) {
+	channels, err := monitoring.NewNotificationChannelClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := monitoring.NewAlertPolicyClient(ctx, opts...)
+	if err != nil {
+		channels.Close()
+		return nil, err
+	}
+	return &alertingClient{
+		channels: channels,
+		policies: policies,
+	}, nil
+}
+
+// alertingClient wraps monitoring.NotificationChannelClient and
+// monitoring.AlertPolicyClient. Is the client that will be used everywhere
+// except unit tests.
+type alertingClient struct {
+	channels *monitoring.NotificationChannelClient
+	policies *monitoring.AlertPolicyClient
+}
+
+// Verify that it satisfies the AlertingClient interface.
+var _ AlertingClient = &alertingClient{}
+
+// Close implements monitoring.NotificationChannelClient.Close and
+// monitoring.AlertPolicyClient.Close
+func (c *alertingClient) Close() error {
+	err := c.channels.Close()
+	if perr := c.policies.Close(); err == nil {
+		err = perr
+	}
+	return err
+}
+
+// GetNotificationChannel implements monitoring.NotificationChannelClient.GetNotificationChannel
+func (c *alertingClient) GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.channels.GetNotificationChannel(ctx, req, opts...)
+}
+
+// CreateNotificationChannel implements monitoring.NotificationChannelClient.CreateNotificationChannel
+func (c *alertingClient) CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.channels.CreateNotificationChannel(ctx, req, opts...)
+}
+
+// DeleteNotificationChannel implements monitoring.NotificationChannelClient.DeleteNotificationChannel
+func (c *alertingClient) DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error {
+	return c.channels.DeleteNotificationChannel(ctx, req, opts...)
+}
+
+// GetAlertPolicy implements monitoring.AlertPolicyClient.GetAlertPolicy
+func (c *alertingClient) GetAlertPolicy(ctx context.Context, req *monitoringpb.GetAlertPolicyRequest, opts ...gax.CallOption) (*monitoringpb.AlertPolicy, error) {
+	return c.policies.GetAlertPolicy(ctx, req, opts...)
+}
+
+// UpdateAlertPolicy implements monitoring.AlertPolicyClient.UpdateAlertPolicy
+func (c *alertingClient) UpdateAlertPolicy(ctx context.Context, req *monitoringpb.UpdateAlertPolicyRequest, opts ...gax.CallOption) (*monitoringpb.AlertPolicy, error) {
+	return c.policies.UpdateAlertPolicy(ctx, req, opts...)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	undeliveredMessagesMetric     = "pubsub.googleapis.com/subscription/num_undelivered_messages"
+	oldestUnackedMessageAgeMetric = "pubsub.googleapis.com/subscription/oldest_unacked_message_age"
+
+	// metricsWindow is how far back the latest sample of a metric is looked up.
+	// Pub/Sub samples the subscription metrics every minute, and the samples
+	// can take a few minutes to be visible.
+	metricsWindow = 5 * time.Minute
+)
+
+// CreateFn is a factory function to create a Cloud Monitoring client.
+type CreateFn func(ctx context.Context, opts ...option.ClientOption) (Client, error)
+
+// NewClient creates a new wrapped Cloud Monitoring client.
+func NewClient(ctx context.Context, opts ...option.ClientOption) (Client, error) {
+	client, err := monitoring.NewMetricClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &monitoringClient{
+		client: client,
+	}, nil
+}
+
+// monitoringClient wraps monitoring.MetricClient. Is the client that will be used everywhere except unit tests.
+type monitoringClient struct {
+	client *monitoring.MetricClient
+}
+
+// Verify that it satisfies the Client interface.
+var _ Client = &monitoringClient{}
+
+// Close implements monitoring.MetricClient.Close
+func (c *monitoringClient) Close() error {
+	return c.client.Close()
+}
+
+// SubscriptionBacklog implements Client.SubscriptionBacklog
+func (c *monitoringClient) SubscriptionBacklog(ctx context.Context, projectID, subscriptionID string) (*Backlog, error) {
+	undelivered, err := c.latestSample(ctx, projectID, subscriptionID, undeliveredMessagesMetric)
+	if err != nil {
+		return nil, err
+	}
+	age, err := c.latestSample(ctx, projectID, subscriptionID, oldestUnackedMessageAgeMetric)
+	if err != nil {
+		return nil, err
+	}
+	return &Backlog{
+		UndeliveredMessages:     undelivered,
+		OldestUnackedMessageAge: time.Duration(age) * time.Second,
+	}, nil
+}
+
+// latestSample returns the latest sample of the int64 metric of the
+// subscription.
+func (c *monitoringClient) latestSample(ctx context.Context, projectID, subscriptionID, metric string) (int64, error) {
+	now := time.Now()
+	it := c.client.ListTimeSeries(ctx, &monitoringpb.ListTimeSeriesRequest{
+		Name:   fmt.Sprintf("projects/%s", projectID),
+		Filter: fmt.Sprintf("metric.type = %q AND resource.type = \"pubsub_subscription\" AND resource.labels.subscription_id = %q", metric, subscriptionID),
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(now.Add(-metricsWindow)),
+			EndTime:   timestamppb.New(now),
+		},
+		View: monitoringpb.ListTimeSeriesRequest_FULL,
+	})
+	ts, err := it.Next()
+	if err == iterator.Done {
+		return 0, ErrNoData
+	}
+	if err != nil {
+		return 0, err
+	}
+	// The points of a time series are returned in reverse time order.
+	if len(ts.GetPoints()) == 0 {
+		return 0, ErrNoData
+	}
+	return ts.GetPoints()[0].GetValue().GetInt64Value(), nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/googleapis/gax-go/v2"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+)
+
+// ErrNoData is returned when Cloud Monitoring has no recent sample of the
+// metrics of a subscription, e.g. because the subscription was just created.
+var ErrNoData = errors.New("no recent metrics data")
+
+// Backlog is the backlog of a Pub/Sub subscription.
+type Backlog struct {
+	// UndeliveredMessages is the number of unacknowledged messages in the
+	// subscription.
+	UndeliveredMessages int64
+	// OldestUnackedMessageAge is the age of the oldest unacknowledged message in
+	// the subscription.
+	OldestUnackedMessageAge time.Duration
+}
+
+// Client reads the metrics of Pub/Sub subscriptions from Cloud Monitoring.
+// It wraps monitoring.MetricClient, see
+// https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#MetricClient
+type Client interface {
+	// Close see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#MetricClient.Close
+	Close() error
+	// SubscriptionBacklog returns the latest backlog of the subscription
+	// reported by the pubsub.googleapis.com/subscription/num_undelivered_messages
+	// and pubsub.googleapis.com/subscription/oldest_unacked_message_age metrics.
+	// It returns ErrNoData if no recent sample of the metrics exists.
+	SubscriptionBacklog(ctx context.Context, projectID, subscriptionID string) (*Backlog, error)
+}
+
+// AlertingClient manages the notification channels and the alert policies of
+// Cloud Monitoring. It wraps monitoring.NotificationChannelClient and
+// monitoring.AlertPolicyClient, see
+// https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient
+// and https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#AlertPolicyClient
+type AlertingClient interface {
+	// Close closes both wrapped clients.
+	Close() error
+	// GetNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient.GetNotificationChannel
+	GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error)
+	// CreateNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient.CreateNotificationChannel
+	CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error)
+	// DeleteNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#NotificationChannelClient.DeleteNotificationChannel
+	DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error
+	// GetAlertPolicy see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#AlertPolicyClient.GetAlertPolicy
+	GetAlertPolicy(ctx context.Context, req *monitoringpb.GetAlertPolicyRequest, opts ...gax.CallOption) (*monitoringpb.AlertPolicy, error)
+	// UpdateAlertPolicy see https://godoc.org/cloud.google.com/go/monitoring/apiv3/v2#AlertPolicyClient.UpdateAlertPolicy
+	UpdateAlertPolicy(ctx context.Context, req *monitoringpb.UpdateAlertPolicyRequest, opts ...gax.CallOption) (*monitoringpb.AlertPolicy, error)
+}
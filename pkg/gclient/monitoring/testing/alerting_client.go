@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/google/knative-gcp/pkg/gclient/monitoring"
+)
+
+// TestAlertingClientCreator returns a monitoring.AlertingCreateFn used to construct the test Cloud Monitoring alerting client.
+func TestAlertingClientCreator(value interface{}) monitoring.AlertingCreateFn {
+	var data TestAlertingClientData
+	var ok bool
+	if data, ok = value.(TestAlertingClientData); !ok {
+		data = TestAlertingClientData{}
+	}
+	if data.CreateClientErr != nil {
+		return func(_ context.Context, _ ...option.ClientOption) (monitoring.AlertingClient, error) {
+			return nil, data.CreateClientErr
+		}
+	}
+
+	return func(_ context.Context, _ ...option.ClientOption) (monitoring.AlertingClient, error) {
+		return &testAlertingClient{
+			data: data,
+		}, nil
+	}
+}
+
+// TestAlertingClientData is the data used to configure the test Cloud Monitoring alerting client.
+type TestAlertingClientData struct {
+	CreateClientErr              error
+	GetNotificationChannelErr    error
+	CreateNotificationChannelErr error
+	DeleteNotificationChannelErr error
+	GetAlertPolicyErr            error
+	UpdateAlertPolicyErr         error
+	CloseErr                     error
+	// NotificationChannelID is the ID given to the channel created by
+	// CreateNotificationChannel.
+	NotificationChannelID string
+	// NotificationChannels are the existing notification channels, by name.
+	// GetNotificationChannel returns a codes.NotFound status for the other
+	// notification channels.
+	NotificationChannels map[string]*monitoringpb.NotificationChannel
+	// AlertPolicies are the existing alert policies, by name. GetAlertPolicy
+	// returns a codes.NotFound status for the other alert policies.
+	AlertPolicies map[string]*monitoringpb.AlertPolicy
+}
+
+// testAlertingClient is the test Cloud Monitoring alerting client.
+type testAlertingClient struct {
+	data TestAlertingClientData
+}
+
+// Verify that it satisfies the monitoring.AlertingClient interface.
+var _ monitoring.AlertingClient = &testAlertingClient{}
+
+// Close implements client.Close
+func (c *testAlertingClient) Close() error {
+	return c.data.CloseErr
+}
+
+// GetNotificationChannel implements client.GetNotificationChannel
+func (c *testAlertingClient) GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	if c.data.GetNotificationChannelErr != nil {
+		return nil, c.data.GetNotificationChannelErr
+	}
+	channel, ok := c.data.NotificationChannels[req.Name]
+	if !ok {
+		return nil, gstatus.Errorf(codes.NotFound, "notification channel %q not found", req.Name)
+	}
+	return proto.Clone(channel).(*monitoringpb.NotificationChannel), nil
+}
+
+// CreateNotificationChannel implements client.CreateNotificationChannel
+func (c *testAlertingClient) CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	if c.data.CreateNotificationChannelErr != nil {
+		return nil, c.data.CreateNotificationChannelErr
+	}
+	channel := proto.Clone(req.NotificationChannel).(*monitoringpb.NotificationChannel)
+	channel.Name = fmt.Sprintf("%s/notificationChannels/%s", req.Name, c.data.NotificationChannelID)
+	return channel, nil
+}
+
+// DeleteNotificationChannel implements client.DeleteNotificationChannel
+func (c *testAlertingClient) DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error {
+	return c.data.DeleteNotificationChannelErr
+}
+
+// GetAlertPolicy implements client.GetAlertPolicy
+func (c *testAlertingClient) GetAlertPolicy(ctx context.Context, req *monitoringpb.GetAlertPolicyRequest, opts ...gax.CallOption) (*monitoringpb.AlertPolicy, error) {
+	if c.data.GetAlertPolicyErr != nil {
+		return nil, c.data.GetAlertPolicyErr
+	}
+	policy, ok := c.data.AlertPolicies[req.Name]
+	if !ok {
+		return nil, gstatus.Errorf(codes.NotFound, "alert policy %q not found", req.Name)
+	}
+	return proto.Clone(policy).(*monitoringpb.AlertPolicy), nil
+}
+
+// UpdateAlertPolicy implements client.UpdateAlertPolicy
+func (c *testAlertingClient) UpdateAlertPolicy(ctx context.Context, req *monitoringpb.UpdateAlertPolicyRequest, opts ...gax.CallOption) (*monitoringpb.AlertPolicy, error) {
+	if c.data.UpdateAlertPolicyErr != nil {
+		return nil, c.data.UpdateAlertPolicyErr
+	}
+	return req.AlertPolicy, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+
+	"google.golang.org/api/option"
+
+	"github.com/google/knative-gcp/pkg/gclient/monitoring"
+)
+
+// TestClientCreator returns a monitoring.CreateFn used to construct the test Cloud Monitoring client.
+func TestClientCreator(value interface{}) monitoring.CreateFn {
+	var data TestClientData
+	var ok bool
+	if data, ok = value.(TestClientData); !ok {
+		data = TestClientData{}
+	}
+	if data.CreateClientErr != nil {
+		return func(_ context.Context, _ ...option.ClientOption) (monitoring.Client, error) {
+			return nil, data.CreateClientErr
+		}
+	}
+
+	return func(_ context.Context, _ ...option.ClientOption) (monitoring.Client, error) {
+		return &testClient{
+			data: data,
+		}, nil
+	}
+}
+
+// TestClientData is the data used to configure the test Cloud Monitoring client.
+type TestClientData struct {
+	CreateClientErr        error
+	SubscriptionBacklogErr error
+	CloseErr               error
+	// Backlogs are the backlogs of the subscriptions, by subscription ID.
+	// SubscriptionBacklog returns monitoring.ErrNoData for the other
+	// subscriptions.
+	Backlogs map[string]monitoring.Backlog
+}
+
+// testClient is the test Cloud Monitoring client.
+type testClient struct {
+	data TestClientData
+}
+
+// Verify that it satisfies the monitoring.Client interface.
+var _ monitoring.Client = &testClient{}
+
+// Close implements client.Close
+func (c *testClient) Close() error {
+	return c.data.CloseErr
+}
+
+// SubscriptionBacklog implements client.SubscriptionBacklog
+func (c *testClient) SubscriptionBacklog(ctx context.Context, projectID, subscriptionID string) (*monitoring.Backlog, error) {
+	if c.data.SubscriptionBacklogErr != nil {
+		return nil, c.data.SubscriptionBacklogErr
+	}
+	backlog, ok := c.data.Backlogs[subscriptionID]
+	if !ok {
+		return nil, monitoring.ErrNoData
+	}
+	return &backlog, nil
+}
@@ -18,6 +18,7 @@ package pubsub
 
 import (
 	"context"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/knative-gcp/pkg/gclient/iam"
@@ -38,6 +39,8 @@ type Client interface {
 	CreateTopic(ctx context.Context, id string) (Topic, error)
 	// CreateTopicWithConfig see https://godoc.org/cloud.google.com/go/pubsub#Client.CreateTopicWithConfig
 	CreateTopicWithConfig(ctx context.Context, id string, cfg *pubsub.TopicConfig) (Topic, error)
+	// Snapshot see https://godoc.org/cloud.google.com/go/pubsub#Client.Snapshot
+	Snapshot(id string) Snapshot
 }
 
 // Subscription matches the interface exposed by pubsub.Subscription
@@ -53,6 +56,21 @@ type Subscription interface {
 	Delete(ctx context.Context) error
 	// ID see https://godoc.org/cloud.google.com/go/pubsub#Subscription.ID
 	ID() string
+	// SeekToTime see https://godoc.org/cloud.google.com/go/pubsub#Subscription.SeekToTime
+	SeekToTime(ctx context.Context, t time.Time) error
+	// SeekToSnapshot see https://godoc.org/cloud.google.com/go/pubsub#Subscription.SeekToSnapshot
+	SeekToSnapshot(ctx context.Context, snap Snapshot) error
+	// CreateSnapshot see https://godoc.org/cloud.google.com/go/pubsub#Subscription.CreateSnapshot
+	CreateSnapshot(ctx context.Context, name string) (*SnapshotConfig, error)
+}
+
+// Snapshot matches the interface exposed by pubsub.Snapshot
+// see https://godoc.org/cloud.google.com/go/pubsub#Snapshot
+type Snapshot interface {
+	// ID see https://godoc.org/cloud.google.com/go/pubsub#Snapshot.ID
+	ID() string
+	// Delete see https://godoc.org/cloud.google.com/go/pubsub#Snapshot.Delete
+	Delete(ctx context.Context) error
 }
 
 // Topic matches the interface exposed by pubsub.Topic
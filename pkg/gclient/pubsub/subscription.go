@@ -18,6 +18,7 @@ package pubsub
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"cloud.google.com/go/pubsub"
@@ -91,3 +92,30 @@ func (s *pubsubSubscription) Delete(ctx context.Context) error {
 func (s *pubsubSubscription) ID() string {
 	return s.sub.ID()
 }
+
+// SeekToTime implements pubsub.Subscription.SeekToTime
+func (s *pubsubSubscription) SeekToTime(ctx context.Context, t time.Time) error {
+	return s.sub.SeekToTime(ctx, t)
+}
+
+// SeekToSnapshot implements pubsub.Subscription.SeekToSnapshot
+func (s *pubsubSubscription) SeekToSnapshot(ctx context.Context, snap Snapshot) error {
+	snapshot, ok := snap.(*pubsubSnapshot)
+	if !ok {
+		return fmt.Errorf("unsupported snapshot type %T", snap)
+	}
+	return s.sub.SeekToSnapshot(ctx, snapshot.snapshot)
+}
+
+// CreateSnapshot implements pubsub.Subscription.CreateSnapshot
+func (s *pubsubSubscription) CreateSnapshot(ctx context.Context, name string) (*SnapshotConfig, error) {
+	cfg, err := s.sub.CreateSnapshot(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotConfig{
+		Snapshot:   &pubsubSnapshot{snapshot: cfg.Snapshot},
+		Topic:      &pubsubTopic{topic: cfg.Topic},
+		Expiration: cfg.Expiration,
+	}, nil
+}
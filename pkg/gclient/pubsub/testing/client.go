@@ -53,6 +53,7 @@ type TestClientData struct {
 	CloseErr              error
 	TopicData             TestTopicData
 	SubscriptionData      TestSubscriptionData
+	SnapshotData          TestSnapshotData
 	HandleData            testiam.TestHandleData
 }
 
@@ -92,3 +93,8 @@ func (c *testClient) CreateTopic(ctx context.Context, id string) (gpubsub.Topic,
 func (c *testClient) CreateTopicWithConfig(ctx context.Context, id string, cfg *pubsub.TopicConfig) (gpubsub.Topic, error) {
 	return &testTopic{data: c.data.TopicData, handleData: c.data.HandleData, id: id, config: cfg}, c.data.CreateTopicErr
 }
+
+// Snapshot implements Client.Snapshot.
+func (c *testClient) Snapshot(id string) gpubsub.Snapshot {
+	return &testSnapshot{data: c.data.SnapshotData, id: id}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+
+	"github.com/google/knative-gcp/pkg/gclient/pubsub"
+)
+
+// testSnapshot is a test Pub/Sub snapshot.
+type testSnapshot struct {
+	data TestSnapshotData
+	id   string
+}
+
+// TestSnapshotData is the data used to configure the test Snapshot.
+type TestSnapshotData struct {
+	DeleteErr error
+}
+
+// Verify that it satisfies the pubsub.Snapshot interface.
+var _ pubsub.Snapshot = &testSnapshot{}
+
+// ID implements Snapshot.ID.
+func (s *testSnapshot) ID() string {
+	return s.id
+}
+
+// Delete implements Snapshot.Delete.
+func (s *testSnapshot) Delete(ctx context.Context) error {
+	return s.data.DeleteErr
+}
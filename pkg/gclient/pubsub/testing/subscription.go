@@ -18,6 +18,7 @@ package testing
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/gclient/pubsub"
 )
@@ -30,11 +31,13 @@ type testSubscription struct {
 
 // TestSubscriptionData is the data used to configure the test Subscription.
 type TestSubscriptionData struct {
-	ExistsErr error
-	Exists    bool
-	ConfigErr error
-	UpdateErr error
-	DeleteErr error
+	ExistsErr         error
+	Exists            bool
+	ConfigErr         error
+	UpdateErr         error
+	DeleteErr         error
+	SeekErr           error
+	CreateSnapshotErr error
 }
 
 // Verify that it satisfies the pubsub.Subscription interface.
@@ -63,3 +66,23 @@ func (s *testSubscription) Delete(ctx context.Context) error {
 func (s *testSubscription) ID() string {
 	return s.id
 }
+
+// SeekToTime implements Subscription.SeekToTime.
+func (s *testSubscription) SeekToTime(ctx context.Context, t time.Time) error {
+	return s.data.SeekErr
+}
+
+// SeekToSnapshot implements Subscription.SeekToSnapshot.
+func (s *testSubscription) SeekToSnapshot(ctx context.Context, snap pubsub.Snapshot) error {
+	return s.data.SeekErr
+}
+
+// CreateSnapshot implements Subscription.CreateSnapshot.
+func (s *testSubscription) CreateSnapshot(ctx context.Context, name string) (*pubsub.SnapshotConfig, error) {
+	if s.data.CreateSnapshotErr != nil {
+		return nil, s.data.CreateSnapshotErr
+	}
+	return &pubsub.SnapshotConfig{
+		Snapshot: &testSnapshot{id: name},
+	}, nil
+}
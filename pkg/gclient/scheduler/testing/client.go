@@ -53,7 +53,12 @@ type TestClientData struct {
 	DeleteJobErr    error
 	UpdateJobErr    error
 	GetJobErr       error
+	PauseJobErr     error
+	ResumeJobErr    error
 	CloseErr        error
+	// Job is the existing job returned by GetJob. GetJob returns a job with
+	// the requested name only if it is nil.
+	Job *schedulerpb.Job
 }
 
 // testClient is the test Scheduler client.
@@ -99,7 +104,32 @@ func (c *testClient) GetJob(ctx context.Context, req *schedulerpb.GetJobRequest,
 	if c.data.GetJobErr != nil {
 		return nil, c.data.GetJobErr
 	}
+	if c.data.Job != nil {
+		return c.data.Job, nil
+	}
 	return &schedulerpb.Job{
 		Name: req.Name,
 	}, nil
 }
+
+// PauseJob implements client.PauseJob
+func (c *testClient) PauseJob(ctx context.Context, req *schedulerpb.PauseJobRequest, opts ...gax.CallOption) (*schedulerpb.Job, error) {
+	if c.data.PauseJobErr != nil {
+		return nil, c.data.PauseJobErr
+	}
+	return &schedulerpb.Job{
+		Name:  req.Name,
+		State: schedulerpb.Job_PAUSED,
+	}, nil
+}
+
+// ResumeJob implements client.ResumeJob
+func (c *testClient) ResumeJob(ctx context.Context, req *schedulerpb.ResumeJobRequest, opts ...gax.CallOption) (*schedulerpb.Job, error) {
+	if c.data.ResumeJobErr != nil {
+		return nil, c.data.ResumeJobErr
+	}
+	return &schedulerpb.Job{
+		Name:  req.Name,
+		State: schedulerpb.Job_ENABLED,
+	}, nil
+}
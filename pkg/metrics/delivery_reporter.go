@@ -45,6 +45,8 @@ type DeliveryReporter struct {
 	containerName         ContainerName
 	dispatchTimeInMsecM   *stats.Float64Measure
 	processingTimeInMsecM *stats.Float64Measure
+	batchSizeM            *stats.Int64Measure
+	throttledM            *stats.Int64Measure
 }
 
 func (r *DeliveryReporter) register() error {
@@ -86,6 +88,30 @@ func (r *DeliveryReporter) register() error {
 				ContainerNameKey,
 			},
 		},
+		&view.View{
+			Name:        r.batchSizeM.Name(),
+			Description: r.batchSizeM.Description(),
+			Measure:     r.batchSizeM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 1000)...), // 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000
+			TagKeys: []tag.Key{
+				TriggerFilterTypeKey,
+				ResponseCodeKey,
+				ResponseCodeClassKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
+		&view.View{
+			Name:        r.throttledM.Name(),
+			Description: r.throttledM.Description(),
+			Measure:     r.throttledM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			},
+		},
 	)
 }
 
@@ -108,6 +134,20 @@ func NewDeliveryReporter(podName PodName, containerName ContainerName) (*Deliver
 			"The time spent processing an event before it is dispatched to a Trigger subscriber",
 			stats.UnitMilliseconds,
 		),
+		// batchSizeM records the number of events in each batch dispatched
+		// to a Trigger subscriber.
+		batchSizeM: stats.Int64(
+			"event_batch_size",
+			"The number of events in a batch dispatched to a Trigger subscriber",
+			stats.UnitDimensionless,
+		),
+		// throttledM records the events whose delivery to a Trigger
+		// subscriber was throttled by the Trigger's rate limit.
+		throttledM: stats.Int64(
+			"throttled_event_count",
+			"Number of events whose delivery to a Trigger subscriber was throttled",
+			stats.UnitDimensionless,
+		),
 	}
 
 	if err := r.register(); err != nil {
@@ -123,6 +163,19 @@ func (r *DeliveryReporter) ReportEventDispatchTime(ctx context.Context, d time.D
 	metrics.Record(ctx, r.dispatchTimeInMsecM.M(float64(d/time.Millisecond)), stats.WithAttachments(attachments))
 }
 
+// ReportBatchSize captures the sizes of dispatched batches. The dispatch time
+// of each event in the batch is reported separately with
+// ReportEventDispatchTime.
+func (r *DeliveryReporter) ReportBatchSize(ctx context.Context, size int) {
+	attachments := getSpanContextAttachments(ctx)
+	metrics.Record(ctx, r.batchSizeM.M(int64(size)), stats.WithAttachments(attachments))
+}
+
+// ReportThrottledEvent counts an event whose delivery was throttled.
+func (r *DeliveryReporter) ReportThrottledEvent(ctx context.Context) {
+	metrics.Record(ctx, r.throttledM.M(1))
+}
+
 // StartEventProcessing records the start of event processing for delivery within the given context.
 func StartEventProcessing(ctx context.Context) context.Context {
 	return context.WithValue(ctx, startDeliveryProcessingTime, time.Now())
@@ -74,6 +74,91 @@ func TestReportEventDispatchTime(t *testing.T) {
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
 }
 
+func TestReportBatchSize(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType:        "testeventtype",
+		metricskey.LabelResponseCode:      "202",
+		metricskey.LabelResponseCodeClass: "2xx",
+		metricskey.PodName:                "testpod",
+		metricskey.ContainerName:          "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace:      "testns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "testbroker",
+		Name:           "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cctx, _ := AddRespStatusCodeTags(ctx, 202)
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportBatchSize(cctx, 3)
+		return nil
+	})
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportBatchSize(cctx, 10)
+		return nil
+	})
+	metricstest.CheckDistributionData(t, "event_batch_size", wantTags, 2, 3.0, 10.0)
+}
+
+func TestReportThrottledEvent(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelFilterType: "testeventtype",
+		metricskey.PodName:         "testpod",
+		metricskey.ContainerName:   "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace:      "testns",
+		CellTenantType: config.CellTenantType_BROKER,
+		CellTenantName: "testbroker",
+		Name:           "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportThrottledEvent(ctx)
+		return nil
+	})
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportThrottledEvent(ctx)
+		return nil
+	})
+	metricstest.CheckCountData(t, "throttled_event_count", wantTags, 2)
+}
+
 func TestReportEventProcessingTime(t *testing.T) {
 	reportertest.ResetDeliveryMetrics()
 
@@ -24,9 +24,10 @@ import (
 )
 
 const (
-	defaultEventType  = "custom"
-	labelResourceKind = "resource_kind"
-	labelResourceName = "resource_name"
+	defaultEventType    = "custom"
+	labelResourceKind   = "resource_kind"
+	labelResourceName   = "resource_name"
+	labelGenerationType = "generation_type"
 )
 
 type PodName string
@@ -44,6 +45,7 @@ var (
 	EventTypeKey         = tag.MustNewKey(metricskey.LabelEventType)
 	ResourceKindKey      = tag.MustNewKey(labelResourceKind)
 	ResourceNameKey      = tag.MustNewKey(labelResourceName)
+	GenerationTypeKey    = tag.MustNewKey(labelGenerationType)
 	TriggerNameKey       = tag.MustNewKey(metricskey.LabelTriggerName)
 	TriggerFilterTypeKey = tag.MustNewKey(metricskey.LabelFilterType)
 
@@ -59,6 +61,7 @@ var (
 		"e2e-sample-event-type":              {},
 		"e2e-testing-resp-event-type-sample": {},
 		"_invalid_cloud_event_":              {},
+		"_unauthorized_":                     {},
 	}
 )
 
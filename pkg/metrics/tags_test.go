@@ -42,6 +42,9 @@ func TestEventTypeMetricValue(t *testing.T) {
 		// Used to mark invalid cloud events
 		{"_invalid_cloud_event_", "_invalid_cloud_event_"},
 
+		// Used to mark unauthorized requests
+		{"_unauthorized_", "_unauthorized_"},
+
 		// Used in E2E tests
 		{"e2e-sample-event-type", "e2e-sample-event-type"},
 		{"e2e-testing-resp-event-type-sample", "e2e-testing-resp-event-type-sample"},
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+const (
+	TargetsConfigGenerationLatencyMetricName = "targets_config_generation_latencies"
+	TargetsConfigSizeMetricName              = "targets_config_size"
+
+	// FullGeneration is the generation type of a targets config generated
+	// from all the Brokers of a BrokerCell.
+	FullGeneration = "full"
+	// IncrementalGeneration is the generation type of a targets config in
+	// which only the changed Brokers are regenerated.
+	IncrementalGeneration = "incremental"
+)
+
+// TargetsConfigReporter reports the latency and the size of the targets
+// config generated by the BrokerCell reconciler.
+type TargetsConfigReporter struct {
+	generationLatencyInMsecM *stats.Float64Measure
+	sizeInBytesM             *stats.Int64Measure
+}
+
+func (r *TargetsConfigReporter) register() error {
+	return metrics.RegisterResourceView(
+		&view.View{
+			Name:        r.generationLatencyInMsecM.Name(),
+			Description: r.generationLatencyInMsecM.Description(),
+			Measure:     r.generationLatencyInMsecM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 100000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000, 20000, 50000, 1000000
+			TagKeys: []tag.Key{
+				NamespaceNameKey,
+				ResourceNameKey,
+				GenerationTypeKey,
+			},
+		},
+		&view.View{
+			Name:        r.sizeInBytesM.Name(),
+			Description: r.sizeInBytesM.Description(),
+			Measure:     r.sizeInBytesM,
+			Aggregation: view.LastValue(),
+			TagKeys: []tag.Key{
+				NamespaceNameKey,
+				ResourceNameKey,
+			},
+		},
+	)
+}
+
+// NewTargetsConfigReporter creates a new TargetsConfigReporter
+func NewTargetsConfigReporter() (*TargetsConfigReporter, error) {
+	r := &TargetsConfigReporter{
+		generationLatencyInMsecM: stats.Float64(
+			TargetsConfigGenerationLatencyMetricName,
+			"The time spent generating the targets config of a BrokerCell in milliseconds",
+			stats.UnitMilliseconds,
+		),
+		sizeInBytesM: stats.Int64(
+			TargetsConfigSizeMetricName,
+			"The size of the serialized targets config of a BrokerCell in bytes",
+			stats.UnitBytes,
+		),
+	}
+	if err := r.register(); err != nil {
+		return nil, fmt.Errorf("failed to register TargetsConfigReporter: %w", err)
+	}
+	return r, nil
+}
+
+// ReportGeneration records the latency of a targets config generation and the
+// size of the generated targets config of the BrokerCell.
+func (r *TargetsConfigReporter) ReportGeneration(ctx context.Context, namespace, brokerCell, generationType string, latency time.Duration, size int) error {
+	ctx, err := tag.New(
+		ctx,
+		tag.Insert(NamespaceNameKey, namespace),
+		tag.Insert(ResourceNameKey, brokerCell),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create metrics tag: %w", err)
+	}
+	metrics.Record(ctx, r.sizeInBytesM.M(int64(size)))
+
+	ctx, err = tag.New(ctx, tag.Insert(GenerationTypeKey, generationType))
+	if err != nil {
+		return fmt.Errorf("failed to create metrics tag: %w", err)
+	}
+	metrics.Record(ctx, r.generationLatencyInMsecM.M(float64(latency/time.Millisecond)))
+	return nil
+}
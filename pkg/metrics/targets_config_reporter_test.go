@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func TestReportGeneration(t *testing.T) {
+	for _, generationType := range []string{FullGeneration, IncrementalGeneration} {
+		t.Run(generationType, func(t *testing.T) {
+			reportertest.ResetTargetsConfigMetrics()
+			r, err := NewTargetsConfigReporter()
+			if err != nil {
+				t.Fatal(err)
+			}
+			samples := []struct {
+				latency time.Duration
+				size    int
+			}{
+				{latency: 5 * time.Millisecond, size: 1024},
+				{latency: 100 * time.Millisecond, size: 4096},
+				{latency: 10 * time.Millisecond, size: 2048},
+			}
+			for _, sample := range samples {
+				reportertest.ExpectMetrics(t, func() error {
+					return r.ReportGeneration(context.Background(), "events-system", "default", generationType, sample.latency, sample.size)
+				})
+			}
+
+			metricstest.CheckLastValueData(t, TargetsConfigSizeMetricName, map[string]string{
+				metricskey.LabelNamespaceName: "events-system",
+				labelResourceName:             "default",
+			}, 2048)
+			metricstest.CheckDistributionData(t, TargetsConfigGenerationLatencyMetricName, map[string]string{
+				metricskey.LabelNamespaceName: "events-system",
+				labelResourceName:             "default",
+				labelGenerationType:           generationType,
+			}, 3, 5.0, 100.0)
+		})
+	}
+}
@@ -13,7 +13,7 @@ func ResetIngressMetrics() {
 
 func ResetDeliveryMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
-	metricstest.Unregister("event_count", "event_dispatch_latencies", "event_processing_latencies")
+	metricstest.Unregister("event_count", "event_dispatch_latencies", "event_processing_latencies", "event_batch_size", "throttled_event_count")
 }
 
 func ResetBrokerCellMetrics() {
@@ -21,6 +21,11 @@ func ResetBrokerCellMetrics() {
 	metricstest.Unregister("brokercell_delay")
 }
 
+func ResetTargetsConfigMetrics() {
+	// OpenCensus metrics carry global state that need to be reset between unit tests.
+	metricstest.Unregister("targets_config_generation_latencies", "targets_config_size")
+}
+
 func ExpectMetrics(t *testing.T, f func() error) {
 	t.Helper()
 	if err := f(); err != nil {
@@ -17,7 +17,10 @@ limitations under the License.
 package adapter
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	nethttp "net/http"
 
 	"go.uber.org/zap"
@@ -52,12 +55,29 @@ type AdapterArgs struct {
 	// Used for channels.
 	TransformerURI string
 
+	// ReplyURI is the URI the replies of the sink are sent to. The replies
+	// are discarded if it is empty.
+	ReplyURI string
+
 	// Extensions is the converted ExtensionsBased64 value.
 	Extensions map[string]string
 
 	// ConverterType use to select which converter to use.
 	ConverterType converters.ConverterType
 
+	// Filter decides which of the converted events are delivered. All the
+	// events are delivered if it is nil.
+	Filter EventFilter
+
+	// Mapper computes attributes of the converted events from their data,
+	// before the extensions are applied. The events are not mapped if it is
+	// nil.
+	Mapper *EventMapper
+
+	// Mode is the encoding of the requests sent to the transformer and the
+	// sink. Defaults to binary.
+	Mode converters.ModeType
+
 	// AuthType is the authentication configuration mode the Pod uses.
 	AuthType authcheck.AuthType
 }
@@ -145,11 +165,25 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 	event, err := a.converter.Convert(ctx, msg, a.args.ConverterType)
 	if err != nil {
 		a.logger.Debug("Failed to convert received message to an event, check the msg format: %v", zap.Error(err))
+		a.reporter.ReportConversionErrorCount(&ReportArgs{})
 		// Ack the message so it won't be retried, we consider all errors to be non-retryable.
 		msg.Ack()
 		return
 	}
 
+	if a.args.Mapper != nil {
+		if err := a.args.Mapper.Map(event); err != nil {
+			a.logger.Debug("Failed to map the event", zap.String("id", event.ID()), zap.Error(err))
+			a.reporter.ReportConversionErrorCount(&ReportArgs{
+				EventType:   event.Type(),
+				EventSource: event.Source(),
+			})
+			// As the conversion errors, the mapping errors are non-retryable.
+			msg.Ack()
+			return
+		}
+	}
+
 	ctx, span := a.startSpan(ctx, event)
 	defer span.End()
 
@@ -158,6 +192,22 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 		EventSource: event.Source(),
 	}
 
+	// Ack the events filtered out so that they aren't redelivered.
+	if a.args.Filter != nil && !a.args.Filter.Matches(event) {
+		a.logger.Debug("Event filtered out", zap.String("id", event.ID()))
+		a.reporter.ReportFilteredEventCount(args)
+		msg.Ack()
+		return
+	}
+
+	if a.args.Mode == converters.Raw {
+		if err := converters.ToRawData(event, msg); err != nil {
+			a.logger.Debug("Failed to set the data of the message on the event", zap.Error(err))
+			msg.Ack()
+			return
+		}
+	}
+
 	// Using this variable to check whether the event came from a reply or not.
 	reply := false
 
@@ -166,7 +216,7 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 	// in case both subscriber and reply are set. The transformer would act as the subscriber and the sink will be where
 	// we will send the reply.
 	if a.args.TransformerURI != "" {
-		resp, err := a.sendMsg(ctx, a.args.TransformerURI, (*binding.EventMessage)(event))
+		resp, err := a.sendEvent(ctx, a.args.TransformerURI, event, msg)
 		if err != nil {
 			a.logger.Error("Failed to send message to transformer", zap.String("address", a.args.TransformerURI), zap.Error(err))
 			msg.Nack()
@@ -220,7 +270,13 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 		}
 	}
 
-	response, err := a.sendMsg(ctx, a.args.SinkURI, (*binding.EventMessage)(event))
+	original := msg
+	if reply {
+		// The reply was not converted from the Pub/Sub message.
+		original = nil
+	}
+
+	response, err := a.sendEvent(ctx, a.args.SinkURI, event, original)
 	if err != nil {
 		a.logger.Error("Failed to send message to sink", zap.String("address", a.args.SinkURI), zap.Error(err))
 		msg.Nack()
@@ -241,9 +297,75 @@ func (a *Adapter) receive(ctx context.Context, msg *pubsub.Message) {
 		return
 	}
 
+	if a.args.ReplyURI != "" {
+		if err := a.sendReply(ctx, response); err != nil {
+			a.logger.Error("Failed to send reply", zap.String("address", a.args.ReplyURI), zap.Error(err))
+			msg.Nack()
+			return
+		}
+	}
+
 	msg.Ack()
 }
 
+// sendReply sends the event the sink replied with, if any, to the reply URI.
+func (a *Adapter) sendReply(ctx context.Context, resp *nethttp.Response) error {
+	respMsg := cehttp.NewMessageFromHttpResponse(resp)
+	if respMsg.ReadEncoding() == binding.EncodingUnknown {
+		// No reply
+		return nil
+	}
+	event, err := binding.ToEvent(ctx, respMsg)
+	if err != nil {
+		return fmt.Errorf("failed to convert response message to event: %w", err)
+	}
+
+	// The reply was not converted from the Pub/Sub message.
+	replyResp, err := a.sendEvent(ctx, a.args.ReplyURI, event, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := replyResp.Body.Close(); err != nil {
+			a.logger.Warn("Failed to close response body", zap.Error(err))
+		}
+	}()
+	if replyResp.StatusCode/100 != 2 {
+		return fmt.Errorf("reply delivery failed with status code %d", replyResp.StatusCode)
+	}
+	return nil
+}
+
+// sendEvent sends the event to address with the encoding of the mode. In the
+// push mode, the Pub/Sub message the event was converted from is sent instead,
+// the replies which weren't converted from a message are sent as CloudEvents.
+func (a *Adapter) sendEvent(ctx context.Context, address string, event *cev2.Event, msg *pubsub.Message) (*nethttp.Response, error) {
+	switch a.args.Mode {
+	case converters.Push:
+		if msg != nil {
+			return a.sendPushMsg(ctx, address, msg)
+		}
+	case converters.Structured:
+		ctx = binding.WithForceStructured(ctx)
+	}
+	return a.sendMsg(ctx, address, (*binding.EventMessage)(event))
+}
+
+// sendPushMsg sends the Pub/Sub message to address as Cloud Pub/Sub push
+// subscriptions do.
+func (a *Adapter) sendPushMsg(ctx context.Context, address string, msg *pubsub.Message) (*nethttp.Response, error) {
+	body, err := json.Marshal(converters.NewPushMessage(a.subscription.String(), msg))
+	if err != nil {
+		return nil, err
+	}
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, address, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return a.outbound.Do(req)
+}
+
 func (a *Adapter) sendMsg(ctx context.Context, address string, msg binding.Message) (*nethttp.Response, error) {
 	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, address, nil)
 	if err != nil {
@@ -74,9 +74,11 @@ func testPubsubClient(ctx context.Context, t *testing.T, projectID string) (*pub
 }
 
 type metricLabels struct {
-	CeType     string
-	CeSource   string
-	StatusCode int
+	CeType          string
+	CeSource        string
+	StatusCode      int
+	Filtered        bool
+	ConversionError bool
 }
 
 type statsReporterRecorder struct {
@@ -88,6 +90,22 @@ func (r *statsReporterRecorder) ReportEventCount(args *ReportArgs, responseCode
 	return nil
 }
 
+func (r *statsReporterRecorder) ReportFilteredEventCount(args *ReportArgs) error {
+	r.labels = append(r.labels, metricLabels{CeType: args.EventType, CeSource: args.EventSource, Filtered: true})
+	return nil
+}
+
+func (r *statsReporterRecorder) ReportConversionErrorCount(args *ReportArgs) error {
+	r.labels = append(r.labels, metricLabels{CeType: args.EventType, CeSource: args.EventSource, ConversionError: true})
+	return nil
+}
+
+type eventFilterFunc func(*cev2.Event) bool
+
+func (f eventFilterFunc) Matches(event *cev2.Event) bool {
+	return f(event)
+}
+
 type mockConverter struct {
 	converted *cev2.Event
 }
@@ -105,16 +123,54 @@ func TestAdapter(t *testing.T) {
 	convertedEvent.SetID("converted")
 	replyEvent := convertedEvent.Clone()
 	replyEvent.SetType("new-type")
+	jsonEvent := convertedEvent.Clone()
+	jsonEvent.SetData(cev2.ApplicationJSON, map[string]string{"kind": "object", "name": "obj"})
+	mappedEvent := jsonEvent.Clone()
+	mappedEvent.SetType("com.example.object")
+	mappedEvent.SetExtension("name", "obj")
+	textEvent := convertedEvent.Clone()
+	textEvent.SetData(cev2.TextPlain, "not json")
+	mapper, err := NewEventMapper(`{"type":"com.example.{.kind}","extensions":{"name":"{.name}"}}`)
+	if err != nil {
+		t.Fatalf("failed to create the event mapper: %v", err)
+	}
 
 	cases := []struct {
 		name             string
 		original         *event.Event
 		converted        *event.Event
 		reply            *event.Event
+		filter           EventFilter
+		mapper           *EventMapper
+		mapped           *event.Event
 		wantMetricLabels []metricLabels
 	}{{
 		name:     "converter fails",
 		original: sampleEvent,
+		wantMetricLabels: []metricLabels{{
+			ConversionError: true,
+		}},
+	}, {
+		name:      "mapped",
+		original:  sampleEvent,
+		converted: &jsonEvent,
+		mapper:    mapper,
+		mapped:    &mappedEvent,
+		wantMetricLabels: []metricLabels{{
+			CeType:     mappedEvent.Type(),
+			CeSource:   mappedEvent.Source(),
+			StatusCode: http.StatusOK,
+		}},
+	}, {
+		name:      "mapping fails",
+		original:  sampleEvent,
+		converted: &textEvent,
+		mapper:    mapper,
+		wantMetricLabels: []metricLabels{{
+			CeType:          textEvent.Type(),
+			CeSource:        textEvent.Source(),
+			ConversionError: true,
+		}},
 	}, {
 		name:      "successful with no reply",
 		original:  sampleEvent,
@@ -124,6 +180,16 @@ func TestAdapter(t *testing.T) {
 			CeSource:   convertedEvent.Source(),
 			StatusCode: http.StatusOK,
 		}},
+	}, {
+		name:      "filtered out",
+		original:  sampleEvent,
+		converted: &convertedEvent,
+		filter:    eventFilterFunc(func(*cev2.Event) bool { return false }),
+		wantMetricLabels: []metricLabels{{
+			CeType:   convertedEvent.Type(),
+			CeSource: convertedEvent.Source(),
+			Filtered: true,
+		}},
 	}, {
 		name:      "successful with reply",
 		original:  sampleEvent,
@@ -190,6 +256,8 @@ func TestAdapter(t *testing.T) {
 				SinkURI:       sinkSvr.URL,
 				Extensions:    map[string]string{},
 				ConverterType: converters.ConverterType(testConverterType),
+				Filter:        tc.filter,
+				Mapper:        tc.mapper,
 			}
 
 			if tc.reply != nil {
@@ -256,6 +324,9 @@ func TestAdapter(t *testing.T) {
 					return fmt.Errorf("sink received message that cannot be converted to an event: %v", err)
 				}
 				wantEvent := tc.converted
+				if tc.mapped != nil {
+					wantEvent = tc.mapped
+				}
 				if tc.reply != nil {
 					wantEvent = tc.reply
 				}
@@ -292,3 +363,266 @@ func newSampleEvent() *event.Event {
 	sampleEvent.SetTime(time.Now())
 	return &sampleEvent
 }
+
+func TestAdapterModes(t *testing.T) {
+	converted := event.New()
+	converted.SetID("converted")
+	converted.SetSource("source")
+	converted.SetType("type")
+	converted.SetDataSchema("https://example.com/schema")
+	converted.SetData(cev2.ApplicationJSON, map[string]string{"wrapped": "data"})
+
+	cases := []struct {
+		name        string
+		mode        converters.ModeType
+		wantHeaders map[string]string
+		wantBody    func(*pubsub.Message) string
+	}{{
+		name: "binary",
+		wantHeaders: map[string]string{
+			"Ce-Id":        "converted",
+			"Ce-Type":      "type",
+			"Content-Type": cev2.ApplicationJSON,
+		},
+		wantBody: func(*pubsub.Message) string {
+			return `{"wrapped":"data"}`
+		},
+	}, {
+		name: "structured",
+		mode: converters.Structured,
+		wantHeaders: map[string]string{
+			"Ce-Id":        "",
+			"Content-Type": cev2.ApplicationCloudEventsJSON,
+		},
+	}, {
+		name: "raw",
+		mode: converters.Raw,
+		wantHeaders: map[string]string{
+			"Ce-Id":         "converted",
+			"Ce-Dataschema": "",
+			"Content-Type":  "application/octet-stream",
+		},
+		wantBody: func(*pubsub.Message) string {
+			return "message data"
+		},
+	}, {
+		name: "push",
+		mode: converters.Push,
+		wantHeaders: map[string]string{
+			"Ce-Id":        "",
+			"Content-Type": cev2.ApplicationJSON,
+		},
+		wantBody: func(msg *pubsub.Message) string {
+			return fmt.Sprintf(`{"subscription":"projects/%s/subscriptions/%s","message":{"messageId":%q,"data":"bWVzc2FnZSBkYXRh","attributes":{"key":"value"},"publishTime":%q}}`,
+				testProjectID, testSub, msg.ID, msg.PublishTime.Format(time.RFC3339Nano))
+		},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtest.TestContextWithLogger(t)
+
+			requests := make(chan *http.Request, 1)
+			bodies := make(chan string, 1)
+			sinkSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests <- r
+				bodies <- string(body)
+			}))
+			defer sinkSvr.Close()
+
+			c, close := testPubsubClient(ctx, t, testProjectID)
+			defer close()
+
+			topic, err := c.CreateTopic(ctx, testTopic)
+			if err != nil {
+				t.Fatalf("failed to create topic: %v", err)
+			}
+			sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+				Topic: topic,
+			})
+			if err != nil {
+				t.Fatalf("failed to create subscription: %v", err)
+			}
+
+			var received *pubsub.Message
+			receivedCh := make(chan *pubsub.Message, 1)
+			adapter := NewAdapter(ctx,
+				clients.ProjectID(testProjectID),
+				Namespace(testNamespace),
+				Name(testName),
+				ResourceGroup(testResourceGroup),
+				sub,
+				http.DefaultClient,
+				converterFunc(func(msg *pubsub.Message) (*cev2.Event, error) {
+					receivedCh <- msg
+					e := converted.Clone()
+					return &e, nil
+				}),
+				&statsReporterRecorder{},
+				&AdapterArgs{
+					TopicID:       testTopic,
+					SinkURI:       sinkSvr.URL,
+					ConverterType: converters.ConverterType(testConverterType),
+					Mode:          tc.mode,
+				})
+
+			rctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			go adapter.Start(rctx)
+			defer adapter.Stop()
+
+			if _, err := topic.Publish(rctx, &pubsub.Message{
+				Data:       []byte("message data"),
+				Attributes: map[string]string{"key": "value"},
+			}).Get(rctx); err != nil {
+				t.Fatalf("failed to publish message: %v", err)
+			}
+
+			var req *http.Request
+			select {
+			case req = <-requests:
+				received = <-receivedCh
+			case <-rctx.Done():
+				t.Fatal("sink did not receive a request")
+			}
+			for k, want := range tc.wantHeaders {
+				if got := req.Header.Get(k); got != want {
+					t.Errorf("unexpected %s header, want: %q, got: %q", k, want, got)
+				}
+			}
+			body := <-bodies
+			if tc.wantBody != nil {
+				if diff := cmp.Diff(tc.wantBody(received), body); diff != "" {
+					t.Errorf("unexpected body (-want,+got): %v", diff)
+				}
+			}
+		})
+	}
+}
+
+type converterFunc func(*pubsub.Message) (*cev2.Event, error)
+
+func (f converterFunc) Convert(ctx context.Context, msg *pubsub.Message, converterType converters.ConverterType) (*cev2.Event, error) {
+	return f(msg)
+}
+
+func TestAdapterReply(t *testing.T) {
+	converted := event.New()
+	converted.SetID("converted")
+	converted.SetSource("source")
+	converted.SetType("type")
+
+	cases := []struct {
+		name      string
+		sinkReply bool
+		wantReply bool
+	}{{
+		name: "no reply",
+	}, {
+		name:      "reply forwarded",
+		sinkReply: true,
+		wantReply: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := logtest.TestContextWithLogger(t)
+
+			sinkCalled := make(chan struct{}, 1)
+			sinkSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.sinkReply {
+					w.Header().Set("Ce-Specversion", "1.0")
+					w.Header().Set("Ce-Id", "reply")
+					w.Header().Set("Ce-Source", "sink")
+					w.Header().Set("Ce-Type", "reply-type")
+					w.Header().Set("Content-Type", "text/plain")
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte("reply data"))
+				} else {
+					w.WriteHeader(http.StatusAccepted)
+				}
+				sinkCalled <- struct{}{}
+			}))
+			defer sinkSvr.Close()
+
+			replies := make(chan *http.Request, 1)
+			bodies := make(chan string, 1)
+			replySvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				replies <- r
+				bodies <- string(body)
+			}))
+			defer replySvr.Close()
+
+			c, close := testPubsubClient(ctx, t, testProjectID)
+			defer close()
+
+			topic, err := c.CreateTopic(ctx, testTopic)
+			if err != nil {
+				t.Fatalf("failed to create topic: %v", err)
+			}
+			sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+				Topic: topic,
+			})
+			if err != nil {
+				t.Fatalf("failed to create subscription: %v", err)
+			}
+
+			adapter := NewAdapter(ctx,
+				clients.ProjectID(testProjectID),
+				Namespace(testNamespace),
+				Name(testName),
+				ResourceGroup(testResourceGroup),
+				sub,
+				http.DefaultClient,
+				converterFunc(func(msg *pubsub.Message) (*cev2.Event, error) {
+					e := converted.Clone()
+					return &e, nil
+				}),
+				&statsReporterRecorder{},
+				&AdapterArgs{
+					TopicID:       testTopic,
+					SinkURI:       sinkSvr.URL,
+					ReplyURI:      replySvr.URL,
+					ConverterType: converters.ConverterType(testConverterType),
+				})
+
+			rctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			go adapter.Start(rctx)
+			defer adapter.Stop()
+
+			if _, err := topic.Publish(rctx, &pubsub.Message{Data: []byte("message data")}).Get(rctx); err != nil {
+				t.Fatalf("failed to publish message: %v", err)
+			}
+
+			select {
+			case <-sinkCalled:
+			case <-rctx.Done():
+				t.Fatal("sink did not receive a request")
+			}
+
+			if !tc.wantReply {
+				select {
+				case <-replies:
+					t.Error("unexpected request to the reply destination")
+				case <-time.After(100 * time.Millisecond):
+				}
+				return
+			}
+
+			select {
+			case req := <-replies:
+				if got, want := req.Header.Get("Ce-Type"), "reply-type"; got != want {
+					t.Errorf("unexpected reply type, got %q, want %q", got, want)
+				}
+				if got, want := <-bodies, "reply data"; got != want {
+					t.Errorf("unexpected reply data, got %q, want %q", got, want)
+				}
+			case <-rctx.Done():
+				t.Fatal("reply destination did not receive a request")
+			}
+		})
+	}
+}
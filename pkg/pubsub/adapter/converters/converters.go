@@ -19,6 +19,7 @@ limitations under the License.
 package converters
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
@@ -38,18 +39,22 @@ const (
 	Structured ModeType = "structured"
 	// Push mode emulates Pub/Sub push encoding.
 	Push ModeType = "push"
+	// Raw mode is binary encoding with the data of the Pub/Sub message as is.
+	Raw ModeType = "raw"
 )
 
 type ConverterType string
 
 const (
 	// The different type of Converters for the different sources.
-	CloudPubSub    ConverterType = "pubsub"
-	CloudStorage   ConverterType = "storage"
-	CloudAuditLogs ConverterType = "auditlogs"
-	CloudScheduler ConverterType = "scheduler"
-	CloudBuild     ConverterType = "build"
-	PubSubPull     ConverterType = "pubsub_pull"
+	CloudPubSub          ConverterType = "pubsub"
+	CloudStorage         ConverterType = "storage"
+	CloudAuditLogs       ConverterType = "auditlogs"
+	CloudScheduler       ConverterType = "scheduler"
+	CloudBuild           ConverterType = "build"
+	CloudFirestore       ConverterType = "firestore"
+	CloudMonitoringAlert ConverterType = "monitoring_alert"
+	PubSubPull           ConverterType = "pubsub_pull"
 )
 
 type converterFn func(context.Context, *pubsub.Message) (*cev2.Event, error)
@@ -71,12 +76,14 @@ type PubSubConverter struct {
 func NewPubSubConverter() Converter {
 	return &PubSubConverter{
 		converters: map[ConverterType]converterFn{
-			CloudPubSub:    convertCloudPubSub,
-			CloudAuditLogs: convertCloudAuditLogs,
-			CloudStorage:   convertCloudStorage,
-			CloudScheduler: convertCloudScheduler,
-			CloudBuild:     convertCloudBuild,
-			PubSubPull:     convertPubSubPull,
+			CloudPubSub:          convertCloudPubSub,
+			CloudAuditLogs:       convertCloudAuditLogs,
+			CloudStorage:         convertCloudStorage,
+			CloudScheduler:       convertCloudScheduler,
+			CloudBuild:           convertCloudBuild,
+			CloudFirestore:       convertCloudFirestore,
+			CloudMonitoringAlert: convertCloudMonitoringAlert,
+			PubSubPull:           convertPubSubPull,
 		},
 	}
 }
@@ -99,3 +106,17 @@ func (c *PubSubConverter) Convert(ctx context.Context, msg *pubsub.Message, conv
 	return binding.ToEvent(ctx, cepubsub.NewMessage(msg))
 
 }
+
+// ToRawData sets the data of the Pub/Sub message as the data of the event,
+// unwrapping it from the envelope some converters set. The events carrying the
+// data of the message as is are left untouched.
+func ToRawData(event *cev2.Event, msg *pubsub.Message) error {
+	if bytes.Equal(event.Data(), msg.Data) {
+		return nil
+	}
+	// The schema is the one of the envelope.
+	event.SetDataSchema("")
+	// We do not know the content type and we do not want to inspect the payload,
+	// thus we set this generic one.
+	return event.SetData("application/octet-stream", msg.Data)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+// convertCloudFirestore converts the messages published by the change feeds of
+// Firestore documents. The feeds publish the changes as CloudEvents in the
+// Pub/Sub binary content mode.
+func convertCloudFirestore(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	event, err := binding.ToEvent(ctx, cepubsub.NewMessage(msg))
+	if err != nil {
+		return nil, err
+	}
+	if !schemasv1.IsCloudFirestoreEventType(event.Type()) {
+		return nil, fmt.Errorf("received event has unexpected type %q", event.Type())
+	}
+	if event.DataSchema() == "" {
+		event.SetDataSchema(schemasv1.CloudFirestoreEventDataSchema)
+	}
+	return event, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/google/go-cmp/cmp"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+)
+
+func TestConvertCloudFirestore(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		message     *pubsub.Message
+		wantEventFn func() *cev2.Event
+		wantErr     string
+	}{{
+		name: "valid change",
+		message: &pubsub.Message{
+			Data: []byte(`{"value":{"name":"users/alice"}}`),
+			Attributes: map[string]string{
+				"ce-specversion": "1.0",
+				"ce-id":          "id",
+				"ce-type":        schemasv1.CloudFirestoreDocumentWrittenEventType,
+				"ce-source":      schemasv1.CloudFirestoreEventSource("my-project", "(default)"),
+				"ce-subject":     schemasv1.CloudFirestoreEventSubject("users/alice"),
+				"Content-Type":   "application/json",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := firestoreCloudEvent(schemasv1.CloudFirestoreDocumentWrittenEventType)
+			e.SetDataSchema(schemasv1.CloudFirestoreEventDataSchema)
+			return e
+		},
+	}, {
+		name: "data schema set",
+		message: &pubsub.Message{
+			Data: []byte(`{"value":{"name":"users/alice"}}`),
+			Attributes: map[string]string{
+				"ce-specversion": "1.0",
+				"ce-id":          "id",
+				"ce-type":        schemasv1.CloudFirestoreDocumentDeletedEventType,
+				"ce-source":      schemasv1.CloudFirestoreEventSource("my-project", "(default)"),
+				"ce-subject":     schemasv1.CloudFirestoreEventSubject("users/alice"),
+				"ce-dataschema":  "https://example.com/schema",
+				"Content-Type":   "application/json",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := firestoreCloudEvent(schemasv1.CloudFirestoreDocumentDeletedEventType)
+			e.SetDataSchema("https://example.com/schema")
+			return e
+		},
+	}, {
+		name: "not a cloudevent",
+		message: &pubsub.Message{
+			Data: []byte(`{"value":{"name":"users/alice"}}`),
+		},
+		wantErr: "unknown Message encoding",
+	}, {
+		name: "unexpected type",
+		message: &pubsub.Message{
+			Data: []byte(`{}`),
+			Attributes: map[string]string{
+				"ce-specversion": "1.0",
+				"ce-id":          "id",
+				"ce-type":        "com.example.type",
+				"ce-source":      "//example.com",
+			},
+		},
+		wantErr: `received event has unexpected type "com.example.type"`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			gotEvent, err := NewPubSubConverter().Convert(context.Background(), test.message, CloudFirestore)
+
+			if test.wantErr != "" || err != nil {
+				var gotErr string
+				if err != nil {
+					gotErr = err.Error()
+				}
+				if gotErr == "" || !strings.Contains(gotErr, test.wantErr) {
+					diff := cmp.Diff(test.wantErr, gotErr)
+					t.Errorf("unexpected error (-want, +got) = %v", diff)
+				}
+				return
+			}
+
+			if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+				t.Errorf("converters.convertCloudFirestore got unexpected cev2.Event (-want +got) %s", diff)
+			}
+		})
+	}
+}
+
+func firestoreCloudEvent(eventType string) *cev2.Event {
+	e := cev2.NewEvent(cev2.VersionV1)
+	e.SetID("id")
+	e.SetType(eventType)
+	e.SetSource(schemasv1.CloudFirestoreEventSource("my-project", "(default)"))
+	e.SetSubject(schemasv1.CloudFirestoreEventSubject("users/alice"))
+	e.SetData(cev2.ApplicationJSON, []byte(`{"value":{"name":"users/alice"}}`))
+	e.DataBase64 = false
+	return &e
+}
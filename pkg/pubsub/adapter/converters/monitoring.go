@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+const (
+	incidentStateOpen   = "open"
+	incidentStateClosed = "closed"
+)
+
+// conditionNameRegex matches the resource names of the conditions of alert
+// policies and captures the ID of the policy.
+var conditionNameRegex = regexp.MustCompile(`^projects/[^/]+/alertPolicies/([^/]+)/conditions/[^/]+$`)
+
+// alertNotification is the part of the JSON payload of the Pub/Sub
+// notifications of Cloud Monitoring that is needed to convert them, see
+// https://cloud.google.com/monitoring/support/notification-options#pubsub
+type alertNotification struct {
+	Incident struct {
+		IncidentID string `json:"incident_id"`
+		State      string `json:"state"`
+		Condition  struct {
+			Name string `json:"name"`
+		} `json:"condition"`
+	} `json:"incident"`
+}
+
+// convertCloudMonitoringAlert converts the incidents notified by Cloud
+// Monitoring to a Pub/Sub notification channel. The type of the event is the
+// state of the incident, its subject is the alert policy, and its data is the
+// notification as is.
+func convertCloudMonitoringAlert(ctx context.Context, msg *pubsub.Message) (*cev2.Event, error) {
+	var notification alertNotification
+	if err := json.Unmarshal(msg.Data, &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode the incident: %w", err)
+	}
+	incident := notification.Incident
+
+	event := cev2.NewEvent(cev2.VersionV1)
+	event.SetID(msg.ID)
+	event.SetTime(msg.PublishTime)
+	switch incident.State {
+	case incidentStateOpen:
+		event.SetType(schemasv1.CloudMonitoringAlertIncidentOpenedEventType)
+	case incidentStateClosed:
+		event.SetType(schemasv1.CloudMonitoringAlertIncidentClosedEventType)
+	default:
+		return nil, fmt.Errorf("received incident has unexpected state %q", incident.State)
+	}
+
+	project, err := GetProjectKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	event.SetSource(schemasv1.CloudMonitoringAlertEventSource(project))
+
+	match := conditionNameRegex.FindStringSubmatch(incident.Condition.Name)
+	if match == nil {
+		return nil, errors.New("received incident did not have the condition of an alert policy")
+	}
+	event.SetSubject(schemasv1.CloudMonitoringAlertEventSubject(match[1]))
+	if incident.IncidentID != "" {
+		event.SetExtension(schemasv1.CloudMonitoringAlertIncidentIDExtension, incident.IncidentID)
+	}
+
+	if err := event.SetData(cev2.ApplicationJSON, msg.Data); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
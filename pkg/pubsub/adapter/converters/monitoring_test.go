@@ -0,0 +1,99 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	. "github.com/google/knative-gcp/pkg/pubsub/adapter/context"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+var alertPublishTime = time.Date(2021, time.March, 10, 23, 0, 0, 0, time.UTC)
+
+func TestConvertCloudMonitoringAlert(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   *pubsub.Message
+		wantEvent *cev2.Event
+		wantErr   bool
+	}{{
+		name:      "open incident",
+		message:   alertMessage(`{"incident":{"incident_id":"0.abc","state":"open","policy_name":"High latency","condition":{"name":"projects/my-project/alertPolicies/123/conditions/456"}},"version":"1.2"}`),
+		wantEvent: alertCloudEvent(schemasv1.CloudMonitoringAlertIncidentOpenedEventType, `{"incident":{"incident_id":"0.abc","state":"open","policy_name":"High latency","condition":{"name":"projects/my-project/alertPolicies/123/conditions/456"}},"version":"1.2"}`),
+	}, {
+		name:      "closed incident",
+		message:   alertMessage(`{"incident":{"incident_id":"0.abc","state":"closed","condition":{"name":"projects/my-project/alertPolicies/123/conditions/456"}},"version":"1.2"}`),
+		wantEvent: alertCloudEvent(schemasv1.CloudMonitoringAlertIncidentClosedEventType, `{"incident":{"incident_id":"0.abc","state":"closed","condition":{"name":"projects/my-project/alertPolicies/123/conditions/456"}},"version":"1.2"}`),
+	}, {
+		name:    "unknown state",
+		message: alertMessage(`{"incident":{"incident_id":"0.abc","state":"acknowledged","condition":{"name":"projects/my-project/alertPolicies/123/conditions/456"}}}`),
+		wantErr: true,
+	}, {
+		name:    "no condition",
+		message: alertMessage(`{"incident":{"incident_id":"0.abc","state":"open"}}`),
+		wantErr: true,
+	}, {
+		name:    "not JSON",
+		message: alertMessage(`incident`),
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithProjectKey(context.Background(), "my-project")
+			gotEvent, err := NewPubSubConverter().Convert(ctx, test.message, CloudMonitoringAlert)
+			if err != nil {
+				if !test.wantErr {
+					t.Fatalf("converters.convertCloudMonitoringAlert got error %v want error=%v", err, test.wantErr)
+				}
+				return
+			}
+			if test.wantErr {
+				t.Fatalf("converters.convertCloudMonitoringAlert got event %v want error", gotEvent)
+			}
+			if diff := cmp.Diff(test.wantEvent, gotEvent); diff != "" {
+				t.Errorf("converters.convertCloudMonitoringAlert got unexpected cev2.Event (-want +got) %s", diff)
+			}
+		})
+	}
+}
+
+func alertMessage(data string) *pubsub.Message {
+	return &pubsub.Message{
+		ID:          "id",
+		PublishTime: alertPublishTime,
+		Data:        []byte(data),
+	}
+}
+
+func alertCloudEvent(eventType, data string) *cev2.Event {
+	e := cev2.NewEvent(cev2.VersionV1)
+	e.SetID("id")
+	e.SetTime(alertPublishTime)
+	e.SetType(eventType)
+	e.SetSource(schemasv1.CloudMonitoringAlertEventSource("my-project"))
+	e.SetSubject(schemasv1.CloudMonitoringAlertEventSubject("123"))
+	e.SetExtension(schemasv1.CloudMonitoringAlertIncidentIDExtension, "0.abc")
+	e.SetData(cev2.ApplicationJSON, []byte(data))
+	return &e
+}
@@ -47,7 +47,16 @@ func convertCloudPubSub(ctx context.Context, msg *pubsub.Message) (*cev2.Event,
 		return nil, err
 	}
 
-	pushMessage := &schemasv1.PushMessage{
+	if err := event.SetData(cev2.ApplicationJSON, NewPushMessage(subscription, msg)); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// NewPushMessage returns the body of the request Cloud Pub/Sub push
+// subscriptions send for the message.
+func NewPushMessage(subscription string, msg *pubsub.Message) *schemasv1.PushMessage {
+	return &schemasv1.PushMessage{
 		Subscription: subscription,
 		Message: &schemasv1.PubSubMessage{
 			ID:          msg.ID,
@@ -56,9 +65,4 @@ func convertCloudPubSub(ctx context.Context, msg *pubsub.Message) (*cev2.Event,
 			Data:        msg.Data,
 		},
 	}
-
-	if err := event.SetData(cev2.ApplicationJSON, pushMessage); err != nil {
-		return nil, err
-	}
-	return &event, nil
 }
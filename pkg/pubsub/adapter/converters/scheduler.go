@@ -40,6 +40,15 @@ func convertCloudScheduler(ctx context.Context, msg *pubsub.Message) (*cev2.Even
 	}
 	event.SetSource(schemasv1.CloudSchedulerEventSource(jobName))
 
+	// The custom attributes of the Job are propagated as extensions. The
+	// webhook only admits valid extension names, other attributes are dropped.
+	for k, v := range msg.Attributes {
+		if k == v1beta1.CloudSchedulerSourceJobName {
+			continue
+		}
+		_ = event.Context.SetExtension(k, v)
+	}
+
 	if err := event.SetData(cev2.ApplicationJSON, &schemasv1.SchedulerJobData{CustomData: msg.Data}); err != nil {
 		return nil, err
 	}
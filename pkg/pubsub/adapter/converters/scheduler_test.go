@@ -50,7 +50,28 @@ func TestConvertCloudSchedulerSource(t *testing.T) {
 			},
 		},
 		wantEventFn: func() *cev2.Event {
-			return schedulerCloudEvent("//cloudscheduler.googleapis.com/projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test")
+			e := schedulerCloudEvent("//cloudscheduler.googleapis.com/projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test")
+			e.SetExtension("schedulername", "scheduler-test")
+			e.SetExtension("attribute1", "value1")
+			e.SetExtension("attribute2", "value2")
+			return e
+		},
+	}, {
+		name: "custom attributes",
+		message: &pubsub.Message{
+			ID:   "id",
+			Data: []byte("test data"),
+			Attributes: map[string]string{
+				"jobName": "projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test",
+				"team":    "payments",
+				"env":     "prod",
+			},
+		},
+		wantEventFn: func() *cev2.Event {
+			e := schedulerCloudEvent("//cloudscheduler.googleapis.com/projects/knative-gcp-test/locations/us-east4/jobs/cre-scheduler-test")
+			e.SetExtension("team", "payments")
+			e.SetExtension("env", "prod")
+			return e
 		},
 	}, {
 		name: "missing jobName attribute",
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	eventsv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+// EventFilter decides which of the converted events the adapter delivers.
+type EventFilter interface {
+	// Matches returns true if the event is delivered.
+	Matches(event *cev2.Event) bool
+}
+
+// NewEventFilter creates the filter of the events of the converter type from
+// its JSON encoding. It returns nil if filter is empty.
+func NewEventFilter(converterType converters.ConverterType, filter string) (EventFilter, error) {
+	if filter == "" {
+		return nil, nil
+	}
+	switch converterType {
+	case converters.CloudStorage:
+		var f storageFilter
+		if err := json.Unmarshal([]byte(filter), &f.CloudStorageObjectFilter); err != nil {
+			return nil, fmt.Errorf("failed to decode the storage filter: %w", err)
+		}
+		return &f, nil
+	default:
+		return nil, fmt.Errorf("the events of the adapter type %q can't be filtered", converterType)
+	}
+}
+
+// storageFilter matches the events of the objects matching its
+// CloudStorageObjectFilter.
+type storageFilter struct {
+	eventsv1.CloudStorageObjectFilter
+}
+
+// storageObject holds the fields of the GCS object in the data of an event
+// which are matched by a storageFilter.
+type storageObject struct {
+	Name        string            `json:"name"`
+	ContentType string            `json:"contentType"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+func (f *storageFilter) Matches(event *cev2.Event) bool {
+	var obj storageObject
+	if err := event.DataAs(&obj); err != nil {
+		return false
+	}
+	if len(f.NamePrefixes) > 0 && !matchesAny(f.NamePrefixes, func(prefix string) bool {
+		return strings.HasPrefix(obj.Name, prefix)
+	}) {
+		return false
+	}
+	if len(f.NameSuffixes) > 0 && !matchesAny(f.NameSuffixes, func(suffix string) bool {
+		return strings.HasSuffix(obj.Name, suffix)
+	}) {
+		return false
+	}
+	if len(f.NamePatterns) > 0 && !matchesAny(f.NamePatterns, func(pattern string) bool {
+		matched, err := path.Match(pattern, obj.Name)
+		return err == nil && matched
+	}) {
+		return false
+	}
+	if len(f.ContentTypes) > 0 && !matchesAny(f.ContentTypes, func(contentType string) bool {
+		return matchesMediaType(contentType, obj.ContentType)
+	}) {
+		return false
+	}
+	for k, v := range f.Metadata {
+		if got, ok := obj.Metadata[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(values []string, match func(string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMediaType returns true if the content type is the media type, or one
+// of its subtypes if the media type is of the form "type/*". The parameters of
+// both are ignored.
+func matchesMediaType(mediaType, contentType string) bool {
+	want, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	got, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if want == "*/*" {
+		return true
+	}
+	if strings.HasSuffix(want, "/*") {
+		return strings.HasPrefix(got, strings.TrimSuffix(want, "*"))
+	}
+	return got == want
+}
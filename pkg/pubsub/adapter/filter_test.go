@@ -0,0 +1,139 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+func TestNewEventFilter(t *testing.T) {
+	if f, err := NewEventFilter(converters.CloudStorage, ""); f != nil || err != nil {
+		t.Errorf("NewEventFilter with no filter = %v, %v, want nil, nil", f, err)
+	}
+	if _, err := NewEventFilter(converters.CloudStorage, "{"); err == nil {
+		t.Error("NewEventFilter with an invalid filter succeeded, want error")
+	}
+	if _, err := NewEventFilter(converters.CloudPubSub, `{"nameSuffixes":[".png"]}`); err == nil {
+		t.Error("NewEventFilter of a pubsub adapter succeeded, want error")
+	}
+}
+
+func TestStorageFilter(t *testing.T) {
+	object := `{
+		"name": "images/2021/thumbnail-cat.png",
+		"contentType": "image/png; charset=binary",
+		"metadata": {"team": "a", "env": "prod"}
+	}`
+	cases := []struct {
+		name   string
+		filter string
+		data   string
+		want   bool
+	}{{
+		name:   "empty filter",
+		filter: `{}`,
+		data:   object,
+		want:   true,
+	}, {
+		name:   "one of the prefixes",
+		filter: `{"namePrefixes": ["videos/", "images/"]}`,
+		data:   object,
+		want:   true,
+	}, {
+		name:   "none of the prefixes",
+		filter: `{"namePrefixes": ["videos/"]}`,
+		data:   object,
+		want:   false,
+	}, {
+		name:   "suffix",
+		filter: `{"nameSuffixes": [".png"]}`,
+		data:   object,
+		want:   true,
+	}, {
+		name:   "other suffix",
+		filter: `{"nameSuffixes": [".jpg"]}`,
+		data:   object,
+		want:   false,
+	}, {
+		name:   "pattern",
+		filter: `{"namePatterns": ["images/*/thumbnail-*"]}`,
+		data:   object,
+		want:   true,
+	}, {
+		name:   "pattern star doesn't match slash",
+		filter: `{"namePatterns": ["images/thumbnail-*"]}`,
+		data:   object,
+		want:   false,
+	}, {
+		name:   "content type",
+		filter: `{"contentTypes": ["image/png"]}`,
+		data:   object,
+		want:   true,
+	}, {
+		name:   "content type wildcard",
+		filter: `{"contentTypes": ["image/*"]}`,
+		data:   object,
+		want:   true,
+	}, {
+		name:   "other content type",
+		filter: `{"contentTypes": ["video/*", "image/jpeg"]}`,
+		data:   object,
+		want:   false,
+	}, {
+		name:   "metadata",
+		filter: `{"metadata": {"team": "a"}}`,
+		data:   object,
+		want:   true,
+	}, {
+		name:   "other metadata",
+		filter: `{"metadata": {"team": "a", "env": "dev"}}`,
+		data:   object,
+		want:   false,
+	}, {
+		name:   "all fields",
+		filter: `{"namePrefixes": ["images/"], "nameSuffixes": [".png"], "contentTypes": ["image/*"], "metadata": {"env": "prod"}}`,
+		data:   object,
+		want:   true,
+	}, {
+		name:   "one field not matching",
+		filter: `{"namePrefixes": ["images/"], "nameSuffixes": [".jpg"], "contentTypes": ["image/*"]}`,
+		data:   object,
+		want:   false,
+	}, {
+		name:   "invalid data",
+		filter: `{"nameSuffixes": [".png"]}`,
+		data:   `not json`,
+		want:   false,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewEventFilter(converters.CloudStorage, tc.filter)
+			if err != nil {
+				t.Fatalf("NewEventFilter failed: %v", err)
+			}
+			event := cev2.NewEvent(cev2.VersionV1)
+			event.SetData(cev2.ApplicationJSON, []byte(tc.data))
+			if got := f.Matches(&event); got != tc.want {
+				t.Errorf("Matches = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
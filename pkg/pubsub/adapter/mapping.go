@@ -0,0 +1,132 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/client-go/util/jsonpath"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+// EventMapper computes attributes of the converted events from their data.
+type EventMapper struct {
+	typ        *jsonpath.JSONPath
+	subject    *jsonpath.JSONPath
+	extensions map[string]*jsonpath.JSONPath
+}
+
+// NewEventMapper creates the mapper of the events from the JSON encoding of
+// an EventMapping. It returns nil if mapping is empty.
+func NewEventMapper(mapping string) (*EventMapper, error) {
+	if mapping == "" {
+		return nil, nil
+	}
+	var m gcpduckv1.EventMapping
+	if err := json.Unmarshal([]byte(mapping), &m); err != nil {
+		return nil, fmt.Errorf("failed to decode the event mapping: %w", err)
+	}
+	mapper := &EventMapper{
+		extensions: make(map[string]*jsonpath.JSONPath, len(m.Extensions)),
+	}
+	var err error
+	if mapper.typ, err = parseTemplate("type", m.Type); err != nil {
+		return nil, err
+	}
+	if mapper.subject, err = parseTemplate("subject", m.Subject); err != nil {
+		return nil, err
+	}
+	for name, template := range m.Extensions {
+		if mapper.extensions[name], err = parseTemplate(name, template); err != nil {
+			return nil, err
+		}
+	}
+	return mapper, nil
+}
+
+// parseTemplate parses the JSONPath template of the attribute. It returns nil
+// if the template is empty.
+func parseTemplate(attribute, template string) (*jsonpath.JSONPath, error) {
+	if template == "" {
+		return nil, nil
+	}
+	j := jsonpath.New(attribute).AllowMissingKeys(true)
+	if err := j.Parse(template); err != nil {
+		return nil, fmt.Errorf("failed to parse the template of %s: %w", attribute, err)
+	}
+	return j, nil
+}
+
+// Map sets the attributes of the event computed from its data. The attributes
+// which evaluate to the empty string are left as is. The event is not modified
+// if any of the templates fails to evaluate.
+func (m *EventMapper) Map(event *cev2.Event) error {
+	var data interface{}
+	if len(event.Data()) > 0 {
+		d := json.NewDecoder(bytes.NewReader(event.Data()))
+		// Keep the numbers as they are written rather than as floats.
+		d.UseNumber()
+		if err := d.Decode(&data); err != nil {
+			return fmt.Errorf("failed to decode the data of the event: %w", err)
+		}
+	}
+
+	typ, err := execute("type", m.typ, data)
+	if err != nil {
+		return err
+	}
+	subject, err := execute("subject", m.subject, data)
+	if err != nil {
+		return err
+	}
+	extensions := make(map[string]string, len(m.extensions))
+	for name, j := range m.extensions {
+		if extensions[name], err = execute(name, j, data); err != nil {
+			return err
+		}
+	}
+
+	if typ != "" {
+		event.SetType(typ)
+	}
+	if subject != "" {
+		event.SetSubject(subject)
+	}
+	for name, value := range extensions {
+		if value != "" {
+			event.SetExtension(name, value)
+		}
+	}
+	return nil
+}
+
+// execute evaluates the template of the attribute on data. It returns the
+// empty string if the template is nil.
+func execute(attribute string, j *jsonpath.JSONPath, data interface{}) (string, error) {
+	if j == nil {
+		return "", nil
+	}
+	var b bytes.Buffer
+	if err := j.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to evaluate the template of %s: %w", attribute, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewEventMapper(t *testing.T) {
+	testCases := map[string]struct {
+		mapping string
+		wantNil bool
+		wantErr bool
+	}{
+		"empty": {
+			wantNil: true,
+		},
+		"valid": {
+			mapping: `{"type":"com.example.{.kind}","subject":"{.name}","extensions":{"bucket":"{.bucket}"}}`,
+		},
+		"bad encoding": {
+			mapping: `{"type":`,
+			wantErr: true,
+		},
+		"bad template": {
+			mapping: `{"extensions":{"bucket":"{.bucket"}}`,
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewEventMapper(tc.mapping)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotNil := got == nil; gotNil != (tc.wantNil || tc.wantErr) {
+				t.Errorf("unexpected mapper: %v", got)
+			}
+		})
+	}
+}
+
+func TestEventMapper(t *testing.T) {
+	const mapping = `{
+		"type": "com.example.{.kind}",
+		"subject": "gs://{.bucket}/{.name}",
+		"extensions": {"size": "{.size}", "method": "{.protoPayload.methodName}"}
+	}`
+	mapper, err := NewEventMapper(mapping)
+	if err != nil {
+		t.Fatalf("failed to create the event mapper: %v", err)
+	}
+
+	testCases := map[string]struct {
+		contentType string
+		data        interface{}
+		want        func(*cev2.Event)
+		wantErr     bool
+	}{
+		"mapped": {
+			contentType: cev2.ApplicationJSON,
+			data: map[string]interface{}{
+				"kind":   "storage#object",
+				"bucket": "my-bucket",
+				"name":   "my-object",
+				"size":   1234567,
+				"protoPayload": map[string]string{
+					"methodName": "storage.objects.create",
+				},
+			},
+			want: func(e *cev2.Event) {
+				e.SetType("com.example.storage#object")
+				e.SetSubject("gs://my-bucket/my-object")
+				e.SetExtension("size", "1234567")
+				e.SetExtension("method", "storage.objects.create")
+			},
+		},
+		"missing fields": {
+			contentType: cev2.ApplicationJSON,
+			data:        map[string]string{"kind": "storage#object"},
+			want: func(e *cev2.Event) {
+				e.SetType("com.example.storage#object")
+				e.SetSubject("gs:///")
+			},
+		},
+		"no data": {
+			want: func(e *cev2.Event) {
+				e.SetType("com.example.")
+				e.SetSubject("gs:///")
+			},
+		},
+		"not json": {
+			contentType: cev2.TextPlain,
+			data:        "not json",
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			event := cev2.NewEvent()
+			event.SetID("id")
+			event.SetSource("source")
+			event.SetType("type")
+			event.SetSubject("subject")
+			if tc.data != nil {
+				if err := event.SetData(tc.contentType, tc.data); err != nil {
+					t.Fatalf("failed to set the data of the event: %v", err)
+				}
+			}
+			want := event.Clone()
+			if tc.want != nil {
+				tc.want(&want)
+			}
+
+			err := mapper.Map(&event)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(want, event); diff != "" {
+				t.Errorf("unexpected event (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
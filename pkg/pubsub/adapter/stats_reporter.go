@@ -37,6 +37,22 @@ var (
 		stats.UnitDimensionless,
 	)
 
+	// filteredEventCountM is a counter which records the number of events
+	// filtered out.
+	filteredEventCountM = stats.Int64(
+		"filtered_event_count",
+		"Number of events filtered out",
+		stats.UnitDimensionless,
+	)
+
+	// conversionErrorCountM is a counter which records the number of
+	// messages which failed to be converted or mapped to events.
+	conversionErrorCountM = stats.Int64(
+		"conversion_error_count",
+		"Number of messages which failed to be converted to events",
+		stats.UnitDimensionless,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -60,6 +76,13 @@ type ReportArgs struct {
 type StatsReporter interface {
 	// ReportEventCount captures the event count. It records one per call.
 	ReportEventCount(args *ReportArgs, responseCode int) error
+	// ReportFilteredEventCount captures the count of the events filtered
+	// out. It records one per call.
+	ReportFilteredEventCount(args *ReportArgs) error
+	// ReportConversionErrorCount captures the count of the messages which
+	// failed to be converted or mapped to events. The args are empty if the
+	// message could not be converted. It records one per call.
+	ReportConversionErrorCount(args *ReportArgs) error
 }
 
 var _ StatsReporter = (*reporter)(nil)
@@ -94,6 +117,36 @@ func (r *reporter) ReportEventCount(args *ReportArgs, responseCode int) error {
 	return nil
 }
 
+func (r *reporter) ReportFilteredEventCount(args *ReportArgs) error {
+	ctx, err := r.generateEventTag(args)
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, filteredEventCountM.M(1))
+	return nil
+}
+
+func (r *reporter) ReportConversionErrorCount(args *ReportArgs) error {
+	ctx, err := r.generateEventTag(args)
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, conversionErrorCountM.M(1))
+	return nil
+}
+
+// generateEventTag generates the tags of the metrics of events which were not
+// sent, and so have no response code.
+func (r *reporter) generateEventTag(args *ReportArgs) (context.Context, error) {
+	return tag.New(
+		emptyContext,
+		tag.Insert(namespaceKey, r.namespace),
+		tag.Insert(eventSourceKey, args.EventSource),
+		tag.Insert(eventTypeKey, args.EventType),
+		tag.Insert(nameKey, r.name),
+		tag.Insert(resourceGroupKey, r.resourceGroup))
+}
+
 func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
 	return tag.New(
 		emptyContext,
@@ -116,6 +169,13 @@ func (r *reporter) register() error {
 		responseCodeKey,
 		responseCodeClassKey}
 
+	eventTagKeys := []tag.Key{
+		namespaceKey,
+		eventSourceKey,
+		eventTypeKey,
+		nameKey,
+		resourceGroupKey}
+
 	// Create view to see our measurements.
 	return metrics.RegisterResourceView(
 		&view.View{
@@ -124,5 +184,17 @@ func (r *reporter) register() error {
 			Aggregation: view.Count(),
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Description: filteredEventCountM.Description(),
+			Measure:     filteredEventCountM,
+			Aggregation: view.Count(),
+			TagKeys:     eventTagKeys,
+		},
+		&view.View{
+			Description: conversionErrorCountM.Description(),
+			Measure:     conversionErrorCountM,
+			Aggregation: view.Count(),
+			TagKeys:     eventTagKeys,
+		},
 	)
 }
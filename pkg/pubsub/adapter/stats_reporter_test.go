@@ -55,6 +55,30 @@ func TestStatsReporter(t *testing.T) {
 		return r.ReportEventCount(args, http.StatusAccepted)
 	})
 	metricstest.CheckCountData(t, "event_count", wantTags, 2)
+
+	// test ReportFilteredEventCount
+	expectSuccess(t, func() error {
+		return r.ReportFilteredEventCount(args)
+	})
+	metricstest.CheckCountData(t, "filtered_event_count", map[string]string{
+		metricskey.LabelNamespaceName: "testns",
+		metricskey.LabelEventType:     "dev.knative.event",
+		metricskey.LabelEventSource:   "unit-test",
+		metricskey.LabelName:          "testobject",
+		metricskey.LabelResourceGroup: "testresourcegroup",
+	}, 1)
+
+	// test ReportConversionErrorCount
+	expectSuccess(t, func() error {
+		return r.ReportConversionErrorCount(args)
+	})
+	metricstest.CheckCountData(t, "conversion_error_count", map[string]string{
+		metricskey.LabelNamespaceName: "testns",
+		metricskey.LabelEventType:     "dev.knative.event",
+		metricskey.LabelEventSource:   "unit-test",
+		metricskey.LabelName:          "testobject",
+		metricskey.LabelResourceGroup: "testresourcegroup",
+	}, 1)
 }
 
 func expectSuccess(t *testing.T, f func() error) {
@@ -21,30 +21,36 @@ package broker
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
 	pkgreconciler "knative.dev/pkg/reconciler"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 	brokerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/broker"
 	inteventslisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1alpha1"
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
 	"github.com/google/knative-gcp/pkg/utils"
 )
 
 const (
 	// Name of the corev1.Events emitted from the Broker reconciliation process.
-	brokerReconciled  = "BrokerReconciled"
-	brokerFinalized   = "BrokerFinalized"
-	brokerCellCreated = "BrokerCellCreated"
+	brokerReconciled   = "BrokerReconciled"
+	brokerFinalized    = "BrokerFinalized"
+	brokerCellCreated  = "BrokerCellCreated"
+	brokerCellAssigned = "BrokerCellAssigned"
+	brokerCellDrained  = "BrokerCellDrained"
 )
 
 type Reconciler struct {
@@ -59,6 +65,20 @@ type Reconciler struct {
 	pubsubClient *pubsub.Client
 
 	dataresidencyStore *dataresidency.Store
+
+	// brokerPlacementStore holds the policy placing new Brokers on
+	// brokercells. Brokers are placed on the default brokercell if nil.
+	brokerPlacementStore *brokerplacement.Store
+
+	clock clock.Clock
+
+	// backlogReporter reports the backlog of the Broker's subscription.
+	backlogReporter *backlog.Reporter
+
+	// enqueueAfter enqueues a Broker after a delay. It is used to wait for
+	// the moves of Brokers between brokercells and to poll the backlog of the
+	// Broker's subscription.
+	enqueueAfter func(obj interface{}, after time.Duration)
 	// clusterRegion is the region where GKE is running
 	clusterRegion string
 }
@@ -97,6 +117,10 @@ func (r *Reconciler) reconcileBroker(ctx context.Context, b *brokerv1beta1.Broke
 	b.Status.InitializeConditions()
 	b.Status.ObservedGeneration = b.Generation
 
+	if err := r.reconcileBrokerCellPlacement(ctx, b); err != nil {
+		return fmt.Errorf("brokercell placement failed: %v", err)
+	}
+
 	if err := r.ensureBrokerCellExists(ctx, b); err != nil {
 		return fmt.Errorf("brokercell reconcile failed: %v", err)
 	}
@@ -164,8 +188,9 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 	// Check if PullSub exists, and if not, create it.
 	subID := resources.GenerateDecouplingSubscriptionName(b)
 	subConfig := pubsub.SubscriptionConfig{
-		Topic:  topic,
-		Labels: labels,
+		Topic:                 topic,
+		Labels:                labels,
+		EnableMessageOrdering: b.OrderingKeyExtension() != "",
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
@@ -178,6 +203,10 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 	//TODO uncomment when eventing webhook allows this
 	//b.Status.SubscriptionID = sub.ID()
 
+	if r.backlogReporter.Report(ctx, b.Annotations, &b.Status, projectID, subID) && r.enqueueAfter != nil {
+		r.enqueueAfter(b, backlog.ResyncPeriod)
+	}
+
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
@@ -27,6 +28,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 
@@ -40,13 +42,17 @@ import (
 	. "knative.dev/pkg/reconciler/testing"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1alpha1/resource"
 	brokerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/broker"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	gmonitoringtesting "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 )
 
 const (
@@ -74,11 +80,22 @@ var (
 	brokerFinalizedEvent        = Eventf(corev1.EventTypeNormal, "BrokerFinalized", `Broker finalized: "testnamespace/test-broker"`)
 	ingressServiceName          = brokercellresources.Name(resources.DefaultBrokerCellName, brokercellresources.IngressName)
 
+	ingressTemplate = fmt.Sprintf("http://%s.%s.svc.%s/{namespace}/{name}", ingressServiceName, systemNS, network.GetClusterDomainName())
+
 	brokerAddress = &apis.URL{
 		Scheme: "http",
 		Host:   fmt.Sprintf("%s.%s.svc.%s", ingressServiceName, systemNS, network.GetClusterDomainName()),
 		Path:   ingress.BrokerPath(testNS, brokerName),
 	}
+	otherBrokerCellName  = "other-brokercell"
+	otherIngressTemplate = "http://other-brokercell.example.com/{namespace}/{name}"
+	otherBrokerAddress   = &apis.URL{
+		Scheme: "http",
+		Host:   "other-brokercell.example.com",
+		Path:   ingress.BrokerPath(testNS, brokerName),
+	}
+	testNow = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
 	brokerDeliverySpec = &eventingduckv1beta1.DeliverySpec{
 		BackoffDelay:  &backoffDelay,
 		BackoffPolicy: &backoffPolicy,
@@ -162,6 +179,47 @@ func TestAllCases(t *testing.T) {
 				WithBrokerSetDefaults),
 			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
 				WithBrokerCellReady,
+				WithIngressTemplate(ingressTemplate),
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Broker reports the backlog of its subscription",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerBacklogReporting,
+				WithBrokerSetDefaults),
+			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithIngressTemplate(ingressTemplate),
 				WithBrokerCellSetDefaults),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
@@ -169,7 +227,60 @@ func TestAllCases(t *testing.T) {
 				WithBrokerClass(brokerv1beta1.BrokerClass),
 				WithBrokerUID(testUID),
 				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerBacklogReporting,
 				WithBrokerReadyURI(brokerAddress),
+				WithBrokerBacklog(7, 20*time.Second),
+				WithBrokerBacklogHealthy,
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+			"monitoring": gmonitoringtesting.TestClientData{
+				Backlogs: map[string]gmonitoring.Backlog{
+					"cre-bkr_testnamespace_test-broker_abc123": {
+						UndeliveredMessages:     7,
+						OldestUnackedMessageAge: 20 * time.Second,
+					},
+				},
+			},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Broker backlog unavailable",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerBacklogReporting,
+				WithBrokerSetDefaults),
+			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithIngressTemplate(ingressTemplate),
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerBacklogReporting,
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerBacklogUnknown("BacklogUnavailable", "No recent backlog metrics of the Pub/Sub subscription cre-bkr_testnamespace_test-broker_abc123"),
 				WithBrokerSetDefaults,
 			),
 		}},
@@ -323,6 +434,7 @@ func TestAllCases(t *testing.T) {
 				WithBrokerSetDefaults),
 			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
 				WithBrokerCellReady,
+				WithIngressTemplate(ingressTemplate),
 				WithBrokerCellSetDefaults),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
@@ -368,6 +480,7 @@ func TestAllCases(t *testing.T) {
 				WithBrokerSetDefaults),
 			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
 				WithBrokerCellReady,
+				WithIngressTemplate(ingressTemplate),
 				WithBrokerCellSetDefaults),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
@@ -409,6 +522,7 @@ func TestAllCases(t *testing.T) {
 				WithBrokerSetDefaults),
 			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
 				WithBrokerCellReady,
+				WithIngressTemplate(ingressTemplate),
 				WithBrokerCellSetDefaults),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
@@ -435,6 +549,218 @@ func TestAllCases(t *testing.T) {
 		},
 		PostConditions: []func(*testing.T, *TableRow){},
 		WantErr:        true,
+	}, {
+		Name: "Broker is placed on the brokercell chosen by the placement policy",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerSetDefaults),
+			NewBrokerCell(otherBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithIngressTemplate(otherIngressTemplate),
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerReadyURI(otherBrokerAddress),
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "BrokerCellAssigned", "Assigned to brokercell other-brokercell"),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+			patchMetadata(testNS, brokerName,
+				`{"annotations":{"internal.events.cloud.google.com/brokerCellDrainDeadline":null},`+
+					`"labels":{"events.cloud.google.com/brokercell":"other-brokercell","internal.events.cloud.google.com/previousBrokerCell":null}}`),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre":                      []PubsubAction{},
+			"brokerPlacementConfigMap": NewBrokerPlacementConfigMap(brokerplacement.FixedStrategy, otherBrokerCellName, 0),
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Broker is moved to the requested brokercell, keeps the address of the previous brokercell",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerRequestedBrokerCell(otherBrokerCellName),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerSetDefaults),
+			NewBrokerCell(resources.DefaultBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithIngressTemplate(ingressTemplate),
+				WithBrokerCellSetDefaults),
+			NewBrokerCell(otherBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithIngressTemplate(otherIngressTemplate),
+				WithBrokerCellSetDefaults),
+		},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "BrokerCellAssigned", "Assigned to brokercell other-brokercell"),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+			patchMetadata(testNS, brokerName,
+				`{"annotations":{"internal.events.cloud.google.com/brokerCellDrainDeadline":"2021-01-01T00:06:00Z"},`+
+					`"labels":{"events.cloud.google.com/brokercell":"other-brokercell","internal.events.cloud.google.com/previousBrokerCell":"default"}}`),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Broker keeps the address of the previous brokercell while the new brokercell is not ready",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerAssignedBrokerCell(otherBrokerCellName),
+				WithBrokerMovingFromBrokerCell(resources.DefaultBrokerCellName, testNow.Add(time.Minute)),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerSetDefaults),
+			NewBrokerCell(otherBrokerCellName, systemNS,
+				WithBrokerCellIngressFailed("", ""),
+				WithIngressTemplate(otherIngressTemplate),
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerAssignedBrokerCell(otherBrokerCellName),
+				WithBrokerMovingFromBrokerCell(resources.DefaultBrokerCellName, testNow.Add(time.Minute)),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerBrokerCellUnknown("BrokerCellNotReady", "Brokercell knative-testing/other-brokercell is not ready"),
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Broker switches to the address of the new brokercell, previous brokercell is draining",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerAssignedBrokerCell(otherBrokerCellName),
+				WithBrokerMovingFromBrokerCell(resources.DefaultBrokerCellName, testNow.Add(time.Minute)),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerSetDefaults),
+			NewBrokerCell(otherBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithIngressTemplate(otherIngressTemplate),
+				WithBrokerCellSetDefaults),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerAssignedBrokerCell(otherBrokerCellName),
+				WithBrokerMovingFromBrokerCell(resources.DefaultBrokerCellName, testNow.Add(time.Minute)),
+				WithBrokerReadyURI(otherBrokerAddress),
+				WithBrokerSetDefaults,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+			patchMetadata(testNS, brokerName,
+				`{"annotations":{"internal.events.cloud.google.com/brokerCellDrainDeadline":"2021-01-01T00:05:00Z"}}`),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Broker is moved, previous brokercell is drained",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerDeliverySpec(brokerDeliverySpec),
+				WithBrokerAssignedBrokerCell(otherBrokerCellName),
+				WithBrokerMovingFromBrokerCell(resources.DefaultBrokerCellName, testNow.Add(-time.Second)),
+				WithBrokerReadyURI(otherBrokerAddress),
+				WithBrokerSetDefaults),
+			NewBrokerCell(otherBrokerCellName, systemNS,
+				WithBrokerCellReady,
+				WithIngressTemplate(otherIngressTemplate),
+				WithBrokerCellSetDefaults),
+		},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "BrokerCellDrained", "Drained previous brokercell default"),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+			patchMetadata(testNS, brokerName,
+				`{"annotations":{"internal.events.cloud.google.com/brokerCellDrainDeadline":null},`+
+					`"labels":{"internal.events.cloud.google.com/previousBrokerCell":null}}`),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
 	}}
 
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
@@ -462,6 +788,12 @@ func TestAllCases(t *testing.T) {
 			drStore = NewDataresidencyTestStore(t, cm.(*corev1.ConfigMap))
 		}
 
+		// If we found "brokerPlacementConfigMap" in OtherData, we create a store with the configmap
+		var bpStore *brokerplacement.Store
+		if cm, ok := testData["brokerPlacementConfigMap"]; ok {
+			bpStore = NewBrokerPlacementTestStore(t, cm.(*corev1.ConfigMap))
+		}
+
 		// If maxPSClientCreateTime is in testData, no pubsub client is passed to reconciler, the reconciler
 		// will create one in demand
 		testPSClient := psclient
@@ -475,12 +807,15 @@ func TestAllCases(t *testing.T) {
 		ctx = addressable.WithDuck(ctx)
 		ctx = resource.WithDuck(ctx)
 		r := &Reconciler{
-			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
-			brokerCellLister:   listers.GetBrokerCellLister(),
-			projectID:          testProject,
-			pubsubClient:       testPSClient,
-			dataresidencyStore: drStore,
-			clusterRegion:      testClusterRegion,
+			Base:                 reconciler.NewBase(ctx, controllerAgentName, cmw),
+			brokerCellLister:     listers.GetBrokerCellLister(),
+			projectID:            testProject,
+			pubsubClient:         testPSClient,
+			dataresidencyStore:   drStore,
+			brokerPlacementStore: bpStore,
+			backlogReporter:      backlog.NewReporter(ctx, gmonitoringtesting.TestClientCreator(testData["monitoring"])),
+			clusterRegion:        testClusterRegion,
+			clock:                clock.NewFakeClock(testNow),
 		}
 		return brokerreconciler.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetBrokerLister(), r.Recorder, r, brokerv1beta1.BrokerClass)
 	}))
@@ -494,3 +829,11 @@ func patchFinalizers(namespace, name, finalizer string) clientgotesting.PatchAct
 	action.Patch = []byte(patch)
 	return action
 }
+
+func patchMetadata(namespace, name, metadata string) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	action.Patch = []byte(`{"metadata":` + metadata + `}`)
+	return action
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+)
+
+const (
+	// brokerCellPropagationDelay is how long a moved Broker keeps the address
+	// of its previous brokercell, so that the data plane of the new brokercell
+	// receives the Broker's targets config before events are sent to it.
+	brokerCellPropagationDelay = time.Minute
+
+	// brokerCellDrainPeriod is how long the previous brokercell of a moved
+	// Broker keeps serving it after its address changed, so that the events of
+	// senders which haven't picked up the new address yet are not dropped.
+	brokerCellDrainPeriod = 5 * time.Minute
+)
+
+// reconcileBrokerCellPlacement assigns the Broker to the brokercell requested
+// by its annotation, or chosen by the placement policy if the Broker isn't
+// placed yet. If the Broker is served by another brokercell, that brokercell
+// keeps serving it until it is drained.
+func (r *Reconciler) reconcileBrokerCellPlacement(ctx context.Context, b *brokerv1beta1.Broker) error {
+	current := resources.BrokerCellName(b)
+	desired := r.desiredBrokerCell(b)
+	if desired == current {
+		return nil
+	}
+	labels := map[string]interface{}{brokerv1beta1.BrokerCellLabelKey: desired}
+	annotations := map[string]interface{}{}
+	if b.Status.Address.URL != nil {
+		// The current brokercell serves the Broker until it is drained.
+		labels[brokerv1beta1.PreviousBrokerCellLabelKey] = current
+		deadline := r.clock.Now().Add(brokerCellPropagationDelay + brokerCellDrainPeriod)
+		annotations[brokerv1beta1.BrokerCellDrainDeadlineAnnotationKey] = deadline.Format(time.RFC3339)
+	} else {
+		labels[brokerv1beta1.PreviousBrokerCellLabelKey] = nil
+		annotations[brokerv1beta1.BrokerCellDrainDeadlineAnnotationKey] = nil
+	}
+	if err := r.patchBrokerMetadata(ctx, b, labels, annotations); err != nil {
+		logging.FromContext(ctx).Error("Failed to assign the brokercell", zap.String("brokercell", desired), zap.Error(err))
+		b.Status.MarkBrokerCellFailed("BrokerCellAssignmentFailed", "Failed to assign brokercell %s: %v", desired, err)
+		return err
+	}
+	r.Recorder.Eventf(b, corev1.EventTypeNormal, brokerCellAssigned, "Assigned to brokercell %s", desired)
+	return nil
+}
+
+// desiredBrokerCell returns the name of the brokercell that should serve the
+// Broker. Changing the placement policy doesn't move placed Brokers.
+func (r *Reconciler) desiredBrokerCell(b *brokerv1beta1.Broker) string {
+	if bc := b.RequestedBrokerCell(); bc != "" {
+		return bc
+	}
+	if _, placed := b.GetLabels()[brokerv1beta1.BrokerCellLabelKey]; placed || b.Status.Address.URL != nil {
+		return resources.BrokerCellName(b)
+	}
+	var policy *brokerplacement.Defaults
+	if r.brokerPlacementStore != nil {
+		policy = r.brokerPlacementStore.Load().BrokerPlacementDefaults
+	}
+	return policy.BrokerCell(b.Namespace, b.Name)
+}
+
+// reconcileBrokerCellMove sets the address of a moving Broker and drains its
+// previous brokercell. The Broker keeps the address of its previous brokercell
+// until the new brokercell is ready and had time to receive the targets config
+// of the Broker, then the previous brokercell keeps serving the Broker for
+// brokerCellDrainPeriod.
+func (r *Reconciler) reconcileBrokerCellMove(ctx context.Context, b *brokerv1beta1.Broker, address *apis.URL, brokerCellReady bool) error {
+	now := r.clock.Now()
+	deadline, err := time.Parse(time.RFC3339, b.GetAnnotations()[brokerv1beta1.BrokerCellDrainDeadlineAnnotationKey])
+	if err != nil {
+		// The deadline is missing or invalid, start draining from now.
+		deadline = now.Add(brokerCellDrainPeriod)
+		return r.patchBrokerCellDrainDeadline(ctx, b, deadline)
+	}
+	if b.Status.Address.URL == nil || b.Status.Address.URL.String() != address.String() {
+		if switchTime := deadline.Add(-brokerCellDrainPeriod); !brokerCellReady || now.Before(switchTime) {
+			// Keep the address of the previous brokercell.
+			r.requeueAfter(b, switchTime.Sub(now))
+			return nil
+		}
+		b.Status.SetAddress(address)
+		// Give the senders the whole drain period to pick up the new address,
+		// even if the new brokercell became ready late.
+		return r.patchBrokerCellDrainDeadline(ctx, b, now.Add(brokerCellDrainPeriod))
+	}
+	if now.Before(deadline) {
+		r.requeueAfter(b, deadline.Sub(now))
+		return nil
+	}
+	previous := resources.PreviousBrokerCellName(b)
+	labels := map[string]interface{}{brokerv1beta1.PreviousBrokerCellLabelKey: nil}
+	annotations := map[string]interface{}{brokerv1beta1.BrokerCellDrainDeadlineAnnotationKey: nil}
+	if err := r.patchBrokerMetadata(ctx, b, labels, annotations); err != nil {
+		logging.FromContext(ctx).Error("Failed to drain the previous brokercell", zap.String("brokercell", previous), zap.Error(err))
+		return err
+	}
+	r.Recorder.Eventf(b, corev1.EventTypeNormal, brokerCellDrained, "Drained previous brokercell %s", previous)
+	return nil
+}
+
+func (r *Reconciler) patchBrokerCellDrainDeadline(ctx context.Context, b *brokerv1beta1.Broker, deadline time.Time) error {
+	annotations := map[string]interface{}{brokerv1beta1.BrokerCellDrainDeadlineAnnotationKey: deadline.Format(time.RFC3339)}
+	if err := r.patchBrokerMetadata(ctx, b, nil, annotations); err != nil {
+		logging.FromContext(ctx).Error("Failed to set the brokercell drain deadline", zap.Error(err))
+		return err
+	}
+	r.requeueAfter(b, deadline.Sub(r.clock.Now()))
+	return nil
+}
+
+// patchBrokerMetadata merges the labels and annotations into the Broker's
+// metadata. Nil values remove the label or annotation.
+func (r *Reconciler) patchBrokerMetadata(ctx context.Context, b *brokerv1beta1.Broker, labels, annotations map[string]interface{}) error {
+	metadata := map[string]interface{}{}
+	if len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if len(annotations) > 0 {
+		metadata["annotations"] = annotations
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": metadata})
+	if err != nil {
+		return err
+	}
+	patched, err := r.RunClientSet.EventingV1beta1().Brokers(b.Namespace).Patch(ctx, b.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	b.SetLabels(patched.GetLabels())
+	b.SetAnnotations(patched.GetAnnotations())
+	return nil
+}
+
+func (r *Reconciler) requeueAfter(b *brokerv1beta1.Broker, after time.Duration) {
+	if r.enqueueAfter != nil {
+		r.enqueueAfter(b, after)
+	}
+}
@@ -20,11 +20,12 @@ import (
 	"context"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 
 	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
-	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/clock"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/google/knative-gcp/pkg/logging"
@@ -33,13 +34,17 @@ import (
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
 	brokercellinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell"
 	brokerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/broker"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 	"github.com/google/knative-gcp/pkg/utils"
 )
 
@@ -52,13 +57,13 @@ const (
 type Constructor injection.ControllerConstructor
 
 // NewConstructor creates a constructor to make a Broker controller.
-func NewConstructor(brokerdeliveryss *brokerdelivery.StoreSingleton, dataresidencyss *dataresidency.StoreSingleton) Constructor {
+func NewConstructor(brokerdeliveryss *brokerdelivery.StoreSingleton, dataresidencyss *dataresidency.StoreSingleton, brokerplacementss *brokerplacement.StoreSingleton) Constructor {
 	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
-		return newController(ctx, cmw, brokerdeliveryss.Store(ctx, cmw), dataresidencyss.Store(ctx, cmw))
+		return newController(ctx, cmw, brokerdeliveryss.Store(ctx, cmw), dataresidencyss.Store(ctx, cmw), brokerplacementss.Store(ctx, cmw))
 	}
 }
 
-func newController(ctx context.Context, cmw configmap.Watcher, brds *brokerdelivery.Store, drs *dataresidency.Store) *controller.Impl {
+func newController(ctx context.Context, cmw configmap.Watcher, brds *brokerdelivery.Store, drs *dataresidency.Store, bps *brokerplacement.Store) *controller.Impl {
 	brokerInformer := brokerinformer.Get(ctx)
 	bcInformer := brokercellinformer.Get(ctx)
 
@@ -86,10 +91,13 @@ func newController(ctx context.Context, cmw configmap.Watcher, brds *brokerdeliv
 	}
 
 	r := &Reconciler{
-		Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
-		brokerCellLister:   bcInformer.Lister(),
-		pubsubClient:       client,
-		dataresidencyStore: drs,
+		Base:                 reconciler.NewBase(ctx, controllerAgentName, cmw),
+		brokerCellLister:     bcInformer.Lister(),
+		pubsubClient:         client,
+		dataresidencyStore:   drs,
+		brokerPlacementStore: bps,
+		backlogReporter:      backlog.NewReporter(ctx, gmonitoring.NewClient),
+		clock:                clock.RealClock{},
 	}
 
 	impl := brokerreconciler.NewImpl(ctx, r, brokerv1beta1.BrokerClass,
@@ -99,6 +107,8 @@ func newController(ctx context.Context, cmw configmap.Watcher, brds *brokerdeliv
 			}
 		})
 
+	r.enqueueAfter = impl.EnqueueAfter
+
 	r.Logger.Info("Setting up event handlers")
 
 	brokerInformer.Informer().AddEventHandlerWithResyncPeriod(
@@ -112,17 +122,16 @@ func newController(ctx context.Context, cmw configmap.Watcher, brds *brokerdeliv
 
 	bcInformer.Informer().AddEventHandler(controller.HandleAll(
 		func(obj interface{}) {
-			if _, ok := obj.(*inteventsv1alpha1.BrokerCell); ok {
-				// TODO(#866) Only select brokers that point to this brokercell by label selector once the
-				// webhook assigns the brokercell label, i.e.,
-				// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
-				brokers, err := brokerInformer.Lister().List(labels.Everything())
-				if err != nil {
-					r.Logger.Error("Failed to list brokers", zap.Error(err))
-					return
-				}
-				for _, broker := range brokers {
-					impl.Enqueue(broker)
+			if bc, ok := obj.(*inteventsv1alpha1.BrokerCell); ok && bc.Namespace == system.Namespace() {
+				for _, selector := range resources.BrokerCellSelectors(bc.Name) {
+					brokers, err := brokerInformer.Lister().List(selector)
+					if err != nil {
+						r.Logger.Error("Failed to list brokers", zap.Error(err))
+						return
+					}
+					for _, broker := range brokers {
+						impl.Enqueue(broker)
+					}
 				}
 			}
 		},
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 
@@ -40,7 +41,7 @@ import (
 func TestNew(t *testing.T) {
 	ctx, _ := SetupFakeContext(t)
 
-	c := NewConstructor(&brokerdelivery.StoreSingleton{}, &dataresidency.StoreSingleton{})(ctx, configmap.NewStaticWatcher(
+	c := NewConstructor(&brokerdelivery.StoreSingleton{}, &dataresidency.StoreSingleton{}, &brokerplacement.StoreSingleton{})(ctx, configmap.NewStaticWatcher(
 		&corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      logging.ConfigMapName(),
@@ -64,6 +65,7 @@ func TestNew(t *testing.T) {
 		},
 		NewBrokerDeliveryConfigMapFromDeliverySpec(nil),
 		NewDataresidencyConfigMapFromRegions([]string{}),
+		NewBrokerPlacementConfigMap(brokerplacement.FixedStrategy, "", 0),
 	))
 
 	if c == nil {
@@ -40,8 +40,7 @@ import (
 func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, b *brokerv1beta1.Broker) error {
 	var bc *inteventsv1alpha1.BrokerCell
 	var err error
-	// TODO(#866) Get brokercell based on the label (or annotation) on the broker.
-	bc, err = r.brokerCellLister.BrokerCells(system.Namespace()).Get(resources.DefaultBrokerCellName)
+	bc, err = r.brokerCellLister.BrokerCells(system.Namespace()).Get(resources.BrokerCellName(b))
 
 	if err != nil && !apierrs.IsNotFound(err) {
 		logging.FromContext(ctx).Error("Error reconciling brokercell", zap.String("namespace", b.Namespace), zap.String("broker", b.Name), zap.Error(err))
@@ -79,13 +78,30 @@ func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, b *brokerv1beta
 		b.Status.MarkBrokerCellUnknown("BrokerCellNotReady", "Brokercell %s/%s is not ready", bc.Namespace, bc.Name)
 	}
 
-	//TODO(#1019) Use the IngressTemplate of brokercell.
+	address, err := addressOnBrokerCell(bc, b)
+	if err != nil {
+		logging.FromContext(ctx).Error("Invalid ingress template of brokercell", zap.String("namespace", b.Namespace), zap.String("broker", b.Name), zap.Error(err))
+		b.Status.MarkBrokerCellFailed("BrokerCellIngressTemplateInvalid", "Invalid ingress template of brokercell %s/%s: %v", bc.Namespace, bc.Name, err)
+		return err
+	}
+	if resources.PreviousBrokerCellName(b) != "" {
+		return r.reconcileBrokerCellMove(ctx, b, address, bc.Status.IsReady())
+	}
+	b.Status.SetAddress(address)
+
+	return nil
+}
+
+// addressOnBrokerCell returns the address of the Broker served by the brokercell.
+func addressOnBrokerCell(bc *inteventsv1alpha1.BrokerCell, b *brokerv1beta1.Broker) (*apis.URL, error) {
+	if bc.Status.IngressTemplate != "" {
+		return resources.BrokerAddress(bc.Status.IngressTemplate, b)
+	}
+	// The brokercell hasn't been reconciled yet.
 	ingressServiceName := brokercellresources.Name(bc.Name, brokercellresources.IngressName)
-	b.Status.SetAddress(&apis.URL{
+	return &apis.URL{
 		Scheme: "http",
 		Host:   network.GetServiceHostname(ingressServiceName, bc.Namespace),
 		Path:   ingress.BrokerPath(b.Namespace, b.Name),
-	})
-
-	return nil
+	}, nil
 }
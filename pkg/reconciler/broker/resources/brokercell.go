@@ -17,23 +17,97 @@ limitations under the License.
 package resources
 
 import (
-	"github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"net/url"
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/system"
 
+	"github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 )
 
-// Right now we only support one brokercell in the system namespace in the cluster.
-// TODO(#866) Delete hard-coded brokercell once we can dynamically assign brokercell to brokers.
+// DefaultBrokerCellName is the name of the BrokerCell of the Brokers which are
+// not assigned to a BrokerCell.
 const DefaultBrokerCellName = "default"
 
+// BrokerCellName returns the name of the BrokerCell in the system namespace
+// that serves the Broker. A nil Broker is served by the default BrokerCell.
+func BrokerCellName(b *v1beta1.Broker) string {
+	if b == nil {
+		return DefaultBrokerCellName
+	}
+	if name := b.GetLabels()[v1beta1.BrokerCellLabelKey]; name != "" {
+		return name
+	}
+	return DefaultBrokerCellName
+}
+
+// PreviousBrokerCellName returns the name of the BrokerCell the Broker is
+// moving away from, or an empty string if the Broker is not moving.
+func PreviousBrokerCellName(b *v1beta1.Broker) string {
+	if b == nil {
+		return ""
+	}
+	return b.GetLabels()[v1beta1.PreviousBrokerCellLabelKey]
+}
+
+// BrokerCellNames returns the names of the BrokerCells that serve the Broker.
+// A Broker moving to another BrokerCell is served by both BrokerCells until its
+// previous BrokerCell is drained.
+func BrokerCellNames(b *v1beta1.Broker) []string {
+	names := []string{BrokerCellName(b)}
+	if previous := PreviousBrokerCellName(b); previous != "" && previous != names[0] {
+		names = append(names, previous)
+	}
+	return names
+}
+
+// ServedByBrokerCell returns true if the BrokerCell with the given name serves
+// the Broker.
+func ServedByBrokerCell(b *v1beta1.Broker, brokerCellName string) bool {
+	for _, name := range BrokerCellNames(b) {
+		if name == brokerCellName {
+			return true
+		}
+	}
+	return false
+}
+
+// BrokerCellSelectors returns the label selectors of the Brokers served by the
+// BrokerCell with the given name. A Broker matches if it matches any of them.
+func BrokerCellSelectors(brokerCellName string) []labels.Selector {
+	selectors := []labels.Selector{
+		labels.SelectorFromSet(map[string]string{v1beta1.BrokerCellLabelKey: brokerCellName}),
+		labels.SelectorFromSet(map[string]string{v1beta1.PreviousBrokerCellLabelKey: brokerCellName}),
+	}
+	if brokerCellName == DefaultBrokerCellName {
+		unassigned, _ := labels.NewRequirement(v1beta1.BrokerCellLabelKey, selection.DoesNotExist, nil)
+		selectors = append(selectors, labels.NewSelector().Add(*unassigned))
+	}
+	return selectors
+}
+
+// BrokerAddress returns the address of the Broker generated from the ingress
+// URI template of the BrokerCell, which may contain the variables `namespace`
+// and `name`.
+func BrokerAddress(ingressTemplate string, b *v1beta1.Broker) (*apis.URL, error) {
+	address := strings.NewReplacer(
+		"{namespace}", url.PathEscape(b.Namespace),
+		"{name}", url.PathEscape(b.Name),
+	).Replace(ingressTemplate)
+	return apis.ParseURL(address)
+}
+
+// CreateBrokerCell returns the BrokerCell that serves the Broker.
 func CreateBrokerCell(b *v1beta1.Broker) *inteventsv1alpha1.BrokerCell {
-	// TODO(#866) Get brokercell from the label (or annotation) from the broker.
 	return &inteventsv1alpha1.BrokerCell{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   system.Namespace(),
-			Name:        DefaultBrokerCellName,
+			Name:        BrokerCellName(b),
 			Annotations: map[string]string{inteventsv1alpha1.CreatorKey: inteventsv1alpha1.Creator},
 		},
 	}
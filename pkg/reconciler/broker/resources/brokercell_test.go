@@ -19,10 +19,174 @@ package resources
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	_ "knative.dev/pkg/system/testing"
+
+	"github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 )
 
 // This is already tested in broker_test.go, this test is just to make coverage tool happy.
 func TestBrokerCellCreation(t *testing.T) {
 	CreateBrokerCell(nil)
 }
+
+func TestBrokerCellName(t *testing.T) {
+	tests := []struct {
+		name   string
+		broker *v1beta1.Broker
+		want   string
+	}{{
+		name: "nil broker",
+		want: DefaultBrokerCellName,
+	}, {
+		name:   "unassigned broker",
+		broker: &v1beta1.Broker{},
+		want:   DefaultBrokerCellName,
+	}, {
+		name: "empty label",
+		broker: &v1beta1.Broker{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{v1beta1.BrokerCellLabelKey: ""},
+		}},
+		want: DefaultBrokerCellName,
+	}, {
+		name: "assigned broker",
+		broker: &v1beta1.Broker{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{v1beta1.BrokerCellLabelKey: "team-a"},
+		}},
+		want: "team-a",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BrokerCellName(tt.broker); got != tt.want {
+				t.Errorf("BrokerCellName() = %q, want %q", got, tt.want)
+			}
+			if got := CreateBrokerCell(tt.broker).Name; got != tt.want {
+				t.Errorf("CreateBrokerCell().Name = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBrokerCellSelectors(t *testing.T) {
+	unassigned := labels.Set{}
+	assignedToDefault := labels.Set{v1beta1.BrokerCellLabelKey: DefaultBrokerCellName}
+	assignedToTeam := labels.Set{v1beta1.BrokerCellLabelKey: "team-a"}
+	movingToTeam := labels.Set{v1beta1.BrokerCellLabelKey: "team-a", v1beta1.PreviousBrokerCellLabelKey: DefaultBrokerCellName}
+
+	tests := []struct {
+		brokerCell string
+		labels     labels.Set
+		want       bool
+	}{
+		{brokerCell: DefaultBrokerCellName, labels: unassigned, want: true},
+		{brokerCell: DefaultBrokerCellName, labels: assignedToDefault, want: true},
+		{brokerCell: DefaultBrokerCellName, labels: assignedToTeam, want: false},
+		{brokerCell: "team-a", labels: unassigned, want: false},
+		{brokerCell: "team-a", labels: assignedToDefault, want: false},
+		{brokerCell: "team-a", labels: assignedToTeam, want: true},
+		{brokerCell: DefaultBrokerCellName, labels: movingToTeam, want: true},
+		{brokerCell: "team-a", labels: movingToTeam, want: true},
+		{brokerCell: "team-b", labels: movingToTeam, want: false},
+	}
+	for _, tt := range tests {
+		got := false
+		for _, selector := range BrokerCellSelectors(tt.brokerCell) {
+			got = got || selector.Matches(tt.labels)
+		}
+		if got != tt.want {
+			t.Errorf("BrokerCellSelectors(%q) match %v = %v, want %v", tt.brokerCell, tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestBrokerCellNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		broker *v1beta1.Broker
+		want   []string
+	}{{
+		name:   "unassigned broker",
+		broker: &v1beta1.Broker{},
+		want:   []string{DefaultBrokerCellName},
+	}, {
+		name: "assigned broker",
+		broker: &v1beta1.Broker{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{v1beta1.BrokerCellLabelKey: "team-a"},
+		}},
+		want: []string{"team-a"},
+	}, {
+		name: "moving broker",
+		broker: &v1beta1.Broker{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				v1beta1.BrokerCellLabelKey:         "team-a",
+				v1beta1.PreviousBrokerCellLabelKey: DefaultBrokerCellName,
+			},
+		}},
+		want: []string{"team-a", DefaultBrokerCellName},
+	}, {
+		name: "moving back to the same brokercell",
+		broker: &v1beta1.Broker{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				v1beta1.BrokerCellLabelKey:         "team-a",
+				v1beta1.PreviousBrokerCellLabelKey: "team-a",
+			},
+		}},
+		want: []string{"team-a"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BrokerCellNames(tt.broker)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("BrokerCellNames() (-want,+got): %v", diff)
+			}
+			for _, name := range tt.want {
+				if !ServedByBrokerCell(tt.broker, name) {
+					t.Errorf("ServedByBrokerCell(%q) = false, want true", name)
+				}
+			}
+			if ServedByBrokerCell(tt.broker, "team-b") {
+				t.Errorf("ServedByBrokerCell(%q) = true, want false", "team-b")
+			}
+		})
+	}
+}
+
+func TestBrokerAddress(t *testing.T) {
+	broker := &v1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "broker"}}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		wantErr  bool
+	}{{
+		name:     "path template",
+		template: "http://default-brokercell-ingress.cloud-run-events.svc.cluster.local/{namespace}/{name}",
+		want:     "http://default-brokercell-ingress.cloud-run-events.svc.cluster.local/ns/broker",
+	}, {
+		name:     "host template",
+		template: "https://{name}.{namespace}.brokers.example.com",
+		want:     "https://broker.ns.brokers.example.com",
+	}, {
+		name:     "no variables",
+		template: "http://localhost",
+		want:     "http://localhost",
+	}, {
+		name:     "invalid template",
+		template: "http://%zz/{namespace}/{name}",
+		wantErr:  true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BrokerAddress(tt.template, broker)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BrokerAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("BrokerAddress() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
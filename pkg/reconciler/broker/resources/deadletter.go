@@ -0,0 +1,37 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/resolver"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// ResolveDeadLetterSink resolves the URI of a Trigger's dead letter sink. A
+// sink reference without a namespace is resolved in the Trigger's namespace.
+func ResolveDeadLetterSink(ctx context.Context, r *resolver.URIResolver, t *brokerv1beta1.Trigger, sink *duckv1.Destination) (*apis.URL, error) {
+	dest := *sink.DeepCopy()
+	if dest.Ref != nil && dest.Ref.Namespace == "" {
+		dest.Ref.Namespace = t.Namespace
+	}
+	return r.URIFromDestinationV1(ctx, dest, t)
+}
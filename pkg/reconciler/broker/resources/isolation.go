@@ -0,0 +1,56 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// attributesNotFilterable are the CloudEvents attributes which can't be matched
+// by a Pub/Sub filter, because they are not published as "ce-" attributes or
+// because their format may change on the way.
+var attributesNotFilterable = map[string]bool{
+	"datacontenttype": true,
+	"time":            true,
+}
+
+// IsolatedSubscriptionFilter returns the Pub/Sub filter of the dedicated
+// subscription of an isolated Trigger. It matches the exact attributes of the
+// Trigger's filter which Pub/Sub can evaluate, and is empty if the Trigger
+// doesn't request a filtered subscription. The data plane still applies the
+// whole Trigger filter on the events of the subscription.
+func IsolatedSubscriptionFilter(t *brokerv1beta1.Trigger) string {
+	if _, filtered := t.Isolation(); !filtered || t.Spec.Filter == nil {
+		return ""
+	}
+	var exprs []string
+	for k, v := range t.Spec.Filter.Attributes {
+		// An empty value matches any value of the attribute.
+		if v == "" || attributesNotFilterable[k] {
+			continue
+		}
+		exprs = append(exprs, fmt.Sprintf("attributes.%s = %s", strconv.Quote("ce-"+k), strconv.Quote(v)))
+	}
+	// Sort the expressions so that the filter is stable across reconciliations.
+	sort.Strings(exprs)
+	return strings.Join(exprs, " AND ")
+}
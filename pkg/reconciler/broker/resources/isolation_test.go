@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+func TestIsolatedSubscriptionFilter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		isolation string
+		filter    *eventingv1beta1.TriggerFilter
+		want      string
+	}{{
+		name:      "not filtered",
+		isolation: brokerv1beta1.IsolationSubscription,
+		filter: &eventingv1beta1.TriggerFilter{
+			Attributes: eventingv1beta1.TriggerFilterAttributes{"type": "foo"},
+		},
+	}, {
+		name:      "no filter",
+		isolation: brokerv1beta1.IsolationFilteredSubscription,
+	}, {
+		name:      "filtered",
+		isolation: brokerv1beta1.IsolationFilteredSubscription,
+		filter: &eventingv1beta1.TriggerFilter{
+			Attributes: eventingv1beta1.TriggerFilterAttributes{
+				"type":            "foo",
+				"source":          `with "quotes"`,
+				"subject":         "",
+				"time":            "2021-01-01T00:00:00Z",
+				"datacontenttype": "application/json",
+				"myextension":     "bar",
+			},
+		},
+		want: `attributes."ce-myextension" = "bar" AND attributes."ce-source" = "with \"quotes\"" AND attributes."ce-type" = "foo"`,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{brokerv1beta1.IsolationAnnotationKey: tc.isolation},
+				},
+				Spec: brokerv1beta1.TriggerSpec{TriggerSpec: eventingv1beta1.TriggerSpec{Filter: tc.filter}},
+			}
+			if got := IsolatedSubscriptionFilter(trig); got != tc.want {
+				t.Errorf("IsolatedSubscriptionFilter()=%q, want=%q", got, tc.want)
+			}
+		})
+	}
+}
@@ -60,3 +60,26 @@ func GenerateRetryTopicName(t *brokerv1beta1.Trigger) string {
 func GenerateRetrySubscriptionName(t *brokerv1beta1.Trigger) string {
 	return naming.TruncatedPubsubResourceName("cre-tgr", t.Namespace, t.Name, t.UID)
 }
+
+// GenerateDeadLetterTopicName generates a deterministic name for the
+// broker-managed dead letter topic of a Trigger. If the topic name would be
+// longer than allowed by PubSub, the Trigger name is truncated to fit.
+func GenerateDeadLetterTopicName(t *brokerv1beta1.Trigger) string {
+	return naming.TruncatedPubsubResourceName("cre-dlq", t.Namespace, t.Name, t.UID)
+}
+
+// GenerateDeadLetterSubscriptionName generates a deterministic name for the
+// subscription of the broker-managed dead letter topic of a Trigger. If the
+// subscription name would be longer than allowed by PubSub, the Trigger name
+// is truncated to fit.
+func GenerateDeadLetterSubscriptionName(t *brokerv1beta1.Trigger) string {
+	return naming.TruncatedPubsubResourceName("cre-dlq", t.Namespace, t.Name, t.UID)
+}
+
+// GenerateIsolatedSubscriptionName generates a deterministic name for the
+// dedicated subscription of an isolated Trigger on the decoupling topic of its
+// Broker. If the subscription name would be longer than allowed by PubSub, the
+// Trigger name is truncated to fit.
+func GenerateIsolatedSubscriptionName(t *brokerv1beta1.Trigger) string {
+	return naming.TruncatedPubsubResourceName("cre-iso", t.Namespace, t.Name, t.UID)
+}
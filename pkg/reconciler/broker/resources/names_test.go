@@ -193,6 +193,84 @@ func TestGenerateRetrySubscriptionName(t *testing.T) {
 	}
 }
 
+func TestGenerateDeadLetterTopicName(t *testing.T) {
+	testCases := []struct {
+		ns   string
+		n    string
+		uid  string
+		want string
+	}{{
+		ns:   "default",
+		n:    "default",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-dlq_default_default_%s", testUID),
+	}, {
+		ns:   "with-dashes",
+		n:    "more-dashes",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-dlq_with-dashes_more-dashes_%s", testUID),
+	}, {
+		ns:   maxNamespace,
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-dlq_%s_%s_%s", maxNamespace, strings.Repeat("n", truncatedNameMax), testUID),
+	}, {
+		ns:   "default",
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-dlq_default_%s_%s", strings.Repeat("n", truncatedNameMax+(naming.K8sNamespaceMax-7)), testUID),
+	}}
+
+	for _, tc := range testCases {
+		got := GenerateDeadLetterTopicName(trigger(tc.ns, tc.n, tc.uid))
+		if len(got) > naming.PubsubMax {
+			t.Errorf("name length %d is greater than %d", len(got), naming.PubsubMax)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unexpected (want, +got) = %v", diff)
+		}
+	}
+}
+
+func TestGenerateDeadLetterSubscriptionName(t *testing.T) {
+	testCases := []struct {
+		ns   string
+		n    string
+		uid  string
+		want string
+	}{{
+		ns:   "default",
+		n:    "default",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-dlq_default_default_%s", testUID),
+	}, {
+		ns:   "with-dashes",
+		n:    "more-dashes",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-dlq_with-dashes_more-dashes_%s", testUID),
+	}, {
+		ns:   maxNamespace,
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-dlq_%s_%s_%s", maxNamespace, strings.Repeat("n", truncatedNameMax), testUID),
+	}, {
+		ns:   "default",
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-dlq_default_%s_%s", strings.Repeat("n", truncatedNameMax+(naming.K8sNamespaceMax-7)), testUID),
+	}}
+
+	for _, tc := range testCases {
+		got := GenerateDeadLetterSubscriptionName(trigger(tc.ns, tc.n, tc.uid))
+		if len(got) > naming.PubsubMax {
+			t.Errorf("name length %d is greater than %d", len(got), naming.PubsubMax)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unexpected (want, +got) = %v", diff)
+		}
+	}
+}
+
 func broker(ns, n, uid string) *brokerv1beta1.Broker {
 	return &brokerv1beta1.Broker{
 		ObjectMeta: metav1.ObjectMeta{
@@ -212,3 +290,42 @@ func trigger(ns, n, uid string) *brokerv1beta1.Trigger {
 		},
 	}
 }
+
+func TestGenerateIsolatedSubscriptionName(t *testing.T) {
+	testCases := []struct {
+		ns   string
+		n    string
+		uid  string
+		want string
+	}{{
+		ns:   "default",
+		n:    "default",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-iso_default_default_%s", testUID),
+	}, {
+		ns:   "with-dashes",
+		n:    "more-dashes",
+		uid:  testUID,
+		want: fmt.Sprintf("cre-iso_with-dashes_more-dashes_%s", testUID),
+	}, {
+		ns:   maxNamespace,
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-iso_%s_%s_%s", maxNamespace, strings.Repeat("n", truncatedNameMax), testUID),
+	}, {
+		ns:   "default",
+		n:    maxName,
+		uid:  testUID,
+		want: fmt.Sprintf("cre-iso_default_%s_%s", strings.Repeat("n", truncatedNameMax+(naming.K8sNamespaceMax-7)), testUID),
+	}}
+
+	for _, tc := range testCases {
+		got := GenerateIsolatedSubscriptionName(trigger(tc.ns, tc.n, tc.uid))
+		if len(got) > naming.PubsubMax {
+			t.Errorf("name length %d is greater than %d", len(got), naming.PubsubMax)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unexpected (want, +got) = %v", diff)
+		}
+	}
+}
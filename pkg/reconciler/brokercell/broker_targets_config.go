@@ -19,10 +19,14 @@ package brokercell
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.uber.org/zap"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/eventing/pkg/apis/eventing"
 
@@ -30,6 +34,7 @@ import (
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
 	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/utils/volume"
@@ -40,42 +45,129 @@ const (
 )
 
 func (r *Reconciler) reconcileConfig(ctx context.Context, bc *intv1alpha1.BrokerCell) error {
-	// TODO(#866) Only select brokers that point to this brokercell by label selector once the
-	// webhook assigns the brokercell label, i.e.,
-	// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
-	brokers, err := r.brokerLister.List(labels.Everything())
+	key := types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name}
+	start := time.Now()
+	brokerTargets, dirty := r.targetsCache.take(key, start)
+	generationType := metrics.IncrementalGeneration
+	var err error
+	if brokerTargets == nil {
+		generationType = metrics.FullGeneration
+		brokerTargets, err = r.generateTargets(ctx, bc)
+	} else {
+		err = r.regenerateCellTenants(ctx, bc, brokerTargets, dirty)
+	}
 	if err != nil {
-		logging.FromContext(ctx).Error("Failed to list brokers", zap.Error(err))
-		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list brokers: %v", err)
+		// Regenerate the dirty CellTenants in the next reconcile.
+		r.targetsCache.markDirty(key, dirty...)
 		return err
 	}
-	// Start with a fresh config and add brokers/triggers into it. This approach is straightforward and reliable,
-	// however not efficient if there are too many triggers. If performance becomes an issue, we can consider
-	// maintaining 2 queues for updated brokers and triggers, and only update the config for updated brokers/triggers.
+	r.targetsCache.store(key, brokerTargets, generationType == metrics.FullGeneration, start)
+	latency := time.Since(start)
+
+	if r.targetsServer != nil {
+		r.targetsServer.Update(key, brokerTargets)
+	}
+	size, err := r.updateTargetsConfig(ctx, bc, brokerTargets)
+	switch {
+	case err != nil && r.targetsServer != nil:
+		// The data plane watches the targets config server, the ConfigMap is
+		// only its fallback if the server is disabled. It may also be larger
+		// than a ConfigMap allows. The update is retried by the next
+		// reconciliation.
+		logging.FromContext(ctx).Warn("Failed to update broker targets configmap, the data plane is served by the targets config server", zap.Error(err))
+	case err != nil:
+		logging.FromContext(ctx).Error("Failed to update broker targets configmap", zap.Error(err))
+		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to update configmap: %v", err)
+		return err
+	}
+	if r.targetsConfigReporter != nil {
+		if err := r.targetsConfigReporter.ReportGeneration(ctx, bc.Namespace, bc.Name, generationType, latency, size); err != nil {
+			logging.FromContext(ctx).Error("Failed to report targets config generation", zap.Error(err))
+		}
+	}
+	bc.Status.MarkTargetsConfigReady()
+	return nil
+}
+
+// generateTargets generates the targets config from all the brokers and
+// channels assigned to the brokercell.
+func (r *Reconciler) generateTargets(ctx context.Context, bc *intv1alpha1.BrokerCell) (config.Targets, error) {
 	brokerTargets := memory.NewEmptyTargets()
-	for _, broker := range brokers {
-		// Filter by `eventing.knative.dev/broker: <name>` here
-		// to get only the triggers for this broker. The trigger webhook will
-		// ensure that triggers are always labeled with their broker name.
-		triggers, err := r.triggerLister.Triggers(broker.Namespace).List(labels.SelectorFromSet(map[string]string{eventing.BrokerLabelKey: broker.Name}))
+	for _, selector := range brokerresources.BrokerCellSelectors(bc.Name) {
+		brokers, err := r.brokerLister.List(selector)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list brokers", zap.Error(err))
+			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list brokers: %v", err)
+			return nil, err
+		}
+		for _, broker := range brokers {
+			if err := r.addBrokerToConfig(ctx, bc, broker, brokerTargets); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := r.addChannelsToConfig(ctx, bc, brokerTargets); err != nil {
+		return nil, err
+	}
+	return brokerTargets, nil
+}
+
+// regenerateCellTenants regenerates the entries of the given CellTenants in
+// the targets config.
+func (r *Reconciler) regenerateCellTenants(ctx context.Context, bc *intv1alpha1.BrokerCell, brokerTargets config.Targets, keys []config.CellTenantKey) error {
+	for _, key := range keys {
+		var err error
+		switch key.Type() {
+		case config.CellTenantType_BROKER:
+			err = r.regenerateBroker(ctx, bc, brokerTargets, key.NamespacedName())
+		case config.CellTenantType_CHANNEL:
+			err = r.regenerateChannel(ctx, bc, brokerTargets, key.NamespacedName())
+		}
 		if err != nil {
-			logging.FromContext(ctx).Error("Failed to list triggers", zap.String("Broker", broker.Name), zap.Error(err))
-			bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list triggers for broker %v: %v", broker.Name, err)
 			return err
 		}
-		r.addToConfig(ctx, broker, triggers, brokerTargets)
 	}
-	if err := r.updateTargetsConfig(ctx, bc, brokerTargets); err != nil {
-		logging.FromContext(ctx).Error("Failed to update broker targets configmap", zap.Error(err))
-		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to update configmap: %v", err)
+	return nil
+}
+
+// regenerateBroker regenerates the entry of the given broker in the targets
+// config. Brokers which no longer exist or were moved to another brokercell
+// are removed from the targets config.
+func (r *Reconciler) regenerateBroker(ctx context.Context, bc *intv1alpha1.BrokerCell, brokerTargets config.Targets, key types.NamespacedName) error {
+	broker, err := r.brokerLister.Brokers(key.Namespace).Get(key.Name)
+	if err != nil && !apierrs.IsNotFound(err) {
+		logging.FromContext(ctx).Error("Failed to get broker", zap.String("Broker", key.String()), zap.Error(err))
+		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to get broker %v: %v", key, err)
 		return err
 	}
-	bc.Status.MarkTargetsConfigReady()
+	if apierrs.IsNotFound(err) || !brokerresources.ServedByBrokerCell(broker, bc.Name) {
+		deleted := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		brokerTargets.MutateCellTenant(config.KeyFromBroker(deleted), func(m config.CellTenantMutation) {
+			m.Delete()
+		})
+		return nil
+	}
+	return r.addBrokerToConfig(ctx, bc, broker, brokerTargets)
+}
+
+// addBrokerToConfig lists the triggers of the broker and adds them with the
+// broker to the targets config.
+func (r *Reconciler) addBrokerToConfig(ctx context.Context, bc *intv1alpha1.BrokerCell, broker *brokerv1beta1.Broker, brokerTargets config.Targets) error {
+	// Filter by `eventing.knative.dev/broker: <name>` here
+	// to get only the triggers for this broker. The trigger webhook will
+	// ensure that triggers are always labeled with their broker name.
+	triggers, err := r.triggerLister.Triggers(broker.Namespace).List(labels.SelectorFromSet(map[string]string{eventing.BrokerLabelKey: broker.Name}))
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list triggers", zap.String("Broker", broker.Name), zap.Error(err))
+		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list triggers for broker %v: %v", broker.Name, err)
+		return err
+	}
+	r.addToConfig(ctx, broker, triggers, brokerTargets)
 	return nil
 }
 
 // addToConfig reconstructs the data entry for the given broker and add it to targets-config.
-func (r *Reconciler) addToConfig(_ context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger, brokerTargets config.Targets) {
+func (r *Reconciler) addToConfig(ctx context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger, brokerTargets config.Targets) {
 	// TODO Maybe get rid of CellTenantMutation and add Delete() and Upsert(broker) methods to TargetsConfig. Now we always
 	//  delete or update the entire broker entry and we don't need partial updates per trigger.
 	// The code can be simplified to r.targetsConfig.Upsert(brokerConfigEntry)
@@ -92,6 +184,8 @@ func (r *Reconciler) addToConfig(_ context.Context, b *brokerv1beta1.Broker, tri
 		// Then reconstruct the broker entry and insert it
 		m.SetID(string(b.UID))
 		m.SetAddress(b.Status.Address.URL.String())
+		m.SetOrderingKeyExtension(b.OrderingKeyExtension())
+		m.SetIngressPolicy(resources.MakeIngressPolicy(b))
 		m.SetDecoupleQueue(&config.Queue{
 			Topic:        brokerresources.GenerateDecouplingTopicName(b),
 			Subscription: brokerresources.GenerateDecouplingSubscriptionName(b),
@@ -121,6 +215,14 @@ func (r *Reconciler) addToConfig(_ context.Context, b *brokerv1beta1.Broker, tri
 				if t.Spec.Filter != nil && t.Spec.Filter.Attributes != nil {
 					target.FilterAttributes = t.Spec.Filter.Attributes
 				}
+				target.Filters = resources.MakeTargetFilters(t.Spec.Filters)
+				target.BatchPolicy = resources.MakeTargetBatchPolicy(t)
+				target.RateLimit = resources.MakeTargetRateLimit(t)
+				target.CircuitBreaker = resources.MakeTargetCircuitBreaker(t)
+				target.IsolatedQueue = resources.MakeTargetIsolatedQueue(b, t)
+				target.Paused = t.IsPaused()
+				target.Audience = t.DeliveryAudience(b)
+				r.setDeadLetterQueue(ctx, b, t, target)
 				// TODO(#939) May need to use "data plane readiness" for trigger in stead of the
 				//  overall status, see https://github.com/google/knative-gcp/issues/939#issuecomment-644337937
 				if t.Status.IsReady() {
@@ -134,11 +236,41 @@ func (r *Reconciler) addToConfig(_ context.Context, b *brokerv1beta1.Broker, tri
 	})
 }
 
+// setDeadLetterQueue sets the broker-managed dead letter queue and the resolved
+// dead letter sink address of the target if the Trigger's dead letter sink is
+// not a Pub/Sub topic. Dead letter sinks which are Pub/Sub topics are handled by
+// Pub/Sub directly and don't need any data plane configuration.
+func (r *Reconciler) setDeadLetterQueue(ctx context.Context, b *brokerv1beta1.Broker, t *brokerv1beta1.Trigger, target *config.Target) {
+	delivery := t.DeliverySpecWithDefaults(ctx, b)
+	if delivery.DeadLetterSink == nil || brokerv1beta1.IsPubsubDeadLetterSink(delivery.DeadLetterSink) {
+		return
+	}
+	deadLetterURI, err := brokerresources.ResolveDeadLetterSink(ctx, r.uriResolver, t, delivery.DeadLetterSink)
+	if err != nil {
+		// The trigger reconciler surfaces the error in the Trigger status. Without an
+		// address the dead letter events are kept in the dead letter queue until the
+		// sink can be resolved.
+		logging.FromContext(ctx).Warn("Failed to resolve the dead letter sink", zap.String("trigger", t.Name), zap.Error(err))
+	} else {
+		target.DeadLetterAddress = deadLetterURI.String()
+	}
+	target.DeadLetterQueue = &config.Queue{
+		Topic:        brokerresources.GenerateDeadLetterTopicName(t),
+		Subscription: brokerresources.GenerateDeadLetterSubscriptionName(t),
+	}
+}
+
 //TODO all this stuff should be in a configmap variant of the config object
-func (r *Reconciler) updateTargetsConfig(ctx context.Context, bc *intv1alpha1.BrokerCell, brokerTargets config.Targets) error {
+// updateTargetsConfig updates the targets config ConfigMap and returns the size
+// of the serialized targets config in bytes.
+func (r *Reconciler) updateTargetsConfig(ctx context.Context, bc *intv1alpha1.BrokerCell, brokerTargets config.Targets) (int, error) {
 	desired, err := resources.MakeTargetsConfig(bc, brokerTargets)
 	if err != nil {
-		return fmt.Errorf("error creating targets config: %w", err)
+		return 0, fmt.Errorf("error creating targets config: %w", err)
+	}
+	size := 0
+	for _, data := range desired.BinaryData {
+		size += len(data)
 	}
 
 	logging.FromContext(ctx).Debug("Current targets config", zap.Any("targetsConfig", brokerTargets.DebugString()))
@@ -149,7 +281,7 @@ func (r *Reconciler) updateTargetsConfig(ctx context.Context, bc *intv1alpha1.Br
 		DeleteFunc: nil,
 	}
 	_, err = r.cmRec.ReconcileConfigMap(ctx, bc, desired, resources.TargetsConfigMapEqual, handlerFuncs)
-	return err
+	return size, err
 }
 
 func (r *Reconciler) refreshPodVolume(ctx context.Context, bc *intv1alpha1.BrokerCell) {
@@ -29,23 +29,36 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	hpav2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"knative.dev/pkg/network"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 
 	pkgreconciler "knative.dev/pkg/reconciler"
 
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
 	bcreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
+	messaginglisters "github.com/google/knative-gcp/pkg/client/listers/messaging/v1beta1"
 	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	reconcilerutils "github.com/google/knative-gcp/pkg/reconciler/utils"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 )
 
+const (
+	// targetsConfigServerName is the name of the Service of the targets
+	// config server.
+	targetsConfigServerName = "targets-config-server"
+)
+
 type envConfig struct {
 	IngressImage           string `envconfig:"INGRESS_IMAGE" required:"true"`
 	FanoutImage            string `envconfig:"FANOUT_IMAGE" required:"true"`
@@ -54,12 +67,21 @@ type envConfig struct {
 	IngressPort            int    `envconfig:"INGRESS_PORT" default:"8080"`
 	MetricsPort            int    `envconfig:"METRICS_PORT" default:"9090"`
 	InternalMetricsEnabled bool   `envconfig:"INTERNAL_METRICS_ENABLED" default:"false"`
+	// TargetsConfigServerPort is the port on which the targets config is
+	// streamed to the data plane. 0 disables the targets config server and
+	// the data plane reads the targets config from the mounted ConfigMap.
+	TargetsConfigServerPort int `envconfig:"TARGETS_CONFIG_SERVER_PORT" default:"0"`
+	// TargetsConfigServerInsecure acknowledges that the targets config
+	// server doesn't use TLS, so the data plane's tokens and the targets
+	// config are sent in plaintext. The server is only enabled if it is set.
+	TargetsConfigServerInsecure bool `envconfig:"TARGETS_CONFIG_SERVER_INSECURE" default:"false"`
 }
 
 type listers struct {
 	brokerLister         brokerlisters.BrokerLister
 	hpaLister            hpav2beta2listers.HorizontalPodAutoscalerLister
 	triggerLister        brokerlisters.TriggerLister
+	channelLister        messaginglisters.ChannelLister
 	configMapLister      corev1listers.ConfigMapLister
 	secretLister         corev1listers.SecretLister
 	serviceAccountLister corev1listers.ServiceAccountLister
@@ -98,6 +120,7 @@ func NewReconciler(base *reconciler.Base, ls listers) (*Reconciler, error) {
 		svcRec:        svcRec,
 		deploymentRec: deploymentRec,
 		cmRec:         cmRec,
+		targetsCache:  newTargetsCache(),
 	}
 	return r, nil
 }
@@ -112,6 +135,21 @@ type Reconciler struct {
 	deploymentRec *reconcilerutils.DeploymentReconciler
 	cmRec         *reconcilerutils.ConfigMapReconciler
 
+	// uriResolver resolves the dead letter sinks of Triggers.
+	uriResolver *resolver.URIResolver
+
+	// targetsServer streams the targets config to the data plane. Nil if the
+	// targets config server is disabled.
+	targetsServer *stream.Server
+
+	// targetsCache caches the targets config of each brokercell so that only
+	// the entries of the changed brokers are regenerated.
+	targetsCache *targetsCache
+
+	// targetsConfigReporter reports the latency and the size of the targets
+	// config generation. Nil if the internal metrics are disabled.
+	targetsConfigReporter *metrics.TargetsConfigReporter
+
 	env envConfig
 }
 
@@ -255,54 +293,86 @@ func (r *Reconciler) shouldGC(ctx context.Context, bc *intv1alpha1.BrokerCell) b
 		return false
 	}
 
-	// TODO(#866) Only select brokers that point to this brokercell by label selector once the
-	// webhook assigns the brokercell label, i.e.,
-	// r.brokerLister.List(labels.SelectorFromSet(map[string]string{"brokercell":bc.Name, "brokercellns":bc.Namespace}))
-	brokers, err := r.brokerLister.List(labels.Everything())
+	for _, selector := range brokerresources.BrokerCellSelectors(bc.Name) {
+		brokers, err := r.brokerLister.List(selector)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to list brokers, skipping garbage collection logic", zap.String("brokercell", bc.Name), zap.String("Namespace", bc.Namespace))
+			return false
+		}
+		if len(brokers) > 0 {
+			return false
+		}
+	}
+
+	channels, err := r.channelLister.List(labels.Everything())
 	if err != nil {
-		logging.FromContext(ctx).Error("Failed to list brokers, skipping garbage collection logic", zap.String("brokercell", bc.Name), zap.String("Namespace", bc.Namespace))
+		logging.FromContext(ctx).Error("Failed to list channels, skipping garbage collection logic", zap.String("brokercell", bc.Name), zap.String("Namespace", bc.Namespace))
 		return false
 	}
-
-	return len(brokers) == 0
+	for _, c := range channels {
+		if c.BrokerCell() == bc.Name {
+			return false
+		}
+	}
+	return true
 }
 
 func (r *Reconciler) delete(ctx context.Context, bc *intv1alpha1.BrokerCell) pkgreconciler.Event {
 	if err := r.RunClientSet.InternalV1alpha1().BrokerCells(bc.Namespace).Delete(ctx, bc.Name, metav1.DeleteOptions{}); err != nil {
 		return fmt.Errorf("failed to garbage collect brokercell: %w", err)
 	}
+	r.forget(types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name})
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "BrokerCellGarbageCollected", "BrokerCell garbage collected: \"%s/%s\"", bc.Namespace, bc.Name)
 }
 
+// forget drops the targets config of the deleted brokercell.
+func (r *Reconciler) forget(key types.NamespacedName) {
+	r.targetsCache.forget(key)
+	if r.targetsServer != nil {
+		r.targetsServer.Delete(key)
+	}
+}
+
 func (r *Reconciler) makeIngressArgs(bc *intv1alpha1.BrokerCell, authType authcheck.AuthType) resources.IngressArgs {
 	return resources.IngressArgs{
 		Args: resources.Args{
-			ComponentName:      resources.IngressName,
-			BrokerCell:         bc,
-			Image:              r.env.IngressImage,
-			ServiceAccountName: r.env.ServiceAccountName,
-			MetricsPort:        r.env.MetricsPort,
-			AllowIstioSidecar:  true,
-			CPURequest:         bc.Spec.Components.Ingress.CPURequest,
-			CPULimit:           bc.Spec.Components.Ingress.CPULimit,
-			MemoryRequest:      bc.Spec.Components.Ingress.MemoryRequest,
-			MemoryLimit:        bc.Spec.Components.Ingress.MemoryLimit,
-			RolloutRestartTime: bc.GetAnnotations()[resources.IngressRestartTimeAnnotationKey],
-			AuthType:           authType,
+			ComponentName:              resources.IngressName,
+			BrokerCell:                 bc,
+			Image:                      r.env.IngressImage,
+			ServiceAccountName:         r.env.ServiceAccountName,
+			MetricsPort:                r.env.MetricsPort,
+			AllowIstioSidecar:          true,
+			CPURequest:                 bc.Spec.Components.Ingress.CPURequest,
+			CPULimit:                   bc.Spec.Components.Ingress.CPULimit,
+			MemoryRequest:              bc.Spec.Components.Ingress.MemoryRequest,
+			MemoryLimit:                bc.Spec.Components.Ingress.MemoryLimit,
+			RolloutRestartTime:         bc.GetAnnotations()[resources.IngressRestartTimeAnnotationKey],
+			AuthType:                   authType,
+			TargetsConfigServerAddress: r.targetsConfigServerAddress(),
 		},
 		Port: r.env.IngressPort,
-		// TODO(#1804): remove this arg when enabling the feature by default.
+		// TODO(#1804): remove this arg when the feature can no longer be disabled.
 		EnableIngressFilter: getIngressFilteringEnabled(bc),
 	}
 }
 
-// TODO(#1804): remove this function when enabling the feature by default.
+// Ingress filtering is enabled unless explicitly disabled through the annotation.
+// TODO(#1804): remove this function when the feature can no longer be disabled.
 func getIngressFilteringEnabled(bc *intv1alpha1.BrokerCell) bool {
 	if val, ok := bc.GetAnnotations()[resources.IngressFilteringEnabledAnnotationKey]; ok {
-		return val == "true"
+		return val != "false"
 	}
 
-	return false
+	return true
+}
+
+// targetsConfigServerAddress returns the address of the targets config server,
+// or an empty string if it is disabled.
+func (r *Reconciler) targetsConfigServerAddress() string {
+	if r.targetsServer == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", network.GetServiceHostname(targetsConfigServerName, system.Namespace()), r.env.TargetsConfigServerPort)
 }
 
 func (r *Reconciler) makeIngressHPAArgs(bc *intv1alpha1.BrokerCell) resources.AutoscalingArgs {
@@ -319,18 +389,19 @@ func (r *Reconciler) makeIngressHPAArgs(bc *intv1alpha1.BrokerCell) resources.Au
 func (r *Reconciler) makeFanoutArgs(bc *intv1alpha1.BrokerCell, authType authcheck.AuthType) resources.FanoutArgs {
 	return resources.FanoutArgs{
 		Args: resources.Args{
-			ComponentName:      resources.FanoutName,
-			BrokerCell:         bc,
-			Image:              r.env.FanoutImage,
-			ServiceAccountName: r.env.ServiceAccountName,
-			MetricsPort:        r.env.MetricsPort,
-			AllowIstioSidecar:  true,
-			CPURequest:         bc.Spec.Components.Fanout.CPURequest,
-			CPULimit:           bc.Spec.Components.Fanout.CPULimit,
-			MemoryRequest:      bc.Spec.Components.Fanout.MemoryRequest,
-			MemoryLimit:        bc.Spec.Components.Fanout.MemoryLimit,
-			RolloutRestartTime: bc.GetAnnotations()[resources.FanoutRestartTimeAnnotationKey],
-			AuthType:           authType,
+			ComponentName:              resources.FanoutName,
+			BrokerCell:                 bc,
+			Image:                      r.env.FanoutImage,
+			ServiceAccountName:         r.env.ServiceAccountName,
+			MetricsPort:                r.env.MetricsPort,
+			AllowIstioSidecar:          true,
+			CPURequest:                 bc.Spec.Components.Fanout.CPURequest,
+			CPULimit:                   bc.Spec.Components.Fanout.CPULimit,
+			MemoryRequest:              bc.Spec.Components.Fanout.MemoryRequest,
+			MemoryLimit:                bc.Spec.Components.Fanout.MemoryLimit,
+			RolloutRestartTime:         bc.GetAnnotations()[resources.FanoutRestartTimeAnnotationKey],
+			AuthType:                   authType,
+			TargetsConfigServerAddress: r.targetsConfigServerAddress(),
 		},
 	}
 }
@@ -349,18 +420,19 @@ func (r *Reconciler) makeFanoutHPAArgs(bc *intv1alpha1.BrokerCell) resources.Aut
 func (r *Reconciler) makeRetryArgs(bc *intv1alpha1.BrokerCell, authType authcheck.AuthType) resources.RetryArgs {
 	return resources.RetryArgs{
 		Args: resources.Args{
-			ComponentName:      resources.RetryName,
-			BrokerCell:         bc,
-			Image:              r.env.RetryImage,
-			ServiceAccountName: r.env.ServiceAccountName,
-			MetricsPort:        r.env.MetricsPort,
-			AllowIstioSidecar:  true,
-			CPURequest:         bc.Spec.Components.Retry.CPURequest,
-			CPULimit:           bc.Spec.Components.Retry.CPULimit,
-			MemoryRequest:      bc.Spec.Components.Retry.MemoryRequest,
-			MemoryLimit:        bc.Spec.Components.Retry.MemoryLimit,
-			RolloutRestartTime: bc.GetAnnotations()[resources.RetryRestartTimeAnnotationKey],
-			AuthType:           authType,
+			ComponentName:              resources.RetryName,
+			BrokerCell:                 bc,
+			Image:                      r.env.RetryImage,
+			ServiceAccountName:         r.env.ServiceAccountName,
+			MetricsPort:                r.env.MetricsPort,
+			AllowIstioSidecar:          true,
+			CPURequest:                 bc.Spec.Components.Retry.CPURequest,
+			CPULimit:                   bc.Spec.Components.Retry.CPULimit,
+			MemoryRequest:              bc.Spec.Components.Retry.MemoryRequest,
+			MemoryLimit:                bc.Spec.Components.Retry.MemoryLimit,
+			RolloutRestartTime:         bc.GetAnnotations()[resources.RetryRestartTimeAnnotationKey],
+			AuthType:                   authType,
+			TargetsConfigServerAddress: r.targetsConfigServerAddress(),
 		},
 	}
 }
@@ -26,16 +26,23 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/resolver"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/proto"
 
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	bcreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
@@ -63,9 +70,9 @@ var (
 		"events.cloud.google.com/fanoutRestartRequestedAt":  "2020-09-25T16:28:36-04:00",
 		"events.cloud.google.com/retryRestartRequestedAt":   "2020-09-25T16:28:36-04:00",
 	}
-	// TODO(1804): remove this variable when feature is enabled by default.
-	enableIngressFilteringAnnotation = map[string]string{
-		"events.cloud.google.com/ingressFilteringEnabled": "true",
+	// TODO(1804): remove this variable when the feature can no longer be disabled.
+	disableIngressFilteringAnnotation = map[string]string{
+		"events.cloud.google.com/ingressFilteringEnabled": "false",
 	}
 
 	brokerCellReconciledEvent     = Eventf(corev1.EventTypeNormal, "BrokerCellReconciled", `BrokerCell reconciled: "testnamespace/test-brokercell"`)
@@ -97,6 +104,16 @@ var (
 	configmapCreatedEvent         = Eventf(corev1.EventTypeNormal, "ConfigMapCreated", "Created configmap testnamespace/test-brokercell-brokercell-broker-targets")
 	configmapUpdatedEvent         = Eventf(corev1.EventTypeNormal, "ConfigMapUpdated", "Updated configmap testnamespace/test-brokercell-brokercell-broker-targets")
 	authTypeEvent                 = Eventf(corev1.EventTypeWarning, "InternalError", "authentication is not configured, when checking Kubernetes Service Account broker, got error: can't find Kubernetes Service Account broker, when checking Kubernetes Secret google-broker-key, got error: can't find Kubernetes Secret google-broker-key")
+
+	backoffPolicy              = eventingduckv1beta1.BackoffPolicyExponential
+	backoffDelay               = "PT1S"
+	deadLetterSinkDeliverySpec = &eventingduckv1beta1.DeliverySpec{
+		BackoffPolicy: &backoffPolicy,
+		BackoffDelay:  &backoffDelay,
+		DeadLetterSink: &duckv1.Destination{
+			URI: &apis.URL{Scheme: "http", Host: "dead-letter.example.com"},
+		},
+	}
 )
 
 func init() {
@@ -149,7 +166,7 @@ func TestAllCases(t *testing.T) {
 			Objects: []runtime.Object{
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
-				NewBroker("broker", testNS, WithBrokerSetDefaults),
+				NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName)),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{InduceFailure("update", "configmaps")},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
@@ -162,7 +179,7 @@ func TestAllCases(t *testing.T) {
 			WantEvents: []string{configmapUpdateFailedEvent},
 			WantUpdates: []clientgotesting.UpdateActionImpl{{Object: testingdata.Config(t,
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
-				NewBroker("broker", testNS, WithBrokerSetDefaults))}},
+				NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName)))}},
 			WantErr: true,
 		},
 		{
@@ -710,7 +727,7 @@ func TestAllCases(t *testing.T) {
 			Objects: []runtime.Object{
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
-				NewBroker("broker", testNS, WithBrokerSetDefaults),
+				NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName)),
 				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
 				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS),
 				NewDeployment(brokerCellName+"-brokercell-ingress", testNS,
@@ -743,7 +760,7 @@ func TestAllCases(t *testing.T) {
 			WantUpdates: []clientgotesting.UpdateActionImpl{
 				{Object: testingdata.Config(t,
 					NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
-					NewBroker("broker", testNS, WithBrokerSetDefaults))},
+					NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName)))},
 				{Object: testingdata.IngressDeployment(t)},
 				{Object: testingdata.IngressHPA(t)},
 				{Object: testingdata.IngressService(t)},
@@ -837,16 +854,16 @@ func TestAllCases(t *testing.T) {
 			},
 		},
 		{
-			// TODO(1804): remove this test case when the feature is enabled by default.
-			Name: "BrokerCell with ingress filtering created successfully",
+			// TODO(1804): remove this test case when the feature can no longer be disabled.
+			Name: "BrokerCell with ingress filtering disabled created successfully",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults,
-					WithBrokerCellAnnotations(enableIngressFilteringAnnotation)),
+					WithBrokerCellAnnotations(disableIngressFilteringAnnotation)),
 				testingdata.EmptyConfig(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)),
 				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
 					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
-				testingdata.IngressDeploymentWithFilteringAnnotation(t),
+				testingdata.IngressDeploymentWithFilteringDisabled(t),
 				testingdata.IngressServiceWithStatus(t),
 				testingdata.FanoutDeploymentWithStatus(t),
 				testingdata.RetryDeploymentWithStatus(t),
@@ -857,7 +874,7 @@ func TestAllCases(t *testing.T) {
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
 				{Object: NewBrokerCell(brokerCellName, testNS,
 					WithBrokerCellReady,
-					WithBrokerCellAnnotations(enableIngressFilteringAnnotation),
+					WithBrokerCellAnnotations(disableIngressFilteringAnnotation),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
 					WithBrokerCellSetDefaults,
 				)},
@@ -874,8 +891,8 @@ func TestAllCases(t *testing.T) {
 					WithBrokerCellAnnotations(creatorAnnotation),
 					WithBrokerCellSetDefaults),
 				testingdata.Config(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
-					NewBroker("broker", testNS, WithBrokerSetDefaults)),
-				NewBroker("broker", testNS, WithBrokerSetDefaults),
+					NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName))),
+				NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName)),
 				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
 					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
 				testingdata.IngressDeploymentWithStatus(t),
@@ -986,6 +1003,7 @@ func TestAllCases(t *testing.T) {
 			brokerLister:         testingListers.GetBrokerLister(),
 			hpaLister:            testingListers.GetHPALister(),
 			triggerLister:        testingListers.GetTriggerLister(),
+			channelLister:        testingListers.GetChannelLister(),
 			configMapLister:      testingListers.GetConfigMapLister(),
 			secretLister:         testingListers.GetSecretLister(),
 			serviceAccountLister: testingListers.GetServiceAccountLister(),
@@ -1012,15 +1030,81 @@ func emptyHPASpec(template *hpav2beta2.HorizontalPodAutoscaler) *hpav2beta2.Hori
 // and trigger. Since the serialization order of the binary data of brokerTargets in the configMap is not guaranteed, we need
 // to deserialization the binary data to a brokerTargets proto to compare, so it should be rewritten without using the tableTest Utility.
 func TestBrokerTargetsReconcileConfig(t *testing.T) {
+	setReconcilerEnv()
+	triggerFilters := []brokerv1beta1.SubscriptionsAPIFilter{{
+		Prefix: map[string]string{"type": "com.example."},
+	}, {
+		Not: &brokerv1beta1.SubscriptionsAPIFilter{SQL: "priority > 5"},
+	}}
+	bc := NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)
+	objects := []runtime.Object{
+		bc,
+		NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName), WithBrokerDeliveryAudience("broker-audience"), WithBrokerIngressPolicy("ns/publisher", "broker-ingress")),
+		NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults, WithTriggerRateLimit("5", "100")),
+		NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults, WithTriggerFilters(triggerFilters...), WithTriggerBatchPolicy("10", "1s"), WithTriggerCircuitBreaker("3", "1m"), WithTriggerDeliveryAudience("trigger-audience")),
+	}
+	ctx, _ := SetupFakeContext(t)
+	cmw := configmap.NewStaticWatcher()
+	ctx, client := fakekubeclient.With(ctx)
+	base := reconciler.NewBase(ctx, controllerAgentName, cmw)
+	testingListers := NewListers(objects)
+	ls := listers{
+		brokerLister:     testingListers.GetBrokerLister(),
+		hpaLister:        testingListers.GetHPALister(),
+		triggerLister:    testingListers.GetTriggerLister(),
+		channelLister:    testingListers.GetChannelLister(),
+		configMapLister:  testingListers.GetConfigMapLister(),
+		serviceLister:    testingListers.GetK8sServiceLister(),
+		endpointsLister:  testingListers.GetEndpointsLister(),
+		deploymentLister: testingListers.GetDeploymentLister(),
+		podLister:        testingListers.GetPodLister(),
+	}
+	r, err := NewReconciler(base, ls)
+	if err != nil {
+		t.Fatalf("Failed to create BrokerCell reconciler: %v", err)
+	}
+	// here we only want to test the functionality of the reconcileConfig that it should create a brokerTargets config successfully
+	r.reconcileConfig(ctx, bc)
+	wantMap := testingdata.Config(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
+		NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName), WithBrokerDeliveryAudience("broker-audience"), WithBrokerIngressPolicy("ns/publisher", "broker-ingress")),
+		NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults, WithTriggerRateLimit("5", "100")),
+		NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults, WithTriggerFilters(triggerFilters...), WithTriggerBatchPolicy("10", "1s"), WithTriggerCircuitBreaker("3", "1m"), WithTriggerDeliveryAudience("trigger-audience")))
+	gotMap, err := client.CoreV1().ConfigMaps(testNS).Get(context.Background(), resources.Name(bc.Name, targetsCMName), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get ConfigMap from client: %v", err)
+	}
+	// compare the ObjectMeta field
+	if diff := cmp.Diff(wantMap.ObjectMeta, gotMap.ObjectMeta); diff != "" {
+		t.Fatalf("Unexpected ObjectMeta in ConfigMap(-want, +got): %s", diff)
+	}
+	// deserialize the binary data to a broker targets config proto
+	var wantBrokerTargets config.TargetsConfig
+	var gotBrokerTargets config.TargetsConfig
+	if err := proto.Unmarshal(wantMap.BinaryData[targetsCMKey], &wantBrokerTargets); err != nil {
+		t.Fatalf("Failed to deserialize the binary data in ConfigMap: %v", err)
+	}
+	if err := proto.Unmarshal(gotMap.BinaryData[targetsCMKey], &gotBrokerTargets); err != nil {
+		t.Fatalf("Failed to deserialize the binary data in ConfigMap: %v", err)
+	}
+	// compare the broker targets config
+	if diff := cmp.Diff(wantBrokerTargets.String(), gotBrokerTargets.String()); diff != "" {
+		t.Fatalf("Unexpected brokerTargets in ConfigMap(-want, +got): %s", diff)
+	}
+}
+
+// The unit test to test that triggers of a broker whose dead letter sink is not a pubsub topic have their
+// dead letter queue and dead letter sink address in the broker targets config.
+func TestBrokerTargetsReconcileConfigWithDeadLetterSink(t *testing.T) {
 	setReconcilerEnv()
 	bc := NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)
 	objects := []runtime.Object{
 		bc,
-		NewBroker("broker", testNS, WithBrokerSetDefaults),
+		NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName), WithBrokerDeliverySpec(deadLetterSinkDeliverySpec)),
 		NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
 		NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults),
 	}
 	ctx, _ := SetupFakeContext(t)
+	ctx = addressable.WithDuck(ctx)
 	cmw := configmap.NewStaticWatcher()
 	ctx, client := fakekubeclient.With(ctx)
 	base := reconciler.NewBase(ctx, controllerAgentName, cmw)
@@ -1029,6 +1113,7 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 		brokerLister:     testingListers.GetBrokerLister(),
 		hpaLister:        testingListers.GetHPALister(),
 		triggerLister:    testingListers.GetTriggerLister(),
+		channelLister:    testingListers.GetChannelLister(),
 		configMapLister:  testingListers.GetConfigMapLister(),
 		serviceLister:    testingListers.GetK8sServiceLister(),
 		endpointsLister:  testingListers.GetEndpointsLister(),
@@ -1039,10 +1124,11 @@ func TestBrokerTargetsReconcileConfig(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create BrokerCell reconciler: %v", err)
 	}
-	// here we only want to test the functionality of the reconcileConfig that it should create a brokerTargets config successfully
+	r.uriResolver = resolver.NewURIResolver(ctx, func(types.NamespacedName) {})
+	// the targets of the broker should have a dead letter queue and the resolved dead letter sink address
 	r.reconcileConfig(ctx, bc)
 	wantMap := testingdata.Config(t, NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults),
-		NewBroker("broker", testNS, WithBrokerSetDefaults),
+		NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName), WithBrokerDeliverySpec(deadLetterSinkDeliverySpec)),
 		NewTrigger("trigger1", testNS, "broker", WithTriggerSetDefaults),
 		NewTrigger("trigger2", testNS, "broker", WithTriggerSetDefaults))
 	gotMap, err := client.CoreV1().ConfigMaps(testNS).Get(context.Background(), resources.Name(bc.Name, targetsCMName), metav1.GetOptions{})
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	messagingv1beta1 "github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/logging"
+	channelresources "github.com/google/knative-gcp/pkg/reconciler/messaging/channel/resources"
+)
+
+// addChannelsToConfig adds all the channels served by the brokercell to the
+// targets config.
+func (r *Reconciler) addChannelsToConfig(ctx context.Context, bc *intv1alpha1.BrokerCell, brokerTargets config.Targets) error {
+	channels, err := r.channelLister.List(labels.Everything())
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list channels", zap.Error(err))
+		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to list channels: %v", err)
+		return err
+	}
+	for _, c := range channels {
+		if c.BrokerCell() == bc.Name {
+			addChannelToConfig(bc, c, brokerTargets)
+		}
+	}
+	return nil
+}
+
+// regenerateChannel regenerates the entry of the given channel in the targets
+// config. Channels which no longer exist or are not served by the brokercell
+// are removed from the targets config.
+func (r *Reconciler) regenerateChannel(ctx context.Context, bc *intv1alpha1.BrokerCell, brokerTargets config.Targets, key types.NamespacedName) error {
+	c, err := r.channelLister.Channels(key.Namespace).Get(key.Name)
+	if err != nil && !apierrs.IsNotFound(err) {
+		logging.FromContext(ctx).Error("Failed to get channel", zap.String("Channel", key.String()), zap.Error(err))
+		bc.Status.MarkTargetsConfigFailed(configFailed, "failed to get channel %v: %v", key, err)
+		return err
+	}
+	if apierrs.IsNotFound(err) || c.BrokerCell() != bc.Name {
+		deleted := &messagingv1beta1.Channel{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		brokerTargets.MutateCellTenant(config.KeyFromChannel(deleted), func(m config.CellTenantMutation) {
+			m.Delete()
+		})
+		return nil
+	}
+	addChannelToConfig(bc, c, brokerTargets)
+	return nil
+}
+
+// addChannelToConfig reconstructs the entry of the given channel and its
+// subscribers in the targets config.
+func addChannelToConfig(bc *intv1alpha1.BrokerCell, c *messagingv1beta1.Channel, brokerTargets config.Targets) {
+	brokerTargets.MutateCellTenant(config.KeyFromChannel(c), func(m config.CellTenantMutation) {
+		m.Delete()
+
+		queueState := config.State_UNKNOWN
+		// PubSub drops the messages published to a topic without
+		// subscription, so the decouple queue is only ready when both are.
+		if c.Status.GetCondition(messagingv1beta1.ChannelConditionTopicReady).IsTrue() && c.Status.GetCondition(messagingv1beta1.ChannelConditionSubscriptionReady).IsTrue() {
+			queueState = config.State_READY
+		}
+		m.SetID(string(c.UID))
+		m.SetAddress(channelresources.BrokerCellAddress(bc.Name, c).String())
+		m.SetDecoupleQueue(&config.Queue{
+			Topic:        channelresources.GenerateTopicID(c),
+			Subscription: channelresources.GenerateDecouplingSubscriptionName(c),
+			State:        queueState,
+		})
+		if c.Status.IsReady() {
+			m.SetState(config.State_READY)
+		} else {
+			m.SetState(config.State_UNKNOWN)
+		}
+
+		if c.Spec.SubscribableSpec == nil {
+			return
+		}
+		ready := make(map[types.UID]bool)
+		for _, s := range c.Status.SubscribableStatus.Subscribers {
+			ready[s.UID] = s.Ready == corev1.ConditionTrue
+		}
+		for _, s := range c.Spec.SubscribableSpec.Subscribers {
+			target := &config.Target{
+				Id:             string(s.UID),
+				Name:           string(s.UID),
+				Namespace:      c.Namespace,
+				CellTenantType: config.CellTenantType_CHANNEL,
+				CellTenantName: c.Name,
+				RetryQueue: &config.Queue{
+					Topic:        channelresources.GenerateRetryTopicName(c, s.UID),
+					Subscription: channelresources.GenerateRetrySubscriptionName(c, s.UID),
+				},
+				State: config.State_UNKNOWN,
+			}
+			if s.SubscriberURI != nil {
+				target.Address = s.SubscriberURI.String()
+			}
+			if s.ReplyURI != nil {
+				target.ReplyAddress = s.ReplyURI.String()
+			}
+			if channelresources.HasManagedDeadLetterQueue(s.Delivery) {
+				target.DeadLetterAddress = s.Delivery.DeadLetterSink.URI.String()
+				target.DeadLetterQueue = &config.Queue{
+					Topic:        channelresources.GenerateDeadLetterTopicName(c, s.UID),
+					Subscription: channelresources.GenerateDeadLetterSubscriptionName(c, s.UID),
+				}
+			}
+			if ready[s.UID] {
+				target.State = config.State_READY
+			}
+			m.UpsertTargets(target)
+		}
+	})
+}
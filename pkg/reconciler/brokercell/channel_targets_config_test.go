@@ -0,0 +1,156 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+func TestAddChannelToConfig(t *testing.T) {
+	bc := NewBrokerCell(brokerCellName, testNS)
+	channel := NewChannel("channel", testNS,
+		WithChannelUID("channel-uid"),
+		WithChannelBrokerCell(brokerCellName),
+		WithChannelSubscribers([]eventingduckv1beta1.SubscriberSpec{{
+			UID:           "sub1-uid",
+			SubscriberURI: apis.HTTP("sub1.example.com"),
+			ReplyURI:      apis.HTTP("reply.example.com"),
+		}, {
+			UID:           "sub2-uid",
+			SubscriberURI: apis.HTTP("sub2.example.com"),
+			Delivery: &eventingduckv1beta1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dls.example.com")},
+			},
+		}}),
+		WithInitChannelBrokerCellConditions,
+		WithChannelTopic("topic"),
+		WithChannelSubscriptionReady,
+		WithChannelSubscribersStatus([]eventingduckv1beta1.SubscriberStatus{{
+			UID:   "sub1-uid",
+			Ready: corev1.ConditionTrue,
+		}}),
+	)
+
+	want := &config.CellTenant{
+		Id:        "channel-uid",
+		Type:      config.CellTenantType_CHANNEL,
+		Namespace: testNS,
+		Name:      "channel",
+		Address:   "http://test-brokercell-brokercell-ingress.knative-testing.svc.cluster.local/channel/testnamespace/channel",
+		DecoupleQueue: &config.Queue{
+			Topic:        "cre-chan_testnamespace_channel_channel-uid",
+			Subscription: "cre-chan_testnamespace_channel_channel-uid",
+			State:        config.State_READY,
+		},
+		State: config.State_UNKNOWN,
+		Targets: map[string]*config.Target{
+			"sub1-uid": {
+				Id:             "sub1-uid",
+				Name:           "sub1-uid",
+				Namespace:      testNS,
+				CellTenantType: config.CellTenantType_CHANNEL,
+				CellTenantName: "channel",
+				Address:        "http://sub1.example.com",
+				ReplyAddress:   "http://reply.example.com",
+				RetryQueue: &config.Queue{
+					Topic:        "cre-chsub_testnamespace_channel_sub1-uid",
+					Subscription: "cre-chsub_testnamespace_channel_sub1-uid",
+				},
+				State: config.State_READY,
+			},
+			"sub2-uid": {
+				Id:             "sub2-uid",
+				Name:           "sub2-uid",
+				Namespace:      testNS,
+				CellTenantType: config.CellTenantType_CHANNEL,
+				CellTenantName: "channel",
+				Address:        "http://sub2.example.com",
+				RetryQueue: &config.Queue{
+					Topic:        "cre-chsub_testnamespace_channel_sub2-uid",
+					Subscription: "cre-chsub_testnamespace_channel_sub2-uid",
+				},
+				DeadLetterAddress: "http://dls.example.com",
+				DeadLetterQueue: &config.Queue{
+					Topic:        "cre-chdlq_testnamespace_channel_sub2-uid",
+					Subscription: "cre-chdlq_testnamespace_channel_sub2-uid",
+				},
+				State: config.State_UNKNOWN,
+			},
+		},
+	}
+
+	targets := memory.NewEmptyTargets()
+	addChannelToConfig(bc, channel, targets)
+	got, ok := targets.GetCellTenantByKey(config.KeyFromChannel(channel))
+	if !ok {
+		t.Fatalf("Channel %v is not in the targets config", config.KeyFromChannel(channel))
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("Unexpected channel in the targets config (-want, +got): %s", diff)
+	}
+}
+
+func TestRegenerateChannel(t *testing.T) {
+	bc := NewBrokerCell(brokerCellName, testNS)
+	key := types.NamespacedName{Namespace: testNS, Name: "channel"}
+	channel := NewChannel("channel", testNS, WithChannelBrokerCell(brokerCellName))
+	movedChannel := NewChannel("channel", testNS, WithChannelBrokerCell("other"))
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    bool
+	}{{
+		name:    "channel served by the brokercell",
+		objects: []runtime.Object{channel},
+		want:    true,
+	}, {
+		name:    "channel moved to another brokercell",
+		objects: []runtime.Object{movedChannel},
+	}, {
+		name: "deleted channel",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			listers := NewListers(tc.objects)
+			r := &Reconciler{}
+			r.channelLister = listers.GetChannelLister()
+			targets := memory.NewEmptyTargets()
+			addChannelToConfig(bc, channel, targets)
+			if err := r.regenerateChannel(context.Background(), bc, targets, key); err != nil {
+				t.Fatalf("regenerateChannel() = %v", err)
+			}
+			if _, got := targets.GetCellTenantByKey(config.KeyFromChannel(channel)); got != tc.want {
+				t.Errorf("Channel in the targets config = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
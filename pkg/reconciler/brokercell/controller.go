@@ -25,9 +25,15 @@ import (
 	"go.uber.org/zap"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	messagingv1beta1 "github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
+	"github.com/google/knative-gcp/pkg/broker/tokenreview"
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
 	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
 	brokercellinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell"
+	channelinformer "github.com/google/knative-gcp/pkg/client/injection/informers/messaging/v1beta1/channel"
 	hpainformer "github.com/google/knative-gcp/pkg/client/injection/kube/informers/autoscaling/v2beta2/horizontalpodautoscaler"
 	v1alpha1brokercell "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
 	"github.com/google/knative-gcp/pkg/logging"
@@ -52,6 +58,7 @@ import (
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
 	systemnamespacesecretinformer "knative.dev/pkg/injection/clients/namespacedkube/informers/core/v1/secret"
+	"knative.dev/pkg/resolver"
 	"knative.dev/pkg/system"
 )
 
@@ -59,6 +66,10 @@ const (
 	// controllerAgentName is the string used by this controller to identify
 	// itself when creating events.
 	controllerAgentName = "brokercell-controller"
+
+	// targetsConfigBatchDelay is how long the changes to brokers and triggers
+	// are batched before the targets config of their brokercell is updated.
+	targetsConfigBatchDelay = time.Second
 )
 
 type Constructor injection.ControllerConstructor
@@ -83,6 +94,7 @@ func NewController(
 		brokerLister:         brokerinformer.Get(ctx).Lister(),
 		hpaLister:            hpainformer.Get(ctx).Lister(),
 		triggerLister:        triggerinformer.Get(ctx).Lister(),
+		channelLister:        channelinformer.Get(ctx).Lister(),
 		configMapLister:      configmapinformer.Get(ctx).Lister(),
 		secretLister:         systemnamespacesecretinformer.Get(ctx).Lister(),
 		serviceAccountLister: serviceaccountinformer.Get(ctx).Lister(),
@@ -98,6 +110,26 @@ func NewController(
 		logger.Fatal("Failed to create BrokerCell reconciler", zap.Error(err))
 	}
 	impl := v1alpha1brokercell.NewImpl(ctx, r)
+	if r.env.TargetsConfigServerPort > 0 && !r.env.TargetsConfigServerInsecure {
+		logger.Error("The targets config server is disabled as it doesn't use TLS, set BROKER_CELL_TARGETS_CONFIG_SERVER_INSECURE to enable it")
+	} else if r.env.TargetsConfigServerPort > 0 {
+		r.targetsServer = stream.NewServer(stream.WithServiceAccountAuth(tokenreview.NewReviewer(r.KubeClientSet), r.env.ServiceAccountName))
+		go func() {
+			if err := r.targetsServer.Serve(ctx, r.env.TargetsConfigServerPort); err != nil {
+				logger.Error("Failed to serve targets config", zap.Error(err))
+			}
+		}()
+	}
+	r.uriResolver = resolver.NewURIResolver(ctx, func(key types.NamespacedName) {
+		// The dead letter sink of the trigger changed.
+		t, err := ls.triggerLister.Triggers(key.Namespace).Get(key.Name)
+		if err != nil {
+			return
+		}
+		if b, err := ls.brokerLister.Brokers(t.Namespace).Get(t.Spec.Broker); err == nil {
+			enqueueBrokerCells(impl, r.targetsCache, b)
+		}
+	})
 
 	var latencyReporter *metrics.BrokerCellLatencyReporter
 	if r.env.InternalMetricsEnabled {
@@ -105,33 +137,92 @@ func NewController(
 		if err != nil {
 			logger.Error("Failed to create latency reporter", zap.Error(err))
 		}
+		r.targetsConfigReporter, err = metrics.NewTargetsConfigReporter()
+		if err != nil {
+			logger.Error("Failed to create targets config reporter", zap.Error(err))
+		}
 	}
 
 	logger.Info("Setting up event handlers.")
 
 	brokerCellInformer.Informer().AddEventHandlerWithResyncPeriod(controller.HandleAll(impl.Enqueue), reconciler.DefaultResyncPeriod)
+	brokerCellInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if bc, ok := obj.(*intv1alpha1.BrokerCell); ok {
+				r.forget(types.NamespacedName{Namespace: bc.Namespace, Name: bc.Name})
+			}
+		},
+	})
 	brokerCellLister := brokerCellInformer.Lister()
 
 	// Watch brokers and triggers to invoke configmap update immediately.
-	brokerinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
-		func(obj interface{}) {
+	brokerinformer.Get(ctx).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
 			if b, ok := obj.(*brokerv1beta1.Broker); ok {
-				// TODO(#866) Select the brokercell that's associated with the given broker.
-				impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
+				enqueueBrokerCells(impl, r.targetsCache, b)
 				reportLatency(ctx, b, latencyReporter, "Broker", b.Name, b.Namespace)
 			}
 		},
-	))
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldBroker, ok := oldObj.(*brokerv1beta1.Broker)
+			if !ok {
+				return
+			}
+			if b, ok := newObj.(*brokerv1beta1.Broker); ok {
+				// The broker may have moved to another brokercell.
+				enqueueBrokerCells(impl, r.targetsCache, oldBroker, b)
+				reportLatency(ctx, b, latencyReporter, "Broker", b.Name, b.Namespace)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if b, ok := obj.(*brokerv1beta1.Broker); ok {
+				enqueueBrokerCells(impl, r.targetsCache, b)
+				reportLatency(ctx, b, latencyReporter, "Broker", b.Name, b.Namespace)
+			}
+		},
+	})
 	triggerinformer.Get(ctx).Informer().AddEventHandler(controller.HandleAll(
 		func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
 			if t, ok := obj.(*brokerv1beta1.Trigger); ok {
-				// TODO(#866) Select the brokercell that's associated with the given broker.
-				impl.EnqueueKey(types.NamespacedName{Namespace: system.Namespace(), Name: brokerresources.DefaultBrokerCellName})
+				// Triggers of brokers which don't exist are not in any targets config.
+				if b, err := ls.brokerLister.Brokers(t.Namespace).Get(t.Spec.Broker); err == nil {
+					enqueueBrokerCells(impl, r.targetsCache, b)
+				}
 				reportLatency(ctx, t, latencyReporter, "Trigger", t.Name, t.Namespace)
 			}
 		},
 	))
 
+	channelinformer.Get(ctx).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c, ok := obj.(*messagingv1beta1.Channel); ok {
+				enqueueChannelBrokerCell(impl, r.targetsCache, c)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if c, ok := newObj.(*messagingv1beta1.Channel); ok {
+				enqueueChannelBrokerCell(impl, r.targetsCache, c)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if c, ok := obj.(*messagingv1beta1.Channel); ok {
+				enqueueChannelBrokerCell(impl, r.targetsCache, c)
+			}
+		},
+	})
+
 	// Watch data plane components created by brokercell so we can update brokercell status immediately.
 	// 1. Watch deployments for ingress, fanout and retry
 	deploymentinformer.Get(ctx).Informer().AddEventHandler(handleResourceUpdate(impl))
@@ -158,6 +249,32 @@ func NewController(
 	return impl
 }
 
+// enqueueBrokerCells marks the brokers dirty in the brokercells they are
+// assigned to and enqueues these brokercells after targetsConfigBatchDelay, so
+// that the changes to many brokers and triggers result in a single update of
+// the targets config.
+func enqueueBrokerCells(impl *controller.Impl, targetsCache *targetsCache, brokers ...*brokerv1beta1.Broker) {
+	for _, b := range brokers {
+		for _, name := range brokerresources.BrokerCellNames(b) {
+			bc := types.NamespacedName{Namespace: system.Namespace(), Name: name}
+			targetsCache.markDirty(bc, *config.KeyFromBroker(b))
+			impl.EnqueueKeyAfter(bc, targetsConfigBatchDelay)
+		}
+	}
+}
+
+// enqueueChannelBrokerCell marks the channel dirty in the brokercell serving it
+// and enqueues the brokercell after targetsConfigBatchDelay. Channels without
+// brokercell are served by their own PullSubscriptions.
+func enqueueChannelBrokerCell(impl *controller.Impl, targetsCache *targetsCache, c *messagingv1beta1.Channel) {
+	if c.BrokerCell() == "" {
+		return
+	}
+	bc := types.NamespacedName{Namespace: system.Namespace(), Name: c.BrokerCell()}
+	targetsCache.markDirty(bc, *config.KeyFromChannel(c))
+	impl.EnqueueKeyAfter(bc, targetsConfigBatchDelay)
+}
+
 // handleResourceUpdate returns an event handler for resources created by brokercell such as the ingress deployment.
 func handleResourceUpdate(impl *controller.Impl) cache.ResourceEventHandler {
 	// Since resources created by brokercell live in the same namespace as the brokercell, we use an
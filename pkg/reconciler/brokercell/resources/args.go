@@ -40,8 +40,9 @@ const (
 	FanoutRestartTimeAnnotationKey  = "events.cloud.google.com/fanoutRestartRequestedAt"
 	RetryRestartTimeAnnotationKey   = "events.cloud.google.com/retryRestartRequestedAt"
 	RolloutRestartTimeAnnotationKey = "events.cloud.google.com/RestartRequestedAt"
-	// IngressFilteringEnabledAnnotationKey is the annotation key for enabling ingress filtering.
-	// TODO(#1804): remove this constant when enabling the feature by default.
+	// IngressFilteringEnabledAnnotationKey is the annotation key for disabling ingress filtering,
+	// which is enabled by default.
+	// TODO(#1804): remove this constant when the feature can no longer be disabled.
 	IngressFilteringEnabledAnnotationKey = "events.cloud.google.com/ingressFilteringEnabled"
 )
 
@@ -63,13 +64,17 @@ type Args struct {
 	MemoryLimit        string
 	RolloutRestartTime string
 	AuthType           authcheck.AuthType
+	// TargetsConfigServerAddress is the address of the server streaming the
+	// targets config. Empty if the targets config is read from the mounted
+	// ConfigMap.
+	TargetsConfigServerAddress string
 }
 
 // IngressArgs are the arguments to create a Broker's ingress Deployment.
 type IngressArgs struct {
 	Args
 	Port int
-	// TODO(#1804): remove this field when enabling the feature by default.
+	// TODO(#1804): remove this field when the feature can no longer be disabled.
 	EnableIngressFilter bool
 }
 
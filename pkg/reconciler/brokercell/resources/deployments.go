@@ -17,8 +17,10 @@ limitations under the License.
 package resources
 
 import (
+	"path"
 	"strconv"
 
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
 	"github.com/google/knative-gcp/pkg/broker/handler"
 	resourceutil "github.com/google/knative-gcp/pkg/utils/resource"
 	appsv1 "k8s.io/api/apps/v1"
@@ -30,14 +32,24 @@ import (
 	"knative.dev/pkg/system"
 )
 
+const (
+	targetsConfigTokenVolume    = "targets-config-token"
+	targetsConfigTokenMountPath = "/var/run/secrets/events.cloud.google.com/targets-config"
+	targetsConfigTokenFile      = "token"
+	// targetsConfigTokenExpiration is how long the projected token
+	// authenticating to the targets config server is valid. The kubelet
+	// rotates it before it expires.
+	targetsConfigTokenExpiration = 3600
+)
+
 // MakeIngressDeployment creates the ingress Deployment object.
 func MakeIngressDeployment(args IngressArgs) *appsv1.Deployment {
 	container := containerTemplate(args.Args)
 	// Decorate the container template with ingress port.
 	container.Env = append(container.Env, corev1.EnvVar{Name: "PORT", Value: strconv.Itoa(args.Port)})
 
-	// TODO(#1804): remove this env variable when enabling the feature by default.
-	// Enable ingress filtering if necessary.
+	// TODO(#1804): remove this env variable when the feature can no longer be disabled.
+	// Disable ingress filtering if necessary.
 	container.Env = append(container.Env, corev1.EnvVar{
 		Name:  "ENABLE_INGRESS_EVENT_FILTERING",
 		Value: strconv.FormatBool(args.EnableIngressFilter),
@@ -141,6 +153,32 @@ func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deploy
 	if args.RolloutRestartTime != "" {
 		annotation[RolloutRestartTimeAnnotationKey] = args.RolloutRestartTime
 	}
+	volumes := []corev1.Volume{
+		{
+			Name:         "broker-config",
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: Name(args.BrokerCell.Name, targetsCMName)}}},
+		},
+		{
+			Name:         "google-broker-key",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "google-broker-key", Optional: &optionalSecretVolume}},
+		},
+	}
+	if args.TargetsConfigServerAddress != "" {
+		// The data plane authenticates to the targets config server with a
+		// token of its service account.
+		volumes = append(volumes, corev1.Volume{
+			Name: targetsConfigTokenVolume,
+			VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          stream.TokenAudience,
+						ExpirationSeconds: ptr.Int64(targetsConfigTokenExpiration),
+						Path:              targetsConfigTokenFile,
+					},
+				}},
+			}},
+		})
+	}
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       args.BrokerCell.Namespace,
@@ -163,17 +201,8 @@ func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deploy
 					Annotations: annotation,
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: args.ServiceAccountName,
-					Volumes: []corev1.Volume{
-						{
-							Name:         "broker-config",
-							VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: Name(args.BrokerCell.Name, targetsCMName)}}},
-						},
-						{
-							Name:         "google-broker-key",
-							VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "google-broker-key", Optional: &optionalSecretVolume}},
-						},
-					},
+					ServiceAccountName:            args.ServiceAccountName,
+					Volumes:                       volumes,
 					Containers:                    containers,
 					TerminationGracePeriodSeconds: ptr.Int64(60),
 				},
@@ -184,7 +213,7 @@ func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deploy
 
 // containerTemplate returns a common template for broker data plane containers.
 func containerTemplate(args Args) corev1.Container {
-	return corev1.Container{
+	container := corev1.Container{
 		Image: args.Image,
 		Name:  args.ComponentName,
 		Env: []corev1.EnvVar{
@@ -238,4 +267,18 @@ func containerTemplate(args Args) corev1.Container {
 			},
 		},
 	}
+	if args.TargetsConfigServerAddress != "" {
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "TARGETS_CONFIG_SERVER_ADDRESS", Value: args.TargetsConfigServerAddress},
+			corev1.EnvVar{Name: "BROKER_CELL_NAMESPACE", Value: args.BrokerCell.Namespace},
+			corev1.EnvVar{Name: "BROKER_CELL_NAME", Value: args.BrokerCell.Name},
+			corev1.EnvVar{Name: "TARGETS_CONFIG_TOKEN_PATH", Value: path.Join(targetsConfigTokenMountPath, targetsConfigTokenFile)},
+		)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      targetsConfigTokenVolume,
+			MountPath: targetsConfigTokenMountPath,
+			ReadOnly:  true,
+		})
+	}
+	return container
 }
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestMakeTargetFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []brokerv1beta1.SubscriptionsAPIFilter
+		want    []*config.Filter
+	}{{
+		name: "no filters",
+	}, {
+		name: "nested filters",
+		filters: []brokerv1beta1.SubscriptionsAPIFilter{{
+			Exact: map[string]string{"type": "foo"},
+		}, {
+			Any: []brokerv1beta1.SubscriptionsAPIFilter{{
+				Prefix: map[string]string{"source": "bar"},
+			}, {
+				Not: &brokerv1beta1.SubscriptionsAPIFilter{
+					All: []brokerv1beta1.SubscriptionsAPIFilter{{
+						Suffix: map[string]string{"subject": ".png"},
+					}},
+				},
+			}},
+		}, {
+			SQL: "myext = 'baz'",
+		}},
+		want: []*config.Filter{{
+			Exact: map[string]string{"type": "foo"},
+		}, {
+			Any: []*config.Filter{{
+				Prefix: map[string]string{"source": "bar"},
+			}, {
+				Not: &config.Filter{
+					All: []*config.Filter{{
+						Suffix: map[string]string{"subject": ".png"},
+					}},
+				},
+			}},
+		}, {
+			Sql: "myext = 'baz'",
+		}},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := MakeTargetFilters(tc.filters)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("MakeTargetFilters (-want,+got): %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// MakeIngressPolicy returns the targets config ingress policy of a Broker, or
+// nil if anyone is allowed to publish events to the Broker.
+func MakeIngressPolicy(b *brokerv1beta1.Broker) *config.IngressPolicy {
+	allowedServiceAccounts, audience := b.IngressPolicy()
+	if len(allowedServiceAccounts) == 0 {
+		return nil
+	}
+	return &config.IngressPolicy{
+		AllowedServiceAccounts: allowedServiceAccounts,
+		Audience:               audience,
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+)
+
+// MakeTargetIsolatedQueue returns the targets config queue of the dedicated
+// subscription of an isolated Trigger, or nil if the Trigger is not isolated.
+// The queue is only ready once the trigger reconciler has created the
+// subscription, the Broker's decouple subscription delivers the events of the
+// Trigger until then.
+func MakeTargetIsolatedQueue(b *brokerv1beta1.Broker, t *brokerv1beta1.Trigger) *config.Queue {
+	if isolated, _ := t.Isolation(); !isolated {
+		return nil
+	}
+	state := config.State_UNKNOWN
+	if t.Status.GetCondition(brokerv1beta1.TriggerConditionIsolatedSubscription).IsTrue() {
+		state = config.State_READY
+	}
+	return &config.Queue{
+		Topic:        brokerresources.GenerateDecouplingTopicName(b),
+		Subscription: brokerresources.GenerateIsolatedSubscriptionName(t),
+		State:        state,
+	}
+}
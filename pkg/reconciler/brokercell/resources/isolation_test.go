@@ -0,0 +1,72 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+func TestMakeTargetIsolatedQueue(t *testing.T) {
+	b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "broker", UID: "broker-uid"}}
+	testCases := []struct {
+		name      string
+		isolation string
+		ready     bool
+		want      *config.Queue
+	}{{
+		name: "not isolated",
+	}, {
+		name:      "subscription not ready",
+		isolation: brokerv1beta1.IsolationSubscription,
+		want: &config.Queue{
+			Topic:        "cre-bkr_ns_broker_broker-uid",
+			Subscription: "cre-iso_ns_trigger_trigger-uid",
+			State:        config.State_UNKNOWN,
+		},
+	}, {
+		name:      "subscription ready",
+		isolation: brokerv1beta1.IsolationFilteredSubscription,
+		ready:     true,
+		want: &config.Queue{
+			Topic:        "cre-bkr_ns_broker_broker-uid",
+			Subscription: "cre-iso_ns_trigger_trigger-uid",
+			State:        config.State_READY,
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "trigger", UID: "trigger-uid"}}
+			if tc.isolation != "" {
+				trig.Annotations = map[string]string{brokerv1beta1.IsolationAnnotationKey: tc.isolation}
+			}
+			if tc.ready {
+				trig.Status.MarkIsolatedSubscriptionReady()
+			}
+			if diff := cmp.Diff(tc.want, MakeTargetIsolatedQueue(b, trig), protocmp.Transform()); diff != "" {
+				t.Errorf("MakeTargetIsolatedQueue (-want,+got): %v", diff)
+			}
+		})
+	}
+}
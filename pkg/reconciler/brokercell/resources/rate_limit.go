@@ -0,0 +1,35 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+// MakeTargetRateLimit returns the targets config rate limit of a Trigger, or
+// nil if the Trigger's deliveries are not limited.
+func MakeTargetRateLimit(t *brokerv1beta1.Trigger) *config.RateLimit {
+	maxConcurrency, maxEventsPerSecond := t.RateLimit()
+	if maxConcurrency == 0 && maxEventsPerSecond == 0 {
+		return nil
+	}
+	return &config.RateLimit{
+		MaxConcurrency:     maxConcurrency,
+		MaxEventsPerSecond: maxEventsPerSecond,
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+const (
+	// fullGenerationPeriod is how often the targets config of a BrokerCell is
+	// generated from all of its CellTenants instead of only the dirty ones.
+	// This picks up the changes which don't mark any CellTenant dirty, e.g. a
+	// change of the default delivery spec.
+	fullGenerationPeriod = 10 * time.Minute
+)
+
+// targetsCache keeps the last generated targets config of each BrokerCell and
+// the CellTenants which changed since, so that the BrokerCell reconciler only
+// regenerates the changed CellTenants.
+type targetsCache struct {
+	mu    sync.Mutex
+	cells map[types.NamespacedName]*cellTargets
+}
+
+type cellTargets struct {
+	// targets is nil until the first full generation.
+	targets            config.Targets
+	lastFullGeneration time.Time
+	dirty              map[config.CellTenantKey]struct{}
+}
+
+func newTargetsCache() *targetsCache {
+	return &targetsCache{cells: make(map[types.NamespacedName]*cellTargets)}
+}
+
+func (c *targetsCache) cell(brokerCell types.NamespacedName) *cellTargets {
+	ct, ok := c.cells[brokerCell]
+	if !ok {
+		ct = &cellTargets{dirty: make(map[config.CellTenantKey]struct{})}
+		c.cells[brokerCell] = ct
+	}
+	return ct
+}
+
+// markDirty marks the CellTenants dirty in the BrokerCell.
+func (c *targetsCache) markDirty(brokerCell types.NamespacedName, cellTenants ...config.CellTenantKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ct := c.cell(brokerCell)
+	for _, k := range cellTenants {
+		ct.dirty[k] = struct{}{}
+	}
+}
+
+// take returns the cached targets config of the BrokerCell and the
+// CellTenants marked dirty since the previous take. The returned targets
+// config is nil if it needs to be generated from all the CellTenants of the
+// BrokerCell.
+func (c *targetsCache) take(brokerCell types.NamespacedName, now time.Time) (config.Targets, []config.CellTenantKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ct := c.cell(brokerCell)
+	dirty := make([]config.CellTenantKey, 0, len(ct.dirty))
+	for k := range ct.dirty {
+		dirty = append(dirty, k)
+	}
+	ct.dirty = make(map[config.CellTenantKey]struct{})
+	if ct.targets == nil || now.Sub(ct.lastFullGeneration) >= fullGenerationPeriod {
+		return nil, dirty
+	}
+	return ct.targets, dirty
+}
+
+// store caches the generated targets config of the BrokerCell.
+func (c *targetsCache) store(brokerCell types.NamespacedName, targets config.Targets, full bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ct := c.cell(brokerCell)
+	ct.targets = targets
+	if full {
+		ct.lastFullGeneration = now
+	}
+}
+
+// forget drops the BrokerCell from the cache.
+func (c *targetsCache) forget(brokerCell types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.cells, brokerCell)
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokercell
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
+	"knative.dev/pkg/configmap"
+	. "knative.dev/pkg/reconciler/testing"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/config/stream"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/brokercell/testingdata"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+func TestTargetsCache(t *testing.T) {
+	cell := types.NamespacedName{Namespace: testNS, Name: brokerCellName}
+	broker1 := *config.TestOnlyBrokerKey(testNS, "broker1")
+	broker2 := *config.TestOnlyBrokerKey(testNS, "broker2")
+	now := time.Now()
+	c := newTargetsCache()
+
+	c.markDirty(cell, broker1)
+	if targets, dirty := c.take(cell, now); targets != nil || len(dirty) != 1 {
+		t.Fatalf("take() before the first generation = (%v, %v), want (nil, [%v])", targets, dirty, broker1)
+	}
+
+	want := memory.NewEmptyTargets()
+	c.store(cell, want, true, now)
+	if targets, dirty := c.take(cell, now.Add(time.Second)); targets != want || len(dirty) != 0 {
+		t.Errorf("take() without dirty brokers = (%v, %v), want (%v, [])", targets, dirty, want)
+	}
+
+	c.markDirty(cell, broker1, broker2)
+	c.markDirty(cell, broker1)
+	targets, dirty := c.take(cell, now.Add(time.Second))
+	if targets != want {
+		t.Errorf("take() returned targets %v, want %v", targets, want)
+	}
+	if diff := cmp.Diff([]config.CellTenantKey{broker1, broker2}, dirty, cmpSortCellTenantKeys); diff != "" {
+		t.Errorf("take() returned unexpected dirty brokers (-want, +got): %s", diff)
+	}
+	if _, dirty := c.take(cell, now.Add(time.Second)); len(dirty) != 0 {
+		t.Errorf("take() returned dirty brokers %v twice", dirty)
+	}
+
+	// An incremental generation doesn't postpone the next full generation.
+	c.store(cell, want, false, now.Add(time.Second))
+	if targets, _ := c.take(cell, now.Add(fullGenerationPeriod)); targets != nil {
+		t.Errorf("take() after fullGenerationPeriod = %v, want nil", targets)
+	}
+
+	c.store(cell, want, true, now)
+	c.forget(cell)
+	if targets, _ := c.take(cell, now); targets != nil {
+		t.Errorf("take() of a forgotten brokercell = %v, want nil", targets)
+	}
+}
+
+var cmpSortCellTenantKeys = cmp.Transformer("sort", func(in []config.CellTenantKey) map[string]bool {
+	out := make(map[string]bool, len(in))
+	for _, k := range in {
+		out[k.PersistenceString()] = true
+	}
+	return out
+})
+
+// TestReconcileConfigIncrementally tests that only the entries of the dirty
+// brokers are regenerated in the targets config.
+func TestReconcileConfigIncrementally(t *testing.T) {
+	setReconcilerEnv()
+	bc := NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)
+	cell := types.NamespacedName{Namespace: testNS, Name: brokerCellName}
+	broker1 := NewBroker("broker1", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName))
+	trigger1 := NewTrigger("trigger1", testNS, "broker1", WithTriggerSetDefaults)
+	broker2 := NewBroker("broker2", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName))
+	trigger2 := NewTrigger("trigger2", testNS, "broker2", WithTriggerSetDefaults)
+	movedBroker2 := NewBroker("broker2", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell("other"))
+
+	ctx, _ := SetupFakeContext(t)
+	cmw := configmap.NewStaticWatcher()
+	ctx, client := fakekubeclient.With(ctx)
+	r, err := NewReconciler(reconciler.NewBase(ctx, controllerAgentName, cmw), listers{})
+	if err != nil {
+		t.Fatalf("Failed to create BrokerCell reconciler: %v", err)
+	}
+
+	reconcileConfig := func(objects ...runtime.Object) {
+		t.Helper()
+		if cm, err := client.CoreV1().ConfigMaps(testNS).Get(context.Background(), resources.Name(bc.Name, targetsCMName), metav1.GetOptions{}); err == nil {
+			objects = append(objects, cm)
+		}
+		testingListers := NewListers(objects)
+		r.brokerLister = testingListers.GetBrokerLister()
+		r.triggerLister = testingListers.GetTriggerLister()
+		r.channelLister = testingListers.GetChannelLister()
+		r.configMapLister = testingListers.GetConfigMapLister()
+		r.podLister = testingListers.GetPodLister()
+		r.cmRec.Lister = r.configMapLister
+		if err := r.reconcileConfig(ctx, bc); err != nil {
+			t.Fatalf("reconcileConfig() = %v", err)
+		}
+	}
+	wantConfig := func(want *corev1.ConfigMap) {
+		t.Helper()
+		got, err := client.CoreV1().ConfigMaps(testNS).Get(context.Background(), resources.Name(bc.Name, targetsCMName), metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get ConfigMap from client: %v", err)
+		}
+		var wantTargets, gotTargets config.TargetsConfig
+		if err := proto.Unmarshal(want.BinaryData[targetsCMKey], &wantTargets); err != nil {
+			t.Fatalf("Failed to deserialize the binary data in ConfigMap: %v", err)
+		}
+		if err := proto.Unmarshal(got.BinaryData[targetsCMKey], &gotTargets); err != nil {
+			t.Fatalf("Failed to deserialize the binary data in ConfigMap: %v", err)
+		}
+		if diff := cmp.Diff(wantTargets.String(), gotTargets.String()); diff != "" {
+			t.Fatalf("Unexpected brokerTargets in ConfigMap(-want, +got): %s", diff)
+		}
+	}
+
+	// The first reconcile generates the targets config from all the brokers.
+	reconcileConfig(bc, broker1, trigger1)
+	wantConfig(testingdata.Config(t, bc, broker1, trigger1))
+
+	// Changes to brokers which are not marked dirty are not picked up.
+	reconcileConfig(bc, broker2, trigger2)
+	wantConfig(testingdata.Config(t, bc, broker1, trigger1))
+
+	// Deleted brokers are removed and added brokers are inserted.
+	r.targetsCache.markDirty(cell, *config.TestOnlyBrokerKey(testNS, "broker1"), *config.TestOnlyBrokerKey(testNS, "broker2"))
+	reconcileConfig(bc, broker2, trigger2)
+	wantConfig(testingdata.Config(t, bc, broker2, trigger2))
+
+	// Brokers moved to another brokercell are removed.
+	r.targetsCache.markDirty(cell, *config.TestOnlyBrokerKey(testNS, "broker2"))
+	reconcileConfig(bc, movedBroker2, trigger2)
+	wantConfig(testingdata.EmptyConfig(t, bc))
+}
+
+// TestReconcileConfigWithServer tests that failing to update the ConfigMap
+// doesn't fail the targets config when it is served by the targets config
+// server.
+func TestReconcileConfigWithServer(t *testing.T) {
+	setReconcilerEnv()
+	bc := NewBrokerCell(brokerCellName, testNS, WithBrokerCellSetDefaults)
+	broker := NewBroker("broker", testNS, WithBrokerSetDefaults, WithBrokerAssignedBrokerCell(brokerCellName))
+
+	ctx, _ := SetupFakeContext(t)
+	cmw := configmap.NewStaticWatcher()
+	ctx, client := fakekubeclient.With(ctx)
+	client.PrependReactor("create", "configmaps", InduceFailure("create", "configmaps"))
+	r, err := NewReconciler(reconciler.NewBase(ctx, controllerAgentName, cmw), listers{})
+	if err != nil {
+		t.Fatalf("Failed to create BrokerCell reconciler: %v", err)
+	}
+	r.targetsServer = stream.NewServer()
+	testingListers := NewListers([]runtime.Object{bc, broker})
+	r.brokerLister = testingListers.GetBrokerLister()
+	r.triggerLister = testingListers.GetTriggerLister()
+	r.channelLister = testingListers.GetChannelLister()
+	r.configMapLister = testingListers.GetConfigMapLister()
+	r.podLister = testingListers.GetPodLister()
+	r.cmRec.Lister = r.configMapLister
+
+	if err := r.reconcileConfig(ctx, bc); err != nil {
+		t.Fatalf("reconcileConfig() = %v", err)
+	}
+	if !bc.Status.GetCondition(intv1alpha1.BrokerCellConditionTargetsConfig).IsTrue() {
+		t.Errorf("Targets config condition = %v, want ready", bc.Status.GetCondition(intv1alpha1.BrokerCellConditionTargetsConfig))
+	}
+	var created bool
+	for _, action := range client.Actions() {
+		if action.Matches("create", "configmaps") {
+			created = true
+		}
+	}
+	if !created {
+		t.Error("The ConfigMap update wasn't attempted")
+	}
+}
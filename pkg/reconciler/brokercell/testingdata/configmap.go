@@ -17,6 +17,8 @@ limitations under the License.
 package testingdata
 
 import (
+	"context"
+
 	"testing"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
@@ -58,6 +60,21 @@ func Config(t *testing.T, bc *intv1alpha1.BrokerCell, broker *brokerv1beta1.Brok
 			},
 			State:            state,
 			FilterAttributes: filterAttributes,
+			Filters:          resources.MakeTargetFilters(t.Spec.Filters),
+			BatchPolicy:      resources.MakeTargetBatchPolicy(t),
+			RateLimit:        resources.MakeTargetRateLimit(t),
+			CircuitBreaker:   resources.MakeTargetCircuitBreaker(t),
+			IsolatedQueue:    resources.MakeTargetIsolatedQueue(broker, t),
+			Paused:           t.IsPaused(),
+			Audience:         t.DeliveryAudience(broker),
+		}
+
+		if d := t.DeliverySpecWithDefaults(context.Background(), broker); d.DeadLetterSink != nil && !brokerv1beta1.IsPubsubDeadLetterSink(d.DeadLetterSink) {
+			target.DeadLetterQueue = &config.Queue{
+				Topic:        brokerresources.GenerateDeadLetterTopicName(t),
+				Subscription: brokerresources.GenerateDeadLetterSubscriptionName(t),
+			}
+			target.DeadLetterAddress = d.DeadLetterSink.URI.String()
 		}
 
 		targets[t.Name] = target
@@ -83,8 +100,10 @@ func Config(t *testing.T, bc *intv1alpha1.BrokerCell, broker *brokerv1beta1.Brok
 			Subscription: brokerresources.GenerateDecouplingSubscriptionName(broker),
 			State:        brokerQueueState,
 		},
-		Targets: targets,
-		State:   state,
+		Targets:              targets,
+		State:                state,
+		OrderingKeyExtension: broker.OrderingKeyExtension(),
+		IngressPolicy:        resources.MakeIngressPolicy(broker),
 	}
 	bt := &config.TargetsConfig{
 		CellTenants: map[string]*config.CellTenant{
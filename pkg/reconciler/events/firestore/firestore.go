@@ -0,0 +1,259 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudfirestoresourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudfirestoresource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	gfirestore "github.com/google/knative-gcp/pkg/gclient/firestore"
+	"github.com/google/knative-gcp/pkg/reconciler/events/firestore/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	resourceGroup = "cloudfirestoresources.events.cloud.google.com"
+
+	deleteChangeFeedFailed       = "ChangeFeedDeleteFailed"
+	deletePubSubFailed           = "PubSubDeleteFailed"
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	reconciledPubSubFailedReason = "PubSubReconcileFailed"
+	reconciledFailedReason       = "ChangeFeedReconcileFailed"
+	reconciledPendingReason      = "ChangeFeedPending"
+	reconciledSuccessReason      = "CloudFirestoreSourceReconciled"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+)
+
+// changeFeedPendingResyncPeriod is the period between reconciliations of
+// CloudFirestoreSources while a change feed operation is in progress.
+const changeFeedPendingResyncPeriod = 10 * time.Second
+
+// errChangeFeedPending is returned while the creation of a change feed, or the
+// deletion of the outdated change feed it replaces, is in progress.
+var errChangeFeedPending = errors.New("change feed operation in progress")
+
+// Reconciler is the controller implementation for the change feeds of Google Cloud Firestore documents.
+type Reconciler struct {
+	*intevents.PubSubBase
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// firestoreLister for reading firestores.
+	firestoreLister listers.CloudFirestoreSourceLister
+
+	createClientFn gfirestore.CreateFn
+
+	// enqueueAfter enqueues a CloudFirestoreSource after a delay. It is used
+	// to wait for the change feed operations in progress.
+	enqueueAfter func(obj interface{}, after time.Duration)
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudfirestoresourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, firestore *v1.CloudFirestoreSource) reconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("firestore", firestore)))
+
+	firestore.Status.InitializeConditions()
+	firestore.Status.ObservedGeneration = firestore.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if firestore.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, firestore.Spec.Project, firestore); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudFirestoreSource workload identity: %s", err.Error())
+		}
+	}
+
+	topic := resources.GenerateTopicName(firestore)
+	_, _, err := r.PubSubBase.ReconcilePubSub(ctx, firestore, topic, resourceGroup)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: %s", err.Error())
+	}
+
+	feeds, err := r.reconcileChangeFeeds(ctx, firestore, topic)
+	if errors.Is(err, errChangeFeedPending) {
+		if r.enqueueAfter != nil {
+			r.enqueueAfter(firestore, changeFeedPendingResyncPeriod)
+		}
+		firestore.Status.MarkChangeFeedUnknown(reconciledPendingReason, "Waiting for the CloudFirestoreSource change feeds: %s", err.Error())
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPendingReason, "Reconcile ChangeFeeds pending: %s", err.Error())
+	}
+	if err != nil {
+		firestore.Status.MarkChangeFeedNotReady(reconciledFailedReason, "Failed to reconcile CloudFirestoreSource change feeds: %s", err.Error())
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile ChangeFeeds failed with: %s", err.Error())
+	}
+	firestore.Status.MarkChangeFeedReady(feeds)
+	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudFirestoreSource reconciled: "%s/%s"`, firestore.Namespace, firestore.Name)
+}
+
+// reconcileChangeFeeds creates a change feed per event type of the spec and
+// deletes the change feeds of the event types removed from the spec. It
+// returns the names of the change feeds of the spec.
+func (r *Reconciler) reconcileChangeFeeds(ctx context.Context, firestore *v1.CloudFirestoreSource, topic string) ([]string, error) {
+	if firestore.Status.ProjectID == "" {
+		projectID, err := utils.ProjectIDOrDefault(firestore.Spec.Project)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+			return nil, err
+		}
+		// Set the projectID in the status.
+		firestore.Status.ProjectID = projectID
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudFirestoreSource client", zap.Error(err))
+		return nil, err
+	}
+	defer client.Close()
+
+	feeds := make([]string, 0, len(firestore.Spec.EventTypes))
+	for _, eventType := range firestore.Spec.EventTypes {
+		feedName := resources.GenerateChangeFeedName(firestore, eventType)
+		want := resources.MakeChangeFeed(firestore, feedName, eventType, topic)
+		if err := r.reconcileChangeFeed(ctx, client, want); err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, feedName)
+	}
+
+	wanted := sets.NewString(feeds...)
+	for _, feedName := range firestore.Status.ChangeFeeds {
+		if wanted.Has(feedName) {
+			continue
+		}
+		if err := r.deleteChangeFeed(ctx, client, feedName); err != nil {
+			return nil, err
+		}
+	}
+	return feeds, nil
+}
+
+func (r *Reconciler) reconcileChangeFeed(ctx context.Context, client gfirestore.Client, want *gfirestore.ChangeFeed) error {
+	// Check if the change feed exists.
+	feed, err := client.GetChangeFeed(ctx, want.Name)
+	if err != nil {
+		if st, ok := gstatus.FromError(err); !ok {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudFirestoreSource client while retrieving CloudFirestoreSource change feed", zap.String("feedName", want.Name), zap.Error(err))
+			return err
+		} else if st.Code() != codes.NotFound {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudFirestoreSource client while retrieving CloudFirestoreSource change feed", zap.String("feedName", want.Name), zap.Any("errorCode", st.Code()), zap.Error(err))
+			return err
+		}
+	} else if !resources.ChangeFeedChanged(feed, want) {
+		return nil
+	} else if err := r.deleteChangeFeed(ctx, client, want.Name); err != nil {
+		// Change feeds cannot be updated, the outdated one is recreated.
+		return err
+	}
+
+	if _, err := client.CreateChangeFeed(ctx, want); gstatus.Code(err) == codes.AlreadyExists {
+		// The creation and the deletion of change feeds are long running
+		// operations, the change feed is found once its creation is done and
+		// created once the deletion of the outdated one is done.
+		logging.FromContext(ctx).Desugar().Debug("CloudFirestoreSource change feed operation in progress", zap.String("feedName", want.Name), zap.Error(err))
+		return fmt.Errorf("%w: %s", errChangeFeedPending, want.Name)
+	} else if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudFirestoreSource change feed", zap.String("feedName", want.Name), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// deleteChangeFeed deletes the change feed, if it exists.
+func (r *Reconciler) deleteChangeFeed(ctx context.Context, client gfirestore.Client, feedName string) error {
+	err := client.DeleteChangeFeed(ctx, feedName)
+	if err == nil {
+		logging.FromContext(ctx).Desugar().Debug("Deleted CloudFirestoreSource change feed", zap.String("feedName", feedName))
+		return nil
+	}
+	if st, ok := gstatus.FromError(err); !ok {
+		logging.FromContext(ctx).Desugar().Error("Failed from CloudFirestoreSource client while deleting CloudFirestoreSource change feed", zap.String("feedName", feedName), zap.Error(err))
+		return err
+	} else if st.Code() != codes.NotFound {
+		logging.FromContext(ctx).Desugar().Error("Failed to delete CloudFirestoreSource change feed", zap.String("feedName", feedName), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// deleteChangeFeeds deletes the change feeds in status.ChangeFeeds, and the
+// ones of the event types of the spec which may have been created by a
+// reconciliation that failed afterwards.
+func (r *Reconciler) deleteChangeFeeds(ctx context.Context, firestore *v1.CloudFirestoreSource) error {
+	feeds := sets.NewString(firestore.Status.ChangeFeeds...)
+	if firestore.Status.ProjectID != "" {
+		for _, eventType := range firestore.Spec.EventTypes {
+			feeds.Insert(resources.GenerateChangeFeedName(firestore, eventType))
+		}
+	}
+	if feeds.Len() == 0 {
+		return nil
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudFirestoreSource client", zap.Error(err))
+		firestore.Status.MarkChangeFeedUnknown(deleteChangeFeedFailed, "Failed to create CloudFirestoreSource client: %s", err.Error())
+		return err
+	}
+	defer client.Close()
+
+	for _, feedName := range feeds.List() {
+		if err := r.deleteChangeFeed(ctx, client, feedName); err != nil {
+			firestore.Status.MarkChangeFeedUnknown(deleteChangeFeedFailed, "Failed to delete CloudFirestoreSource change feed: %s", err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, firestore *v1.CloudFirestoreSource) reconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if firestore.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, firestore.Spec.Project, firestore); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudFirestoreSource workload identity: %s", err.Error())
+		}
+	}
+
+	logging.FromContext(ctx).Desugar().Debug("Deleting CloudFirestoreSource change feeds")
+	if err := r.deleteChangeFeeds(ctx, firestore); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteChangeFeedFailed, "Failed to delete CloudFirestoreSource change feeds: %s", err.Error())
+	}
+
+	if err := r.PubSubBase.DeletePubSub(ctx, firestore); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailed, "Failed to delete CloudFirestoreSource PubSub: %s", err.Error())
+	}
+
+	return nil
+}
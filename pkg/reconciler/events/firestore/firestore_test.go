@@ -0,0 +1,580 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package firestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	reconcilertestingv1 "github.com/google/knative-gcp/pkg/reconciler/testing/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	. "knative.dev/pkg/reconciler/testing"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	firestorev1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudfirestoresource"
+	gfirestoreclient "github.com/google/knative-gcp/pkg/gclient/firestore"
+	gfirestore "github.com/google/knative-gcp/pkg/gclient/firestore/testing"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+const (
+	firestoreName = "my-test-firestore"
+	firestoreUID  = "test-firestore-uid"
+	sinkName      = "sink"
+
+	testNS       = "testnamespace"
+	testProject  = "test-project-id"
+	testTopicURI = "http://" + firestoreName + "-topic." + testNS + ".svc.cluster.local"
+	location     = "nam5"
+	document     = "users/{userId}"
+	parentName   = "projects/" + testProject + "/locations/" + location
+	feedPrefix   = parentName + "/triggers/cre-firestore-" + firestoreUID
+
+	createdFeedName = feedPrefix + "-created"
+	deletedFeedName = feedPrefix + "-deleted"
+	writtenFeedName = feedPrefix + "-written"
+
+	// Message for when the change feeds with the above variables are not ready.
+	failedToReconcileChangeFeedMsg = `Failed to reconcile CloudFirestoreSource change feeds`
+	failedToDeleteChangeFeedMsg    = `Failed to delete CloudFirestoreSource change feed`
+)
+
+var (
+	falseVal = false
+
+	sinkDNS = sinkName + ".mynamespace.svc.cluster.local"
+	sinkURI = apis.HTTP(sinkDNS)
+
+	testTopicID = fmt.Sprintf("cre-src_%s_%s_%s", testNS, firestoreName, firestoreUID)
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1",
+		Kind:    "Sink",
+	}
+
+	secret = corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "google-cloud-key",
+		},
+		Key: "key.json",
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = firestorev1.AddToScheme(scheme.Scheme)
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func newSink() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "testing.cloud.google.com/v1",
+			"kind":       "Sink",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      sinkName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"hostname": sinkDNS,
+				},
+			},
+		},
+	}
+}
+
+func newSinkDestination() duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "testing.cloud.google.com/v1",
+			Kind:       "Sink",
+			Name:       sinkName,
+		},
+	}
+}
+
+// newFirestore returns the CloudFirestoreSource with the spec used in the tests and the given options.
+func newFirestore(so ...reconcilertestingv1.CloudFirestoreSourceOption) *firestorev1.CloudFirestoreSource {
+	return reconcilertestingv1.NewCloudFirestoreSource(firestoreName, testNS, append([]reconcilertestingv1.CloudFirestoreSourceOption{
+		reconcilertestingv1.WithCloudFirestoreSourceProject(testProject),
+		reconcilertestingv1.WithCloudFirestoreSourceSink(sinkGVK, sinkName),
+		reconcilertestingv1.WithCloudFirestoreSourceLocation(location),
+		reconcilertestingv1.WithCloudFirestoreSourceDocument(document),
+		reconcilertestingv1.WithCloudFirestoreSourceSetDefaults,
+	}, so...)...)
+}
+
+// newReadyFirestore returns the CloudFirestoreSource with a ready topic and pullsubscription and the given options.
+func newReadyFirestore(so ...reconcilertestingv1.CloudFirestoreSourceOption) *firestorev1.CloudFirestoreSource {
+	return newFirestore(append([]reconcilertestingv1.CloudFirestoreSourceOption{
+		reconcilertestingv1.WithInitCloudFirestoreSourceConditions,
+		reconcilertestingv1.WithCloudFirestoreSourceTopicReady(testTopicID, testProject),
+		reconcilertestingv1.WithCloudFirestoreSourcePullSubscriptionReady,
+		reconcilertestingv1.WithCloudFirestoreSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+		reconcilertestingv1.WithCloudFirestoreSourceSinkURI(sinkURI),
+	}, so...)...)
+}
+
+func newReadyTopic() *inteventsv1.Topic {
+	return reconcilertestingv1.NewTopic(firestoreName, testNS,
+		reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+			Topic:             testTopicID,
+			PropagationPolicy: "CreateDelete",
+			Project:           testProject,
+			EnablePublisher:   &falseVal,
+		}),
+		reconcilertestingv1.WithTopicReady(testTopicID),
+		reconcilertestingv1.WithTopicAddress(testTopicURI),
+		reconcilertestingv1.WithTopicProjectID(testProject),
+		reconcilertestingv1.WithTopicSetDefaults,
+	)
+}
+
+func newReadyPullSubscription() *inteventsv1.PullSubscription {
+	return reconcilertestingv1.NewPullSubscription(firestoreName, testNS,
+		reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+		reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+			Topic: testTopicID,
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				Secret: &secret,
+				SourceSpec: duckv1.SourceSpec{
+					Sink: newSinkDestination(),
+				},
+				Project: testProject,
+			},
+			AdapterType: string(converters.CloudFirestore),
+		}),
+	)
+}
+
+func changeFeed(name, eventType string) gfirestoreclient.ChangeFeed {
+	return gfirestoreclient.ChangeFeed{
+		Name:      name,
+		Database:  schemasv1.CloudFirestoreDefaultDatabase,
+		Document:  document,
+		EventType: eventType,
+		Topic:     "projects/" + testProject + "/topics/" + testTopicID,
+	}
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "topic exists, topic has not yet been reconciled",
+		Objects: []runtime.Object{
+			newFirestore(),
+			reconcilertestingv1.NewTopic(firestoreName, testNS,
+				reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					Project:           testProject,
+					EnablePublisher:   &falseVal,
+				}),
+				reconcilertestingv1.WithTopicUnknown,
+				reconcilertestingv1.WithTopicSetDefaults,
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newFirestore(
+				reconcilertestingv1.WithInitCloudFirestoreSourceConditions,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: the status of Topic %q is Unknown", firestoreName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create client fails",
+		Objects: []runtime.Object{
+			newFirestore(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				CreateClientErr: errors.New("create-client-induced-error"),
+			},
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileChangeFeedMsg, "create-client-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile ChangeFeeds failed with: create-client-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, get change feed fails with grpc unknown error",
+		Objects: []runtime.Object{
+			newFirestore(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				GetChangeFeedErr: gstatus.Error(codes.Unknown, "get-feed-induced-error"),
+			},
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedNotReady(reconciledFailedReason, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileChangeFeedMsg, codes.Unknown, "get-feed-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, fmt.Sprintf("Reconcile ChangeFeeds failed with: rpc error: code = %s desc = %s", codes.Unknown, "get-feed-induced-error")),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, change feed not found, create change feed fails",
+		Objects: []runtime.Object{
+			newFirestore(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				CreateChangeFeedErr: errors.New("create-feed-induced-error"),
+			},
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileChangeFeedMsg, "create-feed-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile ChangeFeeds failed with: create-feed-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, change feed creation in progress",
+		Objects: []runtime.Object{
+			newFirestore(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				CreateChangeFeedErr: gstatus.Error(codes.AlreadyExists, "create-feed-in-progress"),
+			},
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedUnknown(reconciledPendingReason, fmt.Sprintf("Waiting for the CloudFirestoreSource change feeds: %s: %s", errChangeFeedPending, writtenFeedName)),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeWarning, reconciledPendingReason, fmt.Sprintf("Reconcile ChangeFeeds pending: %s: %s", errChangeFeedPending, writtenFeedName)),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, change feeds created",
+		Objects: []runtime.Object{
+			newFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceEventTypes(schemasv1.CloudFirestoreDocumentCreatedEventType, schemasv1.CloudFirestoreDocumentDeletedEventType),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceEventTypes(schemasv1.CloudFirestoreDocumentCreatedEventType, schemasv1.CloudFirestoreDocumentDeletedEventType),
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(createdFeedName, deletedFeedName),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudFirestoreSource reconciled: "%s/%s"`, testNS, firestoreName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, change feed exists",
+		Objects: []runtime.Object{
+			newFirestore(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				ChangeFeeds: map[string]gfirestoreclient.ChangeFeed{
+					writtenFeedName: changeFeed(writtenFeedName, schemasv1.CloudFirestoreDocumentWrittenEventType),
+				},
+				// The change feed is up to date, thus it is not recreated.
+				CreateChangeFeedErr: errors.New("create-feed-induced-error"),
+			},
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(writtenFeedName),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudFirestoreSource reconciled: "%s/%s"`, testNS, firestoreName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, outdated change feed fails to delete",
+		Objects: []runtime.Object{
+			newFirestore(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				ChangeFeeds: map[string]gfirestoreclient.ChangeFeed{
+					writtenFeedName: func() gfirestoreclient.ChangeFeed {
+						feed := changeFeed(writtenFeedName, schemasv1.CloudFirestoreDocumentWrittenEventType)
+						feed.Topic = "projects/" + testProject + "/topics/other"
+						return feed
+					}(),
+				},
+				DeleteChangeFeedErr: errors.New("delete-feed-induced-error"),
+			},
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileChangeFeedMsg, "delete-feed-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile ChangeFeeds failed with: delete-feed-induced-error"),
+		},
+	}, {
+		Name: "event type removed, change feed of the event type fails to delete",
+		Objects: []runtime.Object{
+			newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(createdFeedName, writtenFeedName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				DeleteChangeFeedErr: gstatus.Error(codes.Unknown, "delete-feed-induced-error"),
+			},
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(createdFeedName, writtenFeedName),
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedNotReady(reconciledFailedReason, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileChangeFeedMsg, codes.Unknown, "delete-feed-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, fmt.Sprintf("Reconcile ChangeFeeds failed with: rpc error: code = %s desc = %s", codes.Unknown, "delete-feed-induced-error")),
+		},
+	}, {
+		Name: "event type removed, change feed of the event type deleted",
+		Objects: []runtime.Object{
+			newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(createdFeedName, writtenFeedName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				DeleteChangeFeedErr: gstatus.Error(codes.NotFound, "delete-feed-induced-error"),
+			},
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(writtenFeedName),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, firestoreName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", firestoreName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudFirestoreSource reconciled: "%s/%s"`, testNS, firestoreName),
+		},
+	}, {
+		Name: "firestore change feed fails to delete with Unknown grpc error",
+		Objects: []runtime.Object{
+			newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(writtenFeedName),
+				reconcilertestingv1.WithCloudFirestoreSourceDeletionTimestamp,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(writtenFeedName),
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedUnknown(deleteChangeFeedFailed,
+					fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToDeleteChangeFeedMsg, codes.Unknown, "delete-feed-induced-error")),
+				reconcilertestingv1.WithCloudFirestoreSourceDeletionTimestamp,
+			),
+		}},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				DeleteChangeFeedErr: gstatus.Error(codes.Unknown, "delete-feed-induced-error"),
+			},
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, deleteChangeFeedFailed, fmt.Sprintf("Failed to delete CloudFirestoreSource change feeds: rpc error: code = %s desc = %s", codes.Unknown, "delete-feed-induced-error")),
+		},
+	}, {
+		Name: "firestore successfully deleted with NotFound grpc error",
+		Objects: []runtime.Object{
+			newReadyFirestore(
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(writtenFeedName),
+				reconcilertestingv1.WithCloudFirestoreSourceDeletionTimestamp,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + firestoreName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newFirestore(
+				reconcilertestingv1.WithInitCloudFirestoreSourceConditions,
+				reconcilertestingv1.WithCloudFirestoreSourceChangeFeedReady(writtenFeedName),
+				reconcilertestingv1.WithCloudFirestoreSourceTopicDeleted,
+				reconcilertestingv1.WithCloudFirestoreSourcePullSubscriptionDeleted,
+				reconcilertestingv1.WithCloudFirestoreSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudFirestoreSourceDeletionTimestamp,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"}},
+				Name: firestoreName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"}},
+				Name: firestoreName,
+			},
+		},
+		OtherTestData: map[string]interface{}{
+			"firestore": gfirestore.TestClientData{
+				DeleteChangeFeedErr: gstatus.Error(codes.NotFound, "delete-feed-induced-error"),
+			},
+		},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			PubSubBase: intevents.NewPubSubBase(ctx,
+				&intevents.PubSubBaseArgs{
+					ControllerAgentName: controllerAgentName,
+					ReceiveAdapterName:  receiveAdapterName,
+					ReceiveAdapterType:  string(converters.CloudFirestore),
+					ConfigWatcher:       cmw,
+				}),
+			Identity:        identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			firestoreLister: listers.GetCloudFirestoreSourceLister(),
+			createClientFn:  gfirestore.TestClientCreator(testData["firestore"]),
+		}
+		return cloudfirestoresource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudFirestoreSourceLister(), r.Recorder, r)
+	}))
+
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	gfirestore "github.com/google/knative-gcp/pkg/gclient/firestore"
+)
+
+// MakeChangeFeed generates (but does not create) the change feed of the event
+// type of the CloudFirestoreSource.
+func MakeChangeFeed(firestore *v1.CloudFirestoreSource, feedName, eventType, topic string) *gfirestore.ChangeFeed {
+	return &gfirestore.ChangeFeed{
+		Name:      feedName,
+		Database:  firestore.Spec.Database,
+		Document:  firestore.Spec.Document,
+		EventType: eventType,
+		Topic:     GenerateChangeFeedTopic(firestore, topic),
+	}
+}
+
+// ChangeFeedChanged returns true if the existing change feed does not publish
+// the changes wanted. Change feeds cannot be updated, they are recreated.
+func ChangeFeedChanged(existing, want *gfirestore.ChangeFeed) bool {
+	return existing.Database != want.Database ||
+		existing.Document != want.Document ||
+		existing.EventType != want.EventType ||
+		existing.Topic != want.Topic
+}
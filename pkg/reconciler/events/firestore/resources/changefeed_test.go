@@ -0,0 +1,73 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	gfirestore "github.com/google/knative-gcp/pkg/gclient/firestore"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+)
+
+func TestMakeChangeFeed(t *testing.T) {
+	want := &gfirestore.ChangeFeed{
+		Name:      "feed",
+		Database:  "(default)",
+		Document:  "users/{userId}",
+		EventType: schemasv1.CloudFirestoreDocumentCreatedEventType,
+		Topic:     "projects/project/topics/topic",
+	}
+	got := MakeChangeFeed(newFirestore(), "feed", schemasv1.CloudFirestoreDocumentCreatedEventType, "topic")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestChangeFeedChanged(t *testing.T) {
+	want := MakeChangeFeed(newFirestore(), "feed", schemasv1.CloudFirestoreDocumentCreatedEventType, "topic")
+
+	testCases := map[string]struct {
+		existing *gfirestore.ChangeFeed
+		changed  bool
+	}{
+		"same": {
+			existing: MakeChangeFeed(newFirestore(), "feed", schemasv1.CloudFirestoreDocumentCreatedEventType, "topic"),
+			changed:  false,
+		},
+		"document changed": {
+			existing: func() *gfirestore.ChangeFeed {
+				f := MakeChangeFeed(newFirestore(), "feed", schemasv1.CloudFirestoreDocumentCreatedEventType, "topic")
+				f.Document = "users/**"
+				return f
+			}(),
+			changed: true,
+		},
+		"topic changed": {
+			existing: MakeChangeFeed(newFirestore(), "feed", schemasv1.CloudFirestoreDocumentCreatedEventType, "other"),
+			changed:  true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := ChangeFeedChanged(tc.existing, want); got != tc.changed {
+				t.Errorf("ChangeFeedChanged() = %v, want %v", got, tc.changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	"github.com/google/knative-gcp/pkg/utils/naming"
+)
+
+const (
+	ChangeFeedPrefix = "triggers/cre-firestore"
+)
+
+// GenerateChangeFeedName generates the name of the change feed of the event type like this:
+// projects/PROJECT_ID/locations/LOCATION_ID/triggers/FEED_ID, where FEED_ID ends with the last
+// segment of the event type, e.g. "written".
+func GenerateChangeFeedName(firestore *v1.CloudFirestoreSource, eventType string) string {
+	change := eventType[strings.LastIndex(eventType, ".")+1:]
+	return fmt.Sprintf("projects/%s/locations/%s/%s-%s-%s", firestore.Status.ProjectID, firestore.Spec.Location, ChangeFeedPrefix, string(firestore.UID), change)
+}
+
+// GenerateTopicName generates a topic name for the firestore. This refers to the underlying Pub/Sub topic, and not our
+// Topic resource.
+func GenerateTopicName(firestore *v1.CloudFirestoreSource) string {
+	return naming.TruncatedPubsubResourceName("cre-src", firestore.Namespace, firestore.Name, firestore.UID)
+}
+
+// GenerateChangeFeedTopic generates the full name of the topic the change feeds publish to.
+func GenerateChangeFeedTopic(firestore *v1.CloudFirestoreSource, topic string) string {
+	return fmt.Sprintf("projects/%s/topics/%s", firestore.Status.ProjectID, topic)
+}
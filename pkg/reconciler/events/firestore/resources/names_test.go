@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	schemasv1 "github.com/google/knative-gcp/pkg/schemas/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newFirestore() *v1.CloudFirestoreSource {
+	return &v1.CloudFirestoreSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+			UID:       "uid",
+		},
+		Spec: v1.CloudFirestoreSourceSpec{
+			Location: "location",
+			Database: "(default)",
+			Document: "users/{userId}",
+		},
+		Status: v1.CloudFirestoreSourceStatus{
+			PubSubStatus: duckv1.PubSubStatus{
+				ProjectID: "project",
+			},
+		},
+	}
+}
+
+func TestGenerateChangeFeedName(t *testing.T) {
+	want := "projects/project/locations/location/" + ChangeFeedPrefix + "-uid-written"
+	got := GenerateChangeFeedName(newFirestore(), schemasv1.CloudFirestoreDocumentWrittenEventType)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestGenerateTopicName(t *testing.T) {
+	want := "cre-src_namespace_name_uid"
+	got := GenerateTopicName(newFirestore())
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestGenerateChangeFeedTopic(t *testing.T) {
+	want := "projects/project/topics/topic"
+	got := GenerateChangeFeedTopic(newFirestore(), "topic")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	cloudmonitoringalertsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudmonitoringalertsource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	resourceGroup = "cloudmonitoringalertsources.events.cloud.google.com"
+
+	deleteNotificationChannelFailed = "NotificationChannelDeleteFailed"
+	deletePubSubFailed              = "PubSubDeleteFailed"
+	deleteWorkloadIdentityFailed    = "WorkloadIdentityDeleteFailed"
+	reconciledPubSubFailedReason    = "PubSubReconcileFailed"
+	reconciledFailedReason          = "NotificationChannelReconcileFailed"
+	reconciledSuccessReason         = "CloudMonitoringAlertSourceReconciled"
+	workloadIdentityFailed          = "WorkloadIdentityReconcileFailed"
+)
+
+// notificationChannelsMask is the update mask of the alert policies the
+// notification channel is attached to or detached from.
+var notificationChannelsMask = &fieldmaskpb.FieldMask{Paths: []string{"notification_channels"}}
+
+// Reconciler is the controller implementation for the notification channels of Google Cloud Monitoring alert policies.
+type Reconciler struct {
+	*intevents.PubSubBase
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// alertLister for reading alert sources.
+	alertLister listers.CloudMonitoringAlertSourceLister
+
+	createClientFn gmonitoring.AlertingCreateFn
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudmonitoringalertsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1.CloudMonitoringAlertSource) reconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("source", source)))
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if source.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudMonitoringAlertSource workload identity: %s", err.Error())
+		}
+	}
+
+	topic := resources.GenerateTopicName(source)
+	_, _, err := r.PubSubBase.ReconcilePubSub(ctx, source, topic, resourceGroup)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: %s", err.Error())
+	}
+
+	channel, policies, err := r.reconcileNotificationChannel(ctx, source, topic)
+	if err != nil {
+		source.Status.MarkNotificationChannelNotReady(reconciledFailedReason, "Failed to reconcile CloudMonitoringAlertSource notification channel: %s", err.Error())
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: %s", err.Error())
+	}
+	source.Status.MarkNotificationChannelReady(channel, policies)
+	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+// reconcileNotificationChannel creates the notification channel to the topic,
+// attaches it to the alert policies of the spec and detaches it from the alert
+// policies removed from the spec. It returns the name of the channel and the
+// names of the alert policies of the spec.
+func (r *Reconciler) reconcileNotificationChannel(ctx context.Context, source *v1.CloudMonitoringAlertSource, topic string) (string, []string, error) {
+	if source.Status.ProjectID == "" {
+		projectID, err := utils.ProjectIDOrDefault(source.Spec.Project)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+			return "", nil, err
+		}
+		// Set the projectID in the status.
+		source.Status.ProjectID = projectID
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource client", zap.Error(err))
+		return "", nil, err
+	}
+	defer client.Close()
+
+	channel, err := r.reconcileChannel(ctx, client, source, topic)
+	if err != nil {
+		return "", nil, err
+	}
+
+	policies := make([]string, 0, len(source.Spec.AlertPolicies))
+	wanted := sets.NewString()
+	for _, policy := range source.Spec.AlertPolicies {
+		policyName := resources.GenerateAlertPolicyName(source, policy)
+		if wanted.Has(policyName) {
+			continue
+		}
+		if err := r.attachNotificationChannel(ctx, client, policyName, channel); err != nil {
+			return "", nil, err
+		}
+		policies = append(policies, policyName)
+		wanted.Insert(policyName)
+	}
+
+	for _, policyName := range source.Status.AlertPolicies {
+		if wanted.Has(policyName) {
+			continue
+		}
+		if err := r.detachNotificationChannel(ctx, client, policyName, channel); err != nil {
+			return "", nil, err
+		}
+	}
+	return channel, policies, nil
+}
+
+// reconcileChannel returns the name of the notification channel in
+// status.NotificationChannel, creating the channel if it does not exist.
+func (r *Reconciler) reconcileChannel(ctx context.Context, client gmonitoring.AlertingClient, source *v1.CloudMonitoringAlertSource, topic string) (string, error) {
+	if source.Status.NotificationChannel != "" {
+		// Check if the notification channel exists.
+		channel, err := client.GetNotificationChannel(ctx, &monitoringpb.GetNotificationChannelRequest{
+			Name: source.Status.NotificationChannel,
+		})
+		if err == nil {
+			return channel.Name, nil
+		}
+		if st, ok := gstatus.FromError(err); !ok {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while retrieving CloudMonitoringAlertSource notification channel", zap.String("channelName", source.Status.NotificationChannel), zap.Error(err))
+			return "", err
+		} else if st.Code() != codes.NotFound {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while retrieving CloudMonitoringAlertSource notification channel", zap.String("channelName", source.Status.NotificationChannel), zap.Any("errorCode", st.Code()), zap.Error(err))
+			return "", err
+		}
+	}
+
+	channel, err := client.CreateNotificationChannel(ctx, &monitoringpb.CreateNotificationChannelRequest{
+		Name:                resources.GenerateProjectName(source),
+		NotificationChannel: resources.MakeNotificationChannel(source, topic),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource notification channel", zap.Error(err))
+		return "", err
+	}
+	// The name of the channel is generated by Cloud Monitoring, it is recorded
+	// right away so that the channel is not leaked if the policies fail to be
+	// updated.
+	source.Status.NotificationChannel = channel.Name
+	return channel.Name, nil
+}
+
+// attachNotificationChannel adds the notification channel to the alert policy,
+// if it is not already attached to it.
+func (r *Reconciler) attachNotificationChannel(ctx context.Context, client gmonitoring.AlertingClient, policyName, channel string) error {
+	policy, err := client.GetAlertPolicy(ctx, &monitoringpb.GetAlertPolicyRequest{Name: policyName})
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while retrieving alert policy", zap.String("policyName", policyName), zap.Error(err))
+		return err
+	}
+	if !resources.AttachNotificationChannel(policy, channel) {
+		return nil
+	}
+	if _, err := client.UpdateAlertPolicy(ctx, &monitoringpb.UpdateAlertPolicyRequest{
+		AlertPolicy: policy,
+		UpdateMask:  notificationChannelsMask,
+	}); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to attach CloudMonitoringAlertSource notification channel", zap.String("policyName", policyName), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// detachNotificationChannel removes the notification channel from the alert
+// policy, if the policy exists and the channel is attached to it.
+func (r *Reconciler) detachNotificationChannel(ctx context.Context, client gmonitoring.AlertingClient, policyName, channel string) error {
+	policy, err := client.GetAlertPolicy(ctx, &monitoringpb.GetAlertPolicyRequest{Name: policyName})
+	if err != nil {
+		if st, ok := gstatus.FromError(err); ok && st.Code() == codes.NotFound {
+			return nil
+		}
+		logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while retrieving alert policy", zap.String("policyName", policyName), zap.Error(err))
+		return err
+	}
+	if !resources.DetachNotificationChannel(policy, channel) {
+		return nil
+	}
+	if _, err := client.UpdateAlertPolicy(ctx, &monitoringpb.UpdateAlertPolicyRequest{
+		AlertPolicy: policy,
+		UpdateMask:  notificationChannelsMask,
+	}); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to detach CloudMonitoringAlertSource notification channel", zap.String("policyName", policyName), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// deleteNotificationChannel deletes the notification channel in
+// status.NotificationChannel, if it exists. The channel is forcibly deleted,
+// which detaches it from the alert policies.
+func (r *Reconciler) deleteNotificationChannel(ctx context.Context, source *v1.CloudMonitoringAlertSource) error {
+	if source.Status.NotificationChannel == "" {
+		return nil
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource client", zap.Error(err))
+		source.Status.MarkNotificationChannelUnknown(deleteNotificationChannelFailed, "Failed to create CloudMonitoringAlertSource client: %s", err.Error())
+		return err
+	}
+	defer client.Close()
+
+	err = client.DeleteNotificationChannel(ctx, &monitoringpb.DeleteNotificationChannelRequest{
+		Name:  source.Status.NotificationChannel,
+		Force: true,
+	})
+	if err == nil {
+		logging.FromContext(ctx).Desugar().Debug("Deleted CloudMonitoringAlertSource notification channel", zap.String("channelName", source.Status.NotificationChannel))
+		return nil
+	}
+	if st, ok := gstatus.FromError(err); !ok || st.Code() != codes.NotFound {
+		logging.FromContext(ctx).Desugar().Error("Failed to delete CloudMonitoringAlertSource notification channel", zap.String("channelName", source.Status.NotificationChannel), zap.Error(err))
+		source.Status.MarkNotificationChannelUnknown(deleteNotificationChannelFailed, "Failed to delete CloudMonitoringAlertSource notification channel: %s", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, source *v1.CloudMonitoringAlertSource) reconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if source.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, source.Spec.Project, source); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudMonitoringAlertSource workload identity: %s", err.Error())
+		}
+	}
+
+	logging.FromContext(ctx).Desugar().Debug("Deleting CloudMonitoringAlertSource notification channel")
+	if err := r.deleteNotificationChannel(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteNotificationChannelFailed, "Failed to delete CloudMonitoringAlertSource notification channel: %s", err.Error())
+	}
+
+	if err := r.PubSubBase.DeletePubSub(ctx, source); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailed, "Failed to delete CloudMonitoringAlertSource PubSub: %s", err.Error())
+	}
+
+	return nil
+}
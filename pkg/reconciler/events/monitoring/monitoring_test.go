@@ -0,0 +1,665 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	reconcilertestingv1 "github.com/google/knative-gcp/pkg/reconciler/testing/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	. "knative.dev/pkg/reconciler/testing"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	monitoringv1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1/cloudmonitoringalertsource"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+const (
+	sourceName = "my-test-alert"
+	sourceUID  = "test-monitoring-alert-uid"
+	sinkName   = "sink"
+
+	testNS       = "testnamespace"
+	testProject  = "test-project-id"
+	testTopicURI = "http://" + sourceName + "-topic." + testNS + ".svc.cluster.local"
+
+	channelID   = "987"
+	channelName = "projects/" + testProject + "/notificationChannels/" + channelID
+	otherName   = "projects/" + testProject + "/notificationChannels/654"
+
+	policyID        = "123"
+	policyName      = "projects/" + testProject + "/alertPolicies/" + policyID
+	otherPolicyName = "projects/" + testProject + "/alertPolicies/456"
+
+	// Message for when the notification channel with the above variables is not ready.
+	failedToReconcileNotificationChannelMsg = `Failed to reconcile CloudMonitoringAlertSource notification channel`
+	failedToDeleteNotificationChannelMsg    = `Failed to delete CloudMonitoringAlertSource notification channel`
+)
+
+var (
+	falseVal = false
+
+	sinkDNS = sinkName + ".mynamespace.svc.cluster.local"
+	sinkURI = apis.HTTP(sinkDNS)
+
+	testTopicID = fmt.Sprintf("cre-src_%s_%s_%s", testNS, sourceName, sourceUID)
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1",
+		Kind:    "Sink",
+	}
+
+	secret = corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "google-cloud-key",
+		},
+		Key: "key.json",
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = monitoringv1.AddToScheme(scheme.Scheme)
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func newSink() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "testing.cloud.google.com/v1",
+			"kind":       "Sink",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      sinkName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"hostname": sinkDNS,
+				},
+			},
+		},
+	}
+}
+
+func newSinkDestination() duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "testing.cloud.google.com/v1",
+			Kind:       "Sink",
+			Name:       sinkName,
+		},
+	}
+}
+
+// newSource returns the CloudMonitoringAlertSource with the spec used in the tests and the given options.
+func newSource(so ...reconcilertestingv1.CloudMonitoringAlertSourceOption) *monitoringv1.CloudMonitoringAlertSource {
+	return reconcilertestingv1.NewCloudMonitoringAlertSource(sourceName, testNS, append([]reconcilertestingv1.CloudMonitoringAlertSourceOption{
+		reconcilertestingv1.WithCloudMonitoringAlertSourceProject(testProject),
+		reconcilertestingv1.WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+		reconcilertestingv1.WithCloudMonitoringAlertSourceAlertPolicies(policyID),
+		reconcilertestingv1.WithCloudMonitoringAlertSourceSetDefaults,
+	}, so...)...)
+}
+
+// newReadySource returns the CloudMonitoringAlertSource with a ready topic and pullsubscription and the given options.
+func newReadySource(so ...reconcilertestingv1.CloudMonitoringAlertSourceOption) *monitoringv1.CloudMonitoringAlertSource {
+	return newSource(append([]reconcilertestingv1.CloudMonitoringAlertSourceOption{
+		reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+		reconcilertestingv1.WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+		reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionReady,
+		reconcilertestingv1.WithCloudMonitoringAlertSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+		reconcilertestingv1.WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+	}, so...)...)
+}
+
+func newReadyTopic() *inteventsv1.Topic {
+	return reconcilertestingv1.NewTopic(sourceName, testNS,
+		reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+			Topic:             testTopicID,
+			PropagationPolicy: "CreateDelete",
+			Project:           testProject,
+			EnablePublisher:   &falseVal,
+		}),
+		reconcilertestingv1.WithTopicReady(testTopicID),
+		reconcilertestingv1.WithTopicAddress(testTopicURI),
+		reconcilertestingv1.WithTopicProjectID(testProject),
+		reconcilertestingv1.WithTopicSetDefaults,
+	)
+}
+
+func newReadyPullSubscription() *inteventsv1.PullSubscription {
+	return reconcilertestingv1.NewPullSubscription(sourceName, testNS,
+		reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+		reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+			Topic: testTopicID,
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				Secret: &secret,
+				SourceSpec: duckv1.SourceSpec{
+					Sink: newSinkDestination(),
+				},
+				Project: testProject,
+			},
+			AdapterType: string(converters.CloudMonitoringAlert),
+		}),
+	)
+}
+
+func alertPolicy(name string, channels ...string) *monitoringpb.AlertPolicy {
+	return &monitoringpb.AlertPolicy{
+		Name:                 name,
+		NotificationChannels: channels,
+	}
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "topic exists, topic has not yet been reconciled",
+		Objects: []runtime.Object{
+			newSource(),
+			reconcilertestingv1.NewTopic(sourceName, testNS,
+				reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					Project:           testProject,
+					EnablePublisher:   &falseVal,
+				}),
+				reconcilertestingv1.WithTopicUnknown,
+				reconcilertestingv1.WithTopicSetDefaults,
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSource(
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: the status of Topic %q is Unknown", sourceName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create client fails",
+		Objects: []runtime.Object{
+			newSource(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				CreateClientErr: errors.New("create-client-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileNotificationChannelMsg, "create-client-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: create-client-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create notification channel fails",
+		Objects: []runtime.Object{
+			newSource(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				CreateNotificationChannelErr: errors.New("create-channel-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileNotificationChannelMsg, "create-channel-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: create-channel-induced-error"),
+		},
+	}, {
+		Name: "notification channel in status, get notification channel fails with grpc unknown error",
+		Objects: []runtime.Object{
+			newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				GetNotificationChannelErr: gstatus.Error(codes.Unknown, "get-channel-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileNotificationChannelMsg, codes.Unknown, "get-channel-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, fmt.Sprintf("Reconcile NotificationChannel failed with: rpc error: code = %s desc = %s", codes.Unknown, "get-channel-induced-error")),
+		},
+	}, {
+		Name: "notification channel created, alert policy not found",
+		Objects: []runtime.Object{
+			newSource(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				NotificationChannelID: channelID,
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				// The created channel is recorded so that it is not leaked.
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: rpc error: code = %s desc = alert policy %q not found", failedToReconcileNotificationChannelMsg, codes.NotFound, policyName)),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, fmt.Sprintf("Reconcile NotificationChannel failed with: rpc error: code = %s desc = alert policy %q not found", codes.NotFound, policyName)),
+		},
+	}, {
+		Name: "notification channel created, update alert policy fails",
+		Objects: []runtime.Object{
+			newSource(),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				NotificationChannelID: channelID,
+				AlertPolicies: map[string]*monitoringpb.AlertPolicy{
+					policyName: alertPolicy(policyName, otherName),
+				},
+				UpdateAlertPolicyErr: errors.New("update-policy-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileNotificationChannelMsg, "update-policy-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: update-policy-induced-error"),
+		},
+	}, {
+		Name: "notification channel created and attached to alert policies",
+		Objects: []runtime.Object{
+			newSource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceAlertPolicies(policyID, otherPolicyName, policyName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				NotificationChannelID: channelID,
+				AlertPolicies: map[string]*monitoringpb.AlertPolicy{
+					policyName:      alertPolicy(policyName, otherName),
+					otherPolicyName: alertPolicy(otherPolicyName),
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceAlertPolicies(policyID, otherPolicyName, policyName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName, otherPolicyName),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+	}, {
+		Name: "notification channel exists and is attached to the alert policy",
+		Objects: []runtime.Object{
+			newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				NotificationChannels: map[string]*monitoringpb.NotificationChannel{
+					channelName: {Name: channelName},
+				},
+				AlertPolicies: map[string]*monitoringpb.AlertPolicy{
+					policyName: alertPolicy(policyName, channelName),
+				},
+				// Neither the channel nor the policy are up to date, thus they are not changed.
+				CreateNotificationChannelErr: errors.New("create-channel-induced-error"),
+				UpdateAlertPolicyErr:         errors.New("update-policy-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+	}, {
+		Name: "notification channel in status not found, notification channel recreated",
+		Objects: []runtime.Object{
+			newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(otherName, policyName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				NotificationChannelID: channelID,
+				AlertPolicies: map[string]*monitoringpb.AlertPolicy{
+					policyName: alertPolicy(policyName),
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+	}, {
+		Name: "alert policy removed, detach notification channel fails",
+		Objects: []runtime.Object{
+			newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName, otherPolicyName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				NotificationChannels: map[string]*monitoringpb.NotificationChannel{
+					channelName: {Name: channelName},
+				},
+				AlertPolicies: map[string]*monitoringpb.AlertPolicy{
+					policyName:      alertPolicy(policyName, channelName),
+					otherPolicyName: alertPolicy(otherPolicyName, otherName, channelName),
+				},
+				UpdateAlertPolicyErr: gstatus.Error(codes.Unknown, "update-policy-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName, otherPolicyName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileNotificationChannelMsg, codes.Unknown, "update-policy-induced-error")),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, fmt.Sprintf("Reconcile NotificationChannel failed with: rpc error: code = %s desc = %s", codes.Unknown, "update-policy-induced-error")),
+		},
+	}, {
+		Name: "alert policy removed, notification channel detached",
+		Objects: []runtime.Object{
+			newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName, otherPolicyName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				NotificationChannels: map[string]*monitoringpb.NotificationChannel{
+					channelName: {Name: channelName},
+				},
+				AlertPolicies: map[string]*monitoringpb.AlertPolicy{
+					policyName:      alertPolicy(policyName, channelName),
+					otherPolicyName: alertPolicy(otherPolicyName, otherName, channelName),
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+	}, {
+		Name: "removed alert policy not found, ignored",
+		Objects: []runtime.Object{
+			newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName, otherPolicyName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				NotificationChannels: map[string]*monitoringpb.NotificationChannel{
+					channelName: {Name: channelName},
+				},
+				AlertPolicies: map[string]*monitoringpb.AlertPolicy{
+					policyName: alertPolicy(policyName, channelName),
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+	}, {
+		Name: "notification channel fails to delete with Unknown grpc error",
+		Objects: []runtime.Object{
+			newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceDeletionTimestamp,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelUnknown(deleteNotificationChannelFailed,
+					fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToDeleteNotificationChannelMsg, codes.Unknown, "delete-channel-induced-error")),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceDeletionTimestamp,
+			),
+		}},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				DeleteNotificationChannelErr: gstatus.Error(codes.Unknown, "delete-channel-induced-error"),
+			},
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, deleteNotificationChannelFailed, fmt.Sprintf("Failed to delete CloudMonitoringAlertSource notification channel: rpc error: code = %s desc = %s", codes.Unknown, "delete-channel-induced-error")),
+		},
+	}, {
+		Name: "notification channel successfully deleted with NotFound grpc error",
+		Objects: []runtime.Object{
+			newReadySource(
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceDeletionTimestamp,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSource(
+				reconcilertestingv1.WithInitCloudMonitoringAlertSourceConditions,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceNotificationChannelReady(channelName, policyName),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceTopicDeleted,
+				reconcilertestingv1.WithCloudMonitoringAlertSourcePullSubscriptionDeleted,
+				reconcilertestingv1.WithCloudMonitoringAlertSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+				reconcilertestingv1.WithCloudMonitoringAlertSourceDeletionTimestamp,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"}},
+				Name: sourceName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"}},
+				Name: sourceName,
+			},
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestAlertingClientData{
+				DeleteNotificationChannelErr: gstatus.Error(codes.NotFound, "delete-channel-induced-error"),
+			},
+		},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			PubSubBase: intevents.NewPubSubBase(ctx,
+				&intevents.PubSubBaseArgs{
+					ControllerAgentName: controllerAgentName,
+					ReceiveAdapterName:  receiveAdapterName,
+					ReceiveAdapterType:  string(converters.CloudMonitoringAlert),
+					ConfigWatcher:       cmw,
+				}),
+			Identity:       identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			alertLister:    listers.GetCloudMonitoringAlertSourceLister(),
+			createClientFn: gmonitoring.TestAlertingClientCreator(testData["monitoring"]),
+		}
+		return cloudmonitoringalertsource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudMonitoringAlertSourceLister(), r.Recorder, r)
+	}))
+
+}
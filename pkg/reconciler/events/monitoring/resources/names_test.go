@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newSource() *v1.CloudMonitoringAlertSource {
+	return &v1.CloudMonitoringAlertSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "namespace",
+			UID:       "uid",
+		},
+		Spec: v1.CloudMonitoringAlertSourceSpec{
+			AlertPolicies: []string{"123"},
+		},
+		Status: v1.CloudMonitoringAlertSourceStatus{
+			PubSubStatus: duckv1.PubSubStatus{
+				ProjectID: "project",
+			},
+		},
+	}
+}
+
+func TestGenerateProjectName(t *testing.T) {
+	want := "projects/project"
+	got := GenerateProjectName(newSource())
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestGenerateAlertPolicyName(t *testing.T) {
+	testCases := map[string]string{
+		"123":                               "projects/project/alertPolicies/123",
+		"projects/project/alertPolicies/12": "projects/project/alertPolicies/12",
+		"projects/other/alertPolicies/456":  "projects/other/alertPolicies/456",
+	}
+	for policy, want := range testCases {
+		got := GenerateAlertPolicyName(newSource(), policy)
+
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("unexpected name of %q (-want, +got) = %v", policy, diff)
+		}
+	}
+}
+
+func TestGenerateTopicName(t *testing.T) {
+	want := "cre-src_namespace_name_uid"
+	got := GenerateTopicName(newSource())
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestGenerateNotificationChannelTopic(t *testing.T) {
+	want := "projects/project/topics/topic"
+	got := GenerateNotificationChannelTopic(newSource(), "topic")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+const (
+	// notificationChannelType is the type of the notification channels which
+	// publish the incidents to a Pub/Sub topic.
+	notificationChannelType = "pubsub"
+	// notificationChannelTopicLabel is the label of the Pub/Sub notification
+	// channels with the full name of the topic.
+	notificationChannelTopicLabel = "topic"
+)
+
+// MakeNotificationChannel generates (but does not create) the Pub/Sub
+// notification channel of the CloudMonitoringAlertSource.
+func MakeNotificationChannel(source *v1.CloudMonitoringAlertSource, topic string) *monitoringpb.NotificationChannel {
+	return &monitoringpb.NotificationChannel{
+		Type:        notificationChannelType,
+		DisplayName: fmt.Sprintf("CloudMonitoringAlertSource %s/%s", source.Namespace, source.Name),
+		Labels: map[string]string{
+			notificationChannelTopicLabel: GenerateNotificationChannelTopic(source, topic),
+		},
+	}
+}
+
+// AttachNotificationChannel adds the notification channel to the alert policy.
+// It returns false if the channel was already attached to it.
+func AttachNotificationChannel(policy *monitoringpb.AlertPolicy, channel string) bool {
+	for _, c := range policy.NotificationChannels {
+		if c == channel {
+			return false
+		}
+	}
+	policy.NotificationChannels = append(policy.NotificationChannels, channel)
+	return true
+}
+
+// DetachNotificationChannel removes the notification channel from the alert
+// policy. It returns false if the channel was not attached to it.
+func DetachNotificationChannel(policy *monitoringpb.AlertPolicy, channel string) bool {
+	channels := make([]string, 0, len(policy.NotificationChannels))
+	for _, c := range policy.NotificationChannels {
+		if c != channel {
+			channels = append(channels, c)
+		}
+	}
+	if len(channels) == len(policy.NotificationChannels) {
+		return false
+	}
+	policy.NotificationChannels = channels
+	return true
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestMakeNotificationChannel(t *testing.T) {
+	want := &monitoringpb.NotificationChannel{
+		Type:        "pubsub",
+		DisplayName: "CloudMonitoringAlertSource namespace/name",
+		Labels: map[string]string{
+			"topic": "projects/project/topics/topic",
+		},
+	}
+	got := MakeNotificationChannel(newSource(), "topic")
+
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestAttachNotificationChannel(t *testing.T) {
+	testCases := map[string]struct {
+		channels []string
+		want     []string
+		changed  bool
+	}{
+		"no channels": {
+			want:    []string{"channel"},
+			changed: true,
+		},
+		"other channels": {
+			channels: []string{"other"},
+			want:     []string{"other", "channel"},
+			changed:  true,
+		},
+		"already attached": {
+			channels: []string{"channel", "other"},
+			want:     []string{"channel", "other"},
+			changed:  false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			policy := &monitoringpb.AlertPolicy{NotificationChannels: tc.channels}
+			if got := AttachNotificationChannel(policy, "channel"); got != tc.changed {
+				t.Errorf("AttachNotificationChannel() = %v, want %v", got, tc.changed)
+			}
+			if diff := cmp.Diff(tc.want, policy.NotificationChannels); diff != "" {
+				t.Errorf("unexpected channels (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDetachNotificationChannel(t *testing.T) {
+	testCases := map[string]struct {
+		channels []string
+		want     []string
+		changed  bool
+	}{
+		"no channels": {
+			changed: false,
+		},
+		"other channels": {
+			channels: []string{"other"},
+			want:     []string{"other"},
+			changed:  false,
+		},
+		"attached": {
+			channels: []string{"other", "channel", "another"},
+			want:     []string{"other", "another"},
+			changed:  true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			policy := &monitoringpb.AlertPolicy{NotificationChannels: tc.channels}
+			if got := DetachNotificationChannel(policy, "channel"); got != tc.changed {
+				t.Errorf("DetachNotificationChannel() = %v, want %v", got, tc.changed)
+			}
+			if diff := cmp.Diff(tc.want, policy.NotificationChannels); diff != "" {
+				t.Errorf("unexpected channels (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/base64"
+	"time"
+
+	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// JobUpdateMask is the mask of the fields of a Job configured by its
+// CloudSchedulerSource.
+var JobUpdateMask = &fieldmaskpb.FieldMask{
+	Paths: []string{"schedule", "time_zone", "pubsub_target", "retry_config"},
+}
+
+// The defaults of Cloud Scheduler for the fields of a Job in JobUpdateMask,
+// see https://cloud.google.com/scheduler/docs/reference/rest/v1/projects.locations.jobs.
+const (
+	defaultTimeZone           = "Etc/UTC"
+	defaultMinBackoffDuration = 5 * time.Second
+	defaultMaxBackoffDuration = time.Hour
+	defaultMaxDoublings       = 5
+)
+
+// MakeJob generates (but does not create) the Job of the CloudSchedulerSource.
+// The fields of the Job in JobUpdateMask left unset by the CloudSchedulerSource
+// are set to the defaults of Cloud Scheduler, so that unsetting them in the
+// CloudSchedulerSource resets them in an existing Job.
+func MakeJob(scheduler *v1.CloudSchedulerSource, jobName, topic string) (*schedulerpb.Job, error) {
+	data := []byte(scheduler.Spec.Data)
+	if scheduler.Spec.DataBase64 != "" {
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(scheduler.Spec.DataBase64); err != nil {
+			return nil, err
+		}
+	}
+
+	attributes := make(map[string]string, len(scheduler.Spec.Attributes)+1)
+	for k, v := range scheduler.Spec.Attributes {
+		attributes[k] = v
+	}
+	// Add jobName as customAttribute.
+	attributes[v1.CloudSchedulerSourceJobName] = jobName
+
+	job := &schedulerpb.Job{
+		Name: jobName,
+		Target: &schedulerpb.Job_PubsubTarget{
+			PubsubTarget: &schedulerpb.PubsubTarget{
+				TopicName:  GeneratePubSubTargetTopic(scheduler, topic),
+				Data:       data,
+				Attributes: attributes,
+			},
+		},
+		Schedule: scheduler.Spec.Schedule,
+		TimeZone: scheduler.Spec.TimeZone,
+		RetryConfig: &schedulerpb.RetryConfig{
+			MaxRetryDuration:   durationpb.New(0),
+			MinBackoffDuration: durationpb.New(defaultMinBackoffDuration),
+			MaxBackoffDuration: durationpb.New(defaultMaxBackoffDuration),
+			MaxDoublings:       defaultMaxDoublings,
+		},
+	}
+	if job.TimeZone == "" {
+		job.TimeZone = defaultTimeZone
+	}
+	if rc := scheduler.Spec.RetryConfig; rc != nil {
+		if rc.RetryCount != nil {
+			job.RetryConfig.RetryCount = *rc.RetryCount
+		}
+		if rc.MaxRetryDuration != nil {
+			job.RetryConfig.MaxRetryDuration = durationProto(*rc.MaxRetryDuration)
+		}
+		if rc.MinBackoffDuration != nil {
+			job.RetryConfig.MinBackoffDuration = durationProto(*rc.MinBackoffDuration)
+		}
+		if rc.MaxBackoffDuration != nil {
+			job.RetryConfig.MaxBackoffDuration = durationProto(*rc.MaxBackoffDuration)
+		}
+		if rc.MaxDoublings != nil {
+			job.RetryConfig.MaxDoublings = *rc.MaxDoublings
+		}
+	}
+	return job, nil
+}
+
+// JobChanged returns true if the fields of the Job in JobUpdateMask differ
+// from the ones of the existing Job.
+func JobChanged(existing, job *schedulerpb.Job) bool {
+	return existing.Schedule != job.Schedule ||
+		existing.TimeZone != job.TimeZone ||
+		!proto.Equal(existing.GetPubsubTarget(), job.GetPubsubTarget()) ||
+		!retryConfigEqual(existing.GetRetryConfig(), job.GetRetryConfig())
+}
+
+// retryConfigEqual returns true if the retry configs have the same values. A
+// zero duration may read back unset.
+func retryConfigEqual(a, b *schedulerpb.RetryConfig) bool {
+	return a.GetRetryCount() == b.GetRetryCount() &&
+		a.GetMaxRetryDuration().AsDuration() == b.GetMaxRetryDuration().AsDuration() &&
+		a.GetMinBackoffDuration().AsDuration() == b.GetMinBackoffDuration().AsDuration() &&
+		a.GetMaxBackoffDuration().AsDuration() == b.GetMaxBackoffDuration().AsDuration() &&
+		a.GetMaxDoublings() == b.GetMaxDoublings()
+}
+
+// durationProto converts a duration validated by the webhook.
+func durationProto(duration string) *durationpb.Duration {
+	d, _ := time.ParseDuration(duration)
+	return durationpb.New(d)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"knative.dev/pkg/ptr"
+
+	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+const (
+	testJobName = "projects/project/locations/location/jobs/job"
+	testTopic   = "topic"
+)
+
+func newScheduler(spec v1.CloudSchedulerSourceSpec) *v1.CloudSchedulerSource {
+	return &v1.CloudSchedulerSource{
+		Spec: spec,
+		Status: v1.CloudSchedulerSourceStatus{
+			PubSubStatus: duckv1.PubSubStatus{
+				ProjectID: "project",
+			},
+		},
+	}
+}
+
+func TestMakeJob(t *testing.T) {
+	testCases := []struct {
+		name    string
+		spec    v1.CloudSchedulerSourceSpec
+		want    *schedulerpb.Job
+		wantErr bool
+	}{{
+		name: "minimal",
+		spec: v1.CloudSchedulerSourceSpec{
+			Schedule: "* * * * *",
+			Data:     "data",
+		},
+		want: &schedulerpb.Job{
+			Name:     testJobName,
+			Schedule: "* * * * *",
+			TimeZone: "Etc/UTC",
+			Target: &schedulerpb.Job_PubsubTarget{
+				PubsubTarget: &schedulerpb.PubsubTarget{
+					TopicName:  "projects/project/topics/topic",
+					Data:       []byte("data"),
+					Attributes: map[string]string{v1.CloudSchedulerSourceJobName: testJobName},
+				},
+			},
+			RetryConfig: &schedulerpb.RetryConfig{
+				MaxRetryDuration:   durationpb.New(0),
+				MinBackoffDuration: durationpb.New(5 * time.Second),
+				MaxBackoffDuration: durationpb.New(time.Hour),
+				MaxDoublings:       5,
+			},
+		},
+	}, {
+		name: "full",
+		spec: v1.CloudSchedulerSourceSpec{
+			Schedule:   "* * * * *",
+			TimeZone:   "America/New_York",
+			DataBase64: "AAEC",
+			Attributes: map[string]string{"team": "a"},
+			RetryConfig: &v1.CloudSchedulerRetryConfig{
+				RetryCount:         ptr.Int32(3),
+				MaxRetryDuration:   ptr.String("1h"),
+				MinBackoffDuration: ptr.String("5s"),
+				MaxBackoffDuration: ptr.String("1m"),
+				MaxDoublings:       ptr.Int32(4),
+			},
+		},
+		want: &schedulerpb.Job{
+			Name:     testJobName,
+			Schedule: "* * * * *",
+			TimeZone: "America/New_York",
+			Target: &schedulerpb.Job_PubsubTarget{
+				PubsubTarget: &schedulerpb.PubsubTarget{
+					TopicName: "projects/project/topics/topic",
+					Data:      []byte{0, 1, 2},
+					Attributes: map[string]string{
+						"team":                         "a",
+						v1.CloudSchedulerSourceJobName: testJobName,
+					},
+				},
+			},
+			RetryConfig: &schedulerpb.RetryConfig{
+				RetryCount:         3,
+				MaxRetryDuration:   durationpb.New(time.Hour),
+				MinBackoffDuration: durationpb.New(5 * time.Second),
+				MaxBackoffDuration: durationpb.New(time.Minute),
+				MaxDoublings:       4,
+			},
+		},
+	}, {
+		name: "unset fields reset to the defaults",
+		spec: v1.CloudSchedulerSourceSpec{
+			Schedule: "* * * * *",
+			Data:     "data",
+			RetryConfig: &v1.CloudSchedulerRetryConfig{
+				RetryCount: ptr.Int32(2),
+			},
+		},
+		want: &schedulerpb.Job{
+			Name:     testJobName,
+			Schedule: "* * * * *",
+			TimeZone: "Etc/UTC",
+			Target: &schedulerpb.Job_PubsubTarget{
+				PubsubTarget: &schedulerpb.PubsubTarget{
+					TopicName:  "projects/project/topics/topic",
+					Data:       []byte("data"),
+					Attributes: map[string]string{v1.CloudSchedulerSourceJobName: testJobName},
+				},
+			},
+			RetryConfig: &schedulerpb.RetryConfig{
+				RetryCount:         2,
+				MaxRetryDuration:   durationpb.New(0),
+				MinBackoffDuration: durationpb.New(5 * time.Second),
+				MaxBackoffDuration: durationpb.New(time.Hour),
+				MaxDoublings:       5,
+			},
+		},
+	}, {
+		name: "invalid base64 data",
+		spec: v1.CloudSchedulerSourceSpec{
+			Schedule:   "* * * * *",
+			DataBase64: "not base64",
+		},
+		wantErr: true,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MakeJob(newScheduler(tc.spec), testJobName, testTopic)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("MakeJob error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestJobChanged(t *testing.T) {
+	spec := v1.CloudSchedulerSourceSpec{
+		Schedule: "* * * * *",
+		Data:     "data",
+	}
+	existing, err := MakeJob(newScheduler(spec), testJobName, testTopic)
+	if err != nil {
+		t.Fatalf("MakeJob failed: %v", err)
+	}
+	existing.State = schedulerpb.Job_ENABLED
+	// Cloud Scheduler doesn't return zero durations.
+	existing.RetryConfig.MaxRetryDuration = nil
+
+	same, _ := MakeJob(newScheduler(spec), testJobName, testTopic)
+	if JobChanged(existing, same) {
+		t.Error("JobChanged = true for an unchanged spec, want false")
+	}
+
+	spec.Data = "other data"
+	changed, _ := MakeJob(newScheduler(spec), testJobName, testTopic)
+	if !JobChanged(existing, changed) {
+		t.Error("JobChanged = false for a changed data, want true")
+	}
+
+	spec.Data = "data"
+	existing.TimeZone = "America/New_York"
+	existing.RetryConfig.RetryCount = 3
+	reset, _ := MakeJob(newScheduler(spec), testJobName, testTopic)
+	if !JobChanged(existing, reset) {
+		t.Error("JobChanged = false for unset time zone and retry count, want true")
+	}
+}
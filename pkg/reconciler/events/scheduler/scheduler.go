@@ -111,41 +111,63 @@ func (r *Reconciler) reconcileJob(ctx context.Context, scheduler *v1.CloudSchedu
 	defer client.Close()
 
 	// Check if the job exists.
-	_, err = client.GetJob(ctx, &schedulerpb.GetJobRequest{Name: jobName})
+	job, err := client.GetJob(ctx, &schedulerpb.GetJobRequest{Name: jobName})
 	if err != nil {
 		if st, ok := gstatus.FromError(err); !ok {
 			logging.FromContext(ctx).Desugar().Error("Failed from CloudSchedulerSource client while retrieving CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
 			return err
-		} else if st.Code() == codes.NotFound {
-			// Create the job as it does not exist. For creation, we need a parent, extract it from the jobName.
-			parent := resources.ExtractParentName(jobName)
-			// Add jobName as customAttribute.
-			customAttributes := map[string]string{
-				v1.CloudSchedulerSourceJobName: jobName,
-			}
-			_, err = client.CreateJob(ctx, &schedulerpb.CreateJobRequest{
-				Parent: parent,
-				Job: &schedulerpb.Job{
-					Name: jobName,
-					Target: &schedulerpb.Job_PubsubTarget{
-						PubsubTarget: &schedulerpb.PubsubTarget{
-							TopicName:  resources.GeneratePubSubTargetTopic(scheduler, topic),
-							Data:       []byte(scheduler.Spec.Data),
-							Attributes: customAttributes,
-						},
-					},
-					Schedule: scheduler.Spec.Schedule,
-				},
+		} else if st.Code() != codes.NotFound {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudSchedulerSource client while retrieving CloudSchedulerSource job", zap.String("jobName", jobName), zap.Any("errorCode", st.Code()), zap.Error(err))
+			return err
+		}
+		// Create the job as it does not exist. For creation, we need a parent, extract it from the jobName.
+		parent := resources.ExtractParentName(jobName)
+		want, err := resources.MakeJob(scheduler, jobName, topic)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to generate CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
+			return err
+		}
+		job, err = client.CreateJob(ctx, &schedulerpb.CreateJobRequest{
+			Parent: parent,
+			Job:    want,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to create CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
+			return err
+		}
+	} else {
+		want, err := resources.MakeJob(scheduler, jobName, topic)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to generate CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
+			return err
+		}
+		// Push the changes of the spec with a job update.
+		if resources.JobChanged(job, want) {
+			job, err = client.UpdateJob(ctx, &schedulerpb.UpdateJobRequest{
+				Job:        want,
+				UpdateMask: resources.JobUpdateMask,
 			})
 			if err != nil {
-				logging.FromContext(ctx).Desugar().Error("Failed to create CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
+				logging.FromContext(ctx).Desugar().Error("Failed to update CloudSchedulerSource job", zap.String("jobName", jobName), zap.Error(err))
 				return err
 			}
-		} else {
-			logging.FromContext(ctx).Desugar().Error("Failed from CloudSchedulerSource client while retrieving CloudSchedulerSource job", zap.String("jobName", jobName), zap.Any("errorCode", st.Code()), zap.Error(err))
-			return err
 		}
 	}
+	return r.reconcileJobState(ctx, client, scheduler, job)
+}
+
+// reconcileJobState pauses or resumes the job according to the spec.
+func (r *Reconciler) reconcileJobState(ctx context.Context, client gscheduler.Client, scheduler *v1.CloudSchedulerSource, job *schedulerpb.Job) error {
+	var err error
+	if scheduler.Spec.Paused && job.State != schedulerpb.Job_PAUSED {
+		_, err = client.PauseJob(ctx, &schedulerpb.PauseJobRequest{Name: job.Name})
+	} else if !scheduler.Spec.Paused && job.State == schedulerpb.Job_PAUSED {
+		_, err = client.ResumeJob(ctx, &schedulerpb.ResumeJobRequest{Name: job.Name})
+	}
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to change the state of CloudSchedulerSource job", zap.String("jobName", job.Name), zap.Bool("paused", scheduler.Spec.Paused), zap.Error(err))
+		return err
+	}
 	return nil
 }
 
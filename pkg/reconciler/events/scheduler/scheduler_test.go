@@ -51,6 +51,7 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 
+	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
 	"google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
 )
@@ -1110,6 +1111,148 @@ func TestAllCases(t *testing.T) {
 				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", schedulerName),
 				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSchedulerSource reconciled: "%s/%s"`, testNS, schedulerName),
 			},
+		}, {
+			Name: "topic and pullsubscription exist and ready, job schedule changed, update job fails",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(schedulerName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(schedulerName, testNS,
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Secret: &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+							Project: testProject,
+						},
+						AdapterType: string(converters.CloudScheduler),
+					}),
+				),
+				newSink(),
+			},
+			OtherTestData: map[string]interface{}{
+				"scheduler": gscheduler.TestClientData{
+					Job: &schedulerpb.Job{
+						Name:     jobName,
+						Schedule: "0 0 * * *",
+					},
+					UpdateJobErr: errors.New("update-job-induced-error"),
+				},
+			},
+			Key: testNS + "/" + schedulerName,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithInitCloudSchedulerSourceConditions,
+					reconcilertestingv1.WithCloudSchedulerSourceTopicReady(testTopicID, testProject),
+					reconcilertestingv1.WithCloudSchedulerSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudSchedulerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudSchedulerSourceJobNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileJobMsg, "update-job-induced-error")),
+					reconcilertestingv1.WithCloudSchedulerSourceSinkURI(schedulerSinkURL),
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+			}},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, schedulerName, true),
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", schedulerName),
+				Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Job failed with: update-job-induced-error"),
+			},
+		}, {
+			Name: "topic and pullsubscription exist and ready, job exists, pause job fails",
+			Objects: []runtime.Object{
+				reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithCloudSchedulerSourcePaused,
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+				reconcilertestingv1.NewTopic(schedulerName, testNS,
+					reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					reconcilertestingv1.WithTopicReady(testTopicID),
+					reconcilertestingv1.WithTopicAddress(testTopicURI),
+					reconcilertestingv1.WithTopicProjectID(testProject),
+					reconcilertestingv1.WithTopicSetDefaults,
+				),
+				reconcilertestingv1.NewPullSubscription(schedulerName, testNS,
+					reconcilertestingv1.WithPullSubscriptionReady(sinkURI),
+					reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: gcpduckv1.PubSubSpec{
+							Secret: &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+							Project: testProject,
+						},
+						AdapterType: string(converters.CloudScheduler),
+					}),
+				),
+				newSink(),
+			},
+			OtherTestData: map[string]interface{}{
+				"scheduler": gscheduler.TestClientData{
+					PauseJobErr: errors.New("pause-job-induced-error"),
+				},
+			},
+			Key: testNS + "/" + schedulerName,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertestingv1.NewCloudSchedulerSource(schedulerName, testNS,
+					reconcilertestingv1.WithCloudSchedulerSourceProject(testProject),
+					reconcilertestingv1.WithCloudSchedulerSourceSink(sinkGVK, sinkName),
+					reconcilertestingv1.WithCloudSchedulerSourceLocation(location),
+					reconcilertestingv1.WithCloudSchedulerSourceData(testData),
+					reconcilertestingv1.WithCloudSchedulerSourceSchedule(onceAMinuteSchedule),
+					reconcilertestingv1.WithCloudSchedulerSourcePaused,
+					reconcilertestingv1.WithInitCloudSchedulerSourceConditions,
+					reconcilertestingv1.WithCloudSchedulerSourceTopicReady(testTopicID, testProject),
+					reconcilertestingv1.WithCloudSchedulerSourcePullSubscriptionReady,
+					reconcilertestingv1.WithCloudSchedulerSourceSubscriptionID(reconcilertestingv1.SubscriptionID),
+					reconcilertestingv1.WithCloudSchedulerSourceJobNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileJobMsg, "pause-job-induced-error")),
+					reconcilertestingv1.WithCloudSchedulerSourceSinkURI(schedulerSinkURL),
+					reconcilertestingv1.WithCloudSchedulerSourceSetDefaults,
+				),
+			}},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, schedulerName, true),
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", schedulerName),
+				Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Job failed with: pause-job-induced-error"),
+			},
 		}, {
 			Name: "scheduler job fails to delete with no-grpc error",
 			Objects: []runtime.Object{
@@ -537,6 +537,83 @@ func TestAllCases(t *testing.T) {
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
 			Eventf(corev1.EventTypeWarning, reconciledPubSubFailed, fmt.Sprintf("%s: %s: PullSubscription %q has not yet been reconciled", failedToReconcilePubSubMsg, failedToPropagatePullSubscriptionStatusMsg, storageName)),
 		},
+	}, {
+		Name: "topic exists and is ready, filtered pullsubscription created",
+		Objects: []runtime.Object{
+			reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+				reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+				reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+				reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudStorageSourceFilter(&storagev1.CloudStorageObjectFilter{
+					NameSuffixes: []string{".png"},
+				}),
+				reconcilertestingv1.WithCloudStorageSourceAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+			),
+			reconcilertestingv1.NewTopic(storageName, testNS,
+				reconcilertestingv1.WithTopicSpec(inteventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				reconcilertestingv1.WithTopicReady(testTopicID),
+				reconcilertestingv1.WithTopicAddress(testTopicURI),
+				reconcilertestingv1.WithTopicProjectID(testProject),
+				reconcilertestingv1.WithTopicSetDefaults,
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + storageName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewCloudStorageSource(storageName, testNS,
+				reconcilertestingv1.WithCloudStorageSourceObjectMetaGeneration(generation),
+				reconcilertestingv1.WithCloudStorageSourceStatusObservedGeneration(generation),
+				reconcilertestingv1.WithCloudStorageSourceBucket(bucket),
+				reconcilertestingv1.WithCloudStorageSourceSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithCloudStorageSourceFilter(&storagev1.CloudStorageObjectFilter{
+					NameSuffixes: []string{".png"},
+				}),
+				reconcilertestingv1.WithInitCloudStorageSourceConditions,
+				reconcilertestingv1.WithCloudStorageSourceTopicReady(testTopicID),
+				reconcilertestingv1.WithCloudStorageSourceProjectID(testProject),
+				reconcilertestingv1.WithCloudStorageSourceAnnotations(map[string]string{
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				reconcilertestingv1.WithCloudStorageSourceSetDefaults,
+				reconcilertestingv1.WithCloudStorageSourcePullSubscriptionUnknown("PullSubscriptionNotConfigured", failedToReconcilepullSubscriptionMsg),
+			),
+		}},
+		WantCreates: []runtime.Object{
+			reconcilertestingv1.NewPullSubscription(storageName, testNS,
+				reconcilertestingv1.WithPullSubscriptionSpec(inteventsv1.PullSubscriptionSpec{
+					Topic: testTopicID,
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Secret: &secret,
+					},
+					AdapterType: string(converters.CloudStorage),
+				}),
+				reconcilertestingv1.WithPullSubscriptionSink(sinkGVK, sinkName),
+				reconcilertestingv1.WithPullSubscriptionLabels(map[string]string{
+					"receive-adapter": receiveAdapterName,
+					SourceLabelKey:    storageName,
+				}),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+					"metrics-resource-group":   resourceGroup,
+					duck.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					AdapterFilterAnnotation:    `{"nameSuffixes":[".png"]}`,
+				}),
+				reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, storageName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+			Eventf(corev1.EventTypeWarning, reconciledPubSubFailed, fmt.Sprintf("%s: %s: PullSubscription %q has not yet been reconciled", failedToReconcilePubSubMsg, failedToPropagatePullSubscriptionStatusMsg, storageName)),
+		},
 	}, {
 		Name: "topic exists and ready, pullsubscription exists but has not yet been reconciled",
 		Objects: []runtime.Object{
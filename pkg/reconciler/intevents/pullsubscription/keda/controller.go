@@ -33,11 +33,13 @@ import (
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1/resource"
 	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1/pullsubscription"
 	pullsubscriptionreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1/pullsubscription"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
 
 	eventingduck "knative.dev/eventing/pkg/duck"
@@ -109,12 +111,14 @@ func newController(
 			PullSubscriptionLister: pullSubscriptionLister,
 			ReceiveAdapterImage:    env.ReceiveAdapter,
 			CreateClientFn:         pubsub.NewClient,
+			BacklogReporter:        backlog.NewReporter(ctx, gmonitoring.NewClient),
 			ControllerAgentName:    controllerAgentName,
 			ResourceGroup:          resourceGroup,
 		},
 	}
 
 	impl := pullsubscriptionreconciler.NewImpl(ctx, r)
+	r.EnqueueAfter = impl.EnqueueAfter
 
 	pubsubBase.Logger.Info("Setting up event handlers")
 	onlyKedaScaler := pkgreconciler.AnnotationFilterFunc(duck.AutoscalingClassAnnotation, duck.KEDA, false)
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/google/knative-gcp/pkg/apis/duck"
 	v1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	pullsubscriptionreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1/pullsubscription"
 	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
@@ -78,8 +79,13 @@ func (r *Reconciler) ReconcileScaledObject(ctx context.Context, ra *appsv1.Deplo
 		return err
 	}
 	// Given than the Deployment replicas will be controlled by Keda, we assume
-	// the replica count from the existing one is the correct one.
-	ra.Spec.Replicas = existing.Spec.Replicas
+	// the replica count from the existing one is the correct one. The receive
+	// adapter of a paused PullSubscription is not scaled by Keda and keeps
+	// zero replicas.
+	paused := duck.IsPaused(src.Annotations)
+	if !paused {
+		ra.Spec.Replicas = existing.Spec.Replicas
+	}
 	if !equality.Semantic.DeepEqual(ra.Spec, existing.Spec) {
 		existing.Spec = ra.Spec
 		existing, err = r.KubeClientSet.AppsV1().Deployments(src.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
@@ -125,6 +131,17 @@ func (r *Reconciler) ReconcileScaledObject(ctx context.Context, ra *appsv1.Deplo
 		return fmt.Errorf("unable to create dynamic client for ScaledObject")
 	}
 
+	if paused {
+		// Delete the ScaledObject so that Keda doesn't scale the receive adapter
+		// back up. It is recreated once the PullSubscription is resumed.
+		err := scaledObjectResourceInterface.Delete(ctx, resources.GenerateScaledObjectName(src), metav1.DeleteOptions{})
+		if err != nil && !apierrs.IsNotFound(err) {
+			logging.FromContext(ctx).Desugar().Error("Failed to delete ScaledObject", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
 	so := resources.MakeScaledObject(ctx, existing, src)
 
 	apiVersion, kind := resources.ScaledObjectGVK.ToAPIVersionAndKind()
@@ -57,12 +57,14 @@ import (
 	pubsubv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1/resource"
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1/pullsubscription"
+	gmonitoringtesting "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
 	. "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
 )
 
@@ -1231,6 +1233,7 @@ func TestAllCases(t *testing.T) {
 				UriResolver:            resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 				ReceiveAdapterImage:    testImage,
 				CreateClientFn:         createClientFn,
+				BacklogReporter:        backlog.NewReporter(ctx, gmonitoringtesting.TestClientCreator(testData["monitoring"])),
 				ControllerAgentName:    controllerAgentName,
 				ResourceGroup:          resourceGroup,
 			},
@@ -43,11 +43,13 @@ import (
 	"knative.dev/pkg/resolver"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
+	"github.com/google/knative-gcp/pkg/apis/duck"
 	v1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	listers "github.com/google/knative-gcp/pkg/client/listers/intevents/v1"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
 	"github.com/google/knative-gcp/pkg/tracing"
 )
@@ -97,6 +99,13 @@ type Base struct {
 
 	// ReconcileDataPlaneFn is the function used to reconcile the data plane resources.
 	ReconcileDataPlaneFn ReconcileDataPlaneFunc
+
+	// BacklogReporter reports the backlog of the subscription.
+	BacklogReporter *backlog.Reporter
+
+	// EnqueueAfter enqueues a PullSubscription after a delay. It is used to
+	// poll the backlog of the subscription.
+	EnqueueAfter func(obj interface{}, after time.Duration)
 }
 
 // ReconcileDataPlaneFunc is used to reconcile the data plane component(s).
@@ -139,6 +148,18 @@ func (r *Base) ReconcileKind(ctx context.Context, ps *v1.PullSubscription) recon
 		ps.Status.TransformerURI = nil
 	}
 
+	// Reply is optional.
+	if ps.Spec.Reply != nil {
+		replyURI, err := r.resolveDestination(ctx, *ps.Spec.Reply, ps)
+		if err != nil {
+			ps.Status.MarkNoSink("InvalidReply", err.Error())
+			return reconciler.NewEvent(corev1.EventTypeWarning, "InvalidReply", "InvalidReply: %s", err.Error())
+		}
+		ps.Status.ReplyURI = replyURI
+	} else {
+		ps.Status.ReplyURI = nil
+	}
+
 	subscriptionID, err := r.reconcileSubscription(ctx, ps)
 	if err != nil {
 		ps.Status.MarkNoSubscription(reconciledPubSubFailedReason, "Failed to reconcile Pub/Sub subscription: %s", err.Error())
@@ -146,6 +167,18 @@ func (r *Base) ReconcileKind(ctx context.Context, ps *v1.PullSubscription) recon
 	}
 	ps.Status.MarkSubscribed(subscriptionID)
 
+	// The receive adapter of a paused PullSubscription is scaled to zero, see
+	// resources.MakeReceiveAdapter.
+	if duck.IsPaused(ps.Annotations) {
+		ps.Status.MarkPaused()
+	} else {
+		ps.Status.ClearPausedCondition()
+	}
+
+	if r.BacklogReporter.Report(ctx, ps.Annotations, &ps.Status, ps.Status.ProjectID, subscriptionID) && r.EnqueueAfter != nil {
+		r.EnqueueAfter(ps, backlog.ResyncPeriod)
+	}
+
 	err = r.reconcileDataPlaneResources(ctx, ps, r.ReconcileDataPlaneFn)
 	if err != nil {
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledDataPlaneFailedReason, "Failed to reconcile Data Plane resource(s): %s", err.Error())
@@ -220,6 +253,13 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 		subConfig.RetentionDuration = retentionDuration
 	}
 
+	retryPolicy := reconcilerutilspubsub.RetryPolicy(ps.Spec.Delivery)
+	deadLetterPolicy := reconcilerutilspubsub.DeadLetterPolicy(ps.Status.ProjectID, ps.Spec.Delivery)
+	if ps.Spec.Delivery != nil {
+		subConfig.RetryPolicy = retryPolicy
+		subConfig.DeadLetterPolicy = deadLetterPolicy
+	}
+
 	// Check if the topic of the subscription is "_deleted-topic_"
 	if subExists {
 		config, err := sub.Config(ctx)
@@ -240,6 +280,15 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 				logging.FromContext(ctx).Desugar().Error("Failed to create subscription", zap.Error(err))
 				return "", err
 			}
+		} else if !reconcilerutilspubsub.RetryPolicyEqual(config.RetryPolicy, retryPolicy) || !reconcilerutilspubsub.DeadLetterPolicyEqual(config.DeadLetterPolicy, deadLetterPolicy) {
+			// Update the subscription config in case the retry or dead letter policy changed.
+			if _, err := sub.Update(ctx, pubsub.SubscriptionConfigToUpdate{
+				RetryPolicy:      retryPolicy,
+				DeadLetterPolicy: deadLetterPolicy,
+			}); err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to update subscription config", zap.Error(err))
+				return "", err
+			}
 		}
 	} else {
 		sub, err = client.CreateSubscription(ctx, subID, subConfig)
@@ -248,7 +297,6 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1.PullSubscriptio
 			return "", err
 		}
 	}
-	// TODO update the subscription's config if needed.
 	return subID, nil
 }
 
@@ -328,6 +376,7 @@ func (r *Base) reconcileDataPlaneResources(ctx context.Context, ps *v1.PullSubsc
 		SubscriptionID:   ps.Status.SubscriptionID,
 		SinkURI:          ps.Status.SinkURI,
 		TransformerURI:   ps.Status.TransformerURI,
+		ReplyURI:         ps.Status.ReplyURI,
 		LoggingConfig:    loggingConfig,
 		MetricsConfig:    metricsConfig,
 		TracingConfig:    tracingConfig,
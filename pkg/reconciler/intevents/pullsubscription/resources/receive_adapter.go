@@ -18,6 +18,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -31,6 +32,8 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
 	"github.com/google/knative-gcp/pkg/apis/intevents"
 	intereventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
@@ -51,6 +54,7 @@ type ReceiveAdapterArgs struct {
 	SubscriptionID   string
 	SinkURI          *apis.URL
 	TransformerURI   *apis.URL
+	ReplyURI         *apis.URL
 	MetricsConfig    string
 	LoggingConfig    string
 	TracingConfig    string
@@ -191,6 +195,41 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 	receiveAdapterContainer.Env = testloggingutil.PropagateLoggingE2ETestAnnotation(
 		args.PullSubscription.Annotations, receiveAdapterContainer.Env)
 
+	// The events of some sources are filtered by the receive adapter.
+	if filter, ok := args.PullSubscription.Annotations[intevents.AdapterFilterAnnotation]; ok {
+		receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+			Name:  "ADAPTER_FILTER",
+			Value: filter,
+		})
+	}
+
+	if mode := args.PullSubscription.Spec.Mode; mode != "" {
+		receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+			Name:  "SEND_MODE",
+			Value: string(sendMode(mode)),
+		})
+	}
+
+	if args.ReplyURI != nil {
+		receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+			Name:  "REPLY_URI",
+			Value: args.ReplyURI.String(),
+		})
+	}
+
+	if mapping := args.PullSubscription.Spec.Mapping; mapping != nil {
+		if b, err := json.Marshal(mapping); err != nil {
+			logging.FromContext(ctx).Warnw("failed to encode the event mapping",
+				zap.Error(err),
+				zap.Any("mapping", mapping))
+		} else {
+			receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, corev1.EnvVar{
+				Name:  "EVENT_MAPPING",
+				Value: string(b),
+			})
+		}
+	}
+
 	// If there is no secret to embed, return what we have.
 	if args.PullSubscription.Spec.Secret == nil {
 		return &corev1.PodSpec{
@@ -239,11 +278,31 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 	}
 }
 
+// sendMode returns the receive adapter mode implementing the mode of the
+// PullSubscription.
+func sendMode(mode gcpduckv1.ModeType) converters.ModeType {
+	switch mode {
+	case gcpduckv1.ModeCloudEventsStructured:
+		return converters.Structured
+	case gcpduckv1.ModeRawData:
+		return converters.Raw
+	case gcpduckv1.ModePush:
+		return converters.Push
+	default:
+		return converters.Binary
+	}
+}
+
 // MakeReceiveAdapter generates (but does not insert into K8s) the Receive Adapter Deployment for
 // PullSubscriptions.
 func MakeReceiveAdapter(ctx context.Context, args *ReceiveAdapterArgs) *v1.Deployment {
 	podSpec := makeReceiveAdapterPodSpec(ctx, args)
 	replicas := int32(1)
+	if duck.IsPaused(args.PullSubscription.Annotations) {
+		// The Pub/Sub subscription accumulates the events until the
+		// PullSubscription is resumed.
+		replicas = 0
+	}
 
 	return &v1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -571,3 +571,155 @@ func TestMakeReceiveAdapterWithServiceAccount(t *testing.T) {
 		t.Errorf("unexpected deploy (-want, +got) = %v", diff)
 	}
 }
+
+func TestMakePausedReceiveAdapter(t *testing.T) {
+	ps := &intereventsv1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+			Annotations: map[string]string{
+				duck.ClusterNameAnnotation: testingmetadata.FakeClusterName,
+				duck.PausedAnnotation:      "true",
+			},
+		},
+		Spec: intereventsv1.PullSubscriptionSpec{
+			Topic: "topic",
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		AuthType:         authcheck.WorkloadIdentityGSA,
+	})
+	if got.Spec.Replicas == nil || *got.Spec.Replicas != 0 {
+		t.Errorf("paused receive adapter replicas=%v, want 0", got.Spec.Replicas)
+	}
+}
+
+func TestMakeFilteredReceiveAdapter(t *testing.T) {
+	filter := `{"nameSuffixes":[".png"]}`
+	ps := &intereventsv1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+			Annotations: map[string]string{
+				intevents.AdapterFilterAnnotation: filter,
+			},
+		},
+		Spec: intereventsv1.PullSubscriptionSpec{
+			Topic: "topic",
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		AuthType:         authcheck.WorkloadIdentityGSA,
+	})
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{Name: "ADAPTER_FILTER", Value: filter}
+	if diff := cmp.Diff(want, env[len(env)-1]); diff != "" {
+		t.Errorf("unexpected filter env (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakeReceiveAdapterWithMode(t *testing.T) {
+	testCases := map[gcpduckv1.ModeType]string{
+		gcpduckv1.ModeCloudEventsBinary:     "binary",
+		gcpduckv1.ModeCloudEventsStructured: "structured",
+		gcpduckv1.ModeRawData:               "raw",
+		gcpduckv1.ModePush:                  "push",
+	}
+	for mode, want := range testCases {
+		t.Run(string(mode), func(t *testing.T) {
+			ps := &intereventsv1.PullSubscription{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testname",
+					Namespace: "testnamespace",
+				},
+				Spec: intereventsv1.PullSubscriptionSpec{
+					PubSubSpec: gcpduckv1.PubSubSpec{
+						Mode: mode,
+					},
+					Topic: "topic",
+				},
+			}
+
+			got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+				Image:            "test-image",
+				PullSubscription: ps,
+				SubscriptionID:   "sub-id",
+				SinkURI:          apis.HTTP("sink-uri"),
+				AuthType:         authcheck.WorkloadIdentityGSA,
+			})
+			env := got.Spec.Template.Spec.Containers[0].Env
+			if diff := cmp.Diff(corev1.EnvVar{Name: "SEND_MODE", Value: want}, env[len(env)-1]); diff != "" {
+				t.Errorf("unexpected mode env (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestMakeReceiveAdapterWithReply(t *testing.T) {
+	ps := &intereventsv1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: intereventsv1.PullSubscriptionSpec{
+			Topic: "topic",
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		ReplyURI:         apis.HTTP("reply-uri"),
+		AuthType:         authcheck.WorkloadIdentityGSA,
+	})
+	env := got.Spec.Template.Spec.Containers[0].Env
+	if diff := cmp.Diff(corev1.EnvVar{Name: "REPLY_URI", Value: "http://reply-uri"}, env[len(env)-1]); diff != "" {
+		t.Errorf("unexpected reply env (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakeReceiveAdapterWithMapping(t *testing.T) {
+	ps := &intereventsv1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: intereventsv1.PullSubscriptionSpec{
+			PubSubSpec: gcpduckv1.PubSubSpec{
+				Mapping: &gcpduckv1.EventMapping{
+					Subject:    "{.name}",
+					Extensions: map[string]string{"bucket": "{.bucket}"},
+				},
+			},
+			Topic: "topic",
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		AuthType:         authcheck.WorkloadIdentityGSA,
+	})
+	env := got.Spec.Template.Spec.Containers[0].Env
+	want := corev1.EnvVar{
+		Name:  "EVENT_MAPPING",
+		Value: `{"subject":"{.name}","extensions":{"bucket":"{.bucket}"}}`,
+	}
+	if diff := cmp.Diff(want, env[len(env)-1]); diff != "" {
+		t.Errorf("unexpected mapping env (-want, +got) = %v", diff)
+	}
+}
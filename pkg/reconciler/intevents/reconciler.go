@@ -32,7 +32,9 @@ import (
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 
+	gcpduck "github.com/google/knative-gcp/pkg/apis/duck"
 	duckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	"github.com/google/knative-gcp/pkg/apis/intevents"
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	clientset "github.com/google/knative-gcp/pkg/client/clientset/versioned"
 	duck "github.com/google/knative-gcp/pkg/duck/v1"
@@ -45,6 +47,7 @@ const (
 	pullSubscriptionGetFailedReason             = "PullSubscriptionGetFailed"
 	pullSubscriptionCreateFailedReason          = "PullSubscriptionCreateFailed"
 	PullSubscriptionStatusPropagateFailedReason = "PullSubscriptionStatusPropagateFailed"
+	deadLetterReconcileFailedReason             = "DeadLetterReconcileFailed"
 )
 
 var falseVal = false
@@ -96,31 +99,9 @@ func (psb *PubSubBase) reconcileTopic(ctx context.Context, pubsubable duck.PubSu
 		Labels:          resources.GetLabels(psb.receiveAdapterName, name),
 		Annotations:     pubsubable.GetObjectMeta().GetAnnotations(),
 	}
-	newTopic := resources.MakeTopic(args)
-
-	topics := psb.pubsubClient.InternalV1().Topics(newTopic.Namespace)
-	t, err := topics.Get(ctx, newTopic.Name, v1.GetOptions{})
-	if apierrs.IsNotFound(err) {
-		logging.FromContext(ctx).Desugar().Debug("Creating Topic", zap.Any("topic", newTopic))
-		t, err = topics.Create(ctx, newTopic, metav1.CreateOptions{})
-		if err != nil {
-			logging.FromContext(ctx).Desugar().Error("Failed to create Topic", zap.Any("topic", newTopic), zap.Error(err))
-			return nil, fmt.Errorf("failed to create Topic: %w", err)
-		}
-	} else if err != nil {
-		logging.FromContext(ctx).Desugar().Error("Failed to get Topic", zap.Error(err))
-		return nil, fmt.Errorf("failed to get Topic: %w", err)
-		// Check whether the specs differ and update the Topic if so.
-	} else if !equality.Semantic.DeepDerivative(newTopic.Spec, t.Spec) {
-		// Don't modify the informers copy.
-		desired := t.DeepCopy()
-		desired.Spec = newTopic.Spec
-		logging.FromContext(ctx).Desugar().Debug("Updating Topic", zap.Any("topic", desired))
-		t, err = topics.Update(ctx, desired, v1.UpdateOptions{})
-		if err != nil {
-			logging.FromContext(ctx).Desugar().Error("Failed to update Topic", zap.Any("topic", t), zap.Error(err))
-			return nil, fmt.Errorf("failed to update Topic: %w", err)
-		}
+	t, err := psb.createOrUpdateTopic(ctx, resources.MakeTopic(args))
+	if err != nil {
+		return nil, err
 	}
 
 	status := pubsubable.PubSubStatus()
@@ -162,10 +143,82 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 		args.Annotations[testloggingutil.LoggingE2ETestAnnotation] = v
 	}
 
+	if f, ok := pubsubable.(duck.AdapterFilterable); ok {
+		if filter := f.AdapterFilter(); filter != "" {
+			args.Annotations[intevents.AdapterFilterAnnotation] = filter
+		}
+	}
+
 	newPS := resources.MakePullSubscription(args)
 
-	pullSubscriptions := psb.pubsubClient.InternalV1().PullSubscriptions(namespace)
-	ps, err := pullSubscriptions.Get(ctx, name, v1.GetOptions{})
+	// A dead letter sink which isn't a Pub/Sub topic is delivered to from a
+	// topic managed by the source.
+	if resources.HasManagedDeadLetterTopic(spec) {
+		dlTopic := resources.GenerateDeadLetterTopicName(namespace, name, pubsubable.GetObjectMeta().GetUID())
+		if err := psb.reconcileDeadLetter(ctx, pubsubable, dlTopic, resourceGroup); err != nil {
+			return nil, err
+		}
+		newPS.Spec.Delivery.DeadLetterSink = resources.DeadLetterSink(dlTopic)
+	} else if err := psb.deleteDeadLetter(ctx, pubsubable); err != nil {
+		return nil, err
+	}
+
+	ps, err := psb.createOrUpdatePullSubscription(ctx, newPS)
+	if err != nil {
+		return nil, err
+	}
+
+	if ps.Status.GetCondition(inteventsv1.PullSubscriptionConditionPaused).IsTrue() {
+		status.MarkPaused(cs)
+	} else {
+		status.ClearPausedCondition(cs)
+	}
+	status.PropagateBacklog(cs, ps.Status.Backlog, ps.Status.GetCondition(inteventsv1.PullSubscriptionConditionBacklogHealthy))
+
+	if err := propagatePullSubscriptionStatus(ps, status, cs); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to propagate PullSubscription status: %s", zap.Error(err))
+		return ps, pkgreconciler.NewEvent(corev1.EventTypeWarning, PullSubscriptionStatusPropagateFailedReason, "Failed to propagate PullSubscription status: %s", err.Error())
+	}
+
+	status.SubscriptionID = ps.Status.SubscriptionID
+	status.SinkURI = ps.Status.SinkURI
+	return ps, nil
+}
+
+// createOrUpdateTopic creates the Topic, or updates it if its spec differs.
+func (psb *PubSubBase) createOrUpdateTopic(ctx context.Context, newTopic *inteventsv1.Topic) (*inteventsv1.Topic, error) {
+	topics := psb.pubsubClient.InternalV1().Topics(newTopic.Namespace)
+	t, err := topics.Get(ctx, newTopic.Name, v1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		logging.FromContext(ctx).Desugar().Debug("Creating Topic", zap.Any("topic", newTopic))
+		t, err = topics.Create(ctx, newTopic, metav1.CreateOptions{})
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to create Topic", zap.Any("topic", newTopic), zap.Error(err))
+			return nil, fmt.Errorf("failed to create Topic: %w", err)
+		}
+	} else if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to get Topic", zap.Error(err))
+		return nil, fmt.Errorf("failed to get Topic: %w", err)
+		// Check whether the specs differ and update the Topic if so.
+	} else if !equality.Semantic.DeepDerivative(newTopic.Spec, t.Spec) {
+		// Don't modify the informers copy.
+		desired := t.DeepCopy()
+		desired.Spec = newTopic.Spec
+		logging.FromContext(ctx).Desugar().Debug("Updating Topic", zap.Any("topic", desired))
+		t, err = topics.Update(ctx, desired, v1.UpdateOptions{})
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to update Topic", zap.Any("topic", t), zap.Error(err))
+			return nil, fmt.Errorf("failed to update Topic: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// createOrUpdatePullSubscription creates the PullSubscription, or updates it
+// if its spec or its propagated annotations differ.
+func (psb *PubSubBase) createOrUpdatePullSubscription(ctx context.Context, newPS *inteventsv1.PullSubscription) (*inteventsv1.PullSubscription, pkgreconciler.Event) {
+	pullSubscriptions := psb.pubsubClient.InternalV1().PullSubscriptions(newPS.Namespace)
+	ps, err := pullSubscriptions.Get(ctx, newPS.Name, v1.GetOptions{})
 	if err != nil {
 		if !apierrs.IsNotFound(err) {
 			logging.FromContext(ctx).Desugar().Error("Failed to get PullSubscription", zap.Error(err))
@@ -177,11 +230,16 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 			logging.FromContext(ctx).Desugar().Error("Failed to create PullSubscription", zap.Any("ps", newPS), zap.Error(err))
 			return nil, pkgreconciler.NewEvent(corev1.EventTypeWarning, pullSubscriptionCreateFailedReason, "Creating PullSubscription failed with: %s", err.Error())
 		}
-		// Check whether the specs differ and update the PS if so.
-	} else if !equality.Semantic.DeepDerivative(newPS.Spec, ps.Spec) {
+		// Check whether the specs or the propagated annotations differ and update the PS if so.
+		// The Mode, Delivery, Reply and Mapping are compared on their own as removing them is not a derivative change.
+	} else if !equality.Semantic.DeepDerivative(newPS.Spec, ps.Spec) || newPS.Spec.Mode != ps.Spec.Mode ||
+		!equality.Semantic.DeepEqual(newPS.Spec.Delivery, ps.Spec.Delivery) || !equality.Semantic.DeepEqual(newPS.Spec.Reply, ps.Spec.Reply) ||
+		!equality.Semantic.DeepEqual(newPS.Spec.Mapping, ps.Spec.Mapping) ||
+		!propagatedAnnotationsEqual(newPS.Annotations, ps.Annotations) {
 		// Don't modify the informers copy.
 		desired := ps.DeepCopy()
 		desired.Spec = newPS.Spec
+		setPropagatedAnnotations(desired, newPS.Annotations)
 		logging.FromContext(ctx).Desugar().Debug("Updating PullSubscription", zap.Any("ps", desired))
 		ps, err = pullSubscriptions.Update(ctx, desired, v1.UpdateOptions{})
 		if err != nil {
@@ -189,15 +247,120 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 			return nil, err
 		}
 	}
+	return ps, nil
+}
 
+// reconcileDeadLetter reconciles the Topic the messages of the source are dead
+// lettered to, and the PullSubscription delivering them to its dead letter
+// sink. The PullSubscription has to be ready before the messages are dead
+// lettered, as Pub/Sub drops the messages of topics without subscriptions.
+func (psb *PubSubBase) reconcileDeadLetter(ctx context.Context, pubsubable duck.PubSubable, topic, resourceGroup string) pkgreconciler.Event {
+	namespace := pubsubable.GetObjectMeta().GetNamespace()
+	name := pubsubable.GetObjectMeta().GetName()
+	dlName := resources.DeadLetterName(name)
+	spec := resources.MakeDeadLetterSpec(pubsubable.PubSubSpec())
+	status := pubsubable.PubSubStatus()
+	cs := pubsubable.ConditionSet()
+
+	if _, err := psb.createOrUpdateTopic(ctx, resources.MakeTopic(&resources.TopicArgs{
+		Namespace:       namespace,
+		Name:            dlName,
+		Spec:            spec,
+		EnablePublisher: &falseVal,
+		Owner:           pubsubable,
+		Topic:           topic,
+		Labels:          resources.GetLabels(psb.receiveAdapterName, name),
+	})); err != nil {
+		status.MarkPullSubscriptionFailed(cs, deadLetterReconcileFailedReason, "Failed to reconcile dead letter Topic: %s", err.Error())
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, deadLetterReconcileFailedReason, "Failed to reconcile dead letter Topic: %s", err.Error())
+	}
+
+	ps, err := psb.createOrUpdatePullSubscription(ctx, resources.MakePullSubscription(&resources.PullSubscriptionArgs{
+		Namespace:   namespace,
+		Name:        dlName,
+		Spec:        spec,
+		Owner:       pubsubable,
+		Topic:       topic,
+		AdapterType: psb.receiveAdapterType,
+		Labels:      resources.GetLabels(psb.receiveAdapterName, name),
+		Annotations: resources.GetAnnotations(nil, resourceGroup),
+	}))
+	if err != nil {
+		status.MarkPullSubscriptionFailed(cs, deadLetterReconcileFailedReason, "Failed to reconcile dead letter PullSubscription: %s", err.Error())
+		return err
+	}
 	if err := propagatePullSubscriptionStatus(ps, status, cs); err != nil {
-		logging.FromContext(ctx).Desugar().Error("Failed to propagate PullSubscription status: %s", zap.Error(err))
-		return ps, pkgreconciler.NewEvent(corev1.EventTypeWarning, PullSubscriptionStatusPropagateFailedReason, "Failed to propagate PullSubscription status: %s", err.Error())
+		logging.FromContext(ctx).Desugar().Error("Failed to propagate dead letter PullSubscription status", zap.Error(err))
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, PullSubscriptionStatusPropagateFailedReason, "Failed to propagate dead letter PullSubscription status: %s", err.Error())
 	}
+	return nil
+}
 
-	status.SubscriptionID = ps.Status.SubscriptionID
-	status.SinkURI = ps.Status.SinkURI
-	return ps, nil
+// deleteDeadLetter deletes the dead letter Topic and PullSubscription of the
+// source, if any.
+func (psb *PubSubBase) deleteDeadLetter(ctx context.Context, pubsubable duck.PubSubable) error {
+	namespace := pubsubable.GetObjectMeta().GetNamespace()
+	dlName := resources.DeadLetterName(pubsubable.GetObjectMeta().GetName())
+
+	pullSubscriptions := psb.pubsubClient.InternalV1().PullSubscriptions(namespace)
+	if ps, err := pullSubscriptions.Get(ctx, dlName, v1.GetOptions{}); err == nil && metav1.IsControlledBy(ps, pubsubable.GetObjectMeta()) {
+		if err := pullSubscriptions.Delete(ctx, dlName, v1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+			logging.FromContext(ctx).Desugar().Error("Failed to delete dead letter PullSubscription", zap.String("name", dlName), zap.Error(err))
+			return fmt.Errorf("failed to delete dead letter PullSubscription: %w", err)
+		}
+	} else if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("failed to get dead letter PullSubscription: %w", err)
+	}
+
+	topics := psb.pubsubClient.InternalV1().Topics(namespace)
+	if t, err := topics.Get(ctx, dlName, v1.GetOptions{}); err == nil && metav1.IsControlledBy(t, pubsubable.GetObjectMeta()) {
+		if err := topics.Delete(ctx, dlName, v1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+			logging.FromContext(ctx).Desugar().Error("Failed to delete dead letter Topic", zap.String("name", dlName), zap.Error(err))
+			return fmt.Errorf("failed to delete dead letter Topic: %w", err)
+		}
+	} else if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("failed to get dead letter Topic: %w", err)
+	}
+	return nil
+}
+
+// propagatedAnnotations are the annotations of a source which are kept in sync
+// on its PullSubscription.
+var propagatedAnnotations = []string{
+	gcpduck.PausedAnnotation,
+	gcpduck.BacklogReportingAnnotation,
+	gcpduck.BacklogThresholdAnnotation,
+	gcpduck.BacklogAgeThresholdAnnotation,
+	intevents.AdapterFilterAnnotation,
+}
+
+// propagatedAnnotationsEqual returns whether the propagated annotations have
+// the same values in both annotations.
+func propagatedAnnotationsEqual(a, b map[string]string) bool {
+	for _, key := range propagatedAnnotations {
+		va, oka := a[key]
+		vb, okb := b[key]
+		if oka != okb || va != vb {
+			return false
+		}
+	}
+	return true
+}
+
+// setPropagatedAnnotations sets the propagated annotations of the
+// PullSubscription to their values in annotations, if any.
+func setPropagatedAnnotations(ps *inteventsv1.PullSubscription, annotations map[string]string) {
+	for _, key := range propagatedAnnotations {
+		value, ok := annotations[key]
+		if !ok {
+			delete(ps.Annotations, key)
+			continue
+		}
+		if ps.Annotations == nil {
+			ps.Annotations = make(map[string]string)
+		}
+		ps.Annotations[key] = value
+	}
 }
 
 func propagatePullSubscriptionStatus(ps *inteventsv1.PullSubscription, status *duckv1.PubSubStatus, cs *apis.ConditionSet) error {
@@ -288,5 +451,11 @@ func (psb *PubSubBase) DeletePubSub(ctx context.Context, pubsubable duck.PubSuba
 		return fmt.Errorf("failed to delete PullSubscription: %w", err)
 	}
 	status.SinkURI = nil
+
+	// Delete the dead letter Topic and PullSubscription
+	if err := psb.deleteDeadLetter(ctx, pubsubable); err != nil {
+		status.MarkPullSubscriptionUnknown(cs, "DeadLetterDeleteFailed", "Failed to delete dead letter resources: %s", err.Error())
+		return err
+	}
 	return nil
 }
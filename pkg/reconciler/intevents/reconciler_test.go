@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientgotesting "k8s.io/client-go/testing"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
@@ -42,6 +43,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/knative-gcp/pkg/apis/duck"
 	v1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	v1events "github.com/google/knative-gcp/pkg/apis/events/v1"
 	intereventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	fakePubsubClient "github.com/google/knative-gcp/pkg/client/clientset/versioned/fake"
 	testingmetadata "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
@@ -878,6 +880,107 @@ func TestCreates(t *testing.T) {
 				reconcilertestingv1.WithPullSubscriptionReady(oldSink.URI),
 			),
 		}},
+	}, {
+		name: "topic exists and is ready, pullsubscription is updated due to the mode reset",
+		objects: []runtime.Object{
+			reconcilertestingv1.NewTopic(name, testNS,
+				reconcilertestingv1.WithTopicSpec(intereventsv1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				reconcilertestingv1.WithTopicLabels(map[string]string{
+					"receive-adapter":                     receiveAdapterName,
+					"events.cloud.google.com/source-name": name,
+				}),
+				reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithTopicProjectID(testProjectID),
+				reconcilertestingv1.WithTopicReadyAndPublisherDeployed(testTopicID),
+				reconcilertestingv1.WithTopicAddress(testTopicURI),
+				reconcilertestingv1.WithTopicSetDefaults,
+			),
+			reconcilertestingv1.NewPullSubscription(name, testNS,
+				reconcilertestingv1.WithPullSubscriptionSpec(intereventsv1.PullSubscriptionSpec{
+					Topic: testTopicID,
+					PubSubSpec: v1.PubSubSpec{
+						Secret: &secret,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: sink,
+						},
+						Mode: v1.ModePush,
+					},
+				}),
+				reconcilertestingv1.WithPullSubscriptionLabels(map[string]string{
+					"receive-adapter":                     receiveAdapterName,
+					"events.cloud.google.com/source-name": name,
+				}),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+					"metrics-resource-group": resourceGroup,
+				}),
+				reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithPullSubscriptionReady(sink.URI),
+			),
+		},
+		expectedTopic: reconcilertestingv1.NewTopic(name, testNS,
+			reconcilertestingv1.WithTopicSpec(intereventsv1.TopicSpec{
+				Secret:            &secret,
+				Topic:             testTopicID,
+				PropagationPolicy: "CreateDelete",
+				EnablePublisher:   &falseVal,
+			}),
+			reconcilertestingv1.WithTopicLabels(map[string]string{
+				"receive-adapter":                     receiveAdapterName,
+				"events.cloud.google.com/source-name": name,
+			}),
+			reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			reconcilertestingv1.WithTopicReadyAndPublisherDeployed(testTopicID),
+			reconcilertestingv1.WithTopicProjectID(testProjectID),
+			reconcilertestingv1.WithTopicAddress(testTopicURI),
+			reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			reconcilertestingv1.WithTopicSetDefaults,
+		),
+		expectedPS: reconcilertestingv1.NewPullSubscription(name, testNS,
+			reconcilertestingv1.WithPullSubscriptionSpec(intereventsv1.PullSubscriptionSpec{
+				Topic: testTopicID,
+				PubSubSpec: v1.PubSubSpec{
+					Secret: &secret,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: sink,
+					},
+				},
+			}),
+			reconcilertestingv1.WithPullSubscriptionLabels(map[string]string{
+				"receive-adapter":                     receiveAdapterName,
+				"events.cloud.google.com/source-name": name,
+			}),
+			reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+				"metrics-resource-group": resourceGroup,
+			}),
+			reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			reconcilertestingv1.WithPullSubscriptionReady(sink.URI),
+		),
+		wantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: reconcilertestingv1.NewPullSubscription(name, testNS,
+				reconcilertestingv1.WithPullSubscriptionSpec(intereventsv1.PullSubscriptionSpec{
+					Topic: testTopicID,
+					PubSubSpec: v1.PubSubSpec{
+						Secret: &secret,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: sink,
+						},
+					},
+				}),
+				reconcilertestingv1.WithPullSubscriptionLabels(map[string]string{
+					"receive-adapter":                     receiveAdapterName,
+					"events.cloud.google.com/source-name": name,
+				}),
+				reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+					"metrics-resource-group": resourceGroup,
+				}),
+				reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				reconcilertestingv1.WithPullSubscriptionReady(sink.URI),
+			),
+		}},
 	}}
 
 	for _, tc := range testCases {
@@ -1109,3 +1212,190 @@ func TestDeletes(t *testing.T) {
 		}
 	}
 }
+
+func TestDeadLetter(t *testing.T) {
+	deadLetterSink := duckv1.Destination{
+		URI: apis.HTTP("dead-letter-sink"),
+	}
+	deadLetterName := name + "-dead-letter"
+	deadLetterTopicID := "cre-src-dl_" + testNS + "_" + name + "_test-storage-uid"
+	deliverySource := reconcilertestingv1.NewCloudStorageSource(name, testNS,
+		reconcilertestingv1.WithCloudStorageSourceSinkDestination(sink),
+		reconcilertestingv1.WithCloudStorageSourceSetDefaults)
+	deliverySource.Spec.Delivery = &eventingduckv1.DeliverySpec{
+		DeadLetterSink: &deadLetterSink,
+	}
+	labels := map[string]string{
+		"receive-adapter":                     receiveAdapterName,
+		"events.cloud.google.com/source-name": name,
+	}
+	topic := func() *intereventsv1.Topic {
+		return reconcilertestingv1.NewTopic(name, testNS,
+			reconcilertestingv1.WithTopicSpec(intereventsv1.TopicSpec{
+				Secret:            &secret,
+				Topic:             testTopicID,
+				PropagationPolicy: "CreateDelete",
+				EnablePublisher:   &falseVal,
+			}),
+			reconcilertestingv1.WithTopicLabels(labels),
+			reconcilertestingv1.WithTopicAnnotations(map[string]string{
+				duck.ClusterNameAnnotation: testingmetadata.FakeClusterName,
+			}),
+			reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			reconcilertestingv1.WithTopicReadyAndPublisherDeployed(testTopicID),
+			reconcilertestingv1.WithTopicProjectID(testProjectID),
+			reconcilertestingv1.WithTopicAddress(testTopicURI),
+			reconcilertestingv1.WithTopicSetDefaults,
+		)
+	}
+	deadLetterTopic := func() *intereventsv1.Topic {
+		return reconcilertestingv1.NewTopic(deadLetterName, testNS,
+			reconcilertestingv1.WithTopicSpec(intereventsv1.TopicSpec{
+				Secret:            &secret,
+				Topic:             deadLetterTopicID,
+				PropagationPolicy: "CreateDelete",
+				EnablePublisher:   &falseVal,
+			}),
+			reconcilertestingv1.WithTopicLabels(labels),
+			reconcilertestingv1.WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+		)
+	}
+	deadLetterPS := func(opts ...reconcilertestingv1.PullSubscriptionOption) *intereventsv1.PullSubscription {
+		return reconcilertestingv1.NewPullSubscription(deadLetterName, testNS, append([]reconcilertestingv1.PullSubscriptionOption{
+			reconcilertestingv1.WithPullSubscriptionSpec(intereventsv1.PullSubscriptionSpec{
+				Topic: deadLetterTopicID,
+				PubSubSpec: v1.PubSubSpec{
+					Secret: &secret,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: deadLetterSink,
+					},
+				},
+			}),
+			reconcilertestingv1.WithPullSubscriptionLabels(labels),
+			reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+				"metrics-resource-group": resourceGroup,
+			}),
+			reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+		}, opts...)...)
+	}
+	ps := func(delivery *eventingduckv1.DeliverySpec) *intereventsv1.PullSubscription {
+		return reconcilertestingv1.NewPullSubscription(name, testNS,
+			reconcilertestingv1.WithPullSubscriptionSpec(intereventsv1.PullSubscriptionSpec{
+				Topic: testTopicID,
+				PubSubSpec: v1.PubSubSpec{
+					Secret: &secret,
+					SourceSpec: duckv1.SourceSpec{
+						Sink: sink,
+					},
+					Delivery: delivery,
+				},
+			}),
+			reconcilertestingv1.WithPullSubscriptionLabels(labels),
+			reconcilertestingv1.WithPullSubscriptionAnnotations(map[string]string{
+				"metrics-resource-group":   resourceGroup,
+				duck.ClusterNameAnnotation: testingmetadata.FakeClusterName,
+			}),
+			reconcilertestingv1.WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+		)
+	}
+
+	testCases := []struct {
+		name        string
+		source      *v1events.CloudStorageSource
+		objects     []runtime.Object
+		expectedPS  *intereventsv1.PullSubscription
+		expectedErr string
+		wantCreates []runtime.Object
+		wantDeletes []clientgotesting.DeleteActionImpl
+	}{{
+		name:        "dead letter topic and pullsubscription created, not yet been reconciled",
+		source:      deliverySource,
+		objects:     []runtime.Object{topic()},
+		expectedErr: fmt.Sprintf("Failed to propagate dead letter PullSubscription status: PullSubscription %q has not yet been reconciled", deadLetterName),
+		wantCreates: []runtime.Object{
+			deadLetterTopic(),
+			deadLetterPS(),
+		},
+	}, {
+		name:   "dead letter pullsubscription ready, pullsubscription created with the dead letter topic",
+		source: deliverySource,
+		objects: []runtime.Object{
+			topic(),
+			deadLetterTopic(),
+			deadLetterPS(reconcilertestingv1.WithPullSubscriptionReady(deadLetterSink.URI)),
+		},
+		expectedPS: ps(&eventingduckv1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{
+				URI: &apis.URL{Scheme: "pubsub", Host: deadLetterTopicID},
+			},
+		}),
+		expectedErr: fmt.Sprintf("%s: PullSubscription %q has not yet been reconciled", failedToPropagatePullSubscriptionStatusMsg, name),
+		wantCreates: []runtime.Object{
+			ps(&eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{
+					URI: &apis.URL{Scheme: "pubsub", Host: deadLetterTopicID},
+				},
+			}),
+		},
+	}, {
+		name:   "dead letter topic and pullsubscription deleted without a dead letter sink",
+		source: pubsubable,
+		objects: []runtime.Object{
+			topic(),
+			deadLetterTopic(),
+			deadLetterPS(),
+		},
+		expectedPS:  ps(nil),
+		expectedErr: fmt.Sprintf("%s: PullSubscription %q has not yet been reconciled", failedToPropagatePullSubscriptionStatusMsg, name),
+		wantCreates: []runtime.Object{
+			ps(nil),
+		},
+		wantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "delete",
+				Resource:  schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "pullsubscriptions"},
+			},
+			Name: deadLetterName,
+		}, {
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS,
+				Verb:      "delete",
+				Resource:  schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1", Resource: "topics"},
+			},
+			Name: deadLetterName,
+		}},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := fakePubsubClient.NewSimpleClientset(tc.objects...)
+
+			psBase := &PubSubBase{
+				Base:               &reconciler.Base{},
+				pubsubClient:       cs,
+				receiveAdapterName: receiveAdapterName,
+			}
+			psBase.Logger = logtesting.TestLogger(t)
+
+			arl := pkgtesting.ActionRecorderList{cs}
+			_, ps, err := psBase.ReconcilePubSub(context.Background(), tc.source, testTopicID, resourceGroup)
+
+			if (tc.expectedErr != "" && err == nil) ||
+				(tc.expectedErr == "" && err != nil) ||
+				(tc.expectedErr != "" && err != nil && tc.expectedErr != err.Error()) {
+				t.Errorf("Error mismatch, want: %q got: %q", tc.expectedErr, err)
+			}
+			if diff := cmp.Diff(tc.expectedPS, ps, ignoreLastTransitionTime); diff != "" {
+				t.Errorf("Unexpected pullsubscription (-want, +got) = %v", diff)
+			}
+
+			actions, err := arl.ActionsByVerb()
+			if err != nil {
+				t.Errorf("Error capturing actions by verb: %q", err)
+			}
+			verifyCreateActions(t, actions.Creates, tc.wantCreates)
+			verifyDeleteActions(t, actions.Deletes, tc.wantDeletes)
+		})
+	}
+}
@@ -16,10 +16,12 @@ limitations under the License.
 
 package resources
 
+// GetAnnotations returns a copy of the original annotations, which belong to
+// the source, with the metrics resource group.
 func GetAnnotations(original map[string]string, resourceGroup string) map[string]string {
-	annotations := original
-	if annotations == nil {
-		annotations = make(map[string]string)
+	annotations := make(map[string]string, len(original)+1)
+	for k, v := range original {
+		annotations[k] = v
 	}
 	annotations["metrics-resource-group"] = resourceGroup
 	return annotations
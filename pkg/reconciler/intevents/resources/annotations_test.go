@@ -0,0 +1,39 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetAnnotations(t *testing.T) {
+	original := map[string]string{"foo": "bar"}
+	want := map[string]string{
+		"foo":                    "bar",
+		"metrics-resource-group": "storages.events.cloud.google.com",
+	}
+	got := GetAnnotations(original, "storages.events.cloud.google.com")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"foo": "bar"}, original); diff != "" {
+		t.Errorf("original annotations modified (-want, +got) = %v", diff)
+	}
+}
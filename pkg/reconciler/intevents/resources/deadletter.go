@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+	"github.com/google/knative-gcp/pkg/utils/naming"
+)
+
+// HasManagedDeadLetterTopic returns true if the dead letter sink of the spec is
+// not a Pub/Sub topic. The messages are then dead lettered to a topic managed
+// by the source, from which a PullSubscription delivers them to the sink.
+func HasManagedDeadLetterTopic(spec *gcpduckv1.PubSubSpec) bool {
+	return spec.Delivery != nil && spec.Delivery.DeadLetterSink != nil &&
+		!brokerv1beta1.IsPubsubDeadLetterSink(spec.Delivery.DeadLetterSink)
+}
+
+// DeadLetterName returns the name of the Topic and the PullSubscription
+// delivering the dead letter messages of the source named name.
+func DeadLetterName(name string) string {
+	return kmeta.ChildName(name, "-dead-letter")
+}
+
+// GenerateDeadLetterTopicName generates the name of the managed Pub/Sub topic
+// the messages of a source are dead lettered to.
+func GenerateDeadLetterTopicName(namespace, name string, uid types.UID) string {
+	return naming.TruncatedPubsubResourceName("cre-src-dl", namespace, name, uid)
+}
+
+// DeadLetterSink returns the dead letter sink referring to the Pub/Sub topic.
+func DeadLetterSink(topic string) *duckv1.Destination {
+	return &duckv1.Destination{
+		URI: &apis.URL{
+			Scheme: brokerv1beta1.PubsubDeadLetterSinkScheme,
+			Host:   topic,
+		},
+	}
+}
+
+// MakeDeadLetterSpec returns the spec of the PullSubscription delivering the
+// dead letter messages of a source with the given spec to its dead letter
+// sink.
+func MakeDeadLetterSpec(spec *gcpduckv1.PubSubSpec) *gcpduckv1.PubSubSpec {
+	return &gcpduckv1.PubSubSpec{
+		SourceSpec: duckv1.SourceSpec{
+			Sink:                *spec.Delivery.DeadLetterSink,
+			CloudEventOverrides: spec.CloudEventOverrides,
+		},
+		IdentitySpec: spec.IdentitySpec,
+		Secret:       spec.Secret,
+		Project:      spec.Project,
+		Mode:         spec.Mode,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+func TestHasManagedDeadLetterTopic(t *testing.T) {
+	testCases := map[string]struct {
+		delivery *eventingduckv1.DeliverySpec
+		want     bool
+	}{
+		"no delivery": {},
+		"no dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{},
+		},
+		"pubsub dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: DeadLetterSink("dead-letter"),
+			},
+		},
+		"http dead letter sink": {
+			delivery: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter")},
+			},
+			want: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := HasManagedDeadLetterTopic(&gcpduckv1.PubSubSpec{Delivery: tc.delivery}); got != tc.want {
+				t.Errorf("HasManagedDeadLetterTopic() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGenerateDeadLetterTopicName(t *testing.T) {
+	want := "cre-src-dl_ns_name_uid"
+	if got := GenerateDeadLetterTopicName("ns", "name", "uid"); got != want {
+		t.Errorf("GenerateDeadLetterTopicName() = %q, want %q", got, want)
+	}
+}
+
+func TestMakeDeadLetterSpec(t *testing.T) {
+	secret := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "eventing-secret-name",
+		},
+		Key: "eventing-secret-key",
+	}
+	deadLetterSink := duckv1.Destination{URI: apis.HTTP("dead-letter")}
+	spec := &gcpduckv1.PubSubSpec{
+		SourceSpec: duckv1.SourceSpec{
+			Sink: duckv1.Destination{URI: apis.HTTP("sink")},
+			CloudEventOverrides: &duckv1.CloudEventOverrides{
+				Extensions: map[string]string{"foo": "bar"},
+			},
+		},
+		Secret:  secret,
+		Project: "project-123",
+		Mode:    gcpduckv1.ModeRawData,
+		Delivery: &eventingduckv1.DeliverySpec{
+			DeadLetterSink: &deadLetterSink,
+		},
+		Reply: &duckv1.Destination{URI: apis.HTTP("reply")},
+	}
+	want := &gcpduckv1.PubSubSpec{
+		SourceSpec: duckv1.SourceSpec{
+			Sink: deadLetterSink,
+			CloudEventOverrides: &duckv1.CloudEventOverrides{
+				Extensions: map[string]string{"foo": "bar"},
+			},
+		},
+		Secret:  secret,
+		Project: "project-123",
+		Mode:    gcpduckv1.ModeRawData,
+	}
+
+	if diff := cmp.Diff(want, MakeDeadLetterSpec(spec)); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
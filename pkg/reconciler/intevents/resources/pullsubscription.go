@@ -54,6 +54,7 @@ func MakePullSubscription(args *PullSubscriptionArgs) *inteventsv1.PullSubscript
 				},
 				Secret:  args.Spec.Secret,
 				Project: args.Spec.Project,
+				Mode:    args.Spec.Mode,
 				SourceSpec: duckv1.SourceSpec{
 					Sink: args.Spec.SourceSpec.Sink,
 				},
@@ -62,6 +63,15 @@ func MakePullSubscription(args *PullSubscriptionArgs) *inteventsv1.PullSubscript
 			AdapterType: args.AdapterType,
 		},
 	}
+	if args.Spec.Delivery != nil {
+		ps.Spec.Delivery = args.Spec.Delivery.DeepCopy()
+	}
+	if args.Spec.Reply != nil {
+		ps.Spec.Reply = args.Spec.Reply.DeepCopy()
+	}
+	if args.Spec.Mapping != nil {
+		ps.Spec.Mapping = args.Spec.Mapping.DeepCopy()
+	}
 	if args.Spec.CloudEventOverrides != nil && args.Spec.CloudEventOverrides.Extensions != nil {
 		ps.Spec.SourceSpec.CloudEventOverrides = &duckv1.CloudEventOverrides{
 			Extensions: args.Spec.CloudEventOverrides.Extensions,
@@ -25,6 +25,8 @@ import (
 	inteventsv1 "github.com/google/knative-gcp/pkg/apis/intevents/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
@@ -39,6 +41,12 @@ func TestMakePullSubscription(t *testing.T) {
 			Bucket: "this-bucket",
 			PubSubSpec: gcpduckv1.PubSubSpec{
 				Project: "project-123",
+				Mode:    gcpduckv1.ModeCloudEventsStructured,
+				Delivery: &eventingduckv1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter"}},
+				},
+				Reply:   &duckv1.Destination{URI: apis.HTTP("reply")},
+				Mapping: &gcpduckv1.EventMapping{Subject: "{.name}"},
 				Secret: &corev1.SecretKeySelector{
 					LocalObjectReference: corev1.LocalObjectReference{
 						Name: "eventing-secret-name",
@@ -107,6 +115,12 @@ func TestMakePullSubscription(t *testing.T) {
 					Key: "eventing-secret-key",
 				},
 				Project: "project-123",
+				Mode:    gcpduckv1.ModeCloudEventsStructured,
+				Delivery: &eventingduckv1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter"}},
+				},
+				Reply:   &duckv1.Destination{URI: apis.HTTP("reply")},
+				Mapping: &gcpduckv1.EventMapping{Subject: "{.name}"},
 				SourceSpec: duckv1.SourceSpec{
 					Sink: duckv1.Destination{
 						Ref: &duckv1.KReference{
@@ -0,0 +1,339 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/channel/resources"
+	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	brokerCellCreated          = "BrokerCellCreated"
+	reconciledBrokerCellFailed = "BrokerCellReconcileFailed"
+	reconciledDecouplingFailed = "DecouplingTopicReconcileFailed"
+	finalizedBrokerCellFailed  = "BrokerCellChannelFinalizeFailed"
+	reconciledSubscriberFailed = "SubscriberReconcileFailed"
+)
+
+// createPubsubClientFn is a function for pubsub client creation. Changed in testing only.
+var createPubsubClientFn reconcilerutilspubsub.CreateFn = pubsub.NewClient
+
+// reconcileOnBrokerCell reconciles a Channel served by a brokercell. The
+// brokercell ingress publishes the events of the Channel to its decoupling
+// topic, and the brokercell fanout and retry deliver them to the subscribers
+// through one retry topic per subscriber, like the Triggers of a Broker.
+func (r *Reconciler) reconcileOnBrokerCell(ctx context.Context, channel *v1beta1.Channel) pkgreconciler.Event {
+	channel.Status.InitializeBrokerCellConditions()
+	channel.Status.ObservedGeneration = channel.Generation
+
+	if err := r.ensureBrokerCellExists(ctx, channel); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledBrokerCellFailed, "Reconcile BrokerCell failed with: %s", err.Error())
+	}
+
+	pubsubReconciler, projectID, err := r.pubsubReconciler(ctx, channel)
+	if err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledDecouplingFailed, "Reconcile decoupling topic failed with: %s", err.Error())
+	}
+	channel.Status.ProjectID = projectID
+
+	if err := r.reconcileDecouplingTopicAndSubscription(ctx, pubsubReconciler, channel); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledDecouplingFailed, "Reconcile decoupling topic failed with: %s", err.Error())
+	}
+
+	if err := r.reconcileSubscribersOnBrokerCell(ctx, pubsubReconciler, projectID, channel); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledSubscribersFailedReason, "Reconcile Subscribers failed with: %s", err.Error())
+	}
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `Channel reconciled: "%s/%s"`, channel.Namespace, channel.Name)
+}
+
+// finalizeOnBrokerCell deletes the Pub/Sub topics and subscriptions of a
+// Channel served by a brokercell.
+func (r *Reconciler) finalizeOnBrokerCell(ctx context.Context, channel *v1beta1.Channel) pkgreconciler.Event {
+	pubsubReconciler, _, err := r.pubsubReconciler(ctx, channel)
+	if err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, finalizedBrokerCellFailed, "Failed to delete Pub/Sub resources: %s", err.Error())
+	}
+	err = multierr.Append(
+		pubsubReconciler.DeleteTopic(ctx, resources.GenerateTopicID(channel), channel, &channel.Status),
+		pubsubReconciler.DeleteSubscription(ctx, resources.GenerateDecouplingSubscriptionName(channel), channel, &channel.Status),
+	)
+	uids := make(map[types.UID]bool)
+	if channel.Spec.SubscribableSpec != nil {
+		for _, s := range channel.Spec.SubscribableSpec.Subscribers {
+			uids[s.UID] = true
+		}
+	}
+	for _, s := range channel.Status.SubscribableStatus.Subscribers {
+		uids[s.UID] = true
+	}
+	for uid := range uids {
+		err = multierr.Append(err, r.deleteSubscriberTopicsAndSubscriptions(ctx, pubsubReconciler, channel, uid, &subscriberStatusUpdater{}))
+	}
+	if err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, finalizedBrokerCellFailed, "Failed to delete Pub/Sub resources: %s", err.Error())
+	}
+	return nil
+}
+
+// ensureBrokerCellExists creates the brokercell of the Channel if it doesn't
+// exist, and updates the Channel status based on the brokercell status.
+func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, channel *v1beta1.Channel) error {
+	bc, err := r.brokerCellLister.BrokerCells(system.Namespace()).Get(channel.BrokerCell())
+	if err != nil && !apierrs.IsNotFound(err) {
+		logging.FromContext(ctx).Desugar().Error("Failed to get brokercell", zap.Error(err))
+		channel.Status.MarkBrokerCellUnknown("BrokerCellUnknown", "Failed to get brokercell %s/%s", system.Namespace(), channel.BrokerCell())
+		return err
+	}
+	if apierrs.IsNotFound(err) {
+		want := resources.MakeBrokerCell(channel)
+		bc, err = r.RunClientSet.InternalV1alpha1().BrokerCells(want.Namespace).Create(ctx, want, metav1.CreateOptions{})
+		if apierrs.IsAlreadyExists(err) {
+			// The informer may not be updated yet, read the brokercell from the
+			// API server.
+			bc, err = r.RunClientSet.InternalV1alpha1().BrokerCells(want.Namespace).Get(ctx, want.Name, metav1.GetOptions{})
+		} else if err == nil {
+			r.Recorder.Eventf(channel, corev1.EventTypeNormal, brokerCellCreated, "Created brokercell %s/%s", bc.Namespace, bc.Name)
+		}
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to create brokercell", zap.Error(err))
+			channel.Status.MarkBrokerCellFailed("BrokerCellCreationFailed", "Failed to create %s/%s", want.Namespace, want.Name)
+			return err
+		}
+	}
+
+	if bc.Status.IsReady() {
+		channel.Status.MarkBrokerCellReady()
+	} else {
+		channel.Status.MarkBrokerCellUnknown("BrokerCellNotReady", "Brokercell %s/%s is not ready", bc.Namespace, bc.Name)
+	}
+	channel.Status.SetAddress(resources.BrokerCellAddress(bc.Name, channel))
+	return nil
+}
+
+// pubsubReconciler returns the Pub/Sub reconciler and the project ID of the
+// topics and subscriptions of the Channel. They live in the project of the
+// brokercell data plane, the project of the Channel spec is not used.
+func (r *Reconciler) pubsubReconciler(ctx context.Context, channel *v1beta1.Channel) (*reconcilerutilspubsub.Reconciler, string, error) {
+	// get ProjectID from metadata if projectID isn't set
+	projectID, err := utils.ProjectIDOrDefault(r.projectID)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+		channel.Status.MarkTopicUnknown("ProjectIdNotFound", "Failed to find project id: %v", err)
+		channel.Status.MarkSubscriptionUnknown("ProjectIdNotFound", "Failed to find project id: %v", err)
+		return nil, "", err
+	}
+	if r.pubsubClient == nil {
+		client, err := createPubsubClientFn(ctx, projectID)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to create Pub/Sub client", zap.Error(err))
+			channel.Status.MarkTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: %v", err)
+			channel.Status.MarkSubscriptionUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: %v", err)
+			return nil, "", err
+		}
+		// Register the client for next run
+		r.pubsubClient = client
+	}
+	return reconcilerutilspubsub.NewReconciler(r.pubsubClient, r.Recorder), projectID, nil
+}
+
+// reconcileDecouplingTopicAndSubscription reconciles the topic the brokercell
+// ingress publishes the events of the Channel to, and the subscription the
+// brokercell fanout pulls them from.
+func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context, pubsubReconciler *reconcilerutilspubsub.Reconciler, channel *v1beta1.Channel) error {
+	labels := map[string]string{
+		"resource":  "channels",
+		"namespace": channel.Namespace,
+		"name":      channel.Name,
+	}
+	topicID := resources.GenerateTopicID(channel)
+	topic, err := pubsubReconciler.ReconcileTopic(ctx, topicID, &pubsub.TopicConfig{Labels: labels}, channel, &channel.Status)
+	if err != nil {
+		return err
+	}
+	channel.Status.TopicID = topicID
+
+	subConfig := pubsub.SubscriptionConfig{
+		Topic:  topic,
+		Labels: labels,
+	}
+	_, err = pubsubReconciler.ReconcileSubscription(ctx, resources.GenerateDecouplingSubscriptionName(channel), subConfig, channel, &channel.Status)
+	return err
+}
+
+// reconcileSubscribersOnBrokerCell reconciles the retry topics and
+// subscriptions of the subscribers of the Channel, and deletes the ones of the
+// subscribers which were removed from the Channel.
+func (r *Reconciler) reconcileSubscribersOnBrokerCell(ctx context.Context, pubsubReconciler *reconcilerutilspubsub.Reconciler, projectID string, channel *v1beta1.Channel) error {
+	removed := make(map[types.UID]bool)
+	for _, s := range channel.Status.SubscribableStatus.Subscribers {
+		removed[s.UID] = true
+	}
+
+	var subscribers []eventingduckv1beta1.SubscriberStatus
+	var errs error
+	if channel.Spec.SubscribableSpec != nil {
+		for _, s := range channel.Spec.SubscribableSpec.Subscribers {
+			delete(removed, s.UID)
+			status := &subscriberStatusUpdater{status: eventingduckv1beta1.SubscriberStatus{
+				UID:                s.UID,
+				ObservedGeneration: s.Generation,
+				Ready:              corev1.ConditionTrue,
+			}}
+			if err := r.reconcileSubscriberTopicsAndSubscriptions(ctx, pubsubReconciler, projectID, channel, s, status); err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to reconcile subscriber", zap.String("subscriber", string(s.UID)), zap.Error(err))
+				errs = multierr.Append(errs, err)
+			}
+			subscribers = append(subscribers, status.status)
+		}
+	}
+
+	for uid := range removed {
+		if err := r.deleteSubscriberTopicsAndSubscriptions(ctx, pubsubReconciler, channel, uid, &subscriberStatusUpdater{}); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to delete subscriber", zap.String("subscriber", string(uid)), zap.Error(err))
+			errs = multierr.Append(errs, err)
+			// Keep the subscriber in the status to retry the deletion.
+			subscribers = append(subscribers, eventingduckv1beta1.SubscriberStatus{
+				UID:     uid,
+				Ready:   corev1.ConditionFalse,
+				Message: fmt.Sprintf("Failed to delete subscriber: %v", err),
+			})
+		}
+	}
+	channel.Status.SubscribableStatus.Subscribers = subscribers
+	return errs
+}
+
+// reconcileSubscriberTopicsAndSubscriptions reconciles the retry topic and
+// subscription of a subscriber of the Channel, and its dead letter topic and
+// subscription if its dead letter sink is not a Pub/Sub topic.
+func (r *Reconciler) reconcileSubscriberTopicsAndSubscriptions(ctx context.Context, pubsubReconciler *reconcilerutilspubsub.Reconciler, projectID string, channel *v1beta1.Channel, s eventingduckv1beta1.SubscriberSpec, status *subscriberStatusUpdater) error {
+	labels := map[string]string{
+		"resource":   "channels",
+		"namespace":  channel.Namespace,
+		"name":       channel.Name,
+		"subscriber": string(s.UID),
+	}
+	topicConfig := &pubsub.TopicConfig{Labels: labels}
+	topic, err := pubsubReconciler.ReconcileTopic(ctx, resources.GenerateRetryTopicName(channel, s.UID), topicConfig, channel, status)
+	if err != nil {
+		return err
+	}
+
+	// The delivery spec has been validated by the webhook.
+	delivery := &eventingduckv1.DeliverySpec{}
+	if s.Delivery != nil {
+		_ = s.Delivery.ConvertTo(ctx, delivery)
+	}
+	var deadLetterPolicy *pubsub.DeadLetterPolicy
+	if resources.HasManagedDeadLetterQueue(s.Delivery) {
+		deadLetterTopic, err := pubsubReconciler.ReconcileTopic(ctx, resources.GenerateDeadLetterTopicName(channel, s.UID), topicConfig, channel, status)
+		if err != nil {
+			return err
+		}
+		subConfig := pubsub.SubscriptionConfig{
+			Topic:       deadLetterTopic,
+			Labels:      labels,
+			RetryPolicy: reconcilerutilspubsub.RetryPolicy(delivery),
+		}
+		if _, err := pubsubReconciler.ReconcileSubscription(ctx, resources.GenerateDeadLetterSubscriptionName(channel, s.UID), subConfig, channel, status); err != nil {
+			return err
+		}
+		deadLetterPolicy = reconcilerutilspubsub.NewDeadLetterPolicy(projectID, deadLetterTopic.ID(), delivery.Retry)
+	} else {
+		// Clean up the dead letter topic and subscription in case the
+		// subscriber used to have a dead letter sink which is not a Pub/Sub
+		// topic.
+		if err := r.deleteDeadLetterTopicAndSubscription(ctx, pubsubReconciler, channel, s.UID, status); err != nil {
+			return err
+		}
+		deadLetterPolicy = reconcilerutilspubsub.DeadLetterPolicy(projectID, delivery)
+	}
+
+	subConfig := pubsub.SubscriptionConfig{
+		Topic:            topic,
+		Labels:           labels,
+		RetryPolicy:      reconcilerutilspubsub.RetryPolicy(delivery),
+		DeadLetterPolicy: deadLetterPolicy,
+	}
+	_, err = pubsubReconciler.ReconcileSubscription(ctx, resources.GenerateRetrySubscriptionName(channel, s.UID), subConfig, channel, status)
+	return err
+}
+
+// deleteSubscriberTopicsAndSubscriptions deletes the retry and dead letter
+// topics and subscriptions of a subscriber of the Channel.
+func (r *Reconciler) deleteSubscriberTopicsAndSubscriptions(ctx context.Context, pubsubReconciler *reconcilerutilspubsub.Reconciler, channel *v1beta1.Channel, uid types.UID, status *subscriberStatusUpdater) error {
+	err := pubsubReconciler.DeleteTopic(ctx, resources.GenerateRetryTopicName(channel, uid), channel, status)
+	err = multierr.Append(err, pubsubReconciler.DeleteSubscription(ctx, resources.GenerateRetrySubscriptionName(channel, uid), channel, status))
+	return multierr.Append(err, r.deleteDeadLetterTopicAndSubscription(ctx, pubsubReconciler, channel, uid, status))
+}
+
+func (r *Reconciler) deleteDeadLetterTopicAndSubscription(ctx context.Context, pubsubReconciler *reconcilerutilspubsub.Reconciler, channel *v1beta1.Channel, uid types.UID, status *subscriberStatusUpdater) error {
+	err := pubsubReconciler.DeleteTopic(ctx, resources.GenerateDeadLetterTopicName(channel, uid), channel, status)
+	return multierr.Append(err, pubsubReconciler.DeleteSubscription(ctx, resources.GenerateDeadLetterSubscriptionName(channel, uid), channel, status))
+}
+
+// subscriberStatusUpdater updates the status of a subscriber of the Channel
+// based on the reconciliation of its topics and subscriptions.
+type subscriberStatusUpdater struct {
+	status eventingduckv1beta1.SubscriberStatus
+}
+
+var _ reconcilerutilspubsub.StatusUpdater = (*subscriberStatusUpdater)(nil)
+
+func (s *subscriberStatusUpdater) mark(ready corev1.ConditionStatus, format string, args ...interface{}) {
+	s.status.Ready = ready
+	s.status.Message = fmt.Sprintf(format, args...)
+}
+
+func (s *subscriberStatusUpdater) MarkTopicFailed(_, format string, args ...interface{}) {
+	s.mark(corev1.ConditionFalse, format, args...)
+}
+
+func (s *subscriberStatusUpdater) MarkTopicUnknown(_, format string, args ...interface{}) {
+	s.mark(corev1.ConditionUnknown, format, args...)
+}
+
+func (s *subscriberStatusUpdater) MarkTopicReady() {}
+
+func (s *subscriberStatusUpdater) MarkSubscriptionFailed(_, format string, args ...interface{}) {
+	s.mark(corev1.ConditionFalse, format, args...)
+}
+
+func (s *subscriberStatusUpdater) MarkSubscriptionUnknown(_, format string, args ...interface{}) {
+	s.mark(corev1.ConditionUnknown, format, args...)
+}
+
+func (s *subscriberStatusUpdater) MarkSubscriptionReady() {}
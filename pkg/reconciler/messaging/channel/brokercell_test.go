@@ -0,0 +1,287 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	. "knative.dev/pkg/reconciler/testing"
+	_ "knative.dev/pkg/system/testing"
+
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/channel"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	brokerCellName = "team-a"
+	systemNS       = "knative-testing"
+
+	decouplingID = "cre-chan_testnamespace_chan_chan-abc-123"
+	retryID      = "cre-chsub_testnamespace_chan_testsubscription-abc-123"
+	deadLetterID = "cre-chdlq_testnamespace_chan_testsubscription-abc-123"
+
+	brokerCellChannelAddress = "http://team-a-brokercell-ingress.knative-testing.svc.cluster.local/channel/testnamespace/chan"
+)
+
+var (
+	backoffDelay  = "PT1S"
+	linearBackoff = eventingduckv1beta1.BackoffPolicyLinear
+	retry         = int32(5)
+)
+
+func brokerCellSubscriber(delivery *eventingduckv1beta1.DeliverySpec) []eventingduckv1beta1.SubscriberSpec {
+	return []eventingduckv1beta1.SubscriberSpec{{
+		UID:           subscriptionUID,
+		Generation:    1,
+		SubscriberURI: subscriberURI,
+		ReplyURI:      replyURI,
+		Delivery:      delivery,
+	}}
+}
+
+func readyBrokerCellSubscriberStatus() []eventingduckv1beta1.SubscriberStatus {
+	return []eventingduckv1beta1.SubscriberStatus{{
+		UID:                subscriptionUID,
+		ObservedGeneration: 1,
+		Ready:              corev1.ConditionTrue,
+	}}
+}
+
+func TestBrokerCellChannel(t *testing.T) {
+	table := TableTest{{
+		Name: "channel with subscriber on ready brokercell",
+		Objects: []runtime.Object{
+			NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelBrokerCell(brokerCellName),
+				WithChannelSubscribers(brokerCellSubscriber(&eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &backoffDelay,
+					BackoffPolicy: &linearBackoff,
+				})),
+			),
+			NewBrokerCell(brokerCellName, systemNS, WithBrokerCellReady),
+		},
+		Key: testNS + "/" + channelName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", channelName),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic %q`, decouplingID),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription %q`, decouplingID),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic %q`, retryID),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription %q`, retryID),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Channel reconciled: "%s/%s"`, testNS, channelName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelBrokerCell(brokerCellName),
+				WithChannelSubscribers(brokerCellSubscriber(&eventingduckv1beta1.DeliverySpec{
+					BackoffDelay:  &backoffDelay,
+					BackoffPolicy: &linearBackoff,
+				})),
+				WithInitChannelBrokerCellConditions,
+				WithChannelBrokerCellReady,
+				WithChannelAddress(brokerCellChannelAddress),
+				WithChannelProjectID(testProject),
+				WithChannelTopic(decouplingID),
+				WithChannelSubscriptionReady,
+				WithChannelSubscribersStatus(readyBrokerCellSubscriberStatus()),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, channelName, true),
+		},
+		OtherTestData: map[string]interface{}{},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlyTopics(decouplingID, retryID),
+			OnlySubscriptions(decouplingID, retryID),
+			SubscriptionHasRetryPolicy(retryID, &pubsub.RetryPolicy{
+				MinimumBackoff: time.Second,
+				MaximumBackoff: time.Second,
+			}),
+		},
+	}, {
+		Name: "channel with dead letter sink, brokercell is created",
+		Objects: []runtime.Object{
+			NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelBrokerCell(brokerCellName),
+				WithChannelSubscribers(brokerCellSubscriber(&eventingduckv1beta1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: subscriberURI},
+					Retry:          &retry,
+				})),
+			),
+		},
+		Key: testNS + "/" + channelName,
+		WantCreates: []runtime.Object{
+			NewBrokerCell(brokerCellName, systemNS,
+				WithBrokerCellAnnotations(map[string]string{inteventsv1alpha1.CreatorKey: inteventsv1alpha1.Creator}),
+			),
+		},
+		// The brokercell is created in the system namespace.
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", channelName),
+			Eventf(corev1.EventTypeNormal, brokerCellCreated, "Created brokercell %s/%s", systemNS, brokerCellName),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic %q`, decouplingID),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription %q`, decouplingID),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic %q`, retryID),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic %q`, deadLetterID),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription %q`, deadLetterID),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription %q`, retryID),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Channel reconciled: "%s/%s"`, testNS, channelName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelBrokerCell(brokerCellName),
+				WithChannelSubscribers(brokerCellSubscriber(&eventingduckv1beta1.DeliverySpec{
+					DeadLetterSink: &duckv1.Destination{URI: subscriberURI},
+					Retry:          &retry,
+				})),
+				WithInitChannelBrokerCellConditions,
+				WithChannelBrokerCellUnknown("BrokerCellNotReady", "Brokercell knative-testing/team-a is not ready"),
+				WithChannelAddress(brokerCellChannelAddress),
+				WithChannelProjectID(testProject),
+				WithChannelTopic(decouplingID),
+				WithChannelSubscriptionReady,
+				WithChannelSubscribersStatus(readyBrokerCellSubscriberStatus()),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, channelName, true),
+		},
+		OtherTestData: map[string]interface{}{},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlyTopics(decouplingID, retryID, deadLetterID),
+			OnlySubscriptions(decouplingID, retryID, deadLetterID),
+			SubscriptionHasDeadLetterPolicy(retryID, &pubsub.DeadLetterPolicy{
+				DeadLetterTopic:     "projects/" + testProject + "/topics/" + deadLetterID,
+				MaxDeliveryAttempts: int(retry),
+			}),
+		},
+	}, {
+		Name: "removed subscriber is deleted",
+		Objects: []runtime.Object{
+			NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelBrokerCell(brokerCellName),
+				WithChannelSubscribersStatus(readyBrokerCellSubscriberStatus()),
+				WithChannelFinalizers(resourceGroup),
+			),
+			NewBrokerCell(brokerCellName, systemNS, WithBrokerCellReady),
+		},
+		Key: testNS + "/" + channelName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic %q`, retryID),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription %q`, retryID),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Channel reconciled: "%s/%s"`, testNS, channelName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelBrokerCell(brokerCellName),
+				WithChannelFinalizers(resourceGroup),
+				WithInitChannelBrokerCellConditions,
+				WithChannelBrokerCellReady,
+				WithChannelAddress(brokerCellChannelAddress),
+				WithChannelProjectID(testProject),
+				WithChannelTopic(decouplingID),
+				WithChannelSubscriptionReady,
+			),
+		}},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				TopicAndSub(decouplingID, decouplingID),
+				TopicAndSub(retryID, retryID),
+			},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			OnlyTopics(decouplingID),
+			OnlySubscriptions(decouplingID),
+		},
+	}, {
+		Name: "channel is deleted",
+		Objects: []runtime.Object{
+			NewChannel(channelName, testNS,
+				WithChannelUID(channelUID),
+				WithChannelBrokerCell(brokerCellName),
+				WithChannelSubscribers(brokerCellSubscriber(nil)),
+				WithChannelFinalizers(resourceGroup),
+				WithChannelDeletionTimestamp,
+			),
+		},
+		Key: testNS + "/" + channelName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic %q`, decouplingID),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription %q`, decouplingID),
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic %q`, retryID),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription %q`, retryID),
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", channelName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, channelName, false),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				TopicAndSub(decouplingID, decouplingID),
+				TopicAndSub(retryID, retryID),
+			},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			NoTopicsExist(),
+			NoSubscriptionsExist(),
+		},
+	}}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		srv := pstest.NewServer()
+		psclient, _ := GetTestClientCreateFunc(srv.Addr)(ctx, testProject)
+		t.Cleanup(func() { srv.Close() })
+		if testData != nil {
+			InjectPubsubClient(testData, psclient)
+			if testData["pre"] != nil {
+				for _, f := range testData["pre"].([]PubsubAction) {
+					f(ctx, t, psclient)
+				}
+			}
+		}
+		r := &Reconciler{
+			Base:             reconciler.NewBase(ctx, controllerAgentName, cmw),
+			Identity:         identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			channelLister:    listers.GetChannelLister(),
+			topicLister:      listers.GetV1beta1TopicLister(),
+			brokerCellLister: listers.GetBrokerCellLister(),
+			projectID:        testProject,
+			pubsubClient:     psclient,
+		}
+		return channel.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetChannelLister(), r.Recorder, r)
+	}))
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -34,6 +35,7 @@ import (
 	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
 	channelreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/channel"
+	brokercelllisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1alpha1"
 	inteventslisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1beta1"
 	listers "github.com/google/knative-gcp/pkg/client/listers/messaging/v1beta1"
 	"github.com/google/knative-gcp/pkg/reconciler"
@@ -58,8 +60,15 @@ type Reconciler struct {
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
 	// listers index properties about resources
-	channelLister listers.ChannelLister
-	topicLister   inteventslisters.TopicLister
+	channelLister    listers.ChannelLister
+	topicLister      inteventslisters.TopicLister
+	brokerCellLister brokercelllisters.BrokerCellLister
+
+	// projectID is the project of the topics and subscriptions of the
+	// Channels served by brokercells.
+	projectID string
+	// pubsubClient is used as the Pubsub client when present.
+	pubsubClient *pubsub.Client
 }
 
 // Check that our Reconciler implements Interface.
@@ -68,6 +77,10 @@ var _ channelreconciler.Interface = (*Reconciler)(nil)
 func (r *Reconciler) ReconcileKind(ctx context.Context, channel *v1beta1.Channel) pkgreconciler.Event {
 	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("channel", channel)))
 
+	if channel.BrokerCell() != "" {
+		return r.reconcileOnBrokerCell(ctx, channel)
+	}
+
 	channel.Status.InitializeConditions()
 	channel.Status.ObservedGeneration = channel.Generation
 
@@ -380,6 +393,10 @@ func (r *Reconciler) getPullSubscriptionStatus(ps *inteventsv1beta1.PullSubscrip
 }
 
 func (r *Reconciler) FinalizeKind(ctx context.Context, channel *v1beta1.Channel) pkgreconciler.Event {
+	if channel.BrokerCell() != "" {
+		return r.finalizeOnBrokerCell(ctx, channel)
+	}
+
 	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
 	// remove the corresponding GCP ServiceAccount iam policy binding.
 	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
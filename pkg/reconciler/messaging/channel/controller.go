@@ -21,12 +21,17 @@ import (
 
 	"knative.dev/pkg/injection"
 
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/system"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	brokercellinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell"
 	pullsubscriptioninformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1beta1/pullsubscription"
 	topicinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1beta1/topic"
 	channelinformer "github.com/google/knative-gcp/pkg/client/injection/informers/messaging/v1beta1/channel"
@@ -67,11 +72,14 @@ func newController(
 	pullSubscriptionInformer := pullsubscriptioninformer.Get(ctx)
 	serviceAccountInformer := serviceaccountinformers.Get(ctx)
 
+	brokerCellInformer := brokercellinformer.Get(ctx)
+
 	r := &Reconciler{
-		Base:          reconciler.NewBase(ctx, controllerAgentName, cmw),
-		Identity:      identity.NewIdentity(ctx, ipm, gcpas),
-		channelLister: channelInformer.Lister(),
-		topicLister:   topicInformer.Lister(),
+		Base:             reconciler.NewBase(ctx, controllerAgentName, cmw),
+		Identity:         identity.NewIdentity(ctx, ipm, gcpas),
+		channelLister:    channelInformer.Lister(),
+		topicLister:      topicInformer.Lister(),
+		brokerCellLister: brokerCellInformer.Lister(),
 	}
 	impl := channelreconciler.NewImpl(ctx, r)
 
@@ -95,5 +103,25 @@ func newController(
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	// Enqueue the Channels served by a brokercell when the brokercell changes.
+	brokerCellInformer.Informer().AddEventHandler(controller.HandleAll(
+		func(obj interface{}) {
+			bc, ok := obj.(*inteventsv1alpha1.BrokerCell)
+			if !ok || bc.Namespace != system.Namespace() {
+				return
+			}
+			channels, err := channelInformer.Lister().List(labels.Everything())
+			if err != nil {
+				r.Logger.Error("Failed to list channels", zap.Error(err))
+				return
+			}
+			for _, c := range channels {
+				if c.BrokerCell() == bc.Name {
+					impl.Enqueue(c)
+				}
+			}
+		},
+	))
+
 	return impl
 }
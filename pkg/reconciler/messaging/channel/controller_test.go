@@ -25,6 +25,7 @@ import (
 
 	// Fake injection informers
 
+	_ "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell/fake"
 	_ "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1beta1/pullsubscription/fake"
 	_ "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1beta1/topic/fake"
 	_ "github.com/google/knative-gcp/pkg/client/injection/informers/messaging/v1beta1/channel/fake"
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/network"
+	"knative.dev/pkg/system"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/ingress"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+)
+
+// MakeBrokerCell returns the BrokerCell that serves the Channel.
+func MakeBrokerCell(channel *v1beta1.Channel) *inteventsv1alpha1.BrokerCell {
+	return &inteventsv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   system.Namespace(),
+			Name:        channel.BrokerCell(),
+			Annotations: map[string]string{inteventsv1alpha1.CreatorKey: inteventsv1alpha1.Creator},
+		},
+	}
+}
+
+// BrokerCellAddress returns the address of the Channel on the ingress of the
+// BrokerCell with the given name.
+func BrokerCellAddress(brokerCellName string, channel *v1beta1.Channel) *apis.URL {
+	ingressServiceName := brokercellresources.Name(brokerCellName, brokercellresources.IngressName)
+	return &apis.URL{
+		Scheme: "http",
+		Host:   network.GetServiceHostname(ingressServiceName, system.Namespace()),
+		Path:   ingress.ChannelPath(channel.Namespace, channel.Name),
+	}
+}
+
+// HasManagedDeadLetterQueue returns true if the dead letter sink of the
+// subscriber delivery spec is an addressable. Its dead letter events are kept
+// in a dead letter topic managed by the Channel until the brokercell retry
+// delivers them. Dead letter sinks which are Pub/Sub topics are handled by
+// Pub/Sub directly.
+func HasManagedDeadLetterQueue(delivery *eventingduckv1beta1.DeliverySpec) bool {
+	return delivery != nil && delivery.DeadLetterSink != nil && delivery.DeadLetterSink.URI != nil &&
+		!brokerv1beta1.IsPubsubDeadLetterSink(delivery.DeadLetterSink)
+}
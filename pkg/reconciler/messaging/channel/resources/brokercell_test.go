@@ -0,0 +1,94 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	_ "knative.dev/pkg/system/testing"
+
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+)
+
+func TestMakeBrokerCell(t *testing.T) {
+	channel := &v1beta1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "default",
+			Annotations: map[string]string{v1beta1.BrokerCellAnnotationKey: "team-a"},
+		},
+	}
+	want := &inteventsv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "knative-testing",
+			Name:        "team-a",
+			Annotations: map[string]string{inteventsv1alpha1.CreatorKey: inteventsv1alpha1.Creator},
+		},
+	}
+	if diff := cmp.Diff(want, MakeBrokerCell(channel)); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestBrokerCellAddress(t *testing.T) {
+	channel := &v1beta1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+		},
+	}
+	want := "http://team-a-brokercell-ingress.knative-testing.svc.cluster.local/channel/default/foo"
+	if got := BrokerCellAddress("team-a", channel).String(); got != want {
+		t.Errorf("unexpected address, want %q, got %q", want, got)
+	}
+}
+
+func TestHasManagedDeadLetterQueue(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		delivery *eventingduckv1beta1.DeliverySpec
+		want     bool
+	}{{
+		name: "no delivery spec",
+	}, {
+		name:     "no dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{},
+	}, {
+		name: "pubsub dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "topic"}},
+		},
+	}, {
+		name: "addressable dead letter sink",
+		delivery: &eventingduckv1beta1.DeliverySpec{
+			DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dls.example.com")},
+		},
+		want: true,
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := HasManagedDeadLetterQueue(tc.delivery); got != tc.want {
+				t.Errorf("HasManagedDeadLetterQueue() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -43,6 +43,38 @@ func GeneratePublisherName(channel *v1beta1.Channel) string {
 	return kmeta.ChildName(fmt.Sprintf("cre-%s", channel.Name), "-chan")
 }
 
+// GenerateDecouplingSubscriptionName generates the name of the Pub/Sub
+// subscription the brokercell fanout pulls the events of a Channel from. It is
+// only used by Channels served by a brokercell.
+func GenerateDecouplingSubscriptionName(channel *v1beta1.Channel) string {
+	return naming.TruncatedPubsubResourceName("cre-chan", channel.Namespace, channel.Name, channel.UID)
+}
+
+// GenerateRetryTopicName generates the name of the Pub/Sub retry topic of a
+// subscriber of a Channel served by a brokercell.
+func GenerateRetryTopicName(channel *v1beta1.Channel, subscriberUID types.UID) string {
+	return naming.TruncatedPubsubResourceName("cre-chsub", channel.Namespace, channel.Name, subscriberUID)
+}
+
+// GenerateRetrySubscriptionName generates the name of the Pub/Sub retry
+// subscription of a subscriber of a Channel served by a brokercell.
+func GenerateRetrySubscriptionName(channel *v1beta1.Channel, subscriberUID types.UID) string {
+	return naming.TruncatedPubsubResourceName("cre-chsub", channel.Namespace, channel.Name, subscriberUID)
+}
+
+// GenerateDeadLetterTopicName generates the name of the managed dead letter
+// topic of a subscriber of a Channel served by a brokercell.
+func GenerateDeadLetterTopicName(channel *v1beta1.Channel, subscriberUID types.UID) string {
+	return naming.TruncatedPubsubResourceName("cre-chdlq", channel.Namespace, channel.Name, subscriberUID)
+}
+
+// GenerateDeadLetterSubscriptionName generates the name of the subscription of
+// the managed dead letter topic of a subscriber of a Channel served by a
+// brokercell.
+func GenerateDeadLetterSubscriptionName(channel *v1beta1.Channel, subscriberUID types.UID) string {
+	return naming.TruncatedPubsubResourceName("cre-chdlq", channel.Namespace, channel.Name, subscriberUID)
+}
+
 // GeneratePullSubscriptionName generates the name of the PullSubscription resource using the subscriber's UID.
 func GeneratePullSubscriptionName(UID types.UID) string {
 	return fmt.Sprintf("%s%s", subscriptionNamePrefix, string(UID))
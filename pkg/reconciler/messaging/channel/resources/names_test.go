@@ -84,3 +84,44 @@ func TestExtractUIDFromSubscriptionName(t *testing.T) {
 		t.Errorf("unexpected (-want, +got) = %v", diff)
 	}
 }
+
+func TestGenerateBrokerCellNames(t *testing.T) {
+	channel := &v1beta1.Channel{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			UID:       "a-uid",
+		},
+	}
+	for _, tc := range []struct {
+		name string
+		got  string
+		want string
+	}{{
+		name: "decoupling subscription",
+		got:  GenerateDecouplingSubscriptionName(channel),
+		want: "cre-chan_default_foo_a-uid",
+	}, {
+		name: "retry topic",
+		got:  GenerateRetryTopicName(channel, "sub-uid"),
+		want: "cre-chsub_default_foo_sub-uid",
+	}, {
+		name: "retry subscription",
+		got:  GenerateRetrySubscriptionName(channel, "sub-uid"),
+		want: "cre-chsub_default_foo_sub-uid",
+	}, {
+		name: "dead letter topic",
+		got:  GenerateDeadLetterTopicName(channel, "sub-uid"),
+		want: "cre-chdlq_default_foo_sub-uid",
+	}, {
+		name: "dead letter subscription",
+		got:  GenerateDeadLetterSubscriptionName(channel, "sub-uid"),
+		want: "cre-chdlq_default_foo_sub-uid",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.got); diff != "" {
+				t.Errorf("unexpected (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/duck"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
@@ -179,3 +180,101 @@ func WithBrokerDeliverySpec(deliverySpec *eventingduckv1beta1.DeliverySpec) Brok
 		b.Spec.Delivery = deliverySpec
 	}
 }
+
+// WithBrokerDeliveryAudience sets the audience of the ID token sent to the
+// subscribers of the Broker's Triggers.
+func WithBrokerDeliveryAudience(audience string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.DeliveryAudienceAnnotationKey] = audience
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerBacklogReporting enables the backlog reporting of the Broker.
+func WithBrokerBacklogReporting(b *brokerv1beta1.Broker) {
+	annotations := b.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[duck.BacklogReportingAnnotation] = "true"
+	b.SetAnnotations(annotations)
+}
+
+func WithBrokerBacklog(undeliveredMessages int64, oldestUnackedMessageAge time.Duration) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		b.Status.SetBacklog(undeliveredMessages, oldestUnackedMessageAge)
+	}
+}
+
+func WithBrokerBacklogHealthy(b *brokerv1beta1.Broker) {
+	b.Status.MarkBacklogHealthy()
+}
+
+func WithBrokerBacklogUnknown(reason, msg string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		b.Status.MarkBacklogUnknown(reason, msg)
+	}
+}
+
+// WithBrokerAssignedBrokerCell assigns the Broker to the BrokerCell with the
+// given name.
+func WithBrokerAssignedBrokerCell(name string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		labels := b.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string, 1)
+		}
+		labels[brokerv1beta1.BrokerCellLabelKey] = name
+		b.SetLabels(labels)
+	}
+}
+
+// WithBrokerRequestedBrokerCell requests the BrokerCell with the given name
+// to serve the Broker.
+func WithBrokerRequestedBrokerCell(name string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.BrokerCellAnnotationKey] = name
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerMovingFromBrokerCell sets the BrokerCell the Broker is moving from
+// and the deadline for draining it.
+func WithBrokerMovingFromBrokerCell(name string, deadline time.Time) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		labels := b.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string, 1)
+		}
+		labels[brokerv1beta1.PreviousBrokerCellLabelKey] = name
+		b.SetLabels(labels)
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.BrokerCellDrainDeadlineAnnotationKey] = deadline.Format(time.RFC3339)
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerIngressPolicy sets the service accounts allowed to publish events
+// to the Broker and the audience of their tokens.
+func WithBrokerIngressPolicy(allowedServiceAccounts, audience string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 2)
+		}
+		annotations[brokerv1beta1.IngressAllowedServiceAccountsAnnotationKey] = allowedServiceAccounts
+		annotations[brokerv1beta1.IngressAudienceAnnotationKey] = audience
+		b.SetAnnotations(annotations)
+	}
+}
@@ -185,3 +185,46 @@ func WithChannelAnnotations(Annotations map[string]string) ChannelOption {
 		c.ObjectMeta.Annotations = Annotations
 	}
 }
+
+// WithChannelBrokerCell places the Channel on the brokercell with the given
+// name.
+func WithChannelBrokerCell(name string) ChannelOption {
+	return func(c *v1beta1.Channel) {
+		if c.Annotations == nil {
+			c.Annotations = make(map[string]string)
+		}
+		c.Annotations[v1beta1.BrokerCellAnnotationKey] = name
+	}
+}
+
+// WithInitChannelBrokerCellConditions initializes the conditions of a Channel
+// served by a brokercell.
+func WithInitChannelBrokerCellConditions(c *v1beta1.Channel) {
+	c.Status.InitializeBrokerCellConditions()
+}
+
+func WithChannelBrokerCellReady(c *v1beta1.Channel) {
+	c.Status.MarkBrokerCellReady()
+}
+
+func WithChannelBrokerCellUnknown(reason, message string) ChannelOption {
+	return func(c *v1beta1.Channel) {
+		c.Status.MarkBrokerCellUnknown(reason, message)
+	}
+}
+
+func WithChannelSubscriptionReady(c *v1beta1.Channel) {
+	c.Status.MarkSubscriptionReady()
+}
+
+func WithChannelProjectID(projectID string) ChannelOption {
+	return func(c *v1beta1.Channel) {
+		c.Status.ProjectID = projectID
+	}
+}
+
+func WithChannelFinalizers(finalizers ...string) ChannelOption {
+	return func(c *v1beta1.Channel) {
+		c.Finalizers = finalizers
+	}
+}
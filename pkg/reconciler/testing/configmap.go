@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/brokerdelivery"
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -135,3 +136,29 @@ func NewBrokerDeliveryConfigMapFromDeliverySpec(spec *eventingduckv1beta1.Delive
 		},
 	}
 }
+
+// NewBrokerPlacementConfigMap creates a new broker placement configuration map
+// with the given cluster placement policy.
+func NewBrokerPlacementConfigMap(strategy brokerplacement.Strategy, brokerCell string, brokerCells int) *corev1.ConfigMap {
+	var sb strings.Builder
+	sb.WriteString("\n  clusterDefaults:")
+	sb.WriteString("\n    strategy: ")
+	sb.WriteString(string(strategy))
+	if brokerCell != "" {
+		sb.WriteString("\n    brokerCell: ")
+		sb.WriteString(brokerCell)
+	}
+	if brokerCells > 0 {
+		sb.WriteString("\n    brokerCells: ")
+		sb.WriteString(fmt.Sprint(brokerCells))
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      brokerplacement.ConfigMapName(),
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{
+			"default-br-placement-config": sb.String(),
+		},
+	}
+}
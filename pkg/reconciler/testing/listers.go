@@ -146,6 +146,14 @@ func (l *Listers) GetCloudSchedulerSourceLister() eventslisters.CloudSchedulerSo
 	return eventslisters.NewCloudSchedulerSourceLister(l.indexerFor(&EventsV1.CloudSchedulerSource{}))
 }
 
+func (l *Listers) GetCloudFirestoreSourceLister() eventslisters.CloudFirestoreSourceLister {
+	return eventslisters.NewCloudFirestoreSourceLister(l.indexerFor(&EventsV1.CloudFirestoreSource{}))
+}
+
+func (l *Listers) GetCloudMonitoringAlertSourceLister() eventslisters.CloudMonitoringAlertSourceLister {
+	return eventslisters.NewCloudMonitoringAlertSourceLister(l.indexerFor(&EventsV1.CloudMonitoringAlertSource{}))
+}
+
 func (l *Listers) GetCloudPubSubSourceLister() eventslisters.CloudPubSubSourceLister {
 	return eventslisters.NewCloudPubSubSourceLister(l.indexerFor(&EventsV1.CloudPubSubSource{}))
 }
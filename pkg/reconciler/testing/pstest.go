@@ -53,6 +53,16 @@ func SubscriptionWithTopic(id string, tid string) PubsubAction {
 	}
 }
 
+func SubscriptionWithTopicAndLabels(id string, tid string, labels map[string]string) PubsubAction {
+	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
+		_, err := c.CreateSubscription(ctx, id, pubsub.SubscriptionConfig{Topic: c.Topic(tid), Labels: labels})
+		if err != nil {
+			t.Fatalf("Error creating subscription %q: %v", id, err)
+		}
+		t.Logf("Created subscription %q", id)
+	}
+}
+
 func TopicAndSub(tid, sid string) PubsubAction {
 	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
 		Topic(tid)(ctx, t, c)
@@ -159,6 +169,34 @@ func SubscriptionHasDeadLetterPolicy(id string, wantPolicy *pubsub.DeadLetterPol
 	}
 }
 
+func SubscriptionHasFilter(id string, wantFilter string) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if diff := cmp.Diff(wantFilter, cfg.Filter); diff != "" {
+			t.Errorf("Pubsub config filter (-want,+got): %v", diff)
+		}
+	}
+}
+
+func SubscriptionHasRetainAckedMessages(id string, want bool) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		sub := c.Subscription(id)
+		cfg, err := sub.Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting pubsub config: %v", err)
+		}
+		if cfg.RetainAckedMessages != want {
+			t.Errorf("Pubsub config retain acked messages got: %v, want: %v", cfg.RetainAckedMessages, want)
+		}
+	}
+}
+
 func OnlySubscriptions(ids ...string) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	logtesting "knative.dev/pkg/logging/testing"
 
+	"github.com/google/knative-gcp/pkg/apis/configs/brokerplacement"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 )
@@ -41,3 +42,11 @@ func NewDataresidencyTestStore(t *testing.T, config *corev1.ConfigMap) *dataresi
 	}
 	return dataresidencyTestStore
 }
+
+func NewBrokerPlacementTestStore(t *testing.T, config *corev1.ConfigMap) *brokerplacement.Store {
+	brokerPlacementTestStore := brokerplacement.NewStore(logtesting.TestLogger(t))
+	if config != nil {
+		brokerPlacementTestStore.OnConfigChanged(config)
+	}
+	return brokerPlacementTestStore
+}
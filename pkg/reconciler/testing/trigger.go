@@ -21,8 +21,10 @@ import (
 	"time"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/duck"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/apis/eventing"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
@@ -44,8 +46,10 @@ func NewTrigger(name, namespace, broker string, to ...TriggerOption) *brokerv1be
 				eventing.BrokerLabelKey: broker,
 			},
 		},
-		Spec: eventingv1beta1.TriggerSpec{
-			Broker: broker,
+		Spec: brokerv1beta1.TriggerSpec{
+			TriggerSpec: eventingv1beta1.TriggerSpec{
+				Broker: broker,
+			},
 		},
 	}
 	for _, opt := range to {
@@ -220,6 +224,179 @@ func WithTriggerFinalizers(finalizers ...string) TriggerOption {
 	}
 }
 
+func WithTriggerDeliverySpec(ds *eventingduckv1beta1.DeliverySpec) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Spec.Delivery = ds
+	}
+}
+
+func WithTriggerAttributesFilter(attributes map[string]string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Spec.Filter = &eventingv1beta1.TriggerFilter{Attributes: attributes}
+	}
+}
+
+func WithTriggerFilters(filters ...brokerv1beta1.SubscriptionsAPIFilter) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Spec.Filters = filters
+	}
+}
+
+func WithTriggerBatchPolicy(maxSize, maxLinger string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.BatchMaxSizeAnnotationKey] = maxSize
+		t.Annotations[brokerv1beta1.BatchMaxLingerAnnotationKey] = maxLinger
+	}
+}
+
+func WithTriggerRateLimit(maxConcurrency, maxEventsPerSecond string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.MaxConcurrencyAnnotationKey] = maxConcurrency
+		t.Annotations[brokerv1beta1.MaxEventsPerSecondAnnotationKey] = maxEventsPerSecond
+	}
+}
+
+func WithTriggerCircuitBreaker(failureThreshold, openDuration string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.CircuitBreakerFailureThresholdAnnotationKey] = failureThreshold
+		t.Annotations[brokerv1beta1.CircuitBreakerOpenDurationAnnotationKey] = openDuration
+	}
+}
+
+func WithTriggerDeliveryAudience(audience string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.DeliveryAudienceAnnotationKey] = audience
+	}
+}
+
+func WithTriggerReplayable(t *brokerv1beta1.Trigger) {
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string)
+	}
+	t.Annotations[brokerv1beta1.ReplayableAnnotationKey] = "true"
+}
+
+func WithTriggerSnapshot(snapshotID string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.SnapshotAnnotationKey] = snapshotID
+	}
+}
+
+func WithTriggerReplayTime(replayTime string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.ReplayTimeAnnotationKey] = replayTime
+	}
+}
+
+func WithTriggerReplaySnapshot(snapshotID string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.ReplaySnapshotAnnotationKey] = snapshotID
+	}
+}
+
+func WithTriggerReplayed(reason, msg string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkReplayed(reason, msg)
+	}
+}
+
+func WithTriggerReplayFailed(reason, msg string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkReplayFailed(reason, msg)
+	}
+}
+
+func WithTriggerIsolation(isolation string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.IsolationAnnotationKey] = isolation
+	}
+}
+
+func WithTriggerPaused(t *brokerv1beta1.Trigger) {
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string)
+	}
+	t.Annotations[brokerv1beta1.PausedAnnotationKey] = "true"
+}
+
+func WithTriggerPausedCondition(t *brokerv1beta1.Trigger) {
+	t.Status.MarkPaused()
+}
+
+// WithTriggerBacklogThreshold enables the backlog reporting of the Trigger
+// with the given threshold of undelivered messages.
+func WithTriggerBacklogThreshold(threshold string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[duck.BacklogThresholdAnnotation] = threshold
+	}
+}
+
+func WithTriggerBacklog(undeliveredMessages int64, oldestUnackedMessageAge time.Duration) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.SetBacklog(undeliveredMessages, oldestUnackedMessageAge)
+	}
+}
+
+func WithTriggerBacklogThresholdExceeded(msg string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkBacklogThresholdExceeded(msg)
+	}
+}
+
+func WithTriggerIsolatedSubscriptionReady(t *brokerv1beta1.Trigger) {
+	t.Status.MarkIsolatedSubscriptionReady()
+}
+
+// WithTriggerIsolatedSubscriptionRecreating reports the isolated subscription
+// of the Trigger not ready since the given time, while it is recreated.
+func WithTriggerIsolatedSubscriptionRecreating(since time.Time) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkIsolatedSubscriptionUnknown("SubscriptionRecreating", "Recreating the isolated subscription to change its filter, the Broker's subscription delivers the events in the meantime")
+		for i := range t.Status.Conditions {
+			if t.Status.Conditions[i].Type == brokerv1beta1.TriggerConditionIsolatedSubscription {
+				t.Status.Conditions[i].LastTransitionTime.Inner = metav1.NewTime(since)
+			}
+		}
+	}
+}
+
+func WithTriggerCircuitBreakerClosed(t *brokerv1beta1.Trigger) {
+	t.Status.MarkCircuitBreakerClosed()
+}
+
+func WithTriggerCircuitBreakerOpen(reason, msg string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkCircuitBreakerOpen(reason, msg)
+	}
+}
+
 func WithTriggerSetDefaults(t *brokerv1beta1.Trigger) {
 	t.SetDefaults(context.Background())
 }
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/reconciler/testing"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	v1 "github.com/google/knative-gcp/pkg/apis/events/v1"
+)
+
+// CloudFirestoreSourceOption enables further configuration of a CloudFirestoreSource.
+type CloudFirestoreSourceOption func(*v1.CloudFirestoreSource)
+
+// NewCloudFirestoreSource creates a CloudFirestoreSource with CloudFirestoreSourceOptions
+func NewCloudFirestoreSource(name, namespace string, so ...CloudFirestoreSourceOption) *v1.CloudFirestoreSource {
+	s := &v1.CloudFirestoreSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-firestore-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	return s
+}
+
+func WithCloudFirestoreSourceSink(gvk metav1.GroupVersionKind, name string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: testing.ApiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudFirestoreSourceLocation(location string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.Location = location
+	}
+}
+
+func WithCloudFirestoreSourceProject(project string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudFirestoreSourceDocument(document string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.Document = document
+	}
+}
+
+func WithCloudFirestoreSourceEventTypes(eventTypes ...string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Spec.EventTypes = eventTypes
+	}
+}
+
+func WithCloudFirestoreSourceDeletionTimestamp(s *v1.CloudFirestoreSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+// WithInitCloudFirestoreSourceConditions initializes the CloudFirestoreSources's conditions.
+func WithInitCloudFirestoreSourceConditions(s *v1.CloudFirestoreSource) {
+	s.Status.InitializeConditions()
+}
+
+// WithCloudFirestoreSourceTopicUnknown marks the condition that the
+// status of topic is Unknown.
+func WithCloudFirestoreSourceTopicUnknown(reason, message string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkTopicUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudFirestoreSourceTopicReady marks the condition that the
+// topic is ready.
+func WithCloudFirestoreSourceTopicReady(topicID, projectID string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkTopicReady(s.ConditionSet())
+		s.Status.TopicID = topicID
+		s.Status.ProjectID = projectID
+	}
+}
+
+// WithCloudFirestoreSourceTopicDeleted is a wrapper to indicate that the
+// topic is deleted. Inside the function, we still mark the status of topic to be ready,
+// as the status of topic is unchanged if the deletion is successful. We do not set the
+// topicID and projectID because they are set to empty when deleting the topic.
+func WithCloudFirestoreSourceTopicDeleted(s *v1.CloudFirestoreSource) {
+	s.Status.MarkTopicReady(s.ConditionSet())
+}
+
+// WithCloudFirestoreSourcePullSubscriptionReady marks the condition that the
+// topic is ready.
+func WithCloudFirestoreSourcePullSubscriptionReady(s *v1.CloudFirestoreSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudFirestoreSourcePullSubscriptionDeleted is a wrapper to indicate that the
+// PullSubscription is deleted. Inside the function, we still mark the status of
+// PullSubscription to be ready, as the status of PullSubscription is unchanged
+// if the deletion is successful.
+func WithCloudFirestoreSourcePullSubscriptionDeleted(s *v1.CloudFirestoreSource) {
+	s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+}
+
+// WithCloudFirestoreSourceChangeFeedNotReady marks the condition that the
+// CloudFirestoreSource change feeds are not ready.
+func WithCloudFirestoreSourceChangeFeedNotReady(reason, message string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkChangeFeedNotReady(reason, message)
+	}
+}
+
+// WithCloudFirestoreSourceChangeFeedUnknown marks the condition that the
+// CloudFirestoreSource change feeds are unknown.
+func WithCloudFirestoreSourceChangeFeedUnknown(reason, message string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkChangeFeedUnknown(reason, message)
+	}
+}
+
+// WithCloudFirestoreSourceChangeFeedReady marks the condition that the
+// CloudFirestoreSource change feeds are ready and sets Status.ChangeFeeds to feeds.
+func WithCloudFirestoreSourceChangeFeedReady(feeds ...string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.MarkChangeFeedReady(feeds)
+	}
+}
+
+// WithCloudFirestoreSourceSinkURI sets the status for sink URI
+func WithCloudFirestoreSourceSinkURI(url *apis.URL) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudFirestoreSourceSubscriptionID(subscriptionID string) CloudFirestoreSourceOption {
+	return func(s *v1.CloudFirestoreSource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudFirestoreSourceSetDefaults(s *v1.CloudFirestoreSource) {
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+}
@@ -91,6 +91,10 @@ func WithCloudSchedulerSourceData(data string) CloudSchedulerSourceOption {
 	}
 }
 
+func WithCloudSchedulerSourcePaused(s *v1.CloudSchedulerSource) {
+	s.Spec.Paused = true
+}
+
 func WithCloudSchedulerSourceDeletionTimestamp(s *v1.CloudSchedulerSource) {
 	t := metav1.NewTime(time.Unix(1e9, 0))
 	s.ObjectMeta.SetDeletionTimestamp(&t)
@@ -60,6 +60,12 @@ func WithCloudStorageSourceBucket(bucket string) CloudStorageSourceOption {
 	}
 }
 
+func WithCloudStorageSourceFilter(filter *v1.CloudStorageObjectFilter) CloudStorageSourceOption {
+	return func(s *v1.CloudStorageSource) {
+		s.Spec.Filter = filter
+	}
+}
+
 func WithCloudStorageSourceProject(project string) CloudStorageSourceOption {
 	return func(s *v1.CloudStorageSource) {
 		s.Spec.Project = project
@@ -42,7 +42,10 @@ import (
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
 	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 	"github.com/google/knative-gcp/pkg/utils"
 )
 
@@ -95,11 +98,14 @@ func newController(ctx context.Context, cmw configmap.Watcher, drs *dataresidenc
 		Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
 		brokerLister:       brokerinformer.Get(ctx).Lister(),
 		pubsubClient:       client,
+		replayClient:       gpubsub.WrapClient,
+		backlogReporter:    backlog.NewReporter(ctx, gmonitoring.NewClient),
 		projectID:          projectID,
 		dataresidencyStore: drs,
 	}
 
 	impl := triggerreconciler.NewImpl(ctx, r, withAgentAndFinalizer)
+	r.enqueueAfter = impl.EnqueueAfter
 	r.sourceTracker = duck.NewListableTracker(ctx, source.Get, impl.EnqueueKey, controller.GetTrackerLease(ctx))
 	r.addressableTracker = duck.NewListableTracker(ctx, addressable.Get, impl.EnqueueKey, controller.GetTrackerLease(ctx))
 	r.uriResolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
+)
+
+// isolatedSubscriptionFallbackPeriod is how long the isolated subscription of a
+// Trigger is reported not ready before it is recreated. It covers the
+// propagation of the targets config to the data plane, including through the
+// mounted ConfigMap.
+const isolatedSubscriptionFallbackPeriod = 2 * time.Minute
+
+// isolatedSubscriptionRetentionDuration is how long the isolated subscription
+// of a Trigger retains its events. Seven days is the default and the longest
+// supported retention, acknowledged events are only retained if the Trigger is
+// replayable.
+const isolatedSubscriptionRetentionDuration = 7 * 24 * time.Hour
+
+// reconcileIsolatedSubscription reconciles the dedicated subscription of an
+// isolated Trigger on the decoupling topic of its Broker, and deletes it once
+// the Trigger is no longer isolated.
+func (r *Reconciler) reconcileIsolatedSubscription(ctx context.Context, trig *brokerv1beta1.Trigger, b *brokerv1beta1.Broker, client *pubsub.Client, pubsubReconciler *reconcilerutilspubsub.Reconciler, labels map[string]string, enableOrdering bool) error {
+	subID := resources.GenerateIsolatedSubscriptionName(trig)
+	if isolated, _ := trig.Isolation(); !isolated {
+		// Only look for a subscription to delete if the Trigger was isolated.
+		if trig.Status.GetCondition(brokerv1beta1.TriggerConditionIsolatedSubscription) == nil {
+			return nil
+		}
+		if err := pubsubReconciler.DeleteSubscription(ctx, subID, trig, &trig.Status); err != nil {
+			return err
+		}
+		trig.Status.ClearIsolatedSubscriptionCondition()
+		return nil
+	}
+
+	logger := logging.FromContext(ctx)
+	filter := resources.IsolatedSubscriptionFilter(trig)
+	cfg, err := client.Subscription(subID).Config(ctx)
+	switch {
+	case status.Code(err) == codes.NotFound:
+	case err != nil:
+		logger.Error("Failed to get Pub/Sub subscription Config", zap.Error(err))
+		trig.Status.MarkSubscriptionUnknown("SubscriptionConfigUnknown", "Failed to get Pub/Sub subscription Config: %v", err)
+		return err
+	case cfg.Filter != filter:
+		// The filter of a subscription can't be updated, so the subscription is
+		// recreated. It is first reported not ready, and only deleted once the
+		// data plane had time to deliver the events of the Trigger from the
+		// Broker's decouple subscription instead.
+		if wait := r.isolatedSubscriptionFallbackWait(trig); wait > 0 {
+			logger.Info("Waiting for the data plane to stop using the isolated subscription before recreating it", zap.String("subscription", subID), zap.Duration("wait", wait))
+			if r.enqueueAfter != nil {
+				r.enqueueAfter(trig, wait)
+			}
+			return nil
+		}
+		logger.Info("Recreating the isolated subscription to change its filter", zap.String("subscription", subID))
+		if err := pubsubReconciler.DeleteSubscription(ctx, subID, trig, &trig.Status); err != nil {
+			return err
+		}
+	}
+
+	subConfig := pubsub.SubscriptionConfig{
+		Topic:                 client.Topic(resources.GenerateDecouplingTopicName(b)),
+		Labels:                labels,
+		Filter:                filter,
+		EnableMessageOrdering: enableOrdering,
+		RetainAckedMessages:   trig.IsReplayable(),
+		RetentionDuration:     isolatedSubscriptionRetentionDuration,
+	}
+	if _, err := pubsubReconciler.ReconcileSubscription(ctx, subID, subConfig, trig, &trig.Status); err != nil {
+		return err
+	}
+	trig.Status.MarkIsolatedSubscriptionReady()
+	return nil
+}
+
+// isolatedSubscriptionFallbackWait reports the isolated subscription of the
+// Trigger not ready, if it isn't already, and returns how long to wait until
+// the data plane stopped using it.
+func (r *Reconciler) isolatedSubscriptionFallbackWait(trig *brokerv1beta1.Trigger) time.Duration {
+	cond := trig.Status.GetCondition(brokerv1beta1.TriggerConditionIsolatedSubscription)
+	if cond == nil {
+		// The data plane never used the subscription.
+		return 0
+	}
+	if cond.IsTrue() {
+		trig.Status.MarkIsolatedSubscriptionUnknown("SubscriptionRecreating", "Recreating the isolated subscription to change its filter, the Broker's subscription delivers the events in the meantime")
+		return isolatedSubscriptionFallbackPeriod
+	}
+	return isolatedSubscriptionFallbackPeriod - time.Since(cond.LastTransitionTime.Inner.Time)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/logging"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+)
+
+const (
+	// Name of the corev1.Events emitted when replaying a Trigger's subscription.
+	snapshotCreated    = "SnapshotCreated"
+	subscriptionSeeked = "SubscriptionSeeked"
+
+	// snapshotLabel and replayLabel are the labels of the isolated
+	// subscription that record the last snapshot created and the last replay
+	// applied, so that each request is only applied once.
+	snapshotLabel = "snapshot"
+	replayLabel   = "replay"
+)
+
+// reconcileReplay creates the Pub/Sub snapshot of the isolated subscription of
+// the Trigger, which delivers all its events, and seeks the subscription as
+// requested by the Trigger's annotations. Requests already reported by the
+// Replayed condition are not looked up again.
+func (r *Reconciler) reconcileReplay(ctx context.Context, trig *brokerv1beta1.Trigger, client *pubsub.Client) error {
+	snapshotID := trig.Snapshot()
+	replayTime, replaySnapshotID := trig.Replay()
+	if snapshotID == "" && replayTime.IsZero() && replaySnapshotID == "" {
+		trig.Status.ClearReplayCondition()
+		return nil
+	}
+	replay := replayDescription(replayTime, replaySnapshotID)
+	reason, message := replayedCondition(snapshotID, replay)
+	if cond := trig.Status.GetCondition(brokerv1beta1.TriggerConditionReplay); cond != nil && cond.IsTrue() && cond.Message == message {
+		return nil
+	}
+
+	logger := logging.FromContext(ctx)
+	if !trig.Status.GetCondition(brokerv1beta1.TriggerConditionIsolatedSubscription).IsTrue() {
+		// The isolated subscription is being recreated, the Trigger is
+		// reconciled again once it is ready.
+		logger.Info("Waiting for the isolated subscription to be ready before replaying it")
+		return nil
+	}
+	subID := resources.GenerateIsolatedSubscriptionName(trig)
+	replayClient := r.replayClient(client)
+	sub := replayClient.Subscription(subID)
+	cfg, err := sub.Config(ctx)
+	if err != nil {
+		logger.Error("Failed to get Pub/Sub subscription Config", zap.Error(err))
+		trig.Status.MarkReplayFailed("SubscriptionConfigUnknown", "Failed to get Pub/Sub subscription Config: %v", err)
+		return err
+	}
+	labels := make(map[string]string, len(cfg.Labels)+2)
+	for k, v := range cfg.Labels {
+		labels[k] = v
+	}
+
+	if snapshotID != "" && labels[snapshotLabel] != requestLabelValue(snapshotID) {
+		_, err := sub.CreateSnapshot(ctx, snapshotID)
+		switch {
+		case status.Code(err) == codes.AlreadyExists:
+			// The snapshot was created by a previous reconciliation which
+			// failed to record it.
+			logger.Info("PubSub snapshot already exists", zap.String("snapshot", snapshotID))
+		case err != nil:
+			logger.Error("Failed to create Pub/Sub snapshot", zap.String("snapshot", snapshotID), zap.Error(err))
+			trig.Status.MarkReplayFailed("SnapshotCreationFailed", "Failed to create Pub/Sub snapshot %q: %v", snapshotID, err)
+			return err
+		default:
+			logger.Info("Created PubSub snapshot", zap.String("snapshot", snapshotID))
+			r.Recorder.Eventf(trig, corev1.EventTypeNormal, snapshotCreated, "Created PubSub snapshot %q", snapshotID)
+		}
+		labels[snapshotLabel] = requestLabelValue(snapshotID)
+	}
+
+	if replay != "" && labels[replayLabel] != requestLabelValue(replay) {
+		if replaySnapshotID != "" {
+			err = sub.SeekToSnapshot(ctx, replayClient.Snapshot(replaySnapshotID))
+		} else {
+			err = sub.SeekToTime(ctx, replayTime)
+		}
+		if err != nil {
+			logger.Error("Failed to seek Pub/Sub subscription", zap.String("replay", replay), zap.Error(err))
+			trig.Status.MarkReplayFailed("SeekFailed", "Failed to seek Pub/Sub subscription to %s: %v", replay, err)
+			return err
+		}
+		logger.Info("Seeked PubSub subscription", zap.String("name", subID), zap.String("replay", replay))
+		r.Recorder.Eventf(trig, corev1.EventTypeNormal, subscriptionSeeked, "Seeked PubSub subscription %q to %s", subID, replay)
+		labels[replayLabel] = requestLabelValue(replay)
+	}
+
+	if len(labels) != len(cfg.Labels) || labels[snapshotLabel] != cfg.Labels[snapshotLabel] || labels[replayLabel] != cfg.Labels[replayLabel] {
+		cfg.Labels = labels
+		if _, err := sub.Update(ctx, cfg); err != nil {
+			logger.Error("Failed to update Pub/Sub subscription labels", zap.Error(err))
+			trig.Status.MarkReplayFailed("SubscriptionConfigUpdateFailed", "Failed to record the replay in the Pub/Sub subscription: %v", err)
+			return err
+		}
+	}
+
+	trig.Status.MarkReplayed(reason, message)
+	return nil
+}
+
+// replayedCondition returns the reason and message of the Replayed condition
+// once the requested snapshot and replay are applied.
+func replayedCondition(snapshotID, replay string) (reason, message string) {
+	switch {
+	case replay == "":
+		return "SnapshotCreated", fmt.Sprintf("Created snapshot %q", snapshotID)
+	case snapshotID == "":
+		return "Replayed", fmt.Sprintf("Replayed events from %s", replay)
+	default:
+		return "Replayed", fmt.Sprintf("Created snapshot %q and replayed events from %s", snapshotID, replay)
+	}
+}
+
+// replayDescription describes the point in time or the snapshot the Trigger's
+// subscription is seeked to, or returns an empty string if no replay is
+// requested.
+func replayDescription(replayTime time.Time, snapshotID string) string {
+	switch {
+	case snapshotID != "":
+		return fmt.Sprintf("snapshot %q", snapshotID)
+	case !replayTime.IsZero():
+		return replayTime.UTC().Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
+
+// requestLabelValue returns a fingerprint of a snapshot or replay request
+// which is a valid Pub/Sub label value.
+func requestLabelValue(request string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(request)))
+}
@@ -21,13 +21,13 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/rickb777/date/period"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/google/knative-gcp/pkg/logging"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/eventing/pkg/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -37,11 +37,14 @@ import (
 	"cloud.google.com/go/pubsub"
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
+	"github.com/google/knative-gcp/pkg/broker/config"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
 	"github.com/google/knative-gcp/pkg/utils"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
@@ -52,9 +55,10 @@ const (
 	triggerReconciled = "TriggerReconciled"
 	triggerFinalized  = "TriggerFinalized"
 
-	// Default maximum backoff duration used in the backoff retry policy for
-	// pubsub subscriptions. 600 seconds is the longest supported time.
-	defaultMaximumBackoff = 600 * time.Second
+
+	// circuitBreakerResyncPeriod is the period between reconciliations of
+	// Triggers with a circuit breaker, to update the circuit breaker state.
+	circuitBreakerResyncPeriod = time.Minute
 )
 
 var (
@@ -83,10 +87,22 @@ type Reconciler struct {
 	// pubsubClient is used as the Pubsub client when present.
 	pubsubClient *pubsub.Client
 
+	// replayClient wraps the Pub/Sub client to create snapshots of and seek
+	// the Trigger subscriptions. Changed in testing only.
+	replayClient func(*pubsub.Client) gpubsub.Client
+
 	dataresidencyStore *dataresidency.Store
 
 	// clusterRegion is the region where GKE is running
 	clusterRegion string
+
+	// backlogReporter reports the backlog of the Trigger subscriptions.
+	backlogReporter *backlog.Reporter
+
+	// enqueueAfter enqueues a Trigger after a delay. It is used to poll the
+	// circuit breaker state recorded by the data plane and the backlog of the
+	// Trigger subscriptions.
+	enqueueAfter func(obj interface{}, after time.Duration)
 }
 
 // Check that TriggerReconciler implements Interface
@@ -151,15 +167,17 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, t *brokerv1beta1.Trigger
 func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b *brokerv1beta1.Broker) pkgreconciler.Event {
 	t.Status.InitializeConditions()
 	t.Status.PropagateBrokerStatus(&b.Status)
+	if t.IsPaused() {
+		t.Status.MarkPaused()
+	} else {
+		t.Status.ClearPausedCondition()
+	}
 
 	if err := r.resolveSubscriber(ctx, t, b); err != nil {
 		return err
 	}
 
-	if b.Spec.Delivery == nil {
-		b.SetDefaults(ctx)
-	}
-	if err := r.reconcileRetryTopicAndSubscription(ctx, t, b.Spec.Delivery); err != nil {
+	if err := r.reconcileRetryTopicAndSubscription(ctx, t, b); err != nil {
 		return err
 	}
 
@@ -218,7 +236,11 @@ func hasGCPBrokerFinalizer(t *brokerv1beta1.Trigger) bool {
 	return false
 }
 
-func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, trig *brokerv1beta1.Trigger, deliverySpec *eventingduckv1beta1.DeliverySpec) error {
+func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, trig *brokerv1beta1.Trigger, b *brokerv1beta1.Broker) error {
+	// The delivery spec has been validated by the webhook.
+	deliverySpec := &eventingduckv1.DeliverySpec{}
+	_ = trig.DeliverySpecWithDefaults(ctx, b).ConvertTo(ctx, deliverySpec)
+	enableOrdering := b.OrderingKeyExtension() != ""
 	logger := logging.FromContext(ctx)
 	logger.Debug("Reconciling retry topic")
 	// get ProjectID from metadata
@@ -270,8 +292,11 @@ func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, tri
 	//TODO uncomment when eventing webhook allows this
 	//trig.Status.TopicID = topic.ID()
 
-	retryPolicy := getPubsubRetryPolicy(deliverySpec)
-	deadLetterPolicy := getPubsubDeadLetterPolicy(projectID, deliverySpec)
+	retryPolicy := reconcilerutilspubsub.RetryPolicy(deliverySpec)
+	deadLetterPolicy, err := r.reconcileDeadLetterTopicAndSubscription(ctx, trig, deliverySpec, projectID, pubsubReconciler, topicConfig, labels)
+	if err != nil {
+		return err
+	}
 
 	// Check if PullSub exists, and if not, create it.
 	subID := resources.GenerateRetrySubscriptionName(trig)
@@ -280,55 +305,106 @@ func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, tri
 		Labels:           labels,
 		RetryPolicy:      retryPolicy,
 		DeadLetterPolicy: deadLetterPolicy,
+		// Retried events keep the ordering key of the Broker's events.
+		EnableMessageOrdering: enableOrdering,
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
 	}
-	if _, err := pubsubReconciler.ReconcileSubscription(ctx, subID, subConfig, trig, &trig.Status); err != nil {
+	sub, err := pubsubReconciler.ReconcileSubscription(ctx, subID, subConfig, trig, &trig.Status)
+	if err != nil {
 		return err
 	}
 	// TODO(grantr): this isn't actually persisted due to webhook issues.
 	//TODO uncomment when eventing webhook allows this
 	//trig.Status.SubscriptionID = sub.ID()
 
-	return nil
+	r.propagateCircuitBreakerState(ctx, trig, sub)
+	if err := r.reconcileIsolatedSubscription(ctx, trig, b, client, pubsubReconciler, labels, enableOrdering); err != nil {
+		return err
+	}
+	r.reportBacklog(ctx, trig, projectID)
+	return r.reconcileReplay(ctx, trig, client)
 }
 
-// getPubsubRetryPolicy gets the eventing retry policy from the Broker delivery
-// spec and translates it to a pubsub retry policy.
-func getPubsubRetryPolicy(spec *eventingduckv1beta1.DeliverySpec) *pubsub.RetryPolicy {
-	// The Broker delivery spec is translated to a pubsub retry policy in the
-	// manner defined in the following post:
-	// https://github.com/google/knative-gcp/issues/1392#issuecomment-655617873
-	p, _ := period.Parse(*spec.BackoffDelay)
-	minimumBackoff, _ := p.Duration()
-	var maximumBackoff time.Duration
-	switch *spec.BackoffPolicy {
-	case eventingduckv1beta1.BackoffPolicyLinear:
-		maximumBackoff = minimumBackoff
-	case eventingduckv1beta1.BackoffPolicyExponential:
-		maximumBackoff = defaultMaximumBackoff
-	}
-	return &pubsub.RetryPolicy{
-		MinimumBackoff: minimumBackoff,
-		MaximumBackoff: maximumBackoff,
+// reportBacklog reports the backlog of the Trigger's retry subscription, and
+// of its dedicated subscription if it is isolated, when the backlog reporting
+// is enabled.
+func (r *Reconciler) reportBacklog(ctx context.Context, trig *brokerv1beta1.Trigger, projectID string) {
+	subIDs := []string{resources.GenerateRetrySubscriptionName(trig)}
+	if isolated, _ := trig.Isolation(); isolated {
+		subIDs = append(subIDs, resources.GenerateIsolatedSubscriptionName(trig))
+	}
+	if r.backlogReporter.Report(ctx, trig.Annotations, &trig.Status, projectID, subIDs...) && r.enqueueAfter != nil {
+		r.enqueueAfter(trig, backlog.ResyncPeriod)
 	}
 }
 
-// getPubsubDeadLetterPolicy gets the eventing dead letter policy from the
-// Broker delivery spec and translates it to a pubsub dead letter policy.
-func getPubsubDeadLetterPolicy(projectID string, spec *eventingduckv1beta1.DeliverySpec) *pubsub.DeadLetterPolicy {
-	if spec.DeadLetterSink == nil {
-		return nil
+// propagateCircuitBreakerState surfaces in the Trigger status the circuit
+// breaker state that the data plane records as a label of the retry
+// subscription.
+func (r *Reconciler) propagateCircuitBreakerState(ctx context.Context, trig *brokerv1beta1.Trigger, sub *pubsub.Subscription) {
+	if failureThreshold, _ := trig.CircuitBreaker(); failureThreshold == 0 {
+		trig.Status.ClearCircuitBreakerCondition()
+		return
 	}
-	// Translate to the pubsub dead letter policy format.
-	dlp := &pubsub.DeadLetterPolicy{
-		DeadLetterTopic: fmt.Sprintf("projects/%s/topics/%s", projectID, spec.DeadLetterSink.URI.Host),
+	// The data plane cannot notify the control plane of state changes, so
+	// poll the state while the circuit breaker is enabled.
+	if r.enqueueAfter != nil {
+		r.enqueueAfter(trig, circuitBreakerResyncPeriod)
 	}
-	if spec.Retry != nil {
-		dlp.MaxDeliveryAttempts = int(*spec.Retry)
+	cfg, err := sub.Config(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get Pub/Sub subscription Config", zap.Error(err))
+		trig.Status.MarkCircuitBreakerUnknown("SubscriptionConfigUnknown", "Failed to get the circuit breaker state: %v", err)
+		return
+	}
+	switch config.CircuitBreakerState(cfg.Labels[config.CircuitBreakerStateLabel]) {
+	case config.CircuitBreakerOpen:
+		trig.Status.MarkCircuitBreakerOpen("CircuitBreakerOpen", "Deliveries to the subscriber are suspended after consecutive failures")
+	case config.CircuitBreakerHalfOpen:
+		trig.Status.MarkCircuitBreakerUnknown("CircuitBreakerHalfOpen", "Probing whether the subscriber recovered")
+	default:
+		trig.Status.MarkCircuitBreakerClosed()
 	}
-	return dlp
+}
+
+// reconcileDeadLetterTopicAndSubscription reconciles the broker-managed dead
+// letter topic and subscription of the Trigger, and returns the dead letter
+// policy to apply to the Trigger's retry subscription. The broker-managed dead
+// letter topic is only used when the dead letter sink is not a Pub/Sub topic,
+// in which case the dead letter events are delivered to the resolved sink by
+// the data plane.
+func (r *Reconciler) reconcileDeadLetterTopicAndSubscription(ctx context.Context, trig *brokerv1beta1.Trigger, deliverySpec *eventingduckv1.DeliverySpec, projectID string, pubsubReconciler *reconcilerutilspubsub.Reconciler, topicConfig *pubsub.TopicConfig, labels map[string]string) (*pubsub.DeadLetterPolicy, error) {
+	if deliverySpec.DeadLetterSink == nil || brokerv1beta1.IsPubsubDeadLetterSink(deliverySpec.DeadLetterSink) {
+		// Clean up the dead letter topic and subscription in case the Trigger
+		// used to have a dead letter sink which is not a Pub/Sub topic.
+		if err := r.deleteDeadLetterTopicAndSubscription(ctx, pubsubReconciler, trig); err != nil {
+			return nil, err
+		}
+		return reconcilerutilspubsub.DeadLetterPolicy(projectID, deliverySpec), nil
+	}
+
+	if _, err := resources.ResolveDeadLetterSink(ctx, r.uriResolver, trig, deliverySpec.DeadLetterSink); err != nil {
+		logging.FromContext(ctx).Error("Unable to get the dead letter sink's URI", zap.Error(err))
+		trig.Status.MarkSubscriptionFailed("DeadLetterSinkResolveFailed", "Unable to get the dead letter sink's URI: %v", err)
+		return nil, err
+	}
+
+	topic, err := pubsubReconciler.ReconcileTopic(ctx, resources.GenerateDeadLetterTopicName(trig), topicConfig, trig, &trig.Status)
+	if err != nil {
+		return nil, err
+	}
+	subConfig := pubsub.SubscriptionConfig{
+		Topic:       topic,
+		Labels:      labels,
+		RetryPolicy: reconcilerutilspubsub.RetryPolicy(deliverySpec),
+	}
+	if _, err := pubsubReconciler.ReconcileSubscription(ctx, resources.GenerateDeadLetterSubscriptionName(trig), subConfig, trig, &trig.Status); err != nil {
+		return nil, err
+	}
+
+	return reconcilerutilspubsub.NewDeadLetterPolicy(projectID, topic.ID(), deliverySpec.Retry), nil
 }
 
 func (r *Reconciler) deleteRetryTopicAndSubscription(ctx context.Context, trig *brokerv1beta1.Trigger) error {
@@ -359,7 +435,15 @@ func (r *Reconciler) deleteRetryTopicAndSubscription(ctx context.Context, trig *
 	// Delete pull subscription if it exists.
 	subID := resources.GenerateRetrySubscriptionName(trig)
 	err = multierr.Append(err, pubsubReconciler.DeleteSubscription(ctx, subID, trig, &trig.Status))
-	return err
+	err = multierr.Append(err, pubsubReconciler.DeleteSubscription(ctx, resources.GenerateIsolatedSubscriptionName(trig), trig, &trig.Status))
+	return multierr.Append(err, r.deleteDeadLetterTopicAndSubscription(ctx, pubsubReconciler, trig))
+}
+
+// deleteDeadLetterTopicAndSubscription deletes the broker-managed dead letter
+// topic and subscription of the Trigger if they exist.
+func (r *Reconciler) deleteDeadLetterTopicAndSubscription(ctx context.Context, pubsubReconciler *reconcilerutilspubsub.Reconciler, trig *brokerv1beta1.Trigger) error {
+	err := pubsubReconciler.DeleteTopic(ctx, resources.GenerateDeadLetterTopicName(trig), trig, &trig.Status)
+	return multierr.Append(err, pubsubReconciler.DeleteSubscription(ctx, resources.GenerateDeadLetterSubscriptionName(trig), trig, &trig.Status))
 }
 
 func (r *Reconciler) checkDependencyAnnotation(ctx context.Context, t *brokerv1beta1.Trigger) error {
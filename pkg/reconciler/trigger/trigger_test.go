@@ -18,6 +18,7 @@ package trigger
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -44,10 +45,16 @@ import (
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/configs/dataresidency"
+	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1alpha1/resource"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	gmonitoringtesting "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+	gpubsubtesting "github.com/google/knative-gcp/pkg/gclient/pubsub/testing"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	"github.com/google/knative-gcp/pkg/reconciler/utils/backlog"
 )
 
 const (
@@ -55,6 +62,7 @@ const (
 	triggerName       = "test-trigger"
 	brokerName        = "test-broker"
 	testUID           = "abc123"
+	testBrokerUID     = "def456"
 	testProject       = "test-project-id"
 	testClusterRegion = "us-east1"
 
@@ -73,17 +81,23 @@ var (
 
 	testKey = fmt.Sprintf("%s/%s", testNS, triggerName)
 
-	triggerFinalizerUpdatedEvent   = Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "test-trigger" finalizers`)
-	triggerReconciledEvent         = Eventf(corev1.EventTypeNormal, "TriggerReconciled", `Trigger reconciled: "testnamespace/test-trigger"`)
-	triggerFinalizedEvent          = Eventf(corev1.EventTypeNormal, "TriggerFinalized", `Trigger finalized: "testnamespace/test-trigger"`)
-	topicCreatedEvent              = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
-	topicDeletedEvent              = Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
-	deadLetterTopicCreatedEvent    = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "test-dead-letter-topic-id"`)
-	subscriptionCreatedEvent       = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
-	subscriptionDeletedEvent       = Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
-	subscriptionConfigUpdatedEvent = Eventf(corev1.EventTypeNormal, "SubscriptionConfigUpdated", `Updated config for PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
-	subscriberAPIVersion           = fmt.Sprintf("%s/%s", subscriberGroup, subscriberVersion)
-	subscriberGVK                  = metav1.GroupVersionKind{
+	testDecouplingTopicID = fmt.Sprintf("cre-bkr_%s_%s_%s", testNS, brokerName, testBrokerUID)
+
+	triggerFinalizerUpdatedEvent     = Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "test-trigger" finalizers`)
+	triggerReconciledEvent           = Eventf(corev1.EventTypeNormal, "TriggerReconciled", `Trigger reconciled: "testnamespace/test-trigger"`)
+	triggerFinalizedEvent            = Eventf(corev1.EventTypeNormal, "TriggerFinalized", `Trigger finalized: "testnamespace/test-trigger"`)
+	topicCreatedEvent                = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
+	topicDeletedEvent                = Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
+	deadLetterTopicCreatedEvent      = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "test-dead-letter-topic-id"`)
+	deadLetterQueueTopicCreatedEvent = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-dlq_testnamespace_test-trigger_abc123"`)
+	deadLetterQueueSubCreatedEvent   = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-dlq_testnamespace_test-trigger_abc123"`)
+	subscriptionCreatedEvent         = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
+	subscriptionDeletedEvent         = Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
+	subscriptionConfigUpdatedEvent   = Eventf(corev1.EventTypeNormal, "SubscriptionConfigUpdated", `Updated config for PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
+	isolatedSubCreatedEvent          = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-iso_testnamespace_test-trigger_abc123"`)
+	isolatedSubDeletedEvent          = Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-iso_testnamespace_test-trigger_abc123"`)
+	subscriberAPIVersion             = fmt.Sprintf("%s/%s", subscriberGroup, subscriberVersion)
+	subscriberGVK                    = metav1.GroupVersionKind{
 		Group:   subscriberGroup,
 		Version: subscriberVersion,
 		Kind:    subscriberKind,
@@ -99,6 +113,23 @@ var (
 			},
 		},
 	}
+	brokerDeliverySpecWithHTTPDeadLetterSink = &eventingduckv1beta1.DeliverySpec{
+		BackoffDelay:  &backoffDelay,
+		BackoffPolicy: &backoffPolicy,
+		Retry:         &retry,
+		DeadLetterSink: &duckv1.Destination{
+			URI: &apis.URL{
+				Scheme: "http",
+				Host:   "dead-letter.example.com",
+			},
+		},
+	}
+	triggerBackoffDelay       = "PT10S"
+	triggerRetry        int32 = 7
+	triggerDeliverySpec       = &eventingduckv1beta1.DeliverySpec{
+		BackoffDelay: &triggerBackoffDelay,
+		Retry:        &triggerRetry,
+	}
 	brokerDeliverySpecWithoutRetry = &eventingduckv1beta1.DeliverySpec{
 		BackoffDelay:  &backoffDelay,
 		BackoffPolicy: &backoffPolicy,
@@ -429,6 +460,59 @@ func TestAllCasesTrigger(t *testing.T) {
 				}),
 			},
 		},
+		{
+			Name: "Trigger created, broker ready, dead letter sink is not a pubsub topic",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpecWithHTTPDeadLetterSink),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				deadLetterQueueTopicCreatedEvent,
+				deadLetterQueueSubCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "cre-dlq_testnamespace_test-trigger_abc123"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", "cre-dlq_testnamespace_test-trigger_abc123"),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						MaxDeliveryAttempts: 3,
+						DeadLetterTopic:     "projects/test-project-id/topics/cre-dlq_testnamespace_test-trigger_abc123",
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-dlq_testnamespace_test-trigger_abc123", nil),
+			},
+		},
 		{
 			Name: "Sub already exists, update config",
 			Key:  testKey,
@@ -489,39 +573,41 @@ func TestAllCasesTrigger(t *testing.T) {
 			},
 		},
 		{
-			Name: "Check topic config and labels - broker without spec.delivery.retry",
+			Name: "Sub already exists, circuit breaker open",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBroker(brokerName, testNS,
 					WithBrokerClass(brokerv1beta1.BrokerClass),
 					WithInitBrokerConditions,
 					WithBrokerReady("url"),
-					WithBrokerDeliverySpec(brokerDeliverySpecWithoutRetry),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
 					WithBrokerSetDefaults,
 				),
 				makeSubscriberAddressableAsUnstructured(),
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerCircuitBreaker("3", "1m"),
 					WithTriggerSetDefaults),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerCircuitBreaker("3", "1m"),
 					WithTriggerBrokerReady,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerCircuitBreakerOpen("CircuitBreakerOpen", "Deliveries to the subscriber are suspended after consecutive failures"),
 					WithTriggerSetDefaults,
 				),
 			}},
 			WantEvents: []string{
 				triggerFinalizerUpdatedEvent,
-				topicCreatedEvent,
-				subscriptionCreatedEvent,
+				subscriptionConfigUpdatedEvent,
 				triggerReconciledEvent,
 			},
 			WantPatches: []clientgotesting.PatchActionImpl{
@@ -529,34 +615,19 @@ func TestAllCasesTrigger(t *testing.T) {
 			},
 			OtherTestData: map[string]interface{}{
 				"pre": []PubsubAction{
+					Topic("cre-tgr_testnamespace_test-trigger_abc123"),
+					SubscriptionWithTopicAndLabels("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123",
+						map[string]string{config.CircuitBreakerStateLabel: string(config.CircuitBreakerOpen)}),
 					Topic("test-dead-letter-topic-id"),
 				},
-				"dataResidencyConfigMap": NewDataresidencyConfigMapFromRegions([]string{"us-east1"}),
 			},
 			PostConditions: []func(*testing.T, *TableRow){
 				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
 				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
-				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.RetryPolicy{
-						MaximumBackoff: 5 * time.Second,
-						MinimumBackoff: 5 * time.Second,
-					}),
-				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.DeadLetterPolicy{
-						DeadLetterTopic: "projects/test-project-id/topics/test-dead-letter-topic-id",
-					}),
-				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
-					MessageStoragePolicy: pubsub.MessageStoragePolicy{
-						AllowedPersistenceRegions: []string{"us-east1"},
-					},
-					Labels: map[string]string{
-						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
-					},
-				}),
 			},
 		},
 		{
-			Name: "Check topic config and labels",
+			Name: "Sub already exists, trigger paused",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBroker(brokerName, testNS,
@@ -570,25 +641,27 @@ func TestAllCasesTrigger(t *testing.T) {
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerPaused,
 					WithTriggerSetDefaults),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerPaused,
 					WithTriggerBrokerReady,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerPausedCondition,
 					WithTriggerSetDefaults,
 				),
 			}},
 			WantEvents: []string{
 				triggerFinalizerUpdatedEvent,
-				topicCreatedEvent,
-				subscriptionCreatedEvent,
+				subscriptionConfigUpdatedEvent,
 				triggerReconciledEvent,
 			},
 			WantPatches: []clientgotesting.PatchActionImpl{
@@ -596,35 +669,18 @@ func TestAllCasesTrigger(t *testing.T) {
 			},
 			OtherTestData: map[string]interface{}{
 				"pre": []PubsubAction{
+					Topic("cre-tgr_testnamespace_test-trigger_abc123"),
+					SubscriptionWithTopic("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
 					Topic("test-dead-letter-topic-id"),
 				},
-				"dataResidencyConfigMap": NewDataresidencyConfigMapFromRegions([]string{"us-east1"}),
 			},
 			PostConditions: []func(*testing.T, *TableRow){
 				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
 				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
-				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.RetryPolicy{
-						MaximumBackoff: 5 * time.Second,
-						MinimumBackoff: 5 * time.Second,
-					}),
-				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.DeadLetterPolicy{
-						MaxDeliveryAttempts: 3,
-						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
-					}),
-				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
-					MessageStoragePolicy: pubsub.MessageStoragePolicy{
-						AllowedPersistenceRegions: []string{"us-east1"},
-					},
-					Labels: map[string]string{
-						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
-					},
-				}),
 			},
 		},
 		{
-			Name: "Trigger created, broker ready, subscriber is addressable, nil pubsub client",
+			Name: "Sub already exists, backlog threshold exceeded",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBroker(brokerName, testNS,
@@ -638,25 +694,28 @@ func TestAllCasesTrigger(t *testing.T) {
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBacklogThreshold("100"),
 					WithTriggerSetDefaults),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBacklogThreshold("100"),
 					WithTriggerBrokerReady,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
 					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerBacklog(150, 3*time.Minute),
+					WithTriggerBacklogThresholdExceeded("150 undelivered messages, the oldest one unacknowledged for 3m0s"),
 					WithTriggerSetDefaults,
 				),
 			}},
 			WantEvents: []string{
 				triggerFinalizerUpdatedEvent,
-				topicCreatedEvent,
-				subscriptionCreatedEvent,
+				subscriptionConfigUpdatedEvent,
 				triggerReconciledEvent,
 			},
 			WantPatches: []clientgotesting.PatchActionImpl{
@@ -664,37 +723,30 @@ func TestAllCasesTrigger(t *testing.T) {
 			},
 			OtherTestData: map[string]interface{}{
 				"pre": []PubsubAction{
+					Topic("cre-tgr_testnamespace_test-trigger_abc123"),
+					SubscriptionWithTopic("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
 					Topic("test-dead-letter-topic-id"),
 				},
-				// TODO: This should make sure the function is called only once, but currently this case only check the create function
-				// will be called on demand since there is no test that reconcile twice or with both reconcile and delete.
-				"maxPSClientCreateTime": 1,
+				"monitoring": gmonitoringtesting.TestClientData{
+					Backlogs: map[string]gmonitoring.Backlog{
+						"cre-tgr_testnamespace_test-trigger_abc123": {
+							UndeliveredMessages:     150,
+							OldestUnackedMessageAge: 3 * time.Minute,
+						},
+					},
+				},
 			},
 			PostConditions: []func(*testing.T, *TableRow){
 				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
 				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
-				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.RetryPolicy{
-						MaximumBackoff: 5 * time.Second,
-						MinimumBackoff: 5 * time.Second,
-					}),
-				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
-					&pubsub.DeadLetterPolicy{
-						MaxDeliveryAttempts: 3,
-						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
-					}),
-				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
-					Labels: map[string]string{
-						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
-					},
-				}),
 			},
 		},
 		{
-			Name: "Trigger created, broker ready, subscriber is addressable, pubsub client creation fails",
+			Name: "Isolated trigger, filtered subscription created",
 			Key:  testKey,
 			Objects: []runtime.Object{
 				NewBroker(brokerName, testNS,
+					WithBrokerUID(testBrokerUID),
 					WithBrokerClass(brokerv1beta1.BrokerClass),
 					WithInitBrokerConditions,
 					WithBrokerReady("url"),
@@ -705,88 +757,834 @@ func TestAllCasesTrigger(t *testing.T) {
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerAttributesFilter(map[string]string{"type": "foo"}),
+					WithTriggerIsolation(brokerv1beta1.IsolationFilteredSubscription),
 					WithTriggerSetDefaults),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerAttributesFilter(map[string]string{"type": "foo"}),
+					WithTriggerIsolation(brokerv1beta1.IsolationFilteredSubscription),
 					WithTriggerBrokerReady,
 					WithTriggerSubscriptionReady,
 					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
 					WithTriggerSubscriberResolvedSucceeded,
 					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerIsolatedSubscriptionReady,
 					WithTriggerSetDefaults,
-					WithTriggerDependencyUnknown("", ""),
-					WithTriggerTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
-					WithTriggerSubscriptionUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
 				),
 			}},
 			WantEvents: []string{
 				triggerFinalizerUpdatedEvent,
-				Eventf(corev1.EventTypeWarning, "InternalError", "Invoke time 0 reaches the max invoke time 0"),
+				subscriptionConfigUpdatedEvent,
+				isolatedSubCreatedEvent,
+				triggerReconciledEvent,
 			},
 			WantPatches: []clientgotesting.PatchActionImpl{
 				patchFinalizers(testNS, triggerName, finalizerName),
 			},
 			OtherTestData: map[string]interface{}{
 				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
 					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopicID),
 				},
-				"maxPSClientCreateTime": 0,
 			},
-			WantErr: true,
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id", testDecouplingTopicID),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", "cre-iso_testnamespace_test-trigger_abc123"),
+				SubscriptionHasFilter("cre-iso_testnamespace_test-trigger_abc123", `attributes."ce-type" = "foo"`),
+			},
 		},
-	}
-
-	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
-		srv := pstest.NewServer()
-		// Insert pubsub client for PostConditions and create fixtures
-		psclient, _ := GetTestClientCreateFunc(srv.Addr)(ctx, testProject)
-		savedCreateFn := createPubsubClientFn
-		close := func() {
-			srv.Close()
-			createPubsubClientFn = savedCreateFn
-		}
-		t.Cleanup(close)
-		var drStore *dataresidency.Store
-		if testData != nil {
-			InjectPubsubClient(testData, psclient)
-			if testData["pre"] != nil {
-				fixtures := testData["pre"].([]PubsubAction)
-				for _, f := range fixtures {
-					f(ctx, t, psclient)
-				}
-			}
-
-			// If we found "dataResidencyConfigMap" in OtherData, we create a store with the configmap
-			if cm, ok := testData["dataResidencyConfigMap"]; ok {
-				drStore = NewDataresidencyTestStore(t, cm.(*corev1.ConfigMap))
-			}
-		}
-
-		// If maxPSClientCreateTime is in testData, no pubsub client is passed to reconciler, the reconciler
-		// will create one in demand
-		testPSClient := psclient
-		if maxTime, ok := testData["maxPSClientCreateTime"]; ok {
-			// Overwrite the createPubsubClientFn to one that failed when called more than maxTime times.
-			// maxTime=0 is used to inject error
-			createPubsubClientFn = GetFailedTestClientCreateFunc(srv.Addr, maxTime.(int))
-			testPSClient = nil
-		}
-
-		ctx = addressable.WithDuck(ctx)
-		ctx = resource.WithDuck(ctx)
-		ctx = source.WithDuck(ctx)
-
-		r := &Reconciler{
-			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
-			brokerLister:       listers.GetBrokerLister(),
-			sourceTracker:      duck.NewListableTracker(ctx, source.Get, func(types.NamespacedName) {}, 0),
-			addressableTracker: duck.NewListableTracker(ctx, addressable.Get, func(types.NamespacedName) {}, 0),
-			uriResolver:        resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
-			projectID:          testProject,
-			pubsubClient:       testPSClient,
+		{
+			Name: "Isolated trigger, filter changed, subscription reported not ready",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerUID(testBrokerUID),
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerAttributesFilter(map[string]string{"type": "foo"}),
+					WithTriggerIsolation(brokerv1beta1.IsolationFilteredSubscription),
+					WithTriggerIsolatedSubscriptionReady,
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerAttributesFilter(map[string]string{"type": "foo"}),
+					WithTriggerIsolation(brokerv1beta1.IsolationFilteredSubscription),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerIsolatedSubscriptionRecreating(time.Now()),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopicID),
+					SubscriptionWithTopic("cre-iso_testnamespace_test-trigger_abc123", testDecouplingTopicID),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id", testDecouplingTopicID),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", "cre-iso_testnamespace_test-trigger_abc123"),
+				SubscriptionHasFilter("cre-iso_testnamespace_test-trigger_abc123", ""),
+			},
+		},
+		{
+			Name: "Isolated trigger, filter changed, subscription recreated",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerUID(testBrokerUID),
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerAttributesFilter(map[string]string{"type": "foo"}),
+					WithTriggerIsolation(brokerv1beta1.IsolationFilteredSubscription),
+					WithTriggerIsolatedSubscriptionRecreating(time.Now().Add(-3*time.Minute)),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerAttributesFilter(map[string]string{"type": "foo"}),
+					WithTriggerIsolation(brokerv1beta1.IsolationFilteredSubscription),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerIsolatedSubscriptionReady,
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				isolatedSubDeletedEvent,
+				isolatedSubCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopicID),
+					SubscriptionWithTopic("cre-iso_testnamespace_test-trigger_abc123", testDecouplingTopicID),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id", testDecouplingTopicID),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", "cre-iso_testnamespace_test-trigger_abc123"),
+				SubscriptionHasFilter("cre-iso_testnamespace_test-trigger_abc123", `attributes."ce-type" = "foo"`),
+			},
+		},
+		{
+			Name: "Trigger no longer isolated, isolated subscription deleted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerUID(testBrokerUID),
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolatedSubscriptionReady,
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				isolatedSubDeletedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopicID),
+					SubscriptionWithTopic("cre-iso_testnamespace_test-trigger_abc123", testDecouplingTopicID),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id", testDecouplingTopicID),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+			},
+		},
+		{
+			Name: "Replayable isolated trigger, acknowledged events retained",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerUID(testBrokerUID),
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolation(brokerv1beta1.IsolationSubscription),
+					WithTriggerReplayable,
+					WithTriggerIsolatedSubscriptionReady,
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolation(brokerv1beta1.IsolationSubscription),
+					WithTriggerReplayable,
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerIsolatedSubscriptionReady,
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				Eventf(corev1.EventTypeNormal, "SubscriptionConfigUpdated", `Updated config for PubSub subscription "cre-iso_testnamespace_test-trigger_abc123"`),
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopicID),
+					SubscriptionWithTopic("cre-iso_testnamespace_test-trigger_abc123", testDecouplingTopicID),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				SubscriptionHasRetainAckedMessages("cre-tgr_testnamespace_test-trigger_abc123", false),
+				SubscriptionHasRetainAckedMessages("cre-iso_testnamespace_test-trigger_abc123", true),
+			},
+		},
+		{
+			Name: "Isolated trigger, snapshot created and replayed",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerUID(testBrokerUID),
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolation(brokerv1beta1.IsolationSubscription),
+					WithTriggerSnapshot("before-deploy"),
+					WithTriggerReplaySnapshot("before-deploy"),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolation(brokerv1beta1.IsolationSubscription),
+					WithTriggerSnapshot("before-deploy"),
+					WithTriggerReplaySnapshot("before-deploy"),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerIsolatedSubscriptionReady,
+					WithTriggerReplayed("Replayed", `Created snapshot "before-deploy" and replayed events from snapshot "before-deploy"`),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				Eventf(corev1.EventTypeNormal, "SnapshotCreated", `Created PubSub snapshot "before-deploy"`),
+				Eventf(corev1.EventTypeNormal, "SubscriptionSeeked", `Seeked PubSub subscription "cre-iso_testnamespace_test-trigger_abc123" to snapshot "before-deploy"`),
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("cre-tgr_testnamespace_test-trigger_abc123"),
+					SubscriptionWithTopic("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopicID),
+					SubscriptionWithTopic("cre-iso_testnamespace_test-trigger_abc123", testDecouplingTopicID),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id", testDecouplingTopicID),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123", "cre-iso_testnamespace_test-trigger_abc123"),
+			},
+		},
+		{
+			Name: "Isolated trigger, replay already applied",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerUID(testBrokerUID),
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolation(brokerv1beta1.IsolationSubscription),
+					WithTriggerReplayTime("2021-01-02T15:04:05Z"),
+					WithTriggerReplayed("Replayed", "Replayed events from 2021-01-02T15:04:05Z"),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolation(brokerv1beta1.IsolationSubscription),
+					WithTriggerReplayTime("2021-01-02T15:04:05Z"),
+					WithTriggerReplayed("Replayed", "Replayed events from 2021-01-02T15:04:05Z"),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerIsolatedSubscriptionReady,
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("cre-tgr_testnamespace_test-trigger_abc123"),
+					SubscriptionWithTopic("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopicID),
+					SubscriptionWithTopic("cre-iso_testnamespace_test-trigger_abc123", testDecouplingTopicID),
+				},
+				// The replay is not looked up again.
+				"replay": gpubsubtesting.TestClientData{
+					SubscriptionData: gpubsubtesting.TestSubscriptionData{
+						ConfigErr: errors.New("config failed"),
+						SeekErr:   errors.New("seek failed"),
+					},
+				},
+			},
+		},
+		{
+			Name: "Isolated trigger, replay fails",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerUID(testBrokerUID),
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolation(brokerv1beta1.IsolationSubscription),
+					WithTriggerReplayTime("2021-01-02T15:04:05Z"),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerIsolation(brokerv1beta1.IsolationSubscription),
+					WithTriggerReplayTime("2021-01-02T15:04:05Z"),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyUnknown("", ""),
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerIsolatedSubscriptionReady,
+					WithTriggerReplayFailed("SeekFailed", "Failed to seek Pub/Sub subscription to 2021-01-02T15:04:05Z: seek failed"),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				Eventf(corev1.EventTypeWarning, "InternalError", "seek failed"),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("cre-tgr_testnamespace_test-trigger_abc123"),
+					SubscriptionWithTopic("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+					Topic(testDecouplingTopicID),
+					SubscriptionWithTopic("cre-iso_testnamespace_test-trigger_abc123", testDecouplingTopicID),
+				},
+				"replay": gpubsubtesting.TestClientData{
+					SubscriptionData: gpubsubtesting.TestSubscriptionData{
+						SeekErr: errors.New("seek failed"),
+					},
+				},
+			},
+			WantErr: true,
+		},
+		{
+			Name: "Sub already exists, update config from trigger delivery spec",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliverySpec(triggerDeliverySpec),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliverySpec(triggerDeliverySpec),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				subscriptionConfigUpdatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+					Topic("test-dead-letter-topic-id"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 10 * time.Second,
+						MinimumBackoff: 10 * time.Second,
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						MaxDeliveryAttempts: 7,
+						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+					}),
+			},
+		},
+		{
+			Name: "Check topic config and labels - broker without spec.delivery.retry",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpecWithoutRetry),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+				"dataResidencyConfigMap": NewDataresidencyConfigMapFromRegions([]string{"us-east1"}),
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 5 * time.Second,
+						MinimumBackoff: 5 * time.Second,
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+						MaxDeliveryAttempts: 5,
+					}),
+				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
+					MessageStoragePolicy: pubsub.MessageStoragePolicy{
+						AllowedPersistenceRegions: []string{"us-east1"},
+					},
+					Labels: map[string]string{
+						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
+					},
+				}),
+			},
+		},
+		{
+			Name: "Check topic config and labels",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+				"dataResidencyConfigMap": NewDataresidencyConfigMapFromRegions([]string{"us-east1"}),
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 5 * time.Second,
+						MinimumBackoff: 5 * time.Second,
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						MaxDeliveryAttempts: 3,
+						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+					}),
+				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
+					MessageStoragePolicy: pubsub.MessageStoragePolicy{
+						AllowedPersistenceRegions: []string{"us-east1"},
+					},
+					Labels: map[string]string{
+						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
+					},
+				}),
+			},
+		},
+		{
+			Name: "Trigger created, broker ready, subscriber is addressable, nil pubsub client",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+				// TODO: This should make sure the function is called only once, but currently this case only check the create function
+				// will be called on demand since there is no test that reconcile twice or with both reconcile and delete.
+				"maxPSClientCreateTime": 1,
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123", "test-dead-letter-topic-id"),
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.RetryPolicy{
+						MaximumBackoff: 5 * time.Second,
+						MinimumBackoff: 5 * time.Second,
+					}),
+				SubscriptionHasDeadLetterPolicy("cre-tgr_testnamespace_test-trigger_abc123",
+					&pubsub.DeadLetterPolicy{
+						MaxDeliveryAttempts: 3,
+						DeadLetterTopic:     "projects/test-project-id/topics/test-dead-letter-topic-id",
+					}),
+				TopicExistsWithConfig("cre-tgr_testnamespace_test-trigger_abc123", &pubsub.TopicConfig{
+					Labels: map[string]string{
+						"name": "test-trigger", "namespace": "testnamespace", "resource": "triggers",
+					},
+				}),
+			},
+		},
+		{
+			Name: "Trigger created, broker ready, subscriber is addressable, pubsub client creation fails",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerDeliverySpec(brokerDeliverySpec),
+					WithBrokerSetDefaults,
+				),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSetDefaults),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSetDefaults,
+					WithTriggerDependencyUnknown("", ""),
+					WithTriggerTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
+					WithTriggerSubscriptionUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: Invoke time 0 reaches the max invoke time 0"),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeWarning, "InternalError", "Invoke time 0 reaches the max invoke time 0"),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					Topic("test-dead-letter-topic-id"),
+				},
+				"maxPSClientCreateTime": 0,
+			},
+			WantErr: true,
+		},
+	}
+
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		srv := pstest.NewServer()
+		// Insert pubsub client for PostConditions and create fixtures
+		psclient, _ := GetTestClientCreateFunc(srv.Addr)(ctx, testProject)
+		savedCreateFn := createPubsubClientFn
+		close := func() {
+			srv.Close()
+			createPubsubClientFn = savedCreateFn
+		}
+		t.Cleanup(close)
+		var drStore *dataresidency.Store
+		if testData != nil {
+			InjectPubsubClient(testData, psclient)
+			if testData["pre"] != nil {
+				fixtures := testData["pre"].([]PubsubAction)
+				for _, f := range fixtures {
+					f(ctx, t, psclient)
+				}
+			}
+
+			// If we found "dataResidencyConfigMap" in OtherData, we create a store with the configmap
+			if cm, ok := testData["dataResidencyConfigMap"]; ok {
+				drStore = NewDataresidencyTestStore(t, cm.(*corev1.ConfigMap))
+			}
+		}
+
+		// If maxPSClientCreateTime is in testData, no pubsub client is passed to reconciler, the reconciler
+		// will create one in demand
+		testPSClient := psclient
+		if maxTime, ok := testData["maxPSClientCreateTime"]; ok {
+			// Overwrite the createPubsubClientFn to one that failed when called more than maxTime times.
+			// maxTime=0 is used to inject error
+			createPubsubClientFn = GetFailedTestClientCreateFunc(srv.Addr, maxTime.(int))
+			testPSClient = nil
+		}
+
+		ctx = addressable.WithDuck(ctx)
+		ctx = resource.WithDuck(ctx)
+		ctx = source.WithDuck(ctx)
+
+		r := &Reconciler{
+			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
+			brokerLister:       listers.GetBrokerLister(),
+			sourceTracker:      duck.NewListableTracker(ctx, source.Get, func(types.NamespacedName) {}, 0),
+			addressableTracker: duck.NewListableTracker(ctx, addressable.Get, func(types.NamespacedName) {}, 0),
+			uriResolver:        resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
+			projectID:          testProject,
+			pubsubClient:       testPSClient,
+			replayClient: func(*pubsub.Client) gpubsub.Client {
+				c, _ := gpubsubtesting.TestClientCreator(testData["replay"])(ctx, testProject)
+				return c
+			},
+			backlogReporter:    backlog.NewReporter(ctx, gmonitoringtesting.TestClientCreator(testData["monitoring"])),
 			dataresidencyStore: drStore,
 			clusterRegion:      testClusterRegion,
 		}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backlog reports the backlog of the Pub/Sub subscriptions of resources
+// in their status.
+package backlog
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/google/knative-gcp/pkg/apis/duck"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	"github.com/google/knative-gcp/pkg/logging"
+)
+
+// ResyncPeriod is the period between the reconciliations of the resources
+// with backlog reporting, to update their backlog. Cloud Monitoring cannot
+// notify the control plane of backlog changes, so the reconcilers poll the
+// backlog while Report returns true.
+const ResyncPeriod = 5 * time.Minute
+
+// StatusUpdater is an interface which updates resource status based on the backlog of its subscriptions.
+type StatusUpdater interface {
+	SetBacklog(undeliveredMessages int64, oldestUnackedMessageAge time.Duration)
+	MarkBacklogHealthy()
+	MarkBacklogThresholdExceeded(format string, args ...interface{})
+	MarkBacklogUnknown(reason, format string, args ...interface{})
+	ClearBacklog()
+}
+
+// Reporter reads the backlog of Pub/Sub subscriptions from Cloud Monitoring
+// and reports it in the status of their resources.
+type Reporter struct {
+	clientProvider gmonitoring.CreateFn
+
+	// client is created on first use and shared by the workers of the
+	// controller.
+	client gmonitoring.Client
+	mu     sync.Mutex
+}
+
+// NewReporter creates a Reporter which creates its Cloud Monitoring client
+// with clientProvider. The client is closed once ctx is done.
+func NewReporter(ctx context.Context, clientProvider gmonitoring.CreateFn) *Reporter {
+	r := &Reporter{
+		clientProvider: clientProvider,
+	}
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.client != nil {
+			r.client.Close()
+		}
+	}()
+	return r
+}
+
+// Report reports the backlog of the subscriptions in the status if the
+// annotations of the resource enable the backlog reporting, and returns
+// whether they do. The backlog of several subscriptions is the sum of their
+// undelivered messages and the age of the oldest unacknowledged message among
+// them.
+func (r *Reporter) Report(ctx context.Context, annotations map[string]string, updater StatusUpdater, projectID string, subscriptionIDs ...string) bool {
+	enabled, thresholds := duck.BacklogReporting(annotations)
+	if !enabled {
+		updater.ClearBacklog()
+		return false
+	}
+	logger := logging.FromContext(ctx)
+	client, err := r.getClient(ctx)
+	if err != nil {
+		logger.Error("Failed to create Cloud Monitoring client", zap.Error(err))
+		updater.MarkBacklogUnknown("MonitoringClientCreationFailed", "Failed to create the Cloud Monitoring client: %v", err)
+		return true
+	}
+
+	var undeliveredMessages int64
+	var oldestUnackedMessageAge time.Duration
+	for _, subscriptionID := range subscriptionIDs {
+		backlog, err := client.SubscriptionBacklog(ctx, projectID, subscriptionID)
+		if errors.Is(err, gmonitoring.ErrNoData) {
+			updater.MarkBacklogUnknown("BacklogUnavailable", "No recent backlog metrics of the Pub/Sub subscription %s", subscriptionID)
+			return true
+		}
+		if err != nil {
+			logger.Error("Failed to read the backlog of the Pub/Sub subscription", zap.String("subscription", subscriptionID), zap.Error(err))
+			updater.MarkBacklogUnknown("BacklogReadFailed", "Failed to read the backlog of the Pub/Sub subscription %s: %v", subscriptionID, err)
+			return true
+		}
+		undeliveredMessages += backlog.UndeliveredMessages
+		if backlog.OldestUnackedMessageAge > oldestUnackedMessageAge {
+			oldestUnackedMessageAge = backlog.OldestUnackedMessageAge
+		}
+	}
+
+	updater.SetBacklog(undeliveredMessages, oldestUnackedMessageAge)
+	if thresholds.Exceeded(undeliveredMessages, oldestUnackedMessageAge) {
+		updater.MarkBacklogThresholdExceeded("%d undelivered messages, the oldest one unacknowledged for %v", undeliveredMessages, oldestUnackedMessageAge)
+	} else {
+		updater.MarkBacklogHealthy()
+	}
+	return true
+}
+
+func (r *Reporter) getClient(ctx context.Context) (gmonitoring.Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		client, err := r.clientProvider(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.client = client
+	}
+	return r.client, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	gmonitoringtesting "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
+)
+
+// fakeStatus records the backlog reported in the status.
+type fakeStatus struct {
+	backlog   string
+	condition string
+}
+
+func (s *fakeStatus) SetBacklog(undeliveredMessages int64, oldestUnackedMessageAge time.Duration) {
+	s.backlog = fmt.Sprintf("%d/%v", undeliveredMessages, oldestUnackedMessageAge)
+}
+
+func (s *fakeStatus) MarkBacklogHealthy() {
+	s.condition = "True"
+}
+
+func (s *fakeStatus) MarkBacklogThresholdExceeded(format string, args ...interface{}) {
+	s.condition = "False: " + fmt.Sprintf(format, args...)
+}
+
+func (s *fakeStatus) MarkBacklogUnknown(reason, format string, args ...interface{}) {
+	s.condition = "Unknown: " + reason
+}
+
+func (s *fakeStatus) ClearBacklog() {
+	s.backlog = ""
+	s.condition = ""
+}
+
+func TestReport(t *testing.T) {
+	backlogs := map[string]gmonitoring.Backlog{
+		"sub-1": {UndeliveredMessages: 10, OldestUnackedMessageAge: time.Minute},
+		"sub-2": {UndeliveredMessages: 5, OldestUnackedMessageAge: 2 * time.Minute},
+	}
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		clientData    gmonitoringtesting.TestClientData
+		subscriptions []string
+		wantEnabled   bool
+		want          fakeStatus
+	}{{
+		name:          "reporting disabled",
+		subscriptions: []string{"sub-1"},
+		want:          fakeStatus{},
+	}, {
+		name:          "healthy",
+		annotations:   map[string]string{"events.cloud.google.com/backlogReporting": "true"},
+		clientData:    gmonitoringtesting.TestClientData{Backlogs: backlogs},
+		subscriptions: []string{"sub-1"},
+		wantEnabled:   true,
+		want:          fakeStatus{backlog: "10/1m0s", condition: "True"},
+	}, {
+		name:          "backlog of several subscriptions",
+		annotations:   map[string]string{"events.cloud.google.com/backlogReporting": "true"},
+		clientData:    gmonitoringtesting.TestClientData{Backlogs: backlogs},
+		subscriptions: []string{"sub-1", "sub-2"},
+		wantEnabled:   true,
+		want:          fakeStatus{backlog: "15/2m0s", condition: "True"},
+	}, {
+		name:          "threshold exceeded",
+		annotations:   map[string]string{"events.cloud.google.com/backlogThreshold": "12"},
+		clientData:    gmonitoringtesting.TestClientData{Backlogs: backlogs},
+		subscriptions: []string{"sub-1", "sub-2"},
+		wantEnabled:   true,
+		want:          fakeStatus{backlog: "15/2m0s", condition: "False: 15 undelivered messages, the oldest one unacknowledged for 2m0s"},
+	}, {
+		name:          "age threshold not exceeded",
+		annotations:   map[string]string{"events.cloud.google.com/backlogAgeThreshold": "2m"},
+		clientData:    gmonitoringtesting.TestClientData{Backlogs: backlogs},
+		subscriptions: []string{"sub-2"},
+		wantEnabled:   true,
+		want:          fakeStatus{backlog: "5/2m0s", condition: "True"},
+	}, {
+		name:          "no data",
+		annotations:   map[string]string{"events.cloud.google.com/backlogReporting": "true"},
+		clientData:    gmonitoringtesting.TestClientData{Backlogs: backlogs},
+		subscriptions: []string{"sub-1", "sub-3"},
+		wantEnabled:   true,
+		want:          fakeStatus{condition: "Unknown: BacklogUnavailable"},
+	}, {
+		name:          "read error",
+		annotations:   map[string]string{"events.cloud.google.com/backlogReporting": "true"},
+		clientData:    gmonitoringtesting.TestClientData{SubscriptionBacklogErr: errors.New("permission denied")},
+		subscriptions: []string{"sub-1"},
+		wantEnabled:   true,
+		want:          fakeStatus{condition: "Unknown: BacklogReadFailed"},
+	}, {
+		name:          "client creation error",
+		annotations:   map[string]string{"events.cloud.google.com/backlogReporting": "true"},
+		clientData:    gmonitoringtesting.TestClientData{CreateClientErr: errors.New("no credentials")},
+		subscriptions: []string{"sub-1"},
+		wantEnabled:   true,
+		want:          fakeStatus{condition: "Unknown: MonitoringClientCreationFailed"},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			r := NewReporter(ctx, gmonitoringtesting.TestClientCreator(tc.clientData))
+			status := &fakeStatus{backlog: "stale", condition: "stale"}
+			if tc.want.backlog == "" && tc.wantEnabled {
+				// The backlog is kept when it can't be read.
+				tc.want.backlog = "stale"
+			}
+			if got := r.Report(ctx, tc.annotations, status, "test-project", tc.subscriptions...); got != tc.wantEnabled {
+				t.Errorf("Report() = %v, want %v", got, tc.wantEnabled)
+			}
+			if diff := cmp.Diff(tc.want, *status, cmp.AllowUnexported(fakeStatus{})); diff != "" {
+				t.Errorf("unexpected status (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"k8s.io/apimachinery/pkg/api/equality"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	gcpduckv1 "github.com/google/knative-gcp/pkg/apis/duck/v1"
+)
+
+const (
+	// DefaultMinimumBackoff is the minimum backoff of Pub/Sub retry policies
+	// when the delivery spec has no backoff delay.
+	DefaultMinimumBackoff = 10 * time.Second
+	// DefaultMaximumBackoff is the maximum backoff of the exponential backoff
+	// policy. 600 seconds is the longest supported time.
+	DefaultMaximumBackoff = 600 * time.Second
+)
+
+// RetryPolicy translates a delivery spec to a Pub/Sub retry policy, in the
+// manner defined in
+// https://github.com/google/knative-gcp/issues/1392#issuecomment-655617873.
+// The zero value, returned when the spec has no backoff, removes any retry
+// policy previously set on the subscription.
+func RetryPolicy(spec *eventingduckv1.DeliverySpec) *pubsub.RetryPolicy {
+	if spec == nil || (spec.BackoffDelay == nil && spec.BackoffPolicy == nil) {
+		return &pubsub.RetryPolicy{}
+	}
+	minimumBackoff := DefaultMinimumBackoff
+	if spec.BackoffDelay != nil {
+		// The backoff delay has been validated by the webhook.
+		minimumBackoff, _ = gcpduckv1.BackoffDelay(spec)
+	}
+	maximumBackoff := DefaultMaximumBackoff
+	if spec.BackoffPolicy != nil && *spec.BackoffPolicy == eventingduckv1.BackoffPolicyLinear {
+		maximumBackoff = minimumBackoff
+	}
+	return &pubsub.RetryPolicy{
+		MinimumBackoff: minimumBackoff,
+		MaximumBackoff: maximumBackoff,
+	}
+}
+
+// DeadLetterPolicy translates a delivery spec to a Pub/Sub dead letter policy.
+// It only applies to dead letter sinks which are Pub/Sub topics. The zero
+// value, returned otherwise, removes any dead letter policy previously set on
+// the subscription.
+func DeadLetterPolicy(projectID string, spec *eventingduckv1.DeliverySpec) *pubsub.DeadLetterPolicy {
+	if spec == nil || !brokerv1beta1.IsPubsubDeadLetterSink(spec.DeadLetterSink) {
+		return &pubsub.DeadLetterPolicy{}
+	}
+	return NewDeadLetterPolicy(projectID, spec.DeadLetterSink.URI.Host, spec.Retry)
+}
+
+// NewDeadLetterPolicy returns the Pub/Sub dead letter policy sending the
+// events to the given topic after the given number of delivery attempts.
+func NewDeadLetterPolicy(projectID, topicID string, retry *int32) *pubsub.DeadLetterPolicy {
+	dlp := &pubsub.DeadLetterPolicy{
+		DeadLetterTopic: fmt.Sprintf("projects/%s/topics/%s", projectID, topicID),
+		// Pub/Sub defaults the maximum delivery attempts to the minimum, set
+		// it explicitly so that the policy reads back the same.
+		MaxDeliveryAttempts: gcpduckv1.MinDeliveryAttempts,
+	}
+	if retry != nil {
+		dlp.MaxDeliveryAttempts = int(*retry)
+	}
+	return dlp
+}
+
+// RetryPolicyEqual returns true if the current retry policy already matches
+// the desired one. A removed retry policy reads back as nil.
+func RetryPolicyEqual(current, desired *pubsub.RetryPolicy) bool {
+	if desired == nil || (desired.MinimumBackoff == nil && desired.MaximumBackoff == nil) {
+		return current == nil || (current.MinimumBackoff == nil && current.MaximumBackoff == nil)
+	}
+	return equality.Semantic.DeepEqual(current, desired)
+}
+
+// DeadLetterPolicyEqual returns true if the current dead letter policy already
+// matches the desired one. A removed dead letter policy reads back as nil.
+func DeadLetterPolicyEqual(current, desired *pubsub.DeadLetterPolicy) bool {
+	if desired == nil || desired.DeadLetterTopic == "" {
+		return current == nil || current.DeadLetterTopic == ""
+	}
+	return equality.Semantic.DeepEqual(current, desired)
+}
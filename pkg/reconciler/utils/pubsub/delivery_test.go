@@ -0,0 +1,166 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/google/go-cmp/cmp"
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	linear := eventingduckv1.BackoffPolicyLinear
+	exponential := eventingduckv1.BackoffPolicyExponential
+	testCases := map[string]struct {
+		spec *eventingduckv1.DeliverySpec
+		want *pubsub.RetryPolicy
+	}{
+		"no delivery": {
+			want: &pubsub.RetryPolicy{},
+		},
+		"no backoff": {
+			spec: &eventingduckv1.DeliverySpec{Retry: ptr.Int32(5)},
+			want: &pubsub.RetryPolicy{},
+		},
+		"linear": {
+			spec: &eventingduckv1.DeliverySpec{
+				BackoffPolicy: &linear,
+				BackoffDelay:  ptr.String("PT30S"),
+			},
+			want: &pubsub.RetryPolicy{
+				MinimumBackoff: 30 * time.Second,
+				MaximumBackoff: 30 * time.Second,
+			},
+		},
+		"exponential": {
+			spec: &eventingduckv1.DeliverySpec{
+				BackoffPolicy: &exponential,
+				BackoffDelay:  ptr.String("PT1S"),
+			},
+			want: &pubsub.RetryPolicy{
+				MinimumBackoff: time.Second,
+				MaximumBackoff: DefaultMaximumBackoff,
+			},
+		},
+		"default backoff delay": {
+			spec: &eventingduckv1.DeliverySpec{
+				BackoffPolicy: &linear,
+			},
+			want: &pubsub.RetryPolicy{
+				MinimumBackoff: DefaultMinimumBackoff,
+				MaximumBackoff: DefaultMinimumBackoff,
+			},
+		},
+		"default backoff policy": {
+			spec: &eventingduckv1.DeliverySpec{
+				BackoffDelay: ptr.String("PT1M"),
+			},
+			want: &pubsub.RetryPolicy{
+				MinimumBackoff: time.Minute,
+				MaximumBackoff: DefaultMaximumBackoff,
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := RetryPolicy(tc.spec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("unexpected retry policy (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestDeadLetterPolicy(t *testing.T) {
+	topicSink := &duckv1.Destination{URI: &apis.URL{Scheme: "pubsub", Host: "dead-letter"}}
+	testCases := map[string]struct {
+		spec *eventingduckv1.DeliverySpec
+		want *pubsub.DeadLetterPolicy
+	}{
+		"no delivery": {
+			want: &pubsub.DeadLetterPolicy{},
+		},
+		"no dead letter sink": {
+			spec: &eventingduckv1.DeliverySpec{BackoffDelay: ptr.String("PT1S")},
+			want: &pubsub.DeadLetterPolicy{},
+		},
+		"dead letter sink isn't a topic": {
+			spec: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: &duckv1.Destination{URI: apis.HTTP("dead-letter")},
+			},
+			want: &pubsub.DeadLetterPolicy{},
+		},
+		"default retry": {
+			spec: &eventingduckv1.DeliverySpec{DeadLetterSink: topicSink},
+			want: &pubsub.DeadLetterPolicy{
+				DeadLetterTopic:     "projects/my-project/topics/dead-letter",
+				MaxDeliveryAttempts: 5,
+			},
+		},
+		"retry": {
+			spec: &eventingduckv1.DeliverySpec{
+				DeadLetterSink: topicSink,
+				Retry:          ptr.Int32(20),
+			},
+			want: &pubsub.DeadLetterPolicy{
+				DeadLetterTopic:     "projects/my-project/topics/dead-letter",
+				MaxDeliveryAttempts: 20,
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := DeadLetterPolicy("my-project", tc.spec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Error("unexpected dead letter policy (-want, +got):", diff)
+			}
+		})
+	}
+}
+
+func TestPolicyEqual(t *testing.T) {
+	retryPolicy := &pubsub.RetryPolicy{MinimumBackoff: time.Second, MaximumBackoff: time.Minute}
+	deadLetterPolicy := &pubsub.DeadLetterPolicy{DeadLetterTopic: "projects/p/topics/t", MaxDeliveryAttempts: 5}
+
+	if !RetryPolicyEqual(nil, &pubsub.RetryPolicy{}) {
+		t.Error("removed retry policy should equal the zero value")
+	}
+	if RetryPolicyEqual(retryPolicy, &pubsub.RetryPolicy{}) {
+		t.Error("retry policy should not equal the zero value")
+	}
+	if !RetryPolicyEqual(&pubsub.RetryPolicy{MinimumBackoff: time.Second, MaximumBackoff: time.Minute}, retryPolicy) {
+		t.Error("same retry policies should be equal")
+	}
+	if !RetryPolicyEqual(&pubsub.RetryPolicy{}, nil) {
+		t.Error("removed retry policy should equal no retry policy")
+	}
+	if !DeadLetterPolicyEqual(nil, &pubsub.DeadLetterPolicy{}) {
+		t.Error("removed dead letter policy should equal the zero value")
+	}
+	if DeadLetterPolicyEqual(nil, deadLetterPolicy) {
+		t.Error("removed dead letter policy should not equal a dead letter policy")
+	}
+	if DeadLetterPolicyEqual(&pubsub.DeadLetterPolicy{DeadLetterTopic: "projects/p/topics/t", MaxDeliveryAttempts: 10}, deadLetterPolicy) {
+		t.Error("dead letter policies with different attempts should not be equal")
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/google/knative-gcp/pkg/logging"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -66,13 +65,22 @@ func (r *Reconciler) ReconcileSubscription(ctx context.Context, id string, subCo
 			}
 			return r.createSubscription(ctx, id, subConfig, obj, updater)
 		}
-		// Update the subscription config in case the retry or dead letter policy changed. A nil policy indicates no change.
-		if (subConfig.RetryPolicy != nil && !equality.Semantic.DeepEqual(config.RetryPolicy, subConfig.RetryPolicy)) ||
-			(subConfig.DeadLetterPolicy != nil && !equality.Semantic.DeepEqual(config.DeadLetterPolicy, subConfig.DeadLetterPolicy)) {
+		// Update the subscription config in case the retry or dead letter policy changed. A nil policy indicates no change,
+		// a zero value dead letter policy removes dead lettering. The retention is only managed by callers setting a
+		// retention duration, other callers keep the current retention.
+		retentionChanged := subConfig.RetentionDuration != 0 &&
+			(subConfig.RetentionDuration != config.RetentionDuration || subConfig.RetainAckedMessages != config.RetainAckedMessages)
+		if retentionChanged ||
+			(subConfig.RetryPolicy != nil && !RetryPolicyEqual(config.RetryPolicy, subConfig.RetryPolicy)) ||
+			(subConfig.DeadLetterPolicy != nil && !DeadLetterPolicyEqual(config.DeadLetterPolicy, subConfig.DeadLetterPolicy)) {
 			updateSubConfig := pubsub.SubscriptionConfigToUpdate{
 				RetryPolicy:      subConfig.RetryPolicy,
 				DeadLetterPolicy: subConfig.DeadLetterPolicy,
 			}
+			if retentionChanged {
+				updateSubConfig.RetainAckedMessages = subConfig.RetainAckedMessages
+				updateSubConfig.RetentionDuration = subConfig.RetentionDuration
+			}
 			if _, err := sub.Update(ctx, updateSubConfig); err != nil {
 				updater.MarkSubscriptionFailed("SubscriptionConfigUpdateFailed", "Failed to update Pub/Sub subscription config: %v", err)
 				return nil, err
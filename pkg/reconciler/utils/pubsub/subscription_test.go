@@ -78,6 +78,61 @@ func TestReconcileSub(t *testing.T) {
 			},
 			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
 		},
+		{
+			name: "sub already exists, remove dead letter policy",
+			pre: []reconcilertesting.PubsubAction{
+				reconcilertesting.Topic(topic),
+				func(ctx context.Context, t *testing.T, c *pubsub.Client) {
+					if _, err := c.CreateSubscription(ctx, sub, pubsub.SubscriptionConfig{
+						Topic: c.Topic(topic),
+						DeadLetterPolicy: &pubsub.DeadLetterPolicy{
+							DeadLetterTopic:     "some-topic-id",
+							MaxDeliveryAttempts: 10,
+						},
+					}); err != nil {
+						t.Fatalf("Error creating subscription %q: %v", sub, err)
+					}
+				},
+			},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				DeadLetterPolicy: &pubsub.DeadLetterPolicy{},
+			},
+			wantEvents: []string{
+				`Normal SubscriptionConfigUpdated Updated config for PubSub subscription "test-sub"`,
+			},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
+		{
+			name: "sub already exists, retain acked messages",
+			pre:  []reconcilertesting.PubsubAction{reconcilertesting.TopicAndSub(topic, sub)},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				RetainAckedMessages: true,
+				RetentionDuration:   24 * time.Hour,
+			},
+			wantEvents: []string{
+				`Normal SubscriptionConfigUpdated Updated config for PubSub subscription "test-sub"`,
+			},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
+		{
+			name: "sub already exists retaining acked messages, default retention",
+			pre:  []reconcilertesting.PubsubAction{reconcilertesting.Topic(topic), createRetainingSub},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				RetentionDuration: 7 * 24 * time.Hour,
+			},
+			wantEvents: []string{
+				`Normal SubscriptionConfigUpdated Updated config for PubSub subscription "test-sub"`,
+			},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
+		{
+			name: "sub already exists without dead letter policy, zero value dead letter policy",
+			pre:  []reconcilertesting.PubsubAction{reconcilertesting.TopicAndSub(topic, sub)},
+			wantSubConfig: &pubsub.SubscriptionConfig{
+				DeadLetterPolicy: &pubsub.DeadLetterPolicy{},
+			},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -90,6 +145,8 @@ func TestReconcileSub(t *testing.T) {
 				subConfig.Labels = tc.wantSubConfig.Labels
 				subConfig.RetryPolicy = tc.wantSubConfig.RetryPolicy
 				subConfig.DeadLetterPolicy = tc.wantSubConfig.DeadLetterPolicy
+				subConfig.RetainAckedMessages = tc.wantSubConfig.RetainAckedMessages
+				subConfig.RetentionDuration = tc.wantSubConfig.RetentionDuration
 			}
 			res, err := r.ReconcileSubscription(context.Background(), sub, subConfig, obj, su)
 
@@ -139,6 +196,16 @@ func deleteTopic(ctx context.Context, t *testing.T, c *pubsub.Client) {
 	}
 }
 
+func createRetainingSub(ctx context.Context, t *testing.T, c *pubsub.Client) {
+	if _, err := c.CreateSubscription(ctx, sub, pubsub.SubscriptionConfig{
+		Topic:               c.Topic(topic),
+		RetainAckedMessages: true,
+		RetentionDuration:   24 * time.Hour,
+	}); err != nil {
+		t.Fatalf("Failed to create sub: %v", err)
+	}
+}
+
 func verifySub(t *testing.T, got *pubsub.Subscription, wantConfig pubsub.SubscriptionConfig) {
 	want := fmt.Sprintf("projects/%s/subscriptions/%s", project, sub)
 	if got.String() != want {
@@ -157,7 +224,18 @@ func verifySub(t *testing.T, got *pubsub.Subscription, wantConfig pubsub.Subscri
 	if !reflect.DeepEqual(gotConfig.RetryPolicy, wantConfig.RetryPolicy) {
 		t.Errorf("Unexpected retry policy in config, got:%+v, want: %+v", gotConfig.RetryPolicy, wantConfig.RetryPolicy)
 	}
-	if !reflect.DeepEqual(gotConfig.DeadLetterPolicy, wantConfig.DeadLetterPolicy) {
-		t.Errorf("Unexpected dead letter policy in config, got:%+v, want: %+v", gotConfig.DeadLetterPolicy, wantConfig.DeadLetterPolicy)
+	if gotConfig.RetainAckedMessages != wantConfig.RetainAckedMessages {
+		t.Errorf("Unexpected retain acked messages in config, got:%v, want: %v", gotConfig.RetainAckedMessages, wantConfig.RetainAckedMessages)
+	}
+	if wantConfig.RetentionDuration != 0 && gotConfig.RetentionDuration != wantConfig.RetentionDuration {
+		t.Errorf("Unexpected retention duration in config, got:%v, want: %v", gotConfig.RetentionDuration, wantConfig.RetentionDuration)
+	}
+	wantDeadLetterPolicy := wantConfig.DeadLetterPolicy
+	if wantDeadLetterPolicy != nil && *wantDeadLetterPolicy == (pubsub.DeadLetterPolicy{}) {
+		// A zero value dead letter policy removes dead lettering.
+		wantDeadLetterPolicy = nil
+	}
+	if !reflect.DeepEqual(gotConfig.DeadLetterPolicy, wantDeadLetterPolicy) {
+		t.Errorf("Unexpected dead letter policy in config, got:%+v, want: %+v", gotConfig.DeadLetterPolicy, wantDeadLetterPolicy)
 	}
 }
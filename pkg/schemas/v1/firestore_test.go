@@ -0,0 +1,59 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCloudFirestoreEventSource(t *testing.T) {
+	want := "//firestore.googleapis.com/projects/PROJECT/databases/(default)"
+
+	got := CloudFirestoreEventSource("PROJECT", CloudFirestoreDefaultDatabase)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("failed to get expected (-want, +got) = %v", diff)
+	}
+}
+
+func TestCloudFirestoreEventSubject(t *testing.T) {
+	want := "documents/users/alice"
+
+	got := CloudFirestoreEventSubject("users/alice")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("failed to get expected (-want, +got) = %v", diff)
+	}
+}
+
+func TestIsCloudFirestoreEventType(t *testing.T) {
+	testCases := map[string]bool{
+		CloudFirestoreDocumentCreatedEventType: true,
+		CloudFirestoreDocumentUpdatedEventType: true,
+		CloudFirestoreDocumentDeletedEventType: true,
+		CloudFirestoreDocumentWrittenEventType: true,
+		CloudStorageObjectFinalizedEventType:   false,
+		"":                                     false,
+	}
+	for eventType, want := range testCases {
+		if got := IsCloudFirestoreEventType(eventType); got != want {
+			t.Errorf("IsCloudFirestoreEventType(%q) = %v, want %v", eventType, got, want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+)
+
+const (
+	CloudMonitoringAlertIncidentOpenedEventType = "google.cloud.monitoring.incident.v1.opened"
+	CloudMonitoringAlertIncidentClosedEventType = "google.cloud.monitoring.incident.v1.closed"
+
+	// CloudMonitoringAlertIncidentIDExtension is the CloudEvent extension with
+	// the ID of the incident, shared by its opened and closed events.
+	CloudMonitoringAlertIncidentIDExtension = "incidentid"
+)
+
+// CloudMonitoringAlertEventSource returns the Cloud Monitoring alert CloudEvent source value.
+func CloudMonitoringAlertEventSource(googleCloudProject string) string {
+	return fmt.Sprintf("//monitoring.googleapis.com/projects/%s", googleCloudProject)
+}
+
+// CloudMonitoringAlertEventSubject returns the Cloud Monitoring alert
+// CloudEvent subject value of the alert policy, e.g. "1234567890".
+func CloudMonitoringAlertEventSubject(policyID string) string {
+	return fmt.Sprintf("alertPolicies/%s", policyID)
+}
@@ -25,7 +25,9 @@ import (
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	cev2 "github.com/cloudevents/sdk-go/v2"
 	"go.opencensus.io/plugin/ochttp"
+	"k8s.io/client-go/kubernetes"
 	"knative.dev/eventing/pkg/kncloudevents"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
 
 	"github.com/google/knative-gcp/pkg/utils/authcheck"
@@ -50,6 +52,11 @@ func NewPubsubClient(ctx context.Context, projectID ProjectID) (*pubsub.Client,
 	return pubsub.NewClient(ctx, string(projectID))
 }
 
+// NewKubeClient provides the Kubernetes client injected in the context.
+func NewKubeClient(ctx context.Context) kubernetes.Interface {
+	return kubeclient.Get(ctx)
+}
+
 // NewObservedPubsubClient creates a pubsub Cloudevents client with observability support.
 func NewObservedPubsubClient(ctx context.Context, client *pubsub.Client) (cev2.Client, error) {
 	p, err := cepubsub.New(ctx, cepubsub.WithClient(client))